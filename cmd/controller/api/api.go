@@ -20,7 +20,6 @@ package api
 import (
 	"net/http"
 
-	"github.com/Sirupsen/logrus"
 	"github.com/gorilla/handlers"
 	"github.com/vmware/purser/cmd/controller/api/apiHandlers"
 	"github.com/vmware/purser/pkg/controller"
@@ -32,6 +31,6 @@ func StartServer(conf controller.Config) {
 	allowedOrigins := handlers.AllowedOrigins([]string{"*"})
 	allowedCredentials := handlers.AllowCredentials()
 	router := NewRouter()
-	logrus.Info("Purser server started on port `localhost:3030`")
-	logrus.Fatal(http.ListenAndServe(":3030", handlers.CORS(allowedOrigins, allowedCredentials)(router)))
+	log.Info("Purser server started on port `localhost:3030`")
+	log.Fatal(http.ListenAndServe(":3030", handlers.CORS(allowedOrigins, allowedCredentials)(router)))
 }
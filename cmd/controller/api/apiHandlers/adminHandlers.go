@@ -0,0 +1,181 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package apiHandlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/vmware/purser/pkg/controller/dgraph"
+	"github.com/vmware/purser/pkg/controller/dgraph/models"
+	"github.com/vmware/purser/pkg/controller/dgraph/models/query"
+	"github.com/vmware/purser/pkg/controller/eventprocessor"
+	"github.com/vmware/purser/pkg/controller/status"
+	"github.com/vmware/purser/pkg/logging"
+)
+
+// RunRetention listens on /api/admin/retention, it removes deleted resources older than the retention period
+func RunRetention(w http.ResponseWriter, r *http.Request) {
+	if isUserAdmin(w, r) {
+		result, err := dgraph.RunRetention()
+		if err != nil {
+			log.Errorf("unable to run retention, %v", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		addHeaders(&w, r)
+		encodeAndWrite(w, result)
+	}
+}
+
+// Resync listens on /api/admin/resync, it re-lists all resources of the cluster and reconciles dgraph with them
+func Resync(w http.ResponseWriter, r *http.Request) {
+	if isUserAdmin(w, r) {
+		report := eventprocessor.ResyncCluster(getKubeClient())
+		eventprocessor.UpdateGroups(getGroupClient())
+		query.ComputeClusterAllocationAndCapacity(r.Context())
+		addHeaders(&w, r)
+		encodeAndWrite(w, report)
+	}
+}
+
+// RestoreNamespace listens on /api/admin/namespace/restore, it moves cost history of deleted namespaces with
+// the given name to the live namespace with that name
+func RestoreNamespace(w http.ResponseWriter, r *http.Request) {
+	if isUserAdmin(w, r) {
+		queryParams, isValid := validateRequest(w, r, requireName)
+		if !isValid {
+			return
+		}
+		restored, err := models.RestoreNamespace(queryParams.Get(query.Name))
+		if err != nil {
+			log.Errorf("unable to restore namespace: %s, err: %v", queryParams.Get(query.Name), err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		addHeaders(&w, r)
+		encodeAndWrite(w, map[string]int{"restored": restored})
+	}
+}
+
+// Reindex listens on /api/admin/reindex, it rebuilds indices of given predicates or of all indexed predicates
+func Reindex(w http.ResponseWriter, r *http.Request) {
+	if isUserAdmin(w, r) {
+		queryParams, isValid := validateRequest(w, r, validatePredicates)
+		if !isValid {
+			return
+		}
+		predicates, err := dgraph.Reindex(queryParams[query.Predicate])
+		if err != nil {
+			log.Errorf("unable to reindex predicates: %v, err: %v", queryParams[query.Predicate], err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		addHeaders(&w, r)
+		encodeAndWrite(w, map[string][]string{"predicates": predicates})
+	}
+}
+
+// MigrateSchema listens on /api/admin/schema/migrate, it applies purser schema on existing data
+func MigrateSchema(w http.ResponseWriter, r *http.Request) {
+	if isUserAdmin(w, r) {
+		migration, err := dgraph.MigrateSchema()
+		if err != nil {
+			log.Errorf("unable to migrate schema, %v", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		addHeaders(&w, r)
+		encodeAndWrite(w, migration)
+	}
+}
+
+// GetConsistencyReport listens on /api/admin/consistency
+func GetConsistencyReport(w http.ResponseWriter, r *http.Request) {
+	if isUserAdmin(w, r) {
+		report, err := dgraph.VerifyConsistency()
+		if err != nil {
+			log.Errorf("unable to verify consistency, %v", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		addHeaders(&w, r)
+		encodeAndWrite(w, report)
+	}
+}
+
+// GetBackup listens on /api/admin/backup, it returns all purser nodes in json as an attachment
+func GetBackup(w http.ResponseWriter, r *http.Request) {
+	if isUserAdmin(w, r) {
+		w.Header().Set("Content-Disposition", "attachment; filename=purser-backup-"+time.Now().UTC().Format("20060102T150405Z")+".json")
+		addHeaders(&w, r)
+		if err := dgraph.Backup(w); err != nil {
+			log.Errorf("unable to backup dgraph, %v", err)
+		}
+	}
+}
+
+// GetStatus listens on /api/status, it returns sync health of each resource kind watched by the controller
+// so that operators can tell whether cost data is current
+func GetStatus(w http.ResponseWriter, r *http.Request) {
+	if isUserAuthenticated(w, r) {
+		tracked, err := query.RetrieveTrackedObjects(r.Context())
+		syncStatus := status.GetStatus(time.Now(), tracked)
+		if err != nil {
+			log.Errorf("unable to retrieve tracked objects, %v", err)
+			syncStatus.Current = false
+			syncStatus.Error = "unable to retrieve tracked objects from dgraph: " + err.Error()
+		}
+		addHeaders(&w, r)
+		encodeAndWrite(w, syncStatus)
+	}
+}
+
+// LogLevels structure
+type LogLevels struct {
+	Default    string            `json:"default"`
+	Components map[string]string `json:"components"`
+}
+
+// GetLogLevels listens on GET /api/admin/log, it returns the default level and levels of components of logs
+func GetLogLevels(w http.ResponseWriter, r *http.Request) {
+	if isUserAdmin(w, r) {
+		defaultLevel, components := logging.GetLevels()
+		addHeaders(&w, r)
+		encodeAndWrite(w, LogLevels{Default: defaultLevel, Components: components})
+	}
+}
+
+// SetLogLevel listens on POST /api/admin/log, it sets the level of logs of the component in query params or the
+// default level if no component is given. Levels set at runtime are lost on restart of the controller.
+func SetLogLevel(w http.ResponseWriter, r *http.Request) {
+	if isUserAdmin(w, r) {
+		queryParams, isValid := validateRequest(w, r, requireLogLevel)
+		if !isValid {
+			return
+		}
+		if err := logging.SetLevel(queryParams.Get(query.Component), queryParams.Get(query.Level)); err != nil {
+			log.Errorf("unable to set log level, %v", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		defaultLevel, components := logging.GetLevels()
+		addHeaders(&w, r)
+		encodeAndWrite(w, LogLevels{Default: defaultLevel, Components: components})
+	}
+}
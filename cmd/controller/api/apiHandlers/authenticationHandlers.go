@@ -22,9 +22,9 @@ import (
 	"encoding/gob"
 	"net/http"
 
-	"github.com/Sirupsen/logrus"
 	"github.com/gorilla/sessions"
 	"github.com/gorilla/securecookie"
+	"github.com/vmware/purser/pkg/controller/auth"
 	"github.com/vmware/purser/pkg/controller/dgraph/models/query"
 )
 
@@ -72,15 +72,15 @@ func LoginUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if !query.Authenticate(cred.Username, cred.Password) {
-		logrus.Errorf("wrong credentials")
+	if !query.Authenticate(r.Context(), cred.Username, cred.Password) {
+		log.Errorf("wrong credentials")
 		w.WriteHeader(http.StatusUnauthorized)
 		return
 	}
 
 	session, err := store.Get(r, cookieName)
 	if err != nil {
-		logrus.Errorf("unable to get session from cookie store, err: %v", err)
+		log.Errorf("unable to get session from cookie store, err: %v", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -91,11 +91,11 @@ func LoginUser(w http.ResponseWriter, r *http.Request) {
 
 	err = session.Save(r, w)
 	if err != nil {
-		logrus.Errorf("unable to get session from cookie store, err: %v", err)
+		log.Errorf("unable to get session from cookie store, err: %v", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
-	logrus.Infof("login success")
+	log.Infof("login success")
 	w.WriteHeader(http.StatusOK)
 }
 
@@ -104,7 +104,7 @@ func LogoutUser(w http.ResponseWriter, r *http.Request) {
 	addAccessControlHeaders(&w, r)
 	session, err := store.Get(r, cookieName)
 	if err != nil {
-		logrus.Errorf("unable to get session from cookie store, err: %v", err)
+		log.Errorf("unable to get session from cookie store, err: %v", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -113,7 +113,7 @@ func LogoutUser(w http.ResponseWriter, r *http.Request) {
 
 	err = session.Save(r, w)
 	if err != nil {
-		logrus.Errorf("unable to get session from cookie store, err: %v", err)
+		log.Errorf("unable to get session from cookie store, err: %v", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -121,20 +121,100 @@ func LogoutUser(w http.ResponseWriter, r *http.Request) {
 }
 
 func isUserAuthenticated(w http.ResponseWriter, r *http.Request) bool {
-	session, err := store.Get(r, cookieName)
+	identity, isAuthenticated := getUserIdentity(w, r)
+	if isAuthenticated && identity.Role == auth.Viewer && auth.HasScopedViewers() {
+		http.Error(w, "viewers can only query /api/cluster and /api/namespaces endpoints", http.StatusForbidden)
+		return false
+	}
+	return isAuthenticated
+}
+
+// isUserAdmin returns true if the user is authenticated and has the admin role, responds with 403 if the user
+// is only a viewer
+func isUserAdmin(w http.ResponseWriter, r *http.Request) bool {
+	identity, isAuthenticated := getUserIdentity(w, r)
+	if isAuthenticated && identity.Role != auth.Admin {
+		http.Error(w, "admin role required", http.StatusForbidden)
+		return false
+	}
+	return isAuthenticated
+}
+
+// canUserView returns true if the user is authenticated and can view the scope(auth.ClusterScope or a namespace),
+// responds with 403 if the user can not view it
+func canUserView(w http.ResponseWriter, r *http.Request, scope string) bool {
+	identity, isAuthenticated := getUserIdentity(w, r)
+	if !isAuthenticated {
+		return false
+	}
+	canView, err := auth.CanView(identity, scope)
 	if err != nil {
-		logrus.Errorf("unable to get session from cookie store, err: %v", err)
+		log.Errorf("access review failed, err: %v", err)
 		http.Error(w, "Internal Error", http.StatusInternalServerError)
 		return false
 	}
+	if !canView {
+		http.Error(w, "access to scope denied", http.StatusForbidden)
+		return false
+	}
+	return true
+}
+
+// getUserIdentity returns the identity of the user of the request. Requests with a bearer token are authenticated
+// with a TokenReview of the token(ex: service account token) and have the role of its user, others need a session
+// of the purser login which has the admin role.
+func getUserIdentity(w http.ResponseWriter, r *http.Request) (auth.Identity, bool) {
+	if token := auth.GetBearerToken(r.Header.Get("Authorization")); token != "" && auth.IsConfigured() {
+		identity, err := auth.Authenticate(token)
+		if err != nil {
+			log.Errorf("unable to authenticate bearer token, err: %v", err)
+			http.Error(w, "invalid token", http.StatusUnauthorized)
+			return auth.Identity{}, false
+		}
+		if identity.Role == "" {
+			log.Errorf("user %s has no role", identity.Username)
+			http.Error(w, "user has no role", http.StatusForbidden)
+			return auth.Identity{}, false
+		}
+		return identity, true
+	}
+
+	session, err := store.Get(r, cookieName)
+	if err != nil {
+		log.Errorf("unable to get session from cookie store, err: %v", err)
+		http.Error(w, "Internal Error", http.StatusInternalServerError)
+		return auth.Identity{}, false
+	}
 	// Check if user is authenticated
 	var usr User
 	usr, convertionSuccess := session.Values["user"].(User)
 	if !convertionSuccess || !usr.Authenticated {
 		http.Redirect(w, r, "/", http.StatusForbidden)
-		return false
+		return auth.Identity{}, false
 	}
-	return true
+	return auth.Identity{Username: usr.Username, Role: auth.Admin}, true
+}
+
+// GetUser returns the authenticated user of the request without responding to it, "token:" and the username of its
+// bearer token or "session:" and the username of its session, empty string if it is not authenticated
+func GetUser(r *http.Request) string {
+	if token := auth.GetBearerToken(r.Header.Get("Authorization")); token != "" && auth.IsConfigured() {
+		identity, err := auth.Authenticate(token)
+		if err != nil {
+			return ""
+		}
+		return "token:" + identity.Username
+	}
+
+	session, err := store.Get(r, cookieName)
+	if err != nil {
+		return ""
+	}
+	usr, convertionSuccess := session.Values["user"].(User)
+	if !convertionSuccess || !usr.Authenticated {
+		return ""
+	}
+	return "session:" + usr.Username
 }
 
 // ChangePassword listens on /auth/changePassword endpoint
@@ -147,7 +227,7 @@ func ChangePassword(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if !query.UpdatePassword(cred.Username, cred.Password, cred.NewPassword) {
+	if !query.UpdatePassword(r.Context(), cred.Username, cred.Password, cred.NewPassword) {
 		w.WriteHeader(http.StatusUnauthorized)
 		return
 	}
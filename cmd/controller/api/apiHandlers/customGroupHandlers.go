@@ -19,7 +19,6 @@ package apiHandlers
 
 import (
 	"encoding/json"
-	"github.com/Sirupsen/logrus"
 	group_v1 "github.com/vmware/purser/pkg/apis/groups/v1"
 	"github.com/vmware/purser/pkg/controller/dgraph/models"
 	"github.com/vmware/purser/pkg/controller/dgraph/models/query"
@@ -33,9 +32,9 @@ func GetGroupsData(w http.ResponseWriter, r *http.Request) {
 	if isUserAuthenticated(w, r) {
 		addHeaders(&w, r)
 
-		groupsData, err := query.RetrieveGroupsData()
+		groupsData, err := query.RetrieveGroupsData(r.Context())
 		if err != nil {
-			logrus.Errorf("unable to retrieve groups data from dgraph, %v", err)
+			log.Errorf("unable to retrieve groups data from dgraph, %v", err)
 		} else {
 			encodeAndWrite(w, groupsData)
 		}
@@ -44,43 +43,43 @@ func GetGroupsData(w http.ResponseWriter, r *http.Request) {
 
 // DeleteGroup listens on /api/group/delete
 func DeleteGroup(w http.ResponseWriter, r *http.Request) {
-	if isUserAuthenticated(w, r) {
+	if isUserAdmin(w, r) {
+		queryParams, isValid := validateRequest(w, r, requireName)
+		if !isValid {
+			return
+		}
 		addAccessControlHeaders(&w, r)
-		queryParams := r.URL.Query()
-		logrus.Debugf("Query params: (%v)", queryParams)
-		var err error
-		if name, isName := queryParams[query.Name]; isName {
-			err = getGroupClient().Delete(name[0], &meta_v1.DeleteOptions{})
-			if err == nil {
-				w.WriteHeader(http.StatusOK)
-				models.DeleteGroup(name[0])
-				return
-			}
+		name := queryParams.Get(query.Name)
+		err := getGroupClient().Delete(name, &meta_v1.DeleteOptions{})
+		if err == nil {
+			w.WriteHeader(http.StatusOK)
+			models.DeleteGroup(name)
+			return
 		}
-		logrus.Errorf("unable to delete: query params: %v, err: %v", queryParams, err)
+		log.Errorf("unable to delete: query params: %v, err: %v", queryParams, err)
 		http.Error(w, err.Error(), http.StatusBadRequest)
 	}
 }
 
 // CreateGroup listens on /api/group/create
 func CreateGroup(w http.ResponseWriter, r *http.Request) {
-	if isUserAuthenticated(w, r) {
+	if isUserAdmin(w, r) {
 		addAccessControlHeaders(&w, r)
 		groupData, err := convertRequestBodyToJSON(r)
 		if err != nil {
-			logrus.Errorf("unable to parse request as either JSON or YAML, err: %v", err)
+			log.Errorf("unable to parse request as either JSON or YAML, err: %v", err)
 			http.Error(w, err.Error(), http.StatusBadRequest)
 			return
 		}
 
 		newGroup := group_v1.Group{}
 		if jsonErr := json.Unmarshal(groupData, &newGroup); jsonErr != nil {
-			logrus.Errorf("unable to parse object as group, err: %v", jsonErr)
+			log.Errorf("unable to parse object as group, err: %v", jsonErr)
 			http.Error(w, jsonErr.Error(), http.StatusBadRequest)
 			return
 		}
 		if _, groupErr := getGroupClient().Create(&newGroup); groupErr != nil {
-			logrus.Errorf("unable to create group: %v", groupErr)
+			log.Errorf("unable to create group: %v", groupErr)
 			http.Error(w, groupErr.Error(), http.StatusBadRequest)
 			return
 		}
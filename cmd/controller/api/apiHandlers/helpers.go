@@ -19,16 +19,19 @@ package apiHandlers
 
 import (
 	"encoding/json"
-	"github.com/Sirupsen/logrus"
 	"io"
 	"io/ioutil"
 	"k8s.io/apimachinery/pkg/util/yaml"
 	"net/http"
 	"github.com/vmware/purser/pkg/controller"
+	"github.com/vmware/purser/pkg/controller/dgraph/models/query"
 	"github.com/vmware/purser/pkg/client/clientset/typed/groups/v1"
 	"k8s.io/client-go/kubernetes"
+	"github.com/vmware/purser/pkg/logging"
 )
 
+var log = logging.Component("api")
+
 var groupClient *v1.GroupClient
 var kubeClient *kubernetes.Clientset
 
@@ -41,19 +44,35 @@ func addHeaders(w *http.ResponseWriter, r *http.Request) {
 func addAccessControlHeaders(w *http.ResponseWriter, r *http.Request) {
 	(*w).Header().Set("Access-Control-Allow-Origin", r.Header.Get("Origin"))
 	(*w).Header().Set("Access-Control-Allow-Credentials", "true")
+	(*w).Header().Set("Access-Control-Expose-Headers", "X-Request-ID")
 }
 
+// writeBytes writes the JSON response without the fields which are not allowed by the field policy
 func writeBytes(w io.Writer, data []byte) {
-	_, err := w.Write(data)
+	data, err := query.FilterFields(data)
+	if err != nil {
+		log.Errorf("Unable to filter fields of json: (%v)", err)
+		return
+	}
+	_, err = w.Write(data)
 	if err != nil {
-		logrus.Errorf("Unable to encode to json: (%v)", err)
+		log.Errorf("Unable to encode to json: (%v)", err)
 	}
 }
 
 func encodeAndWrite(w io.Writer, obj interface{}) {
+	if query.HasFieldPolicy() {
+		data, err := json.Marshal(obj)
+		if err != nil {
+			log.Errorf("Unable to encode to json: (%v)", err)
+			return
+		}
+		writeBytes(w, data)
+		return
+	}
 	err := json.NewEncoder(w).Encode(obj)
 	if err != nil {
-		logrus.Errorf("Unable to encode to json: (%v)", err)
+		log.Errorf("Unable to encode to json: (%v)", err)
 	}
 }
 
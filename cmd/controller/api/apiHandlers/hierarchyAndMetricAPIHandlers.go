@@ -18,15 +18,19 @@
 package apiHandlers
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
+	"io/ioutil"
 	"net/http"
+	"strconv"
 
-	"github.com/Sirupsen/logrus"
+	"github.com/vmware/purser/pkg/controller/chargeback"
+	"github.com/vmware/purser/pkg/controller/dgraph"
 	"github.com/vmware/purser/pkg/controller/dgraph/models"
 	"github.com/vmware/purser/pkg/controller/dgraph/models/query"
 	"github.com/vmware/purser/pkg/controller/discovery/generator"
 	"github.com/vmware/purser/pkg/controller/eventprocessor"
+	"github.com/vmware/purser/pkg/controller/exporter"
 )
 
 // GetHomePage is the default api home page
@@ -34,26 +38,47 @@ func GetHomePage(w http.ResponseWriter, r *http.Request) {
 	if isUserAuthenticated(w, r) {
 		_, err := fmt.Fprintf(w, "Welcome to the Purser!")
 		if err != nil {
-			logrus.Errorf("Unable to write welcome message to Homepage: (%v)", err)
+			log.Errorf("Unable to write welcome message to Homepage: (%v)", err)
 		}
 	}
 }
 
-// GetPodInteractions listens on /interactions/pod endpoint and returns pod interactions
+// GetPrometheusMetrics listens on /metrics and returns month to date costs of live pods, namespaces and nodes as
+// of the last refresh in Prometheus text format. It needs no login so that Prometheus can scrape it.
+func GetPrometheusMetrics(w http.ResponseWriter, r *http.Request) {
+	if !exporter.IsConfigured() {
+		http.Error(w, "prometheus exporter is disabled, enable it with --metricsRefreshInterval", http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", exporter.ContentType)
+	isRefreshed, err := exporter.WriteMetrics(w)
+	if err != nil {
+		log.Errorf("unable to write metrics: %v", err)
+	} else if !isRefreshed {
+		http.Error(w, "costs are not exported yet", http.StatusServiceUnavailable)
+	}
+}
+
+// GetPodInteractions listens on /interactions/pod endpoint and returns pod interactions, restricted to pods
+// of the namespace if it is given
 func GetPodInteractions(w http.ResponseWriter, r *http.Request) {
 	if isUserAuthenticated(w, r) {
+		queryParams, isValid := validateRequest(w, r, validateName, validateNamespace, validateOrphan, validatePagination)
+		if !isValid {
+			return
+		}
 		addHeaders(&w, r)
-		queryParams := r.URL.Query()
-		logrus.Debugf("Query params: (%v)", queryParams)
 
 		var jsonResp []byte
+		namespace := queryParams.Get(query.Namespace)
 		if name, isName := queryParams[query.Name]; isName {
-			jsonResp = query.RetrievePodsInteractions(name[0], false)
+			jsonResp = query.RetrievePodsInteractionsRaw(r.Context(), name[0], namespace, false, query.Page{})
 		} else {
+			page := getPage(queryParams)
 			if orphanVal, isOrphan := queryParams[query.Orphan]; isOrphan && orphanVal[0] == query.False {
-				jsonResp = query.RetrievePodsInteractions(query.All, false)
+				jsonResp = query.RetrievePodsInteractionsRaw(r.Context(), query.All, namespace, false, page)
 			} else {
-				jsonResp = query.RetrievePodsInteractions(query.All, true)
+				jsonResp = query.RetrievePodsInteractionsRaw(r.Context(), query.All, namespace, true, page)
 			}
 		}
 		writeBytes(w, jsonResp)
@@ -63,15 +88,17 @@ func GetPodInteractions(w http.ResponseWriter, r *http.Request) {
 // GetClusterHierarchy listens on /hierarchy endpoint and returns all namespaces(or nodes and PV) in the cluster
 func GetClusterHierarchy(w http.ResponseWriter, r *http.Request) {
 	if isUserAuthenticated(w, r) {
+		queryParams, isValid := validateRequest(w, r, validateView, validateAsOf, validateDeleted)
+		if !isValid {
+			return
+		}
 		addHeaders(&w, r)
-		queryParams := r.URL.Query()
-		logrus.Debugf("Query params: (%v)", queryParams)
 
 		var jsonData query.JSONDataWrapper
 		if view, isView := queryParams[query.View]; isView && view[0] == query.Physical {
-			jsonData = query.RetrieveClusterHierarchy(query.Physical)
+			jsonData = query.RetrieveClusterHierarchyAsOf(r.Context(), query.Physical, queryParams.Get(query.AsOf))
 		} else {
-			jsonData = query.RetrieveClusterHierarchy(query.Logical)
+			jsonData = query.RetrieveClusterHierarchyWithDeleted(r.Context(), query.Logical, queryParams.Get(query.AsOf), queryParams.Get(query.Deleted))
 		}
 		encodeAndWrite(w, jsonData)
 	}
@@ -80,9 +107,11 @@ func GetClusterHierarchy(w http.ResponseWriter, r *http.Request) {
 // GetNamespaceHierarchy listens on /hierarchy/namespace endpoint and returns all children of namespace
 func GetNamespaceHierarchy(w http.ResponseWriter, r *http.Request) {
 	if isUserAuthenticated(w, r) {
+		queryParams, isValid := validateRequest(w, r, validateName, validateMatch, validateAsOf, validateDeleted, validatePagination)
+		if !isValid {
+			return
+		}
 		addHeaders(&w, r)
-		queryParams := r.URL.Query()
-		logrus.Debugf("Query params: (%v)", queryParams)
 
 		var jsonData query.JSONDataWrapper
 		if name, isName := queryParams[query.Name]; isName {
@@ -91,10 +120,13 @@ func GetNamespaceHierarchy(w http.ResponseWriter, r *http.Request) {
 				Type:        query.NamespaceType,
 				Name:        name[0],
 				ChildFilter: query.NamespaceChildFilter,
+				Page:        getPage(queryParams),
+				AsOf:        queryParams.Get(query.AsOf),
+				Match:       queryParams.Get(query.Match),
 			}
-			jsonData = resourceQuery.RetrieveResourceHierarchy()
+			jsonData = resourceQuery.RetrieveResourceHierarchy(r.Context())
 		} else {
-			jsonData = query.RetrieveClusterHierarchy(query.Logical)
+			jsonData = query.RetrieveClusterHierarchyWithDeleted(r.Context(), query.Logical, queryParams.Get(query.AsOf), queryParams.Get(query.Deleted))
 		}
 		encodeAndWrite(w, jsonData)
 	}
@@ -103,22 +135,45 @@ func GetNamespaceHierarchy(w http.ResponseWriter, r *http.Request) {
 // GetDeploymentHierarchy listens on /hierarchy/deployment endpoint and returns all children of deployment
 func GetDeploymentHierarchy(w http.ResponseWriter, r *http.Request) {
 	if isUserAuthenticated(w, r) {
+		queryParams, isValid := validateRequest(w, r, requireName, validateMatch, validateAsOf, validatePagination)
+		if !isValid {
+			return
+		}
 		addHeaders(&w, r)
-		queryParams := r.URL.Query()
-		logrus.Debugf("Query params: (%v)", queryParams)
 
-		var jsonData query.JSONDataWrapper
-		if name, isName := queryParams[query.Name]; isName {
-			resourceQuery := query.Resource{
-				Check:       query.DeploymentCheck,
-				Type:        query.DeploymentType,
-				Name:        name[0],
-				ChildFilter: query.IsReplicasetFilter,
-			}
-			jsonData = resourceQuery.RetrieveResourceHierarchy()
-		} else {
-			logrus.Errorf("wrong type of query for deployment, no name is given")
+		resourceQuery := query.Resource{
+			Check:       query.DeploymentCheck,
+			Type:        query.DeploymentType,
+			Name:        queryParams.Get(query.Name),
+			ChildFilter: query.IsReplicasetFilter,
+			Page:        getPage(queryParams),
+			AsOf:        queryParams.Get(query.AsOf),
+			Match:       queryParams.Get(query.Match),
+		}
+		jsonData := resourceQuery.RetrieveResourceHierarchy(r.Context())
+		encodeAndWrite(w, jsonData)
+	}
+}
+
+// GetDeploymentConfigHierarchy listens on /hierarchy/deploymentconfig endpoint and returns all children of OpenShift deployment config
+func GetDeploymentConfigHierarchy(w http.ResponseWriter, r *http.Request) {
+	if isUserAuthenticated(w, r) {
+		queryParams, isValid := validateRequest(w, r, requireName, validateMatch, validateAsOf, validatePagination)
+		if !isValid {
+			return
 		}
+		addHeaders(&w, r)
+
+		resourceQuery := query.Resource{
+			Check:       query.DeploymentConfigCheck,
+			Type:        query.DeploymentConfigType,
+			Name:        queryParams.Get(query.Name),
+			ChildFilter: query.IsPodFilter,
+			Page:        getPage(queryParams),
+			AsOf:        queryParams.Get(query.AsOf),
+			Match:       queryParams.Get(query.Match),
+		}
+		jsonData := resourceQuery.RetrieveResourceHierarchy(r.Context())
 		encodeAndWrite(w, jsonData)
 	}
 }
@@ -126,22 +181,22 @@ func GetDeploymentHierarchy(w http.ResponseWriter, r *http.Request) {
 // GetReplicasetHierarchy listens on /hierarchy/replicaset endpoint and returns all children of replicaset
 func GetReplicasetHierarchy(w http.ResponseWriter, r *http.Request) {
 	if isUserAuthenticated(w, r) {
+		queryParams, isValid := validateRequest(w, r, requireName, validateMatch, validateAsOf, validatePagination)
+		if !isValid {
+			return
+		}
 		addHeaders(&w, r)
-		queryParams := r.URL.Query()
-		logrus.Debugf("Query params: (%v)", queryParams)
 
-		var jsonData query.JSONDataWrapper
-		if name, isName := queryParams[query.Name]; isName {
-			resourceQuery := query.Resource{
-				Check:       query.ReplicasetCheck,
-				Type:        query.ReplicasetType,
-				Name:        name[0],
-				ChildFilter: query.IsPodFilter,
-			}
-			jsonData = resourceQuery.RetrieveResourceHierarchy()
-		} else {
-			logrus.Errorf("wrong type of query for replicaset, no name is given")
+		resourceQuery := query.Resource{
+			Check:       query.ReplicasetCheck,
+			Type:        query.ReplicasetType,
+			Name:        queryParams.Get(query.Name),
+			ChildFilter: query.IsPodFilter,
+			Page:        getPage(queryParams),
+			AsOf:        queryParams.Get(query.AsOf),
+			Match:       queryParams.Get(query.Match),
 		}
+		jsonData := resourceQuery.RetrieveResourceHierarchy(r.Context())
 		encodeAndWrite(w, jsonData)
 	}
 }
@@ -149,22 +204,22 @@ func GetReplicasetHierarchy(w http.ResponseWriter, r *http.Request) {
 // GetStatefulsetHierarchy listens on /hierarchy/statefulset endpoint and returns all children of statefulset
 func GetStatefulsetHierarchy(w http.ResponseWriter, r *http.Request) {
 	if isUserAuthenticated(w, r) {
+		queryParams, isValid := validateRequest(w, r, requireName, validateMatch, validateAsOf, validatePagination)
+		if !isValid {
+			return
+		}
 		addHeaders(&w, r)
-		queryParams := r.URL.Query()
-		logrus.Debugf("Query params: (%v)", queryParams)
 
-		var jsonData query.JSONDataWrapper
-		if name, isName := queryParams[query.Name]; isName {
-			resourceQuery := query.Resource{
-				Check:       query.StatefulsetCheck,
-				Type:        query.StatefulsetType,
-				Name:        name[0],
-				ChildFilter: query.IsPodFilter,
-			}
-			jsonData = resourceQuery.RetrieveResourceHierarchy()
-		} else {
-			logrus.Errorf("wrong type of query for statefulset, no name is given")
+		resourceQuery := query.Resource{
+			Check:       query.StatefulsetCheck,
+			Type:        query.StatefulsetType,
+			Name:        queryParams.Get(query.Name),
+			ChildFilter: query.IsPodFilter,
+			Page:        getPage(queryParams),
+			AsOf:        queryParams.Get(query.AsOf),
+			Match:       queryParams.Get(query.Match),
 		}
+		jsonData := resourceQuery.RetrieveResourceHierarchy(r.Context())
 		encodeAndWrite(w, jsonData)
 	}
 }
@@ -172,22 +227,22 @@ func GetStatefulsetHierarchy(w http.ResponseWriter, r *http.Request) {
 // GetPodHierarchy listens on /hierarchy/pod endpoint and returns all children of pod
 func GetPodHierarchy(w http.ResponseWriter, r *http.Request) {
 	if isUserAuthenticated(w, r) {
+		queryParams, isValid := validateRequest(w, r, requireName, validateMatch, validateAsOf, validatePagination)
+		if !isValid {
+			return
+		}
 		addHeaders(&w, r)
-		queryParams := r.URL.Query()
-		logrus.Debugf("Query params: (%v)", queryParams)
 
-		var jsonData query.JSONDataWrapper
-		if name, isName := queryParams[query.Name]; isName {
-			resourceQuery := query.Resource{
-				Check:       query.PodCheck,
-				Type:        query.PodType,
-				Name:        name[0],
-				ChildFilter: query.IsContainerFilter,
-			}
-			jsonData = resourceQuery.RetrieveResourceHierarchy()
-		} else {
-			logrus.Errorf("wrong type of query for pod, no name is given")
+		resourceQuery := query.Resource{
+			Check:       query.PodCheck,
+			Type:        query.PodType,
+			Name:        queryParams.Get(query.Name),
+			ChildFilter: query.IsContainerFilter,
+			Page:        getPage(queryParams),
+			AsOf:        queryParams.Get(query.AsOf),
+			Match:       queryParams.Get(query.Match),
 		}
+		jsonData := resourceQuery.RetrieveResourceHierarchy(r.Context())
 		encodeAndWrite(w, jsonData)
 	}
 }
@@ -195,22 +250,21 @@ func GetPodHierarchy(w http.ResponseWriter, r *http.Request) {
 // GetContainerHierarchy listens on /hierarchy/container endpoint and returns all children of container
 func GetContainerHierarchy(w http.ResponseWriter, r *http.Request) {
 	if isUserAuthenticated(w, r) {
+		queryParams, isValid := validateRequest(w, r, requireName, validateMatch, validatePagination)
+		if !isValid {
+			return
+		}
 		addHeaders(&w, r)
-		queryParams := r.URL.Query()
-		logrus.Debugf("Query params: (%v)", queryParams)
 
-		var jsonData query.JSONDataWrapper
-		if name, isName := queryParams[query.Name]; isName {
-			resourceQuery := query.Resource{
-				Check:       query.ContainerCheck,
-				Type:        query.ContainerType,
-				Name:        name[0],
-				ChildFilter: query.IsProcFilter,
-			}
-			jsonData = resourceQuery.RetrieveResourceHierarchy()
-		} else {
-			logrus.Errorf("wrong type of query for container, no name is given")
+		resourceQuery := query.Resource{
+			Check:       query.ContainerCheck,
+			Type:        query.ContainerType,
+			Name:        queryParams.Get(query.Name),
+			ChildFilter: query.IsProcFilter,
+			Page:        getPage(queryParams),
+			Match:       queryParams.Get(query.Match),
 		}
+		jsonData := resourceQuery.RetrieveResourceHierarchy(r.Context())
 		encodeAndWrite(w, jsonData)
 	}
 }
@@ -218,9 +272,10 @@ func GetContainerHierarchy(w http.ResponseWriter, r *http.Request) {
 // GetEmptyHierarchy listens on /hierarchy/process and /hierarchy/pvc endpoint and returns empty data
 func GetEmptyHierarchy(w http.ResponseWriter, r *http.Request) {
 	if isUserAuthenticated(w, r) {
+		if _, isValid := validateRequest(w, r, validateName, validateMatch); !isValid {
+			return
+		}
 		addHeaders(&w, r)
-		queryParams := r.URL.Query()
-		logrus.Debugf("Query params: (%v)", queryParams)
 
 		var jsonData query.JSONDataWrapper
 		encodeAndWrite(w, jsonData)
@@ -230,22 +285,22 @@ func GetEmptyHierarchy(w http.ResponseWriter, r *http.Request) {
 // GetNodeHierarchy listens on /hierarchy/node endpoint and returns all children of node
 func GetNodeHierarchy(w http.ResponseWriter, r *http.Request) {
 	if isUserAuthenticated(w, r) {
+		queryParams, isValid := validateRequest(w, r, requireName, validateMatch, validateAsOf, validatePagination)
+		if !isValid {
+			return
+		}
 		addHeaders(&w, r)
-		queryParams := r.URL.Query()
-		logrus.Debugf("Query params: (%v)", queryParams)
 
-		var jsonData query.JSONDataWrapper
-		if name, isName := queryParams[query.Name]; isName {
-			resourceQuery := query.Resource{
-				Check:       query.NodeCheck,
-				Type:        query.NodeType,
-				Name:        name[0],
-				ChildFilter: query.IsPodFilter,
-			}
-			jsonData = resourceQuery.RetrieveResourceHierarchy()
-		} else {
-			logrus.Errorf("wrong type of query for node, no name is given")
+		resourceQuery := query.Resource{
+			Check:       query.NodeCheck,
+			Type:        query.NodeType,
+			Name:        queryParams.Get(query.Name),
+			ChildFilter: query.IsPodFilter,
+			Page:        getPage(queryParams),
+			AsOf:        queryParams.Get(query.AsOf),
+			Match:       queryParams.Get(query.Match),
 		}
+		jsonData := resourceQuery.RetrieveResourceHierarchy(r.Context())
 		encodeAndWrite(w, jsonData)
 	}
 }
@@ -253,22 +308,22 @@ func GetNodeHierarchy(w http.ResponseWriter, r *http.Request) {
 // GetPVHierarchy listens on /hierarchy/pv endpoint and returns all children of PV
 func GetPVHierarchy(w http.ResponseWriter, r *http.Request) {
 	if isUserAuthenticated(w, r) {
+		queryParams, isValid := validateRequest(w, r, requireName, validateMatch, validateAsOf, validatePagination)
+		if !isValid {
+			return
+		}
 		addHeaders(&w, r)
-		queryParams := r.URL.Query()
-		logrus.Debugf("Query params: (%v)", queryParams)
 
-		var jsonData query.JSONDataWrapper
-		if name, isName := queryParams[query.Name]; isName {
-			resourceQuery := query.Resource{
-				Check:       query.PVCheck,
-				Type:        query.PVType,
-				Name:        name[0],
-				ChildFilter: query.IsPVCFilter,
-			}
-			jsonData = resourceQuery.RetrieveResourceHierarchy()
-		} else {
-			logrus.Errorf("wrong type of query for PV, no name is given")
+		resourceQuery := query.Resource{
+			Check:       query.PVCheck,
+			Type:        query.PVType,
+			Name:        queryParams.Get(query.Name),
+			ChildFilter: query.IsPVCFilter,
+			Page:        getPage(queryParams),
+			AsOf:        queryParams.Get(query.AsOf),
+			Match:       queryParams.Get(query.Match),
 		}
+		jsonData := resourceQuery.RetrieveResourceHierarchy(r.Context())
 		encodeAndWrite(w, jsonData)
 	}
 }
@@ -276,22 +331,22 @@ func GetPVHierarchy(w http.ResponseWriter, r *http.Request) {
 // GetDaemonsetHierarchy listens on /hierarchy/daemonset endpoint and returns all children of Daemonset
 func GetDaemonsetHierarchy(w http.ResponseWriter, r *http.Request) {
 	if isUserAuthenticated(w, r) {
+		queryParams, isValid := validateRequest(w, r, requireName, validateMatch, validateAsOf, validatePagination)
+		if !isValid {
+			return
+		}
 		addHeaders(&w, r)
-		queryParams := r.URL.Query()
-		logrus.Debugf("Query params: (%v)", queryParams)
 
-		var jsonData query.JSONDataWrapper
-		if name, isName := queryParams[query.Name]; isName {
-			resourceQuery := query.Resource{
-				Check:       query.DaemonsetCheck,
-				Type:        query.DaemonsetType,
-				Name:        name[0],
-				ChildFilter: query.IsPodFilter,
-			}
-			jsonData = resourceQuery.RetrieveResourceHierarchy()
-		} else {
-			logrus.Errorf("wrong type of query for Daemonset, no name is given")
+		resourceQuery := query.Resource{
+			Check:       query.DaemonsetCheck,
+			Type:        query.DaemonsetType,
+			Name:        queryParams.Get(query.Name),
+			ChildFilter: query.IsPodFilter,
+			Page:        getPage(queryParams),
+			AsOf:        queryParams.Get(query.AsOf),
+			Match:       queryParams.Get(query.Match),
 		}
+		jsonData := resourceQuery.RetrieveResourceHierarchy(r.Context())
 		encodeAndWrite(w, jsonData)
 	}
 }
@@ -299,63 +354,76 @@ func GetDaemonsetHierarchy(w http.ResponseWriter, r *http.Request) {
 // GetJobHierarchy listens on /hierarchy/job endpoint and returns all children of Job
 func GetJobHierarchy(w http.ResponseWriter, r *http.Request) {
 	if isUserAuthenticated(w, r) {
+		queryParams, isValid := validateRequest(w, r, requireName, validateMatch, validateAsOf, validatePagination)
+		if !isValid {
+			return
+		}
 		addHeaders(&w, r)
-		queryParams := r.URL.Query()
-		logrus.Debugf("Query params: (%v)", queryParams)
 
-		var jsonData query.JSONDataWrapper
-		if name, isName := queryParams[query.Name]; isName {
-			resourceQuery := query.Resource{
-				Check:       query.JobCheck,
-				Type:        query.JobType,
-				Name:        name[0],
-				ChildFilter: query.IsPodFilter,
-			}
-			jsonData = resourceQuery.RetrieveResourceHierarchy()
-		} else {
-			logrus.Errorf("wrong type of query for Job, no name is given")
+		resourceQuery := query.Resource{
+			Check:       query.JobCheck,
+			Type:        query.JobType,
+			Name:        queryParams.Get(query.Name),
+			ChildFilter: query.IsPodFilter,
+			Page:        getPage(queryParams),
+			AsOf:        queryParams.Get(query.AsOf),
+			Match:       queryParams.Get(query.Match),
 		}
+		jsonData := resourceQuery.RetrieveResourceHierarchy(r.Context())
 		encodeAndWrite(w, jsonData)
 	}
 }
 
-// GetClusterMetrics listens on /metrics endpoint with option for view(physical or logical)
+// GetClusterMetrics listens on /metrics endpoint with option for view(physical or logical) and os(linux or windows)
 func GetClusterMetrics(w http.ResponseWriter, r *http.Request) {
 	if isUserAuthenticated(w, r) {
+		queryParams, isValid := validateRequest(w, r, validateView, validateOS, validateAsOf, validateDeleted, validateTimeRange)
+		if !isValid {
+			return
+		}
 		addHeaders(&w, r)
-		queryParams := r.URL.Query()
-		logrus.Debugf("Query params: (%v)", queryParams)
 
 		var jsonData query.JSONDataWrapper
+		os := queryParams.Get(query.OS)
 		if view, isView := queryParams[query.View]; isView && view[0] == query.Physical {
-			jsonData = query.RetrieveClusterMetrics(query.Physical)
+			jsonData = query.RetrieveClusterMetricsInRange(r.Context(), query.Physical, os, getTimeRange(queryParams), query.Include)
 		} else {
-			jsonData = query.RetrieveClusterMetrics(query.Logical)
+			jsonData = query.RetrieveClusterMetricsInRange(r.Context(), query.Logical, os, getTimeRange(queryParams), queryParams.Get(query.Deleted))
 		}
-		query.PopulateClusterAllocationAndCapacity(&jsonData)
+		query.PopulateClusterAllocationAndCapacity(r.Context(), &jsonData)
 		encodeAndWrite(w, jsonData)
 	}
 }
 
-// GetNamespaceMetrics listens on /metrics/namespace
+// GetNamespaceMetrics listens on /metrics/namespace with option for os(linux or windows)
 func GetNamespaceMetrics(w http.ResponseWriter, r *http.Request) {
 	if isUserAuthenticated(w, r) {
+		queryParams, isValid := validateRequest(w, r, validateName, validateMatch, validateOS, validateAsOf, validateGroupBy, validateDeleted, validateTimeRange, validateCostMode)
+		if !isValid {
+			return
+		}
 		addHeaders(&w, r)
-		queryParams := r.URL.Query()
-		logrus.Debugf("Query params: (%v)", queryParams)
 
 		var jsonData query.JSONDataWrapper
+		os := queryParams.Get(query.OS)
 		if name, isName := queryParams[query.Name]; isName {
 			resourceQuery := query.Resource{
-				Check: query.NamespaceCheck,
-				Type:  query.NamespaceType,
-				Name:  name[0],
+				Check:    query.NamespaceCheck,
+				Type:     query.NamespaceType,
+				Name:     name[0],
+				Start:    queryParams.Get(query.Start),
+				End:      queryParams.Get(query.End),
+				OS:       os,
+				AsOf:     queryParams.Get(query.AsOf),
+				Match:    queryParams.Get(query.Match),
+				GroupBy:  queryParams.Get(query.GroupBy),
+				CostMode: queryParams.Get(query.CostMode),
 			}
-			jsonData = resourceQuery.RetrieveResourceMetrics()
+			jsonData = resourceQuery.RetrieveResourceMetrics(r.Context())
 		} else {
-			jsonData = query.RetrieveClusterMetrics(query.Logical)
+			jsonData = query.RetrieveClusterMetricsInRange(r.Context(), query.Logical, os, getTimeRange(queryParams), queryParams.Get(query.Deleted))
 		}
-		query.PopulateClusterAllocationAndCapacity(&jsonData)
+		query.PopulateClusterAllocationAndCapacity(r.Context(), &jsonData)
 		encodeAndWrite(w, jsonData)
 	}
 }
@@ -363,22 +431,48 @@ func GetNamespaceMetrics(w http.ResponseWriter, r *http.Request) {
 // GetDeploymentMetrics listens on /metrics/deployment
 func GetDeploymentMetrics(w http.ResponseWriter, r *http.Request) {
 	if isUserAuthenticated(w, r) {
+		queryParams, isValid := validateRequest(w, r, requireName, validateNamespace, validateMatch, validateAsOf, validateTimeRange)
+		if !isValid {
+			return
+		}
 		addHeaders(&w, r)
-		queryParams := r.URL.Query()
-		logrus.Debugf("Query params: (%v)", queryParams)
 
-		var jsonData query.JSONDataWrapper
-		if name, isName := queryParams[query.Name]; isName {
-			resourceQuery := query.Resource{
-				Check: query.DeploymentCheck,
-				Type:  query.DeploymentType,
-				Name:  name[0],
-			}
-			jsonData = resourceQuery.RetrieveResourceMetrics()
-			query.PopulateClusterAllocationAndCapacity(&jsonData)
-		} else {
-			logrus.Errorf("wrong type of query for deployment, no name is given")
+		resourceQuery := query.Resource{
+			Check:     query.DeploymentCheck,
+			Type:      query.DeploymentType,
+			Name:      queryParams.Get(query.Name),
+			Start:     queryParams.Get(query.Start),
+			End:       queryParams.Get(query.End),
+			AsOf:      queryParams.Get(query.AsOf),
+			Match:     queryParams.Get(query.Match),
+			Namespace: queryParams.Get(query.Namespace),
 		}
+		jsonData := resourceQuery.RetrieveResourceMetrics(r.Context())
+		query.PopulateClusterAllocationAndCapacity(r.Context(), &jsonData)
+		encodeAndWrite(w, jsonData)
+	}
+}
+
+// GetDeploymentConfigMetrics listens on /metrics/deploymentconfig
+func GetDeploymentConfigMetrics(w http.ResponseWriter, r *http.Request) {
+	if isUserAuthenticated(w, r) {
+		queryParams, isValid := validateRequest(w, r, requireName, validateMatch, validateAsOf, validateTimeRange)
+		if !isValid {
+			return
+		}
+		addHeaders(&w, r)
+
+		resourceQuery := query.Resource{
+			Check: query.DeploymentConfigCheck,
+			Type:  query.DeploymentConfigType,
+			Name:  queryParams.Get(query.Name),
+			Start: queryParams.Get(query.Start),
+			End:   queryParams.Get(query.End),
+			AsOf:  queryParams.Get(query.AsOf),
+			Match: queryParams.Get(query.Match),
+		}
+		jsonData := resourceQuery.RetrieveResourceMetrics(r.Context())
+		query.PopulateClusterAllocationAndCapacity(r.Context(), &jsonData)
 		encodeAndWrite(w, jsonData)
 	}
 }
@@ -386,22 +480,24 @@ func GetDeploymentMetrics(w http.ResponseWriter, r *http.Request) {
 // GetDaemonsetMetrics listens on /metrics/daemonset
 func GetDaemonsetMetrics(w http.ResponseWriter, r *http.Request) {
 	if isUserAuthenticated(w, r) {
+		queryParams, isValid := validateRequest(w, r, requireName, validateNamespace, validateMatch, validateAsOf, validateTimeRange)
+		if !isValid {
+			return
+		}
 		addHeaders(&w, r)
-		queryParams := r.URL.Query()
-		logrus.Debugf("Query params: (%v)", queryParams)
 
-		var jsonData query.JSONDataWrapper
-		if name, isName := queryParams[query.Name]; isName {
-			resourceQuery := query.Resource{
-				Check: query.DaemonsetCheck,
-				Type:  query.DaemonsetType,
-				Name:  name[0],
-			}
-			jsonData = resourceQuery.RetrieveResourceMetrics()
-			query.PopulateClusterAllocationAndCapacity(&jsonData)
-		} else {
-			logrus.Errorf("wrong type of query for daemonset, no name is given")
+		resourceQuery := query.Resource{
+			Check:     query.DaemonsetCheck,
+			Type:      query.DaemonsetType,
+			Name:      queryParams.Get(query.Name),
+			Start:     queryParams.Get(query.Start),
+			End:       queryParams.Get(query.End),
+			AsOf:      queryParams.Get(query.AsOf),
+			Match:     queryParams.Get(query.Match),
+			Namespace: queryParams.Get(query.Namespace),
 		}
+		jsonData := resourceQuery.RetrieveResourceMetrics(r.Context())
+		query.PopulateClusterAllocationAndCapacity(r.Context(), &jsonData)
 		encodeAndWrite(w, jsonData)
 	}
 }
@@ -409,22 +505,49 @@ func GetDaemonsetMetrics(w http.ResponseWriter, r *http.Request) {
 // GetJobMetrics listens on /metrics/job
 func GetJobMetrics(w http.ResponseWriter, r *http.Request) {
 	if isUserAuthenticated(w, r) {
+		queryParams, isValid := validateRequest(w, r, requireName, validateNamespace, validateMatch, validateAsOf, validateTimeRange)
+		if !isValid {
+			return
+		}
 		addHeaders(&w, r)
-		queryParams := r.URL.Query()
-		logrus.Debugf("Query params: (%v)", queryParams)
 
-		var jsonData query.JSONDataWrapper
-		if name, isName := queryParams[query.Name]; isName {
-			resourceQuery := query.Resource{
-				Check: query.JobCheck,
-				Type:  query.JobType,
-				Name:  name[0],
-			}
-			jsonData = resourceQuery.RetrieveResourceMetrics()
-			query.PopulateClusterAllocationAndCapacity(&jsonData)
-		} else {
-			logrus.Errorf("wrong type of query for job, no name is given")
+		resourceQuery := query.Resource{
+			Check:     query.JobCheck,
+			Type:      query.JobType,
+			Name:      queryParams.Get(query.Name),
+			Start:     queryParams.Get(query.Start),
+			End:       queryParams.Get(query.End),
+			AsOf:      queryParams.Get(query.AsOf),
+			Match:     queryParams.Get(query.Match),
+			Namespace: queryParams.Get(query.Namespace),
 		}
+		jsonData := resourceQuery.RetrieveResourceMetrics(r.Context())
+		query.PopulateClusterAllocationAndCapacity(r.Context(), &jsonData)
+		encodeAndWrite(w, jsonData)
+	}
+}
+
+// GetCronJobMetrics listens on /metrics/cronjob
+func GetCronJobMetrics(w http.ResponseWriter, r *http.Request) {
+	if isUserAuthenticated(w, r) {
+		queryParams, isValid := validateRequest(w, r, requireName, validateNamespace, validateMatch, validateAsOf, validateTimeRange)
+		if !isValid {
+			return
+		}
+		addHeaders(&w, r)
+
+		resourceQuery := query.Resource{
+			Check:     query.CronJobCheck,
+			Type:      query.CronJobType,
+			Name:      queryParams.Get(query.Name),
+			Start:     queryParams.Get(query.Start),
+			End:       queryParams.Get(query.End),
+			AsOf:      queryParams.Get(query.AsOf),
+			Match:     queryParams.Get(query.Match),
+			Namespace: queryParams.Get(query.Namespace),
+		}
+		jsonData := resourceQuery.RetrieveResourceMetrics(r.Context())
+		query.PopulateClusterAllocationAndCapacity(r.Context(), &jsonData)
 		encodeAndWrite(w, jsonData)
 	}
 }
@@ -432,22 +555,24 @@ func GetJobMetrics(w http.ResponseWriter, r *http.Request) {
 // GetStatefulsetMetrics listens on /metrics/statefulset
 func GetStatefulsetMetrics(w http.ResponseWriter, r *http.Request) {
 	if isUserAuthenticated(w, r) {
+		queryParams, isValid := validateRequest(w, r, requireName, validateNamespace, validateMatch, validateAsOf, validateTimeRange)
+		if !isValid {
+			return
+		}
 		addHeaders(&w, r)
-		queryParams := r.URL.Query()
-		logrus.Debugf("Query params: (%v)", queryParams)
 
-		var jsonData query.JSONDataWrapper
-		if name, isName := queryParams[query.Name]; isName {
-			resourceQuery := query.Resource{
-				Check: query.StatefulsetCheck,
-				Type:  query.StatefulsetType,
-				Name:  name[0],
-			}
-			jsonData = resourceQuery.RetrieveResourceMetrics()
-			query.PopulateClusterAllocationAndCapacity(&jsonData)
-		} else {
-			logrus.Errorf("wrong type of query for statefulset, no name is given")
+		resourceQuery := query.Resource{
+			Check:     query.StatefulsetCheck,
+			Type:      query.StatefulsetType,
+			Name:      queryParams.Get(query.Name),
+			Start:     queryParams.Get(query.Start),
+			End:       queryParams.Get(query.End),
+			AsOf:      queryParams.Get(query.AsOf),
+			Match:     queryParams.Get(query.Match),
+			Namespace: queryParams.Get(query.Namespace),
 		}
+		jsonData := resourceQuery.RetrieveResourceMetrics(r.Context())
+		query.PopulateClusterAllocationAndCapacity(r.Context(), &jsonData)
 		encodeAndWrite(w, jsonData)
 	}
 }
@@ -455,22 +580,24 @@ func GetStatefulsetMetrics(w http.ResponseWriter, r *http.Request) {
 // GetReplicasetMetrics listens on /metrics/replicaset
 func GetReplicasetMetrics(w http.ResponseWriter, r *http.Request) {
 	if isUserAuthenticated(w, r) {
+		queryParams, isValid := validateRequest(w, r, requireName, validateNamespace, validateMatch, validateAsOf, validateTimeRange)
+		if !isValid {
+			return
+		}
 		addHeaders(&w, r)
-		queryParams := r.URL.Query()
-		logrus.Debugf("Query params: (%v)", queryParams)
 
-		var jsonData query.JSONDataWrapper
-		if name, isName := queryParams[query.Name]; isName {
-			resourceQuery := query.Resource{
-				Check: query.ReplicasetCheck,
-				Type:  query.ReplicasetType,
-				Name:  name[0],
-			}
-			jsonData = resourceQuery.RetrieveResourceMetrics()
-			query.PopulateClusterAllocationAndCapacity(&jsonData)
-		} else {
-			logrus.Errorf("wrong type of query for statefulset, no name is given")
+		resourceQuery := query.Resource{
+			Check:     query.ReplicasetCheck,
+			Type:      query.ReplicasetType,
+			Name:      queryParams.Get(query.Name),
+			Start:     queryParams.Get(query.Start),
+			End:       queryParams.Get(query.End),
+			AsOf:      queryParams.Get(query.AsOf),
+			Match:     queryParams.Get(query.Match),
+			Namespace: queryParams.Get(query.Namespace),
 		}
+		jsonData := resourceQuery.RetrieveResourceMetrics(r.Context())
+		query.PopulateClusterAllocationAndCapacity(r.Context(), &jsonData)
 		encodeAndWrite(w, jsonData)
 	}
 }
@@ -478,22 +605,23 @@ func GetReplicasetMetrics(w http.ResponseWriter, r *http.Request) {
 // GetNodeMetrics listens on /metrics/node
 func GetNodeMetrics(w http.ResponseWriter, r *http.Request) {
 	if isUserAuthenticated(w, r) {
+		queryParams, isValid := validateRequest(w, r, requireName, validateMatch, validateTimeRange, validateCostMode)
+		if !isValid {
+			return
+		}
 		addHeaders(&w, r)
-		queryParams := r.URL.Query()
-		logrus.Debugf("Query params: (%v)", queryParams)
 
-		var jsonData query.JSONDataWrapper
-		if name, isName := queryParams[query.Name]; isName {
-			resourceQuery := query.Resource{
-				Check: query.NodeCheck,
-				Type:  query.NodeType,
-				Name:  name[0],
-			}
-			jsonData = resourceQuery.RetrieveResourceMetrics()
-			resourceQuery.PopulateNodeOrPVAllocationAndCapacity(&jsonData)
-		} else {
-			logrus.Errorf("wrong type of query for node, no name is given")
+		resourceQuery := query.Resource{
+			Check:    query.NodeCheck,
+			Type:     query.NodeType,
+			Name:     queryParams.Get(query.Name),
+			Start:    queryParams.Get(query.Start),
+			End:      queryParams.Get(query.End),
+			Match:    queryParams.Get(query.Match),
+			CostMode: queryParams.Get(query.CostMode),
 		}
+		jsonData := resourceQuery.RetrieveResourceMetrics(r.Context())
+		resourceQuery.PopulateNodeOrPVAllocationAndCapacity(r.Context(), &jsonData)
 		encodeAndWrite(w, jsonData)
 	}
 }
@@ -501,22 +629,24 @@ func GetNodeMetrics(w http.ResponseWriter, r *http.Request) {
 // GetPodMetrics listens on /metrics/pod
 func GetPodMetrics(w http.ResponseWriter, r *http.Request) {
 	if isUserAuthenticated(w, r) {
+		queryParams, isValid := validateRequest(w, r, requireName, validateNamespace, validateMatch, validateTimeRange, validateCostMode)
+		if !isValid {
+			return
+		}
 		addHeaders(&w, r)
-		queryParams := r.URL.Query()
-		logrus.Debugf("Query params: (%v)", queryParams)
 
-		var jsonData query.JSONDataWrapper
-		if name, isName := queryParams[query.Name]; isName {
-			resourceQuery := query.Resource{
-				Check: query.PodCheck,
-				Type:  query.PodType,
-				Name:  name[0],
-			}
-			jsonData = resourceQuery.RetrieveResourceMetrics()
-			query.PopulateClusterAllocationAndCapacity(&jsonData)
-		} else {
-			logrus.Errorf("wrong type of query for pod, no name is given")
+		resourceQuery := query.Resource{
+			Check:     query.PodCheck,
+			Type:      query.PodType,
+			Name:      queryParams.Get(query.Name),
+			Start:     queryParams.Get(query.Start),
+			End:       queryParams.Get(query.End),
+			Match:     queryParams.Get(query.Match),
+			Namespace: queryParams.Get(query.Namespace),
+			CostMode:  queryParams.Get(query.CostMode),
 		}
+		jsonData := resourceQuery.RetrieveResourceMetrics(r.Context())
+		query.PopulateClusterAllocationAndCapacity(r.Context(), &jsonData)
 		encodeAndWrite(w, jsonData)
 	}
 }
@@ -524,22 +654,23 @@ func GetPodMetrics(w http.ResponseWriter, r *http.Request) {
 // GetContainerMetrics listens on /metrics/container
 func GetContainerMetrics(w http.ResponseWriter, r *http.Request) {
 	if isUserAuthenticated(w, r) {
+		queryParams, isValid := validateRequest(w, r, requireName, validateNamespace, validateMatch, validateTimeRange)
+		if !isValid {
+			return
+		}
 		addHeaders(&w, r)
-		queryParams := r.URL.Query()
-		logrus.Debugf("Query params: (%v)", queryParams)
 
-		var jsonData query.JSONDataWrapper
-		if name, isName := queryParams[query.Name]; isName {
-			resourceQuery := query.Resource{
-				Check: query.ContainerCheck,
-				Type:  query.ContainerType,
-				Name:  name[0],
-			}
-			jsonData = resourceQuery.RetrieveResourceMetrics()
-			query.PopulateClusterAllocationAndCapacity(&jsonData)
-		} else {
-			logrus.Errorf("wrong type of query for container, no name is given")
+		resourceQuery := query.Resource{
+			Check:     query.ContainerCheck,
+			Type:      query.ContainerType,
+			Name:      queryParams.Get(query.Name),
+			Start:     queryParams.Get(query.Start),
+			End:       queryParams.Get(query.End),
+			Match:     queryParams.Get(query.Match),
+			Namespace: queryParams.Get(query.Namespace),
 		}
+		jsonData := resourceQuery.RetrieveResourceMetrics(r.Context())
+		query.PopulateClusterAllocationAndCapacity(r.Context(), &jsonData)
 		encodeAndWrite(w, jsonData)
 	}
 }
@@ -547,22 +678,22 @@ func GetContainerMetrics(w http.ResponseWriter, r *http.Request) {
 // GetPVMetrics listens on /metrics/pv
 func GetPVMetrics(w http.ResponseWriter, r *http.Request) {
 	if isUserAuthenticated(w, r) {
+		queryParams, isValid := validateRequest(w, r, requireName, validateMatch, validateTimeRange)
+		if !isValid {
+			return
+		}
 		addHeaders(&w, r)
-		queryParams := r.URL.Query()
-		logrus.Debugf("Query params: (%v)", queryParams)
 
-		var jsonData query.JSONDataWrapper
-		if name, isName := queryParams[query.Name]; isName {
-			resourceQuery := query.Resource{
-				Check: query.PVCheck,
-				Type:  query.PVType,
-				Name:  name[0],
-			}
-			jsonData = resourceQuery.RetrieveResourceMetrics()
-			resourceQuery.PopulateNodeOrPVAllocationAndCapacity(&jsonData)
-		} else {
-			logrus.Errorf("wrong type of query for PV, no name is given")
+		resourceQuery := query.Resource{
+			Check: query.PVCheck,
+			Type:  query.PVType,
+			Name:  queryParams.Get(query.Name),
+			Start: queryParams.Get(query.Start),
+			End:   queryParams.Get(query.End),
+			Match: queryParams.Get(query.Match),
 		}
+		jsonData := resourceQuery.RetrieveResourceMetrics(r.Context())
+		resourceQuery.PopulateNodeOrPVAllocationAndCapacity(r.Context(), &jsonData)
 		encodeAndWrite(w, jsonData)
 	}
 }
@@ -570,71 +701,532 @@ func GetPVMetrics(w http.ResponseWriter, r *http.Request) {
 // GetPVCMetrics listens on /metrics/pvc
 func GetPVCMetrics(w http.ResponseWriter, r *http.Request) {
 	if isUserAuthenticated(w, r) {
+		queryParams, isValid := validateRequest(w, r, requireName, validateMatch, validateTimeRange)
+		if !isValid {
+			return
+		}
 		addHeaders(&w, r)
-		queryParams := r.URL.Query()
-		logrus.Debugf("Query params: (%v)", queryParams)
 
-		var jsonData query.JSONDataWrapper
-		if name, isName := queryParams[query.Name]; isName {
-			resourceQuery := query.Resource{
-				Check: query.PVCCheck,
-				Type:  query.PVCType,
-				Name:  name[0],
-			}
-			jsonData = resourceQuery.RetrieveResourceMetrics()
-			query.PopulateClusterAllocationAndCapacity(&jsonData)
-		} else {
-			logrus.Errorf("wrong type of query for PVC, no name is given")
+		resourceQuery := query.Resource{
+			Check: query.PVCCheck,
+			Type:  query.PVCType,
+			Name:  queryParams.Get(query.Name),
+			Start: queryParams.Get(query.Start),
+			End:   queryParams.Get(query.End),
+			Match: queryParams.Get(query.Match),
+		}
+		jsonData := resourceQuery.RetrieveResourceMetrics(r.Context())
+		query.PopulateClusterAllocationAndCapacity(r.Context(), &jsonData)
+		encodeAndWrite(w, jsonData)
+	}
+}
+
+// GetServiceUnitCosts listens on /metrics/service/unitcost and returns cost per 1k requests of the service
+// for every hour in the optional time range(start, end)
+func GetServiceUnitCosts(w http.ResponseWriter, r *http.Request) {
+	if isUserAuthenticated(w, r) {
+		queryParams, isValid := validateRequest(w, r, requireName, validateNamespace, validateTimeRange)
+		if !isValid {
+			return
+		}
+		addHeaders(&w, r)
+
+		jsonData := query.RetrieveServiceUnitCostsInNamespace(r.Context(), queryParams.Get(query.Name), queryParams.Get(query.Namespace),
+			queryParams.Get(query.Start), queryParams.Get(query.End))
+		encodeAndWrite(w, jsonData)
+	}
+}
+
+// GetTenantCosts listens on /metrics/tenants and returns monthly cost of every value of the tenant label
+// including their share of cost of shared namespaces, optional param label overrides the configured tenant label
+func GetTenantCosts(w http.ResponseWriter, r *http.Request) {
+	if isUserAuthenticated(w, r) {
+		queryParams, isValid := validateRequest(w, r, validateLabel)
+		if !isValid {
+			return
+		}
+		addHeaders(&w, r)
+
+		jsonData := query.RetrieveTenantCosts(r.Context(), queryParams.Get(query.Label))
+		encodeAndWrite(w, jsonData)
+	}
+}
+
+// GetApplicationCosts listens on /apps and returns cost of each Argo CD or Flux application
+func GetApplicationCosts(w http.ResponseWriter, r *http.Request) {
+	if isUserAuthenticated(w, r) {
+		addHeaders(&w, r)
+		jsonData := query.RetrieveApplicationCosts(r.Context())
+		encodeAndWrite(w, jsonData)
+	}
+}
+
+// GetHelmReleaseCosts listens on /helm/releases and returns cost of each helm release
+func GetHelmReleaseCosts(w http.ResponseWriter, r *http.Request) {
+	if isUserAuthenticated(w, r) {
+		addHeaders(&w, r)
+		jsonData := query.RetrieveHelmReleaseCosts(r.Context())
+		encodeAndWrite(w, jsonData)
+	}
+}
+
+// GetHelmChartCosts listens on /helm/charts and returns cost of each helm chart over all its releases
+func GetHelmChartCosts(w http.ResponseWriter, r *http.Request) {
+	if isUserAuthenticated(w, r) {
+		addHeaders(&w, r)
+		jsonData := query.RetrieveHelmChartCosts(r.Context())
+		encodeAndWrite(w, jsonData)
+	}
+}
+
+// GetRevisionCosts listens on /metrics/revisions and returns cost of each revision(commit, version or
+// deployment revision) of the workload with the given name
+func GetRevisionCosts(w http.ResponseWriter, r *http.Request) {
+	if isUserAuthenticated(w, r) {
+		queryParams, isValid := validateRequest(w, r, requireName)
+		if !isValid {
+			return
+		}
+		addHeaders(&w, r)
+
+		jsonData := query.RetrieveRevisionCosts(r.Context(), queryParams.Get(query.Name))
+		encodeAndWrite(w, jsonData)
+	}
+}
+
+// GetReplicaCosts listens on /metrics/replicas and returns month to date cost of each pod of the workload with
+// the given name, with replicas costing far more or running far longer than the median flagged as outliers
+func GetReplicaCosts(w http.ResponseWriter, r *http.Request) {
+	if isUserAuthenticated(w, r) {
+		queryParams, isValid := validateRequest(w, r, requireName)
+		if !isValid {
+			return
+		}
+		addHeaders(&w, r)
+
+		jsonData := query.RetrieveReplicaCostVariance(r.Context(), queryParams.Get(query.Name))
+		encodeAndWrite(w, jsonData)
+	}
+}
+
+// GetPodTimeline listens on /api/timeline/pod and returns the time and cost the pod with the given name spent
+// unscheduled, pending, running and terminated
+func GetPodTimeline(w http.ResponseWriter, r *http.Request) {
+	if isUserAuthenticated(w, r) {
+		queryParams, isValid := validateRequest(w, r, requireName)
+		if !isValid {
+			return
+		}
+		addHeaders(&w, r)
+
+		jsonData := query.RetrievePodTimeline(r.Context(), queryParams.Get(query.Name))
+		encodeAndWrite(w, jsonData)
+	}
+}
+
+// GetWorkloadTimeline listens on /api/timeline/workload and returns timelines of pods of the workload with
+// the given name along with the time and cost they spent in each phase
+func GetWorkloadTimeline(w http.ResponseWriter, r *http.Request) {
+	if isUserAuthenticated(w, r) {
+		queryParams, isValid := validateRequest(w, r, requireName)
+		if !isValid {
+			return
+		}
+		addHeaders(&w, r)
+
+		jsonData := query.RetrieveWorkloadTimeline(r.Context(), queryParams.Get(query.Name))
+		encodeAndWrite(w, jsonData)
+	}
+}
+
+// GetFailureCosts listens on /api/metrics/failures and returns the cost of failed and crash looping pods per
+// namespace and workload over the time range, month to date by default
+func GetFailureCosts(w http.ResponseWriter, r *http.Request) {
+	if isUserAuthenticated(w, r) {
+		queryParams, isValid := validateRequest(w, r, validateAsOf, validateTimeRange)
+		if !isValid {
+			return
+		}
+		addHeaders(&w, r)
+
+		jsonData := query.RetrieveFailureCosts(r.Context(), getTimeRange(queryParams))
+		encodeAndWrite(w, jsonData)
+	}
+}
+
+// GetCostEfficiency listens on /api/metrics/efficiency and returns allocated, utilized and idle cost of cpu and
+// memory of pods per namespace and workload over the time range, month to date by default
+func GetCostEfficiency(w http.ResponseWriter, r *http.Request) {
+	if isUserAuthenticated(w, r) {
+		queryParams, isValid := validateRequest(w, r, validateAsOf, validateTimeRange)
+		if !isValid {
+			return
+		}
+		addHeaders(&w, r)
+
+		jsonData := query.RetrieveCostEfficiency(r.Context(), getTimeRange(queryParams))
+		encodeAndWrite(w, jsonData)
+	}
+}
+
+// GetCostTimeSeries listens on /api/costs/timeseries and returns daily cost of pods, namespaces or nodes of the
+// type between optional params start and end, the last 30 days by default, from daily cost snapshots
+func GetCostTimeSeries(w http.ResponseWriter, r *http.Request) {
+	if isUserAuthenticated(w, r) {
+		queryParams, isValid := validateRequest(w, r, requireCostSnapshotType, validateName, validateNamespace, validateTimeRange)
+		if !isValid {
+			return
 		}
+		addHeaders(&w, r)
+
+		jsonData := query.RetrieveCostTimeSeries(r.Context(), queryParams.Get(query.Type), queryParams.Get(query.Name),
+			queryParams.Get(query.Namespace), getTimeRange(queryParams))
+		encodeAndWrite(w, jsonData)
+	}
+}
+
+// GetChargebackReport listens on /api/export/chargeback and returns cost of pods per workload between optional
+// params start and end grouped by namespace, label or owner as a downloadable csv(default) or json report
+func GetChargebackReport(w http.ResponseWriter, r *http.Request) {
+	if isUserAuthenticated(w, r) {
+		queryParams, isValid := validateRequest(w, r, validateTimeRange, validateChargebackGroupBy, validateLabel, validateFormat)
+		if !isValid {
+			return
+		}
+		format := queryParams.Get(query.Format)
+		if format == query.All {
+			format = query.CSV
+		}
+		report := query.RetrieveChargebackReport(r.Context(), getTimeRange(queryParams), queryParams.Get(query.GroupBy), queryParams.Get(query.Label)).Data
+
+		addAccessControlHeaders(&w, r)
+		w.Header().Set("Content-Type", chargeback.ContentType(format))
+		w.Header().Set("Content-Disposition", `attachment; filename="`+chargeback.FileName(report, format)+`"`)
+		w.WriteHeader(http.StatusOK)
+		if err := chargeback.Write(w, report, format); err != nil {
+			log.Errorf("unable to write chargeback report: %v", err)
+		}
+	}
+}
+
+// GetFOCUSExport listens on /api/export/focus and returns charges of resources requested by pods between optional
+// params start and end in the FinOps FOCUS schema as a downloadable csv(default) or json export
+func GetFOCUSExport(w http.ResponseWriter, r *http.Request) {
+	if isUserAuthenticated(w, r) {
+		queryParams, isValid := validateRequest(w, r, validateTimeRange, validateFormat)
+		if !isValid {
+			return
+		}
+		format := queryParams.Get(query.Format)
+		if format == query.All {
+			format = query.CSV
+		}
+		report := query.RetrieveFOCUSReport(r.Context(), getTimeRange(queryParams)).Data
+
+		addAccessControlHeaders(&w, r)
+		w.Header().Set("Content-Type", chargeback.ContentType(format))
+		w.Header().Set("Content-Disposition", `attachment; filename="`+chargeback.FOCUSFileName(report, format)+`"`)
+		w.WriteHeader(http.StatusOK)
+		if err := chargeback.WriteFOCUS(w, report, format); err != nil {
+			log.Errorf("unable to write FOCUS export: %v", err)
+		}
+	}
+}
+
+// GetBackstageEntityCost listens on /api/backstage/cost and returns daily cost of pods of the Backstage catalog entity
+// in the intervals in the shape of Cost of the cost insights plugin, optional param groupBy groups it by a pod label
+func GetBackstageEntityCost(w http.ResponseWriter, r *http.Request) {
+	if isUserAuthenticated(w, r) {
+		queryParams, isValid := validateRequest(w, r, requireEntity, requireIntervals, validateLabelGroupBy)
+		if !isValid {
+			return
+		}
+		intervals, _ := query.ParseCostIntervals(queryParams.Get(query.Intervals))
+		cost, err := query.RetrieveBackstageEntityCost(r.Context(), queryParams.Get(query.Entity), intervals, queryParams.Get(query.GroupBy))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		addHeaders(&w, r)
+		encodeAndWrite(w, cost)
+	}
+}
+
+// EstimateManifestCost listens on /api/estimate and returns the projected monthly cost on the cluster of workloads
+// of the YAML or JSON manifest in the request body
+func EstimateManifestCost(w http.ResponseWriter, r *http.Request) {
+	if isUserAuthenticated(w, r) {
+		workloads, err := readManifestWorkloads(r)
+		if err != nil {
+			writeAPIError(w, r, http.StatusBadRequest, &APIError{
+				Code:    ErrInvalidManifest,
+				Message: "unable to parse manifest: " + err.Error(),
+				Hint:    "use a YAML or JSON manifest of pods or workloads(ex: Deployment) as the request body",
+			})
+			return
+		}
+		addHeaders(&w, r)
+
+		jsonData := query.EstimateManifestCost(r.Context(), workloads)
+		encodeAndWrite(w, jsonData)
+	}
+}
+
+// CheckBudget listens on /api/budget/check and returns whether the namespace of the name stays within its monthly
+// budget if the workloads of the YAML or JSON manifest in the request body are deployed in it. The budget query
+// parameter overrides the configured budget of the namespace.
+func CheckBudget(w http.ResponseWriter, r *http.Request) {
+	if isUserAuthenticated(w, r) {
+		queryParams, isValid := validateRequest(w, r, requireName, validateBudget)
+		if !isValid {
+			return
+		}
+		workloads, err := readManifestWorkloads(r)
+		if err != nil {
+			writeAPIError(w, r, http.StatusBadRequest, &APIError{
+				Code:    ErrInvalidManifest,
+				Message: "unable to parse manifest: " + err.Error(),
+				Hint:    "use a YAML or JSON manifest of pods or workloads(ex: Deployment) as the request body",
+			})
+			return
+		}
+
+		namespace := queryParams.Get(query.Name)
+		budget, hasBudget := query.GetNamespaceBudget(namespace)
+		if value, isBudget := queryParams[query.Budget]; isBudget {
+			budget, _ = strconv.ParseFloat(value[0], 64)
+			hasBudget = true
+		}
+		jsonData, err := query.CheckNamespaceBudget(r.Context(), namespace, budget, hasBudget, workloads)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		addHeaders(&w, r)
 		encodeAndWrite(w, jsonData)
 	}
 }
 
-// GetPodDiscoveryNodes listens on /discovery/pod/nodes endpoint
+func readManifestWorkloads(r *http.Request) ([]models.ManifestWorkload, error) {
+	manifest, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+	return models.ParseManifest(manifest)
+}
+
+// GetAffinityCostImpact listens on /api/metrics/affinity and returns the extra node capacity and cost which required
+// pod anti-affinity of live pods forces compared to packing them without constraints
+func GetAffinityCostImpact(w http.ResponseWriter, r *http.Request) {
+	if isUserAuthenticated(w, r) {
+		addHeaders(&w, r)
+		jsonData := query.RetrieveAffinityCostImpact(r.Context())
+		encodeAndWrite(w, jsonData)
+	}
+}
+
+// GetDedicatedPools listens on /api/metrics/pools and returns utilization and idle cost of node pools dedicated
+// via taints with idle cost attributed to the teams owning them
+func GetDedicatedPools(w http.ResponseWriter, r *http.Request) {
+	if isUserAuthenticated(w, r) {
+		queryParams, isValid := validateRequest(w, r, validateLabel)
+		if !isValid {
+			return
+		}
+		addHeaders(&w, r)
+
+		jsonData := query.RetrieveDedicatedPools(r.Context(), queryParams.Get(query.Label))
+		encodeAndWrite(w, jsonData)
+	}
+}
+
+// GetImageCosts listens on /api/images and returns month to date cost of containers grouped by image repository,
+// or by registry with groupBy=registry
+func GetImageCosts(w http.ResponseWriter, r *http.Request) {
+	if isUserAuthenticated(w, r) {
+		queryParams, isValid := validateRequest(w, r, validateImageGroupBy)
+		if !isValid {
+			return
+		}
+		addHeaders(&w, r)
+
+		jsonData := query.RetrieveImageCosts(r.Context(), queryParams.Get(query.GroupBy))
+		encodeAndWrite(w, jsonData)
+	}
+}
+
+// GetZoneCosts listens on /api/zones and returns month to date cost of pods grouped by zone of their nodes, or by
+// region with groupBy=region. Costs are restricted to the namespace if name of a namespace is given.
+func GetZoneCosts(w http.ResponseWriter, r *http.Request) {
+	if isUserAuthenticated(w, r) {
+		queryParams, isValid := validateRequest(w, r, validateName, validateZoneGroupBy)
+		if !isValid {
+			return
+		}
+		addHeaders(&w, r)
+
+		jsonData := query.RetrieveZoneCosts(r.Context(), queryParams.Get(query.Name), queryParams.Get(query.GroupBy))
+		encodeAndWrite(w, jsonData)
+	}
+}
+
+// GetEgressCosts listens on /api/egress and returns estimated cost of intra-zone, cross-zone and external traffic of
+// pods per namespace. Costs are restricted to the namespace if name of a namespace is given.
+func GetEgressCosts(w http.ResponseWriter, r *http.Request) {
+	if isUserAuthenticated(w, r) {
+		queryParams, isValid := validateRequest(w, r, validateName)
+		if !isValid {
+			return
+		}
+		addHeaders(&w, r)
+
+		jsonData := query.RetrieveEgressCosts(r.Context(), queryParams.Get(query.Name))
+		encodeAndWrite(w, jsonData)
+	}
+}
+
+// GetInfraTagCosts listens on /api/infratags and returns month to date cost of pods grouped by value of the infra
+// tag key of their nodes. Costs are restricted to the namespace if name of a namespace is given.
+func GetInfraTagCosts(w http.ResponseWriter, r *http.Request) {
+	if isUserAuthenticated(w, r) {
+		queryParams, isValid := validateRequest(w, r, validateName, requireInfraTagKey)
+		if !isValid {
+			return
+		}
+		addHeaders(&w, r)
+
+		jsonData := query.RetrieveInfraTagCosts(r.Context(), queryParams.Get(query.Name), queryParams.Get(query.Key))
+		encodeAndWrite(w, jsonData)
+	}
+}
+
+// GetOverProvisionedVolumes listens on /api/volumes/overprovisioned and returns pvcs using a small part of their
+// provisioned size as reported by kubelet volume stats, with monthly savings if they are resized
+func GetOverProvisionedVolumes(w http.ResponseWriter, r *http.Request) {
+	if isUserAuthenticated(w, r) {
+		addHeaders(&w, r)
+		jsonData := query.RetrieveOverProvisionedVolumes(r.Context())
+		encodeAndWrite(w, jsonData)
+	}
+}
+
+// GetClusterDiff listens on /diff and returns workloads created, deleted or resized between start and end,
+// and the resulting change in cost of each namespace
+func GetClusterDiff(w http.ResponseWriter, r *http.Request) {
+	if isUserAuthenticated(w, r) {
+		queryParams, isValid := validateRequest(w, r, validateDiffTimeRange)
+		if !isValid {
+			return
+		}
+		addHeaders(&w, r)
+
+		jsonData := query.RetrieveClusterDiff(r.Context(), queryParams.Get(query.Start), queryParams.Get(query.End))
+		encodeAndWrite(w, jsonData)
+	}
+}
+
+// GetNodeChurn listens on /api/churn/nodes and returns scale up/down frequency, average lifetime and idle capacity
+// cost of nodes between optional params start and end, the last 30 days by default
+func GetNodeChurn(w http.ResponseWriter, r *http.Request) {
+	if isUserAuthenticated(w, r) {
+		queryParams, isValid := validateRequest(w, r, validateTimeRange)
+		if !isValid {
+			return
+		}
+		addHeaders(&w, r)
+
+		jsonData := query.RetrieveNodeChurn(r.Context(), queryParams.Get(query.Start), queryParams.Get(query.End))
+		encodeAndWrite(w, jsonData)
+	}
+}
+
+// GetScaleUpCosts listens on /api/churn/scaleups and returns cost of nodes added between optional params start
+// and end, the last 30 days by default, attributed to the workloads whose pending pods triggered them
+func GetScaleUpCosts(w http.ResponseWriter, r *http.Request) {
+	if isUserAuthenticated(w, r) {
+		queryParams, isValid := validateRequest(w, r, validateTimeRange)
+		if !isValid {
+			return
+		}
+		addHeaders(&w, r)
+
+		jsonData := query.RetrieveScaleUpCosts(r.Context(), queryParams.Get(query.Start), queryParams.Get(query.End))
+		encodeAndWrite(w, jsonData)
+	}
+}
+
+// GetPendingPods listens on /api/pods/pending and returns pods unschedulable for at least optional param minDuration
+// between optional params start and end, the last 30 days by default, with the cost of their delay and the node
+// capacity needed by pods still unscheduled
+func GetPendingPods(w http.ResponseWriter, r *http.Request) {
+	if isUserAuthenticated(w, r) {
+		queryParams, isValid := validateRequest(w, r, validateTimeRange, validateMinDuration)
+		if !isValid {
+			return
+		}
+		addHeaders(&w, r)
+
+		jsonData := query.RetrievePendingPods(r.Context(), queryParams.Get(query.Start), queryParams.Get(query.End), queryParams.Get(query.MinDuration))
+		encodeAndWrite(w, jsonData)
+	}
+}
+
+// GetAlerts listens on /alerts and returns alerts of alert rules, optional param state(firing or resolved) filters them
+func GetAlerts(w http.ResponseWriter, r *http.Request) {
+	if isUserAuthenticated(w, r) {
+		queryParams, isValid := validateRequest(w, r, validateAlertState)
+		if !isValid {
+			return
+		}
+		addHeaders(&w, r)
+
+		jsonData := query.RetrieveAlerts(r.Context(), queryParams.Get(query.State))
+		encodeAndWrite(w, jsonData)
+	}
+}
+
+// GetPodDiscoveryNodes listens on /discovery/pod/nodes endpoint, the graph has pods of the namespace if it is given
 func GetPodDiscoveryNodes(w http.ResponseWriter, r *http.Request) {
 	if isUserAuthenticated(w, r) {
+		queryParams, isValid := validateRequest(w, r, validateNamespace)
+		if !isValid {
+			return
+		}
 		var pods []models.Pod
 		var err error
 
 		addHeaders(&w, r)
-		pods, err = query.RetrievePodsInteractionsForAllLivePodsWithCount()
+		pods, err = query.RetrievePodsInteractionsForLivePodsWithCountInNamespace(r.Context(), queryParams.Get(query.Namespace), query.Page{})
 		generator.GeneratePodNodesAndEdges(pods)
 		if err != nil {
-			logrus.Errorf("Unable to get response: (%v)", err)
+			log.Errorf("Unable to get response: (%v)", err)
 		}
 		nodes := generator.GetGraphNodes()
 		if nodes != nil {
-			logrus.Infof("No nodes found")
+			log.Infof("No nodes found")
 			return
 		}
-		err = json.NewEncoder(w).Encode(nodes)
-		if err != nil {
-			logrus.Errorf("Unable to encode to json: (%v)", err)
-		}
+		encodeAndWrite(w, nodes)
 	}
 }
 
 // GetPodDiscoveryEdges listens on /discovery/pod/edges endpoint
 func GetPodDiscoveryEdges(w http.ResponseWriter, r *http.Request) {
 	if isUserAuthenticated(w, r) {
-		var err error
 		addHeaders(&w, r)
 
 		edges := generator.GetGraphEdges()
 		if edges == nil {
-			logrus.Infof("No edges found")
+			log.Infof("No edges found")
 			return
 		}
-		err = json.NewEncoder(w).Encode(edges)
-		if err != nil {
-			logrus.Errorf("Unable to encode to json: (%v)", err)
-		}
+		encodeAndWrite(w, edges)
 	}
 }
 
-// SyncCluster listens on /api/sync
+// SyncCluster listens on /api/sync, only admins can sync the cluster
 func SyncCluster(w http.ResponseWriter, r *http.Request) {
-	if isUserAuthenticated(w, r) {
+	if isUserAdmin(w, r) {
 		w.WriteHeader(http.StatusAccepted)
 		go syncResourcesInCluster()
 	}
@@ -643,5 +1235,5 @@ func SyncCluster(w http.ResponseWriter, r *http.Request) {
 func syncResourcesInCluster() {
 	eventprocessor.SyncCluster(getKubeClient())
 	eventprocessor.UpdateGroups(getGroupClient())
-	query.ComputeClusterAllocationAndCapacity()
+	query.ComputeClusterAllocationAndCapacity(dgraph.WithOwnCluster(context.Background()))
 }
@@ -0,0 +1,184 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package apiHandlers
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/vmware/purser/pkg/controller/auth"
+	"github.com/vmware/purser/pkg/controller/dgraph/models/query"
+)
+
+// podPathVar is the path var of pod names in namespace scoped endpoints
+const podPathVar = "pod"
+
+// GetClusterNodes listens on /api/cluster/nodes and returns nodes with their capacity and its cost over the time
+// range, it needs access to the cluster scope
+func GetClusterNodes(w http.ResponseWriter, r *http.Request) {
+	if canUserView(w, r, auth.ClusterScope) {
+		queryParams, isValid := validateRequest(w, r, validateTimeRange)
+		if !isValid {
+			return
+		}
+		addHeaders(&w, r)
+
+		jsonData := query.RetrieveClusterNodes(r.Context(), getTimeRange(queryParams))
+		encodeAndWrite(w, jsonData)
+	}
+}
+
+// GetClusterIdleCost listens on /api/cluster/idle and returns cost of the capacity of nodes and volumes which is not
+// allocated to pods over the time range, month to date by default. It needs access to the cluster scope.
+func GetClusterIdleCost(w http.ResponseWriter, r *http.Request) {
+	if canUserView(w, r, auth.ClusterScope) {
+		queryParams, isValid := validateRequest(w, r, validateTimeRange)
+		if !isValid {
+			return
+		}
+		addHeaders(&w, r)
+
+		jsonData := query.RetrieveClusterIdleCost(r.Context(), getTimeRange(queryParams))
+		encodeAndWrite(w, jsonData)
+	}
+}
+
+// GetClusterTotals listens on /api/cluster/totals and returns allocated, capacity and idle cost of the cluster over
+// the time range, month to date by default. It needs access to the cluster scope.
+func GetClusterTotals(w http.ResponseWriter, r *http.Request) {
+	if canUserView(w, r, auth.ClusterScope) {
+		queryParams, isValid := validateRequest(w, r, validateTimeRange)
+		if !isValid {
+			return
+		}
+		addHeaders(&w, r)
+
+		jsonData := query.RetrieveClusterTotals(r.Context(), getTimeRange(queryParams))
+		encodeAndWrite(w, jsonData)
+	}
+}
+
+// GetScopedNamespaceMetrics listens on /api/namespaces/{namespace}/metrics and returns metrics of the namespace
+// and its workloads. Unlike /api/metrics/namespace it does not add allocation and capacity of the cluster.
+func GetScopedNamespaceMetrics(w http.ResponseWriter, r *http.Request) {
+	if namespace, canView := canUserViewPathNamespace(w, r); canView {
+		queryParams, isValid := validateRequest(w, r, validateOS, validateAsOf, validateGroupBy, validateTimeRange, validateCostMode)
+		if !isValid {
+			return
+		}
+		addHeaders(&w, r)
+
+		resourceQuery := query.Resource{
+			Check:    query.NamespaceCheck,
+			Type:     query.NamespaceType,
+			Name:     namespace,
+			Start:    queryParams.Get(query.Start),
+			End:      queryParams.Get(query.End),
+			OS:       queryParams.Get(query.OS),
+			AsOf:     queryParams.Get(query.AsOf),
+			GroupBy:  queryParams.Get(query.GroupBy),
+			CostMode: queryParams.Get(query.CostMode),
+		}
+		jsonData := resourceQuery.RetrieveResourceMetrics(r.Context())
+		encodeAndWrite(w, jsonData)
+	}
+}
+
+// GetScopedNamespaceHierarchy listens on /api/namespaces/{namespace}/hierarchy and returns all children of the
+// namespace
+func GetScopedNamespaceHierarchy(w http.ResponseWriter, r *http.Request) {
+	if namespace, canView := canUserViewPathNamespace(w, r); canView {
+		queryParams, isValid := validateRequest(w, r, validateAsOf, validatePagination)
+		if !isValid {
+			return
+		}
+		addHeaders(&w, r)
+
+		resourceQuery := query.Resource{
+			Check:       query.NamespaceCheck,
+			Type:        query.NamespaceType,
+			Name:        namespace,
+			ChildFilter: query.NamespaceChildFilter,
+			Page:        getPage(queryParams),
+			AsOf:        queryParams.Get(query.AsOf),
+		}
+		jsonData := resourceQuery.RetrieveResourceHierarchy(r.Context())
+		encodeAndWrite(w, jsonData)
+	}
+}
+
+// GetScopedPodInteractions listens on /api/namespaces/{namespace}/interactions and returns interactions of pods of
+// the namespace with other pods of the namespace
+func GetScopedPodInteractions(w http.ResponseWriter, r *http.Request) {
+	if namespace, canView := canUserViewPathNamespace(w, r); canView {
+		queryParams, isValid := validateRequest(w, r, validateName, validateOrphan, validatePagination)
+		if !isValid {
+			return
+		}
+		addHeaders(&w, r)
+
+		var jsonResp []byte
+		if name, isName := queryParams[query.Name]; isName {
+			jsonResp = query.RetrievePodsInteractionsRaw(r.Context(), name[0], namespace, false, query.Page{})
+		} else {
+			isOrphan := queryParams.Get(query.Orphan) != query.False
+			jsonResp = query.RetrievePodsInteractionsRaw(r.Context(), query.All, namespace, isOrphan, getPage(queryParams))
+		}
+		writeBytes(w, jsonResp)
+	}
+}
+
+// GetScopedPodMetrics listens on /api/namespaces/{namespace}/pods/{pod}/metrics and returns metrics of the pod
+// and its containers
+func GetScopedPodMetrics(w http.ResponseWriter, r *http.Request) {
+	if namespace, canView := canUserViewPathNamespace(w, r); canView {
+		pod := mux.Vars(r)[podPathVar]
+		if apiErr := validatePathName(podPathVar, pod); apiErr != nil {
+			writeAPIError(w, r, http.StatusBadRequest, apiErr)
+			return
+		}
+		queryParams, isValid := validateRequest(w, r, validateTimeRange, validateCostMode)
+		if !isValid {
+			return
+		}
+		addHeaders(&w, r)
+
+		resourceQuery := query.Resource{
+			Check:     query.PodCheck,
+			Type:      query.PodType,
+			Name:      query.PodType + "-" + pod,
+			Start:     queryParams.Get(query.Start),
+			End:       queryParams.Get(query.End),
+			Namespace: namespace,
+			CostMode:  queryParams.Get(query.CostMode),
+		}
+		jsonData := resourceQuery.RetrieveResourceMetrics(r.Context())
+		encodeAndWrite(w, jsonData)
+	}
+}
+
+// canUserViewPathNamespace returns the stored name(ex: namespace-default) of the namespace of the request path and
+// whether the user can view it, it responds with 400 if the namespace is not a valid name
+func canUserViewPathNamespace(w http.ResponseWriter, r *http.Request) (string, bool) {
+	namespace := mux.Vars(r)[query.Namespace]
+	if apiErr := validatePathName(query.Namespace, namespace); apiErr != nil {
+		writeAPIError(w, r, http.StatusBadRequest, apiErr)
+		return "", false
+	}
+	return query.NamespaceType + "-" + namespace, canUserView(w, r, namespace)
+}
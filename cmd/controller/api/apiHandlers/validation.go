@@ -0,0 +1,790 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package apiHandlers
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/vmware/purser/pkg/controller/dgraph"
+	"github.com/vmware/purser/pkg/controller/dgraph/models/query"
+	"github.com/vmware/purser/pkg/controller/notification"
+	"github.com/vmware/purser/pkg/logging"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// Machine readable error codes returned on failed request validation
+const (
+	ErrMissingParameter   = "MISSING_PARAMETER"
+	ErrDuplicateParameter = "DUPLICATE_PARAMETER"
+	ErrInvalidName        = "INVALID_NAME"
+	ErrInvalidView        = "INVALID_VIEW"
+	ErrInvalidBoolean     = "INVALID_BOOLEAN"
+	ErrInvalidTime        = "INVALID_TIME"
+	ErrInvalidTimeRange   = "INVALID_TIME_RANGE"
+	ErrInvalidPagination  = "INVALID_PAGINATION"
+	ErrInvalidOS          = "INVALID_OS"
+	ErrInvalidLabel       = "INVALID_LABEL"
+	ErrInvalidState       = "INVALID_STATE"
+	ErrInvalidPredicate   = "INVALID_PREDICATE"
+	ErrInvalidMatch       = "INVALID_MATCH"
+	ErrInvalidGroupBy     = "INVALID_GROUP_BY"
+	ErrInvalidDeleted     = "INVALID_DELETED"
+	ErrInvalidDuration    = "INVALID_DURATION"
+	ErrInvalidCostMode    = "INVALID_COST_MODE"
+	ErrInvalidManifest    = "INVALID_MANIFEST"
+	ErrInvalidBudget      = "INVALID_BUDGET"
+	ErrInvalidFormat      = "INVALID_FORMAT"
+	ErrInvalidEntity      = "INVALID_ENTITY"
+	ErrInvalidIntervals   = "INVALID_INTERVALS"
+	ErrInvalidKey         = "INVALID_KEY"
+	ErrInvalidType        = "INVALID_TYPE"
+	ErrInvalidLevel       = "INVALID_LEVEL"
+)
+
+const (
+	maxNameLength   = 512
+	maxPageSize     = 1000
+	maxTagKeyLength = 128
+)
+
+var uidRegex = regexp.MustCompile(`^0x[0-9a-fA-F]+$`)
+
+// APIError structure
+type APIError struct {
+	Code      string `json:"code"`
+	Parameter string `json:"parameter,omitempty"`
+	Message   string `json:"message"`
+	Hint      string `json:"hint,omitempty"`
+	// RequestID is the ID of the failed request, to be given when it is reported
+	RequestID string `json:"requestID,omitempty"`
+}
+
+// APIErrorWrapper structure
+type APIErrorWrapper struct {
+	Error APIError `json:"error"`
+}
+
+func (e *APIError) Error() string {
+	return e.Code + ": " + e.Message
+}
+
+// requestValidator validates one or more query parameters of a request
+type requestValidator func(queryParams url.Values) *APIError
+
+// validateRequest runs all validators on query params of the request and writes a bad request response
+// for the first failed validation. It returns query params of the request and whether they are valid.
+func validateRequest(w http.ResponseWriter, r *http.Request, validators ...requestValidator) (url.Values, bool) {
+	queryParams := r.URL.Query()
+	log.Debugf("Query params: (%v)", queryParams)
+	for _, validator := range validators {
+		if apiErr := validator(queryParams); apiErr != nil {
+			writeAPIError(w, r, http.StatusBadRequest, apiErr)
+			return queryParams, false
+		}
+	}
+	return queryParams, true
+}
+
+func writeAPIError(w http.ResponseWriter, r *http.Request, status int, apiErr *APIError) {
+	logging.WithContext(log, r.Context()).Errorf("invalid request: %s, err: %v", r.URL.String(), apiErr)
+	apiErr.RequestID = logging.RequestID(r.Context())
+	addAccessControlHeaders(&w, r)
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	w.WriteHeader(status)
+	err := json.NewEncoder(w).Encode(APIErrorWrapper{Error: *apiErr})
+	if err != nil {
+		log.Errorf("Unable to encode to json: (%v)", err)
+	}
+}
+
+// getSingleValue returns value of the param, whether it is present and an error if it is given more than once
+func getSingleValue(queryParams url.Values, param string) (string, bool, *APIError) {
+	values, isPresent := queryParams[param]
+	if !isPresent {
+		return "", false, nil
+	}
+	if len(values) > 1 {
+		return "", true, &APIError{
+			Code:      ErrDuplicateParameter,
+			Parameter: param,
+			Message:   "parameter " + param + " is given more than once",
+			Hint:      "pass only one value for " + param,
+		}
+	}
+	return values[0], true, nil
+}
+
+// requireName checks that a valid name is present in query params
+func requireName(queryParams url.Values) *APIError {
+	if _, isName := queryParams[query.Name]; !isName {
+		return &APIError{
+			Code:      ErrMissingParameter,
+			Parameter: query.Name,
+			Message:   "no name is given",
+			Hint:      "add query parameter name=<resource-name>",
+		}
+	}
+	return validateName(queryParams)
+}
+
+// validateName checks the name in query params if it is present
+func validateName(queryParams url.Values) *APIError {
+	name, isName, apiErr := getSingleValue(queryParams, query.Name)
+	if apiErr != nil || !isName {
+		return apiErr
+	}
+	if name == query.All || len(name) > maxNameLength || !query.IsValidName(name) {
+		return &APIError{
+			Code:      ErrInvalidName,
+			Parameter: query.Name,
+			Message:   "name '" + name + "' is not a valid resource name",
+			Hint:      "name must be non empty, at most " + strconv.Itoa(maxNameLength) + " characters and contain only alphanumerics or '-._:*+'",
+		}
+	}
+	return nil
+}
+
+// validateNamespace checks the namespace in query params if it is present
+func validateNamespace(queryParams url.Values) *APIError {
+	namespace, isNamespace, apiErr := getSingleValue(queryParams, query.Namespace)
+	if apiErr != nil || !isNamespace {
+		return apiErr
+	}
+	if len(namespace) > maxNameLength || !query.IsValidName(namespace) {
+		return &APIError{
+			Code:      ErrInvalidName,
+			Parameter: query.Namespace,
+			Message:   "namespace '" + namespace + "' is not a valid namespace name",
+			Hint:      "use the name of the namespace prefixed with namespace-, ex: namespace=namespace-default",
+		}
+	}
+	return nil
+}
+
+// validateMatch checks that match is a known name match mode and that the name is long enough
+// to be looked up with the trigram index for ignoreCase and partial matches
+func validateMatch(queryParams url.Values) *APIError {
+	match, isMatch, apiErr := getSingleValue(queryParams, query.Match)
+	if apiErr != nil || !isMatch {
+		return apiErr
+	}
+	switch match {
+	case query.ExactMatch, query.FuzzyMatch:
+		return nil
+	case query.IgnoreCaseMatch, query.PartialMatch:
+		if len(queryParams.Get(query.Name)) < query.MinRegexpMatchLength {
+			return &APIError{
+				Code:      ErrInvalidName,
+				Parameter: query.Name,
+				Message:   "name is too short for " + match + " match",
+				Hint:      "name must be at least " + strconv.Itoa(query.MinRegexpMatchLength) + " characters, or use match=" + query.FuzzyMatch,
+			}
+		}
+		return nil
+	}
+	return &APIError{
+		Code:      ErrInvalidMatch,
+		Parameter: query.Match,
+		Message:   "match '" + match + "' is not supported",
+		Hint:      "use match=" + query.ExactMatch + ", match=" + query.IgnoreCaseMatch + ", match=" + query.PartialMatch + " or match=" + query.FuzzyMatch,
+	}
+}
+
+// validateView checks that view is either physical or logical
+func validateView(queryParams url.Values) *APIError {
+	view, isView, apiErr := getSingleValue(queryParams, query.View)
+	if apiErr != nil || !isView {
+		return apiErr
+	}
+	if view != query.Physical && view != query.Logical {
+		return &APIError{
+			Code:      ErrInvalidView,
+			Parameter: query.View,
+			Message:   "view '" + view + "' is not supported",
+			Hint:      "use view=" + query.Physical + " or view=" + query.Logical,
+		}
+	}
+	return nil
+}
+
+// validateOS checks that os is either linux or windows
+func validateOS(queryParams url.Values) *APIError {
+	os, isOS, apiErr := getSingleValue(queryParams, query.OS)
+	if apiErr != nil || !isOS {
+		return apiErr
+	}
+	if os != query.Linux && os != query.Windows {
+		return &APIError{
+			Code:      ErrInvalidOS,
+			Parameter: query.OS,
+			Message:   "os '" + os + "' is not supported",
+			Hint:      "use os=" + query.Linux + " or os=" + query.Windows,
+		}
+	}
+	return nil
+}
+
+// validateGroupBy checks that children are grouped by kind if groupBy is present
+func validateGroupBy(queryParams url.Values) *APIError {
+	groupBy, isGroupBy, apiErr := getSingleValue(queryParams, query.GroupBy)
+	if apiErr != nil || !isGroupBy {
+		return apiErr
+	}
+	if groupBy != query.Kind {
+		return &APIError{
+			Code:      ErrInvalidGroupBy,
+			Parameter: query.GroupBy,
+			Message:   "groupBy '" + groupBy + "' is not supported",
+			Hint:      "use groupBy=" + query.Kind,
+		}
+	}
+	return nil
+}
+
+// validateChargebackGroupBy checks that groupBy is a dimension of chargeback reports if it is present
+func validateChargebackGroupBy(queryParams url.Values) *APIError {
+	groupBy, isGroupBy, apiErr := getSingleValue(queryParams, query.GroupBy)
+	if apiErr != nil || !isGroupBy {
+		return apiErr
+	}
+	switch groupBy {
+	case query.Namespace, query.Label, query.Owner, query.Cluster:
+		return nil
+	}
+	return &APIError{
+		Code:      ErrInvalidGroupBy,
+		Parameter: query.GroupBy,
+		Message:   "groupBy '" + groupBy + "' is not supported",
+		Hint:      "use groupBy=" + query.Namespace + ", groupBy=" + query.Label + ", groupBy=" + query.Owner + " or groupBy=" + query.Cluster,
+	}
+}
+
+// validateFormat checks that format is csv or json if it is present
+func validateFormat(queryParams url.Values) *APIError {
+	format, isFormat, apiErr := getSingleValue(queryParams, query.Format)
+	if apiErr != nil || !isFormat {
+		return apiErr
+	}
+	if format != query.CSV && format != query.JSON {
+		return &APIError{
+			Code:      ErrInvalidFormat,
+			Parameter: query.Format,
+			Message:   "format '" + format + "' is not supported",
+			Hint:      "use format=" + query.CSV + " or format=" + query.JSON,
+		}
+	}
+	return nil
+}
+
+// validateDeleted checks that deleted namespaces are included, excluded or only retrieved if deleted is present
+func validateDeleted(queryParams url.Values) *APIError {
+	deleted, isDeleted, apiErr := getSingleValue(queryParams, query.Deleted)
+	if apiErr != nil || !isDeleted {
+		return apiErr
+	}
+	if deleted != query.Include && deleted != query.Exclude && deleted != query.Only {
+		return &APIError{
+			Code:      ErrInvalidDeleted,
+			Parameter: query.Deleted,
+			Message:   "deleted '" + deleted + "' is not supported",
+			Hint:      "use deleted=" + query.Include + ", deleted=" + query.Exclude + " or deleted=" + query.Only,
+		}
+	}
+	return nil
+}
+
+// validateCostMode checks that costs are of requests or usage if costMode is present
+func validateCostMode(queryParams url.Values) *APIError {
+	costMode, isCostMode, apiErr := getSingleValue(queryParams, query.CostMode)
+	if apiErr != nil || !isCostMode {
+		return apiErr
+	}
+	if costMode != query.Request && costMode != query.Usage {
+		return &APIError{
+			Code:      ErrInvalidCostMode,
+			Parameter: query.CostMode,
+			Message:   "costMode '" + costMode + "' is not supported",
+			Hint:      "use costMode=" + query.Request + " or costMode=" + query.Usage,
+		}
+	}
+	return nil
+}
+
+// validateImageGroupBy checks that images are grouped by repository or registry if groupBy is present
+func validateImageGroupBy(queryParams url.Values) *APIError {
+	groupBy, isGroupBy, apiErr := getSingleValue(queryParams, query.GroupBy)
+	if apiErr != nil || !isGroupBy {
+		return apiErr
+	}
+	if groupBy != query.Repository && groupBy != query.Registry {
+		return &APIError{
+			Code:      ErrInvalidGroupBy,
+			Parameter: query.GroupBy,
+			Message:   "groupBy '" + groupBy + "' is not supported",
+			Hint:      "use groupBy=" + query.Repository + " or groupBy=" + query.Registry,
+		}
+	}
+	return nil
+}
+
+// validateZoneGroupBy checks that costs are grouped by zone or region if groupBy is present
+func validateZoneGroupBy(queryParams url.Values) *APIError {
+	groupBy, isGroupBy, apiErr := getSingleValue(queryParams, query.GroupBy)
+	if apiErr != nil || !isGroupBy {
+		return apiErr
+	}
+	if groupBy != query.Zone && groupBy != query.Region {
+		return &APIError{
+			Code:      ErrInvalidGroupBy,
+			Parameter: query.GroupBy,
+			Message:   "groupBy '" + groupBy + "' is not supported",
+			Hint:      "use groupBy=" + query.Zone + " or groupBy=" + query.Region,
+		}
+	}
+	return nil
+}
+
+// validateOrphan checks that orphan is a boolean
+func validateOrphan(queryParams url.Values) *APIError {
+	orphan, isOrphan, apiErr := getSingleValue(queryParams, query.Orphan)
+	if apiErr != nil || !isOrphan {
+		return apiErr
+	}
+	if orphan != query.True && orphan != query.False {
+		return &APIError{
+			Code:      ErrInvalidBoolean,
+			Parameter: query.Orphan,
+			Message:   "orphan '" + orphan + "' is not a boolean",
+			Hint:      "use orphan=" + query.True + " or orphan=" + query.False,
+		}
+	}
+	return nil
+}
+
+// validateTimeRange checks that start and end are RFC3339 timestamps and start is before end
+func validateTimeRange(queryParams url.Values) *APIError {
+	start, isStart, apiErr := parseTime(queryParams, query.Start)
+	if apiErr != nil {
+		return apiErr
+	}
+	end, isEnd, apiErr := parseTime(queryParams, query.End)
+	if apiErr != nil {
+		return apiErr
+	}
+	if isStart && isEnd && !start.Before(end) {
+		return &APIError{
+			Code:      ErrInvalidTimeRange,
+			Parameter: query.Start,
+			Message:   "start " + start.Format(time.RFC3339) + " is not before end " + end.Format(time.RFC3339),
+			Hint:      "swap start and end or widen the time range",
+		}
+	}
+	return nil
+}
+
+// getTimeRange returns the time range given by start and end, which ends at asOf if no end is given
+func getTimeRange(queryParams url.Values) query.TimeRange {
+	end := queryParams.Get(query.End)
+	if end == "" {
+		end = queryParams.Get(query.AsOf)
+	}
+	return query.TimeRange{Start: queryParams.Get(query.Start), End: end}
+}
+
+// getPage returns the page given by first, offset and after which are validated by validatePagination
+func getPage(queryParams url.Values) query.Page {
+	first, _ := strconv.Atoi(queryParams.Get(query.First))
+	offset, _ := strconv.Atoi(queryParams.Get(query.Offset))
+	return query.Page{First: first, Offset: offset, After: queryParams.Get(query.After)}
+}
+
+// validateDiffTimeRange checks that both start and end are given, start is before end and end is not in the future
+func validateDiffTimeRange(queryParams url.Values) *APIError {
+	for _, param := range []string{query.Start, query.End} {
+		if _, isPresent := queryParams[param]; !isPresent {
+			return &APIError{
+				Code:      ErrMissingParameter,
+				Parameter: param,
+				Message:   "no " + param + " is given",
+				Hint:      "add query parameter " + param + "=<RFC3339 time>",
+			}
+		}
+	}
+	if apiErr := validateTimeRange(queryParams); apiErr != nil {
+		return apiErr
+	}
+	end, _, _ := parseTime(queryParams, query.End)
+	if end.After(time.Now()) {
+		return &APIError{
+			Code:      ErrInvalidTime,
+			Parameter: query.End,
+			Message:   "end " + end.Format(time.RFC3339) + " is in the future",
+			Hint:      "use a time in the past, ex: end=" + time.Now().Format(time.RFC3339),
+		}
+	}
+	return nil
+}
+
+// validateAsOf checks that asOf is an RFC3339 timestamp which is not in the future
+func validateAsOf(queryParams url.Values) *APIError {
+	asOf, isAsOf, apiErr := parseTime(queryParams, query.AsOf)
+	if apiErr != nil || !isAsOf {
+		return apiErr
+	}
+	if asOf.After(time.Now()) {
+		return &APIError{
+			Code:      ErrInvalidTime,
+			Parameter: query.AsOf,
+			Message:   "asOf " + asOf.Format(time.RFC3339) + " is in the future",
+			Hint:      "omit asOf to get the current state",
+		}
+	}
+	return nil
+}
+
+func parseTime(queryParams url.Values, param string) (time.Time, bool, *APIError) {
+	value, isPresent, apiErr := getSingleValue(queryParams, param)
+	if apiErr != nil || !isPresent {
+		return time.Time{}, isPresent, apiErr
+	}
+	parsedTime, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, true, &APIError{
+			Code:      ErrInvalidTime,
+			Parameter: param,
+			Message:   param + " '" + value + "' is not a valid time",
+			Hint:      "use RFC3339 format, ex: " + param + "=2018-10-01T00:00:00Z",
+		}
+	}
+	return parsedTime, true, nil
+}
+
+// validatePagination checks that first is in (0, maxPageSize], offset is non negative and after is a dgraph uid
+func validatePagination(queryParams url.Values) *APIError {
+	if apiErr := validateIntegerInRange(queryParams, query.First, 1, maxPageSize); apiErr != nil {
+		return apiErr
+	}
+	if apiErr := validateIntegerInRange(queryParams, query.Offset, 0, -1); apiErr != nil {
+		return apiErr
+	}
+	after, isAfter, apiErr := getSingleValue(queryParams, query.After)
+	if apiErr != nil || !isAfter {
+		return apiErr
+	}
+	if !uidRegex.MatchString(after) {
+		return &APIError{
+			Code:      ErrInvalidPagination,
+			Parameter: query.After,
+			Message:   "after '" + after + "' is not a valid uid",
+			Hint:      "use the uid of the last item of previous page, ex: after=0x1a",
+		}
+	}
+	return nil
+}
+
+// validateIntegerInRange checks the param lies in [lower, upper], upper < 0 means no upper bound
+func validateIntegerInRange(queryParams url.Values, param string, lower, upper int) *APIError {
+	value, isPresent, apiErr := getSingleValue(queryParams, param)
+	if apiErr != nil || !isPresent {
+		return apiErr
+	}
+	number, err := strconv.Atoi(value)
+	if err != nil || number < lower || (upper >= 0 && number > upper) {
+		hint := param + " must be an integer greater than or equal to " + strconv.Itoa(lower)
+		if upper >= 0 {
+			hint += " and less than or equal to " + strconv.Itoa(upper)
+		}
+		return &APIError{
+			Code:      ErrInvalidPagination,
+			Parameter: param,
+			Message:   param + " '" + value + "' is out of range",
+			Hint:      hint,
+		}
+	}
+	return nil
+}
+
+// validateLabel checks that label is a valid k8s label key if it is present
+func validateLabel(queryParams url.Values) *APIError {
+	label, isLabel, apiErr := getSingleValue(queryParams, query.Label)
+	if apiErr != nil || !isLabel {
+		return apiErr
+	}
+	if errs := validation.IsQualifiedName(label); len(errs) > 0 {
+		return &APIError{
+			Code:      ErrInvalidLabel,
+			Parameter: query.Label,
+			Message:   "label '" + label + "' is not a valid label key: " + strings.Join(errs, "; "),
+			Hint:      "use a k8s label key, ex: label=example.com/tenant",
+		}
+	}
+	return nil
+}
+
+// validatePathName checks that the name of a path var(ex: namespace of /api/namespaces/{namespace}/metrics) is a
+// valid k8s object name, namespaces must be DNS labels
+func validatePathName(param, name string) *APIError {
+	errs := validation.IsDNS1123Subdomain(name)
+	if param == query.Namespace {
+		errs = validation.IsDNS1123Label(name)
+	}
+	if len(errs) > 0 {
+		return &APIError{
+			Code:      ErrInvalidName,
+			Parameter: param,
+			Message:   param + " '" + name + "' is not a valid name: " + strings.Join(errs, "; "),
+			Hint:      "use the k8s name of the " + param + ", ex: default",
+		}
+	}
+	return nil
+}
+
+// requireEntity checks that entity is given and that the id of the entity ref is a valid label value
+func requireEntity(queryParams url.Values) *APIError {
+	entity, isEntity, apiErr := getSingleValue(queryParams, query.Entity)
+	if apiErr != nil {
+		return apiErr
+	}
+	if !isEntity {
+		return &APIError{
+			Code:      ErrMissingParameter,
+			Parameter: query.Entity,
+			Message:   "no entity is given",
+			Hint:      "add query parameter entity=<entity ref>, ex: entity=component:default/web",
+		}
+	}
+	id := query.GetBackstageEntityID(entity)
+	if errs := validation.IsValidLabelValue(id); id == "" || len(errs) > 0 {
+		return &APIError{
+			Code:      ErrInvalidEntity,
+			Parameter: query.Entity,
+			Message:   "entity '" + entity + "' is not a valid entity ref: " + strings.Join(errs, "; "),
+			Hint:      "use a Backstage entity ref whose name is a label value, ex: entity=component:default/web",
+		}
+	}
+	return nil
+}
+
+// requireIntervals checks that intervals is a repeating interval of Backstage cost insights
+func requireIntervals(queryParams url.Values) *APIError {
+	intervals, isIntervals, apiErr := getSingleValue(queryParams, query.Intervals)
+	if apiErr != nil {
+		return apiErr
+	}
+	if !isIntervals {
+		return &APIError{
+			Code:      ErrMissingParameter,
+			Parameter: query.Intervals,
+			Message:   "no intervals is given",
+			Hint:      "add query parameter intervals=R<repetitions>/P<days>D/<end date>, ex: intervals=R2/P30D/2020-09-01",
+		}
+	}
+	if _, err := query.ParseCostIntervals(intervals); err != nil {
+		return &APIError{
+			Code:      ErrInvalidIntervals,
+			Parameter: query.Intervals,
+			Message:   err.Error(),
+			Hint:      "use an ISO 8601 repeating interval in days or months, ex: intervals=R2/P30D/2020-09-01 or intervals=R2/P3M/2020-09-01",
+		}
+	}
+	return nil
+}
+
+// requireCostSnapshotType checks that type is a type of resources whose daily cost is snapshotted
+func requireCostSnapshotType(queryParams url.Values) *APIError {
+	resourceType, isType, apiErr := getSingleValue(queryParams, query.Type)
+	if apiErr != nil {
+		return apiErr
+	}
+	if !isType {
+		return &APIError{
+			Code:      ErrMissingParameter,
+			Parameter: query.Type,
+			Message:   "no type is given",
+			Hint:      "add query parameter type=" + query.NamespaceType + ", type=" + query.PodType + " or type=" + query.NodeType,
+		}
+	}
+	if !query.IsCostSnapshotType(resourceType) {
+		return &APIError{
+			Code:      ErrInvalidType,
+			Parameter: query.Type,
+			Message:   "type '" + resourceType + "' has no daily cost snapshots",
+			Hint:      "use type=" + query.NamespaceType + ", type=" + query.PodType + " or type=" + query.NodeType,
+		}
+	}
+	return nil
+}
+
+// validateLabelGroupBy checks that groupBy is a valid k8s label key if it is present
+func validateLabelGroupBy(queryParams url.Values) *APIError {
+	groupBy, isGroupBy, apiErr := getSingleValue(queryParams, query.GroupBy)
+	if apiErr != nil || !isGroupBy {
+		return apiErr
+	}
+	if errs := validation.IsQualifiedName(groupBy); len(errs) > 0 {
+		return &APIError{
+			Code:      ErrInvalidGroupBy,
+			Parameter: query.GroupBy,
+			Message:   "groupBy '" + groupBy + "' is not a valid label key: " + strings.Join(errs, "; "),
+			Hint:      "use a k8s label key, ex: groupBy=app.kubernetes.io/component",
+		}
+	}
+	return nil
+}
+
+// requireInfraTagKey checks that key of an infra tag is given, keys of cloud tags allow most characters so only
+// their length is limited
+func requireInfraTagKey(queryParams url.Values) *APIError {
+	key, isKey, apiErr := getSingleValue(queryParams, query.Key)
+	if apiErr != nil {
+		return apiErr
+	}
+	if !isKey {
+		return &APIError{
+			Code:      ErrMissingParameter,
+			Parameter: query.Key,
+			Message:   "no key is given",
+			Hint:      "add query parameter key=<tag-key>, ex: key=cost-center",
+		}
+	}
+	if key == "" || len(key) > maxTagKeyLength {
+		return &APIError{
+			Code:      ErrInvalidKey,
+			Parameter: query.Key,
+			Message:   "key '" + key + "' is not a valid tag key",
+			Hint:      "use a tag key of 1 to 128 characters, ex: key=cost-center",
+		}
+	}
+	return nil
+}
+
+// validateAlertState checks that state is either firing or resolved if it is present
+func validateAlertState(queryParams url.Values) *APIError {
+	state, isState, apiErr := getSingleValue(queryParams, query.State)
+	if apiErr != nil || !isState {
+		return apiErr
+	}
+	if state != notification.Firing && state != notification.Resolved {
+		return &APIError{
+			Code:      ErrInvalidState,
+			Parameter: query.State,
+			Message:   "state '" + state + "' is not valid",
+			Hint:      "use state=" + notification.Firing + " or state=" + notification.Resolved,
+		}
+	}
+	return nil
+}
+
+// validateMinDuration checks that minDuration is a non negative duration if it is present
+func validateMinDuration(queryParams url.Values) *APIError {
+	value, isPresent, apiErr := getSingleValue(queryParams, query.MinDuration)
+	if apiErr != nil || !isPresent {
+		return apiErr
+	}
+	if duration, err := time.ParseDuration(value); err != nil || duration < 0 {
+		return &APIError{
+			Code:      ErrInvalidDuration,
+			Parameter: query.MinDuration,
+			Message:   "minDuration '" + value + "' is not a valid duration",
+			Hint:      "use a non negative duration, ex: minDuration=30m",
+		}
+	}
+	return nil
+}
+
+// validateBudget checks that budget is a non negative amount if it is present
+func validateBudget(queryParams url.Values) *APIError {
+	value, isPresent, apiErr := getSingleValue(queryParams, query.Budget)
+	if apiErr != nil || !isPresent {
+		return apiErr
+	}
+	if budget, err := strconv.ParseFloat(value, 64); err != nil || budget < 0 || math.IsNaN(budget) || math.IsInf(budget, 0) {
+		return &APIError{
+			Code:      ErrInvalidBudget,
+			Parameter: query.Budget,
+			Message:   "budget '" + value + "' is not a valid amount",
+			Hint:      "use a non negative monthly amount, ex: budget=500",
+		}
+	}
+	return nil
+}
+
+// validatePredicates checks that each predicate in query params is an indexed predicate of purser schema
+func validatePredicates(queryParams url.Values) *APIError {
+	indexed := dgraph.IndexedPredicates()
+	isIndexed := make(map[string]bool)
+	for _, predicate := range indexed {
+		isIndexed[predicate] = true
+	}
+	for _, predicate := range queryParams[query.Predicate] {
+		if !isIndexed[predicate] {
+			return &APIError{
+				Code:      ErrInvalidPredicate,
+				Parameter: query.Predicate,
+				Message:   "predicate '" + predicate + "' has no index in purser schema",
+				Hint:      "use one of " + strings.Join(indexed, ", "),
+			}
+		}
+	}
+	return nil
+}
+
+// requireLogLevel checks that a level is present in query params and that the component is a valid name if
+// it is present
+func requireLogLevel(queryParams url.Values) *APIError {
+	level, isLevel, apiErr := getSingleValue(queryParams, query.Level)
+	if apiErr != nil {
+		return apiErr
+	}
+	if !isLevel {
+		return &APIError{
+			Code:      ErrMissingParameter,
+			Parameter: query.Level,
+			Message:   "no level is given",
+			Hint:      "add query parameter level=debug, level=info, level=warning or level=error",
+		}
+	}
+	if !logging.IsLevel(level) {
+		return &APIError{
+			Code:      ErrInvalidLevel,
+			Parameter: query.Level,
+			Message:   "level '" + level + "' is not a log level",
+			Hint:      "use level=debug, level=info, level=warning or level=error",
+		}
+	}
+	component, _, apiErr := getSingleValue(queryParams, query.Component)
+	if apiErr != nil {
+		return apiErr
+	}
+	if component != "" && !query.IsValidName(component) {
+		return &APIError{
+			Code:      ErrInvalidName,
+			Parameter: query.Component,
+			Message:   "component '" + component + "' is not a valid name",
+			Hint:      "use a component such as component=query, component=dgraph, component=api or component=grpcapi",
+		}
+	}
+	return nil
+}
@@ -0,0 +1,223 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package apiHandlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vmware/purser/test/utils"
+)
+
+func TestRequireName(t *testing.T) {
+	utils.Equals(t, ErrMissingParameter, requireName(url.Values{}).Code)
+	utils.Equals(t, ErrInvalidName, requireName(url.Values{"name": {""}}).Code)
+	utils.Equals(t, ErrInvalidName, requireName(url.Values{"name": {`pod-a") { uid }`}}).Code)
+	utils.Equals(t, ErrDuplicateParameter, requireName(url.Values{"name": {"pod-a", "pod-b"}}).Code)
+	utils.Assert(t, requireName(url.Values{"name": {"pod-purser-0*2018-10-10T10:10:10+05:30"}}) == nil, "valid name rejected")
+}
+
+func TestValidateName(t *testing.T) {
+	utils.Assert(t, validateName(url.Values{}) == nil, "optional name rejected")
+}
+
+func TestValidateNamespace(t *testing.T) {
+	utils.Assert(t, validateNamespace(url.Values{}) == nil, "optional namespace rejected")
+	utils.Assert(t, validateNamespace(url.Values{"namespace": {"namespace-default"}}) == nil, "valid namespace rejected")
+	utils.Equals(t, ErrInvalidName, validateNamespace(url.Values{"namespace": {""}}).Code)
+	utils.Equals(t, ErrInvalidName, validateNamespace(url.Values{"namespace": {`namespace-a") { uid }`}}).Code)
+	utils.Equals(t, ErrDuplicateParameter, validateNamespace(url.Values{"namespace": {"namespace-a", "namespace-b"}}).Code)
+}
+
+func TestValidateViewAndOrphan(t *testing.T) {
+	utils.Assert(t, validateView(url.Values{"view": {"physical"}}) == nil, "valid view rejected")
+	utils.Equals(t, ErrInvalidView, validateView(url.Values{"view": {"virtual"}}).Code)
+	utils.Assert(t, validateOrphan(url.Values{"orphan": {"false"}}) == nil, "valid orphan rejected")
+	utils.Equals(t, ErrInvalidBoolean, validateOrphan(url.Values{"orphan": {"no"}}).Code)
+}
+
+func TestValidateOS(t *testing.T) {
+	utils.Assert(t, validateOS(url.Values{}) == nil, "optional os rejected")
+	utils.Assert(t, validateOS(url.Values{"os": {"windows"}}) == nil, "valid os rejected")
+	utils.Equals(t, ErrInvalidOS, validateOS(url.Values{"os": {"darwin"}}).Code)
+}
+
+func TestValidateMatch(t *testing.T) {
+	utils.Assert(t, validateMatch(url.Values{}) == nil, "optional match rejected")
+	utils.Assert(t, validateMatch(url.Values{"name": {"fe"}, "match": {"fuzzy"}}) == nil, "valid fuzzy match rejected")
+	utils.Assert(t, validateMatch(url.Values{"name": {"Frontend"}, "match": {"ignoreCase"}}) == nil, "valid ignoreCase match rejected")
+	utils.Equals(t, ErrInvalidName, validateMatch(url.Values{"name": {"fe"}, "match": {"partial"}}).Code)
+	utils.Equals(t, ErrInvalidMatch, validateMatch(url.Values{"name": {"frontend"}, "match": {"regex"}}).Code)
+}
+
+func TestValidateGroupBy(t *testing.T) {
+	utils.Assert(t, validateGroupBy(url.Values{}) == nil, "optional groupBy rejected")
+	utils.Assert(t, validateGroupBy(url.Values{"groupBy": {"kind"}}) == nil, "valid groupBy rejected")
+	utils.Equals(t, ErrInvalidGroupBy, validateGroupBy(url.Values{"groupBy": {"label"}}).Code)
+}
+
+func TestValidateCostMode(t *testing.T) {
+	utils.Assert(t, validateCostMode(url.Values{}) == nil, "optional costMode rejected")
+	utils.Assert(t, validateCostMode(url.Values{"costMode": {"usage"}}) == nil, "valid costMode rejected")
+	utils.Equals(t, ErrInvalidCostMode, validateCostMode(url.Values{"costMode": {"limit"}}).Code)
+}
+
+func TestValidateDeleted(t *testing.T) {
+	utils.Assert(t, validateDeleted(url.Values{}) == nil, "optional deleted rejected")
+	utils.Assert(t, validateDeleted(url.Values{"deleted": {"exclude"}}) == nil, "valid deleted rejected")
+	utils.Equals(t, ErrInvalidDeleted, validateDeleted(url.Values{"deleted": {"true"}}).Code)
+}
+
+func TestValidateImageGroupBy(t *testing.T) {
+	utils.Assert(t, validateImageGroupBy(url.Values{}) == nil, "optional groupBy rejected")
+	utils.Assert(t, validateImageGroupBy(url.Values{"groupBy": {"registry"}}) == nil, "valid groupBy rejected")
+	utils.Equals(t, ErrInvalidGroupBy, validateImageGroupBy(url.Values{"groupBy": {"kind"}}).Code)
+}
+
+func TestValidateZoneGroupBy(t *testing.T) {
+	utils.Assert(t, validateZoneGroupBy(url.Values{}) == nil, "optional groupBy rejected")
+	utils.Assert(t, validateZoneGroupBy(url.Values{"groupBy": {"region"}}) == nil, "valid groupBy rejected")
+	utils.Equals(t, ErrInvalidGroupBy, validateZoneGroupBy(url.Values{"groupBy": {"registry"}}).Code)
+}
+
+func TestRequireInfraTagKey(t *testing.T) {
+	utils.Assert(t, requireInfraTagKey(url.Values{"key": {"aws:cloudformation:stack-name"}}) == nil, "valid key rejected")
+	utils.Equals(t, ErrMissingParameter, requireInfraTagKey(url.Values{}).Code)
+	utils.Equals(t, ErrInvalidKey, requireInfraTagKey(url.Values{"key": {""}}).Code)
+	utils.Equals(t, ErrInvalidKey, requireInfraTagKey(url.Values{"key": {strings.Repeat("k", 129)}}).Code)
+}
+
+func TestValidateTimeRange(t *testing.T) {
+	utils.Assert(t, validateTimeRange(url.Values{"start": {"2018-10-01T00:00:00Z"}, "end": {"2018-11-01T00:00:00Z"}}) == nil, "valid time range rejected")
+	utils.Equals(t, ErrInvalidTime, validateTimeRange(url.Values{"start": {"yesterday"}}).Code)
+	utils.Equals(t, ErrInvalidTimeRange, validateTimeRange(url.Values{"start": {"2018-11-01T00:00:00Z"}, "end": {"2018-10-01T00:00:00Z"}}).Code)
+}
+
+func TestValidatePagination(t *testing.T) {
+	utils.Assert(t, validatePagination(url.Values{"first": {"10"}, "offset": {"20"}, "after": {"0x1a"}}) == nil, "valid pagination rejected")
+	utils.Equals(t, ErrInvalidPagination, validatePagination(url.Values{"first": {"0"}}).Code)
+	utils.Equals(t, ErrInvalidPagination, validatePagination(url.Values{"first": {"100000"}}).Code)
+	utils.Equals(t, ErrInvalidPagination, validatePagination(url.Values{"offset": {"-1"}}).Code)
+	utils.Equals(t, ErrInvalidPagination, validatePagination(url.Values{"after": {"abc"}}).Code)
+}
+
+func TestValidateLabel(t *testing.T) {
+	utils.Assert(t, validateLabel(url.Values{"label": {"example.com/tenant"}}) == nil, "valid label rejected")
+	utils.Equals(t, ErrInvalidLabel, validateLabel(url.Values{"label": {"tenant\") OR has(isPod"}}).Code)
+}
+
+func TestValidateRequest(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/api/metrics/pod", nil)
+	w := httptest.NewRecorder()
+	_, isValid := validateRequest(w, r, requireName)
+	utils.Assert(t, !isValid, "request without name is valid")
+	utils.Equals(t, http.StatusBadRequest, w.Code)
+	utils.Equals(t, "{\"error\":{\"code\":\"MISSING_PARAMETER\",\"parameter\":\"name\",\"message\":\"no name is given\",\"hint\":\"add query parameter name=\\u003cresource-name\\u003e\"}}\n", w.Body.String())
+}
+
+func TestValidateAlertState(t *testing.T) {
+	utils.Assert(t, validateAlertState(url.Values{"state": {"firing"}}) == nil, "valid state rejected")
+	utils.Assert(t, validateAlertState(url.Values{}) == nil, "optional state rejected")
+	utils.Equals(t, ErrInvalidState, validateAlertState(url.Values{"state": {"pending"}}).Code)
+}
+
+func TestValidateMinDuration(t *testing.T) {
+	utils.Assert(t, validateMinDuration(url.Values{"minDuration": {"30m"}}) == nil, "valid min duration rejected")
+	utils.Assert(t, validateMinDuration(url.Values{}) == nil, "optional min duration rejected")
+	utils.Equals(t, ErrInvalidDuration, validateMinDuration(url.Values{"minDuration": {"soon"}}).Code)
+	utils.Equals(t, ErrInvalidDuration, validateMinDuration(url.Values{"minDuration": {"-1h"}}).Code)
+}
+
+func TestValidateChargebackGroupBy(t *testing.T) {
+	utils.Assert(t, validateChargebackGroupBy(url.Values{"groupBy": {"owner"}}) == nil, "valid groupBy rejected")
+	utils.Assert(t, validateChargebackGroupBy(url.Values{"groupBy": {"cluster"}}) == nil, "valid groupBy rejected")
+	utils.Assert(t, validateChargebackGroupBy(url.Values{}) == nil, "optional groupBy rejected")
+	utils.Equals(t, ErrInvalidGroupBy, validateChargebackGroupBy(url.Values{"groupBy": {"kind"}}).Code)
+}
+
+func TestValidateFormat(t *testing.T) {
+	utils.Assert(t, validateFormat(url.Values{"format": {"csv"}}) == nil, "valid format rejected")
+	utils.Assert(t, validateFormat(url.Values{}) == nil, "optional format rejected")
+	utils.Equals(t, ErrInvalidFormat, validateFormat(url.Values{"format": {"xlsx"}}).Code)
+}
+
+func TestValidatePathName(t *testing.T) {
+	utils.Assert(t, validatePathName("namespace", "kube-system") == nil, "valid namespace rejected")
+	utils.Assert(t, validatePathName("pod", "purser-0.web") == nil, "valid pod rejected")
+	utils.Equals(t, ErrInvalidName, validatePathName("namespace", "team.a").Code)
+	utils.Equals(t, ErrInvalidName, validatePathName("pod", `a") { uid }`).Code)
+}
+
+func TestRequireEntity(t *testing.T) {
+	utils.Assert(t, requireEntity(url.Values{"entity": {"component:default/web"}}) == nil, "valid entity rejected")
+	utils.Equals(t, ErrMissingParameter, requireEntity(url.Values{}).Code)
+	utils.Equals(t, ErrInvalidEntity, requireEntity(url.Values{"entity": {"component:default/"}}).Code)
+}
+
+func TestRequireIntervals(t *testing.T) {
+	utils.Assert(t, requireIntervals(url.Values{"intervals": {"R2/P30D/2020-09-01"}}) == nil, "valid intervals rejected")
+	utils.Equals(t, ErrMissingParameter, requireIntervals(url.Values{}).Code)
+	utils.Equals(t, ErrInvalidIntervals, requireIntervals(url.Values{"intervals": {"P30D"}}).Code)
+}
+
+func TestRequireCostSnapshotType(t *testing.T) {
+	utils.Assert(t, requireCostSnapshotType(url.Values{"type": {"namespace"}}) == nil, "valid type rejected")
+	utils.Equals(t, ErrMissingParameter, requireCostSnapshotType(url.Values{}).Code)
+	utils.Equals(t, ErrInvalidType, requireCostSnapshotType(url.Values{"type": {"container"}}).Code)
+}
+
+func TestRequireLogLevel(t *testing.T) {
+	utils.Assert(t, requireLogLevel(url.Values{"level": {"debug"}}) == nil, "valid level rejected")
+	utils.Assert(t, requireLogLevel(url.Values{"level": {"error"}, "component": {"query"}}) == nil, "valid component rejected")
+	utils.Equals(t, ErrMissingParameter, requireLogLevel(url.Values{"component": {"query"}}).Code)
+	utils.Equals(t, ErrInvalidLevel, requireLogLevel(url.Values{"level": {"verbose"}}).Code)
+	utils.Equals(t, ErrInvalidName, requireLogLevel(url.Values{"level": {"info"}, "component": {"{query}"}}).Code)
+}
+
+func TestValidateBudget(t *testing.T) {
+	utils.Assert(t, validateBudget(url.Values{"budget": {"500.5"}}) == nil, "valid budget rejected")
+	utils.Assert(t, validateBudget(url.Values{}) == nil, "optional budget rejected")
+	utils.Equals(t, ErrInvalidBudget, validateBudget(url.Values{"budget": {"lots"}}).Code)
+	utils.Equals(t, ErrInvalidBudget, validateBudget(url.Values{"budget": {"-10"}}).Code)
+	utils.Equals(t, ErrInvalidBudget, validateBudget(url.Values{"budget": {"NaN"}}).Code)
+}
+
+func TestValidateAsOf(t *testing.T) {
+	utils.Assert(t, validateAsOf(url.Values{"asOf": {"2018-10-01T00:00:00Z"}}) == nil, "valid asOf rejected")
+	utils.Equals(t, ErrInvalidTime, validateAsOf(url.Values{"asOf": {"yesterday"}}).Code)
+	future := time.Now().Add(time.Hour).Format(time.RFC3339)
+	utils.Equals(t, ErrInvalidTime, validateAsOf(url.Values{"asOf": {future}}).Code)
+}
+
+func TestValidateDiffTimeRange(t *testing.T) {
+	utils.Assert(t, validateDiffTimeRange(url.Values{"start": {"2018-10-01T00:00:00Z"}, "end": {"2018-11-01T00:00:00Z"}}) == nil, "valid range rejected")
+	utils.Equals(t, ErrMissingParameter, validateDiffTimeRange(url.Values{"start": {"2018-10-01T00:00:00Z"}}).Code)
+	utils.Equals(t, ErrInvalidTimeRange, validateDiffTimeRange(url.Values{"start": {"2018-11-01T00:00:00Z"}, "end": {"2018-10-01T00:00:00Z"}}).Code)
+	future := time.Now().Add(time.Hour).Format(time.RFC3339)
+	utils.Equals(t, ErrInvalidTime, validateDiffTimeRange(url.Values{"start": {"2018-10-01T00:00:00Z"}, "end": {future}}).Code)
+}
+
+func TestValidatePredicates(t *testing.T) {
+	utils.Assert(t, validatePredicates(url.Values{}) == nil, "optional predicate rejected")
+	utils.Assert(t, validatePredicates(url.Values{"predicate": {"name", "endTime"}}) == nil, "valid predicates rejected")
+	utils.Equals(t, ErrInvalidPredicate, validatePredicates(url.Values{"predicate": {"name", "isPod"}}).Code)
+}
@@ -0,0 +1,62 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/vmware/purser/cmd/controller/api/apiHandlers"
+	"github.com/vmware/purser/pkg/controller/dgraph"
+	"github.com/vmware/purser/pkg/controller/dgraph/models/query"
+)
+
+// ClusterScope restricts queries of the request to nodes of the cluster in query parameter cluster if it is given,
+// requests of all clusters are answered with nodes of all clusters
+func ClusterScope(inner http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		values, isCluster := r.URL.Query()[query.Cluster]
+		if !isCluster {
+			inner.ServeHTTP(w, r)
+			return
+		}
+		if len(values) > 1 || !dgraph.IsValidClusterName(values[0]) {
+			writeInvalidCluster(w, values)
+			return
+		}
+		inner.ServeHTTP(w, r.WithContext(dgraph.WithCluster(r.Context(), values[0])))
+	})
+}
+
+func writeInvalidCluster(w http.ResponseWriter, values []string) {
+	apiErr := apiHandlers.APIError{
+		Code:      apiHandlers.ErrInvalidName,
+		Parameter: query.Cluster,
+		Message:   "cluster is not a valid cluster name",
+		Hint:      "pass one cluster of letters, digits, '-', '.' or '_', ex: cluster=production",
+	}
+	if len(values) > 1 {
+		apiErr.Code = apiHandlers.ErrDuplicateParameter
+		apiErr.Message = "parameter cluster is given more than once"
+	}
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	w.WriteHeader(http.StatusBadRequest)
+	if err := json.NewEncoder(w).Encode(apiHandlers.APIErrorWrapper{Error: apiErr}); err != nil {
+		log.Errorf("Unable to encode to json: (%v)", err)
+	}
+}
@@ -0,0 +1,140 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/vmware/purser/pkg/controller/dgraph"
+)
+
+// StaleHeader is set to true on last known responses returned while dgraph is unavailable
+const StaleHeader = "X-Purser-Stale"
+
+// maxLastKnownResponses is the number of last known responses kept by a route, the oldest one is replaced when it is full
+const maxLastKnownResponses = 256
+
+// summaryRoutes are routes whose last known responses are returned while dgraph is unavailable instead of errors
+var summaryRoutes = map[string]bool{
+	"GetClusterHierarchy":       true,
+	"GetClusterMetrics":         true,
+	"GetClusterTotals":          true,
+	"GetClusterNodes":           true,
+	"GetNamespaceMetrics":       true,
+	"GetScopedNamespaceMetrics": true,
+	"GetCostTimeSeries":         true,
+	"GetChargebackReport":       true,
+}
+
+// replaced in tests
+var isDgraphAvailable = dgraph.IsAvailable
+
+// lastKnownResponses are the last successful responses of a route per client and request URI
+type lastKnownResponses struct {
+	mu        sync.Mutex
+	responses map[string]*cachedResponse
+}
+
+func (c *lastKnownResponses) get(key string) *cachedResponse {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.responses[key]
+}
+
+func (c *lastKnownResponses) put(key string, response *cachedResponse) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.responses == nil {
+		c.responses = make(map[string]*cachedResponse)
+	}
+	if _, isPresent := c.responses[key]; !isPresent && len(c.responses) >= maxLastKnownResponses {
+		oldestKey := ""
+		for cachedKey, cached := range c.responses {
+			if oldestKey == "" || cached.executed.Before(c.responses[oldestKey].executed) {
+				oldestKey = cachedKey
+			}
+		}
+		delete(c.responses, oldestKey)
+	}
+	c.responses[key] = response
+}
+
+// Degraded returns the last known response of the request, flagged as stale, while dgraph is unavailable(its
+// circuit breaker is open) instead of executing it. Requests without a last known response are executed.
+func Degraded(inner http.Handler, name string) http.Handler {
+	cache := &lastKnownResponses{}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := getClientKey(r) + " " + r.Method + " " + r.URL.RequestURI()
+		if !isDgraphAvailable() {
+			if lastKnown := cache.get(key); lastKnown != nil {
+				log.Warnf("dgraph is unavailable, returning last known response of %s of %v", name, lastKnown.executed)
+				writeStaleResponse(w, lastKnown, time.Now())
+				return
+			}
+		}
+
+		start := time.Now()
+		recorder := &responseRecorder{ResponseWriter: w}
+		inner.ServeHTTP(recorder, r)
+		// responses of requests which failed because dgraph became unavailable are not kept
+		if recorder.status != http.StatusOK || !isDgraphAvailable() {
+			return
+		}
+		cache.put(key, &cachedResponse{
+			status:   recorder.status,
+			header:   copyHeader(w.Header()),
+			body:     recorder.body.Bytes(),
+			executed: start,
+		})
+	})
+}
+
+// writeStaleResponse writes the last known response with header X-Purser-Stale, JSON objects get fields stale and
+// staleSince(RFC3339 time of the execution of the response)
+func writeStaleResponse(w http.ResponseWriter, lastKnown *cachedResponse, now time.Time) {
+	for key, values := range lastKnown.header {
+		if key != http.CanonicalHeaderKey(RequestIDHeader) && key != "Content-Length" {
+			w.Header()[key] = values
+		}
+	}
+	w.Header().Set(StaleHeader, "true")
+	w.Header().Set("Age", strconv.Itoa(int(now.Sub(lastKnown.executed).Seconds())))
+	w.Header().Set("Warning", `110 - "Response is Stale"`)
+	w.WriteHeader(lastKnown.status)
+	if _, err := w.Write(getStaleBody(lastKnown.body, lastKnown.executed)); err != nil {
+		log.Errorf("unable to write last known response, %v", err)
+	}
+}
+
+func getStaleBody(body []byte, executed time.Time) []byte {
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(body, &fields); err != nil || fields == nil {
+		return body
+	}
+	fields["stale"] = json.RawMessage("true")
+	fields["staleSince"] = json.RawMessage(strconv.Quote(executed.UTC().Format(time.RFC3339)))
+	staleBody, err := json.Marshal(fields)
+	if err != nil {
+		return body
+	}
+	return append(staleBody, '\n')
+}
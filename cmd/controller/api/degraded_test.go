@@ -0,0 +1,77 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vmware/purser/test/utils"
+)
+
+func TestDegraded(t *testing.T) {
+	isAvailable := true
+	isDgraphAvailable = func() bool { return isAvailable }
+	defer func() { isDgraphAvailable = func() bool { return true } }()
+
+	executions := 0
+	handler := RequestID(Degraded(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		executions++
+		w.Header().Set("Content-Type", "application/json")
+		if !isAvailable {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		_, _ = w.Write([]byte(`{"name":"cluster","cpuCost":1.5}` + "\n"))
+	}), "GetClusterMetrics"))
+
+	request := func(uri, requestID string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest("GET", uri, nil)
+		r.Header.Set(RequestIDHeader, requestID)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w
+	}
+
+	fresh := request("/api/metrics", "first")
+	utils.Equals(t, "", fresh.Header().Get(StaleHeader))
+
+	isAvailable = false
+	stale := request("/api/metrics", "second")
+	utils.Equals(t, 1, executions)
+	utils.Equals(t, http.StatusOK, stale.Code)
+	utils.Equals(t, "true", stale.Header().Get(StaleHeader))
+	utils.Equals(t, "second", stale.Header().Get(RequestIDHeader))
+	utils.Equals(t, "application/json", stale.Header().Get("Content-Type"))
+	utils.Assert(t, stale.Header().Get("Age") != "", "expected Age header")
+	body := stale.Body.String()
+	utils.Assert(t, strings.HasPrefix(body, `{"cpuCost":1.5,"name":"cluster","stale":true,"staleSince":"`), "unexpected stale body: %s", body)
+
+	unknown := request("/api/metrics?view=physical", "third")
+	utils.Equals(t, 2, executions)
+	utils.Equals(t, http.StatusInternalServerError, unknown.Code)
+	utils.Equals(t, "", unknown.Header().Get(StaleHeader))
+}
+
+func TestGetStaleBodyKeepsNonObjects(t *testing.T) {
+	utils.Equals(t, `[{"name":"node-1"}]`, string(getStaleBody([]byte(`[{"name":"node-1"}]`), time.Now())))
+	utils.Equals(t, `null`, string(getStaleBody([]byte(`null`), time.Now())))
+}
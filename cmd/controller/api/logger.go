@@ -21,15 +21,17 @@ import (
 	"net/http"
 	"time"
 
-	"github.com/Sirupsen/logrus"
+	"github.com/vmware/purser/pkg/logging"
 )
 
+var log = logging.Component("api")
+
 // Logger implements web logging logic
 func Logger(inner http.Handler, name string) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		start := time.Now()
 		inner.ServeHTTP(w, r)
-		logrus.Infof(
+		logging.WithContext(log, r.Context()).Infof(
 			"%s\t%s\t%s\t%s",
 			r.Method,
 			r.RequestURI,
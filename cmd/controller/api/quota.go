@@ -0,0 +1,267 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package api
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vmware/purser/cmd/controller/api/apiHandlers"
+)
+
+// QuotaCostFactor is the multiple of the duration of an execution that a client waits before executing
+// the same expensive request again if it is longer than the quota interval
+const QuotaCostFactor = 10
+
+// MaxCachedResponses is the number of responses cached per expensive route, the response expiring first is evicted
+// when a response of another client is cached
+const MaxCachedResponses = 1000
+
+// expensiveRoutes are routes querying the whole cluster, their responses are cached per client
+var expensiveRoutes = map[string]bool{
+	"GetPodInteractions":   true,
+	"GetClusterHierarchy":  true,
+	"GetClusterMetrics":    true,
+	"GetClusterDiff":       true,
+	"GetPodDiscoveryNodes": true,
+	"GetPodDiscoveryEdges": true,
+	"GetClusterNodes":      true,
+	"GetClusterIdleCost":   true,
+	"GetClusterTotals":     true,
+}
+
+var quotaInterval = 30 * time.Second
+
+// trustedProxies are the networks of proxies whose X-Forwarded-For header identifies the client
+var trustedProxies []*net.IPNet
+
+// getUser is replaced in tests
+var getUser = apiHandlers.GetUser
+
+// SetQuota sets the minimum interval between executions of the same expensive request by a client,
+// zero disables the quota
+func SetQuota(interval time.Duration) {
+	if interval < 0 {
+		log.Errorf("quota interval can't be negative: %v", interval)
+		return
+	}
+	quotaInterval = interval
+}
+
+// SetTrustedProxies sets the addresses or networks(CIDR) of proxies in front of the API, requests from them are
+// identified by the last address in their X-Forwarded-For header which is not a trusted proxy
+func SetTrustedProxies(proxies []string) error {
+	networks := []*net.IPNet{}
+	for _, proxy := range proxies {
+		if strings.Contains(proxy, "/") {
+			_, network, err := net.ParseCIDR(proxy)
+			if err != nil {
+				return fmt.Errorf("invalid trusted proxy %s: %v", proxy, err)
+			}
+			networks = append(networks, network)
+			continue
+		}
+		ip := net.ParseIP(proxy)
+		if ip == nil {
+			return fmt.Errorf("invalid trusted proxy address: %s", proxy)
+		}
+		if ipv4 := ip.To4(); ipv4 != nil {
+			ip = ipv4
+		}
+		networks = append(networks, &net.IPNet{IP: ip, Mask: net.CIDRMask(len(ip)*8, len(ip)*8)})
+	}
+	trustedProxies = networks
+	return nil
+}
+
+// cachedResponse is the response of an execution of an expensive request which is returned to its client until
+// the request can be executed again
+type cachedResponse struct {
+	status   int
+	header   http.Header
+	body     []byte
+	executed time.Time
+	expires  time.Time
+}
+
+type responseCache struct {
+	mu        sync.Mutex
+	responses map[string]*cachedResponse
+}
+
+func (c *responseCache) get(key string, now time.Time) *cachedResponse {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	response, isCached := c.responses[key]
+	if !isCached || !now.Before(response.expires) {
+		return nil
+	}
+	return response
+}
+
+func (c *responseCache) put(key string, response *cachedResponse) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.responses == nil {
+		c.responses = make(map[string]*cachedResponse)
+	}
+	firstKey := ""
+	for cachedKey, cached := range c.responses {
+		if !response.executed.Before(cached.expires) {
+			delete(c.responses, cachedKey)
+		} else if firstKey == "" || cached.expires.Before(c.responses[firstKey].expires) {
+			firstKey = cachedKey
+		}
+	}
+	if _, isCached := c.responses[key]; !isCached && len(c.responses) >= MaxCachedResponses {
+		delete(c.responses, firstKey)
+	}
+	c.responses[key] = response
+}
+
+// responseRecorder writes the response to the client and keeps a copy of it
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
+
+// Quota limits how often a client executes the same expensive request. Until the quota interval, or QuotaCostFactor
+// times the duration of the execution if it is longer, has passed the client gets the response of its last execution.
+func Quota(inner http.Handler, name string) http.Handler {
+	cache := &responseCache{}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if quotaInterval == 0 {
+			inner.ServeHTTP(w, r)
+			return
+		}
+		key := getClientKey(r) + " " + r.Method + " " + r.URL.RequestURI()
+		start := time.Now()
+		if cached := cache.get(key, start); cached != nil {
+			log.Debugf("quota of %s exceeded, returning response of %v", name, cached.executed)
+			writeCachedResponse(w, cached, start)
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: w}
+		inner.ServeHTTP(recorder, r)
+		if recorder.status != http.StatusOK {
+			return
+		}
+		cache.put(key, &cachedResponse{
+			status:   recorder.status,
+			header:   copyHeader(w.Header()),
+			body:     recorder.body.Bytes(),
+			executed: start,
+			expires:  start.Add(getQuotaInterval(time.Since(start))),
+		})
+	})
+}
+
+// getQuotaInterval returns the quota interval, or QuotaCostFactor times the duration if it is longer
+func getQuotaInterval(duration time.Duration) time.Duration {
+	if costInterval := QuotaCostFactor * duration; costInterval > quotaInterval {
+		return costInterval
+	}
+	return quotaInterval
+}
+
+// getClientKey identifies the client by its address and its authenticated user, the user of its session or of its
+// bearer token, so that users behind the same address do not get responses of scopes they can not view
+func getClientKey(r *http.Request) string {
+	return getClientAddress(r) + " " + getUser(r)
+}
+
+// getClientAddress returns the address of the request, or the address in its X-Forwarded-For header added by the
+// last proxy which is not trusted if the request is from a trusted proxy
+func getClientAddress(r *http.Request) string {
+	address := r.RemoteAddr
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		address = host
+	}
+	if !isTrustedProxy(address) {
+		return address
+	}
+	forwarded := strings.Split(strings.Join(r.Header["X-Forwarded-For"], ","), ",")
+	for i := len(forwarded) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(forwarded[i])
+		if net.ParseIP(hop) == nil {
+			break
+		}
+		address = hop
+		if !isTrustedProxy(hop) {
+			break
+		}
+	}
+	return address
+}
+
+func isTrustedProxy(address string) bool {
+	ip := net.ParseIP(address)
+	if ip == nil {
+		return false
+	}
+	for _, network := range trustedProxies {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+func writeCachedResponse(w http.ResponseWriter, cached *cachedResponse, now time.Time) {
+	for key, values := range cached.header {
+		// the request ID is the one of this request, not of the cached execution
+		if key != http.CanonicalHeaderKey(RequestIDHeader) {
+			w.Header()[key] = values
+		}
+	}
+	w.Header().Set("Age", strconv.Itoa(int(now.Sub(cached.executed).Seconds())))
+	w.Header().Set("X-Purser-Cache", "hit")
+	w.WriteHeader(cached.status)
+	if _, err := w.Write(cached.body); err != nil {
+		log.Errorf("unable to write cached response, %v", err)
+	}
+}
+
+func copyHeader(header http.Header) http.Header {
+	copied := make(http.Header, len(header))
+	for key, values := range header {
+		copied[key] = append([]string(nil), values...)
+	}
+	return copied
+}
@@ -0,0 +1,137 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/vmware/purser/cmd/controller/api/apiHandlers"
+	"github.com/vmware/purser/test/utils"
+)
+
+func TestQuota(t *testing.T) {
+	executions := 0
+	handler := Quota(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		executions++
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"name":"cluster"}`))
+	}), "GetClusterHierarchy")
+
+	request := func(remoteAddr, uri string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest("GET", uri, nil)
+		r.RemoteAddr = remoteAddr
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w
+	}
+
+	first := request("10.0.0.1:5000", "/api/hierarchy")
+	utils.Equals(t, `{"name":"cluster"}`, first.Body.String())
+	utils.Equals(t, "", first.Header().Get("X-Purser-Cache"))
+
+	cached := request("10.0.0.1:5001", "/api/hierarchy")
+	utils.Equals(t, 1, executions)
+	utils.Equals(t, `{"name":"cluster"}`, cached.Body.String())
+	utils.Equals(t, "hit", cached.Header().Get("X-Purser-Cache"))
+	utils.Equals(t, "application/json", cached.Header().Get("Content-Type"))
+
+	request("10.0.0.1:5000", "/api/hierarchy?view=physical")
+	request("10.0.0.2:5000", "/api/hierarchy")
+	utils.Equals(t, 3, executions)
+}
+
+func TestQuotaSkipsFailedResponses(t *testing.T) {
+	executions := 0
+	handler := Quota(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		executions++
+		http.Error(w, "unable to connect", http.StatusInternalServerError)
+	}), "GetClusterMetrics")
+	for i := 0; i < 2; i++ {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/api/metrics", nil))
+	}
+	utils.Equals(t, 2, executions)
+}
+
+func TestGetClientKeyIncludesUser(t *testing.T) {
+	defer func() { getUser = apiHandlers.GetUser }()
+	getUser = func(r *http.Request) string {
+		switch r.Header.Get("Authorization") {
+		case "Bearer admin-token":
+			return "token:admin"
+		case "Bearer viewer-token":
+			return "token:viewer"
+		}
+		return ""
+	}
+	request := func(authorization, cookie string) *http.Request {
+		r := httptest.NewRequest("GET", "/api/cluster/totals", nil)
+		r.Header.Set("Authorization", authorization)
+		r.Header.Set("Cookie", cookie)
+		return r
+	}
+	admin := request("Bearer admin-token", "")
+	viewer := request("Bearer viewer-token", "")
+	utils.Assert(t, getClientKey(admin) != getClientKey(viewer), "users with different tokens share a client key")
+	utils.Equals(t, getClientKey(admin), getClientKey(request("Bearer admin-token", "junk=1")))
+	utils.Equals(t, getClientKey(request("Bearer invalid-token", "")), getClientKey(request("Bearer other-token", "")))
+}
+
+func TestGetClientAddress(t *testing.T) {
+	defer func() { trustedProxies = nil }()
+	request := func(remoteAddr string, forwarded ...string) *http.Request {
+		r := httptest.NewRequest("GET", "/api/hierarchy", nil)
+		r.RemoteAddr = remoteAddr
+		for _, hops := range forwarded {
+			r.Header.Add("X-Forwarded-For", hops)
+		}
+		return r
+	}
+
+	utils.Equals(t, "10.0.0.1", getClientAddress(request("10.0.0.1:5000", "1.2.3.4")))
+
+	utils.Ok(t, SetTrustedProxies([]string{"10.0.0.1", "192.168.0.0/16"}))
+	utils.Equals(t, "1.2.3.4", getClientAddress(request("10.0.0.1:5000", "1.2.3.4")))
+	utils.Equals(t, "1.2.3.4", getClientAddress(request("10.0.0.1:5000", "5.6.7.8, 1.2.3.4, 192.168.1.1")))
+	utils.Equals(t, "1.2.3.4", getClientAddress(request("10.0.0.1:5000", "5.6.7.8", "1.2.3.4")))
+	utils.Equals(t, "192.168.1.1", getClientAddress(request("10.0.0.1:5000", "junk, 192.168.1.1")))
+	utils.Equals(t, "10.0.0.1", getClientAddress(request("10.0.0.1:5000")))
+	utils.Equals(t, "10.0.0.2", getClientAddress(request("10.0.0.2:5000", "1.2.3.4")))
+
+	utils.Assert(t, SetTrustedProxies([]string{"proxy"}) != nil, "expected error of invalid proxy")
+	utils.Assert(t, SetTrustedProxies([]string{"10.0.0.0/33"}) != nil, "expected error of invalid network")
+}
+
+func TestResponseCacheIsBounded(t *testing.T) {
+	cache := &responseCache{}
+	now := time.Now()
+	for i := 0; i <= MaxCachedResponses; i++ {
+		cache.put(strconv.Itoa(i), &cachedResponse{executed: now, expires: now.Add(time.Duration(i+1) * time.Second)})
+	}
+	utils.Equals(t, MaxCachedResponses, len(cache.responses))
+	utils.Assert(t, cache.get("0", now) == nil, "expected response expiring first to be evicted")
+	utils.Assert(t, cache.get(strconv.Itoa(MaxCachedResponses), now) != nil, "expected last response to be cached")
+}
+
+func TestGetQuotaInterval(t *testing.T) {
+	utils.Equals(t, quotaInterval, getQuotaInterval(time.Second))
+	utils.Equals(t, QuotaCostFactor*time.Minute, getQuotaInterval(time.Minute))
+}
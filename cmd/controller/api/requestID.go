@@ -0,0 +1,57 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package api
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"regexp"
+
+	"github.com/vmware/purser/pkg/logging"
+)
+
+// RequestIDHeader is the header of requests and responses holding the ID of the request
+const RequestIDHeader = "X-Request-ID"
+
+// request IDs of clients are added to logs and dgraph queries, so only short IDs of safe characters are kept
+var requestIDRegex = regexp.MustCompile(`^[A-Za-z0-9\-._:]{1,64}$`)
+
+// RequestID gives each request an ID, the one in header X-Request-ID if the client(ex: a proxy) sets a valid one.
+// The ID is returned in header X-Request-ID, added to logs of the request and commented in its dgraph queries so
+// slow or failed requests reported by users can be found.
+func RequestID(inner http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID := r.Header.Get(RequestIDHeader)
+		if !requestIDRegex.MatchString(requestID) {
+			requestID = newRequestID()
+		}
+		w.Header().Set(RequestIDHeader, requestID)
+		inner.ServeHTTP(w, r.WithContext(logging.WithRequestID(r.Context(), requestID)))
+	})
+}
+
+// newRequestID returns 16 random hex characters
+func newRequestID() string {
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		log.Errorf("unable to generate request ID, %v", err)
+		return "unknown"
+	}
+	return hex.EncodeToString(id)
+}
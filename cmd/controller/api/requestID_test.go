@@ -0,0 +1,63 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/vmware/purser/pkg/logging"
+	"github.com/vmware/purser/test/utils"
+)
+
+func TestRequestID(t *testing.T) {
+	var requestID string
+	handler := RequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID = logging.RequestID(r.Context())
+	}))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/api/hierarchy", nil))
+	utils.Equals(t, 16, len(requestID))
+	utils.Equals(t, requestID, w.Header().Get(RequestIDHeader))
+
+	r := httptest.NewRequest("GET", "/api/hierarchy", nil)
+	r.Header.Set(RequestIDHeader, "proxy-42")
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	utils.Equals(t, "proxy-42", requestID)
+	utils.Equals(t, "proxy-42", w.Header().Get(RequestIDHeader))
+
+	r.Header.Set(RequestIDHeader, "id\nwith newline")
+	handler.ServeHTTP(httptest.NewRecorder(), r)
+	utils.Assert(t, requestID != "id\nwith newline", "invalid request ID of client is used")
+}
+
+func TestQuotaKeepsRequestID(t *testing.T) {
+	handler := RequestID(Quota(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"name":"cluster"}`))
+	}), "GetClusterHierarchy"))
+
+	first := httptest.NewRecorder()
+	handler.ServeHTTP(first, httptest.NewRequest("GET", "/api/hierarchy", nil))
+	cached := httptest.NewRecorder()
+	handler.ServeHTTP(cached, httptest.NewRequest("GET", "/api/hierarchy", nil))
+	utils.Equals(t, "hit", cached.Header().Get("X-Purser-Cache"))
+	utils.Assert(t, first.Header().Get(RequestIDHeader) != cached.Header().Get(RequestIDHeader), "cached response has request ID of its execution")
+}
@@ -18,6 +18,8 @@
 package api
 
 import (
+	"net/http"
+
 	"github.com/gorilla/mux"
 )
 
@@ -26,7 +28,16 @@ func NewRouter() *mux.Router {
 	router := mux.NewRouter().StrictSlash(true)
 	for _, route := range routes {
 		handlerFunc := route.HandlerFunc
-		handler := Logger(handlerFunc, route.Name)
+		var handler http.Handler = handlerFunc
+		if expensiveRoutes[route.Name] {
+			handler = Quota(handler, route.Name)
+		}
+		if summaryRoutes[route.Name] {
+			handler = Degraded(handler, route.Name)
+		}
+		handler = ClusterScope(handler)
+		handler = Logger(handler, route.Name)
+		handler = RequestID(handler)
 
 		router.
 			Methods(route.Method).
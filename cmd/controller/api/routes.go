@@ -40,6 +40,12 @@ var routes = Routes{
 		"/api",
 		apiHandlers.GetHomePage,
 	},
+	Route{
+		"GetPrometheusMetrics",
+		"GET",
+		"/metrics",
+		apiHandlers.GetPrometheusMetrics,
+	},
 	Route{
 		"GetPodInteractions",
 		"GET",
@@ -64,6 +70,12 @@ var routes = Routes{
 		"/api/hierarchy/deployment",
 		apiHandlers.GetDeploymentHierarchy,
 	},
+	Route{
+		"GetDeploymentConfigHierarchy",
+		"GET",
+		"/api/hierarchy/deploymentconfig",
+		apiHandlers.GetDeploymentConfigHierarchy,
+	},
 	Route{
 		"GetReplicasetHierarchy",
 		"GET",
@@ -142,6 +154,12 @@ var routes = Routes{
 		"/api/metrics/deployment",
 		apiHandlers.GetDeploymentMetrics,
 	},
+	Route{
+		"GetDeploymentConfigMetrics",
+		"GET",
+		"/api/metrics/deploymentconfig",
+		apiHandlers.GetDeploymentConfigMetrics,
+	},
 	Route{
 		"GetDaemonsetMetrics",
 		"GET",
@@ -154,6 +172,12 @@ var routes = Routes{
 		"/api/metrics/job",
 		apiHandlers.GetJobMetrics,
 	},
+	Route{
+		"GetCronJobMetrics",
+		"GET",
+		"/api/metrics/cronjob",
+		apiHandlers.GetCronJobMetrics,
+	},
 	Route{
 		"GetStatefulsetMetrics",
 		"GET",
@@ -196,6 +220,180 @@ var routes = Routes{
 		"/api/metrics/pvc",
 		apiHandlers.GetPVCMetrics,
 	},
+	Route{
+		"GetServiceUnitCosts",
+		"GET",
+		"/api/metrics/service/unitcost",
+		apiHandlers.GetServiceUnitCosts,
+	},
+	Route{
+		"GetTenantCosts",
+		"GET",
+		"/api/metrics/tenants",
+		apiHandlers.GetTenantCosts,
+	},
+	Route{
+		"GetApplicationCosts",
+		"GET",
+		"/api/apps",
+		apiHandlers.GetApplicationCosts,
+	},
+	Route{
+		"GetHelmReleaseCosts",
+		"GET",
+		"/api/helm/releases",
+		apiHandlers.GetHelmReleaseCosts,
+	},
+	Route{
+		"GetHelmChartCosts",
+		"GET",
+		"/api/helm/charts",
+		apiHandlers.GetHelmChartCosts,
+	},
+	Route{
+		"GetImageCosts",
+		"GET",
+		"/api/images",
+		apiHandlers.GetImageCosts,
+	},
+	Route{
+		"GetZoneCosts",
+		"GET",
+		"/api/zones",
+		apiHandlers.GetZoneCosts,
+	},
+	Route{
+		"GetEgressCosts",
+		"GET",
+		"/api/egress",
+		apiHandlers.GetEgressCosts,
+	},
+	Route{
+		"GetInfraTagCosts",
+		"GET",
+		"/api/infratags",
+		apiHandlers.GetInfraTagCosts,
+	},
+	Route{
+		"GetRevisionCosts",
+		"GET",
+		"/api/metrics/revisions",
+		apiHandlers.GetRevisionCosts,
+	},
+	Route{
+		"GetReplicaCosts",
+		"GET",
+		"/api/metrics/replicas",
+		apiHandlers.GetReplicaCosts,
+	},
+	Route{
+		"GetPodTimeline",
+		"GET",
+		"/api/timeline/pod",
+		apiHandlers.GetPodTimeline,
+	},
+	Route{
+		"GetWorkloadTimeline",
+		"GET",
+		"/api/timeline/workload",
+		apiHandlers.GetWorkloadTimeline,
+	},
+	Route{
+		"GetFailureCosts",
+		"GET",
+		"/api/metrics/failures",
+		apiHandlers.GetFailureCosts,
+	},
+	Route{
+		"GetCostEfficiency",
+		"GET",
+		"/api/metrics/efficiency",
+		apiHandlers.GetCostEfficiency,
+	},
+	Route{
+		"GetCostTimeSeries",
+		"GET",
+		"/api/costs/timeseries",
+		apiHandlers.GetCostTimeSeries,
+	},
+	Route{
+		"GetChargebackReport",
+		"GET",
+		"/api/export/chargeback",
+		apiHandlers.GetChargebackReport,
+	},
+	Route{
+		"GetFOCUSExport",
+		"GET",
+		"/api/export/focus",
+		apiHandlers.GetFOCUSExport,
+	},
+	Route{
+		"GetBackstageEntityCost",
+		"GET",
+		"/api/backstage/cost",
+		apiHandlers.GetBackstageEntityCost,
+	},
+	Route{
+		"EstimateManifestCost",
+		"POST",
+		"/api/estimate",
+		apiHandlers.EstimateManifestCost,
+	},
+	Route{
+		"CheckBudget",
+		"POST",
+		"/api/budget/check",
+		apiHandlers.CheckBudget,
+	},
+	Route{
+		"GetAffinityCostImpact",
+		"GET",
+		"/api/metrics/affinity",
+		apiHandlers.GetAffinityCostImpact,
+	},
+	Route{
+		"GetDedicatedPools",
+		"GET",
+		"/api/metrics/pools",
+		apiHandlers.GetDedicatedPools,
+	},
+	Route{
+		"GetOverProvisionedVolumes",
+		"GET",
+		"/api/volumes/overprovisioned",
+		apiHandlers.GetOverProvisionedVolumes,
+	},
+	Route{
+		"GetClusterDiff",
+		"GET",
+		"/api/diff",
+		apiHandlers.GetClusterDiff,
+	},
+	Route{
+		"GetNodeChurn",
+		"GET",
+		"/api/churn/nodes",
+		apiHandlers.GetNodeChurn,
+	},
+	Route{
+		"GetScaleUpCosts",
+		"GET",
+		"/api/churn/scaleups",
+		apiHandlers.GetScaleUpCosts,
+	},
+	Route{
+		"GetPendingPods",
+		"GET",
+		"/api/pods/pending",
+		apiHandlers.GetPendingPods,
+	},
+	Route{
+		"GetAlerts",
+		"GET",
+		"/api/alerts",
+		apiHandlers.GetAlerts,
+	},
 	Route{
 		"GetPodDiscoveryNodes",
 		"GET",
@@ -250,4 +448,106 @@ var routes = Routes{
 		"/api/sync",
 		apiHandlers.SyncCluster,
 	},
+	Route{
+		"GetStatus",
+		"GET",
+		"/api/status",
+		apiHandlers.GetStatus,
+	},
+	Route{
+		"RunRetention",
+		"POST",
+		"/api/admin/retention",
+		apiHandlers.RunRetention,
+	},
+	Route{
+		"Resync",
+		"POST",
+		"/api/admin/resync",
+		apiHandlers.Resync,
+	},
+	Route{
+		"RestoreNamespace",
+		"POST",
+		"/api/admin/namespace/restore",
+		apiHandlers.RestoreNamespace,
+	},
+	Route{
+		"Reindex",
+		"POST",
+		"/api/admin/reindex",
+		apiHandlers.Reindex,
+	},
+	Route{
+		"MigrateSchema",
+		"POST",
+		"/api/admin/schema/migrate",
+		apiHandlers.MigrateSchema,
+	},
+	Route{
+		"GetConsistencyReport",
+		"GET",
+		"/api/admin/consistency",
+		apiHandlers.GetConsistencyReport,
+	},
+	Route{
+		"GetBackup",
+		"GET",
+		"/api/admin/backup",
+		apiHandlers.GetBackup,
+	},
+	Route{
+		"GetLogLevels",
+		"GET",
+		"/api/admin/log",
+		apiHandlers.GetLogLevels,
+	},
+	Route{
+		"SetLogLevel",
+		"POST",
+		"/api/admin/log",
+		apiHandlers.SetLogLevel,
+	},
+	Route{
+		"GetClusterNodes",
+		"GET",
+		"/api/cluster/nodes",
+		apiHandlers.GetClusterNodes,
+	},
+	Route{
+		"GetClusterIdleCost",
+		"GET",
+		"/api/cluster/idle",
+		apiHandlers.GetClusterIdleCost,
+	},
+	Route{
+		"GetClusterTotals",
+		"GET",
+		"/api/cluster/totals",
+		apiHandlers.GetClusterTotals,
+	},
+	Route{
+		"GetScopedNamespaceMetrics",
+		"GET",
+		"/api/namespaces/{namespace}/metrics",
+		apiHandlers.GetScopedNamespaceMetrics,
+	},
+	Route{
+		"GetScopedNamespaceHierarchy",
+		"GET",
+		"/api/namespaces/{namespace}/hierarchy",
+		apiHandlers.GetScopedNamespaceHierarchy,
+	},
+	Route{
+		"GetScopedPodInteractions",
+		"GET",
+		"/api/namespaces/{namespace}/interactions",
+		apiHandlers.GetScopedPodInteractions,
+	},
+	Route{
+		"GetScopedPodMetrics",
+		"GET",
+		"/api/namespaces/{namespace}/pods/{pod}/metrics",
+		apiHandlers.GetScopedPodMetrics,
+	},
 }
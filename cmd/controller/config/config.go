@@ -23,10 +23,14 @@ import (
 	log "github.com/Sirupsen/logrus"
 
 	"github.com/vmware/purser/pkg/client"
+	alertrule_client "github.com/vmware/purser/pkg/client/clientset/typed/alertrule/v1"
+	costbudget_client "github.com/vmware/purser/pkg/client/clientset/typed/costbudget/v1"
 	group_client "github.com/vmware/purser/pkg/client/clientset/typed/groups/v1"
+	openshift_client "github.com/vmware/purser/pkg/client/clientset/typed/openshift/v1"
 	subscriber_client "github.com/vmware/purser/pkg/client/clientset/typed/subscriber/v1"
 	"github.com/vmware/purser/pkg/controller"
 	"github.com/vmware/purser/pkg/controller/buffering"
+	"github.com/vmware/purser/pkg/controller/status"
 	"github.com/vmware/purser/pkg/utils"
 )
 
@@ -55,7 +59,28 @@ func Setup(conf *controller.Config, kubeconfig string) {
 		Subscriber:            true,
 	}
 	conf.RingBuffer = &buffering.RingBuffer{Size: buffering.BufferSize, Mutex: &sync.Mutex{}}
+	status.SetBuffer(conf.RingBuffer)
 	clientset, clusterConfig := client.GetAPIExtensionClient(kubeconfig)
 	conf.Groupcrdclient = group_client.NewGroupClient(clientset, clusterConfig)
 	conf.Subscriberclient = subscriber_client.NewSubscriberClient(clientset, clusterConfig)
+	conf.Alertruleclient = alertrule_client.NewAlertRuleClient(clientset, clusterConfig)
+	conf.Costbudgetclient = costbudget_client.NewCostBudgetClient(clientset, clusterConfig)
+	setupOpenShift(conf)
+}
+
+// setupOpenShift enables ingestion of OpenShift resources if the cluster serves OpenShift APIs
+func setupOpenShift(conf *controller.Config) {
+	if !openshift_client.IsOpenShift(conf.Kubeclient.Discovery()) {
+		return
+	}
+	openShiftClient, err := openshift_client.NewOpenShiftClient(conf.KubeConfig)
+	if err != nil {
+		log.Errorf("unable to create OpenShift client, OpenShift resources will not be tracked: %v", err)
+		return
+	}
+	log.Info("OpenShift cluster detected, tracking deployment configs, routes and image streams")
+	conf.OpenShiftclient = openShiftClient
+	conf.Resource.DeploymentConfig = true
+	conf.Resource.Route = true
+	conf.Resource.ImageStream = true
 }
@@ -18,12 +18,21 @@
 package main
 
 import (
+	"context"
 	"flag"
+	"os"
+	"strings"
 	"time"
 
+	"github.com/vmware/purser/pkg/controller/dgraph/models"
 	"github.com/vmware/purser/pkg/controller/dgraph/models/query"
 
 	"github.com/vmware/purser/pkg/pricing"
+	"github.com/vmware/purser/pkg/pricing/adjustment"
+	"github.com/vmware/purser/pkg/pricing/extended"
+	"github.com/vmware/purser/pkg/pricing/external"
+	"github.com/vmware/purser/pkg/pricing/overrides"
+	"github.com/vmware/purser/pkg/pricing/storage"
 
 	log "github.com/Sirupsen/logrus"
 
@@ -31,9 +40,29 @@ import (
 	"github.com/vmware/purser/cmd/controller/api"
 	"github.com/vmware/purser/cmd/controller/config"
 	"github.com/vmware/purser/pkg/controller"
+	"github.com/vmware/purser/pkg/controller/admission"
+	"github.com/vmware/purser/pkg/controller/alerting"
+	"github.com/vmware/purser/pkg/controller/auth"
+	"github.com/vmware/purser/pkg/controller/autoscaler"
+	"github.com/vmware/purser/pkg/controller/cloudevents"
+	"github.com/vmware/purser/pkg/controller/costbudget"
 	"github.com/vmware/purser/pkg/controller/dgraph"
 	"github.com/vmware/purser/pkg/controller/discovery/processor"
+	"github.com/vmware/purser/pkg/controller/discovery/telemetry"
+	"github.com/vmware/purser/pkg/controller/discovery/telemetry/hubble"
+	"github.com/vmware/purser/pkg/controller/discovery/telemetry/istio"
+	"github.com/vmware/purser/pkg/controller/discovery/telemetry/linkerd"
+	"github.com/vmware/purser/pkg/controller/energy"
 	"github.com/vmware/purser/pkg/controller/eventprocessor"
+	"github.com/vmware/purser/pkg/controller/exporter"
+	"github.com/vmware/purser/pkg/controller/grpcapi"
+	"github.com/vmware/purser/pkg/controller/notification"
+	"github.com/vmware/purser/pkg/controller/report"
+	"github.com/vmware/purser/pkg/controller/status"
+	"github.com/vmware/purser/pkg/controller/usage"
+	"github.com/vmware/purser/pkg/controller/volume"
+	"github.com/vmware/purser/pkg/emissions"
+	"github.com/vmware/purser/pkg/logging"
 	"github.com/vmware/purser/pkg/utils"
 )
 
@@ -42,26 +71,293 @@ var conf controller.Config
 // InClusterConfigPath should be empty to get client and config for InCluster environment.
 const InClusterConfigPath = ""
 
-var interactions *string
+var interactions, autoscalerEvents, costSnapshots *string
+
+var evaluationInterval time.Duration
+
+var pricingRefreshInterval time.Duration
+var retentionInterval time.Duration
+
+var mutationLogPath string
+var mutationReplayInterval time.Duration
+
+var gcpPricingAPIKey string
+
+var admissionWebhookAddress, admissionWebhookCert, admissionWebhookKey string
+
+var grpcAPIAddress, grpcAPICert, grpcAPIKey string
 
 func init() {
-	logLevel := flag.String("log", "info", "set log level as info or debug")
+	logLevel := flag.String("log", "info", "set log level as debug, info, warning or error")
+	logFormat := flag.String("logFormat", logging.TextFormat, "format of logs, text or json")
+	logLevels := flag.String("logLevels", "", "comma separated levels of components(query, dgraph, api, grpcapi) overriding log, ex: query=error,dgraph=debug")
 	dgraphURL := flag.String("dgraphURL", "purser-db", "dgraph zero url")
 	dgraphPort := flag.String("dgraphPort", "9080", "dgraph zero port")
+	dgraphPoolSize := flag.Int("dgraphPoolSize", dgraph.DefaultPoolSize, "number of connections to dgraph of the write path and of read queries each")
+	dgraphRetries := flag.Int("dgraphRetries", dgraph.DefaultRetries, "number of retries of dgraph requests failing with transient errors, 0 disables retrying")
+	dgraphRetryBackoff := flag.Duration("dgraphRetryBackoff", dgraph.DefaultRetryBackoff, "delay before the first retry of a dgraph request, doubled for each retry")
+	dgraphRetryMaxBackoff := flag.Duration("dgraphRetryMaxBackoff", dgraph.DefaultRetryMaxBackoff, "maximum delay between retries of a dgraph request")
+	dgraphBreakerThreshold := flag.Int("dgraphBreakerThreshold", dgraph.DefaultBreakerThreshold, "consecutive dgraph requests failing after retries which stop requests to dgraph for dgraphBreakerCooldown, 0 disables it")
+	dgraphBreakerCooldown := flag.Duration("dgraphBreakerCooldown", dgraph.DefaultBreakerCooldown, "duration for which requests to dgraph fail without being sent once dgraphBreakerThreshold is reached")
+	dgraphQueryTimeout := flag.Duration("dgraphQueryTimeout", dgraph.DefaultQueryTimeout, "deadline of each dgraph query including its retries, 0 disables it")
+	dgraphTLS := flag.Bool("dgraphTLS", false, "connect to dgraph over TLS, enabled by any of the dgraphTLS files")
+	dgraphTLSCACert := flag.String("dgraphTLSCACert", "", "path to the CA certificate verifying the dgraph certificate, system CAs by default")
+	dgraphTLSCert := flag.String("dgraphTLSCert", "", "path to the client certificate presented to dgraph")
+	dgraphTLSKey := flag.String("dgraphTLSKey", "", "path to the key of the client certificate")
+	dgraphTLSServerName := flag.String("dgraphTLSServerName", "", "name verified in the dgraph certificate, host of dgraphURL by default")
+	dgraphUser := flag.String("dgraphUser", os.Getenv("DGRAPH_USER"), "user of dgraph ACL to log in as(env DGRAPH_USER), empty disables ACL login")
+	dgraphPassword := flag.String("dgraphPassword", os.Getenv("DGRAPH_PASSWORD"), "password of the dgraph ACL user(env DGRAPH_PASSWORD)")
+	mutationLogFlag := flag.String("mutationLog", "", "path of the file in which mutations of the controller are queued while dgraph is unavailable to be replayed once it is reachable, empty disables queueing")
+	mutationLogMaxSize := flag.Int64("mutationLogMaxSize", dgraph.DefaultMutationLogMaxSize, "size in bytes of the mutation log above which mutations are not queued")
+	mutationReplayIntervalFlag := flag.Duration("mutationReplayInterval", time.Minute, "interval between replays of mutations queued in the mutation log, they are also replayed at start and when dgraph is reachable again")
+	clusterName := flag.String("clusterName", os.Getenv("CLUSTER_NAME"), "name of the cluster stored with its resources(env CLUSTER_NAME) when several clusters share dgraph, empty for a single cluster")
 	interactions = flag.String("interactions", "disable", "enable discovery of interactions")
 	kubeconfig := flag.String("kubeconfig", InClusterConfigPath, "path to the kubeconfig file")
+	pricingProviders := flag.String("pricingProviders", models.RateCardPricingProvider, "comma separated pricing providers in the order of preference")
+	pricingRefresh := flag.Duration("pricingRefreshInterval", 168*time.Hour, "interval of refreshing the rate card from the price API of the cloud provider(AWS Pricing, GCP Cloud Billing Catalog, Azure Retail Prices)")
+	gcpAPIKey := flag.String("gcpPricingAPIKey", "", "API key of GCP Cloud Billing Catalog API used for the GCP rate card")
+	pricingCatalog := flag.String("pricingCatalog", "", "path to the static pricing catalog(JSON or YAML) used by static pricing provider")
+	onPremPricing := flag.String("onPremPricing", "", "path to the machine costs(JSON or YAML) used by onprem pricing provider")
+	vSpherePricing := flag.String("vSpherePricing", "", "path to the vCenter and host type prices config(JSON or YAML) used by vsphere pricing provider")
+	costAdjustments := flag.String("costAdjustments", "", "path to the cost adjustments config(JSON or YAML) applied on computed costs")
+	pricePrecision := flag.Int("pricePrecision", query.DefaultPricePrecision, "decimals of prices used in query math")
+	costPrecision := flag.Int("costPrecision", -1, "decimals to which costs returned by APIs are rounded after adjustments, negative disables rounding")
+	costRounding := flag.String("costRounding", query.RoundNearest, "rounding mode(nearest, up or down) of costs returned by APIs")
+	costModelAddress := flag.String("costModelAddress", "", "address(host:port) of external gRPC cost model service")
+	costModelTimeout := flag.Duration("costModelTimeout", 5*time.Second, "timeout of requests to external cost model service")
+	retentionMonths := flag.Int("retentionMonths", 0, "months before current month for which deleted resources are retained")
+	podRetentionMonths := flag.Int("podRetentionMonths", 2, "months before current month for which deleted pods are retained")
+	deletedNamespaceRetentionMonths := flag.Int("deletedNamespaceRetentionMonths", 12, "months before current month for which deleted namespaces and their resources are retained")
+	retentionWindow := flag.Duration("retentionWindow", 0, "duration until now for which deleted resources, deleted pods and daily costs of groups are retained(ex: 2160h for 90 days), replaces retentionMonths and podRetentionMonths, 0 disables it")
+	retentionIntervalFlag := flag.Duration("retentionInterval", dgraph.DefaultRetentionInterval, "interval between runs of retention removing old resources")
+	emissionsConfig := flag.String("emissionsConfig", "", "path of JSON/YAML file with power and carbon intensity coefficients")
+	serverlessCPUPrice := flag.Float64("serverlessCPUPrice", models.DefaultServerlessCPUCostInFloat64, "price per vCPU per hour of pods running on virtual kubelet nodes")
+	serverlessMemoryPrice := flag.Float64("serverlessMemoryPrice", models.DefaultServerlessMemCostInFloat64, "price per GB per hour of pods running on virtual kubelet nodes")
+	localDiskPrice := flag.Float64("localDiskPrice", models.DefaultLocalDiskCostInFloat64, "price per GB per hour of local disk of nodes used by ephemeral storage of pods")
+	hugepagesPrice := flag.Float64("hugepagesPrice", 0, "price per GB per hour of hugepages, memory price of the node is used if not set")
+	gpuPrice := flag.Float64("gpuPrice", models.DefaultGPUCostInFloat64, "price per GPU per hour of GPUs requested by pods")
+	gpuResources := flag.String("gpuResources", "", "comma separated extended resources counted as GPUs in addition to nvidia.com/gpu and amd.com/gpu(ex: gpu.intel.com/i915)")
+	spotDiscount := flag.Float64("spotDiscount", models.DefaultSpotDiscount, "fraction of on-demand node prices saved by spot/preemptible nodes")
+	reservedDiscount := flag.Float64("reservedDiscount", models.DefaultReservedDiscount, "fraction of on-demand node prices saved by reserved nodes(label purser.vmware.com/capacity-type=reserved)")
+	extendedResourcePrices := flag.String("extendedResourcePrices", "", "path of JSON/YAML file with hourly prices of extended resources(ex: xilinx.com/fpga)")
+	storageClassPrices := flag.String("storageClassPrices", "", "path of JSON/YAML file with prices per GB per hour of volumes per storage class or volume type(ex: gp3, io2, standard)")
+	bestEffortRequests := flag.String("bestEffortRequests", models.BestEffortRequestsNone, "requests of BestEffort pods for costing: none, static(--bestEffortCPURequest and --bestEffortMemoryRequest) or usage(average usage of the pod)")
+	bestEffortCPURequest := flag.Float64("bestEffortCPURequest", models.DefaultBestEffortCPURequest, "cpu cores requested by BestEffort pods for costing with --bestEffortRequests=static or until their usage is collected")
+	bestEffortMemoryRequest := flag.Float64("bestEffortMemoryRequest", models.DefaultBestEffortMemoryRequest, "memory in GB requested by BestEffort pods for costing with --bestEffortRequests=static or until their usage is collected")
+	bandwidthPrice := flag.Float64("bandwidthPrice", 0, "price per Mbps per hour of bandwidth reserved by kubernetes.io/ingress-bandwidth and egress-bandwidth annotations of pods")
+	egressPriceIntraZone := flag.Float64("egressPriceIntraZone", query.DefaultIntraZoneEgressPrice, "price per GB of traffic between pods in the same zone")
+	egressPriceCrossZone := flag.Float64("egressPriceCrossZone", query.DefaultCrossZoneEgressPrice, "price per GB of traffic between pods in different zones")
+	egressPriceExternal := flag.Float64("egressPriceExternal", query.DefaultExternalEgressPrice, "price per GB of traffic between pods and destinations outside the cluster")
+	containerPriceOverrides := flag.String("containerPriceOverrides", "", "path of JSON/YAML file with prices of containers matching image or name patterns")
+	interactionSources := flag.String("interactionSources", telemetry.CaptureSource, "comma separated sources of interactions(capture, istio, linkerd, hubble)")
+	istioPrometheusURL := flag.String("istioPrometheusURL", "", "url of prometheus scraping istio metrics(ex: http://prometheus.istio-system:9090)")
+	linkerdPrometheusURL := flag.String("linkerdPrometheusURL", "", "url of linkerd viz prometheus(ex: http://prometheus.linkerd-viz:9090)")
+	hubbleRelayAddress := flag.String("hubbleRelayAddress", "", "address of hubble relay grpc server(ex: hubble-relay.kube-system:80)")
+	powerPrometheusURL := flag.String("powerPrometheusURL", "", "url of prometheus scraping node power(RAPL/IPMI) and container cpu usage metrics")
+	nodePowerQuery := flag.String("nodePowerQuery", energy.RAPLNodePowerQuery, "query returning average power in Watts of each node over the last hour")
+	nodePowerLabel := flag.String("nodePowerLabel", energy.DefaultNodeLabel, "label of node power samples holding name of the node")
+	usagePrometheusURL := flag.String("usagePrometheusURL", "", "url of prometheus scraping cadvisor container cpu and memory usage metrics, enables costMode=usage")
+	volumePrometheusURL := flag.String("volumePrometheusURL", "", "url of prometheus scraping kubelet volume stats(kubelet_volume_stats_used_bytes)")
+	tenantLabel := flag.String("tenantLabel", query.DefaultTenantLabel, "label whose values identify customers/tenants of workloads")
+	sharedNamespaces := flag.String("sharedNamespaces", "kube-system", "comma separated namespaces whose cost is shared by all tenants")
+	quotaInterval := flag.Duration("quotaInterval", 30*time.Second, "minimum interval between executions of the same cluster wide query(hierarchy, metrics, interactions) by a client, 0 disables it")
+	trustedProxies := flag.String("trustedProxies", "", "comma separated addresses or networks(CIDR) of proxies in front of the API whose X-Forwarded-For header identifies clients of the quota")
+	alertEvaluationInterval := flag.Duration("alertEvaluationInterval", time.Minute, "interval of evaluation of alert rules and cost budgets")
+	notificationTimeout := flag.Duration("notificationTimeout", 10*time.Second, "timeout of requests to notification channels")
+	pagerDutyRoutingKey := flag.String("pagerDutyRoutingKey", "", "routing key of PagerDuty Events API v2 integration to page on alerts")
+	opsgenieAPIKey := flag.String("opsgenieAPIKey", "", "API key of Opsgenie integration to page on alerts")
+	opsgenieURL := flag.String("opsgenieURL", notification.OpsgenieURL, "url of Opsgenie API(ex: https://api.eu.opsgenie.com for EU accounts)")
+	teamsWebhookURL := flag.String("teamsWebhookURL", "", "url of Microsoft Teams incoming webhook receiving alerts as adaptive cards")
+	pageSeverity := flag.String("pageSeverity", notification.SeverityCritical, "minimum severity(info, warning or critical) of alerts sent to PagerDuty and Opsgenie")
+	costSnapshots = flag.String("costSnapshots", "enable", "store daily cost of pods, namespaces and nodes every hour for time series on /api/costs/timeseries")
+	autoscalerEvents = flag.String("autoscalerEvents", "enable", "collect scale ups triggered by pods from cluster-autoscaler and karpenter events")
+	metricsRefreshInterval := flag.Duration("metricsRefreshInterval", 0, "interval of refreshing costs of pods, namespaces and nodes exported as Prometheus metrics on /metrics, 0 disables the exporter")
+	telemetryTimeout := flag.Duration("telemetryTimeout", 30*time.Second, "timeout of requests to telemetry sources")
+	admissionAddress := flag.String("admissionAddress", "", "address(ex: :8443) of the admission webhook annotating workloads with their estimated monthly cost, empty disables it")
+	admissionTLSCert := flag.String("admissionTLSCert", "/etc/purser/webhook/tls.crt", "path to the TLS certificate of the admission webhook")
+	admissionTLSKey := flag.String("admissionTLSKey", "/etc/purser/webhook/tls.key", "path to the TLS private key of the admission webhook")
+	admissionCostLimits := flag.String("admissionCostLimits", "", "comma separated monthly cost limits of workloads per namespace(ex: dev=100,*=1000), * applies to other namespaces")
+	admissionDeny := flag.Bool("admissionDeny", false, "deny workloads above the cost limit of their namespace instead of admitting them with a warning")
+	focusBillingAccount := flag.String("focusBillingAccount", query.DefaultFOCUSBillingAccount, "billing account id of charges exported in the FOCUS schema on /api/export/focus(ex: name of the cluster)")
+	backstageEntityLabel := flag.String("backstageEntityLabel", query.DefaultBackstageEntityLabel, "label of pods whose value is the name of their Backstage catalog entity")
+	reportConfig := flag.String("reportConfig", "", "path of JSON/YAML file with schedule and email/Slack destinations of cost reports, empty disables them")
+	cloudEventsURL := flag.String("cloudEventsURL", "", "url(ex: Knative broker ingress) receiving resource and alert events as CloudEvents over HTTP, empty disables it")
+	cloudEventsMode := flag.String("cloudEventsMode", cloudevents.BinaryMode, "content mode(binary or structured) of CloudEvents sent over HTTP")
+	cloudEventsKafkaProxyURL := flag.String("cloudEventsKafkaProxyURL", "", "url of cluster of Kafka REST proxy v3(ex: http://kafka-rest:8082/v3/clusters/<cluster id>) producing CloudEvents to Kafka, empty disables it")
+	cloudEventsKafkaTopic := flag.String("cloudEventsKafkaTopic", "purser-events", "Kafka topic of CloudEvents")
+	apiTokenAuth := flag.Bool("apiTokenAuth", true, "authenticate API requests with a bearer token(ex: service account token) by a TokenReview of the kubernetes API")
+	apiAdmins := flag.String("apiAdmins", "", "comma separated users or groups of bearer tokens with the admin role(ex: system:serviceaccount:purser:purser-admin)")
+	apiViewers := flag.String("apiViewers", auth.AuthenticatedGroup, "comma separated users or groups of bearer tokens with the viewer role(ex: system:serviceaccounts:dev)")
+	apiTokenCacheTTL := flag.Duration("apiTokenCacheTTL", auth.DefaultCacheTTL, "duration for which results of token reviews are reused")
+	apiScopedViewers := flag.Bool("apiScopedViewers", false, "restrict viewers to /api/cluster and /api/namespaces endpoints which check their access to the scope")
+	apiAllowedFields := flag.String("apiAllowedFields", "", "comma separated fields(ex: name,cpu,labels.key) whose values responses and exports of the HTTP and gRPC APIs may contain, empty allows all fields")
+	apiDeniedFields := flag.String("apiDeniedFields", "", "comma separated fields(ex: annotations,labels.value) removed from responses and exports of the HTTP and gRPC APIs")
+	hashedLabelKeys := flag.String("hashedLabelKeys", "", "regular expression matching keys of pod labels and annotations and of node infra tags whose values are stored hashed(ex: ^(owner|contact)$), empty hashes none")
+	droppedLabelKeys := flag.String("droppedLabelKeys", "", "regular expression matching keys of pod labels and annotations and of node infra tags which are not stored(ex: ^vault\\.hashicorp\\.com/), empty drops none")
+	redactionSalt := flag.String("redactionSalt", os.Getenv("REDACTION_SALT"), "secret key of HMAC of hashed label values, so they can't be guessed from common values")
+	infraTagKeys := flag.String("infraTagKeys", "", "comma separated keys of node labels or annotations recorded as infra tags in addition to tags.purser.vmware.com/ annotations(ex: team,eks.amazonaws.com/nodegroup)")
+	grpcAddress := flag.String("grpcAddress", "", "address(ex: :3031) of the gRPC API serving pod hierarchy, metrics and interactions, empty disables it")
+	grpcTLSCert := flag.String("grpcTLSCert", "", "path to the TLS certificate of the gRPC API, plaintext if empty")
+	grpcTLSKey := flag.String("grpcTLSKey", "", "path to the TLS private key of the gRPC API")
+	namespaceBudgets := flag.String("namespaceBudgets", "", "comma separated monthly budgets per namespace(ex: dev=500,*=2000) checked on /api/budget/check, * applies to other namespaces")
 	flag.Parse()
 
 	utils.InitializeLogger(*logLevel)
+	if err := logging.Configure(*logLevel, *logFormat, *logLevels); err != nil {
+		log.Fatal(err)
+	}
 	config.Setup(&conf, *kubeconfig)
+	if err := external.Configure(*costModelAddress, *costModelTimeout); err != nil {
+		log.Fatal(err)
+	}
+	if err := pricing.ConfigurePricingProviders(*pricingProviders, *pricingCatalog, *onPremPricing, *vSpherePricing); err != nil {
+		log.Fatal(err)
+	}
+	if err := adjustment.Configure(*costAdjustments); err != nil {
+		log.Fatal(err)
+	}
+	if err := adjustment.ConfigurePrecision(*pricePrecision, *costPrecision, *costRounding); err != nil {
+		log.Fatal(err)
+	}
+	query.ConfigureTenancy(*tenantLabel, splitList(*sharedNamespaces))
+	query.ConfigureBackstage(*backstageEntityLabel)
+	query.ConfigureFOCUS(*focusBillingAccount)
+	models.SetServerlessPricing(*serverlessCPUPrice, *serverlessMemoryPrice)
+	models.SetLocalDiskPricing(*localDiskPrice)
+	models.SetInfraTagKeys(splitList(*infraTagKeys))
+	if err := models.SetRedaction(*hashedLabelKeys, *droppedLabelKeys, *redactionSalt); err != nil {
+		log.Fatal(err)
+	}
+	models.SetHugepagesPricing(*hugepagesPrice)
+	models.SetGPUPricing(*gpuPrice, splitList(*gpuResources))
+	models.SetBandwidthPricing(*bandwidthPrice)
+	query.SetEgressPricing(*egressPriceIntraZone, *egressPriceCrossZone, *egressPriceExternal)
+	models.SetCapacityTypeDiscounts(*spotDiscount, *reservedDiscount)
+	if err := emissions.Configure(*emissionsConfig); err != nil {
+		log.Fatal(err)
+	}
+	if err := extended.Configure(*extendedResourcePrices); err != nil {
+		log.Fatal(err)
+	}
+	if err := storage.Configure(*storageClassPrices); err != nil {
+		log.Fatal(err)
+	}
+	if err := models.SetBestEffortRequests(*bestEffortRequests, *bestEffortCPURequest, *bestEffortMemoryRequest); err != nil {
+		log.Fatal(err)
+	}
+	if err := overrides.Configure(*containerPriceOverrides); err != nil {
+		log.Fatal(err)
+	}
+	istio.Configure(*istioPrometheusURL, *telemetryTimeout)
+	linkerd.Configure(*linkerdPrometheusURL, *telemetryTimeout)
+	if err := hubble.Configure(*hubbleRelayAddress); err != nil {
+		log.Fatal(err)
+	}
+	energy.Configure(*powerPrometheusURL, *nodePowerQuery, *nodePowerLabel, *telemetryTimeout)
+	usage.Configure(*usagePrometheusURL, *telemetryTimeout)
+	exporter.Configure(*metricsRefreshInterval)
+	volume.Configure(*volumePrometheusURL, *telemetryTimeout)
+	if err := telemetry.SelectSources(strings.Split(*interactionSources, ",")); err != nil {
+		log.Fatal(err)
+	}
+	if err := admission.Configure(*admissionCostLimits, *admissionDeny); err != nil {
+		log.Fatal(err)
+	}
+	if err := query.ConfigureBudgets(*namespaceBudgets); err != nil {
+		log.Fatal(err)
+	}
+	if err := query.ConfigureFieldPolicy(splitList(*apiAllowedFields), splitList(*apiDeniedFields)); err != nil {
+		log.Fatal(err)
+	}
+	if *apiTokenAuth {
+		auth.Configure(conf.Kubeclient, splitList(*apiAdmins), splitList(*apiViewers), *apiTokenCacheTTL)
+		auth.SetScopedViewers(*apiScopedViewers)
+	}
+	admissionWebhookAddress, admissionWebhookCert, admissionWebhookKey = *admissionAddress, *admissionTLSCert, *admissionTLSKey
+	grpcAPIAddress, grpcAPICert, grpcAPIKey = *grpcAddress, *grpcTLSCert, *grpcTLSKey
+
+	notification.RegisterChannel(notification.NewWebhookChannel(*notificationTimeout))
+	if *teamsWebhookURL != "" {
+		notification.RegisterChannel(notification.NewTeamsChannel(*teamsWebhookURL, *notificationTimeout))
+	}
+	if *pagerDutyRoutingKey != "" {
+		notification.RegisterChannel(notification.NewPagerDutyChannel(*pagerDutyRoutingKey, *pageSeverity, *notificationTimeout))
+	}
+	if *opsgenieAPIKey != "" {
+		notification.RegisterChannel(notification.NewOpsgenieChannel(*opsgenieURL, *opsgenieAPIKey, *pageSeverity, *notificationTimeout))
+	}
+	if err := report.Configure(*reportConfig, *notificationTimeout); err != nil {
+		log.Fatal(err)
+	}
+	if err := cloudevents.Configure(*cloudEventsURL, *cloudEventsMode, *cloudEventsKafkaProxyURL, *cloudEventsKafkaTopic, *notificationTimeout); err != nil {
+		log.Fatal(err)
+	}
+	if cloudevents.IsConfigured() {
+		notification.RegisterChannel(cloudevents.NewChannel())
+	}
+	evaluationInterval = *alertEvaluationInterval
+	pricingRefreshInterval = *pricingRefresh
+	gcpPricingAPIKey = *gcpAPIKey
+	api.SetQuota(*quotaInterval)
+	if err := api.SetTrustedProxies(splitList(*trustedProxies)); err != nil {
+		log.Fatal(err)
+	}
 
 	// start dgraph and create login if not exists
+	dgraph.SetPoolSize(*dgraphPoolSize)
+	dgraph.SetRetry(*dgraphRetries, *dgraphRetryBackoff, *dgraphRetryMaxBackoff)
+	dgraph.SetCircuitBreaker(*dgraphBreakerThreshold, *dgraphBreakerCooldown)
+	dgraph.SetQueryTimeout(*dgraphQueryTimeout)
+	if *dgraphTLS || *dgraphTLSCACert != "" || *dgraphTLSCert != "" || *dgraphTLSKey != "" {
+		if err := dgraph.SetTLS(*dgraphTLSCACert, *dgraphTLSCert, *dgraphTLSKey, *dgraphTLSServerName); err != nil {
+			log.Fatal(err)
+		}
+	}
+	dgraph.SetACL(*dgraphUser, *dgraphPassword)
+	if err := dgraph.SetCluster(*clusterName); err != nil {
+		log.Fatal(err)
+	}
+	dgraph.SetMutationLogMaxSize(*mutationLogMaxSize)
+	mutationLogPath = *mutationLogFlag
+	if err := dgraph.SetMutationLog(mutationLogPath); err != nil {
+		log.Fatal(err)
+	}
+	status.SetMutationQueue(dgraph.QueuedMutations)
+	mutationReplayInterval = *mutationReplayIntervalFlag
 	dgraph.Start(*dgraphURL, *dgraphPort)
 	dgraph.StoreLogin()
+	dgraph.SetRetention(*retentionMonths, *podRetentionMonths)
+	dgraph.SetDeletedNamespaceRetention(*deletedNamespaceRetentionMonths)
+	dgraph.SetRetentionWindow(*retentionWindow)
+	retentionInterval = *retentionIntervalFlag
+	if retentionInterval <= 0 {
+		log.Errorf("retention interval must be positive: %v", retentionInterval)
+		retentionInterval = dgraph.DefaultRetentionInterval
+	}
+}
+
+// splitList splits a comma separated list and drops empty items
+func splitList(list string) []string {
+	items := []string{}
+	for _, item := range strings.Split(list, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
 }
 
 func main() {
 	go api.StartServer(conf)
+	if admissionWebhookAddress != "" {
+		go admission.StartServer(admissionWebhookAddress, admissionWebhookCert, admissionWebhookKey)
+	}
+	if grpcAPIAddress != "" {
+		go grpcapi.StartServer(grpcAPIAddress, grpcAPICert, grpcAPIKey)
+	}
+	if mutationLogPath != "" {
+		go startCronJobForReplayingMutations()
+	}
 	go startCronJobForPopulatingRateCard()
 	time.Sleep(time.Minute * 3)
 	go eventprocessor.ProcessEvents(&conf)
@@ -70,9 +366,117 @@ func main() {
 		go startInteractionsDiscovery()
 	}
 	go startCronJobForUpdatingCustomGroups()
+	go startCronJobForRetention()
+	go startCronJobForAlertEvaluation()
+	go startCronJobForBudgetEvaluation()
+	if energy.IsConfigured() {
+		go startCronJobForEnergyCollection()
+	}
+	if usage.IsConfigured() {
+		go startCronJobForPodUsageCollection()
+	}
+	if volume.IsConfigured() {
+		go startCronJobForVolumeUsageCollection()
+	}
+	if exporter.IsConfigured() {
+		go startCronJobForExportingCosts()
+	}
+	if *autoscalerEvents == "enable" {
+		go startCronJobForScaleUpCollection()
+	}
+	if *costSnapshots == "enable" {
+		go startCronJobForCostSnapshots()
+	}
+	if report.IsConfigured() {
+		go startCronJobForCostReports()
+	}
 	controller.Start(&conf)
 }
 
+// evaluates alert rules periodically
+func startCronJobForAlertEvaluation() {
+	c := cron.New()
+	err := c.AddFunc("@every "+evaluationInterval.String(), func() { alerting.EvaluateRules(conf.Alertruleclient) })
+	if err != nil {
+		log.Error(err)
+	}
+	c.Start()
+}
+
+// evaluates spend of cost budgets periodically
+func startCronJobForBudgetEvaluation() {
+	c := cron.New()
+	err := c.AddFunc("@every "+evaluationInterval.String(), func() { costbudget.EvaluateBudgets(conf.Costbudgetclient, conf.Kubeclient) })
+	if err != nil {
+		log.Error(err)
+	}
+	c.Start()
+}
+
+// collects energy of nodes and pods every hour
+func startCronJobForEnergyCollection() {
+	c := cron.New()
+	err := c.AddFunc("@every 1h", func() { energy.CollectAndStoreEnergy(conf.Kubeclient) })
+	if err != nil {
+		log.Error(err)
+	}
+	c.Start()
+}
+
+// collects cpu and memory used by pods every hour
+func startCronJobForPodUsageCollection() {
+	c := cron.New()
+	err := c.AddFunc("@every 1h", usage.CollectAndStorePodUsage)
+	if err != nil {
+		log.Error(err)
+	}
+	c.Start()
+}
+
+// collects space used by volumes of pvcs every hour
+func startCronJobForVolumeUsageCollection() {
+	c := cron.New()
+	err := c.AddFunc("@every 1h", volume.CollectAndStoreVolumeUsage)
+	if err != nil {
+		log.Error(err)
+	}
+	c.Start()
+}
+
+// stores daily cost snapshots of today and of yesterday once it is complete every hour
+func startCronJobForCostSnapshots() {
+	c := cron.New()
+	err := c.AddFunc("@every 1h", query.StoreDailyCostSnapshots)
+	if err != nil {
+		log.Error(err)
+	}
+	c.Start()
+}
+
+// refreshes costs exported as prometheus metrics
+func startCronJobForExportingCosts() {
+	exporter.Refresh()
+
+	c := cron.New()
+	err := c.AddFunc("@every "+exporter.GetRefreshInterval().String(), exporter.Refresh)
+	if err != nil {
+		log.Error(err)
+	}
+	c.Start()
+}
+
+// collects scale ups from events of pods every 5 min, events are kept for an hour by default
+func startCronJobForScaleUpCollection() {
+	autoscaler.CollectAndStoreScaleUps(conf.Kubeclient)
+
+	c := cron.New()
+	err := c.AddFunc("@every 0h5m", func() { autoscaler.CollectAndStoreScaleUps(conf.Kubeclient) })
+	if err != nil {
+		log.Error(err)
+	}
+	c.Start()
+}
+
 // starts first discovery after 5 min of controller starting. Next runs will occur in every 59 min
 func startInteractionsDiscovery() {
 	time.Sleep(time.Minute * 5)
@@ -83,7 +487,25 @@ func startInteractionsDiscovery() {
 	if err != nil {
 		log.Error(err)
 	}
-	err = c.AddFunc("@daily", dgraph.RemoveResourcesInactive)
+	c.Start()
+}
+
+// replays mutations queued in the mutation log, ex: by a previous run of the controller, at start and periodically
+// in case they were queued after transient errors which did not open the circuit breaker
+func startCronJobForReplayingMutations() {
+	dgraph.ReplayMutations()
+	c := cron.New()
+	err := c.AddFunc("@every "+mutationReplayInterval.String(), func() { dgraph.ReplayMutations() })
+	if err != nil {
+		log.Error(err)
+	}
+	c.Start()
+}
+
+// removes resources older than the retention period periodically
+func startCronJobForRetention() {
+	c := cron.New()
+	err := c.AddFunc("@every "+retentionInterval.String(), dgraph.RemoveResourcesInactive)
 	if err != nil {
 		log.Error(err)
 	}
@@ -91,12 +513,15 @@ func startInteractionsDiscovery() {
 }
 
 func runDiscovery() {
-	processor.ProcessPodInteractions(conf)
+	if telemetry.IsCaptureSelected() {
+		processor.ProcessPodInteractions(conf)
+	}
+	telemetry.CollectAndStoreInteractions(conf.Kubeclient)
 	processor.ProcessServiceInteractions(conf)
 }
 
 func startCronJobForUpdatingCustomGroups() {
-	query.ComputeClusterAllocationAndCapacity()
+	query.ComputeClusterAllocationAndCapacity(dgraph.WithOwnCluster(context.Background()))
 	runGroupUpdate()
 
 	c := cron.New()
@@ -104,7 +529,7 @@ func startCronJobForUpdatingCustomGroups() {
 	if err != nil {
 		log.Error(err)
 	}
-	err = c.AddFunc("@every 0h5m", query.ComputeClusterAllocationAndCapacity)
+	err = c.AddFunc("@every 0h5m", func() { query.ComputeClusterAllocationAndCapacity(dgraph.WithOwnCluster(context.Background())) })
 	if err != nil {
 		log.Error(err)
 	}
@@ -116,14 +541,24 @@ func runGroupUpdate() {
 }
 
 func startCronJobForPopulatingRateCard() {
-	cloud := &pricing.Cloud{Kubeclient: conf.Kubeclient}
+	cloud := &pricing.Cloud{Kubeclient: conf.Kubeclient, GCPAPIKey: gcpPricingAPIKey}
 	// find cloud provider and region
-	cloud.CloudProvider, cloud.Region = pricing.GetClusterProviderAndRegion()
+	cloud.CloudProvider, cloud.Region = pricing.GetClusterProviderAndRegion(conf.Kubeclient)
 	cloud.PopulateRateCard()
 
 	c := cron.New()
 
-	err := c.AddFunc("@every 168h", cloud.PopulateRateCard)
+	err := c.AddFunc("@every "+pricingRefreshInterval.String(), cloud.PopulateRateCard)
+	if err != nil {
+		log.Error(err)
+	}
+	c.Start()
+}
+
+// delivers cost reports of the last week or month to their destinations
+func startCronJobForCostReports() {
+	c := cron.New()
+	err := c.AddFunc(report.GetCronSpec(), report.Deliver)
 	if err != nil {
 		log.Error(err)
 	}
@@ -0,0 +1,102 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	log "github.com/Sirupsen/logrus"
+
+	"github.com/vmware/purser/pkg/controller/dgraph"
+	"github.com/vmware/purser/pkg/devtools"
+	"github.com/vmware/purser/pkg/utils"
+)
+
+const usage = `Usage:
+  purser-devtools seed [options]
+  purser-devtools benchmark [options]
+
+seed generates a synthetic cluster(nodes, namespaces, deployments, pods, services and interactions) into Dgraph
+for development of Purser UI and performance tests without a live cluster.
+
+benchmark replays a mix of query package calls against a Dgraph seeded with the same seed options and reports
+latency percentiles(milliseconds) and throughput.
+
+options:
+`
+
+func main() {
+	if len(os.Args) < 2 || (os.Args[1] != "seed" && os.Args[1] != "benchmark") {
+		fmt.Print(usage)
+		os.Exit(1)
+	}
+
+	seedConfig := devtools.DefaultSeedConfig
+	benchmarkConfig := devtools.DefaultBenchmarkConfig
+	flags := flag.NewFlagSet(os.Args[1], flag.ExitOnError)
+	logLevel := flags.String("log", "info", "set log level as info or debug")
+	dgraphURL := flags.String("dgraphURL", "localhost", "dgraph zero url")
+	dgraphPort := flags.String("dgraphPort", "9080", "dgraph zero port")
+	flags.IntVar(&seedConfig.Nodes, "nodes", seedConfig.Nodes, "number of nodes")
+	flags.IntVar(&seedConfig.Namespaces, "namespaces", seedConfig.Namespaces, "number of namespaces")
+	flags.IntVar(&seedConfig.DeploymentsPerNamespace, "deployments", seedConfig.DeploymentsPerNamespace, "number of deployments in each namespace")
+	flags.IntVar(&seedConfig.Replicas, "replicas", seedConfig.Replicas, "number of live pods of each deployment")
+	flags.Float64Var(&seedConfig.Churn, "churn", seedConfig.Churn, "fraction of pods of each deployment terminated and replaced during the history")
+	flags.IntVar(&seedConfig.Interactions, "interactions", seedConfig.Interactions, "number of pods of other deployments each live pod sends requests to")
+	flags.DurationVar(&seedConfig.History, "history", seedConfig.History, "age of the oldest resources")
+	flags.Int64Var(&seedConfig.RandomSeed, "seed", seedConfig.RandomSeed, "seed of the random generator, the same seed generates the same cluster")
+	flags.IntVar(&benchmarkConfig.Requests, "requests", benchmarkConfig.Requests, "benchmark: number of requests")
+	flags.IntVar(&benchmarkConfig.Concurrency, "concurrency", benchmarkConfig.Concurrency, "benchmark: number of concurrent requests")
+	mix := flags.String("mix", "", "benchmark: comma separated operation=weight pairs, ex: podMetrics=3,nodeMetrics=1 (default mix of all operations)")
+	flags.Usage = func() {
+		if _, err := fmt.Fprint(flags.Output(), usage); err != nil {
+			log.Fatal(err)
+		}
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(os.Args[2:]); err != nil {
+		log.Fatal(err)
+	}
+	benchmarkConfig.Seed = seedConfig
+	if *mix != "" {
+		weights, err := devtools.ParseMix(*mix)
+		if err != nil {
+			log.Fatal(err)
+		}
+		benchmarkConfig.Mix = weights
+	}
+
+	utils.InitializeLogger(*logLevel)
+	dgraph.Start(*dgraphURL, *dgraphPort)
+	defer dgraph.Close()
+
+	var report interface{}
+	if os.Args[1] == "seed" {
+		report = devtools.Seed(seedConfig)
+	} else {
+		report = devtools.Benchmark(benchmarkConfig)
+	}
+	output, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(string(output))
+}
@@ -0,0 +1,62 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"flag"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+
+	"github.com/vmware/purser/pkg/client"
+	installation_client "github.com/vmware/purser/pkg/client/clientset/typed/installation/v1"
+	"github.com/vmware/purser/pkg/operator"
+	"github.com/vmware/purser/pkg/utils"
+)
+
+// InClusterConfigPath should be empty to get client and config for InCluster environment.
+const InClusterConfigPath = ""
+
+func main() {
+	logLevel := flag.String("log", "info", "set log level as info or debug")
+	kubeconfig := flag.String("kubeconfig", InClusterConfigPath, "path to the kubeconfig file")
+	namespace := flag.String("namespace", "purser", "namespace of PurserInstallation resources and managed objects")
+	resyncPeriod := flag.Duration("resyncPeriod", 30*time.Second, "period after which every PurserInstallation is reconciled again")
+	flag.Parse()
+
+	utils.InitializeLogger(*logLevel)
+	kubeConfig, err := utils.GetKubeconfig(*kubeconfig)
+	if err != nil {
+		log.Fatal(err)
+	}
+	kubeClient := utils.GetKubeclient(kubeConfig)
+	clientset, clusterConfig := client.GetAPIExtensionClient(*kubeconfig)
+	installationClient := installation_client.NewPurserInstallationClient(clientset, clusterConfig, *namespace)
+
+	stopCh := make(chan struct{})
+	go operator.NewOperator(kubeClient, installationClient, *resyncPeriod).Run(stopCh)
+
+	sigterm := make(chan os.Signal, 1)
+	signal.Notify(sigterm, syscall.SIGTERM)
+	signal.Notify(sigterm, syscall.SIGINT)
+	<-sigterm
+	close(stopCh)
+}
@@ -39,18 +39,25 @@ var (
 	groupClient *groups_client_v1.GroupClient
 
 	// Variables used for cmd interface
-	kubeconfig string
-	info       string
-	version    string
+	kubeconfig     string
+	info           string
+	version        string
+	purserURL      string
+	purserUser     string
+	purserPassword string
+	filename       string
 
 	description   = fmt.Sprintf("Purser gives cost insights of kubernetes deployments.\n\n")
 	usage         = fmt.Sprintf("Usage:\n  kubectl plugin purser [options] <command> <args>\n\n")
-	supportedCmds = fmt.Sprintf("The supported commands are:\n  get  Get resource information.\n  set  Set resource information.\n\n")
+	supportedCmds = fmt.Sprintf("The supported commands are:\n  get       Get resource information.\n  set       Set resource information.\n  admin     Run operational tasks of purser controller.\n  estimate  Estimate monthly cost of a manifest before deploying it.\n\n")
 
 	optionHelp       = fmt.Sprintf("\n  --info            Show more details about the plugin.")
 	optionKubeConfig = fmt.Sprintf("\n  --kubeconfig      Absolute path for the kube config file.")
 	optionVersion    = fmt.Sprintf("\n  --version         Show plugin version.")
-	options          = fmt.Sprintf("options:%s%s%s\n\n", optionHelp, optionKubeConfig, optionVersion)
+	optionPurserURL  = fmt.Sprintf("\n  --purserURL       URL of purser controller API, needed for namespace and group costs.")
+	optionPurserUser = fmt.Sprintf("\n  --purserUser      Username and --purserPassword password of purser controller API.")
+	optionFilename   = fmt.Sprintf("\n  -f, --filename    Manifest file of workloads to estimate cost of.")
+	options          = fmt.Sprintf("options:%s%s%s%s%s%s\n\n", optionHelp, optionKubeConfig, optionVersion, optionPurserURL, optionPurserUser, optionFilename)
 
 	kubecltOption = fmt.Sprintf("\nUse \"kubectl options\" for a list of global command-line options (applies to all commands).\n\n")
 )
@@ -60,6 +67,10 @@ func init() {
 
 	flag.StringVar(&info, "info", os.Getenv("KUBECTL_PLUGINS_LOCAL_FLAG_INFO"), "Show help documentation")
 	flag.StringVar(&version, "version", os.Getenv("KUBECTL_PLUGINS_LOCAL_FLAG_VERSION"), "Show version number")
+	flag.StringVar(&purserURL, "purserURL", os.Getenv("KUBECTL_PLUGINS_LOCAL_FLAG_PURSERURL"), "URL of purser controller API")
+	flag.StringVar(&purserUser, "purserUser", os.Getenv("KUBECTL_PLUGINS_LOCAL_FLAG_PURSERUSER"), "Username of purser controller API")
+	flag.StringVar(&purserPassword, "purserPassword", os.Getenv("KUBECTL_PLUGINS_LOCAL_FLAG_PURSERPASSWORD"), "Password of purser controller API")
+	flag.StringVar(&filename, "filename", os.Getenv("KUBECTL_PLUGINS_LOCAL_FLAG_FILENAME"), "Manifest file of workloads")
 
 	flag.Usage = func() {
 		_, err := fmt.Fprint(flag.CommandLine.Output(), description)
@@ -108,13 +119,25 @@ func init() {
 	}
 	plugin.ProvideClientSetInstance(utils.GetKubeclient(config))
 
-	client, clusterConfig := client.GetAPIExtensionClient(kubeconfig)
-	groupClient = groups_client_v1.NewGroupClient(client, clusterConfig)
+	extClient, clusterConfig := client.GetAPIExtensionClient(kubeconfig)
+	groupClient = groups_client_v1.NewGroupClient(extClient, clusterConfig)
+
+	if purserURL != "" {
+		apiClient := client.NewAPIClient(purserURL, nil)
+		if err := apiClient.Login(purserUser, purserPassword); err != nil {
+			log.Errorf("unable to login to purser controller: %v", err)
+		}
+		plugin.ProvideAPIClientInstance(apiClient)
+	}
 }
 
 func main() {
 	inputs := os.Args[2:] // index 1 is empty
-	if len(inputs) == 4 && inputs[0] == Get {
+	if len(inputs) >= 2 && inputs[0] == Admin {
+		runAdminTask(inputs)
+	} else if len(inputs) == 1 && inputs[0] == Estimate && filename != "" {
+		plugin.EstimateManifestCost(filename)
+	} else if len(inputs) == 4 && inputs[0] == Get {
 		computeMetricInsight(inputs)
 	} else if len(inputs) == 2 {
 		computeStats(inputs)
@@ -140,6 +163,10 @@ func computeCost(inputs []string) {
 		plugin.GetPodCost(inputs[3])
 	case Node:
 		plugin.GetAllNodesCost()
+	case Namespace:
+		plugin.GetNamespaceCost(inputs[3])
+	case Group:
+		plugin.GetGroupCost(inputs[3])
 	default:
 		printHelp()
 	}
@@ -176,6 +203,27 @@ func fetchResource(inputs []string) {
 	}
 }
 
+func runAdminTask(inputs []string) {
+	switch inputs[1] {
+	case Retention:
+		plugin.RunRetention()
+	case Reindex:
+		plugin.Reindex(inputs[2:])
+	case Backup:
+		if len(inputs) != 3 {
+			printHelp()
+			return
+		}
+		plugin.Backup(inputs[2])
+	case SchemaMigrate:
+		plugin.MigrateSchema()
+	case VerifyConsistency:
+		plugin.VerifyConsistency()
+	default:
+		printHelp()
+	}
+}
+
 func createGroupNameFromLabel(input string) string {
 	inp := strings.Split(input, "=")
 	key, val := inp[0], inp[1]
@@ -246,9 +294,17 @@ func printHelp() {
 	fmt.Println(pluginExt + "get cost label <key=val>")
 	fmt.Println(pluginExt + "get cost pod <pod name>")
 	fmt.Println(pluginExt + "get cost node all")
+	fmt.Println(pluginExt + "--purserURL=<url> get cost namespace <namespace name>")
+	fmt.Println(pluginExt + "--purserURL=<url> get cost group <group name>")
 	fmt.Println(pluginExt + "set user-costs")
 	fmt.Println(pluginExt + "get user-costs")
 	fmt.Println(pluginExt + "get savings")
+	fmt.Println(pluginExt + "--purserURL=<url> estimate -f <manifest file>")
+	fmt.Println(pluginExt + "--purserURL=<url> admin retention")
+	fmt.Println(pluginExt + "--purserURL=<url> admin reindex [<predicate>...]")
+	fmt.Println(pluginExt + "--purserURL=<url> admin backup <file>")
+	fmt.Println(pluginExt + "--purserURL=<url> admin schema-migrate")
+	fmt.Println(pluginExt + "--purserURL=<url> admin verify-consistency")
 }
 
 func logError(err error) {
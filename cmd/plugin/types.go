@@ -19,8 +19,10 @@ package main
 
 // These are possible actions for resources
 const (
-	Get = "get"
-	Set = "set"
+	Get      = "get"
+	Set      = "set"
+	Admin    = "admin"
+	Estimate = "estimate"
 )
 
 // These are kubernetes components
@@ -37,3 +39,12 @@ const (
 	Cost      = "cost"
 	Resources = "resources"
 )
+
+// These are operational tasks of admin command
+const (
+	Retention         = "retention"
+	Reindex           = "reindex"
+	Backup            = "backup"
+	SchemaMigrate     = "schema-migrate"
+	VerifyConsistency = "verify-consistency"
+)
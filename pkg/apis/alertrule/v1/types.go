@@ -0,0 +1,86 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1
+
+import meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+// CRD AlertRule attributes
+const (
+	AlertRulePlural   string = "alertrules"
+	AlertRuleGroup    string = "vmware.purser.com"
+	AlertRuleVersion  string = "v1"
+	AlertRuleFullName string = AlertRulePlural + "." + AlertRuleGroup
+)
+
+// Metrics on which alert rules can be defined
+const (
+	CostMetric             = "cost"
+	ProjectedCostMetric    = "projectedCost"
+	CarbonMetric           = "carbon"
+	CPUEfficiencyMetric    = "cpuEfficiency"
+	MemoryEfficiencyMetric = "memoryEfficiency"
+	IdleCostMetric         = "idleCost"
+)
+
+// Scopes of alert rules
+const (
+	ClusterScope   = "cluster"
+	NamespaceScope = "namespace"
+	GroupScope     = "group"
+)
+
+// Severities of alert rules
+const (
+	SeverityInfo     = "info"
+	SeverityWarning  = "warning"
+	SeverityCritical = "critical"
+)
+
+// AlertRule information
+type AlertRule struct {
+	meta_v1.TypeMeta   `json:",inline"`
+	meta_v1.ObjectMeta `json:"metadata"`
+	Spec               AlertRuleSpec   `json:"spec"`
+	Status             AlertRuleStatus `json:"status,omitempty"`
+}
+
+// AlertRuleSpec definition details. The rule fires when the metric of the resource(Scope, Name) compared with
+// Operator(>, >=, < or <=) against Threshold holds continuously for Duration(ex: 30m).
+type AlertRuleSpec struct {
+	Metric    string  `json:"metric"`
+	Scope     string  `json:"scope,omitempty"`
+	Name      string  `json:"name,omitempty"`
+	Operator  string  `json:"operator,omitempty"`
+	Threshold float64 `json:"threshold"`
+	Duration  string  `json:"duration,omitempty"`
+	Severity  string  `json:"severity,omitempty"`
+	Summary   string  `json:"summary,omitempty"`
+}
+
+// AlertRuleStatus definition
+type AlertRuleStatus struct {
+	State   string `json:"state,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// AlertRuleList type
+type AlertRuleList struct {
+	meta_v1.TypeMeta `json:",inline"`
+	meta_v1.ListMeta `json:"metadata"`
+	Items            []AlertRule `json:"items"`
+}
@@ -0,0 +1,53 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1
+
+import (
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// SchemeBuilder parameters
+var (
+	SchemeBuilder = runtime.NewSchemeBuilder(AddKnownTypes)
+	AddToScheme   = SchemeBuilder.AddToScheme
+)
+
+// CostBudgetGroupVersion is group version used to register these objects
+var CostBudgetGroupVersion = schema.GroupVersion{Group: CostBudgetGroup, Version: CostBudgetVersion}
+
+// Kind takes an unqualified kind and returns a Group qualified GroupKind
+func Kind(kind string) schema.GroupKind {
+	return CostBudgetGroupVersion.WithKind(kind).GroupKind()
+}
+
+// Resource takes an unqualified resource and returns a Group qualified GroupResource
+func Resource(resource string) schema.GroupResource {
+	return CostBudgetGroupVersion.WithResource(resource).GroupResource()
+}
+
+// AddKnownTypes ...
+func AddKnownTypes(scheme *runtime.Scheme) error {
+	scheme.AddKnownTypes(CostBudgetGroupVersion,
+		&CostBudget{},
+		&CostBudgetList{},
+	)
+	meta_v1.AddToGroupVersion(scheme, CostBudgetGroupVersion)
+	return nil
+}
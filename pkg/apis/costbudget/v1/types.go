@@ -0,0 +1,60 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1
+
+import meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+// CRD CostBudget attributes
+const (
+	CostBudgetPlural   string = "costbudgets"
+	CostBudgetGroup    string = "vmware.purser.com"
+	CostBudgetVersion  string = "v1"
+	CostBudgetFullName string = CostBudgetPlural + "." + CostBudgetGroup
+)
+
+// CostBudget information
+type CostBudget struct {
+	meta_v1.TypeMeta   `json:",inline"`
+	meta_v1.ObjectMeta `json:"metadata"`
+	Spec               CostBudgetSpec   `json:"spec"`
+	Status             CostBudgetStatus `json:"status,omitempty"`
+}
+
+// CostBudgetSpec definition details. The budget is spent by pods of Namespace having all labels of Selector, pods of
+// all namespaces if Namespace is empty. Limit is the monthly budget in dollars.
+type CostBudgetSpec struct {
+	Namespace string            `json:"namespace,omitempty"`
+	Selector  map[string]string `json:"selector,omitempty"`
+	Limit     float64           `json:"limit"`
+}
+
+// CostBudgetStatus definition. Threshold is the highest percent of the limit(80 or 100) crossed in Month(ex: 2018-03).
+type CostBudgetStatus struct {
+	Month           string  `json:"month,omitempty"`
+	MonthToDateCost float64 `json:"monthToDateCost"`
+	PercentUsed     float64 `json:"percentUsed"`
+	Threshold       float64 `json:"threshold,omitempty"`
+	Message         string  `json:"message,omitempty"`
+}
+
+// CostBudgetList type
+type CostBudgetList struct {
+	meta_v1.TypeMeta `json:",inline"`
+	meta_v1.ListMeta `json:"metadata"`
+	Items            []CostBudget `json:"items"`
+}
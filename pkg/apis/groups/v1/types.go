@@ -65,12 +65,13 @@ type GroupMetrics struct {
 	StorageClaim    float64
 }
 
-// Cost details
+// Cost details, Carbon is the emission in gCO2e for the same period
 type Cost struct {
 	TotalCost   float64
 	CPUCost     float64
 	MemoryCost  float64
 	StorageCost float64
+	Carbon      float64
 }
 
 // GroupList is the list of Group resources
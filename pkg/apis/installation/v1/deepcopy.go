@@ -0,0 +1,82 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1
+
+import "k8s.io/apimachinery/pkg/runtime"
+
+// DeepCopyInto copies all properties of this object into another object of the
+// same type that is provided as a pointer.
+func (in *PurserInstallation) DeepCopyInto(out *PurserInstallation) {
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	out.Status = in.Status
+}
+
+// DeepCopyInto copies all properties of this spec into another spec
+func (in *PurserInstallationSpec) DeepCopyInto(out *PurserInstallationSpec) {
+	*out = *in
+	if in.UI.Replicas != nil {
+		replicas := *in.UI.Replicas
+		out.UI.Replicas = &replicas
+	}
+	if in.Retention.ResourceMonths != nil {
+		months := *in.Retention.ResourceMonths
+		out.Retention.ResourceMonths = &months
+	}
+	if in.Retention.PodMonths != nil {
+		months := *in.Retention.PodMonths
+		out.Retention.PodMonths = &months
+	}
+	if in.Retention.DeletedNamespaceMonths != nil {
+		months := *in.Retention.DeletedNamespaceMonths
+		out.Retention.DeletedNamespaceMonths = &months
+	}
+	if in.ExtraArgs != nil {
+		out.ExtraArgs = make([]string, len(in.ExtraArgs))
+		copy(out.ExtraArgs, in.ExtraArgs)
+	}
+	if in.NodeSelector != nil {
+		out.NodeSelector = make(map[string]string, len(in.NodeSelector))
+		for key, value := range in.NodeSelector {
+			out.NodeSelector[key] = value
+		}
+	}
+}
+
+// DeepCopyObject returns a generically typed copy of an object
+func (in *PurserInstallation) DeepCopyObject() runtime.Object {
+	out := PurserInstallation{}
+	in.DeepCopyInto(&out)
+	return &out
+}
+
+// DeepCopyObject returns a generically typed copy of an object
+func (in *PurserInstallationList) DeepCopyObject() runtime.Object {
+	out := PurserInstallationList{}
+	out.TypeMeta = in.TypeMeta
+	out.ListMeta = in.ListMeta
+
+	if in.Items != nil {
+		out.Items = make([]PurserInstallation, len(in.Items))
+		for i := range in.Items {
+			in.Items[i].DeepCopyInto(&out.Items[i])
+		}
+	}
+	return &out
+}
@@ -0,0 +1,99 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1
+
+import meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+// CRD PurserInstallation attributes
+const (
+	InstallationPlural   string = "purserinstallations"
+	InstallationGroup    string = "vmware.purser.com"
+	InstallationVersion  string = "v1"
+	InstallationFullName string = InstallationPlural + "." + InstallationGroup
+)
+
+// PurserInstallation states
+const (
+	StateReconciling = "Reconciling"
+	StateReady       = "Ready"
+	StateFailed      = "Failed"
+)
+
+// PurserInstallation describes the desired installation of Purser managed by purser operator
+type PurserInstallation struct {
+	meta_v1.TypeMeta   `json:",inline"`
+	meta_v1.ObjectMeta `json:"metadata"`
+	Spec               PurserInstallationSpec   `json:"spec"`
+	Status             PurserInstallationStatus `json:"status,omitempty"`
+}
+
+// PurserInstallationSpec is the spec for the PurserInstallation resource
+type PurserInstallationSpec struct {
+	// Version is the image tag of controller and UI, changing it upgrades the installation
+	Version      string            `json:"version"`
+	Controller   ControllerSpec    `json:"controller,omitempty"`
+	UI           UISpec            `json:"ui,omitempty"`
+	Dgraph       DgraphSpec        `json:"dgraph,omitempty"`
+	Retention    RetentionSpec     `json:"retention,omitempty"`
+	ExtraArgs    []string          `json:"extraArgs,omitempty"`
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+}
+
+// ControllerSpec is the spec for purser controller deployment
+type ControllerSpec struct {
+	Image        string `json:"image,omitempty"`
+	LogLevel     string `json:"logLevel,omitempty"`
+	Interactions bool   `json:"interactions,omitempty"`
+}
+
+// UISpec is the spec for purser UI deployment
+type UISpec struct {
+	Enabled  bool   `json:"enabled,omitempty"`
+	Image    string `json:"image,omitempty"`
+	Replicas *int32 `json:"replicas,omitempty"`
+}
+
+// DgraphSpec has the connection details of dgraph. If SecretName is given, url and port are read from
+// keys `url` and `port` of the secret instead.
+type DgraphSpec struct {
+	URL        string `json:"url,omitempty"`
+	Port       string `json:"port,omitempty"`
+	SecretName string `json:"secretName,omitempty"`
+}
+
+// RetentionSpec has number of months(before the current month) for which deleted resources are retained
+type RetentionSpec struct {
+	ResourceMonths         *int `json:"resourceMonths,omitempty"`
+	PodMonths              *int `json:"podMonths,omitempty"`
+	DeletedNamespaceMonths *int `json:"deletedNamespaceMonths,omitempty"`
+}
+
+// PurserInstallationStatus is the status for the PurserInstallation resource
+type PurserInstallationStatus struct {
+	State              string `json:"state,omitempty"`
+	Message            string `json:"message,omitempty"`
+	InstalledVersion   string `json:"installedVersion,omitempty"`
+	ObservedGeneration int64  `json:"observedGeneration,omitempty"`
+}
+
+// PurserInstallationList type
+type PurserInstallationList struct {
+	meta_v1.TypeMeta `json:",inline"`
+	meta_v1.ListMeta `json:"metadata"`
+	Items            []PurserInstallation `json:"items"`
+}
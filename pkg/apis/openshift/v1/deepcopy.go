@@ -0,0 +1,156 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1
+
+import "k8s.io/apimachinery/pkg/runtime"
+
+// DeepCopyInto copies all properties of this object into another object of the
+// same type that is provided as a pointer.
+func (in *DeploymentConfig) DeepCopyInto(out *DeploymentConfig) {
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	out.Status = in.Status
+}
+
+// DeepCopyInto copies all properties of this spec into another spec
+func (in *DeploymentConfigSpec) DeepCopyInto(out *DeploymentConfigSpec) {
+	*out = *in
+	if in.Selector != nil {
+		out.Selector = make(map[string]string, len(in.Selector))
+		for key, value := range in.Selector {
+			out.Selector[key] = value
+		}
+	}
+	if in.Triggers != nil {
+		out.Triggers = make([]DeploymentTriggerPolicy, len(in.Triggers))
+		for i, trigger := range in.Triggers {
+			out.Triggers[i] = trigger
+			if trigger.ImageChangeParams != nil {
+				params := *trigger.ImageChangeParams
+				if trigger.ImageChangeParams.ContainerNames != nil {
+					params.ContainerNames = make([]string, len(trigger.ImageChangeParams.ContainerNames))
+					copy(params.ContainerNames, trigger.ImageChangeParams.ContainerNames)
+				}
+				out.Triggers[i].ImageChangeParams = &params
+			}
+		}
+	}
+}
+
+// DeepCopyObject returns a generically typed copy of an object
+func (in *DeploymentConfig) DeepCopyObject() runtime.Object {
+	out := DeploymentConfig{}
+	in.DeepCopyInto(&out)
+	return &out
+}
+
+// DeepCopyObject returns a generically typed copy of an object
+func (in *DeploymentConfigList) DeepCopyObject() runtime.Object {
+	out := DeploymentConfigList{}
+	out.TypeMeta = in.TypeMeta
+	out.ListMeta = in.ListMeta
+
+	if in.Items != nil {
+		out.Items = make([]DeploymentConfig, len(in.Items))
+		for i := range in.Items {
+			in.Items[i].DeepCopyInto(&out.Items[i])
+		}
+	}
+	return &out
+}
+
+// DeepCopyInto copies all properties of this object into another object of the
+// same type that is provided as a pointer.
+func (in *Route) DeepCopyInto(out *Route) {
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	out.Spec.To = copyRouteTargetReference(in.Spec.To)
+	if in.Spec.AlternateBackends != nil {
+		out.Spec.AlternateBackends = make([]RouteTargetReference, len(in.Spec.AlternateBackends))
+		for i, backend := range in.Spec.AlternateBackends {
+			out.Spec.AlternateBackends[i] = copyRouteTargetReference(backend)
+		}
+	}
+}
+
+func copyRouteTargetReference(in RouteTargetReference) RouteTargetReference {
+	out := in
+	if in.Weight != nil {
+		weight := *in.Weight
+		out.Weight = &weight
+	}
+	return out
+}
+
+// DeepCopyObject returns a generically typed copy of an object
+func (in *Route) DeepCopyObject() runtime.Object {
+	out := Route{}
+	in.DeepCopyInto(&out)
+	return &out
+}
+
+// DeepCopyObject returns a generically typed copy of an object
+func (in *RouteList) DeepCopyObject() runtime.Object {
+	out := RouteList{}
+	out.TypeMeta = in.TypeMeta
+	out.ListMeta = in.ListMeta
+
+	if in.Items != nil {
+		out.Items = make([]Route, len(in.Items))
+		for i := range in.Items {
+			in.Items[i].DeepCopyInto(&out.Items[i])
+		}
+	}
+	return &out
+}
+
+// DeepCopyInto copies all properties of this object into another object of the
+// same type that is provided as a pointer.
+func (in *ImageStream) DeepCopyInto(out *ImageStream) {
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Status = in.Status
+	if in.Status.Tags != nil {
+		out.Status.Tags = make([]NamedTagEventList, len(in.Status.Tags))
+		copy(out.Status.Tags, in.Status.Tags)
+	}
+}
+
+// DeepCopyObject returns a generically typed copy of an object
+func (in *ImageStream) DeepCopyObject() runtime.Object {
+	out := ImageStream{}
+	in.DeepCopyInto(&out)
+	return &out
+}
+
+// DeepCopyObject returns a generically typed copy of an object
+func (in *ImageStreamList) DeepCopyObject() runtime.Object {
+	out := ImageStreamList{}
+	out.TypeMeta = in.TypeMeta
+	out.ListMeta = in.ListMeta
+
+	if in.Items != nil {
+		out.Items = make([]ImageStream, len(in.Items))
+		for i := range in.Items {
+			in.Items[i].DeepCopyInto(&out.Items[i])
+		}
+	}
+	return &out
+}
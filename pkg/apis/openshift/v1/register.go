@@ -0,0 +1,73 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1
+
+import (
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// OpenShift API groups
+const (
+	AppsGroup  string = "apps.openshift.io"
+	RouteGroup string = "route.openshift.io"
+	ImageGroup string = "image.openshift.io"
+	Version    string = "v1"
+
+	DeploymentConfigPlural string = "deploymentconfigs"
+	RoutePlural            string = "routes"
+	ImageStreamPlural      string = "imagestreams"
+)
+
+// Group versions used to register OpenShift objects
+var (
+	AppsGroupVersion  = schema.GroupVersion{Group: AppsGroup, Version: Version}
+	RouteGroupVersion = schema.GroupVersion{Group: RouteGroup, Version: Version}
+	ImageGroupVersion = schema.GroupVersion{Group: ImageGroup, Version: Version}
+)
+
+// AddAppsKnownTypes registers DeploymentConfig types
+func AddAppsKnownTypes(scheme *runtime.Scheme) error {
+	scheme.AddKnownTypes(AppsGroupVersion,
+		&DeploymentConfig{},
+		&DeploymentConfigList{},
+	)
+	meta_v1.AddToGroupVersion(scheme, AppsGroupVersion)
+	return nil
+}
+
+// AddRouteKnownTypes registers Route types
+func AddRouteKnownTypes(scheme *runtime.Scheme) error {
+	scheme.AddKnownTypes(RouteGroupVersion,
+		&Route{},
+		&RouteList{},
+	)
+	meta_v1.AddToGroupVersion(scheme, RouteGroupVersion)
+	return nil
+}
+
+// AddImageKnownTypes registers ImageStream types
+func AddImageKnownTypes(scheme *runtime.Scheme) error {
+	scheme.AddKnownTypes(ImageGroupVersion,
+		&ImageStream{},
+		&ImageStreamList{},
+	)
+	meta_v1.AddToGroupVersion(scheme, ImageGroupVersion)
+	return nil
+}
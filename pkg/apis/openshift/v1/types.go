@@ -0,0 +1,125 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1
+
+import (
+	api_v1 "k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Annotations and labels set by OpenShift on replication controllers and pods of a deployment config
+const (
+	DeploymentConfigAnnotation = "openshift.io/deployment-config.name"
+	DeploymentConfigLabel      = "deploymentconfig"
+)
+
+// DeploymentTriggerTypeImageChange is the trigger type which redeploys on change of an image stream tag
+const DeploymentTriggerTypeImageChange = "ImageChange"
+
+// DeploymentConfig describes an OpenShift deployment config
+type DeploymentConfig struct {
+	meta_v1.TypeMeta   `json:",inline"`
+	meta_v1.ObjectMeta `json:"metadata"`
+	Spec               DeploymentConfigSpec   `json:"spec"`
+	Status             DeploymentConfigStatus `json:"status,omitempty"`
+}
+
+// DeploymentConfigSpec is the spec of deployment config
+type DeploymentConfigSpec struct {
+	Replicas int32                     `json:"replicas"`
+	Selector map[string]string         `json:"selector,omitempty"`
+	Triggers []DeploymentTriggerPolicy `json:"triggers,omitempty"`
+}
+
+// DeploymentTriggerPolicy describes a policy for a single trigger that results in a new deployment
+type DeploymentTriggerPolicy struct {
+	Type              string                              `json:"type,omitempty"`
+	ImageChangeParams *DeploymentTriggerImageChangeParams `json:"imageChangeParams,omitempty"`
+}
+
+// DeploymentTriggerImageChangeParams represents the parameters to the ImageChange trigger
+type DeploymentTriggerImageChangeParams struct {
+	ContainerNames []string               `json:"containerNames,omitempty"`
+	From           api_v1.ObjectReference `json:"from"`
+}
+
+// DeploymentConfigStatus is the status of deployment config
+type DeploymentConfigStatus struct {
+	LatestVersion int64 `json:"latestVersion,omitempty"`
+}
+
+// DeploymentConfigList is the list of deployment configs
+type DeploymentConfigList struct {
+	meta_v1.TypeMeta `json:",inline"`
+	meta_v1.ListMeta `json:"metadata"`
+	Items            []DeploymentConfig `json:"items"`
+}
+
+// Route describes an OpenShift route exposing a service
+type Route struct {
+	meta_v1.TypeMeta   `json:",inline"`
+	meta_v1.ObjectMeta `json:"metadata"`
+	Spec               RouteSpec `json:"spec"`
+}
+
+// RouteSpec is the spec of route
+type RouteSpec struct {
+	Host              string                 `json:"host,omitempty"`
+	Path              string                 `json:"path,omitempty"`
+	To                RouteTargetReference   `json:"to"`
+	AlternateBackends []RouteTargetReference `json:"alternateBackends,omitempty"`
+}
+
+// RouteTargetReference specifies the target that resolve into endpoints
+type RouteTargetReference struct {
+	Kind   string `json:"kind"`
+	Name   string `json:"name"`
+	Weight *int32 `json:"weight,omitempty"`
+}
+
+// RouteList is the list of routes
+type RouteList struct {
+	meta_v1.TypeMeta `json:",inline"`
+	meta_v1.ListMeta `json:"metadata"`
+	Items            []Route `json:"items"`
+}
+
+// ImageStream describes an OpenShift image stream
+type ImageStream struct {
+	meta_v1.TypeMeta   `json:",inline"`
+	meta_v1.ObjectMeta `json:"metadata"`
+	Status             ImageStreamStatus `json:"status,omitempty"`
+}
+
+// ImageStreamStatus is the status of image stream
+type ImageStreamStatus struct {
+	DockerImageRepository string              `json:"dockerImageRepository,omitempty"`
+	Tags                  []NamedTagEventList `json:"tags,omitempty"`
+}
+
+// NamedTagEventList relates a tag to its image history
+type NamedTagEventList struct {
+	Tag string `json:"tag"`
+}
+
+// ImageStreamList is the list of image streams
+type ImageStreamList struct {
+	meta_v1.TypeMeta `json:",inline"`
+	meta_v1.ListMeta `json:"metadata"`
+	Items            []ImageStream `json:"items"`
+}
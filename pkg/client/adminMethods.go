@@ -0,0 +1,73 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package client
+
+import (
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// RunRetention removes deleted resources and pods older than the retention period of the controller
+func (c *APIClient) RunRetention() (*RetentionResult, error) {
+	result := RetentionResult{}
+	if err := c.post("/api/admin/retention", nil, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Reindex rebuilds indices of the predicates, of all indexed predicates if none is given, and returns
+// the reindexed predicates
+func (c *APIClient) Reindex(predicates ...string) ([]string, error) {
+	params := url.Values{"predicate": predicates}
+	root := struct {
+		Predicates []string `json:"predicates"`
+	}{}
+	err := c.post("/api/admin/reindex", params, &root)
+	return root.Predicates, err
+}
+
+// MigrateSchema applies the schema of the controller version on existing data
+func (c *APIClient) MigrateSchema() (*SchemaMigration, error) {
+	migration := SchemaMigration{}
+	if err := c.post("/api/admin/schema/migrate", nil, &migration); err != nil {
+		return nil, err
+	}
+	return &migration, nil
+}
+
+// VerifyConsistency returns the result of consistency checks of purser data
+func (c *APIClient) VerifyConsistency() (*ConsistencyReport, error) {
+	report := ConsistencyReport{}
+	if err := c.get("/api/admin/consistency", nil, &report); err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// Backup writes the json backup of all purser nodes to w
+func (c *APIClient) Backup(w io.Writer) error {
+	resp, err := c.send(http.MethodGet, "/api/admin/backup", nil, nil)
+	if err != nil {
+		return err
+	}
+	defer closeBody(resp)
+	_, err = io.Copy(w, resp.Body)
+	return err
+}
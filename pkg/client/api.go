@@ -0,0 +1,232 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"strings"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+)
+
+// APIClient is a client of the Purser controller API, it keeps the session cookie of its login
+type APIClient struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// APIError is the error returned by the API for invalid requests
+type APIError struct {
+	StatusCode int    `json:"-"`
+	Code       string `json:"code"`
+	Parameter  string `json:"parameter"`
+	Message    string `json:"message"`
+	Hint       string `json:"hint"`
+}
+
+func (e *APIError) Error() string {
+	if e.Code == "" {
+		return fmt.Sprintf("purser api returned status %d", e.StatusCode)
+	}
+	return fmt.Sprintf("%s: %s (%s)", e.Code, e.Message, e.Hint)
+}
+
+// QueryOptions are optional query params of metrics and hierarchy requests
+type QueryOptions struct {
+	// View is logical(default) or physical, only for cluster requests
+	View string
+	// OS restricts metrics to pods running linux or windows
+	OS string
+	// AsOf returns the state of the cluster at the given time instead of now
+	AsOf time.Time
+	// Match is how name is matched: exact(default), ignoreCase, partial or fuzzy
+	Match string
+}
+
+// ChargebackOptions are optional query params of chargeback reports
+type ChargebackOptions struct {
+	// Start and End of the report, month to date by default
+	Start time.Time
+	End   time.Time
+	// GroupBy is namespace(default), label or owner
+	GroupBy string
+	// Label groups pods by its value if GroupBy is label, the tenant label by default
+	Label string
+	// Format is csv(default) or json
+	Format string
+}
+
+// FOCUSOptions are optional query params of FOCUS exports
+type FOCUSOptions struct {
+	// Start and End of the export, month to date by default
+	Start time.Time
+	End   time.Time
+	// Format is csv(default) or json
+	Format string
+}
+
+// NewAPIClient returns a client of the API served at baseURL(ex: http://purser.purser.svc:3030), a http client
+// with a cookie jar is created if httpClient is nil
+func NewAPIClient(baseURL string, httpClient *http.Client) *APIClient {
+	if httpClient == nil {
+		jar, _ := cookiejar.New(nil)
+		httpClient = &http.Client{Jar: jar, Timeout: 30 * time.Second}
+	}
+	return &APIClient{baseURL: strings.TrimSuffix(baseURL, "/"), httpClient: httpClient}
+}
+
+// Login authenticates the client, the session is used by all later requests
+func (c *APIClient) Login(username, password string) error {
+	body, err := json.Marshal(map[string]string{"username": username, "password": password})
+	if err != nil {
+		return err
+	}
+	resp, err := c.httpClient.Post(c.baseURL+"/auth/login", "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer closeBody(resp)
+	if resp.StatusCode != http.StatusOK {
+		return &APIError{StatusCode: resp.StatusCode, Code: "LOGIN_FAILED", Message: "unable to login as " + username, Hint: "check the credentials"}
+	}
+	return nil
+}
+
+// get sends a GET request to path with params and decodes the response in target
+func (c *APIClient) get(path string, params url.Values, target interface{}) error {
+	return c.request(http.MethodGet, path, params, target)
+}
+
+// post sends a POST request to path with params and decodes the response in target
+func (c *APIClient) post(path string, params url.Values, target interface{}) error {
+	return c.request(http.MethodPost, path, params, target)
+}
+
+func (c *APIClient) request(method, path string, params url.Values, target interface{}) error {
+	return c.requestWithBody(method, path, params, nil, target)
+}
+
+func (c *APIClient) requestWithBody(method, path string, params url.Values, body io.Reader, target interface{}) error {
+	resp, err := c.send(method, path, params, body)
+	if err != nil {
+		return err
+	}
+	defer closeBody(resp)
+	return json.NewDecoder(resp.Body).Decode(target)
+}
+
+// send sends the request with the body if it isn't nil and returns the response if its status is OK, the caller
+// closes the body
+func (c *APIClient) send(method, path string, params url.Values, body io.Reader) (*http.Response, error) {
+	requestURL := c.baseURL + path
+	if len(params) > 0 {
+		requestURL += "?" + params.Encode()
+	}
+	req, err := http.NewRequest(method, requestURL, body)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer closeBody(resp)
+		return nil, decodeAPIError(resp)
+	}
+	return resp, nil
+}
+
+func decodeAPIError(resp *http.Response) error {
+	wrapper := struct {
+		Error APIError `json:"error"`
+	}{}
+	// body of errors other than validation errors is not json
+	_ = json.NewDecoder(resp.Body).Decode(&wrapper)
+	wrapper.Error.StatusCode = resp.StatusCode
+	return &wrapper.Error
+}
+
+func closeBody(resp *http.Response) {
+	if err := resp.Body.Close(); err != nil {
+		log.Errorf("unable to close response body: %v", err)
+	}
+}
+
+func (o *QueryOptions) values() url.Values {
+	params := url.Values{}
+	if o == nil {
+		return params
+	}
+	if o.View != "" {
+		params.Set("view", o.View)
+	}
+	if o.OS != "" {
+		params.Set("os", o.OS)
+	}
+	if !o.AsOf.IsZero() {
+		params.Set("asOf", o.AsOf.UTC().Format(time.RFC3339))
+	}
+	if o.Match != "" {
+		params.Set("match", o.Match)
+	}
+	return params
+}
+
+func (o *FOCUSOptions) values() url.Values {
+	params := url.Values{}
+	if o == nil {
+		return params
+	}
+	if !o.Start.IsZero() {
+		params.Set("start", o.Start.UTC().Format(time.RFC3339))
+	}
+	if !o.End.IsZero() {
+		params.Set("end", o.End.UTC().Format(time.RFC3339))
+	}
+	if o.Format != "" {
+		params.Set("format", o.Format)
+	}
+	return params
+}
+
+func (o *ChargebackOptions) values() url.Values {
+	params := url.Values{}
+	if o == nil {
+		return params
+	}
+	if !o.Start.IsZero() {
+		params.Set("start", o.Start.UTC().Format(time.RFC3339))
+	}
+	if !o.End.IsZero() {
+		params.Set("end", o.End.UTC().Format(time.RFC3339))
+	}
+	for name, value := range map[string]string{"groupBy": o.GroupBy, "label": o.Label, "format": o.Format} {
+		if value != "" {
+			params.Set(name, value)
+		}
+	}
+	return params
+}
@@ -0,0 +1,167 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package client
+
+import (
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// ClusterMetrics returns metrics of the cluster with its namespaces as children(nodes and PVs in physical view)
+func (c *APIClient) ClusterMetrics(opts *QueryOptions) (*Resource, error) {
+	return c.getResource("/api/metrics", opts.values())
+}
+
+// Metrics returns metrics of the resource of resourceType(namespace, deployment, pod, node, ...) with its children,
+// name is prefixed with the resource type(ex: namespace-default) if it isn't already
+func (c *APIClient) Metrics(resourceType, name string, opts *QueryOptions) (*Resource, error) {
+	params := opts.values()
+	params.Set("name", getResourceName(resourceType, name))
+	return c.getResource("/api/metrics/"+resourceType, params)
+}
+
+// ClusterHierarchy returns the namespaces(nodes and PVs in physical view) of the cluster
+func (c *APIClient) ClusterHierarchy(opts *QueryOptions) (*Resource, error) {
+	return c.getResource("/api/hierarchy", opts.values())
+}
+
+// Hierarchy returns the children of the resource of resourceType, name is prefixed as in Metrics
+func (c *APIClient) Hierarchy(resourceType, name string, opts *QueryOptions) (*Resource, error) {
+	params := opts.values()
+	params.Set("name", getResourceName(resourceType, name))
+	return c.getResource("/api/hierarchy/"+resourceType, params)
+}
+
+// PodInteractions returns inbound and outbound pods of the pod, of all pods with interactions if name is empty
+func (c *APIClient) PodInteractions(name string) ([]PodInteraction, error) {
+	params := url.Values{}
+	if name != "" {
+		params.Set("name", getResourceName("pod", name))
+	} else {
+		params.Set("orphan", "false")
+	}
+	root := struct {
+		Pods []PodInteraction `json:"pods"`
+	}{}
+	err := c.get("/api/interactions/pod", params, &root)
+	return root.Pods, err
+}
+
+// Groups returns all custom groups with their costs
+func (c *APIClient) Groups() ([]Group, error) {
+	groups := []Group{}
+	err := c.get("/api/groups", nil, &groups)
+	return groups, err
+}
+
+// TenantCosts returns the report of monthly costs of each tenant, the configured tenant label is used if label is empty
+func (c *APIClient) TenantCosts(label string) (*TenantCosts, error) {
+	params := url.Values{}
+	if label != "" {
+		params.Set("label", label)
+	}
+	root := struct {
+		Data TenantCosts `json:"data"`
+	}{}
+	if err := c.get("/api/metrics/tenants", params, &root); err != nil {
+		return nil, err
+	}
+	return &root.Data, nil
+}
+
+// ClusterDiff returns the report of workloads created, deleted or resized between start and end and the cost
+// change of each namespace
+func (c *APIClient) ClusterDiff(start, end time.Time) (*ClusterDiff, error) {
+	params := url.Values{}
+	params.Set("start", start.UTC().Format(time.RFC3339))
+	params.Set("end", end.UTC().Format(time.RFC3339))
+	root := struct {
+		Data ClusterDiff `json:"data"`
+	}{}
+	if err := c.get("/api/diff", params, &root); err != nil {
+		return nil, err
+	}
+	return &root.Data, nil
+}
+
+// EstimateManifest returns the projected monthly cost on the cluster of workloads of the YAML or JSON manifest
+func (c *APIClient) EstimateManifest(manifest io.Reader) (*ManifestEstimate, error) {
+	root := struct {
+		Data ManifestEstimate `json:"data"`
+	}{}
+	if err := c.requestWithBody(http.MethodPost, "/api/estimate", nil, manifest, &root); err != nil {
+		return nil, err
+	}
+	return &root.Data, nil
+}
+
+// CheckBudget returns whether the namespace stays within its monthly budget if the workloads of the YAML or JSON
+// manifest are deployed in it, namespace is prefixed as in Metrics
+func (c *APIClient) CheckBudget(namespace string, manifest io.Reader) (*BudgetCheck, error) {
+	params := url.Values{}
+	params.Set("name", getResourceName("namespace", namespace))
+	root := struct {
+		Data BudgetCheck `json:"data"`
+	}{}
+	if err := c.requestWithBody(http.MethodPost, "/api/budget/check", params, manifest, &root); err != nil {
+		return nil, err
+	}
+	return &root.Data, nil
+}
+
+// ExportChargeback writes the chargeback report of cost of workloads grouped by namespace, label or owner to w
+func (c *APIClient) ExportChargeback(opts *ChargebackOptions, w io.Writer) error {
+	resp, err := c.send(http.MethodGet, "/api/export/chargeback", opts.values(), nil)
+	if err != nil {
+		return err
+	}
+	defer closeBody(resp)
+	_, err = io.Copy(w, resp.Body)
+	return err
+}
+
+// ExportFOCUS writes charges of resources requested by pods in the FinOps FOCUS schema to w
+func (c *APIClient) ExportFOCUS(opts *FOCUSOptions, w io.Writer) error {
+	resp, err := c.send(http.MethodGet, "/api/export/focus", opts.values(), nil)
+	if err != nil {
+		return err
+	}
+	defer closeBody(resp)
+	_, err = io.Copy(w, resp.Body)
+	return err
+}
+
+func (c *APIClient) getResource(path string, params url.Values) (*Resource, error) {
+	root := struct {
+		Data Resource `json:"data"`
+	}{}
+	if err := c.get(path, params, &root); err != nil {
+		return nil, err
+	}
+	return &root.Data, nil
+}
+
+func getResourceName(resourceType, name string) string {
+	if strings.HasPrefix(name, resourceType+"-") {
+		return name
+	}
+	return resourceType + "-" + name
+}
@@ -0,0 +1,205 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package client
+
+// Resource is a node of hierarchy or metrics responses with its children, costs are month to date
+type Resource struct {
+	Name                 string     `json:"name"`
+	Type                 string     `json:"type"`
+	CPU                  float64    `json:"cpu,omitempty"`
+	Memory               float64    `json:"memory,omitempty"`
+	Storage              float64    `json:"storage,omitempty"`
+	CPUCost              float64    `json:"cpuCost,omitempty"`
+	MemoryCost           float64    `json:"memoryCost,omitempty"`
+	StorageCost          float64    `json:"storageCost,omitempty"`
+	EphemeralStorage     float64    `json:"ephemeralStorage,omitempty"`
+	EphemeralStorageCost float64    `json:"ephemeralStorageCost,omitempty"`
+	Hugepages            float64    `json:"hugepages,omitempty"`
+	HugepagesCost        float64    `json:"hugepagesCost,omitempty"`
+	GPU                  float64    `json:"gpu,omitempty"`
+	GPUCost              float64    `json:"gpuCost,omitempty"`
+	ExtendedResourceCost float64    `json:"extendedResourceCost,omitempty"`
+	BandwidthCost        float64    `json:"bandwidthCost,omitempty"`
+	Carbon               float64    `json:"carbon,omitempty"`
+	Energy               float64    `json:"energy,omitempty"`
+	CPUAllocated         float64    `json:"cpuAllocated,omitempty"`
+	MemoryAllocated      float64    `json:"memoryAllocated,omitempty"`
+	StorageAllocated     float64    `json:"storageAllocated,omitempty"`
+	CPUCapacity          float64    `json:"cpuCapacity,omitempty"`
+	MemoryCapacity       float64    `json:"memoryCapacity,omitempty"`
+	StorageCapacity      float64    `json:"storageCapacity,omitempty"`
+	Children             []Resource `json:"children,omitempty"`
+}
+
+// TotalCost returns sum of cpu, memory, storage, ephemeral storage, hugepages, gpu, extended resource and bandwidth costs
+func (r Resource) TotalCost() float64 {
+	return r.CPUCost + r.MemoryCost + r.StorageCost + r.EphemeralStorageCost + r.HugepagesCost + r.GPUCost +
+		r.ExtendedResourceCost + r.BandwidthCost
+}
+
+// PodInteraction is a pod with the pods it sends traffic to(Outbound) and receives traffic from(Inbound)
+type PodInteraction struct {
+	Name     string `json:"name"`
+	Outbound []struct {
+		Name  string  `json:"name"`
+		Count float64 `json:"pod|count,omitempty"`
+	} `json:"outbound,omitempty"`
+	Inbound []struct {
+		Name string `json:"name"`
+	} `json:"inbound,omitempty"`
+}
+
+// Group is a custom group with its usage and costs
+type Group struct {
+	Name                string  `json:"name"`
+	PodsCount           int     `json:"podsCount,omitempty"`
+	CPU                 float64 `json:"cpu,omitempty"`
+	Memory              float64 `json:"memory,omitempty"`
+	Storage             float64 `json:"storage,omitempty"`
+	MtdCPUCost          float64 `json:"mtdCPUCost,omitempty"`
+	MtdMemoryCost       float64 `json:"mtdMemoryCost,omitempty"`
+	MtdStorageCost      float64 `json:"mtdStorageCost,omitempty"`
+	MtdCost             float64 `json:"mtdCost,omitempty"`
+	ProjectedCost       float64 `json:"projectedCost,omitempty"`
+	LastMonthCost       float64 `json:"lastMonthCost,omitempty"`
+	LastLastMonthCost   float64 `json:"lastLastMonthCost,omitempty"`
+	MtdCarbon           float64 `json:"mtdCarbon,omitempty"`
+	ProjectedCarbon     float64 `json:"projectedCarbon,omitempty"`
+	LastMonthCarbon     float64 `json:"lastMonthCarbon,omitempty"`
+	LastLastMonthCarbon float64 `json:"lastLastMonthCarbon,omitempty"`
+}
+
+// TenantCosts is the report of monthly cost of each value of the tenant label
+type TenantCosts struct {
+	Label            string   `json:"label"`
+	SharedNamespaces []string `json:"sharedNamespaces,omitempty"`
+	Tenants          []struct {
+		Name  string `json:"name"`
+		Costs []struct {
+			Period     string  `json:"period"`
+			Start      string  `json:"start"`
+			DirectCost float64 `json:"directCost"`
+			SharedCost float64 `json:"sharedCost"`
+			TotalCost  float64 `json:"totalCost"`
+		} `json:"costs"`
+	} `json:"tenants"`
+}
+
+// ClusterDiff is the report of workload and namespace cost changes between two times
+type ClusterDiff struct {
+	Start         string  `json:"start"`
+	End           string  `json:"end"`
+	CostBefore    float64 `json:"costBefore"`
+	CostAfter     float64 `json:"costAfter"`
+	CostChange    float64 `json:"costChange"`
+	PercentChange float64 `json:"percentChange"`
+	Namespaces    []struct {
+		Name          string  `json:"name"`
+		CostBefore    float64 `json:"costBefore"`
+		CostAfter     float64 `json:"costAfter"`
+		CostChange    float64 `json:"costChange"`
+		PercentChange float64 `json:"percentChange"`
+		Created       int     `json:"created"`
+		Deleted       int     `json:"deleted"`
+		Resized       int     `json:"resized"`
+	} `json:"namespaces"`
+	Workloads []struct {
+		Name         string  `json:"name"`
+		Type         string  `json:"type"`
+		Namespace    string  `json:"namespace"`
+		Change       string  `json:"change"`
+		CPUBefore    float64 `json:"cpuBefore"`
+		CPUAfter     float64 `json:"cpuAfter"`
+		MemoryBefore float64 `json:"memoryBefore"`
+		MemoryAfter  float64 `json:"memoryAfter"`
+	} `json:"workloads"`
+}
+
+// RetentionResult is the number of deleted resources, pods and resources of deleted namespaces removed by a
+// retention run, with the number of edges to them and of daily costs of groups and cost snapshots removed
+type RetentionResult struct {
+	Resources                 int `json:"resources"`
+	Pods                      int `json:"pods"`
+	DeletedNamespaceResources int `json:"deletedNamespaceResources"`
+	Edges                     int `json:"edges"`
+	DailyCosts                int `json:"dailyCosts"`
+}
+
+// SchemaMigration lists predicates added or changed by a schema migration and migrated renamed predicates
+type SchemaMigration struct {
+	Added    []string `json:"added"`
+	Changed  []string `json:"changed"`
+	Migrated []string `json:"migrated,omitempty"`
+}
+
+// ConsistencyReport is the result of consistency checks of purser data in dgraph
+type ConsistencyReport struct {
+	Consistent bool `json:"consistent"`
+	Checks     []struct {
+		Name        string   `json:"name"`
+		Description string   `json:"description"`
+		Count       int      `json:"count"`
+		UIDs        []string `json:"uids,omitempty"`
+	} `json:"checks"`
+}
+
+// ManifestEstimate is the projected cost of workloads of a manifest, RequiredCapacity is the node capacity their
+// pods need if the cluster has no free capacity
+type ManifestEstimate struct {
+	Nodes            int     `json:"nodes"`
+	CPU              float64 `json:"cpu"`
+	Memory           float64 `json:"memory"`
+	GPU              float64 `json:"gpu"`
+	HourlyCost       float64 `json:"hourlyCost"`
+	MonthlyCost      float64 `json:"monthlyCost"`
+	RequiredCapacity struct {
+		InstanceType string  `json:"instanceType,omitempty"`
+		Nodes        int     `json:"nodes"`
+		MonthlyCost  float64 `json:"monthlyCost"`
+	} `json:"requiredCapacity"`
+	Workloads []struct {
+		Name         string  `json:"name"`
+		Kind         string  `json:"kind"`
+		Replicas     int     `json:"replicas"`
+		InstanceType string  `json:"instanceType,omitempty"`
+		CPU          float64 `json:"cpu"`
+		Memory       float64 `json:"memory"`
+		GPU          float64 `json:"gpu"`
+		MonthlyCost  float64 `json:"monthlyCost"`
+	} `json:"workloads"`
+}
+
+// BudgetCheck is the result of checking the monthly budget of a namespace for a manifest, Failures has the
+// machine readable reasons(ex: BUDGET_EXCEEDED) if it did not pass
+type BudgetCheck struct {
+	Namespace       string  `json:"namespace"`
+	Passed          bool    `json:"passed"`
+	HasBudget       bool    `json:"hasBudget"`
+	Budget          float64 `json:"budget"`
+	MonthToDateCost float64 `json:"monthToDateCost"`
+	HourlyCost      float64 `json:"hourlyCost"`
+	ManifestCost    float64 `json:"manifestCost"`
+	ProjectedCost   float64 `json:"projectedCost"`
+	MonthlyRunRate  float64 `json:"monthlyRunRate"`
+	RemainingBudget float64 `json:"remainingBudget"`
+	Failures        []struct {
+		Reason  string `json:"reason"`
+		Message string `json:"message"`
+	} `json:"failures"`
+	Estimate ManifestEstimate `json:"estimate"`
+}
@@ -0,0 +1,170 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package client
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vmware/purser/test/utils"
+)
+
+func newTestServer(t *testing.T) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/auth/login", func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "purser-session-token", Value: "token", Path: "/"})
+	})
+	mux.HandleFunc("/api/metrics/namespace", func(w http.ResponseWriter, r *http.Request) {
+		if cookie, err := r.Cookie("purser-session-token"); err != nil || cookie.Value != "token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		utils.Equals(t, "namespace-default", r.URL.Query().Get("name"))
+		utils.Equals(t, "2018-10-15T00:00:00Z", r.URL.Query().Get("asOf"))
+		_, _ = w.Write([]byte(`{"data": {"name": "namespace-default", "type": "namespace", "cpuCost": 2, "memoryCost": 1,
+			"children": [{"name": "deployment-web", "type": "deployment", "cpuCost": 2, "memoryCost": 1}]}}`))
+	})
+	mux.HandleFunc("/api/diff", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"error": {"code": "INVALID_TIME_RANGE", "parameter": "start", "message": "start is not before end", "hint": "swap start and end"}}`))
+	})
+	mux.HandleFunc("/api/admin/reindex", func(w http.ResponseWriter, r *http.Request) {
+		utils.Equals(t, http.MethodPost, r.Method)
+		utils.Equals(t, []string{"name", "endTime"}, r.URL.Query()["predicate"])
+		_, _ = w.Write([]byte(`{"predicates": ["name", "endTime"]}`))
+	})
+	mux.HandleFunc("/api/estimate", func(w http.ResponseWriter, r *http.Request) {
+		utils.Equals(t, http.MethodPost, r.Method)
+		manifest, _ := ioutil.ReadAll(r.Body)
+		utils.Equals(t, "kind: Deployment", string(manifest))
+		_, _ = w.Write([]byte(`{"data": {"nodes": 2, "monthlyCost": 72, "workloads": [{"name": "web", "kind": "Deployment", "replicas": 3, "monthlyCost": 72}]}}`))
+	})
+	mux.HandleFunc("/api/budget/check", func(w http.ResponseWriter, r *http.Request) {
+		utils.Equals(t, http.MethodPost, r.Method)
+		utils.Equals(t, "namespace-dev", r.URL.Query().Get("name"))
+		_, _ = w.Write([]byte(`{"data": {"namespace": "namespace-dev", "passed": false, "budget": 100, "failures": [{"reason": "BUDGET_EXCEEDED"}]}}`))
+	})
+	mux.HandleFunc("/api/export/chargeback", func(w http.ResponseWriter, r *http.Request) {
+		utils.Equals(t, "owner", r.URL.Query().Get("groupBy"))
+		utils.Equals(t, "2018-10-01T00:00:00Z", r.URL.Query().Get("start"))
+		_, _ = w.Write([]byte("start,end,group\n"))
+	})
+	mux.HandleFunc("/api/export/focus", func(w http.ResponseWriter, r *http.Request) {
+		utils.Equals(t, "json", r.URL.Query().Get("format"))
+		_, _ = w.Write([]byte(`{"data": {"rows": []}}`))
+	})
+	mux.HandleFunc("/api/admin/backup", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"nodes": [{"uid": "0x1", "name": "pod-web"}]}`))
+	})
+	return httptest.NewServer(mux)
+}
+
+func TestAPIClientMetrics(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+	c := NewAPIClient(server.URL+"/", nil)
+
+	asOf := time.Date(2018, 10, 15, 0, 0, 0, 0, time.UTC)
+	_, err := c.Metrics("namespace", "default", &QueryOptions{AsOf: asOf})
+	utils.Assert(t, err != nil, "request without login succeeded")
+	utils.Equals(t, http.StatusForbidden, err.(*APIError).StatusCode)
+
+	utils.Ok(t, c.Login("admin", "purser!123"))
+	namespace, err := c.Metrics("namespace", "default", &QueryOptions{AsOf: asOf})
+	utils.Ok(t, err)
+	utils.Equals(t, 3.0, namespace.TotalCost())
+	utils.Equals(t, "deployment-web", namespace.Children[0].Name)
+}
+
+func TestQueryOptionsValues(t *testing.T) {
+	var opts *QueryOptions
+	utils.Equals(t, 0, len(opts.values()))
+	opts = &QueryOptions{OS: "linux", Match: "ignoreCase"}
+	utils.Equals(t, "linux", opts.values().Get("os"))
+	utils.Equals(t, "ignoreCase", opts.values().Get("match"))
+	utils.Equals(t, "", opts.values().Get("asOf"))
+}
+
+func TestAPIClientError(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+	c := NewAPIClient(server.URL, nil)
+
+	now := time.Now()
+	_, err := c.ClusterDiff(now, now.Add(-time.Hour))
+	apiErr, isAPIError := err.(*APIError)
+	utils.Assert(t, isAPIError, "error is not an api error")
+	utils.Equals(t, "INVALID_TIME_RANGE", apiErr.Code)
+	utils.Equals(t, "start", apiErr.Parameter)
+}
+
+func TestAPIClientEstimateManifest(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+	c := NewAPIClient(server.URL, nil)
+
+	estimate, err := c.EstimateManifest(strings.NewReader("kind: Deployment"))
+	utils.Ok(t, err)
+	utils.Equals(t, 72.0, estimate.MonthlyCost)
+	utils.Equals(t, 3, estimate.Workloads[0].Replicas)
+}
+
+func TestAPIClientCheckBudget(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+	c := NewAPIClient(server.URL, nil)
+
+	check, err := c.CheckBudget("dev", strings.NewReader("kind: Deployment"))
+	utils.Ok(t, err)
+	utils.Assert(t, !check.Passed, "budget check passed")
+	utils.Equals(t, "BUDGET_EXCEEDED", check.Failures[0].Reason)
+}
+
+func TestAPIClientExportChargeback(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+	c := NewAPIClient(server.URL, nil)
+
+	report := &bytes.Buffer{}
+	start := time.Date(2018, 10, 1, 0, 0, 0, 0, time.UTC)
+	utils.Ok(t, c.ExportChargeback(&ChargebackOptions{Start: start, GroupBy: "owner"}, report))
+	utils.Equals(t, "start,end,group\n", report.String())
+
+	export := &bytes.Buffer{}
+	utils.Ok(t, c.ExportFOCUS(&FOCUSOptions{Format: "json"}, export))
+	utils.Equals(t, `{"data": {"rows": []}}`, export.String())
+}
+
+func TestAPIClientAdmin(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+	c := NewAPIClient(server.URL, nil)
+
+	predicates, err := c.Reindex("name", "endTime")
+	utils.Ok(t, err)
+	utils.Equals(t, []string{"name", "endTime"}, predicates)
+
+	backup := &bytes.Buffer{}
+	utils.Ok(t, c.Backup(backup))
+	utils.Equals(t, `{"nodes": [{"uid": "0x1", "name": "pod-web"}]}`, backup.String())
+}
@@ -0,0 +1,114 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1
+
+import (
+	"reflect"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+
+	alertrule_v1 "github.com/vmware/purser/pkg/apis/alertrule/v1"
+
+	apiextv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	apiextcs "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/client-go/rest"
+)
+
+// NewAlertRuleClient returns an instance of the AlertRule Client
+func NewAlertRuleClient(clientset apiextcs.Interface, config *rest.Config) *AlertRuleClient {
+	err := createAlertRuleCRD(clientset)
+	if err != nil {
+		log.Fatalf("failed to create CRD alert rule %v", err)
+	}
+
+	// Wait for the CRD to be created before we use it (only needed if its a new one)
+	time.Sleep(3 * time.Second)
+
+	// Create a new clientset which include our CRD schema
+	crdcs, scheme, err := newClient(config)
+	if err != nil {
+		log.Fatalf("failed to add CRD alert rule schema to clientset %v", err)
+	}
+
+	// Create a CRD client interface
+	return AlertRule(crdcs, scheme, "default")
+}
+
+// AlertRule returns an instance of the alert rule client
+func AlertRule(client *rest.RESTClient, scheme *runtime.Scheme, namespace string) *AlertRuleClient {
+	return &AlertRuleClient{
+		client: client,
+		ns:     namespace,
+		plural: alertrule_v1.AlertRulePlural,
+		codec:  runtime.NewParameterCodec(scheme),
+	}
+}
+
+func createAlertRuleCRD(clientset apiextcs.Interface) error {
+	crd := &apiextv1beta1.CustomResourceDefinition{
+		ObjectMeta: meta_v1.ObjectMeta{Name: alertrule_v1.AlertRuleFullName},
+		Spec: apiextv1beta1.CustomResourceDefinitionSpec{
+			Group:   alertrule_v1.AlertRuleGroup,
+			Version: alertrule_v1.AlertRuleVersion,
+			Scope:   apiextv1beta1.NamespaceScoped,
+			Names: apiextv1beta1.CustomResourceDefinitionNames{
+				Plural: alertrule_v1.AlertRulePlural,
+				Kind:   reflect.TypeOf(alertrule_v1.AlertRule{}).Name(),
+			},
+		},
+	}
+	_, err := clientset.ApiextensionsV1beta1().CustomResourceDefinitions().Create(crd)
+	// Ignore error if it already exists
+	if err != nil && apierrors.IsAlreadyExists(err) {
+		return nil
+	}
+	return err
+}
+
+func newClient(cfg *rest.Config) (*rest.RESTClient, *runtime.Scheme, error) {
+	config := *cfg
+	scheme, err := setConfigDefaults(&config)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	client, err := rest.RESTClientFor(&config)
+	if err != nil {
+		return nil, nil, err
+	}
+	return client, scheme, nil
+}
+
+func setConfigDefaults(config *rest.Config) (*runtime.Scheme, error) {
+	scheme := runtime.NewScheme()
+	SchemeBuilder := runtime.NewSchemeBuilder(alertrule_v1.AddKnownTypes)
+	if err := SchemeBuilder.AddToScheme(scheme); err != nil {
+		return nil, err
+	}
+	config.GroupVersion = &alertrule_v1.AlertRuleGroupVersion
+	config.APIPath = "/apis"
+	config.ContentType = runtime.ContentTypeJSON
+	config.NegotiatedSerializer = serializer.DirectCodecFactory{
+		CodecFactory: serializer.NewCodecFactory(scheme)}
+	return scheme, nil
+}
@@ -0,0 +1,116 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1
+
+import (
+	"github.com/vmware/purser/pkg/apis/openshift/v1"
+
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/rest"
+)
+
+// OpenShiftInterface has client methods we need to access OpenShift objects across all namespaces
+type OpenShiftInterface interface {
+	ListDeploymentConfigs(opts meta_v1.ListOptions) (*v1.DeploymentConfigList, error)
+	WatchDeploymentConfigs(opts meta_v1.ListOptions) (watch.Interface, error)
+	ListRoutes(opts meta_v1.ListOptions) (*v1.RouteList, error)
+	WatchRoutes(opts meta_v1.ListOptions) (watch.Interface, error)
+	ListImageStreams(opts meta_v1.ListOptions) (*v1.ImageStreamList, error)
+	WatchImageStreams(opts meta_v1.ListOptions) (watch.Interface, error)
+}
+
+// OpenShiftClient holds clients of OpenShift apps, route and image API groups
+type OpenShiftClient struct {
+	appsClient  *rest.RESTClient
+	appsCodec   runtime.ParameterCodec
+	routeClient *rest.RESTClient
+	routeCodec  runtime.ParameterCodec
+	imageClient *rest.RESTClient
+	imageCodec  runtime.ParameterCodec
+}
+
+// ListDeploymentConfigs lists deployment configs of all namespaces.
+func (c *OpenShiftClient) ListDeploymentConfigs(opts meta_v1.ListOptions) (*v1.DeploymentConfigList, error) {
+	result := v1.DeploymentConfigList{}
+	err := c.appsClient.Get().
+		Namespace(meta_v1.NamespaceAll).
+		Resource(v1.DeploymentConfigPlural).
+		VersionedParams(&opts, c.appsCodec).
+		Do().
+		Into(&result)
+	return &result, err
+}
+
+// WatchDeploymentConfigs watches deployment configs of all namespaces.
+func (c *OpenShiftClient) WatchDeploymentConfigs(opts meta_v1.ListOptions) (watch.Interface, error) {
+	opts.Watch = true
+	return c.appsClient.
+		Get().
+		Namespace(meta_v1.NamespaceAll).
+		Resource(v1.DeploymentConfigPlural).
+		VersionedParams(&opts, c.appsCodec).
+		Watch()
+}
+
+// ListRoutes lists routes of all namespaces.
+func (c *OpenShiftClient) ListRoutes(opts meta_v1.ListOptions) (*v1.RouteList, error) {
+	result := v1.RouteList{}
+	err := c.routeClient.Get().
+		Namespace(meta_v1.NamespaceAll).
+		Resource(v1.RoutePlural).
+		VersionedParams(&opts, c.routeCodec).
+		Do().
+		Into(&result)
+	return &result, err
+}
+
+// WatchRoutes watches routes of all namespaces.
+func (c *OpenShiftClient) WatchRoutes(opts meta_v1.ListOptions) (watch.Interface, error) {
+	opts.Watch = true
+	return c.routeClient.
+		Get().
+		Namespace(meta_v1.NamespaceAll).
+		Resource(v1.RoutePlural).
+		VersionedParams(&opts, c.routeCodec).
+		Watch()
+}
+
+// ListImageStreams lists image streams of all namespaces.
+func (c *OpenShiftClient) ListImageStreams(opts meta_v1.ListOptions) (*v1.ImageStreamList, error) {
+	result := v1.ImageStreamList{}
+	err := c.imageClient.Get().
+		Namespace(meta_v1.NamespaceAll).
+		Resource(v1.ImageStreamPlural).
+		VersionedParams(&opts, c.imageCodec).
+		Do().
+		Into(&result)
+	return &result, err
+}
+
+// WatchImageStreams watches image streams of all namespaces.
+func (c *OpenShiftClient) WatchImageStreams(opts meta_v1.ListOptions) (watch.Interface, error) {
+	opts.Watch = true
+	return c.imageClient.
+		Get().
+		Namespace(meta_v1.NamespaceAll).
+		Resource(v1.ImageStreamPlural).
+		VersionedParams(&opts, c.imageCodec).
+		Watch()
+}
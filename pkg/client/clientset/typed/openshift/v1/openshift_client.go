@@ -0,0 +1,93 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1
+
+import (
+	log "github.com/Sirupsen/logrus"
+
+	openshift_v1 "github.com/vmware/purser/pkg/apis/openshift/v1"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/rest"
+)
+
+// NewOpenShiftClient returns an instance of the OpenShift Client
+func NewOpenShiftClient(config *rest.Config) (*OpenShiftClient, error) {
+	appsClient, appsScheme, err := newClient(config, openshift_v1.AppsGroupVersion, openshift_v1.AddAppsKnownTypes)
+	if err != nil {
+		return nil, err
+	}
+	routeClient, routeScheme, err := newClient(config, openshift_v1.RouteGroupVersion, openshift_v1.AddRouteKnownTypes)
+	if err != nil {
+		return nil, err
+	}
+	imageClient, imageScheme, err := newClient(config, openshift_v1.ImageGroupVersion, openshift_v1.AddImageKnownTypes)
+	if err != nil {
+		return nil, err
+	}
+	return &OpenShiftClient{
+		appsClient:  appsClient,
+		appsCodec:   runtime.NewParameterCodec(appsScheme),
+		routeClient: routeClient,
+		routeCodec:  runtime.NewParameterCodec(routeScheme),
+		imageClient: imageClient,
+		imageCodec:  runtime.NewParameterCodec(imageScheme),
+	}, nil
+}
+
+// IsOpenShift returns true if the cluster serves OpenShift apps API
+func IsOpenShift(client discovery.DiscoveryInterface) bool {
+	_, err := client.ServerResourcesForGroupVersion(openshift_v1.AppsGroupVersion.String())
+	if err != nil {
+		log.Debugf("OpenShift apps API is not available: %v", err)
+		return false
+	}
+	return true
+}
+
+func newClient(cfg *rest.Config, groupVersion schema.GroupVersion, addKnownTypes func(*runtime.Scheme) error) (*rest.RESTClient, *runtime.Scheme, error) {
+	config := *cfg
+	scheme, err := setConfigDefaults(&config, groupVersion, addKnownTypes)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	client, err := rest.RESTClientFor(&config)
+	if err != nil {
+		return nil, nil, err
+	}
+	return client, scheme, nil
+}
+
+func setConfigDefaults(config *rest.Config, groupVersion schema.GroupVersion, addKnownTypes func(*runtime.Scheme) error) (*runtime.Scheme, error) {
+	scheme := runtime.NewScheme()
+	SchemeBuilder := runtime.NewSchemeBuilder(addKnownTypes)
+	if err := SchemeBuilder.AddToScheme(scheme); err != nil {
+		return nil, err
+	}
+	config.GroupVersion = &groupVersion
+	config.APIPath = "/apis"
+	config.ContentType = runtime.ContentTypeJSON
+	config.NegotiatedSerializer = serializer.DirectCodecFactory{
+		CodecFactory: serializer.NewCodecFactory(scheme),
+	}
+	return scheme, nil
+}
@@ -0,0 +1,173 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package admission
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/vmware/purser/pkg/controller/dgraph/models"
+	"github.com/vmware/purser/pkg/controller/dgraph/models/query"
+	"github.com/vmware/purser/pkg/controller/utils"
+
+	"k8s.io/api/admission/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Annotations set on admitted workloads by the mutating webhook
+const (
+	MonthlyCostAnnotation = "purser.vmware.com/monthly-cost"
+	CostWarningAnnotation = "purser.vmware.com/cost-warning"
+)
+
+var (
+	limits         = map[string]float64{}
+	denyAboveLimit bool
+
+	// estimateMonthlyCost returns the projected monthly cost of workloads on the cluster
+	estimateMonthlyCost = func(ctx context.Context, workloads []models.ManifestWorkload) float64 {
+		return query.EstimateManifestCost(ctx, workloads).Data.MonthlyCost
+	}
+)
+
+type admittedObject struct {
+	Metadata struct {
+		Annotations     map[string]string       `json:"annotations"`
+		OwnerReferences []metav1.OwnerReference `json:"ownerReferences"`
+	} `json:"metadata"`
+}
+
+type patchOperation struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	Value interface{} `json:"value"`
+}
+
+// Configure sets monthly cost limits of workloads from a comma separated list of <namespace>=<cost>, limit of
+// namespace * applies to other namespaces. Workloads above the limit of their namespace are denied if deny is true,
+// admitted with a warning otherwise.
+func Configure(costLimits string, deny bool) error {
+	parsedLimits, err := utils.ParseNamespaceCosts(costLimits)
+	if err != nil {
+		return err
+	}
+	limits = parsedLimits
+	denyAboveLimit = deny
+	return nil
+}
+
+// StartServer serves the admission webhook over TLS on the address. Path /mutate annotates workloads with their
+// estimated monthly cost, /validate only checks cost limits.
+func StartServer(address, certFile, keyFile string) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/mutate", func(w http.ResponseWriter, r *http.Request) { serve(w, r, true) })
+	mux.HandleFunc("/validate", func(w http.ResponseWriter, r *http.Request) { serve(w, r, false) })
+	log.Infof("admission webhook listening on %s", address)
+	log.Fatal(http.ListenAndServeTLS(address, certFile, keyFile, mux))
+}
+
+func serve(w http.ResponseWriter, r *http.Request, mutate bool) {
+	review := v1beta1.AdmissionReview{}
+	if err := json.NewDecoder(r.Body).Decode(&review); err != nil || review.Request == nil {
+		log.Errorf("invalid admission review, err: %v", err)
+		http.Error(w, "invalid admission review", http.StatusBadRequest)
+		return
+	}
+	review.Response = Review(r.Context(), review.Request, mutate)
+	review.Response.UID = review.Request.UID
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(review); err != nil {
+		log.Errorf("unable to encode admission review: %v", err)
+	}
+}
+
+// Review admits the created or updated workload of the request with its estimated monthly cost as annotation if
+// mutate is true. Pods and workloads created by controllers(ex: ReplicaSets of Deployments) and other objects are
+// admitted without estimate, so cost of a workload is checked once.
+func Review(ctx context.Context, request *v1beta1.AdmissionRequest, mutate bool) *v1beta1.AdmissionResponse {
+	response := &v1beta1.AdmissionResponse{Allowed: true}
+	if request.Operation != v1beta1.Create && request.Operation != v1beta1.Update {
+		return response
+	}
+	object := admittedObject{}
+	if err := json.Unmarshal(request.Object.Raw, &object); err != nil || len(object.Metadata.OwnerReferences) > 0 {
+		return response
+	}
+	workloads, err := models.ParseManifest(request.Object.Raw)
+	if err != nil {
+		return response
+	}
+	monthlyCost := estimateMonthlyCost(ctx, workloads)
+	return admit(response, object, request.Namespace, monthlyCost, mutate)
+}
+
+func admit(response *v1beta1.AdmissionResponse, object admittedObject, namespace string, monthlyCost float64, mutate bool) *v1beta1.AdmissionResponse {
+	annotations := map[string]string{MonthlyCostAnnotation: strconv.FormatFloat(monthlyCost, 'f', 2, 64)}
+	if limit, isLimited := utils.GetNamespaceCost(limits, namespace); isLimited && monthlyCost > limit {
+		message := fmt.Sprintf("estimated monthly cost %.2f exceeds limit %.2f of namespace %s", monthlyCost, limit, namespace)
+		if denyAboveLimit {
+			response.Allowed = false
+			response.Result = &metav1.Status{
+				Status:  metav1.StatusFailure,
+				Message: message,
+				Reason:  metav1.StatusReasonForbidden,
+				Code:    http.StatusForbidden,
+			}
+			return response
+		}
+		log.Warnf("admitted workload with %s", message)
+		annotations[CostWarningAnnotation] = message
+	}
+	if !mutate {
+		return response
+	}
+	patch, err := json.Marshal(getAnnotationsPatch(object.Metadata.Annotations, annotations))
+	if err != nil {
+		log.Errorf("unable to encode annotations patch: %v", err)
+		return response
+	}
+	patchType := v1beta1.PatchTypeJSONPatch
+	response.Patch = patch
+	response.PatchType = &patchType
+	return response
+}
+
+// getAnnotationsPatch returns JSON patch operations adding the annotations to the existing annotations
+func getAnnotationsPatch(existing, annotations map[string]string) []patchOperation {
+	if existing == nil {
+		return []patchOperation{{Op: "add", Path: "/metadata/annotations", Value: annotations}}
+	}
+	keys := []string{}
+	for key := range annotations {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	patch := []patchOperation{}
+	for _, key := range keys {
+		path := "/metadata/annotations/" + strings.NewReplacer("~", "~0", "/", "~1").Replace(key)
+		patch = append(patch, patchOperation{Op: "add", Path: path, Value: annotations[key]})
+	}
+	return patch
+}
@@ -0,0 +1,114 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package admission
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/vmware/purser/pkg/controller/dgraph/models"
+	"github.com/vmware/purser/test/utils"
+
+	"k8s.io/api/admission/v1beta1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const deployment = `{"kind": "Deployment", "metadata": {"name": "web", "namespace": "dev", "annotations": {"team": "shop"}},
+	"spec": {"replicas": 2, "template": {"spec": {"containers": [{"name": "app", "resources": {"requests": {"cpu": "1"}}}]}}}}`
+
+func newRequest(object string) *v1beta1.AdmissionRequest {
+	return &v1beta1.AdmissionRequest{
+		UID:       "uid",
+		Namespace: "dev",
+		Operation: v1beta1.Create,
+		Object:    runtime.RawExtension{Raw: []byte(object)},
+	}
+}
+
+func stubEstimate(t *testing.T, monthlyCost float64) {
+	estimateMonthlyCost = func(ctx context.Context, workloads []models.ManifestWorkload) float64 {
+		utils.Equals(t, 1, len(workloads))
+		return monthlyCost
+	}
+}
+
+func TestConfigure(t *testing.T) {
+	utils.Ok(t, Configure("dev=100, *=500.5", true))
+	utils.Equals(t, map[string]float64{"dev": 100, "*": 500.5}, limits)
+	utils.Assert(t, Configure("dev", false) != nil, "limit without cost is accepted")
+	utils.Assert(t, Configure("dev=-1", false) != nil, "negative limit is accepted")
+}
+
+func TestReview(t *testing.T) {
+	utils.Ok(t, Configure("dev=100", false))
+	stubEstimate(t, 72)
+
+	response := Review(context.Background(), newRequest(deployment), true)
+	utils.Assert(t, response.Allowed, "workload below limit is denied")
+	utils.Equals(t, v1beta1.PatchTypeJSONPatch, *response.PatchType)
+	patch := []patchOperation{}
+	utils.Ok(t, json.Unmarshal(response.Patch, &patch))
+	utils.Equals(t, []patchOperation{
+		{Op: "add", Path: "/metadata/annotations/purser.vmware.com~1monthly-cost", Value: "72.00"},
+	}, patch)
+
+	response = Review(context.Background(), newRequest(deployment), false)
+	utils.Assert(t, response.Allowed, "workload below limit is denied")
+	utils.Assert(t, response.Patch == nil, "validating review patches workload")
+}
+
+func TestReviewAboveLimit(t *testing.T) {
+	utils.Ok(t, Configure("*=50", false))
+	stubEstimate(t, 72)
+
+	response := Review(context.Background(), newRequest(deployment), true)
+	utils.Assert(t, response.Allowed, "workload above limit is denied without deny")
+	patch := []patchOperation{}
+	utils.Ok(t, json.Unmarshal(response.Patch, &patch))
+	utils.Equals(t, 2, len(patch))
+	utils.Equals(t, "/metadata/annotations/purser.vmware.com~1cost-warning", patch[0].Path)
+	utils.Equals(t, "estimated monthly cost 72.00 exceeds limit 50.00 of namespace dev", patch[0].Value)
+
+	utils.Ok(t, Configure("*=50", true))
+	response = Review(context.Background(), newRequest(deployment), true)
+	utils.Assert(t, !response.Allowed, "workload above limit is allowed with deny")
+	utils.Equals(t, int32(403), response.Result.Code)
+}
+
+func TestReviewSkipsOwnedAndOtherObjects(t *testing.T) {
+	utils.Ok(t, Configure("*=0", true))
+	estimateMonthlyCost = func(ctx context.Context, workloads []models.ManifestWorkload) float64 {
+		t.Fatal("cost is estimated")
+		return 0
+	}
+
+	pod := `{"kind": "Pod", "metadata": {"name": "web-1", "ownerReferences": [{"kind": "ReplicaSet", "name": "web"}]},
+		"spec": {"containers": [{"name": "app"}]}}`
+	utils.Assert(t, Review(context.Background(), newRequest(pod), true).Allowed, "pod of a replicaset is denied")
+	utils.Assert(t, Review(context.Background(), newRequest(`{"kind": "Service", "metadata": {"name": "web"}}`), true).Allowed, "service is denied")
+
+	request := newRequest(deployment)
+	request.Operation = v1beta1.Delete
+	utils.Assert(t, Review(context.Background(), request, true).Allowed, "deletion is denied")
+}
+
+func TestGetAnnotationsPatch(t *testing.T) {
+	annotations := map[string]string{MonthlyCostAnnotation: "1.00"}
+	utils.Equals(t, []patchOperation{{Op: "add", Path: "/metadata/annotations", Value: annotations}}, getAnnotationsPatch(nil, annotations))
+}
@@ -0,0 +1,223 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package alerting
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	alertrule_v1 "github.com/vmware/purser/pkg/apis/alertrule/v1"
+	alertrule_client "github.com/vmware/purser/pkg/client/clientset/typed/alertrule/v1"
+	"github.com/vmware/purser/pkg/controller/dgraph/models"
+	"github.com/vmware/purser/pkg/controller/notification"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Comparison operators of alert rules
+const (
+	GreaterThan         = ">"
+	GreaterThanOrEquals = ">="
+	LessThan            = "<"
+	LessThanOrEquals    = "<="
+)
+
+// Source of notifications of alerts
+const Source = "purser"
+
+// ruleState tracks since when the condition of a rule holds and since when it is firing
+type ruleState struct {
+	pendingSince time.Time
+	firingSince  time.Time
+	rule         alertrule_v1.AlertRule
+	value        float64
+}
+
+var (
+	statesMu sync.Mutex
+	states   = make(map[string]*ruleState)
+
+	storeAlert = models.StoreAlert
+	dispatch   = notification.Dispatch
+)
+
+// EvaluateRules evaluates all alert rules, stores alerts which started firing or resolved in dgraph
+// and dispatches them. Alerts of deleted rules are resolved.
+func EvaluateRules(client *alertrule_client.AlertRuleClient) {
+	rules, err := client.List(meta_v1.ListOptions{})
+	if err != nil {
+		log.Errorf("unable to list alert rules: %v", err)
+		return
+	}
+	evaluate(rules.Items, time.Now())
+}
+
+func evaluate(rules []alertrule_v1.AlertRule, now time.Time) {
+	statesMu.Lock()
+	defer statesMu.Unlock()
+
+	present := make(map[string]bool)
+	for _, rule := range rules {
+		present[rule.Name] = true
+		if err := evaluateRule(rule, now); err != nil {
+			log.Errorf("unable to evaluate alert rule: %s, err: %v", rule.Name, err)
+		}
+	}
+	for name, state := range states {
+		if !present[name] {
+			if !state.firingSince.IsZero() {
+				notify(state, notification.Resolved, now)
+			}
+			delete(states, name)
+		}
+	}
+}
+
+func evaluateRule(rule alertrule_v1.AlertRule, now time.Time) error {
+	setDefaults(&rule.Spec)
+	duration, err := getDuration(rule.Spec.Duration)
+	if err != nil {
+		return err
+	}
+	value, err := getMetricValue(rule.Spec)
+	if err != nil {
+		return err
+	}
+	holds, err := compare(rule.Spec.Operator, value, rule.Spec.Threshold)
+	if err != nil {
+		return err
+	}
+
+	state, isPresent := states[rule.Name]
+	if !isPresent {
+		state = &ruleState{}
+		states[rule.Name] = state
+	}
+	state.rule = rule
+	state.value = value
+	if transition := state.update(holds, duration, now); transition != "" {
+		notify(state, transition, now)
+	}
+	return nil
+}
+
+// update moves the state for the current evaluation and returns Firing if the rule started firing,
+// Resolved if it stopped firing and empty string otherwise
+func (s *ruleState) update(holds bool, duration time.Duration, now time.Time) string {
+	if !holds {
+		s.pendingSince = time.Time{}
+		if !s.firingSince.IsZero() {
+			return notification.Resolved
+		}
+		return ""
+	}
+	if s.pendingSince.IsZero() {
+		s.pendingSince = now
+	}
+	if s.firingSince.IsZero() && now.Sub(s.pendingSince) >= duration {
+		s.firingSince = now
+		return notification.Firing
+	}
+	return ""
+}
+
+// notify stores the alert of the rule in dgraph and dispatches it
+func notify(s *ruleState, status string, now time.Time) {
+	spec := s.rule.Spec
+	alert := models.Alert{
+		Rule:      s.rule.Name,
+		Metric:    spec.Metric,
+		Scope:     spec.Scope,
+		Resource:  spec.Name,
+		Severity:  spec.Severity,
+		State:     status,
+		Value:     s.value,
+		Threshold: spec.Threshold,
+		Summary:   spec.Summary,
+	}
+	if status == notification.Resolved {
+		alert.EndTime = now.Format(time.RFC3339)
+	}
+	if _, err := storeAlert(alert, s.firingSince); err != nil {
+		log.Errorf("unable to store alert of rule: %s, err: %v", s.rule.Name, err)
+	}
+
+	resource := spec.Scope
+	if spec.Name != "" {
+		resource += " " + spec.Name
+	}
+	dispatch(notification.Notification{
+		Title:    fmt.Sprintf("%s: %s of %s is %.2f (%s %.2f)", s.rule.Name, spec.Metric, resource, s.value, spec.Operator, spec.Threshold),
+		Summary:  spec.Summary,
+		Severity: spec.Severity,
+		Status:   status,
+		DedupKey: "purser-alert-" + s.rule.Name,
+		Source:   Source,
+		Time:     now,
+		Details: map[string]interface{}{
+			"rule":      s.rule.Name,
+			"metric":    spec.Metric,
+			"scope":     spec.Scope,
+			"resource":  spec.Name,
+			"value":     s.value,
+			"threshold": spec.Threshold,
+			"since":     s.firingSince.Format(time.RFC3339),
+		},
+	})
+	if status == notification.Resolved {
+		s.firingSince = time.Time{}
+	}
+}
+
+func setDefaults(spec *alertrule_v1.AlertRuleSpec) {
+	if spec.Scope == "" {
+		spec.Scope = alertrule_v1.ClusterScope
+	}
+	if spec.Operator == "" {
+		spec.Operator = GreaterThan
+	}
+	if spec.Severity == "" {
+		spec.Severity = alertrule_v1.SeverityWarning
+	}
+}
+
+func getDuration(duration string) (time.Duration, error) {
+	if duration == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(duration)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration: %s, %v", duration, err)
+	}
+	return d, nil
+}
+
+func compare(operator string, value, threshold float64) (bool, error) {
+	switch operator {
+	case GreaterThan:
+		return value > threshold, nil
+	case GreaterThanOrEquals:
+		return value >= threshold, nil
+	case LessThan:
+		return value < threshold, nil
+	case LessThanOrEquals:
+		return value <= threshold, nil
+	}
+	return false, fmt.Errorf("unknown operator: %s", operator)
+}
@@ -0,0 +1,102 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package alerting
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dgraph-io/dgo/protos/api"
+	alertrule_v1 "github.com/vmware/purser/pkg/apis/alertrule/v1"
+	"github.com/vmware/purser/pkg/controller/dgraph/models"
+	"github.com/vmware/purser/pkg/controller/dgraph/models/query"
+	"github.com/vmware/purser/pkg/controller/notification"
+	"github.com/vmware/purser/test/utils"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func mockAlerting(cost float64) (*[]models.Alert, *[]notification.Notification) {
+	alerts := []models.Alert{}
+	notifications := []notification.Notification{}
+	storeAlert = func(alert models.Alert, startsAt time.Time) (*api.Assigned, error) {
+		alerts = append(alerts, alert)
+		return nil, nil
+	}
+	dispatch = func(n notification.Notification) {
+		notifications = append(notifications, n)
+	}
+	retrieveClusterMetrics = func(ctx context.Context, view string) query.JSONDataWrapper {
+		return query.JSONDataWrapper{Data: query.ParentWrapper{CPUCost: cost}}
+	}
+	states = make(map[string]*ruleState)
+	return &alerts, &notifications
+}
+
+// TestRuleStateUpdate ...
+func TestRuleStateUpdate(t *testing.T) {
+	now := time.Date(2018, 10, 10, 10, 0, 0, 0, time.UTC)
+	state := &ruleState{}
+	utils.Equals(t, "", state.update(true, 10*time.Minute, now))
+	utils.Equals(t, "", state.update(true, 10*time.Minute, now.Add(5*time.Minute)))
+	utils.Equals(t, notification.Firing, state.update(true, 10*time.Minute, now.Add(10*time.Minute)))
+	utils.Equals(t, "", state.update(true, 10*time.Minute, now.Add(15*time.Minute)))
+	utils.Equals(t, notification.Resolved, state.update(false, 10*time.Minute, now.Add(20*time.Minute)))
+
+	state = &ruleState{}
+	utils.Equals(t, "", state.update(true, 10*time.Minute, now))
+	utils.Equals(t, "", state.update(false, 10*time.Minute, now.Add(5*time.Minute)))
+	utils.Equals(t, "", state.update(true, 10*time.Minute, now.Add(10*time.Minute)))
+}
+
+// TestEvaluate ...
+func TestEvaluate(t *testing.T) {
+	alerts, notifications := mockAlerting(120)
+	rule := alertrule_v1.AlertRule{
+		ObjectMeta: meta_v1.ObjectMeta{Name: "high-cost"},
+		Spec:       alertrule_v1.AlertRuleSpec{Metric: alertrule_v1.CostMetric, Threshold: 100, Severity: alertrule_v1.SeverityCritical},
+	}
+	now := time.Now()
+	evaluate([]alertrule_v1.AlertRule{rule}, now)
+	utils.Equals(t, 1, len(*alerts))
+	utils.Equals(t, notification.Firing, (*alerts)[0].State)
+	utils.Equals(t, 120.0, (*alerts)[0].Value)
+	utils.Equals(t, "purser-alert-high-cost", (*notifications)[0].DedupKey)
+	utils.Equals(t, alertrule_v1.SeverityCritical, (*notifications)[0].Severity)
+
+	evaluate([]alertrule_v1.AlertRule{rule}, now.Add(time.Minute))
+	utils.Equals(t, 1, len(*notifications))
+
+	// alert of deleted rule is resolved
+	evaluate(nil, now.Add(2*time.Minute))
+	utils.Equals(t, 2, len(*notifications))
+	utils.Equals(t, notification.Resolved, (*notifications)[1].Status)
+	utils.Assert(t, (*alerts)[1].EndTime != "", "end time of resolved alert not set")
+}
+
+// TestEvaluateInvalidRule ...
+func TestEvaluateInvalidRule(t *testing.T) {
+	alerts, _ := mockAlerting(120)
+	rules := []alertrule_v1.AlertRule{
+		{ObjectMeta: meta_v1.ObjectMeta{Name: "a"}, Spec: alertrule_v1.AlertRuleSpec{Metric: alertrule_v1.CostMetric, Operator: "!="}},
+		{ObjectMeta: meta_v1.ObjectMeta{Name: "b"}, Spec: alertrule_v1.AlertRuleSpec{Metric: alertrule_v1.CostMetric, Duration: "soon"}},
+		{ObjectMeta: meta_v1.ObjectMeta{Name: "c"}, Spec: alertrule_v1.AlertRuleSpec{Metric: alertrule_v1.ProjectedCostMetric}},
+	}
+	evaluate(rules, time.Now())
+	utils.Equals(t, 0, len(*alerts))
+}
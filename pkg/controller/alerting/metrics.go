@@ -0,0 +1,125 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package alerting
+
+import (
+	"context"
+	"fmt"
+
+	alertrule_v1 "github.com/vmware/purser/pkg/apis/alertrule/v1"
+	"github.com/vmware/purser/pkg/controller/dgraph"
+	"github.com/vmware/purser/pkg/controller/dgraph/models"
+	"github.com/vmware/purser/pkg/controller/dgraph/models/query"
+)
+
+var (
+	retrieveClusterMetrics  = query.RetrieveClusterMetrics
+	retrieveResourceMetrics = func(ctx context.Context, r *query.Resource) query.JSONDataWrapper {
+		return r.RetrieveResourceMetrics(ctx)
+	}
+	retrieveGroupsData = query.RetrieveGroupsData
+)
+
+// getMetricValue returns the current value of the metric of the resource given in the spec
+func getMetricValue(spec alertrule_v1.AlertRuleSpec) (float64, error) {
+	switch spec.Scope {
+	case alertrule_v1.ClusterScope:
+		return getClusterMetricValue(spec.Metric)
+	case alertrule_v1.NamespaceScope:
+		return getNamespaceMetricValue(spec.Name, spec.Metric)
+	case alertrule_v1.GroupScope:
+		return getGroupMetricValue(spec.Name, spec.Metric)
+	}
+	return 0, fmt.Errorf("unknown scope: %s", spec.Scope)
+}
+
+func getClusterMetricValue(metric string) (float64, error) {
+	allocation := retrieveClusterMetrics(dgraph.WithOwnCluster(context.Background()), query.Logical).Data
+	switch metric {
+	case alertrule_v1.CostMetric:
+		return getTotalCost(allocation), nil
+	case alertrule_v1.CarbonMetric:
+		carbon := 0.0
+		for _, child := range allocation.Children {
+			carbon += child.Carbon
+		}
+		return carbon, nil
+	}
+
+	capacity := retrieveClusterMetrics(dgraph.WithOwnCluster(context.Background()), query.Physical).Data
+	switch metric {
+	case alertrule_v1.CPUEfficiencyMetric:
+		return getRatio(allocation.CPU, capacity.CPU), nil
+	case alertrule_v1.MemoryEfficiencyMetric:
+		return getRatio(allocation.Memory, capacity.Memory), nil
+	case alertrule_v1.IdleCostMetric:
+		return getTotalCost(capacity) - getTotalCost(allocation), nil
+	}
+	return 0, fmt.Errorf("metric: %s is not supported for scope: %s", metric, alertrule_v1.ClusterScope)
+}
+
+func getNamespaceMetricValue(name, metric string) (float64, error) {
+	if name == "" {
+		return 0, fmt.Errorf("name of namespace is not given")
+	}
+	resource := &query.Resource{Check: query.NamespaceCheck, Type: query.NamespaceType, Name: "namespace-" + name}
+	data := retrieveResourceMetrics(dgraph.WithOwnCluster(context.Background()), resource).Data
+	switch metric {
+	case alertrule_v1.CostMetric:
+		return getTotalCost(data), nil
+	case alertrule_v1.CarbonMetric:
+		return data.Carbon, nil
+	}
+	return 0, fmt.Errorf("metric: %s is not supported for scope: %s", metric, alertrule_v1.NamespaceScope)
+}
+
+func getGroupMetricValue(name, metric string) (float64, error) {
+	groups, err := retrieveGroupsData(dgraph.WithOwnCluster(context.Background()))
+	if err != nil {
+		return 0, err
+	}
+	var group *models.Group
+	for i := range groups {
+		if groups[i].Name == name {
+			group = &groups[i]
+		}
+	}
+	if group == nil {
+		return 0, fmt.Errorf("group: %s is not found", name)
+	}
+	switch metric {
+	case alertrule_v1.CostMetric:
+		return group.MtdCost, nil
+	case alertrule_v1.ProjectedCostMetric:
+		return group.ProjectedCost, nil
+	case alertrule_v1.CarbonMetric:
+		return group.MtdCarbon, nil
+	}
+	return 0, fmt.Errorf("metric: %s is not supported for scope: %s", metric, alertrule_v1.GroupScope)
+}
+
+func getTotalCost(data query.ParentWrapper) float64 {
+	return data.CPUCost + data.MemoryCost + data.StorageCost
+}
+
+func getRatio(numerator, denominator float64) float64 {
+	if denominator == 0 {
+		return 0
+	}
+	return numerator / denominator
+}
@@ -0,0 +1,250 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package auth
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	auth_v1 "k8s.io/api/authentication/v1"
+	authz_v1 "k8s.io/api/authorization/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Roles of users of the API
+const (
+	// Viewer can query costs, hierarchies and interactions
+	Viewer = "viewer"
+	// Admin can also create and delete groups and run admin operations
+	Admin = "admin"
+)
+
+// AuthenticatedGroup is the group of all authenticated users, viewers by default
+const AuthenticatedGroup = "system:authenticated"
+
+// ClusterScope is the scope of costs of the whole cluster(ex: nodes and idle cost), other scopes are namespaces
+const ClusterScope = ""
+
+// DefaultCacheTTL is the duration for which results of token reviews are reused
+const DefaultCacheTTL = time.Minute
+
+// Identity of the user of a token
+type Identity struct {
+	Username string
+	Groups   []string
+	Role     string
+}
+
+type cachedIdentity struct {
+	identity Identity
+	expiry   time.Time
+}
+
+var (
+	mu       sync.Mutex
+	admins   []string
+	viewers  []string
+	cacheTTL time.Duration
+	cache    = make(map[string]cachedIdentity)
+
+	accessCache   = make(map[string]cachedAccess)
+	scopedViewers bool
+)
+
+type cachedAccess struct {
+	isAllowed bool
+	expiry    time.Time
+}
+
+// reviewToken validates the token against the kubernetes API, it is nil until Configure is called
+var reviewToken func(token string) (auth_v1.UserInfo, bool, error)
+
+// reviewAccess checks the attributes for the user with a SubjectAccessReview, it is nil until Configure is called
+var reviewAccess func(identity Identity, attributes authz_v1.ResourceAttributes) (bool, error)
+
+// Configure validates tokens with TokenReviews of the kubernetes API, users(or groups) in admins are admins and
+// those in viewers are viewers
+func Configure(kubeClient kubernetes.Interface, adminSubjects, viewerSubjects []string, ttl time.Duration) {
+	reviewToken = func(token string) (auth_v1.UserInfo, bool, error) {
+		review, err := kubeClient.AuthenticationV1().TokenReviews().Create(&auth_v1.TokenReview{
+			Spec: auth_v1.TokenReviewSpec{Token: token},
+		})
+		if err != nil {
+			return auth_v1.UserInfo{}, false, err
+		}
+		if review.Status.Error != "" {
+			log.Debugf("token review failed: %s", review.Status.Error)
+		}
+		return review.Status.User, review.Status.Authenticated, nil
+	}
+	reviewAccess = func(identity Identity, attributes authz_v1.ResourceAttributes) (bool, error) {
+		review, err := kubeClient.AuthorizationV1().SubjectAccessReviews().Create(&authz_v1.SubjectAccessReview{
+			Spec: authz_v1.SubjectAccessReviewSpec{
+				User:               identity.Username,
+				Groups:             identity.Groups,
+				ResourceAttributes: &attributes,
+			},
+		})
+		if err != nil {
+			return false, err
+		}
+		return review.Status.Allowed, nil
+	}
+	setRoles(adminSubjects, viewerSubjects, ttl)
+	log.Infof("token authentication of API enabled, admins: %v, viewers: %v", admins, viewers)
+}
+
+func setRoles(adminSubjects, viewerSubjects []string, ttl time.Duration) {
+	mu.Lock()
+	defer mu.Unlock()
+	admins, viewers, cacheTTL = adminSubjects, viewerSubjects, ttl
+	cache = make(map[string]cachedIdentity)
+	accessCache = make(map[string]cachedAccess)
+}
+
+// SetScopedViewers restricts viewers to endpoints of the cluster and namespace scopes if scoped is true
+func SetScopedViewers(scoped bool) {
+	mu.Lock()
+	defer mu.Unlock()
+	scopedViewers = scoped
+}
+
+// HasScopedViewers returns true if viewers can only use endpoints of the cluster and namespace scopes
+func HasScopedViewers() bool {
+	mu.Lock()
+	defer mu.Unlock()
+	return scopedViewers
+}
+
+// IsConfigured returns true if bearer tokens can be validated
+func IsConfigured() bool {
+	return reviewToken != nil
+}
+
+// Authenticate returns the identity of the user of the token, the role is empty if the user is neither an admin
+// nor a viewer
+func Authenticate(token string) (Identity, error) {
+	key := fmt.Sprintf("%x", sha256.Sum256([]byte(token)))
+	now := time.Now()
+	mu.Lock()
+	cached, isCached := cache[key]
+	mu.Unlock()
+	if isCached && now.Before(cached.expiry) {
+		return cached.identity, nil
+	}
+
+	user, authenticated, err := reviewToken(token)
+	if err != nil {
+		return Identity{}, fmt.Errorf("unable to review token: %v", err)
+	}
+	if !authenticated {
+		return Identity{}, fmt.Errorf("invalid token")
+	}
+	identity := Identity{Username: user.Username, Groups: user.Groups, Role: getRole(user)}
+
+	mu.Lock()
+	defer mu.Unlock()
+	for k, c := range cache {
+		if now.After(c.expiry) {
+			delete(cache, k)
+		}
+	}
+	cache[key] = cachedIdentity{identity: identity, expiry: now.Add(cacheTTL)}
+	return identity, nil
+}
+
+// CanView returns true if the user can view costs of the scope, a namespace or ClusterScope. Admins can view all
+// scopes, viewers can view namespaces in which kubernetes RBAC allows them to get pods and the cluster if it allows
+// them to list nodes, so teams see the costs of what they can already see in the cluster.
+func CanView(identity Identity, scope string) (bool, error) {
+	if identity.Role == Admin {
+		return true, nil
+	}
+	if identity.Role != Viewer || reviewAccess == nil {
+		return false, nil
+	}
+	attributes := authz_v1.ResourceAttributes{Namespace: scope, Verb: "get", Resource: "pods"}
+	if scope == ClusterScope {
+		attributes = authz_v1.ResourceAttributes{Verb: "list", Resource: "nodes"}
+	}
+
+	key := identity.Username + "\x00" + strings.Join(identity.Groups, ",") + "\x00" + scope
+	now := time.Now()
+	mu.Lock()
+	cached, isCached := accessCache[key]
+	mu.Unlock()
+	if isCached && now.Before(cached.expiry) {
+		return cached.isAllowed, nil
+	}
+
+	isAllowed, err := reviewAccess(identity, attributes)
+	if err != nil {
+		return false, fmt.Errorf("unable to review access of %s to scope: %q, err: %v", identity.Username, scope, err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	for k, c := range accessCache {
+		if now.After(c.expiry) {
+			delete(accessCache, k)
+		}
+	}
+	accessCache[key] = cachedAccess{isAllowed: isAllowed, expiry: now.Add(cacheTTL)}
+	return isAllowed, nil
+}
+
+// GetBearerToken returns the token of the Authorization header(Bearer <token>), empty if there is none
+func GetBearerToken(authorization string) string {
+	parts := strings.SplitN(strings.TrimSpace(authorization), " ", 2)
+	if len(parts) != 2 || !strings.EqualFold(parts[0], "Bearer") {
+		return ""
+	}
+	return strings.TrimSpace(parts[1])
+}
+
+// getRole returns admin if the username or a group of the user is an admin subject, else viewer if it is a viewer
+// subject
+func getRole(user auth_v1.UserInfo) string {
+	mu.Lock()
+	defer mu.Unlock()
+	if matchesAny(user, admins) {
+		return Admin
+	}
+	if matchesAny(user, viewers) {
+		return Viewer
+	}
+	return ""
+}
+
+func matchesAny(user auth_v1.UserInfo, subjects []string) bool {
+	for _, subject := range subjects {
+		if subject == user.Username {
+			return true
+		}
+		for _, group := range user.Groups {
+			if subject == group {
+				return true
+			}
+		}
+	}
+	return false
+}
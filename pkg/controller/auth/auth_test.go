@@ -0,0 +1,142 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package auth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/vmware/purser/test/utils"
+	auth_v1 "k8s.io/api/authentication/v1"
+	authz_v1 "k8s.io/api/authorization/v1"
+)
+
+func mockReviewToken(reviews *int) {
+	users := map[string]auth_v1.UserInfo{
+		"admin-token": {Username: "system:serviceaccount:purser:purser-admin", Groups: []string{"system:serviceaccounts", "system:authenticated"}},
+		"dev-token":   {Username: "system:serviceaccount:dev:default", Groups: []string{"system:serviceaccounts:dev", "system:authenticated"}},
+		"ops-token":   {Username: "jane", Groups: []string{"ops", "system:authenticated"}},
+		"robot-token": {Username: "robot", Groups: []string{}},
+	}
+	reviewToken = func(token string) (auth_v1.UserInfo, bool, error) {
+		*reviews++
+		user, isPresent := users[token]
+		return user, isPresent, nil
+	}
+}
+
+// TestAuthenticate ...
+func TestAuthenticate(t *testing.T) {
+	reviews := 0
+	mockReviewToken(&reviews)
+	defer func() { reviewToken = nil }()
+	setRoles([]string{"system:serviceaccount:purser:purser-admin", "ops"}, []string{AuthenticatedGroup}, time.Minute)
+
+	identity, err := Authenticate("admin-token")
+	utils.Ok(t, err)
+	utils.Equals(t, Admin, identity.Role)
+	utils.Equals(t, "system:serviceaccount:purser:purser-admin", identity.Username)
+
+	identity, err = Authenticate("ops-token")
+	utils.Ok(t, err)
+	utils.Equals(t, Admin, identity.Role)
+
+	identity, err = Authenticate("dev-token")
+	utils.Ok(t, err)
+	utils.Equals(t, Viewer, identity.Role)
+
+	identity, err = Authenticate("robot-token")
+	utils.Ok(t, err)
+	utils.Equals(t, "", identity.Role)
+
+	_, err = Authenticate("expired-token")
+	utils.Assert(t, err != nil, "invalid token authenticated")
+
+	reviews = 0
+	_, err = Authenticate("dev-token")
+	utils.Ok(t, err)
+	utils.Equals(t, 0, reviews)
+}
+
+// TestAuthenticateWithoutCache ...
+func TestAuthenticateWithoutCache(t *testing.T) {
+	reviews := 0
+	mockReviewToken(&reviews)
+	defer func() { reviewToken = nil }()
+	setRoles(nil, []string{"system:serviceaccounts:dev"}, 0)
+
+	for i := 0; i < 2; i++ {
+		identity, err := Authenticate("dev-token")
+		utils.Ok(t, err)
+		utils.Equals(t, Viewer, identity.Role)
+	}
+	utils.Equals(t, 2, reviews)
+
+	identity, err := Authenticate("ops-token")
+	utils.Ok(t, err)
+	utils.Equals(t, "", identity.Role)
+}
+
+// TestCanView ...
+func TestCanView(t *testing.T) {
+	reviews := 0
+	reviewAccess = func(identity Identity, attributes authz_v1.ResourceAttributes) (bool, error) {
+		reviews++
+		if attributes.Resource == "nodes" {
+			return identity.Username == "jane", nil
+		}
+		return attributes.Namespace == "dev" && attributes.Verb == "get" && attributes.Resource == "pods", nil
+	}
+	defer func() { reviewAccess = nil }()
+	setRoles(nil, nil, time.Minute)
+
+	dev := Identity{Username: "system:serviceaccount:dev:default", Groups: []string{"system:serviceaccounts:dev"}, Role: Viewer}
+	isAllowed, err := CanView(dev, "dev")
+	utils.Ok(t, err)
+	utils.Assert(t, isAllowed, "viewer can't view its namespace")
+	isAllowed, err = CanView(dev, "prod")
+	utils.Ok(t, err)
+	utils.Assert(t, !isAllowed, "viewer can view other namespace")
+	isAllowed, err = CanView(dev, ClusterScope)
+	utils.Ok(t, err)
+	utils.Assert(t, !isAllowed, "viewer without access to nodes can view cluster")
+
+	isAllowed, err = CanView(Identity{Username: "jane", Role: Viewer}, ClusterScope)
+	utils.Ok(t, err)
+	utils.Assert(t, isAllowed, "viewer with access to nodes can't view cluster")
+
+	isAllowed, err = CanView(Identity{Username: "robot"}, "dev")
+	utils.Ok(t, err)
+	utils.Assert(t, !isAllowed, "user without role can view namespace")
+
+	reviews = 0
+	isAllowed, err = CanView(Identity{Username: "admin", Role: Admin}, ClusterScope)
+	utils.Ok(t, err)
+	utils.Assert(t, isAllowed, "admin can't view cluster")
+	_, err = CanView(dev, "dev")
+	utils.Ok(t, err)
+	utils.Equals(t, 0, reviews)
+}
+
+// TestGetBearerToken ...
+func TestGetBearerToken(t *testing.T) {
+	utils.Equals(t, "abc.def", GetBearerToken("Bearer abc.def"))
+	utils.Equals(t, "abc.def", GetBearerToken("bearer  abc.def "))
+	utils.Equals(t, "", GetBearerToken("Basic dXNlcjpwYXNz"))
+	utils.Equals(t, "", GetBearerToken(""))
+}
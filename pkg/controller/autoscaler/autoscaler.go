@@ -0,0 +1,109 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package autoscaler
+
+import (
+	"regexp"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/vmware/purser/pkg/controller/dgraph/models"
+
+	api_v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Reasons of pod events reporting scale ups
+const (
+	// TriggeredScaleUpReason is the reason of events cluster-autoscaler records on pods which triggered a scale up,
+	// ex: pod triggered scale-up: [{eks-workers 1->2 (max: 10)}]
+	TriggeredScaleUpReason = "TriggeredScaleUp"
+	// NominatedReason is the reason of events karpenter records on pods for which it launches a node,
+	// ex: Pod should schedule on: nodeclaim/default-x2bkc, node/ip-10-0-1-10.ec2.internal
+	NominatedReason = "Nominated"
+)
+
+var (
+	nodeGroupRegex     = regexp.MustCompile(`\[\{(\S+) \d+->\d+`)
+	nominatedNodeRegex = regexp.MustCompile(`(?:node|nodeclaim|machine)/([^\s,]+)`)
+)
+
+// CollectAndStoreScaleUps stores scale ups reported in events of pods. Events are kept by kubernetes for an hour by
+// default so this has to run more often than that.
+func CollectAndStoreScaleUps(client *kubernetes.Clientset) {
+	count := 0
+	for _, reason := range []string{TriggeredScaleUpReason, NominatedReason} {
+		events, err := client.CoreV1().Events(metav1.NamespaceAll).List(metav1.ListOptions{
+			FieldSelector: "involvedObject.kind=Pod,reason=" + reason,
+		})
+		if err != nil {
+			log.Errorf("failed to retrieve %s events: %v", reason, err)
+			continue
+		}
+		for _, event := range events.Items {
+			scaleUp, isScaleUp := parseScaleUpEvent(event)
+			if !isScaleUp {
+				continue
+			}
+			podXid := event.InvolvedObject.Namespace + ":" + event.InvolvedObject.Name
+			if err := models.StoreScaleUp(scaleUp, string(event.UID), podXid); err != nil {
+				log.Errorf("unable to store scale up of pod: %s, err: %v", podXid, err)
+				continue
+			}
+			count++
+		}
+	}
+	log.Debugf("processed (%d) scale up events", count)
+}
+
+// parseScaleUpEvent returns the scale up reported by a cluster-autoscaler or karpenter event of a pod
+func parseScaleUpEvent(event api_v1.Event) (models.ScaleUp, bool) {
+	scaleUp := models.ScaleUp{StartTime: getEventTime(event).Format(time.RFC3339)}
+	switch event.Reason {
+	case TriggeredScaleUpReason:
+		scaleUp.Source = models.ClusterAutoscalerSource
+		if match := nodeGroupRegex.FindStringSubmatch(event.Message); match != nil {
+			scaleUp.Target = match[1]
+		}
+	case NominatedReason:
+		// nominations of other schedulers don't launch nodes
+		if event.Source.Component != models.KarpenterSource && event.ReportingController != models.KarpenterSource {
+			return scaleUp, false
+		}
+		scaleUp.Source = models.KarpenterSource
+		// node name is preferred to node claim name when both are given
+		for _, match := range nominatedNodeRegex.FindAllStringSubmatch(event.Message, -1) {
+			scaleUp.Target = match[1]
+		}
+	default:
+		return scaleUp, false
+	}
+	return scaleUp, true
+}
+
+// getEventTime returns the first time the event happened, events recorded by newer clients only have event time
+func getEventTime(event api_v1.Event) time.Time {
+	if !event.FirstTimestamp.IsZero() {
+		return event.FirstTimestamp.Time
+	}
+	if !event.EventTime.IsZero() {
+		return event.EventTime.Time
+	}
+	return event.CreationTimestamp.Time
+}
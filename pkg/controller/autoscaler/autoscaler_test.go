@@ -0,0 +1,53 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package autoscaler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/vmware/purser/pkg/controller/dgraph/models"
+	"github.com/vmware/purser/test/utils"
+
+	api_v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestParseScaleUpEvent(t *testing.T) {
+	firstTime := metav1.NewTime(time.Date(2018, 10, 10, 10, 0, 0, 0, time.UTC))
+
+	scaleUp, isScaleUp := parseScaleUpEvent(api_v1.Event{
+		Reason:         TriggeredScaleUpReason,
+		Message:        "pod triggered scale-up: [{eks-workers 1->2 (max: 10)}]",
+		FirstTimestamp: firstTime,
+	})
+	utils.Assert(t, isScaleUp, "cluster-autoscaler event is not a scale up")
+	utils.Equals(t, models.ScaleUp{Source: models.ClusterAutoscalerSource, Target: "eks-workers", StartTime: "2018-10-10T10:00:00Z"}, scaleUp)
+
+	scaleUp, isScaleUp = parseScaleUpEvent(api_v1.Event{
+		Reason:         NominatedReason,
+		Message:        "Pod should schedule on: nodeclaim/default-x2bkc, node/ip-10-0-1-10.ec2.internal",
+		Source:         api_v1.EventSource{Component: models.KarpenterSource},
+		FirstTimestamp: firstTime,
+	})
+	utils.Assert(t, isScaleUp, "karpenter event is not a scale up")
+	utils.Equals(t, "ip-10-0-1-10.ec2.internal", scaleUp.Target)
+
+	_, isScaleUp = parseScaleUpEvent(api_v1.Event{Reason: NominatedReason, Message: "Pod should schedule on node/a"})
+	utils.Assert(t, !isScaleUp, "nomination of other component is a scale up")
+}
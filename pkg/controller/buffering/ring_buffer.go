@@ -100,6 +100,19 @@ func (r *RingBuffer) RemoveN(n uint32) {
 	}
 }
 
+// Len returns the number of elements in the buffer.
+func (r *RingBuffer) Len() uint32 {
+	r.Mutex.Lock()
+	defer r.Mutex.Unlock()
+
+	return (r.end + r.Size - r.start) % r.Size
+}
+
+// Capacity returns the maximum number of elements the buffer can hold.
+func (r *RingBuffer) Capacity() uint32 {
+	return r.Size - 1
+}
+
 func (r *RingBuffer) isEmpty() bool {
 	return r.start == r.end
 }
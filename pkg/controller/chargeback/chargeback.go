@@ -0,0 +1,151 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package chargeback
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/vmware/purser/pkg/controller/dgraph/models/query"
+)
+
+// Content types of chargeback reports
+const (
+	CSVContentType  = "text/csv; charset=utf-8"
+	JSONContentType = "application/json; charset=UTF-8"
+)
+
+// csvHeader is the header of rows of chargeback reports in csv format
+var csvHeader = []string{"start", "end", "group", "namespace", "workload", "type", "pods", "cpuCost", "memoryCost", "storageCost", "totalCost"}
+
+// ContentType returns the content type of reports in the format(csv or json)
+func ContentType(format string) string {
+	if format == query.CSV {
+		return CSVContentType
+	}
+	return JSONContentType
+}
+
+// FileName returns the name of the downloaded report in the format(ex: chargeback-namespace-2018-10-01-2018-11-01.csv)
+func FileName(report query.ChargebackReport, format string) string {
+	if format != query.CSV {
+		format = query.JSON
+	}
+	return fmt.Sprintf("chargeback-%s-%s-%s.%s", report.GroupBy, getDate(report.Start), getDate(report.End), format)
+}
+
+// Write renders the report in the format, csv reports have a row per workload and json reports are the report itself.
+// Both contain only the fields allowed by the field policy of the API.
+func Write(w io.Writer, report query.ChargebackReport, format string) error {
+	if format == query.CSV {
+		return WriteCSV(w, report)
+	}
+	return writeJSON(w, query.ChargebackReportWrapper{Data: report})
+}
+
+// WriteCSV renders the rows of the report with a header, costs are formatted with full precision. Columns of fields
+// which are not allowed by the field policy of the API are left out.
+func WriteCSV(w io.Writer, report query.ChargebackReport) error {
+	if err := query.FilterResult(&report); err != nil {
+		return err
+	}
+	writer := newColumnWriter(w, csvHeader, func(column string) bool {
+		if column == "start" || column == "end" {
+			return query.IsFieldAllowed("data", column)
+		}
+		return query.IsFieldAllowed("data", "rows", column)
+	})
+	if err := writer.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, row := range report.Rows {
+		record := []string{
+			report.Start,
+			report.End,
+			row.Group,
+			row.Namespace,
+			row.Workload,
+			row.Type,
+			strconv.Itoa(row.Pods),
+			formatCost(row.CPUCost),
+			formatCost(row.MemoryCost),
+			formatCost(row.StorageCost),
+			formatCost(row.TotalCost),
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// writeJSON writes the report without the fields which are not allowed by the field policy of the API
+func writeJSON(w io.Writer, report interface{}) error {
+	data, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+	data, err = query.FilterFields(data)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+// columnWriter writes csv records without the columns whose fields are not allowed by the field policy of the API
+type columnWriter struct {
+	*csv.Writer
+	isAllowed []bool
+}
+
+// newColumnWriter returns a writer of records with the columns of the header, isAllowed checks the field of a column
+// in json reports(ex: data.rows.cpuCost)
+func newColumnWriter(w io.Writer, header []string, isAllowed func(column string) bool) *columnWriter {
+	writer := &columnWriter{Writer: csv.NewWriter(w)}
+	for _, column := range header {
+		writer.isAllowed = append(writer.isAllowed, isAllowed(column))
+	}
+	return writer
+}
+
+func (w *columnWriter) Write(record []string) error {
+	columns := make([]string, 0, len(record))
+	for index, value := range record {
+		if w.isAllowed[index] {
+			columns = append(columns, value)
+		}
+	}
+	return w.Writer.Write(columns)
+}
+
+func formatCost(cost float64) string {
+	return strconv.FormatFloat(cost, 'f', -1, 64)
+}
+
+// getDate returns the date of the RFC3339 time(ex: 2018-10-01)
+func getDate(t string) string {
+	if len(t) < len("2006-01-02") {
+		return t
+	}
+	return t[:len("2006-01-02")]
+}
@@ -0,0 +1,83 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package chargeback
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/vmware/purser/pkg/controller/dgraph/models/query"
+	"github.com/vmware/purser/test/utils"
+)
+
+func getTestReport() query.ChargebackReport {
+	return query.ChargebackReport{
+		Start:   "2018-10-01T00:00:00Z",
+		End:     "2018-11-01T00:00:00Z",
+		GroupBy: query.Namespace,
+		Rows: []query.ChargebackRow{
+			{Group: "shop", Namespace: "shop", Workload: "web", Type: query.DeploymentType,
+				ChargebackCost: query.ChargebackCost{Pods: 2, CPUCost: 8, MemoryCost: 2.25, StorageCost: 1, TotalCost: 11.25}},
+			{Group: "shop", Namespace: "shop", Workload: "db, primary", Type: query.StatefulsetType,
+				ChargebackCost: query.ChargebackCost{Pods: 1, CPUCost: 2, TotalCost: 2}},
+		},
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	utils.Ok(t, WriteCSV(buffer, getTestReport()))
+	utils.Equals(t, "start,end,group,namespace,workload,type,pods,cpuCost,memoryCost,storageCost,totalCost\n"+
+		"2018-10-01T00:00:00Z,2018-11-01T00:00:00Z,shop,shop,web,deployment,2,8,2.25,1,11.25\n"+
+		`2018-10-01T00:00:00Z,2018-11-01T00:00:00Z,shop,shop,"db, primary",statefulset,1,2,0,0,2`+"\n", buffer.String())
+}
+
+func TestWriteJSON(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	utils.Ok(t, Write(buffer, query.ChargebackReport{GroupBy: query.Owner}, query.JSON))
+	utils.Assert(t, bytes.HasPrefix(buffer.Bytes(), []byte(`{"data":{"start":"","end":"","groupBy":"owner",`)), "unexpected json report %s", buffer.String())
+}
+
+func TestWriteWithFieldPolicy(t *testing.T) {
+	utils.Ok(t, query.ConfigureFieldPolicy(nil, []string{"workload", "memoryCost", "storageCost"}))
+	defer func() { utils.Ok(t, query.ConfigureFieldPolicy(nil, nil)) }()
+
+	buffer := &bytes.Buffer{}
+	utils.Ok(t, WriteCSV(buffer, getTestReport()))
+	utils.Equals(t, "start,end,group,namespace,type,pods,cpuCost,totalCost\n"+
+		"2018-10-01T00:00:00Z,2018-11-01T00:00:00Z,shop,shop,deployment,2,8,11.25\n"+
+		"2018-10-01T00:00:00Z,2018-11-01T00:00:00Z,shop,shop,statefulset,1,2,2\n", buffer.String())
+
+	buffer.Reset()
+	utils.Ok(t, Write(buffer, getTestReport(), query.JSON))
+	utils.Assert(t, !bytes.Contains(buffer.Bytes(), []byte(`"memoryCost"`)), "denied field in json report %s", buffer.String())
+	utils.Assert(t, !bytes.Contains(buffer.Bytes(), []byte(`"web"`)), "denied field in json report %s", buffer.String())
+	utils.Assert(t, bytes.Contains(buffer.Bytes(), []byte(`"cpuCost":8`)), "unexpected json report %s", buffer.String())
+
+	utils.Ok(t, query.ConfigureFieldPolicy([]string{"rows.namespace", "rows.totalCost"}, nil))
+	buffer.Reset()
+	utils.Ok(t, WriteCSV(buffer, getTestReport()))
+	utils.Equals(t, "namespace,totalCost\nshop,11.25\nshop,2\n", buffer.String())
+}
+
+func TestFileName(t *testing.T) {
+	utils.Equals(t, "chargeback-namespace-2018-10-01-2018-11-01.csv", FileName(getTestReport(), query.CSV))
+	utils.Equals(t, "chargeback-namespace-2018-10-01-2018-11-01.json", FileName(getTestReport(), ""))
+	utils.Equals(t, CSVContentType, ContentType(query.CSV))
+	utils.Equals(t, JSONContentType, ContentType(query.JSON))
+}
@@ -0,0 +1,92 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package chargeback
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/vmware/purser/pkg/controller/dgraph/models/query"
+)
+
+// focusHeader is the header of rows of FOCUS exports in csv format, the columns of query.FOCUSRow
+var focusHeader = []string{
+	"BilledCost", "BillingAccountId", "BillingAccountName", "BillingCurrency", "BillingPeriodStart", "BillingPeriodEnd",
+	"ChargeCategory", "ChargeDescription", "ChargeFrequency", "ChargePeriodStart", "ChargePeriodEnd",
+	"ConsumedQuantity", "ConsumedUnit", "ContractedCost", "ContractedUnitPrice", "EffectiveCost", "InvoiceIssuerName",
+	"ListCost", "ListUnitPrice", "PricingCategory", "PricingQuantity", "PricingUnit", "ProviderName", "PublisherName",
+	"RegionId", "RegionName", "ResourceId", "ResourceName", "ResourceType", "ServiceCategory", "ServiceName", "SkuId",
+	"SubAccountId", "SubAccountName", "Tags", "x_Namespace", "x_Node", "x_Workload", "x_WorkloadType",
+}
+
+// FOCUSFileName returns the name of the downloaded FOCUS export in the format(ex: focus-2018-10-01-2018-11-01.csv)
+func FOCUSFileName(report query.FOCUSReport, format string) string {
+	if format != query.CSV {
+		format = query.JSON
+	}
+	return fmt.Sprintf("focus-%s-%s.%s", getDate(report.Start), getDate(report.End), format)
+}
+
+// WriteFOCUS renders the FOCUS export in the format, csv exports have a row per charge and json exports are the
+// report itself. Both contain only the fields allowed by the field policy of the API.
+func WriteFOCUS(w io.Writer, report query.FOCUSReport, format string) error {
+	if format == query.CSV {
+		return WriteFOCUSCSV(w, report)
+	}
+	return writeJSON(w, query.FOCUSReportWrapper{Data: report})
+}
+
+// WriteFOCUSCSV renders the charges of the report with a header, tags are a JSON object. Columns of fields which are
+// not allowed by the field policy of the API are left out, tags are only left out if they are denied.
+func WriteFOCUSCSV(w io.Writer, report query.FOCUSReport) error {
+	if err := query.FilterResult(&report); err != nil {
+		return err
+	}
+	writer := newColumnWriter(w, focusHeader, func(column string) bool {
+		if column == "Tags" {
+			return !query.IsFieldDenied("data", "rows", column)
+		}
+		return query.IsFieldAllowed("data", "rows", column)
+	})
+	if err := writer.Write(focusHeader); err != nil {
+		return err
+	}
+	for _, row := range report.Rows {
+		tags, err := json.Marshal(row.Tags)
+		if err != nil {
+			return err
+		}
+		record := []string{
+			formatCost(row.BilledCost), row.BillingAccountID, row.BillingAccountName, row.BillingCurrency,
+			row.BillingPeriodStart, row.BillingPeriodEnd, row.ChargeCategory, row.ChargeDescription, row.ChargeFrequency,
+			row.ChargePeriodStart, row.ChargePeriodEnd, formatCost(row.ConsumedQuantity), row.ConsumedUnit,
+			formatCost(row.ContractedCost), formatCost(row.ContractedUnitPrice), formatCost(row.EffectiveCost),
+			row.InvoiceIssuerName, formatCost(row.ListCost), formatCost(row.ListUnitPrice), row.PricingCategory,
+			formatCost(row.PricingQuantity), row.PricingUnit, row.ProviderName, row.PublisherName, row.RegionID,
+			row.RegionName, row.ResourceID, row.ResourceName, row.ResourceType, row.ServiceCategory, row.ServiceName,
+			row.SkuID, row.SubAccountID, row.SubAccountName, string(tags), row.Namespace, row.Node, row.Workload,
+			row.WorkloadType,
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
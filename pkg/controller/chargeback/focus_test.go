@@ -0,0 +1,94 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package chargeback
+
+import (
+	"bytes"
+	"encoding/csv"
+	"reflect"
+	"testing"
+
+	"github.com/vmware/purser/pkg/controller/dgraph/models/query"
+	"github.com/vmware/purser/test/utils"
+)
+
+// TestWriteFOCUSCSV ...
+func TestWriteFOCUSCSV(t *testing.T) {
+	report := query.FOCUSReport{
+		Start: "2018-10-01T00:00:00Z",
+		End:   "2018-11-01T00:00:00Z",
+		Rows: []query.FOCUSRow{
+			{BilledCost: 2.4, ResourceID: "shop/web-1", SkuID: query.CPUCostType, ChargeDescription: "CPU requested by pod web-1 in namespace shop",
+				ConsumedQuantity: 120, Tags: map[string]string{"team": "payments"}, Namespace: "shop"},
+		},
+	}
+	buffer := &bytes.Buffer{}
+	utils.Ok(t, WriteFOCUSCSV(buffer, report))
+
+	records, err := csv.NewReader(buffer).ReadAll()
+	utils.Ok(t, err)
+	utils.Equals(t, 2, len(records))
+	utils.Equals(t, focusHeader, records[0])
+	utils.Equals(t, len(focusHeader), len(records[1]))
+	column := make(map[string]string)
+	for i, name := range focusHeader {
+		column[name] = records[1][i]
+	}
+	utils.Equals(t, "2.4", column["BilledCost"])
+	utils.Equals(t, "120", column["ConsumedQuantity"])
+	utils.Equals(t, "shop/web-1", column["ResourceId"])
+	utils.Equals(t, `{"team":"payments"}`, column["Tags"])
+	utils.Equals(t, "shop", column["x_Namespace"])
+	utils.Equals(t, "focus-2018-10-01-2018-11-01.csv", FOCUSFileName(report, query.CSV))
+}
+
+// TestWriteFOCUSWithFieldPolicy ...
+func TestWriteFOCUSWithFieldPolicy(t *testing.T) {
+	utils.Ok(t, query.ConfigureFieldPolicy([]string{"BilledCost", "Tags.team"}, []string{"owner"}))
+	defer func() { utils.Ok(t, query.ConfigureFieldPolicy(nil, nil)) }()
+	report := query.FOCUSReport{Rows: []query.FOCUSRow{
+		{BilledCost: 2.4, ResourceID: "shop/web-1", Tags: map[string]string{"team": "payments", "owner": "jane", "app": "web"}},
+	}}
+
+	buffer := &bytes.Buffer{}
+	utils.Ok(t, WriteFOCUSCSV(buffer, report))
+	records, err := csv.NewReader(buffer).ReadAll()
+	utils.Ok(t, err)
+	utils.Equals(t, [][]string{{"BilledCost", "Tags"}, {"2.4", `{"team":"payments"}`}}, records)
+
+	buffer.Reset()
+	utils.Ok(t, WriteFOCUS(buffer, report, query.JSON))
+	utils.Equals(t, `{"data":{"rows":[{"BilledCost":2.4,"Tags":{"team":"payments"}}]}}`+"\n", buffer.String())
+
+	utils.Ok(t, query.ConfigureFieldPolicy(nil, []string{"Tags"}))
+	buffer.Reset()
+	utils.Ok(t, WriteFOCUSCSV(buffer, report))
+	records, err = csv.NewReader(buffer).ReadAll()
+	utils.Ok(t, err)
+	utils.Equals(t, len(focusHeader)-1, len(records[0]))
+}
+
+// TestFOCUSHeader ...
+func TestFOCUSHeader(t *testing.T) {
+	rowType := reflect.TypeOf(query.FOCUSRow{})
+	columns := []string{}
+	for i := 0; i < rowType.NumField(); i++ {
+		columns = append(columns, rowType.Field(i).Tag.Get("json"))
+	}
+	utils.Equals(t, columns, focusHeader)
+}
@@ -0,0 +1,45 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cloudevents
+
+import "github.com/vmware/purser/pkg/controller/notification"
+
+// ChannelName is the name of the channel emitting notifications as cloud events
+const ChannelName = "cloudevents"
+
+// Channel emits notifications of alerts and budgets as cloud events to the configured sinks
+type Channel struct{}
+
+// NewChannel returns the channel emitting notifications as cloud events
+func NewChannel() *Channel {
+	return &Channel{}
+}
+
+// Name of the channel
+func (c *Channel) Name() string {
+	return ChannelName
+}
+
+// Send emits the notification as an alert event
+func (c *Channel) Send(n notification.Notification) error {
+	event, err := NewAlertEvent(n)
+	if err != nil {
+		return err
+	}
+	return Emit(event)
+}
@@ -0,0 +1,203 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cloudevents
+
+import (
+	"crypto/sha1"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/vmware/purser/pkg/controller"
+	"github.com/vmware/purser/pkg/controller/notification"
+)
+
+// SpecVersion of CloudEvents emitted
+const SpecVersion = "1.0"
+
+// Source of all events emitted by purser
+const Source = "purser"
+
+// TypePrefix of types of events, resource events are <prefix>.<kind>.<created|updated|deleted>(ex:
+// com.vmware.purser.pod.created) and alert events are <prefix>.alert.<firing|resolved>
+const TypePrefix = "com.vmware.purser"
+
+// JSONContentType is the content type of data of events
+const JSONContentType = "application/json"
+
+// Event is a CloudEvent with the required and optional context attributes used by purser, it is marshaled in
+// the JSON event format
+type Event struct {
+	SpecVersion     string          `json:"specversion"`
+	ID              string          `json:"id"`
+	Source          string          `json:"source"`
+	Type            string          `json:"type"`
+	Subject         string          `json:"subject,omitempty"`
+	Time            string          `json:"time,omitempty"`
+	DataContentType string          `json:"datacontenttype,omitempty"`
+	Data            json.RawMessage `json:"data,omitempty"`
+}
+
+// Sink delivers events to a destination in one of the protocol bindings of CloudEvents
+type Sink interface {
+	Name() string
+	Send(event Event) error
+}
+
+var (
+	sinksMu sync.RWMutex
+	sinks   []Sink
+)
+
+// resourceEventTypes are the types of events of resources per event type of payloads
+var resourceEventTypes = map[string]string{
+	controller.Create: "created",
+	controller.Update: "updated",
+	controller.Delete: "deleted",
+}
+
+// Configure sets the sinks of events, a http sink in binary or structured mode if httpURL is given and a kafka sink
+// producing to the topic through the REST proxy if kafkaProxyURL is given
+func Configure(httpURL, mode, kafkaProxyURL, kafkaTopic string, timeout time.Duration) error {
+	configured := []Sink{}
+	if httpURL != "" {
+		sink, err := NewHTTPSink(httpURL, mode, timeout)
+		if err != nil {
+			return err
+		}
+		configured = append(configured, sink)
+	}
+	if kafkaProxyURL != "" {
+		if kafkaTopic == "" {
+			return fmt.Errorf("kafka topic of cloud events is not given")
+		}
+		configured = append(configured, NewKafkaSink(kafkaProxyURL, kafkaTopic, timeout))
+	}
+	sinksMu.Lock()
+	defer sinksMu.Unlock()
+	sinks = configured
+	for _, sink := range sinks {
+		log.Infof("emitting cloud events to %s sink", sink.Name())
+	}
+	return nil
+}
+
+// IsConfigured returns true if events are emitted to at least one sink
+func IsConfigured() bool {
+	sinksMu.RLock()
+	defer sinksMu.RUnlock()
+	return len(sinks) > 0
+}
+
+// Emit sends the events to all sinks, returns an error with the failed deliveries
+func Emit(events ...Event) error {
+	sinksMu.RLock()
+	defer sinksMu.RUnlock()
+	var failed []string
+	for _, event := range events {
+		for _, sink := range sinks {
+			if err := sink.Send(event); err != nil {
+				failed = append(failed, fmt.Sprintf("%s: %v", sink.Name(), err))
+			}
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("%d deliveries of cloud events failed: %v", len(failed), failed)
+	}
+	return nil
+}
+
+// EmitResourceEvents sends an event for each create, update and delete of resources in the payloads
+func EmitResourceEvents(payloads []*interface{}) {
+	events := []Event{}
+	for _, data := range payloads {
+		payload, isPayload := (*data).(*controller.Payload)
+		if !isPayload {
+			continue
+		}
+		if event, isEvent := NewResourceEvent(payload); isEvent {
+			events = append(events, event)
+		}
+	}
+	if err := Emit(events...); err != nil {
+		log.Errorf("unable to emit cloud events of resources: %v", err)
+	}
+}
+
+// NewResourceEvent returns the event of the payload with the resource as data, subject is the key of the
+// resource(ex: default/web-1). Payloads with unknown event types have no event.
+func NewResourceEvent(payload *controller.Payload) (Event, bool) {
+	action, isPresent := resourceEventTypes[payload.EventType]
+	if !isPresent {
+		return Event{}, false
+	}
+	captureTime := payload.CaptureTime.Time.UTC()
+	event := Event{
+		SpecVersion:     SpecVersion,
+		ID:              getID(payload.ResourceType, payload.Key, payload.EventType, captureTime.Format(time.RFC3339Nano)),
+		Source:          Source,
+		Type:            TypePrefix + "." + strings.ToLower(payload.ResourceType) + "." + action,
+		Subject:         payload.Key,
+		DataContentType: JSONContentType,
+		Data:            getData(payload.Data),
+	}
+	if !captureTime.IsZero() {
+		event.Time = captureTime.Format(time.RFC3339Nano)
+	}
+	return event, true
+}
+
+// NewAlertEvent returns the event of the notification with the notification as data, subject is its dedup key
+func NewAlertEvent(n notification.Notification) (Event, error) {
+	data, err := json.Marshal(n)
+	if err != nil {
+		return Event{}, err
+	}
+	eventType := TypePrefix + ".alert"
+	if n.Status != "" {
+		eventType += "." + n.Status
+	}
+	notificationTime := n.Time.UTC()
+	return Event{
+		SpecVersion:     SpecVersion,
+		ID:              getID(n.DedupKey, n.Status, notificationTime.Format(time.RFC3339Nano)),
+		Source:          Source,
+		Type:            eventType,
+		Subject:         n.DedupKey,
+		Time:            notificationTime.Format(time.RFC3339Nano),
+		DataContentType: JSONContentType,
+		Data:            data,
+	}, nil
+}
+
+// getID returns the id of an event, the same occurrence has the same id so that consumers can drop duplicates
+func getID(parts ...string) string {
+	return fmt.Sprintf("%x", sha1.Sum([]byte(strings.Join(parts, "/"))))
+}
+
+// getData returns the JSON of the resource, or the JSON string of it if it is not valid JSON
+func getData(resource string) json.RawMessage {
+	if json.Valid([]byte(resource)) {
+		return json.RawMessage(resource)
+	}
+	data, _ := json.Marshal(resource)
+	return data
+}
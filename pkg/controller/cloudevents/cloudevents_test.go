@@ -0,0 +1,118 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cloudevents
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/vmware/purser/pkg/controller"
+	"github.com/vmware/purser/pkg/controller/notification"
+	"github.com/vmware/purser/test/utils"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type testSink struct {
+	events []Event
+}
+
+func (s *testSink) Name() string {
+	return "test"
+}
+
+func (s *testSink) Send(event Event) error {
+	s.events = append(s.events, event)
+	return nil
+}
+
+func mockSink() *testSink {
+	sink := &testSink{}
+	sinks = []Sink{sink}
+	return sink
+}
+
+// TestNewResourceEvent ...
+func TestNewResourceEvent(t *testing.T) {
+	captureTime := meta_v1.NewTime(time.Date(2018, 10, 10, 10, 0, 0, 0, time.UTC))
+	payload := &controller.Payload{Key: "default/web-1", EventType: controller.Delete, ResourceType: "Pod", Data: `{"metadata": {"name": "web-1"}}`, CaptureTime: captureTime}
+
+	event, isEvent := NewResourceEvent(payload)
+	utils.Assert(t, isEvent, "no event for delete of pod")
+	utils.Equals(t, "com.vmware.purser.pod.deleted", event.Type)
+	utils.Equals(t, "default/web-1", event.Subject)
+	utils.Equals(t, "2018-10-10T10:00:00Z", event.Time)
+	utils.Equals(t, `{"metadata": {"name": "web-1"}}`, string(event.Data))
+	utils.Equals(t, 40, len(event.ID))
+
+	same, _ := NewResourceEvent(payload)
+	utils.Equals(t, event.ID, same.ID)
+
+	payload.Data = "not json"
+	event, _ = NewResourceEvent(payload)
+	utils.Equals(t, `"not json"`, string(event.Data))
+
+	payload.EventType = "resync"
+	_, isEvent = NewResourceEvent(payload)
+	utils.Assert(t, !isEvent, "event for unknown event type")
+}
+
+// TestEmitResourceEvents ...
+func TestEmitResourceEvents(t *testing.T) {
+	sink := mockSink()
+	defer func() { sinks = nil }()
+
+	var created, updated interface{}
+	created = &controller.Payload{Key: "default", EventType: controller.Create, ResourceType: "Namespace", Data: "{}"}
+	updated = &controller.Payload{Key: "default/web", EventType: controller.Update, ResourceType: "Deployment", Data: "{}"}
+	EmitResourceEvents([]*interface{}{&created, &updated})
+	utils.Equals(t, 2, len(sink.events))
+	utils.Equals(t, "com.vmware.purser.namespace.created", sink.events[0].Type)
+	utils.Equals(t, "com.vmware.purser.deployment.updated", sink.events[1].Type)
+}
+
+// TestChannelSend ...
+func TestChannelSend(t *testing.T) {
+	sink := mockSink()
+	defer func() { sinks = nil }()
+
+	n := notification.Notification{Title: "high cost", Status: notification.Firing, DedupKey: "purser-alert-high-cost", Time: time.Date(2018, 10, 10, 10, 0, 0, 0, time.UTC)}
+	utils.Ok(t, NewChannel().Send(n))
+	utils.Equals(t, 1, len(sink.events))
+	event := sink.events[0]
+	utils.Equals(t, "com.vmware.purser.alert.firing", event.Type)
+	utils.Equals(t, "purser-alert-high-cost", event.Subject)
+	utils.Equals(t, Source, event.Source)
+
+	data := notification.Notification{}
+	utils.Ok(t, json.Unmarshal(event.Data, &data))
+	utils.Equals(t, "high cost", data.Title)
+}
+
+// TestConfigure ...
+func TestConfigure(t *testing.T) {
+	defer func() { sinks = nil }()
+	utils.Ok(t, Configure("http://broker-ingress/default/default", "", "http://kafka-rest:8082/v3/clusters/c1", "purser-events", time.Second))
+	utils.Assert(t, IsConfigured(), "cloud events not configured")
+	utils.Equals(t, 2, len(sinks))
+
+	utils.Assert(t, Configure("http://broker-ingress", "batch", "", "", time.Second) != nil, "unknown mode accepted")
+	utils.Assert(t, Configure("", "", "http://kafka-rest:8082/v3/clusters/c1", "", time.Second) != nil, "kafka sink without topic accepted")
+	utils.Ok(t, Configure("", "", "", "", time.Second))
+	utils.Assert(t, !IsConfigured(), "cloud events configured without sinks")
+}
@@ -0,0 +1,122 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cloudevents
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Modes of the HTTP protocol binding
+const (
+	// BinaryMode sends context attributes as ce- headers and data as the body
+	BinaryMode = "binary"
+	// StructuredMode sends the event in JSON event format as the body
+	StructuredMode = "structured"
+)
+
+// StructuredContentType is the content type of events in structured mode
+const StructuredContentType = "application/cloudevents+json"
+
+// HTTPSink posts events to a url(ex: Knative broker ingress) in binary or structured mode
+type HTTPSink struct {
+	url    string
+	mode   string
+	client *http.Client
+}
+
+// NewHTTPSink returns a sink posting events to the url in the mode, binary by default
+func NewHTTPSink(url, mode string, timeout time.Duration) (*HTTPSink, error) {
+	if mode == "" {
+		mode = BinaryMode
+	}
+	if mode != BinaryMode && mode != StructuredMode {
+		return nil, fmt.Errorf("unknown cloud events mode: %s, expected %s or %s", mode, BinaryMode, StructuredMode)
+	}
+	return &HTTPSink{url: url, mode: mode, client: &http.Client{Timeout: timeout}}, nil
+}
+
+// Name of the sink
+func (s *HTTPSink) Name() string {
+	return "http"
+}
+
+// Send posts the event and expects a 2xx response
+func (s *HTTPSink) Send(event Event) error {
+	req, err := s.newRequest(event)
+	if err != nil {
+		return err
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error sending event to %s: %v", s.url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("posting event to %s failed: %s", s.url, resp.Status)
+	}
+	return nil
+}
+
+func (s *HTTPSink) newRequest(event Event) (*http.Request, error) {
+	if s.mode == StructuredMode {
+		body, err := json.Marshal(event)
+		if err != nil {
+			return nil, err
+		}
+		req, err := http.NewRequest("POST", s.url, bytes.NewBuffer(body))
+		if err != nil {
+			return nil, fmt.Errorf("error creating HTTP request for %s: %v", s.url, err)
+		}
+		req.Header.Set("Content-Type", StructuredContentType)
+		return req, nil
+	}
+
+	req, err := http.NewRequest("POST", s.url, bytes.NewBuffer(event.Data))
+	if err != nil {
+		return nil, fmt.Errorf("error creating HTTP request for %s: %v", s.url, err)
+	}
+	for name, value := range getAttributes(event) {
+		req.Header.Set("ce-"+name, value)
+	}
+	if event.DataContentType != "" {
+		req.Header.Set("Content-Type", event.DataContentType)
+	}
+	return req, nil
+}
+
+// getAttributes returns the context attributes of the event without datacontenttype, which is the content type
+// of messages in binary mode
+func getAttributes(event Event) map[string]string {
+	attributes := map[string]string{
+		"specversion": event.SpecVersion,
+		"id":          event.ID,
+		"source":      event.Source,
+		"type":        event.Type,
+	}
+	if event.Subject != "" {
+		attributes["subject"] = event.Subject
+	}
+	if event.Time != "" {
+		attributes["time"] = event.Time
+	}
+	return attributes
+}
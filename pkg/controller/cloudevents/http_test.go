@@ -0,0 +1,90 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cloudevents
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/vmware/purser/test/utils"
+)
+
+func getTestEvent() Event {
+	return Event{
+		SpecVersion:     SpecVersion,
+		ID:              "1",
+		Source:          Source,
+		Type:            "com.vmware.purser.pod.created",
+		Subject:         "default/web-1",
+		Time:            "2018-10-10T10:00:00Z",
+		DataContentType: JSONContentType,
+		Data:            json.RawMessage(`{"kind":"Pod"}`),
+	}
+}
+
+// TestHTTPSinkBinary ...
+func TestHTTPSinkBinary(t *testing.T) {
+	var headers http.Header
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers = r.Header
+		body, _ = ioutil.ReadAll(r.Body)
+	}))
+	defer server.Close()
+
+	sink, err := NewHTTPSink(server.URL, "", time.Second)
+	utils.Ok(t, err)
+	utils.Ok(t, sink.Send(getTestEvent()))
+	utils.Equals(t, "1.0", headers.Get("ce-specversion"))
+	utils.Equals(t, "com.vmware.purser.pod.created", headers.Get("ce-type"))
+	utils.Equals(t, "default/web-1", headers.Get("ce-subject"))
+	utils.Equals(t, JSONContentType, headers.Get("Content-Type"))
+	utils.Equals(t, `{"kind":"Pod"}`, string(body))
+}
+
+// TestHTTPSinkStructured ...
+func TestHTTPSinkStructured(t *testing.T) {
+	var contentType string
+	event := Event{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentType = r.Header.Get("Content-Type")
+		utils.Ok(t, json.NewDecoder(r.Body).Decode(&event))
+	}))
+	defer server.Close()
+
+	sink, err := NewHTTPSink(server.URL, StructuredMode, time.Second)
+	utils.Ok(t, err)
+	utils.Ok(t, sink.Send(getTestEvent()))
+	utils.Equals(t, StructuredContentType, contentType)
+	utils.Equals(t, getTestEvent(), event)
+}
+
+// TestHTTPSinkFailure ...
+func TestHTTPSinkFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	sink, _ := NewHTTPSink(server.URL, BinaryMode, time.Second)
+	utils.Assert(t, sink.Send(getTestEvent()) != nil, "failed delivery not reported")
+}
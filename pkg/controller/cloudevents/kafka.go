@@ -0,0 +1,113 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cloudevents
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// KafkaSink produces events to a topic through the REST API v3 of a Kafka REST proxy in binary mode of the Kafka
+// protocol binding, context attributes are ce_ headers, data is the value and subject is the key so that events
+// of a resource keep their order
+type KafkaSink struct {
+	url    string
+	topic  string
+	client *http.Client
+}
+
+type kafkaRecord struct {
+	Key     *kafkaData    `json:"key,omitempty"`
+	Value   kafkaData     `json:"value"`
+	Headers []kafkaHeader `json:"headers"`
+}
+
+type kafkaData struct {
+	Type string      `json:"type"`
+	Data interface{} `json:"data"`
+}
+
+type kafkaHeader struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// NewKafkaSink returns a sink producing to the topic through the REST proxy cluster url(ex:
+// http://kafka-rest:8082/v3/clusters/<cluster id>)
+func NewKafkaSink(url, topic string, timeout time.Duration) *KafkaSink {
+	return &KafkaSink{url: strings.TrimSuffix(url, "/"), topic: topic, client: &http.Client{Timeout: timeout}}
+}
+
+// Name of the sink
+func (s *KafkaSink) Name() string {
+	return "kafka"
+}
+
+// Send produces the event as a record of the topic
+func (s *KafkaSink) Send(event Event) error {
+	body, err := json.Marshal(getKafkaRecord(event))
+	if err != nil {
+		return err
+	}
+	url := s.url + "/topics/" + s.topic + "/records"
+	req, err := http.NewRequest("POST", url, bytes.NewBuffer(body))
+	if err != nil {
+		return fmt.Errorf("error creating HTTP request for %s: %v", url, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error producing event to %s: %v", url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("producing event to %s failed: %s", url, resp.Status)
+	}
+	return nil
+}
+
+// getKafkaRecord returns the record of the event, values of headers are base64 encoded as required by the proxy
+func getKafkaRecord(event Event) kafkaRecord {
+	record := kafkaRecord{Value: kafkaData{Type: "JSON", Data: event.Data}, Headers: []kafkaHeader{}}
+	if event.Subject != "" {
+		record.Key = &kafkaData{Type: "STRING", Data: event.Subject}
+	}
+	attributes := getAttributes(event)
+	names := []string{}
+	for name := range attributes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		record.Headers = append(record.Headers, kafkaHeader{Name: "ce_" + name, Value: encodeHeader(attributes[name])})
+	}
+	if event.DataContentType != "" {
+		record.Headers = append(record.Headers, kafkaHeader{Name: "content-type", Value: encodeHeader(event.DataContentType)})
+	}
+	return record
+}
+
+func encodeHeader(value string) string {
+	return base64.StdEncoding.EncodeToString([]byte(value))
+}
@@ -0,0 +1,56 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cloudevents
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/vmware/purser/test/utils"
+)
+
+// TestKafkaSinkSend ...
+func TestKafkaSinkSend(t *testing.T) {
+	var path string
+	record := struct {
+		Key     kafkaData       `json:"key"`
+		Value   json.RawMessage `json:"value"`
+		Headers []kafkaHeader   `json:"headers"`
+	}{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		utils.Ok(t, json.NewDecoder(r.Body).Decode(&record))
+	}))
+	defer server.Close()
+
+	utils.Ok(t, NewKafkaSink(server.URL+"/v3/clusters/c1/", "purser-events", time.Second).Send(getTestEvent()))
+	utils.Equals(t, "/v3/clusters/c1/topics/purser-events/records", path)
+	utils.Equals(t, kafkaData{Type: "STRING", Data: "default/web-1"}, record.Key)
+	utils.Equals(t, `{"type":"JSON","data":{"kind":"Pod"}}`, string(record.Value))
+
+	headers := make(map[string]string)
+	for _, header := range record.Headers {
+		headers[header.Name] = header.Value
+	}
+	utils.Equals(t, encodeHeader("1.0"), headers["ce_specversion"])
+	utils.Equals(t, encodeHeader("com.vmware.purser.pod.created"), headers["ce_type"])
+	utils.Equals(t, encodeHeader(JSONContentType), headers["content-type"])
+}
@@ -27,7 +27,10 @@ import (
 
 	log "github.com/Sirupsen/logrus"
 
+	"github.com/vmware/purser/pkg/controller/status"
+
 	groups_v1 "github.com/vmware/purser/pkg/apis/groups/v1"
+	openshift_v1 "github.com/vmware/purser/pkg/apis/openshift/v1"
 	subscriber_v1 "github.com/vmware/purser/pkg/apis/subscriber/v1"
 
 	apps_v1beta1 "k8s.io/api/apps/v1beta1"
@@ -368,6 +371,75 @@ func Start(conf *Config) {
 		go c.Run(stopCh)
 	}
 
+	if conf.Resource.DeploymentConfig {
+		informer := cache.NewSharedIndexInformer(
+			&cache.ListWatch{
+				ListFunc: func(options meta_v1.ListOptions) (runtime.Object, error) {
+					return conf.OpenShiftclient.ListDeploymentConfigs(options)
+				},
+				WatchFunc: func(options meta_v1.ListOptions) (watch.Interface, error) {
+					return conf.OpenShiftclient.WatchDeploymentConfigs(options)
+				},
+			},
+			&openshift_v1.DeploymentConfig{},
+			0,
+			cache.Indexers{},
+		)
+
+		c := newResourceController(Kubeclient, informer, "DeploymentConfig")
+		c.conf = conf
+		stopCh := make(chan struct{})
+		defer close(stopCh)
+
+		go c.Run(stopCh)
+	}
+
+	if conf.Resource.Route {
+		informer := cache.NewSharedIndexInformer(
+			&cache.ListWatch{
+				ListFunc: func(options meta_v1.ListOptions) (runtime.Object, error) {
+					return conf.OpenShiftclient.ListRoutes(options)
+				},
+				WatchFunc: func(options meta_v1.ListOptions) (watch.Interface, error) {
+					return conf.OpenShiftclient.WatchRoutes(options)
+				},
+			},
+			&openshift_v1.Route{},
+			0,
+			cache.Indexers{},
+		)
+
+		c := newResourceController(Kubeclient, informer, "Route")
+		c.conf = conf
+		stopCh := make(chan struct{})
+		defer close(stopCh)
+
+		go c.Run(stopCh)
+	}
+
+	if conf.Resource.ImageStream {
+		informer := cache.NewSharedIndexInformer(
+			&cache.ListWatch{
+				ListFunc: func(options meta_v1.ListOptions) (runtime.Object, error) {
+					return conf.OpenShiftclient.ListImageStreams(options)
+				},
+				WatchFunc: func(options meta_v1.ListOptions) (watch.Interface, error) {
+					return conf.OpenShiftclient.WatchImageStreams(options)
+				},
+			},
+			&openshift_v1.ImageStream{},
+			0,
+			cache.Indexers{},
+		)
+
+		c := newResourceController(Kubeclient, informer, "ImageStream")
+		c.conf = conf
+		stopCh := make(chan struct{})
+		defer close(stopCh)
+
+		go c.Run(stopCh)
+	}
+
 	sigterm := make(chan os.Signal, 1)
 	signal.Notify(sigterm, syscall.SIGTERM)
 	signal.Notify(sigterm, syscall.SIGINT)
@@ -387,17 +459,23 @@ func newResourceController(client kubernetes.Interface, informer cache.SharedInd
 			log.Printf("Processing add to %v: %s", resourceType, newEvent.key)
 			if err == nil {
 				queue.Add(newEvent)
+				status.RecordEvent(resourceType, newEvent.captureTime.Time)
 			}
 		},
-		// TODO: Fixme
+		// only phase transitions and restarts of pods are propagated, other updates are picked up by resync
 		UpdateFunc: func(old, new interface{}) {
-			/*newEvent.key, err = cache.MetaNamespaceKeyFunc(old)
-			newEvent.eventType = "update"
+			if !isPodStatusTransition(old, new) {
+				return
+			}
+			newEvent.key, err = cache.MetaNamespaceKeyFunc(new)
+			newEvent.eventType = Update
 			newEvent.resourceType = resourceType
+			newEvent.captureTime = meta_v1.Now()
 			log.Printf("Processing update to %v: %s", resourceType, newEvent.key)
 			if err == nil {
 				queue.Add(newEvent)
-			}*/
+				status.RecordEvent(resourceType, newEvent.captureTime.Time)
+			}
 		},
 		DeleteFunc: func(obj interface{}) {
 			newEvent.key, err = cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
@@ -408,15 +486,18 @@ func newResourceController(client kubernetes.Interface, informer cache.SharedInd
 			log.Printf("Processing delete to %v: %s", resourceType, newEvent.key)
 			if err == nil {
 				queue.Add(newEvent)
+				status.RecordEvent(resourceType, newEvent.captureTime.Time)
 			}
 		},
 	})
 
-	return &Controller{
+	c := &Controller{
 		clientset: client,
 		informer:  informer,
 		queue:     queue,
 	}
+	status.Register(resourceType, c)
+	return c
 }
 
 // Run initiates the controller
@@ -440,6 +521,16 @@ func (c *Controller) HasSynced() bool {
 	return c.informer.HasSynced()
 }
 
+// QueueLength returns the number of events waiting to be buffered, it is required for the status.Source interface.
+func (c *Controller) QueueLength() int {
+	return c.queue.Len()
+}
+
+// ObjectsInCluster returns the number of objects in the informer cache, it is required for the status.Source interface.
+func (c *Controller) ObjectsInCluster() int {
+	return len(c.informer.GetStore().ListKeys())
+}
+
 // LastSyncResourceVersion is required for the cache.Controller interface.
 func (c *Controller) LastSyncResourceVersion() string {
 	return c.informer.LastSyncResourceVersion()
@@ -451,6 +542,25 @@ func (c *Controller) runWorker() {
 	}
 }
 
+// isPodStatusTransition returns true if the pod changed its phase, got scheduled on a node or its containers restarted
+func isPodStatusTransition(old, new interface{}) bool {
+	oldPod, isOldPod := old.(*api_v1.Pod)
+	newPod, isNewPod := new.(*api_v1.Pod)
+	if !isOldPod || !isNewPod {
+		return false
+	}
+	return oldPod.Status.Phase != newPod.Status.Phase || oldPod.Spec.NodeName != newPod.Spec.NodeName ||
+		getRestartCount(oldPod) != getRestartCount(newPod)
+}
+
+func getRestartCount(pod *api_v1.Pod) int32 {
+	var restarts int32
+	for _, container := range pod.Status.ContainerStatuses {
+		restarts += container.RestartCount
+	}
+	return restarts
+}
+
 func (c *Controller) processNextItem() bool {
 	newEvent, quit := c.queue.Get()
 
@@ -478,17 +588,14 @@ func (c *Controller) processItem(newEvent Event) error {
 
 	// process events based on its type
 	switch newEvent.eventType {
-	case Create:
+	case Create, Update:
 		str, err := json.Marshal(obj)
 		if err != nil {
 			log.Errorf("Error marshalling object %s", obj)
 		}
 		payload := &Payload{Key: newEvent.key, EventType: newEvent.eventType, ResourceType: newEvent.resourceType,
 			CloudType: "aws", Data: string(str), CaptureTime: newEvent.captureTime}
-		c.conf.RingBuffer.Put(payload)
-		return nil
-	case Update:
-		// TODO: Decide on what needs to be propagated.
+		c.putPayload(payload)
 		return nil
 	case Delete:
 		str, err := json.Marshal(newEvent.data)
@@ -497,8 +604,16 @@ func (c *Controller) processItem(newEvent Event) error {
 		}
 		payload := &Payload{Key: newEvent.key, EventType: newEvent.eventType, ResourceType: newEvent.resourceType,
 			CloudType: "aws", Data: string(str), CaptureTime: newEvent.captureTime}
-		c.conf.RingBuffer.Put(payload)
+		c.putPayload(payload)
 		return nil
 	}
 	return nil
 }
+
+// putPayload buffers the payload, it is dropped if the buffer is full
+func (c *Controller) putPayload(payload *Payload) {
+	if !c.conf.RingBuffer.Put(payload) {
+		log.Errorf("Event buffer is full, dropping %s event of %s %s", payload.EventType, payload.ResourceType, payload.Key)
+		status.RecordDropped(payload.ResourceType)
+	}
+}
@@ -0,0 +1,200 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package costbudget
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	costbudget_v1 "github.com/vmware/purser/pkg/apis/costbudget/v1"
+	costbudget_client "github.com/vmware/purser/pkg/client/clientset/typed/costbudget/v1"
+	"github.com/vmware/purser/pkg/controller/dgraph"
+	"github.com/vmware/purser/pkg/controller/dgraph/models/query"
+	"github.com/vmware/purser/pkg/controller/notification"
+	api_v1 "k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Thresholds of budgets in percent of their limit, crossing them emits an event and a notification once a month
+var Thresholds = []float64{80, 100}
+
+// Reasons of events of budgets
+const (
+	ThresholdCrossed = "BudgetThresholdCrossed"
+	Exceeded         = "BudgetExceeded"
+)
+
+// Source of events and notifications of budgets
+const Source = "purser"
+
+var (
+	retrieveMonthToDateCost = query.RetrieveMonthToDateCost
+	dispatch                = notification.Dispatch
+)
+
+// EvaluateBudgets updates the month to date spend of all budgets, emits an event on the budget and dispatches a
+// notification when it crosses 80% or 100% of its limit. Notifications of crossed budgets resolve when a month starts.
+func EvaluateBudgets(client *costbudget_client.CostBudgetClient, kubeclient kubernetes.Interface) {
+	budgets, err := client.List(meta_v1.ListOptions{})
+	if err != nil {
+		log.Errorf("unable to list cost budgets: %v", err)
+		return
+	}
+	now := time.Now()
+	for i := range budgets.Items {
+		budget := &budgets.Items[i]
+		if crossed, changed := evaluate(budget, now); changed {
+			if _, err := client.Update(budget); err != nil {
+				log.Errorf("unable to update status of cost budget: %s/%s, err: %v", budget.Namespace, budget.Name, err)
+			}
+			if crossed != 0 {
+				recordEvent(kubeclient, budget, crossed, now)
+			}
+		}
+	}
+}
+
+// evaluate computes the status of the budget and notifies if it crossed a threshold or a month started, it returns
+// the crossed threshold(0 if none) and whether the status changed
+func evaluate(budget *costbudget_v1.CostBudget, now time.Time) (float64, bool) {
+	status := budget.Status
+	month := now.Format("2006-01")
+	if status.Month != month {
+		if status.Threshold != 0 {
+			notify(budget, notification.Resolved, now)
+		}
+		status = costbudget_v1.CostBudgetStatus{Month: month}
+	}
+
+	cost, err := retrieveMonthToDateCost(dgraph.WithOwnCluster(context.Background()), budget.Spec.Namespace, budget.Spec.Selector)
+	if err != nil {
+		status.Message = fmt.Sprintf("unable to retrieve month to date cost: %v", err)
+		return 0, setStatus(budget, status)
+	}
+	if budget.Spec.Limit <= 0 {
+		status.Message = fmt.Sprintf("invalid limit: %v, limit must be positive", budget.Spec.Limit)
+		return 0, setStatus(budget, status)
+	}
+	status.MonthToDateCost = cost
+	status.PercentUsed = cost / budget.Spec.Limit * 100
+	status.Message = fmt.Sprintf("%.2f of %.2f spent", cost, budget.Spec.Limit)
+
+	crossed := 0.0
+	for _, threshold := range Thresholds {
+		if status.PercentUsed >= threshold && threshold > status.Threshold {
+			crossed = threshold
+		}
+	}
+	if crossed != 0 {
+		status.Threshold = crossed
+	}
+	changed := setStatus(budget, status)
+	if crossed != 0 {
+		notify(budget, notification.Firing, now)
+	}
+	return crossed, changed
+}
+
+func setStatus(budget *costbudget_v1.CostBudget, status costbudget_v1.CostBudgetStatus) bool {
+	changed := !reflect.DeepEqual(budget.Status, status)
+	budget.Status = status
+	return changed
+}
+
+// notify dispatches a notification of the budget with its current status, the dedup key is the same for all
+// thresholds so resolving it closes the incident of the month
+func notify(budget *costbudget_v1.CostBudget, status string, now time.Time) {
+	severity := notification.SeverityWarning
+	if budget.Status.Threshold >= 100 {
+		severity = notification.SeverityCritical
+	}
+	dispatch(notification.Notification{
+		Title:    getTitle(budget),
+		Summary:  budget.Status.Message,
+		Severity: severity,
+		Status:   status,
+		DedupKey: "purser-budget-" + budget.Namespace + "-" + budget.Name,
+		Source:   Source,
+		Time:     now,
+		Details: map[string]interface{}{
+			"budget":          budget.Namespace + "/" + budget.Name,
+			"scope":           getScope(budget.Spec),
+			"limit":           budget.Spec.Limit,
+			"monthToDateCost": budget.Status.MonthToDateCost,
+			"percentUsed":     budget.Status.PercentUsed,
+			"threshold":       budget.Status.Threshold,
+			"month":           budget.Status.Month,
+		},
+	})
+}
+
+// recordEvent emits an event on the budget for the crossed threshold
+func recordEvent(kubeclient kubernetes.Interface, budget *costbudget_v1.CostBudget, threshold float64, now time.Time) {
+	reason := ThresholdCrossed
+	if threshold >= 100 {
+		reason = Exceeded
+	}
+	event := &api_v1.Event{
+		ObjectMeta: meta_v1.ObjectMeta{GenerateName: budget.Name + "-", Namespace: budget.Namespace},
+		InvolvedObject: api_v1.ObjectReference{
+			APIVersion:      costbudget_v1.CostBudgetGroupVersion.String(),
+			Kind:            reflect.TypeOf(costbudget_v1.CostBudget{}).Name(),
+			Namespace:       budget.Namespace,
+			Name:            budget.Name,
+			UID:             budget.UID,
+			ResourceVersion: budget.ResourceVersion,
+		},
+		Reason:         reason,
+		Message:        fmt.Sprintf("%.0f%% of budget %.2f of %s crossed: %s", threshold, budget.Spec.Limit, getScope(budget.Spec), budget.Status.Message),
+		Type:           api_v1.EventTypeWarning,
+		Source:         api_v1.EventSource{Component: Source},
+		FirstTimestamp: meta_v1.NewTime(now),
+		LastTimestamp:  meta_v1.NewTime(now),
+		Count:          1,
+	}
+	if _, err := kubeclient.CoreV1().Events(budget.Namespace).Create(event); err != nil {
+		log.Errorf("unable to create event of cost budget: %s/%s, err: %v", budget.Namespace, budget.Name, err)
+	}
+}
+
+func getTitle(budget *costbudget_v1.CostBudget) string {
+	return fmt.Sprintf("%s: %.0f%% of budget %.2f of %s spent in %s", budget.Name, budget.Status.PercentUsed, budget.Spec.Limit, getScope(budget.Spec), budget.Status.Month)
+}
+
+// getScope returns the pods spending the budget(ex: namespace shop with team=payments)
+func getScope(spec costbudget_v1.CostBudgetSpec) string {
+	scope := "cluster"
+	if spec.Namespace != "" {
+		scope = "namespace " + spec.Namespace
+	}
+	if len(spec.Selector) > 0 {
+		labels := []string{}
+		for key, value := range spec.Selector {
+			labels = append(labels, key+"="+value)
+		}
+		sort.Strings(labels)
+		scope += " with " + strings.Join(labels, ",")
+	}
+	return scope
+}
@@ -0,0 +1,98 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package costbudget
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	costbudget_v1 "github.com/vmware/purser/pkg/apis/costbudget/v1"
+	"github.com/vmware/purser/pkg/controller/notification"
+	"github.com/vmware/purser/test/utils"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func mockCostBudget(cost *float64) *[]notification.Notification {
+	notifications := []notification.Notification{}
+	dispatch = func(n notification.Notification) {
+		notifications = append(notifications, n)
+	}
+	retrieveMonthToDateCost = func(ctx context.Context, namespace string, selector map[string]string) (float64, error) {
+		return *cost, nil
+	}
+	return &notifications
+}
+
+// TestEvaluate ...
+func TestEvaluate(t *testing.T) {
+	cost := 50.0
+	notifications := mockCostBudget(&cost)
+	budget := &costbudget_v1.CostBudget{
+		ObjectMeta: meta_v1.ObjectMeta{Name: "payments", Namespace: "shop"},
+		Spec:       costbudget_v1.CostBudgetSpec{Namespace: "shop", Selector: map[string]string{"team": "payments"}, Limit: 100},
+	}
+	now := time.Date(2018, 3, 10, 10, 0, 0, 0, time.UTC)
+
+	crossed, changed := evaluate(budget, now)
+	utils.Equals(t, 0.0, crossed)
+	utils.Assert(t, changed, "status of new budget not set")
+	utils.Equals(t, costbudget_v1.CostBudgetStatus{Month: "2018-03", MonthToDateCost: 50, PercentUsed: 50, Message: "50.00 of 100.00 spent"}, budget.Status)
+	utils.Equals(t, 0, len(*notifications))
+
+	cost = 85
+	crossed, _ = evaluate(budget, now.Add(time.Hour))
+	utils.Equals(t, 80.0, crossed)
+	utils.Equals(t, 1, len(*notifications))
+	utils.Equals(t, notification.SeverityWarning, (*notifications)[0].Severity)
+	utils.Equals(t, "purser-budget-shop-payments", (*notifications)[0].DedupKey)
+	utils.Equals(t, "payments: 85% of budget 100.00 of namespace shop with team=payments spent in 2018-03", (*notifications)[0].Title)
+
+	cost = 90
+	crossed, changed = evaluate(budget, now.Add(2*time.Hour))
+	utils.Equals(t, 0.0, crossed)
+	utils.Assert(t, changed, "spend of budget not updated")
+	utils.Equals(t, 1, len(*notifications))
+
+	cost = 120
+	crossed, _ = evaluate(budget, now.Add(3*time.Hour))
+	utils.Equals(t, 100.0, crossed)
+	utils.Equals(t, 100.0, budget.Status.Threshold)
+	utils.Equals(t, notification.SeverityCritical, (*notifications)[1].Severity)
+	utils.Equals(t, notification.Firing, (*notifications)[1].Status)
+
+	cost = 2
+	crossed, _ = evaluate(budget, time.Date(2018, 4, 1, 1, 0, 0, 0, time.UTC))
+	utils.Equals(t, 0.0, crossed)
+	utils.Equals(t, 3, len(*notifications))
+	utils.Equals(t, notification.Resolved, (*notifications)[2].Status)
+	utils.Equals(t, "2018-03", (*notifications)[2].Details["month"])
+	utils.Equals(t, costbudget_v1.CostBudgetStatus{Month: "2018-04", MonthToDateCost: 2, PercentUsed: 2, Message: "2.00 of 100.00 spent"}, budget.Status)
+}
+
+// TestEvaluateInvalidLimit ...
+func TestEvaluateInvalidLimit(t *testing.T) {
+	cost := 50.0
+	notifications := mockCostBudget(&cost)
+	budget := &costbudget_v1.CostBudget{ObjectMeta: meta_v1.ObjectMeta{Name: "cluster", Namespace: "default"}}
+
+	crossed, _ := evaluate(budget, time.Date(2018, 3, 10, 10, 0, 0, 0, time.UTC))
+	utils.Equals(t, 0.0, crossed)
+	utils.Equals(t, "invalid limit: 0, limit must be positive", budget.Status.Message)
+	utils.Equals(t, 0, len(*notifications))
+}
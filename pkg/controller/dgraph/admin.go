@@ -0,0 +1,262 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dgraph
+
+import (
+	"context"
+	"io"
+	"sort"
+
+	"github.com/dgraph-io/dgo/protos/api"
+	qb "github.com/vmware/purser/pkg/querybuilder"
+)
+
+// maxInconsistentUIDs is the number of uids reported for each failed consistency check
+const maxInconsistentUIDs = 10
+
+// SchemaMigration lists predicates added or changed while applying purser schema and renamed predicates
+// whose data is migrated
+type SchemaMigration struct {
+	Added    []string `json:"added"`
+	Changed  []string `json:"changed"`
+	Migrated []string `json:"migrated,omitempty"`
+}
+
+// ConsistencyCheck is the result of one consistency check, UIDs has a sample of inconsistent nodes
+type ConsistencyCheck struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Count       int      `json:"count"`
+	UIDs        []string `json:"uids,omitempty"`
+}
+
+// ConsistencyReport is the result of all consistency checks
+type ConsistencyReport struct {
+	Consistent bool               `json:"consistent"`
+	Checks     []ConsistencyCheck `json:"checks"`
+}
+
+type timedResource struct {
+	ID
+	StartTime string `json:"startTime,omitempty"`
+	EndTime   string `json:"endTime,omitempty"`
+}
+
+// Reindex rebuilds indices of given predicates(all indexed predicates if none is given) by dropping
+// and setting them again. It returns the reindexed predicates.
+func Reindex(predicates []string) ([]string, error) {
+	withIndex, withoutIndex, err := getIndexDefinitions(predicates)
+	if err != nil {
+		return nil, err
+	}
+	if len(predicates) == 0 {
+		predicates = IndexedPredicates()
+	}
+
+	for i, predicate := range predicates {
+		log.Infof("rebuilding index of predicate %s", predicate)
+		if err = alter(&api.Operation{Schema: withoutIndex[i]}); err != nil {
+			return nil, err
+		}
+		if err = alter(&api.Operation{Schema: withIndex[i]}); err != nil {
+			return nil, err
+		}
+	}
+	return predicates, nil
+}
+
+// MigrateSchema applies purser schema on the existing data and returns the predicates which are added or changed.
+// Values of renamed predicates are moved to their new predicates.
+func MigrateSchema() (SchemaMigration, error) {
+	migration := SchemaMigration{}
+	before, err := retrieveSchema()
+	if err != nil {
+		return migration, err
+	}
+	if err = CreateSchema(); err != nil {
+		return migration, err
+	}
+	after, err := retrieveSchema()
+	if err != nil {
+		return migration, err
+	}
+
+	for predicate, definition := range after {
+		previous, isPresent := before[predicate]
+		if !isPresent {
+			migration.Added = append(migration.Added, predicate)
+		} else if previous != definition {
+			migration.Changed = append(migration.Changed, predicate)
+		}
+	}
+	sort.Strings(migration.Added)
+	sort.Strings(migration.Changed)
+
+	migration.Migrated, err = migrateAliases()
+	return migration, err
+}
+
+// retrieveSchema returns definition of each predicate in dgraph keyed by predicate name
+func retrieveSchema() (map[string]string, error) {
+	resp, err := client.NewReadOnlyTxn().Query(context.Background(), `schema {}`)
+	if err != nil {
+		return nil, err
+	}
+	definitions := make(map[string]string)
+	for _, node := range resp.Schema {
+		definitions[node.Predicate] = node.String()
+	}
+	return definitions, nil
+}
+
+// Backup writes all purser nodes with their predicates and edges(as uids) in json to w
+func Backup(w io.Writer) error {
+	query := qb.New(
+		qb.Func("nodes", qb.Has("xid")).
+			Fields("uid").
+			Child(qb.Edge("expand(_all_)").Fields("uid")),
+	)
+	data, err := ExecuteQueryRaw(query.String())
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// VerifyConsistency checks for nodes which are not linked as the controller links them and
+// returns the number of inconsistent nodes found by each check.
+func VerifyConsistency() (ConsistencyReport, error) {
+	report := ConsistencyReport{Consistent: true}
+	checks := []struct {
+		name        string
+		description string
+		retrieve    func() ([]string, error)
+	}{
+		{"containersWithoutPod", "containers which are not linked to a pod", func() ([]string, error) {
+			return retrieveUIDs(qb.And(qb.Has("isContainer"), qb.Not(qb.Has("pod"))))
+		}},
+		{"podsWithoutNamespace", "pods which are not linked to a namespace", func() ([]string, error) {
+			return retrieveUIDs(qb.And(qb.Has("isPod"), qb.Not(qb.Has("namespace"))))
+		}},
+		{"activePodsOnDeletedNodes", "pods without end time on nodes which have an end time", retrieveActivePodsOnDeletedNodes},
+		{"endTimeBeforeStartTime", "resources which have end time before their start time", retrieveResourcesEndedBeforeStart},
+		{"duplicateXids", "nodes having the same xid as another node", retrieveDuplicateXids},
+	}
+
+	for _, check := range checks {
+		uids, err := check.retrieve()
+		if err != nil {
+			return report, err
+		}
+		result := ConsistencyCheck{Name: check.name, Description: check.description, Count: len(uids)}
+		if len(uids) > maxInconsistentUIDs {
+			uids = uids[:maxInconsistentUIDs]
+		}
+		result.UIDs = uids
+		if result.Count > 0 {
+			report.Consistent = false
+		}
+		report.Checks = append(report.Checks, result)
+	}
+	return report, nil
+}
+
+func retrieveUIDs(filter qb.Filter) ([]string, error) {
+	query := qb.New(qb.Func("resources", filter).Fields("uid"))
+	resources, err := retrieveResources(query)
+	if err != nil {
+		return nil, err
+	}
+	return getUIDs(resources), nil
+}
+
+func retrieveActivePodsOnDeletedNodes() ([]string, error) {
+	query := qb.New(
+		qb.Func("resources", qb.Has("isPod")).
+			Filter(qb.Not(qb.Has("endTime"))).
+			Directive("@cascade").
+			Fields("uid").
+			Child(qb.Edge("node").Filter(qb.Has("endTime")).Fields("uid")),
+	)
+	resources, err := retrieveResources(query)
+	if err != nil {
+		return nil, err
+	}
+	return getUIDs(resources), nil
+}
+
+func retrieveResourcesEndedBeforeStart() ([]string, error) {
+	query := qb.New(
+		qb.Func("resources", qb.Has("endTime")).
+			Filter(qb.Has("startTime")).
+			Fields("uid", "startTime", "endTime"),
+	)
+	resources, err := retrieveResources(query)
+	if err != nil {
+		return nil, err
+	}
+	var uids []string
+	for _, r := range resources {
+		// both times are in RFC3339 UTC so they are ordered as strings
+		if r.EndTime < r.StartTime {
+			uids = append(uids, r.UID)
+		}
+	}
+	return uids, nil
+}
+
+func retrieveDuplicateXids() ([]string, error) {
+	query := qb.New(qb.Func("resources", qb.Has("xid")).Fields("uid", "xid"))
+	resources, err := retrieveResources(query)
+	if err != nil {
+		return nil, err
+	}
+	nodesOfXid := make(map[string][]string)
+	for _, r := range resources {
+		nodesOfXid[r.Xid] = append(nodesOfXid[r.Xid], r.UID)
+	}
+	var uids []string
+	for _, nodes := range nodesOfXid {
+		if len(nodes) > 1 {
+			uids = append(uids, nodes...)
+		}
+	}
+	sort.Strings(uids)
+	return uids, nil
+}
+
+func retrieveResources(query *qb.Query) ([]timedResource, error) {
+	type root struct {
+		Resources []timedResource `json:"resources"`
+	}
+	newRoot := root{}
+	err := ExecuteQuery(query.String(), &newRoot)
+	if err != nil {
+		return nil, err
+	}
+	return newRoot.Resources, nil
+}
+
+func getUIDs(resources []timedResource) []string {
+	uids := make([]string, 0, len(resources))
+	for _, r := range resources {
+		uids = append(uids, r.UID)
+	}
+	return uids
+}
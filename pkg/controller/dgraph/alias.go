@@ -0,0 +1,235 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dgraph
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// aliasSuffix is added to the key of the old predicate read next to the new one
+const aliasSuffix = "__alias"
+
+// PredicateAlias is a predicate renamed from Old to New in purser Version. Until data stored with the old
+// predicate is migrated(see MigrateSchema) queries read both predicates and responses have the value of the old
+// predicate under the key of the new one if the new one has no value.
+type PredicateAlias struct {
+	Old     string `json:"old"`
+	New     string `json:"new"`
+	Version string `json:"version"`
+}
+
+// predicateAliases are renamed predicates, new predicates are in schema and old ones aren't used by models anymore.
+// ex: {Old: "cpuRequest", New: "cpuRequests", Version: "1.1.0"}
+var predicateAliases []PredicateAlias
+
+// pendingAliases are aliases whose old predicate still has values
+var (
+	pendingAliasesMu sync.RWMutex
+	pendingAliases   []PredicateAlias
+)
+
+// PredicateAliases returns the renamed predicates
+func PredicateAliases() []PredicateAlias {
+	return predicateAliases
+}
+
+// getPendingAliases returns aliases whose old predicates are read by queries
+func getPendingAliases() []PredicateAlias {
+	pendingAliasesMu.RLock()
+	defer pendingAliasesMu.RUnlock()
+	return pendingAliases
+}
+
+func setPendingAliases(aliases []PredicateAlias) {
+	pendingAliasesMu.Lock()
+	defer pendingAliasesMu.Unlock()
+	pendingAliases = aliases
+}
+
+// updatePendingAliases finds aliases whose old predicate still has values, until they are migrated
+// queries read the old predicates too
+func updatePendingAliases() error {
+	var pending []PredicateAlias
+	for _, alias := range predicateAliases {
+		count, err := countNodesWithOldPredicate(alias)
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			log.Infof("predicate %s renamed to %s in %s has %d nodes to migrate", alias.Old, alias.New, alias.Version, count)
+			pending = append(pending, alias)
+		}
+	}
+	setPendingAliases(pending)
+	return nil
+}
+
+func countNodesWithOldPredicate(alias PredicateAlias) (int, error) {
+	q := `query {
+		resources(func: has(` + alias.Old + `)) {
+			count(uid)
+		}
+	}`
+	type root struct {
+		Resources []struct {
+			Count int `json:"count"`
+		} `json:"resources"`
+	}
+	newRoot := root{}
+	respJSON, err := queryInTxn(context.Background(), client.NewReadOnlyTxn(), q, nil)
+	if err != nil {
+		return 0, err
+	}
+	if err = json.Unmarshal(respJSON, &newRoot); err != nil || len(newRoot.Resources) == 0 {
+		return 0, err
+	}
+	return newRoot.Resources[0].Count, nil
+}
+
+// migrateAliases moves values of old predicates of aliases to the new predicates unless they have a value
+// and returns the migrated aliases with the number of nodes having the old predicate
+func migrateAliases() ([]string, error) {
+	var migrated []string
+	definitions := schemaDefinitions()
+	for _, alias := range predicateAliases {
+		isEdge := strings.Contains(definitions[alias.New], ": uid")
+		nodes, err := retrieveNodesWithOldPredicate(alias, isEdge)
+		if err != nil {
+			return migrated, err
+		}
+		if len(nodes) == 0 {
+			continue
+		}
+		var updated, deleted []map[string]interface{}
+		for _, node := range nodes {
+			if _, hasNew := node[alias.New]; !hasNew {
+				updated = append(updated, map[string]interface{}{"uid": node["uid"], alias.New: node[alias.Old]})
+			}
+			deleted = append(deleted, map[string]interface{}{"uid": node["uid"], alias.Old: nil})
+		}
+		if len(updated) > 0 {
+			if _, err = MutateNode(updated, UPDATE); err != nil {
+				return migrated, err
+			}
+		}
+		if _, err = MutateNode(deleted, DELETE); err != nil {
+			return migrated, err
+		}
+		migrated = append(migrated, fmt.Sprintf("%s -> %s(%d)", alias.Old, alias.New, len(nodes)))
+	}
+	return migrated, updatePendingAliases()
+}
+
+func retrieveNodesWithOldPredicate(alias PredicateAlias, isEdge bool) ([]map[string]interface{}, error) {
+	fields := alias.Old + "\n\t\t\t" + alias.New
+	if isEdge {
+		fields = alias.Old + " { uid }\n\t\t\t" + alias.New + " { uid }"
+	}
+	q := `query {
+		nodes(func: has(` + alias.Old + `)) {
+			uid
+			` + fields + `
+		}
+	}`
+	type root struct {
+		Nodes []map[string]interface{} `json:"nodes"`
+	}
+	newRoot := root{}
+	// old predicates are read as they are, without expanding aliases
+	respJSON, err := queryInTxn(context.Background(), client.NewReadOnlyTxn(), q, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal(respJSON, &newRoot); err != nil {
+		return nil, err
+	}
+	return newRoot.Nodes, nil
+}
+
+// expandAliases rewrites the query to read old predicates of aliases too: a selection of the new predicate,
+// ex: "cpu: cpuRequests", gets a sibling selecting the old one with aliasSuffix added to its key,
+// "cpu__alias: cpuRequest", and has(new) filters become (has(new) OR has(old)). Root functions, other functions and
+// value variables only use the new predicate. It returns whether the query has been changed.
+func expandAliases(query string, aliases []PredicateAlias) (string, bool) {
+	expanded := false
+	for _, alias := range aliases {
+		selection := regexp.MustCompile(`(?m)^([ \t]*)(?:(\w+)[ \t]*:[ \t]*)?` + regexp.QuoteMeta(alias.New) + `[ \t]*$`)
+		query = selection.ReplaceAllStringFunc(query, func(line string) string {
+			expanded = true
+			groups := selection.FindStringSubmatch(line)
+			key := groups[2]
+			if key == "" {
+				key = alias.New
+			}
+			return line + "\n" + groups[1] + key + aliasSuffix + ": " + alias.Old
+		})
+
+		hasNew := "has(" + alias.New + ")"
+		if strings.Contains(query, hasNew) {
+			rootPlaceholder := "func: has(\x00)"
+			query = strings.Replace(query, "func: "+hasNew, rootPlaceholder, -1)
+			if strings.Contains(query, hasNew) {
+				expanded = true
+				query = strings.Replace(query, hasNew, "("+hasNew+" OR has("+alias.Old+"))", -1)
+			}
+			query = strings.Replace(query, rootPlaceholder, "func: "+hasNew, -1)
+		}
+	}
+	return query, expanded
+}
+
+// normalizeAliases moves values read from old predicates by expandAliases to the keys of the new predicates
+// if they have no value, so that responses look the same whether the data is migrated or not
+func normalizeAliases(respJSON []byte) ([]byte, error) {
+	var response interface{}
+	decoder := json.NewDecoder(bytes.NewReader(respJSON))
+	decoder.UseNumber()
+	if err := decoder.Decode(&response); err != nil {
+		return nil, err
+	}
+	normalizeAliasedKeys(response)
+	return json.Marshal(response)
+}
+
+func normalizeAliasedKeys(value interface{}) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			if !strings.HasSuffix(key, aliasSuffix) {
+				normalizeAliasedKeys(child)
+				continue
+			}
+			delete(v, key)
+			newKey := strings.TrimSuffix(key, aliasSuffix)
+			if _, hasNew := v[newKey]; !hasNew {
+				normalizeAliasedKeys(child)
+				v[newKey] = child
+			}
+		}
+	case []interface{}:
+		for _, child := range v {
+			normalizeAliasedKeys(child)
+		}
+	}
+}
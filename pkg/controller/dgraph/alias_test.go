@@ -0,0 +1,68 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dgraph
+
+import (
+	"testing"
+
+	"github.com/vmware/purser/test/utils"
+)
+
+var testAliases = []PredicateAlias{{Old: "cpuRequest", New: "cpuRequests", Version: "1.1.0"}}
+
+func TestExpandAliases(t *testing.T) {
+	query := `query {
+		pods(func: has(cpuRequests)) @filter(has(isPod) AND has(cpuRequests)) {
+			name
+			cpuRequests
+			cpu: cpuRequests
+			podCpu as cpuRequests
+		}
+	}`
+	expected := `query {
+		pods(func: has(cpuRequests)) @filter(has(isPod) AND (has(cpuRequests) OR has(cpuRequest))) {
+			name
+			cpuRequests
+			cpuRequests__alias: cpuRequest
+			cpu: cpuRequests
+			cpu__alias: cpuRequest
+			podCpu as cpuRequests
+		}
+	}`
+	expanded, isExpanded := expandAliases(query, testAliases)
+	utils.Assert(t, isExpanded, "query with new predicate is not expanded")
+	utils.Equals(t, expected, expanded)
+
+	query = `query {
+		pods(func: has(isPod)) {
+			name
+		}
+	}`
+	expanded, isExpanded = expandAliases(query, testAliases)
+	utils.Assert(t, !isExpanded, "query without new predicate is expanded")
+	utils.Equals(t, query, expanded)
+}
+
+func TestNormalizeAliases(t *testing.T) {
+	response := `{"pods":[{"name":"pod-a","cpu__alias":0.5},{"name":"pod-b","cpu":0.25,"cpu__alias":1},` +
+		`{"name":"pod-c","containers":[{"memory__alias":12345678901234567}]}]}`
+	normalized, err := normalizeAliases([]byte(response))
+	utils.Ok(t, err)
+	utils.Equals(t, `{"pods":[{"cpu":0.5,"name":"pod-a"},{"cpu":0.25,"name":"pod-b"},`+
+		`{"containers":[{"memory":12345678901234567}],"name":"pod-c"}]}`, string(normalized))
+}
@@ -0,0 +1,226 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dgraph
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// ClusterPredicate is the predicate holding the name of the cluster of a node
+const ClusterPredicate = "cluster"
+
+// cluster names are interpolated in dgraph queries so only characters of k8s object names are allowed
+var clusterNameRegex = regexp.MustCompile(`^[A-Za-z0-9]([A-Za-z0-9\-._]*)?$`)
+
+var (
+	clusterMu sync.RWMutex
+	// cluster is the name of the cluster of this controller, empty if only one cluster is stored
+	cluster string
+)
+
+type clusterKey struct{}
+
+// clusterScope is the cluster which read queries of a context are restricted to
+type clusterScope struct {
+	name string
+	// withUnassigned includes nodes stored without cluster
+	withUnassigned bool
+}
+
+// SetCluster sets the name of the cluster whose resources the controller stores. Nodes created or updated with an
+// xid get it in predicate cluster and UIDs, queries and mutations of the controller only see nodes of the cluster
+// and nodes stored before it was set, which are claimed by it once they are updated.
+func SetCluster(name string) error {
+	if name != "" && !IsValidClusterName(name) {
+		return fmt.Errorf("invalid cluster name: %s", name)
+	}
+	clusterMu.Lock()
+	defer clusterMu.Unlock()
+	cluster = name
+	return nil
+}
+
+// GetCluster returns the name of the cluster of the controller, empty if none is set
+func GetCluster() string {
+	clusterMu.RLock()
+	defer clusterMu.RUnlock()
+	return cluster
+}
+
+// IsValidClusterName returns whether the name can be the name of a cluster
+func IsValidClusterName(name string) bool {
+	return clusterNameRegex.MatchString(name)
+}
+
+// WithCluster returns a copy of ctx whose read queries(see ExecuteReadQueryRawWithVars) only return nodes of the
+// cluster, ctx is returned if the cluster is empty
+func WithCluster(ctx context.Context, name string) context.Context {
+	if name == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, clusterKey{}, clusterScope{name: name})
+}
+
+// WithOwnCluster returns a copy of ctx whose read queries only return nodes of the cluster of the controller and
+// nodes stored without cluster, like queries of the write path. Jobs of the controller comparing stored resources
+// with the cluster(ex: sync) or storing results of queries(ex: cost snapshots) use it so they don't see resources
+// of other clusters. ctx is returned if the controller has no cluster.
+func WithOwnCluster(ctx context.Context) context.Context {
+	name := GetCluster()
+	if name == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, clusterKey{}, clusterScope{name: name, withUnassigned: true})
+}
+
+// ClusterFromContext returns the cluster which read queries with ctx are restricted to, empty if they aren't
+func ClusterFromContext(ctx context.Context) string {
+	scope, _ := ctx.Value(clusterKey{}).(clusterScope)
+	return scope.name
+}
+
+// getClusterFilter returns the filter of nodes of the cluster, nodes without cluster are included if
+// withUnassigned is true
+func getClusterFilter(name string, withUnassigned bool) string {
+	filter := `eq(` + ClusterPredicate + `, "` + name + `")`
+	if withUnassigned {
+		filter = `(` + filter + ` OR NOT has(` + ClusterPredicate + `))`
+	}
+	return filter
+}
+
+// scopeToCluster adds the filter to every root function of the query, ex: "pods(func: has(isPod)) @filter(
+// has(name))" becomes "pods(func: has(isPod)) @filter(<filter> AND (has(name)))". Edges only link nodes of the
+// same cluster so nested blocks need no filter.
+func scopeToCluster(query, filter string) string {
+	var scoped strings.Builder
+	rest := query
+	for {
+		index := strings.Index(rest, "(func:")
+		if index < 0 {
+			scoped.WriteString(rest)
+			return scoped.String()
+		}
+		end := matchingParen(rest, index)
+		if end < 0 {
+			scoped.WriteString(rest)
+			return scoped.String()
+		}
+		scoped.WriteString(rest[:end+1])
+		rest = rest[end+1:]
+
+		isFiltered := false
+		position := 0
+		for {
+			directive := skipSpaces(rest, position)
+			if directive >= len(rest) || rest[directive] != '@' {
+				break
+			}
+			name := directive + 1
+			for name < len(rest) && isWordChar(rest[name]) {
+				name++
+			}
+			argsEnd := name
+			if name < len(rest) && rest[name] == '(' {
+				argsEnd = matchingParen(rest, name) + 1
+				if argsEnd == 0 {
+					break
+				}
+			}
+			if rest[directive:name] == "@filter" && argsEnd > name {
+				scoped.WriteString(rest[:name+1] + filter + " AND (" + rest[name+1:argsEnd-1] + "))")
+				rest = rest[argsEnd:]
+				isFiltered = true
+				break
+			}
+			position = argsEnd
+		}
+		if !isFiltered {
+			scoped.WriteString(" @filter(" + filter + ")")
+		}
+	}
+}
+
+// matchingParen returns the index of the parenthesis closing the one at open, -1 if it isn't closed. Parentheses in
+// strings are skipped.
+func matchingParen(query string, open int) int {
+	depth := 0
+	inString := false
+	for i := open; i < len(query); i++ {
+		switch c := query[i]; {
+		case inString && c == '\\':
+			i++
+		case c == '"':
+			inString = !inString
+		case inString:
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func skipSpaces(query string, position int) int {
+	for position < len(query) && strings.IndexByte(" \t\r\n", query[position]) >= 0 {
+		position++
+	}
+	return position
+}
+
+func isWordChar(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
+
+// addCluster sets the cluster of nodes having an xid in the json of a mutation
+func addCluster(data []byte, name string) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var nodes interface{}
+	if err := decoder.Decode(&nodes); err != nil {
+		return nil, err
+	}
+	setClusterOfNodes(nodes, name)
+	return json.Marshal(nodes)
+}
+
+func setClusterOfNodes(data interface{}, name string) {
+	switch value := data.(type) {
+	case map[string]interface{}:
+		if _, hasXid := value["xid"]; hasXid {
+			value[ClusterPredicate] = name
+		}
+		for _, child := range value {
+			setClusterOfNodes(child, name)
+		}
+	case []interface{}:
+		for _, child := range value {
+			setClusterOfNodes(child, name)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dgraph
+
+import (
+	"context"
+	"testing"
+
+	"github.com/vmware/purser/test/utils"
+)
+
+func TestScopeToCluster(t *testing.T) {
+	query := `query q($name: string) {
+		pods as var(func: has(isPod)) @filter(eq(name, "pod-(a)") OR has(endTime))
+		pods(func: uid(pods)) @normalize {
+			name
+			namespace @filter(has(isNamespace)) {
+				namespace: name
+			}
+		}
+		nodes(func: has(isNode)) @cascade @filter(has(name)) {
+			name
+		}
+	}`
+	expected := `query q($name: string) {
+		pods as var(func: has(isPod)) @filter(eq(cluster, "production") AND (eq(name, "pod-(a)") OR has(endTime)))
+		pods(func: uid(pods)) @filter(eq(cluster, "production")) @normalize {
+			name
+			namespace @filter(has(isNamespace)) {
+				namespace: name
+			}
+		}
+		nodes(func: has(isNode)) @cascade @filter(eq(cluster, "production") AND (has(name))) {
+			name
+		}
+	}`
+	utils.Equals(t, expected, scopeToCluster(query, getClusterFilter("production", false)))
+}
+
+func TestGetClusterFilter(t *testing.T) {
+	utils.Equals(t, `eq(cluster, "production")`, getClusterFilter("production", false))
+	utils.Equals(t, `(eq(cluster, "production") OR NOT has(cluster))`, getClusterFilter("production", true))
+}
+
+func TestAddCluster(t *testing.T) {
+	data := []byte(`[{"xid": "default:web", "name": "pod-web", "uid": "_:pod", "cpuRequest": 0.5,
+		"namespace": {"xid": "namespace-default", "uid": "_:namespace"}, "node": {"uid": "0x1"}}, {"uid": "0x2", "endTime": "2019-01-01T00:00:00Z"}]`)
+	got, err := addCluster(data, "production")
+	utils.Ok(t, err)
+	utils.Equals(t, `[{"cluster":"production","cpuRequest":0.5,"name":"pod-web","namespace":{"cluster":"production","uid":"_:namespace","xid":"namespace-default"},"node":{"uid":"0x1"},"uid":"_:pod","xid":"default:web"},{"endTime":"2019-01-01T00:00:00Z","uid":"0x2"}]`, string(got))
+}
+
+func TestClusterContext(t *testing.T) {
+	utils.Equals(t, "", ClusterFromContext(context.Background()))
+	utils.Equals(t, "production", ClusterFromContext(WithCluster(context.Background(), "production")))
+
+	utils.Ok(t, SetCluster("staging"))
+	defer func() { utils.Ok(t, SetCluster("")) }()
+	scope := WithOwnCluster(context.Background()).Value(clusterKey{}).(clusterScope)
+	utils.Equals(t, clusterScope{name: "staging", withUnassigned: true}, scope)
+	utils.Assert(t, SetCluster("staging cluster") != nil, "invalid cluster name is set")
+	utils.Equals(t, "staging", GetCluster())
+}
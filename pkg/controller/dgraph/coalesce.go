@@ -0,0 +1,116 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dgraph
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+// readQueries coalesces identical read queries of APIs running at the same time, ex: after many dashboards refresh
+var readQueries = &queryGroup{}
+
+// getQueryKey returns the key of a query with variables for coalescing, variables are sorted by name
+func getQueryKey(query string, vars map[string]string) string {
+	if len(vars) == 0 {
+		return query
+	}
+	names := []string{}
+	for name := range vars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	key := query
+	for _, name := range names {
+		key += "\x00" + name + "=" + vars[name]
+	}
+	return key
+}
+
+// queryCall is an execution of a query shared by callers asking for the same query while it runs
+type queryCall struct {
+	done       chan struct{}
+	json       []byte
+	err        error
+	duplicates int
+	waiting    int
+	cancel     context.CancelFunc
+}
+
+// queryGroup executes a query once for all concurrent callers of it and shares the result with them
+type queryGroup struct {
+	mu    sync.Mutex
+	calls map[string]*queryCall
+}
+
+// do executes execute for the query unless an execution of the same query is running, in which case it waits for
+// that execution and returns its result. A caller whose context is done returns its error without waiting, the
+// execution is cancelled once no caller waits for it and later callers start a new execution.
+func (g *queryGroup) do(ctx context.Context, query string, execute func(ctx context.Context) ([]byte, error)) ([]byte, error) {
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = make(map[string]*queryCall)
+	}
+	c, isRunning := g.calls[query]
+	if isRunning {
+		c.duplicates++
+	} else {
+		executionCtx, cancel := context.WithCancel(context.Background())
+		c = &queryCall{done: make(chan struct{}), cancel: cancel}
+		g.calls[query] = c
+		go g.execute(executionCtx, query, c, execute)
+	}
+	c.waiting++
+	g.mu.Unlock()
+
+	select {
+	case <-c.done:
+		return c.json, c.err
+	case <-ctx.Done():
+		g.mu.Lock()
+		c.waiting--
+		if c.waiting == 0 {
+			// callers arriving after the cancel start a new execution instead of joining the cancelled one
+			c.cancel()
+			g.remove(query, c)
+		}
+		g.mu.Unlock()
+		return nil, ctx.Err()
+	}
+}
+
+func (g *queryGroup) execute(ctx context.Context, query string, c *queryCall, execute func(ctx context.Context) ([]byte, error)) {
+	c.json, c.err = execute(ctx)
+	c.cancel()
+
+	g.mu.Lock()
+	g.remove(query, c)
+	g.mu.Unlock()
+	if c.duplicates > 0 {
+		log.Debugf("query executed once for %d concurrent requests", c.duplicates+1)
+	}
+	close(c.done)
+}
+
+// remove deletes the call of the query unless it is replaced by a new execution, g.mu must be held
+func (g *queryGroup) remove(query string, c *queryCall) {
+	if g.calls[query] == c {
+		delete(g.calls, query)
+	}
+}
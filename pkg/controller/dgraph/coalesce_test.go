@@ -0,0 +1,158 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dgraph
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/vmware/purser/test/utils"
+)
+
+func TestQueryGroupCoalesces(t *testing.T) {
+	g := &queryGroup{}
+	release := make(chan struct{})
+	executions := 0
+	execute := func(ctx context.Context) ([]byte, error) {
+		executions++
+		<-release
+		return []byte(`{"pods":[]}`), nil
+	}
+
+	callers := 5
+	results := make([]string, callers)
+	errs := make([]error, callers)
+	var wg sync.WaitGroup
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			json, err := g.do(context.Background(), "pods", execute)
+			results[i], errs[i] = string(json), err
+		}(i)
+	}
+	waitForDuplicates(t, g, "pods", callers-1)
+	close(release)
+	wg.Wait()
+
+	utils.Equals(t, 1, executions)
+	for i, result := range results {
+		utils.Ok(t, errs[i])
+		utils.Equals(t, `{"pods":[]}`, result)
+	}
+	utils.Equals(t, 0, len(g.calls))
+}
+
+func TestQueryGroupExecutesSequentialAndDifferentQueries(t *testing.T) {
+	g := &queryGroup{}
+	executions := 0
+	execute := func(ctx context.Context) ([]byte, error) {
+		executions++
+		return nil, fmt.Errorf("unable to connect")
+	}
+	_, err := g.do(context.Background(), "pods", execute)
+	utils.Assert(t, err != nil, "error of execution is not returned")
+	_, _ = g.do(context.Background(), "pods", execute)
+	_, _ = g.do(context.Background(), "nodes", execute)
+	utils.Equals(t, 3, executions)
+}
+
+func TestQueryGroupCancellation(t *testing.T) {
+	g := &queryGroup{}
+	started := make(chan struct{})
+	cancelled := make(chan struct{})
+	execute := func(ctx context.Context) ([]byte, error) {
+		close(started)
+		<-ctx.Done()
+		close(cancelled)
+		return nil, ctx.Err()
+	}
+
+	first, cancelFirst := context.WithCancel(context.Background())
+	second, cancelSecond := context.WithCancel(context.Background())
+	errs := make(chan error, 2)
+	go func() {
+		_, err := g.do(first, "pods", execute)
+		errs <- err
+	}()
+	<-started
+	go func() {
+		_, err := g.do(second, "pods", execute)
+		errs <- err
+	}()
+	waitForDuplicates(t, g, "pods", 1)
+
+	cancelFirst()
+	utils.Equals(t, context.Canceled, <-errs)
+	select {
+	case <-cancelled:
+		t.Fatal("execution is cancelled while a caller waits for it")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	cancelSecond()
+	utils.Equals(t, context.Canceled, <-errs)
+	<-cancelled
+}
+
+func TestQueryGroupDoesNotJoinCancelledExecution(t *testing.T) {
+	g := &queryGroup{}
+	release := make(chan struct{})
+	execute := func(ctx context.Context) ([]byte, error) {
+		<-release
+		return nil, ctx.Err()
+	}
+	caller, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := g.do(caller, "pods", execute)
+	utils.Equals(t, context.Canceled, err)
+
+	json, err := g.do(context.Background(), "pods", func(ctx context.Context) ([]byte, error) {
+		return []byte(`{"pods":[]}`), ctx.Err()
+	})
+	utils.Ok(t, err)
+	utils.Equals(t, `{"pods":[]}`, string(json))
+	close(release)
+}
+
+func TestGetQueryKey(t *testing.T) {
+	query := `query q($name: string, $start: string) { pods(func: eq(name, $name)) { name } }`
+	utils.Equals(t, "pods", getQueryKey("pods", nil))
+	utils.Equals(t, getQueryKey(query, map[string]string{"$name": "a", "$start": "b"}),
+		getQueryKey(query, map[string]string{"$start": "b", "$name": "a"}))
+	utils.Assert(t, getQueryKey(query, map[string]string{"$name": "a"}) != getQueryKey(query, map[string]string{"$name": "b"}),
+		"queries with different values of variables are coalesced")
+}
+
+// waitForDuplicates waits until the given number of callers are waiting for the running execution of the query
+func waitForDuplicates(t *testing.T, g *queryGroup, query string, duplicates int) {
+	for i := 0; i < 1000; i++ {
+		g.mu.Lock()
+		c, isRunning := g.calls[query]
+		waiting := isRunning && c.duplicates == duplicates
+		g.mu.Unlock()
+		if waiting {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("callers of query %s are not coalesced", query)
+}
@@ -21,12 +21,12 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-
-	log "github.com/Sirupsen/logrus"
+	"time"
 
 	"github.com/dgraph-io/dgo"
 	"github.com/dgraph-io/dgo/protos/api"
 	"github.com/vmware/purser/pkg/controller/utils"
+	"github.com/vmware/purser/pkg/logging"
 	"google.golang.org/grpc"
 )
 
@@ -37,12 +37,21 @@ const (
 	DELETE = "delete"
 )
 
-// Dgraph variables
+// Dgraph variables, queries of APIs use readClient which has a pool of connections of its own so that they don't
+// contend with mutations and reads of the controller on the write path
 var (
-	client     *dgo.Dgraph
-	connection *grpc.ClientConn
+	client          *dgo.Dgraph
+	connections     []*grpc.ClientConn
+	readClient      *dgo.Dgraph
+	readConnections []*grpc.ClientConn
+	queryTimeout    = DefaultQueryTimeout
 )
 
+var log = logging.Component("dgraph")
+
+// DefaultQueryTimeout is the default deadline of queries
+const DefaultQueryTimeout = time.Minute
+
 // ID maps the external ID used in Dgraph to the UID
 type ID struct {
 	Xid string `json:"xid,omitempty"`
@@ -60,106 +69,136 @@ func Start(url string, port string) {
 	if err != nil {
 		log.Errorf("error while creating schema: %v", err)
 	}
+
+	err = updatePendingAliases()
+	if err != nil {
+		log.Errorf("error while finding predicates to migrate: %v", err)
+	}
 }
 
-// Open creates and establishes a new Dgraph connection
+// Open creates and establishes new pools of Dgraph connections(see SetPoolSize) for the write path and for read queries
 func Open(url string) error {
-	conn, err := grpc.Dial(url, grpc.WithInsecure())
+	size := getPoolSize()
+	conns, err := dialPool(url, size)
 	if err != nil {
 		return err
 	}
+	readConns, err := dialPool(url, size)
+	if err != nil {
+		closePool(conns)
+		return err
+	}
+
+	connections = conns
+	client = dgo.NewDgraphClient(newDgraphClients(connections)...)
 
-	connection = conn
-	dc := api.NewDgraphClient(connection)
-	client = dgo.NewDgraphClient(dc)
+	readConnections = readConns
+	readClient = dgo.NewDgraphClient(newDgraphClients(readConnections)...)
 
 	return nil
 }
 
-// Close terminates the Dgraph connection
+// dialPool dials size connections to Dgraph with TLS and ACL(see SetTLS and SetACL), none is left open if any of
+// them fails
+func dialPool(url string, size int) ([]*grpc.ClientConn, error) {
+	conns := []*grpc.ClientConn{}
+	for i := 0; i < size; i++ {
+		conn, err := grpc.Dial(url, getDialOptions()...)
+		if err != nil {
+			closePool(conns)
+			return nil, err
+		}
+		conns = append(conns, conn)
+	}
+	return conns, nil
+}
+
+// newDgraphClients returns a Dgraph client for each connection, dgo spreads requests over them
+func newDgraphClients(conns []*grpc.ClientConn) []api.DgraphClient {
+	clients := []api.DgraphClient{}
+	for _, conn := range conns {
+		clients = append(clients, api.NewDgraphClient(conn))
+	}
+	return clients
+}
+
+func closePool(conns []*grpc.ClientConn) {
+	for _, conn := range conns {
+		if err := conn.Close(); err != nil {
+			log.Errorf("error closing connection to Dgraph: %v", err)
+		}
+	}
+}
+
+// Close terminates the Dgraph connections
 func Close() {
-	err := connection.Close()
-	if err != nil {
-		fmt.Println("Error closing connection to Dgraph ", err)
+	closePool(connections)
+	closePool(readConnections)
+}
+
+// SetQueryTimeout sets the deadline of each query including its retries, 0 disables it. Queries of APIs also stop
+// when their request is cancelled.
+func SetQueryTimeout(timeout time.Duration) {
+	if timeout < 0 {
+		log.Errorf("dgraph query timeout can't be negative: %v", timeout)
+		return
 	}
+	queryTimeout = timeout
+}
+
+// withQueryTimeout returns ctx with the query timeout if it is set
+func withQueryTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if queryTimeout == 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, queryTimeout)
+}
+
+// newReadTxn returns a read only transaction for read queries on the read connections
+func newReadTxn() *dgo.Txn {
+	return readClient.NewReadOnlyTxn()
 }
 
 // CreateSchema sets the Dgraph schema
 func CreateSchema() error {
 	op := &api.Operation{}
-	op.Schema = `
-		name: string @index(term) .
-		username: string @index(term) .
-		xid:  string @index(term) .
-		startTime: dateTime @index(hour) .
-		endTime: dateTime @index(hour) .
-		isService: bool .
-		isPod: bool .
-		isContainer: bool .
-		isProc: bool .
-		isGroup: bool .
-		isNodePrice: bool .
-		isStoragePrice: bool .
-		isRateCard: bool .
-        isLogin: bool .
-		pod: uid @reverse .
-		namespace: uid @reverse .
-		deployment: uid @reverse .
-		replicaset: uid @reverse .
-		statefulset: uid @reverse .
-		container: uid @reverse .
-		service: uid @reverse .
-		node: uid @reverse .
-		pv: uid @reverse .
-		daemonset: uid @reverse .
-		job: uid @reverse .
-		label: uid @reverse .
-		key: string @index(term) .
-		value: string @index(term) .
-		cpu: float .
-		cpuRequest: float .
-		cpuLimit: float .
-		cpuCapacity: float .
-		cpuPrice: float .
-		memory: float .
-		memoryRequest: float .
-		memoryLimit: float .
-		memoryCapacity: float .
-		memoryPrice: float .
-		storage: float .
-		storageRequest: float .
-		storageLimit: float .
-		storageCapacity: float .
-		storagePrice: float .
-		mtdCPU: float .
-		mtdCPUCost: float .
-		mtdCost: float .
-		mtdMemory: float .
-		mtdMemoryCost: float .
-		price: float .
-		podsCount: int .
-	`
-	ctx := context.Background()
-	err := client.Alter(ctx, op)
+	op.Schema = schema
+	return alter(op)
+}
 
-	return err
+// alter alters Dgraph, retrying on transient errors
+func alter(op *api.Operation) error {
+	ctx := context.Background()
+	return withRetry(ctx, "alter", func() error {
+		return client.Alter(ctx, op)
+	})
 }
 
-// GetUID returns the UID of the node in the Dgraph
+// GetUID returns the UID of the node in the Dgraph, of the cluster of the controller if it is set(see SetCluster)
 // returns empty string if error has occurred
 func GetUID(id string, nodeType string) string {
+	filter := `has(` + nodeType + `)`
+	if name := GetCluster(); name != "" {
+		filter += ` AND ` + getClusterFilter(name, true)
+	}
 	query := `query Me($id:string, $nodeType:string) {
-		getUid(func: eq(xid, $id)) @filter(has(` + nodeType + `)) {
+		getUid(func: eq(xid, $id)) @filter(` + filter + `) {
 			uid
 		}
 	}`
 
-	ctx := context.Background()
 	variables := make(map[string]string)
 	variables["$nodeType"] = nodeType
 	variables["$id"] = id
 
-	resp, err := client.NewReadOnlyTxn().QueryWithVars(ctx, query, variables)
+	ctx, cancel := withQueryTimeout(context.Background())
+	defer cancel()
+	var resp *api.Response
+	err := withRetry(ctx, "query", func() error {
+		var err error
+		resp, err = client.NewReadOnlyTxn().QueryWithVars(ctx, query, variables)
+		return err
+	})
 	if err != nil {
 		log.Printf("failed to fetch UID from Dgraph %v", err)
 		return ""
@@ -167,27 +206,113 @@ func GetUID(id string, nodeType string) string {
 	return unmarshalDgraphResponse(resp, id)
 }
 
-// ExecuteQueryRaw given a query and it fetches and writes result into interface
+// ExecuteQueryRaw given a query and it fetches and writes result into interface.
+// It is used on the write path and sees all committed mutations.
 func ExecuteQueryRaw(query string) ([]byte, error) {
-	log.Debugf("query: (%v)", query)
-	ctx := context.Background()
+	return ExecuteQueryRawWithVars(query, nil)
+}
 
-	resp, err := client.NewTxn().Query(ctx, query)
+// ExecuteQuery given a query and it fetches and writes result into interface.
+// It is used on the write path and sees all committed mutations.
+func ExecuteQuery(query string, root interface{}) error {
+	return ExecuteQueryWithVars(query, nil, root)
+}
+
+// ExecuteQueryRawWithVars executes a query declaring variables(ex: query q($name: string)) with their values
+// keyed by name(ex: $name) on the write path and returns the response json. Only nodes of the cluster of the
+// controller are returned if it is set.
+func ExecuteQueryRawWithVars(query string, vars map[string]string) ([]byte, error) {
+	if name := GetCluster(); name != "" {
+		query = scopeToCluster(query, getClusterFilter(name, true))
+	}
+	return executeQueryRawInTxn(context.Background(), client.NewReadOnlyTxn(), query, vars)
+}
+
+// ExecuteQueryWithVars executes a query with variables like ExecuteQueryRawWithVars and writes result into root
+func ExecuteQueryWithVars(query string, vars map[string]string, root interface{}) error {
+	respJSON, err := ExecuteQueryRawWithVars(query, vars)
 	if err != nil {
-		log.Error(err)
-		return nil, err
+		return err
 	}
-	return resp.Json, nil
+	return unmarshalQueryResponse(respJSON, root)
 }
 
-// ExecuteQuery given a query and it fetches and writes result into interface
-func ExecuteQuery(query string, root interface{}) error {
-	respJSON, err := ExecuteQueryRaw(query)
+// ExecuteReadQueryRaw executes a query of APIs in a read only transaction on the read connection and returns the
+// response json. Concurrent executions of the same query share a single execution and its response, which must not
+// be modified. It returns the error of ctx once ctx is done, ex: when the client of the API disconnects.
+func ExecuteReadQueryRaw(ctx context.Context, query string) ([]byte, error) {
+	return ExecuteReadQueryRawWithVars(ctx, query, nil)
+}
+
+// ExecuteReadQuery executes a query of APIs like ExecuteReadQueryRaw and writes result into root
+func ExecuteReadQuery(ctx context.Context, query string, root interface{}) error {
+	return ExecuteReadQueryWithVars(ctx, query, nil, root)
+}
+
+// ExecuteReadQueryRawWithVars executes a query of APIs with variables like ExecuteReadQueryRaw,
+// concurrent executions share a single execution only if values of the variables are the same too.
+// Only nodes of the cluster of ctx(see WithCluster) are returned if it has one.
+func ExecuteReadQueryRawWithVars(ctx context.Context, query string, vars map[string]string) ([]byte, error) {
+	if scope, isScoped := ctx.Value(clusterKey{}).(clusterScope); isScoped {
+		query = scopeToCluster(query, getClusterFilter(scope.name, scope.withUnassigned))
+	}
+	// a shared execution logs and comments the query with the request ID of the caller starting it
+	requestID := logging.RequestID(ctx)
+	return readQueries.do(ctx, getQueryKey(query, vars), func(ctx context.Context) ([]byte, error) {
+		return executeQueryRawInTxn(logging.WithRequestID(ctx, requestID), newReadTxn(), query, vars)
+	})
+}
+
+// ExecuteReadQueryWithVars executes a query of APIs with variables like ExecuteReadQueryRawWithVars
+// and writes result into root
+func ExecuteReadQueryWithVars(ctx context.Context, query string, vars map[string]string, root interface{}) error {
+	respJSON, err := ExecuteReadQueryRawWithVars(ctx, query, vars)
 	if err != nil {
 		return err
 	}
+	return unmarshalQueryResponse(respJSON, root)
+}
 
-	err = json.Unmarshal(respJSON, root)
+// executeQueryRawInTxn executes the query reading old predicates of renamed predicates too until they are migrated
+func executeQueryRawInTxn(ctx context.Context, txn *dgo.Txn, query string, vars map[string]string) ([]byte, error) {
+	query, isExpanded := expandAliases(query, getPendingAliases())
+	respJSON, err := queryInTxn(ctx, txn, query, vars)
+	if err != nil || !isExpanded {
+		return respJSON, err
+	}
+	return normalizeAliases(respJSON)
+}
+
+// queryInTxn executes the query with the query timeout(see SetQueryTimeout), retrying it on transient errors. Queries
+// of API requests start with a comment holding the request ID so they can be found in logs of dgraph.
+func queryInTxn(ctx context.Context, txn *dgo.Txn, query string, vars map[string]string) ([]byte, error) {
+	logger := logging.WithContext(log, ctx)
+	if requestID := logging.RequestID(ctx); requestID != "" {
+		query = "# requestID: " + requestID + "\n" + query
+	}
+	logger.Debugf("query: (%v), vars: (%v)", query, vars)
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	var resp *api.Response
+	err := withRetry(ctx, "query", func() error {
+		var err error
+		resp, err = txn.QueryWithVars(ctx, query, vars)
+		return err
+	})
+	if err != nil {
+		if ctx.Err() != nil {
+			logger.Warnf("query is stopped: %v, err: %v", ctx.Err(), err)
+		} else {
+			logger.Error(err)
+		}
+		return nil, err
+	}
+	return resp.Json, nil
+}
+
+func unmarshalQueryResponse(respJSON []byte, root interface{}) error {
+	err := json.Unmarshal(respJSON, root)
 	if err != nil {
 		log.Fatal(err)
 		return err
@@ -195,13 +320,33 @@ func ExecuteQuery(query string, root interface{}) error {
 	return nil
 }
 
-// MutateNode mutates a Dgraph transaction
+// MutateNode mutates a Dgraph transaction, retrying in a new transaction on transient errors. Nodes with an xid
+// which are set get the cluster of the controller if it is set. If the mutation log is set and dgraph is unreachable,
+// or mutations queued before are not replayed yet, the mutation is queued for replay and ErrQueued is returned.
 func MutateNode(data interface{}, mutateType string) (*api.Assigned, error) {
 	bytes := utils.JSONMarshal(data)
 	if bytes == nil {
 		return nil, fmt.Errorf("unable to marshal data: %v", data)
 	}
+	if name := GetCluster(); name != "" && mutateType != DELETE {
+		var err error
+		if bytes, err = addCluster(bytes, name); err != nil {
+			return nil, fmt.Errorf("unable to add cluster to data: %v, err: %v", data, err)
+		}
+	}
 
+	if queueMutation(mutateType, bytes, nil) {
+		return nil, ErrQueued
+	}
+	assigned, err := mutate(mutateType, bytes)
+	if err != nil && queueMutation(mutateType, bytes, err) {
+		return nil, ErrQueued
+	}
+	return assigned, err
+}
+
+// mutateJSON sends the JSON data as a mutation of the type in a transaction committed immediately
+func mutateJSON(mutateType string, bytes []byte) (*api.Assigned, error) {
 	mu := &api.Mutation{
 		CommitNow: true,
 	}
@@ -213,7 +358,13 @@ func MutateNode(data interface{}, mutateType string) (*api.Assigned, error) {
 	}
 
 	ctx := context.Background()
-	return client.NewTxn().Mutate(ctx, mu)
+	var assigned *api.Assigned
+	err := withRetry(ctx, "mutation", func() error {
+		var err error
+		assigned, err = client.NewTxn().Mutate(ctx, mu)
+		return err
+	})
+	return assigned, err
 }
 
 // unmarshalDgraphResponse returns empty string if error has occurred
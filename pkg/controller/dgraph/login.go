@@ -18,7 +18,6 @@
 package dgraph
 
 import (
-	"github.com/Sirupsen/logrus"
 	"golang.org/x/crypto/bcrypt"
 )
 
@@ -44,7 +43,7 @@ func StoreLogin() {
 	if uid == "" {
 		hashedPassword, err := bcrypt.GenerateFromPassword([]byte(DefaultPassword), bcrypt.MinCost)
 		if err != nil {
-			logrus.Errorf("error while hashing login information")
+			log.Errorf("error while hashing login information")
 		}
 		login := Login{
 			ID:       ID{Xid: DefaultLoginXID},
@@ -54,7 +53,7 @@ func StoreLogin() {
 		}
 		_, err = MutateNode(login, CREATE)
 		if err != nil {
-			logrus.Errorf("error while storing login information")
+			log.Errorf("error while storing login information")
 		}
 	}
 }
@@ -0,0 +1,59 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package models
+
+import (
+	"time"
+
+	"github.com/dgraph-io/dgo/protos/api"
+	"github.com/vmware/purser/pkg/controller/dgraph"
+)
+
+// Dgraph Model Constants
+const (
+	IsAlert        = "isAlert"
+	alertXIDPrefix = "alert-"
+)
+
+// Alert is a firing or resolved alert of an alert rule, startTime is when it started firing and endTime when it resolved
+type Alert struct {
+	dgraph.ID
+	IsAlert   bool    `json:"isAlert,omitempty"`
+	Name      string  `json:"name,omitempty"`
+	Rule      string  `json:"rule,omitempty"`
+	Metric    string  `json:"metric,omitempty"`
+	Scope     string  `json:"scope,omitempty"`
+	Resource  string  `json:"resource,omitempty"`
+	Severity  string  `json:"severity,omitempty"`
+	State     string  `json:"state,omitempty"`
+	Value     float64 `json:"alertValue,omitempty"`
+	Threshold float64 `json:"threshold,omitempty"`
+	Summary   string  `json:"summary,omitempty"`
+	StartTime string  `json:"startTime,omitempty"`
+	EndTime   string  `json:"endTime,omitempty"`
+}
+
+// StoreAlert creates the alert in dgraph or updates it if already present, alerts are identified by rule and start time
+func StoreAlert(alert Alert, startsAt time.Time) (*api.Assigned, error) {
+	alert.StartTime = startsAt.Format(time.RFC3339)
+	xid := alertXIDPrefix + alert.Rule + "-" + alert.StartTime
+	alert.ID = dgraph.ID{Xid: xid, UID: dgraph.GetUID(xid, IsAlert)}
+	alert.IsAlert = true
+	alert.Name = xid
+	return dgraph.MutateNode(alert, dgraph.UPDATE)
+}
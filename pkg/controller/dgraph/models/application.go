@@ -0,0 +1,57 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package models
+
+// GitOps tools and their ownership labels
+const (
+	ArgoCD = "argocd"
+	Flux   = "flux"
+
+	ArgoCDInstanceLabel           = "argocd.argoproj.io/instance"
+	InstanceLabel                 = "app.kubernetes.io/instance"
+	FluxKustomizeNameLabel        = "kustomize.toolkit.fluxcd.io/name"
+	FluxKustomizeNamespaceLabel   = "kustomize.toolkit.fluxcd.io/namespace"
+	FluxHelmReleaseNameLabel      = "helm.toolkit.fluxcd.io/name"
+	FluxHelmReleaseNamespaceLabel = "helm.toolkit.fluxcd.io/namespace"
+)
+
+// getApplication returns the GitOps application owning an object and the tool managing it from its labels,
+// Flux applications are named <namespace>/<name> of their Kustomization or HelmRelease.
+// Empty strings are returned if it isn't managed by Argo CD or Flux.
+func getApplication(labels map[string]string) (string, string) {
+	if name := labels[FluxKustomizeNameLabel]; name != "" {
+		return getFluxApplication(labels[FluxKustomizeNamespaceLabel], name), Flux
+	}
+	if name := labels[FluxHelmReleaseNameLabel]; name != "" {
+		return getFluxApplication(labels[FluxHelmReleaseNamespaceLabel], name), Flux
+	}
+	if name := labels[ArgoCDInstanceLabel]; name != "" {
+		return name, ArgoCD
+	}
+	if name := labels[InstanceLabel]; name != "" {
+		return name, ArgoCD
+	}
+	return "", ""
+}
+
+func getFluxApplication(namespace, name string) string {
+	if namespace == "" {
+		return name
+	}
+	return namespace + "/" + name
+}
@@ -0,0 +1,38 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package models
+
+import (
+	"testing"
+
+	"github.com/vmware/purser/test/utils"
+)
+
+func TestGetApplication(t *testing.T) {
+	name, tool := getApplication(map[string]string{"app": "web"})
+	utils.Equals(t, "", name)
+	utils.Equals(t, "", tool)
+
+	name, tool = getApplication(map[string]string{InstanceLabel: "guestbook"})
+	utils.Equals(t, "guestbook", name)
+	utils.Equals(t, ArgoCD, tool)
+
+	name, tool = getApplication(map[string]string{InstanceLabel: "guestbook", FluxKustomizeNameLabel: "apps", FluxKustomizeNamespaceLabel: "flux-system"})
+	utils.Equals(t, "flux-system/apps", name)
+	utils.Equals(t, Flux, tool)
+}
@@ -0,0 +1,71 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package models
+
+import (
+	"sync"
+
+	log "github.com/Sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// Annotations of pods used by bandwidth CNI plugin to shape traffic of pods(ex: 10M)
+const (
+	IngressBandwidthAnnotation = "kubernetes.io/ingress-bandwidth"
+	EgressBandwidthAnnotation  = "kubernetes.io/egress-bandwidth"
+	bitsInMegabit              = 1000 * 1000
+)
+
+var (
+	bandwidthMu    sync.RWMutex
+	bandwidthPrice float64
+)
+
+// SetBandwidthPricing sets price per Mbps per hour of reserved bandwidth of pods.
+// Non positive price disables pricing of bandwidth.
+func SetBandwidthPricing(price float64) {
+	bandwidthMu.Lock()
+	defer bandwidthMu.Unlock()
+	if price <= 0 {
+		bandwidthPrice = 0
+		return
+	}
+	bandwidthPrice = price
+	log.Infof("bandwidth pricing: %v", bandwidthPrice)
+}
+
+// getBandwidthPrice returns hourly price of the given reserved bandwidth in Mbps
+func getBandwidthPrice(ingressBandwidth, egressBandwidth float64) float64 {
+	bandwidthMu.RLock()
+	defer bandwidthMu.RUnlock()
+	return (ingressBandwidth + egressBandwidth) * bandwidthPrice
+}
+
+// getBandwidth returns bandwidth in Mbps given by the annotation, 0 if it is absent or invalid
+func getBandwidth(annotations map[string]string, key string) float64 {
+	value, isPresent := annotations[key]
+	if !isPresent {
+		return 0
+	}
+	quantity, err := resource.ParseQuantity(value)
+	if err != nil {
+		log.Debugf("invalid bandwidth annotation %s: %s, err: %v", key, value, err)
+		return 0
+	}
+	return float64(quantity.Value()) / bitsInMegabit
+}
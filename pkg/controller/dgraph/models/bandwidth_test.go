@@ -0,0 +1,42 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package models
+
+import (
+	"testing"
+
+	"github.com/vmware/purser/test/utils"
+)
+
+func TestGetBandwidth(t *testing.T) {
+	annotations := map[string]string{
+		IngressBandwidthAnnotation: "10M",
+		EgressBandwidthAnnotation:  "1G",
+	}
+	utils.Equals(t, 10.0, getBandwidth(annotations, IngressBandwidthAnnotation))
+	utils.Equals(t, 1000.0, getBandwidth(annotations, EgressBandwidthAnnotation))
+	utils.Equals(t, 0.0, getBandwidth(map[string]string{IngressBandwidthAnnotation: "fast"}, IngressBandwidthAnnotation))
+	utils.Equals(t, 0.0, getBandwidth(nil, EgressBandwidthAnnotation))
+}
+
+func TestGetBandwidthPrice(t *testing.T) {
+	utils.Equals(t, 0.0, getBandwidthPrice(10, 20))
+	SetBandwidthPricing(0.001)
+	defer SetBandwidthPricing(0)
+	utils.Equals(t, 0.03, getBandwidthPrice(10, 20))
+}
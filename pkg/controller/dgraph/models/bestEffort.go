@@ -0,0 +1,114 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package models
+
+import (
+	"fmt"
+	"sync"
+
+	log "github.com/Sirupsen/logrus"
+)
+
+// Policies of requests of BestEffort pods(pods without cpu and memory requests and limits) used for costing
+const (
+	BestEffortRequestsNone   = "none"
+	BestEffortRequestsStatic = "static"
+	BestEffortRequestsUsage  = "usage"
+)
+
+// Default synthetic requests of BestEffort pods, cpu in cores and memory in GB
+const (
+	DefaultBestEffortCPURequest    = 0.1
+	DefaultBestEffortMemoryRequest = 0.125
+)
+
+var (
+	bestEffortMu            sync.RWMutex
+	bestEffortPolicy        = BestEffortRequestsNone
+	bestEffortCPURequest    = DefaultBestEffortCPURequest
+	bestEffortMemoryRequest = DefaultBestEffortMemoryRequest
+)
+
+// SetBestEffortRequests sets the policy of requests of BestEffort pods, with none they have no requests and no cost.
+// With static they are costed as requesting cpu(cores) and memory(GB), with usage as requesting their average usage,
+// and cpu and memory until usage of the pod is collected.
+func SetBestEffortRequests(policy string, cpu, memory float64) error {
+	switch policy {
+	case BestEffortRequestsNone, BestEffortRequestsStatic, BestEffortRequestsUsage:
+	default:
+		return fmt.Errorf("unknown policy: %s of requests of best effort pods, expected one of: %s, %s or %s",
+			policy, BestEffortRequestsNone, BestEffortRequestsStatic, BestEffortRequestsUsage)
+	}
+	if cpu < 0 || memory < 0 {
+		return fmt.Errorf("negative requests of best effort pods, cpu: %v, memory: %v", cpu, memory)
+	}
+	bestEffortMu.Lock()
+	defer bestEffortMu.Unlock()
+	bestEffortPolicy, bestEffortCPURequest, bestEffortMemoryRequest = policy, cpu, memory
+	log.Infof("requests of best effort pods, policy: %s, cpu: %v, memory: %v", policy, cpu, memory)
+	return nil
+}
+
+// isBestEffort returns true if the pod requests and limits neither cpu nor memory
+func isBestEffort(metrics Metrics) bool {
+	return metrics.CPURequest == 0 && metrics.CPULimit == 0 && metrics.MemoryRequest == 0 && metrics.MemoryLimit == 0
+}
+
+// setSyntheticRequests sets synthetic requests of the pod if it is BestEffort and the policy assigns it requests,
+// usage is the average usage stored for the pod so far. They are stored apart from cpuRequest and memoryRequest, which
+// stay the requests of the pod spec, and are only used for costing. Pods with synthetic requests are flagged with
+// SyntheticRequests.
+func setSyntheticRequests(pod *Pod, metrics Metrics, usage Pod) {
+	if !isBestEffort(metrics) {
+		return
+	}
+	bestEffortMu.RLock()
+	defer bestEffortMu.RUnlock()
+	switch bestEffortPolicy {
+	case BestEffortRequestsStatic:
+		pod.SyntheticCPURequest, pod.SyntheticMemoryRequest = bestEffortCPURequest, bestEffortMemoryRequest
+	case BestEffortRequestsUsage:
+		pod.SyntheticCPURequest, pod.SyntheticMemoryRequest = bestEffortCPURequest, bestEffortMemoryRequest
+		if usage.UsageSamples > 0 {
+			pod.SyntheticCPURequest, pod.SyntheticMemoryRequest = usage.CPUUsage, usage.MemoryUsage
+		}
+	default:
+		return
+	}
+	pod.SyntheticRequests = true
+}
+
+// isUsageBasedBestEffortRequests returns true if requests of BestEffort pods are derived from their usage
+func isUsageBasedBestEffortRequests() bool {
+	bestEffortMu.RLock()
+	defer bestEffortMu.RUnlock()
+	return bestEffortPolicy == BestEffortRequestsUsage
+}
+
+// getUsageForSyntheticRequests returns the average usage stored for the pod with given uid if requests of BestEffort
+// pods are derived from usage and the pod is BestEffort
+func getUsageForSyntheticRequests(uid string, metrics Metrics) Pod {
+	if !isBestEffort(metrics) || !isUsageBasedBestEffortRequests() {
+		return Pod{}
+	}
+	usage, err := retrievePodUsage(uid)
+	if err != nil {
+		log.Errorf("unable to retrieve usage of pod with uid: %s, err: %v", uid, err)
+	}
+	return usage
+}
@@ -0,0 +1,55 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package models
+
+import (
+	"testing"
+
+	"github.com/vmware/purser/test/utils"
+)
+
+func TestSetSyntheticRequests(t *testing.T) {
+	defer SetBestEffortRequests(BestEffortRequestsNone, DefaultBestEffortCPURequest, DefaultBestEffortMemoryRequest)
+	usage := Pod{CPUUsage: 0.3, MemoryUsage: 0.5, UsageSamples: 2}
+
+	pod := Pod{}
+	setSyntheticRequests(&pod, Metrics{}, usage)
+	utils.Equals(t, Pod{}, pod)
+
+	utils.Ok(t, SetBestEffortRequests(BestEffortRequestsStatic, 0.2, 0.25))
+	setSyntheticRequests(&pod, Metrics{}, usage)
+	utils.Equals(t, Pod{SyntheticCPURequest: 0.2, SyntheticMemoryRequest: 0.25, SyntheticRequests: true}, pod)
+
+	pod = Pod{CPURequest: 0.5}
+	setSyntheticRequests(&pod, Metrics{CPURequest: 0.5}, usage)
+	utils.Equals(t, Pod{CPURequest: 0.5}, pod)
+
+	utils.Ok(t, SetBestEffortRequests(BestEffortRequestsUsage, 0.2, 0.25))
+	pod = Pod{}
+	setSyntheticRequests(&pod, Metrics{}, usage)
+	utils.Equals(t, Pod{SyntheticCPURequest: 0.3, SyntheticMemoryRequest: 0.5, SyntheticRequests: true}, pod)
+
+	pod = Pod{}
+	setSyntheticRequests(&pod, Metrics{}, Pod{})
+	utils.Equals(t, Pod{SyntheticCPURequest: 0.2, SyntheticMemoryRequest: 0.25, SyntheticRequests: true}, pod)
+}
+
+func TestSetBestEffortRequestsWithInvalidPolicy(t *testing.T) {
+	utils.Assert(t, SetBestEffortRequests("guess", 0.1, 0.1) != nil, "expected error for unknown policy")
+	utils.Assert(t, SetBestEffortRequests(BestEffortRequestsStatic, -1, 0.1) != nil, "expected error for negative request")
+}
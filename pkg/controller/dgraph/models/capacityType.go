@@ -0,0 +1,102 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package models
+
+import (
+	"sync"
+
+	log "github.com/Sirupsen/logrus"
+	api_v1 "k8s.io/api/core/v1"
+)
+
+// Capacity types of nodes, nodes are on-demand unless their labels say otherwise
+const (
+	OnDemandCapacity = "on-demand"
+	SpotCapacity     = "spot"
+	ReservedCapacity = "reserved"
+)
+
+// CapacityTypeLabelKey is the label setting the capacity type of a node where the cloud doesn't label it, ex: reserved
+const CapacityTypeLabelKey = "purser.vmware.com/capacity-type"
+
+// Default discounts on on-demand prices, taken from typical AWS spot savings and 1 year reserved instances
+const (
+	DefaultSpotDiscount     = 0.7
+	DefaultReservedDiscount = 0.4
+)
+
+// capacityTypeLabel is a node label identifying its capacity type, values map label values to capacity types
+type capacityTypeLabel struct {
+	key    string
+	values map[string]string
+}
+
+// capacityTypeLabels are checked in order, the first present with a known value decides
+var capacityTypeLabels = []capacityTypeLabel{
+	{CapacityTypeLabelKey, map[string]string{OnDemandCapacity: OnDemandCapacity, SpotCapacity: SpotCapacity, ReservedCapacity: ReservedCapacity}},
+	{"karpenter.sh/capacity-type", map[string]string{OnDemandCapacity: OnDemandCapacity, SpotCapacity: SpotCapacity, ReservedCapacity: ReservedCapacity}},
+	{"eks.amazonaws.com/capacityType", map[string]string{"ON_DEMAND": OnDemandCapacity, "SPOT": SpotCapacity}},
+	{"cloud.google.com/gke-spot", map[string]string{"true": SpotCapacity}},
+	{"cloud.google.com/gke-preemptible", map[string]string{"true": SpotCapacity}},
+	{"kubernetes.azure.com/scalesetpriority", map[string]string{"spot": SpotCapacity, "regular": OnDemandCapacity}},
+	{"node.kubernetes.io/lifecycle", map[string]string{"spot": SpotCapacity, "normal": OnDemandCapacity}},
+}
+
+var (
+	capacityTypeMu   sync.RWMutex
+	spotDiscount     = DefaultSpotDiscount
+	reservedDiscount = DefaultReservedDiscount
+)
+
+// SetCapacityTypeDiscounts sets the fraction of on-demand prices saved by spot and reserved nodes.
+// Discounts outside [0, 1) are ignored and defaults are retained.
+func SetCapacityTypeDiscounts(spot, reserved float64) {
+	capacityTypeMu.Lock()
+	defer capacityTypeMu.Unlock()
+	if spot >= 0 && spot < 1 {
+		spotDiscount = spot
+	}
+	if reserved >= 0 && reserved < 1 {
+		reservedDiscount = reserved
+	}
+	log.Infof("capacity type discounts, spot: %v, reserved: %v", spotDiscount, reservedDiscount)
+}
+
+// getCapacityType returns the capacity type of the node from its labels
+func getCapacityType(node api_v1.Node) string {
+	nodeLabels := node.GetLabels()
+	for _, label := range capacityTypeLabels {
+		if capacityType, isKnown := label.values[nodeLabels[label.key]]; isKnown {
+			return capacityType
+		}
+	}
+	return OnDemandCapacity
+}
+
+// getCapacityTypeFactor returns the factor converting on-demand prices to prices of the capacity type
+func getCapacityTypeFactor(capacityType string) float64 {
+	capacityTypeMu.RLock()
+	defer capacityTypeMu.RUnlock()
+	switch capacityType {
+	case SpotCapacity:
+		return 1 - spotDiscount
+	case ReservedCapacity:
+		return 1 - reservedDiscount
+	}
+	return 1
+}
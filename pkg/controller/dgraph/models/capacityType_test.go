@@ -0,0 +1,58 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package models
+
+import (
+	"testing"
+
+	"github.com/vmware/purser/test/utils"
+	api_v1 "k8s.io/api/core/v1"
+)
+
+func TestGetCapacityType(t *testing.T) {
+	node := api_v1.Node{}
+	utils.Equals(t, OnDemandCapacity, getCapacityType(node))
+
+	node.Labels = map[string]string{"eks.amazonaws.com/capacityType": "SPOT"}
+	utils.Equals(t, SpotCapacity, getCapacityType(node))
+
+	node.Labels = map[string]string{"cloud.google.com/gke-preemptible": "true"}
+	utils.Equals(t, SpotCapacity, getCapacityType(node))
+
+	node.Labels = map[string]string{"kubernetes.azure.com/scalesetpriority": "spot"}
+	utils.Equals(t, SpotCapacity, getCapacityType(node))
+
+	node.Labels = map[string]string{CapacityTypeLabelKey: ReservedCapacity, "karpenter.sh/capacity-type": SpotCapacity}
+	utils.Equals(t, ReservedCapacity, getCapacityType(node))
+
+	node.Labels = map[string]string{"karpenter.sh/capacity-type": "unknown"}
+	utils.Equals(t, OnDemandCapacity, getCapacityType(node))
+}
+
+func TestGetCapacityTypeFactor(t *testing.T) {
+	SetCapacityTypeDiscounts(0.6, 0.25)
+	defer SetCapacityTypeDiscounts(DefaultSpotDiscount, DefaultReservedDiscount)
+	utils.Equals(t, 1.0, getCapacityTypeFactor(OnDemandCapacity))
+	utils.Equals(t, 1.0, getCapacityTypeFactor(""))
+	utils.Equals(t, 0.4, getCapacityTypeFactor(SpotCapacity))
+	utils.Equals(t, 0.75, getCapacityTypeFactor(ReservedCapacity))
+
+	SetCapacityTypeDiscounts(1.5, -1)
+	utils.Equals(t, 0.4, getCapacityTypeFactor(SpotCapacity))
+	utils.Equals(t, 0.75, getCapacityTypeFactor(ReservedCapacity))
+}
@@ -9,9 +9,13 @@ const (
 	DefaultCPUCostInFloat64        = 0.024
 	DefaultMemCostInFloat64        = 0.01
 	DefaultStorageCostInFloat64    = 0.00013888888
+	DefaultGPUCostPerGPUPerHour    = "0.9"
+	DefaultGPUCostInFloat64        = 0.9
 
 	// Cloud provider constants
-	AWS = "aws"
+	AWS   = "aws"
+	GCP   = "gcp"
+	Azure = "azure"
 
 	// Time constants
 	HoursInMonth = 720
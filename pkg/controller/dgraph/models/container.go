@@ -37,35 +37,58 @@ const (
 // Container schema in dgraph
 type Container struct {
 	dgraph.ID
-	IsContainer   bool       `json:"isContainer,omitempty"`
-	Name          string     `json:"name,omitempty"`
-	StartTime     string     `json:"startTime,omitempty"`
-	EndTime       string     `json:"endTime,omitempty"`
-	Pod           Pod        `json:"pod,omitempty"`
-	Procs         []*Proc    `json:"procs,omitempty"`
-	Namespace     *Namespace `json:"namespace,omitempty"`
-	CPURequest    float64    `json:"cpuRequest,omitempty"`
-	CPULimit      float64    `json:"cpuLimit,omitempty"`
-	MemoryRequest float64    `json:"memoryRequest,omitempty"`
-	MemoryLimit   float64    `json:"memoryLimit,omitempty"`
-	Type          string     `json:"type,omitempty"`
+	IsContainer             bool       `json:"isContainer,omitempty"`
+	Name                    string     `json:"name,omitempty"`
+	StartTime               string     `json:"startTime,omitempty"`
+	EndTime                 string     `json:"endTime,omitempty"`
+	Pod                     Pod        `json:"pod,omitempty"`
+	Procs                   []*Proc    `json:"procs,omitempty"`
+	Namespace               *Namespace `json:"namespace,omitempty"`
+	CPURequest              float64    `json:"cpuRequest,omitempty"`
+	CPULimit                float64    `json:"cpuLimit,omitempty"`
+	MemoryRequest           float64    `json:"memoryRequest,omitempty"`
+	MemoryLimit             float64    `json:"memoryLimit,omitempty"`
+	Type                    string     `json:"type,omitempty"`
+	OS                      string     `json:"os,omitempty"`
+	EphemeralStorageRequest float64    `json:"ephemeralStorageRequest,omitempty"`
+	EphemeralStorageLimit   float64    `json:"ephemeralStorageLimit,omitempty"`
+	HugepagesRequest        float64    `json:"hugepagesRequest,omitempty"`
+	HugepagesLimit          float64    `json:"hugepagesLimit,omitempty"`
+	GPURequest              float64    `json:"gpuRequest,omitempty"`
+	ExtendedResourcePrice   float64    `json:"extendedResourcePrice,omitempty"`
+	CPUPrice                float64    `json:"cpuPrice,omitempty"`
+	MemoryPrice             float64    `json:"memoryPrice,omitempty"`
+	Image                   string     `json:"image,omitempty"`
+	ImageRegistry           string     `json:"imageRegistry,omitempty"`
+	ImageRepository         string     `json:"imageRepository,omitempty"`
+	ImageTag                string     `json:"imageTag,omitempty"`
 }
 
-func newContainer(container api_v1.Container, podUID, namespaceUID string, pod api_v1.Pod) (*api.Assigned, error) {
+func newContainer(container api_v1.Container, podUID, namespaceUID string, pod api_v1.Pod, os string) (*api.Assigned, error) {
 	containerXid := pod.Namespace + ":" + pod.Name + ":" + container.Name
-	requests := container.Resources.Requests
-	limits := container.Resources.Limits
+	res := getContainerResources(container)
 	c := &Container{
-		ID:            dgraph.ID{Xid: containerXid},
-		Name:          "container-" + container.Name,
-		IsContainer:   true,
-		Type:          "container",
-		StartTime:     pod.GetCreationTimestamp().Time.Format(time.RFC3339),
-		Pod:           Pod{ID: dgraph.ID{UID: podUID, Xid: pod.Namespace + ":" + pod.Name}},
-		CPURequest:    utils.ConvertToFloat64CPU(requests.Cpu()),
-		CPULimit:      utils.ConvertToFloat64CPU(limits.Cpu()),
-		MemoryRequest: utils.ConvertToFloat64GB(requests.Memory()),
-		MemoryLimit:   utils.ConvertToFloat64GB(limits.Memory()),
+		ID:                      dgraph.ID{Xid: containerXid},
+		Name:                    "container-" + container.Name,
+		IsContainer:             true,
+		Type:                    "container",
+		StartTime:               pod.GetCreationTimestamp().Time.Format(time.RFC3339),
+		Pod:                     Pod{ID: dgraph.ID{UID: podUID, Xid: pod.Namespace + ":" + pod.Name}},
+		CPURequest:              utils.ConvertToFloat64CPU(res.cpuRequest),
+		CPULimit:                utils.ConvertToFloat64CPU(res.cpuLimit),
+		MemoryRequest:           utils.ConvertToFloat64GB(res.memoryRequest),
+		MemoryLimit:             utils.ConvertToFloat64GB(res.memoryLimit),
+		OS:                      os,
+		EphemeralStorageRequest: utils.ConvertToFloat64GB(res.ephemeralStorageRequest),
+		EphemeralStorageLimit:   utils.ConvertToFloat64GB(res.ephemeralStorageLimit),
+		HugepagesRequest:        utils.ConvertToFloat64GB(res.hugepagesRequest),
+		HugepagesLimit:          utils.ConvertToFloat64GB(res.hugepagesLimit),
+		GPURequest:              getGPUCount(res.gpuRequest),
+		ExtendedResourcePrice:   res.extendedResourcePrice,
+	}
+	if container.Image != "" {
+		c.Image = container.Image
+		c.ImageRegistry, c.ImageRepository, c.ImageTag = ParseImage(container.Image)
 	}
 	if namespaceUID != "" {
 		c.Namespace = &Namespace{ID: dgraph.ID{UID: namespaceUID, Xid: pod.Namespace}}
@@ -75,30 +98,48 @@ func newContainer(container api_v1.Container, podUID, namespaceUID string, pod a
 
 // StoreAndRetrieveContainersAndMetrics fetchs the list of containers in given pod
 // Create a new container in dgraph if container is not in it.
-func StoreAndRetrieveContainersAndMetrics(pod api_v1.Pod, podUID, namespaceUID string) ([]*Container, Metrics) {
+// Containers get the given os of the pod, which is updated with the pod once it is scheduled.
+func StoreAndRetrieveContainersAndMetrics(pod api_v1.Pod, podUID, namespaceUID, os string) ([]*Container, Metrics) {
 	containers := []*Container{}
 	cpuRequest := &resource.Quantity{}
 	memoryRequest := &resource.Quantity{}
 	cpuLimit := &resource.Quantity{}
 	memoryLimit := &resource.Quantity{}
+	ephemeralStorageRequest := &resource.Quantity{}
+	ephemeralStorageLimit := &resource.Quantity{}
+	hugepagesRequest := &resource.Quantity{}
+	hugepagesLimit := &resource.Quantity{}
+	gpuRequest := &resource.Quantity{}
+	extendedResourcePrice := 0.0
 
 	for _, c := range pod.Spec.Containers {
-		container, err := storeContainerIfNotExist(c, pod, podUID, namespaceUID)
+		container, err := storeContainerIfNotExist(c, pod, podUID, namespaceUID, os)
 		if err == nil {
 			containers = append(containers, container)
 		}
-		requests := c.Resources.Requests
-		limits := c.Resources.Limits
-		utils.AddResourceAToResourceB(requests.Cpu(), cpuRequest)
-		utils.AddResourceAToResourceB(requests.Memory(), memoryRequest)
-		utils.AddResourceAToResourceB(limits.Cpu(), cpuLimit)
-		utils.AddResourceAToResourceB(limits.Memory(), memoryLimit)
+		res := getContainerResources(c)
+		utils.AddResourceAToResourceB(res.cpuRequest, cpuRequest)
+		utils.AddResourceAToResourceB(res.memoryRequest, memoryRequest)
+		utils.AddResourceAToResourceB(res.cpuLimit, cpuLimit)
+		utils.AddResourceAToResourceB(res.memoryLimit, memoryLimit)
+		utils.AddResourceAToResourceB(res.ephemeralStorageRequest, ephemeralStorageRequest)
+		utils.AddResourceAToResourceB(res.ephemeralStorageLimit, ephemeralStorageLimit)
+		utils.AddResourceAToResourceB(res.hugepagesRequest, hugepagesRequest)
+		utils.AddResourceAToResourceB(res.hugepagesLimit, hugepagesLimit)
+		utils.AddResourceAToResourceB(res.gpuRequest, gpuRequest)
+		extendedResourcePrice += res.extendedResourcePrice
 	}
 	return containers, Metrics{
-		CPURequest:    utils.ConvertToFloat64CPU(cpuRequest),
-		CPULimit:      utils.ConvertToFloat64CPU(cpuLimit),
-		MemoryRequest: utils.ConvertToFloat64GB(memoryRequest),
-		MemoryLimit:   utils.ConvertToFloat64GB(memoryLimit),
+		CPURequest:              utils.ConvertToFloat64CPU(cpuRequest),
+		CPULimit:                utils.ConvertToFloat64CPU(cpuLimit),
+		MemoryRequest:           utils.ConvertToFloat64GB(memoryRequest),
+		MemoryLimit:             utils.ConvertToFloat64GB(memoryLimit),
+		EphemeralStorageRequest: utils.ConvertToFloat64GB(ephemeralStorageRequest),
+		EphemeralStorageLimit:   utils.ConvertToFloat64GB(ephemeralStorageLimit),
+		HugepagesRequest:        utils.ConvertToFloat64GB(hugepagesRequest),
+		HugepagesLimit:          utils.ConvertToFloat64GB(hugepagesLimit),
+		GPURequest:              getGPUCount(gpuRequest),
+		ExtendedResourcePrice:   extendedResourcePrice,
 	}
 }
 
@@ -118,14 +159,14 @@ func StoreContainerProcessEdge(containerXID string, procsXIDs []string) error {
 	return err
 }
 
-func storeContainerIfNotExist(c api_v1.Container, pod api_v1.Pod, podUID, namespaceUID string) (*Container, error) {
+func storeContainerIfNotExist(c api_v1.Container, pod api_v1.Pod, podUID, namespaceUID, os string) (*Container, error) {
 	podXid := pod.Namespace + ":" + pod.Name
 	containerXid := podXid + ":" + c.Name
 	containerUID := dgraph.GetUID(containerXid, IsContainer)
 
 	var container *Container
 	if containerUID == "" {
-		assigned, err := newContainer(c, podUID, namespaceUID, pod)
+		assigned, err := newContainer(c, podUID, namespaceUID, pod, os)
 		if err != nil {
 			log.Errorf("Unable to create container: %s", containerXid)
 			return container, err
@@ -136,7 +177,9 @@ func storeContainerIfNotExist(c api_v1.Container, pod api_v1.Pod, podUID, namesp
 
 	container = &Container{
 		ID: dgraph.ID{UID: containerUID, Xid: containerXid},
+		OS: os,
 	}
+	container.CPUPrice, container.MemoryPrice = getContainerPrices(c)
 	return container, nil
 }
 
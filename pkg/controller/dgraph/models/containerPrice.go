@@ -0,0 +1,89 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package models
+
+import (
+	"path"
+	"sync"
+
+	api_v1 "k8s.io/api/core/v1"
+)
+
+// ContainerPriceOverride overrides per unit resource prices of containers whose image and name match the given
+// patterns(path.Match syntax, ex: nvcr.io/nvidia/*). Empty pattern matches all, prices which are not given are
+// taken from the pod. Unit of prices should be USD($)-(per unit resource)-(per Hour)
+type ContainerPriceOverride struct {
+	Image       string  `json:"image,omitempty"`
+	Name        string  `json:"name,omitempty"`
+	CPUPrice    float64 `json:"cpuPrice,omitempty"`
+	MemoryPrice float64 `json:"memoryPrice,omitempty"`
+}
+
+// ContainerPriceOverrides holds container price overrides, first matching override is used for a container
+type ContainerPriceOverrides struct {
+	Overrides []ContainerPriceOverride `json:"overrides"`
+}
+
+var (
+	containerPriceMu        sync.RWMutex
+	containerPriceOverrides []ContainerPriceOverride
+)
+
+// SetContainerPriceOverrides sets price overrides of containers
+func SetContainerPriceOverrides(overrides ContainerPriceOverrides) {
+	containerPriceMu.Lock()
+	defer containerPriceMu.Unlock()
+	containerPriceOverrides = overrides.Overrides
+}
+
+// Matches returns true if image and name of the container match the override
+func (o ContainerPriceOverride) Matches(c api_v1.Container) bool {
+	return matchesPattern(o.Image, c.Image) && matchesPattern(o.Name, c.Name)
+}
+
+func matchesPattern(pattern, value string) bool {
+	if pattern == "" {
+		return true
+	}
+	matched, err := path.Match(pattern, value)
+	return err == nil && matched
+}
+
+// getContainerPrices returns overridden price per cpu and price per memory of the container, 0 if not overridden
+func getContainerPrices(c api_v1.Container) (float64, float64) {
+	containerPriceMu.RLock()
+	defer containerPriceMu.RUnlock()
+	for _, override := range containerPriceOverrides {
+		if override.Matches(c) {
+			return override.CPUPrice, override.MemoryPrice
+		}
+	}
+	return 0, 0
+}
+
+// populateContainerPrices sets prices of the pod on its containers whose prices aren't overridden
+func populateContainerPrices(pod *Pod) {
+	for _, container := range pod.Containers {
+		if container.CPUPrice == 0 {
+			container.CPUPrice = pod.CPUPrice
+		}
+		if container.MemoryPrice == 0 {
+			container.MemoryPrice = pod.MemoryPrice
+		}
+	}
+}
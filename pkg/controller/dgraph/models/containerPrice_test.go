@@ -0,0 +1,56 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package models
+
+import (
+	"testing"
+
+	"github.com/vmware/purser/test/utils"
+	api_v1 "k8s.io/api/core/v1"
+)
+
+func TestGetContainerPrices(t *testing.T) {
+	SetContainerPriceOverrides(ContainerPriceOverrides{Overrides: []ContainerPriceOverride{
+		{Image: "nvcr.io/nvidia/*", CPUPrice: 0.5, MemoryPrice: 0.05},
+		{Name: "sidecar-*", CPUPrice: 0.01},
+	}})
+	defer SetContainerPriceOverrides(ContainerPriceOverrides{})
+
+	cpuPrice, memoryPrice := getContainerPrices(api_v1.Container{Name: "trainer", Image: "nvcr.io/nvidia/pytorch:23.08"})
+	utils.Equals(t, 0.5, cpuPrice)
+	utils.Equals(t, 0.05, memoryPrice)
+	cpuPrice, memoryPrice = getContainerPrices(api_v1.Container{Name: "sidecar-proxy", Image: "envoy:1.27"})
+	utils.Equals(t, 0.01, cpuPrice)
+	utils.Equals(t, 0.0, memoryPrice)
+	cpuPrice, memoryPrice = getContainerPrices(api_v1.Container{Name: "web", Image: "nginx:1.25"})
+	utils.Equals(t, 0.0, cpuPrice)
+	utils.Equals(t, 0.0, memoryPrice)
+}
+
+func TestPopulateContainerPrices(t *testing.T) {
+	pod := &Pod{
+		CPUPrice:    0.024,
+		MemoryPrice: 0.01,
+		Containers:  []*Container{{CPUPrice: 0.5}, {}},
+	}
+	populateContainerPrices(pod)
+	utils.Equals(t, 0.5, pod.Containers[0].CPUPrice)
+	utils.Equals(t, 0.01, pod.Containers[0].MemoryPrice)
+	utils.Equals(t, 0.024, pod.Containers[1].CPUPrice)
+	utils.Equals(t, 0.01, pod.Containers[1].MemoryPrice)
+}
@@ -0,0 +1,100 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package models
+
+import (
+	"time"
+
+	"github.com/vmware/purser/pkg/controller/dgraph"
+)
+
+// Dgraph Model Constants
+const (
+	IsCostSnapshot        = "isCostSnapshot"
+	costSnapshotXIDPrefix = "cost-snapshot-"
+	costSnapshotBatchSize = 500
+)
+
+// CostSnapshot is the cost of a pod, namespace or node over a day, cost is the sum of cpu, memory and storage cost.
+// Names are names of resources in dgraph(ex: namespace-shop), namespace is the namespace of pods.
+type CostSnapshot struct {
+	dgraph.ID
+	IsCostSnapshot bool    `json:"isCostSnapshot,omitempty"`
+	ResourceType   string  `json:"resourceType,omitempty"`
+	ResourceName   string  `json:"resourceName,omitempty"`
+	Namespace      string  `json:"snapshotNamespace,omitempty"`
+	Day            string  `json:"day,omitempty"`
+	CPUCost        float64 `json:"cpuCost,omitempty"`
+	MemoryCost     float64 `json:"memoryCost,omitempty"`
+	StorageCost    float64 `json:"storageCost,omitempty"`
+	Cost           float64 `json:"cost,omitempty"`
+}
+
+// StoreCostSnapshots stores snapshots of resources for the day, replacing snapshots of the same resources stored
+// for that day before
+func StoreCostSnapshots(day time.Time, snapshots []CostSnapshot) error {
+	day = time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, day.Location())
+	uids, err := retrieveCostSnapshotUIDs(day)
+	if err != nil {
+		return err
+	}
+
+	for start := 0; start < len(snapshots); start += costSnapshotBatchSize {
+		end := start + costSnapshotBatchSize
+		if end > len(snapshots) {
+			end = len(snapshots)
+		}
+		batch := make([]CostSnapshot, 0, end-start)
+		for _, snapshot := range snapshots[start:end] {
+			xid := costSnapshotXIDPrefix + snapshot.ResourceType + ":" + snapshot.Namespace + "/" +
+				snapshot.ResourceName + ":" + day.Format(dayFormat)
+			snapshot.ID = dgraph.ID{UID: uids[xid], Xid: xid}
+			snapshot.IsCostSnapshot = true
+			snapshot.Day = day.Format(time.RFC3339)
+			snapshot.Cost = snapshot.CPUCost + snapshot.MemoryCost + snapshot.StorageCost
+			batch = append(batch, snapshot)
+		}
+		if _, err := dgraph.MutateNode(batch, dgraph.CREATE); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// retrieveCostSnapshotUIDs returns uids of snapshots of the day keyed by their xid
+func retrieveCostSnapshotUIDs(day time.Time) (map[string]string, error) {
+	q := `query {
+		snapshots(func: eq(day, "` + day.Format(time.RFC3339) + `")) @filter(has(isCostSnapshot)) {
+			uid
+			xid
+		}
+	}`
+	type root struct {
+		Snapshots []CostSnapshot `json:"snapshots"`
+	}
+	newRoot := root{}
+	err := dgraph.ExecuteQuery(q, &newRoot)
+	if err != nil {
+		return nil, err
+	}
+	uids := make(map[string]string, len(newRoot.Snapshots))
+	for _, snapshot := range newRoot.Snapshots {
+		uids[snapshot.Xid] = snapshot.UID
+	}
+	return uids, nil
+}
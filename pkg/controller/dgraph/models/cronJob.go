@@ -0,0 +1,69 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package models
+
+import (
+	log "github.com/Sirupsen/logrus"
+	"github.com/vmware/purser/pkg/controller/dgraph"
+)
+
+// Dgraph Model Constants
+const (
+	IsCronJob = "isCronJob"
+)
+
+// CronJob schema in dgraph, it is created from owner references of its jobs so that costs of all jobs it has
+// spawned(ex: deleted ones) can be rolled up to it
+type CronJob struct {
+	dgraph.ID
+	IsCronJob bool       `json:"isCronJob,omitempty"`
+	Name      string     `json:"name,omitempty"`
+	Namespace *Namespace `json:"namespace,omitempty"`
+	Jobs      []*Job     `json:"job,omitempty"`
+	Type      string     `json:"type,omitempty"`
+}
+
+// CreateOrGetCronJob returns the uid of the cronjob of the namespace if exists,
+// otherwise creates the cronjob and returns uid.
+func CreateOrGetCronJob(namespace, name string) string {
+	if name == "" {
+		return ""
+	}
+	xid := namespace + ":" + name
+	uid := dgraph.GetUID(xid, IsCronJob)
+	if uid != "" {
+		return uid
+	}
+
+	cronJob := CronJob{
+		ID:        dgraph.ID{Xid: xid},
+		Name:      "cronjob-" + name,
+		IsCronJob: true,
+		Type:      "cronjob",
+	}
+	namespaceUID := CreateOrGetNamespaceByID(namespace)
+	if namespaceUID != "" {
+		cronJob.Namespace = &Namespace{ID: dgraph.ID{UID: namespaceUID, Xid: namespace}}
+	}
+	assigned, err := dgraph.MutateNode(cronJob, dgraph.CREATE)
+	if err != nil {
+		log.Errorf("unable to store cronjob: %s, err: %v", xid, err)
+		return ""
+	}
+	return assigned.Uids["blank-0"]
+}
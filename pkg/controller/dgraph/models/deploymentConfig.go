@@ -0,0 +1,142 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package models
+
+import (
+	"strings"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	openshift_v1 "github.com/vmware/purser/pkg/apis/openshift/v1"
+	"github.com/vmware/purser/pkg/controller/dgraph"
+	api_v1 "k8s.io/api/core/v1"
+)
+
+// Dgraph Model Constants
+const (
+	IsDeploymentConfig = "isDeploymentConfig"
+)
+
+// DeploymentConfig schema in dgraph
+type DeploymentConfig struct {
+	dgraph.ID
+	IsDeploymentConfig bool           `json:"isDeploymentConfig,omitempty"`
+	Name               string         `json:"name,omitempty"`
+	StartTime          string         `json:"startTime,omitempty"`
+	EndTime            string         `json:"endTime,omitempty"`
+	Namespace          *Namespace     `json:"namespace,omitempty"`
+	ImageStreams       []*ImageStream `json:"imagestream,omitempty"`
+	Type               string         `json:"type,omitempty"`
+}
+
+func createDeploymentConfigObject(dc openshift_v1.DeploymentConfig) DeploymentConfig {
+	newDeploymentConfig := DeploymentConfig{
+		Name:               "deploymentconfig-" + dc.Name,
+		IsDeploymentConfig: true,
+		Type:               "deploymentconfig",
+		ID:                 dgraph.ID{Xid: dc.Namespace + ":" + dc.Name},
+		StartTime:          dc.GetCreationTimestamp().Time.Format(time.RFC3339),
+		ImageStreams:       getDeploymentConfigImageStreams(dc),
+	}
+	namespaceUID := CreateOrGetNamespaceByID(dc.Namespace)
+	if namespaceUID != "" {
+		newDeploymentConfig.Namespace = &Namespace{ID: dgraph.ID{UID: namespaceUID, Xid: dc.Namespace}}
+	}
+	dcDeletionTimestamp := dc.GetDeletionTimestamp()
+	if !dcDeletionTimestamp.IsZero() {
+		newDeploymentConfig.EndTime = dcDeletionTimestamp.Time.Format(time.RFC3339)
+		newDeploymentConfig.Xid += newDeploymentConfig.EndTime
+		newDeploymentConfig.Name += "*" + newDeploymentConfig.EndTime
+	}
+	return newDeploymentConfig
+}
+
+// StoreDeploymentConfig create a new deployment config in the Dgraph and updates if already present.
+func StoreDeploymentConfig(dc openshift_v1.DeploymentConfig) (string, error) {
+	xid := dc.Namespace + ":" + dc.Name
+	uid := dgraph.GetUID(xid, IsDeploymentConfig)
+
+	newDeploymentConfig := createDeploymentConfigObject(dc)
+	if uid != "" {
+		newDeploymentConfig.UID = uid
+	}
+	assigned, err := dgraph.MutateNode(newDeploymentConfig, dgraph.CREATE)
+	if err != nil {
+		return "", err
+	}
+	return assigned.Uids["blank-0"], nil
+}
+
+// CreateOrGetDeploymentConfigByID returns the uid of deployment config if exists,
+// otherwise creates the deployment config and returns uid.
+func CreateOrGetDeploymentConfigByID(xid string) string {
+	if xid == "" {
+		return ""
+	}
+	uid := dgraph.GetUID(xid, IsDeploymentConfig)
+
+	if uid != "" {
+		return uid
+	}
+
+	d := DeploymentConfig{
+		ID:                 dgraph.ID{Xid: xid},
+		Name:               xid,
+		IsDeploymentConfig: true,
+	}
+	assigned, err := dgraph.MutateNode(d, dgraph.CREATE)
+	if err != nil {
+		log.Errorf("unable to create deployment config: %s, err: %v", xid, err)
+		return ""
+	}
+	return assigned.Uids["blank-0"]
+}
+
+// getDeploymentConfigImageStreams returns image streams whose tags trigger redeployment of the deployment config
+func getDeploymentConfigImageStreams(dc openshift_v1.DeploymentConfig) []*ImageStream {
+	imageStreams := []*ImageStream{}
+	for _, trigger := range dc.Spec.Triggers {
+		if trigger.Type != openshift_v1.DeploymentTriggerTypeImageChange || trigger.ImageChangeParams == nil {
+			continue
+		}
+		from := trigger.ImageChangeParams.From
+		if from.Kind != "ImageStreamTag" {
+			continue
+		}
+		namespace := from.Namespace
+		if namespace == "" {
+			namespace = dc.Namespace
+		}
+		// ImageStreamTag name is of the form <imagestream>:<tag>
+		imageStreamXID := namespace + ":" + strings.Split(from.Name, ":")[0]
+		imageStreamUID := CreateOrGetImageStreamByID(imageStreamXID)
+		if imageStreamUID != "" {
+			imageStreams = append(imageStreams, &ImageStream{ID: dgraph.ID{UID: imageStreamUID, Xid: imageStreamXID}})
+		}
+	}
+	return imageStreams
+}
+
+// getDeploymentConfigOfPod returns name of the deployment config which created the pod through a replication controller,
+// empty string if pod is not part of a deployment config
+func getDeploymentConfigOfPod(k8sPod api_v1.Pod) string {
+	if name, isPresent := k8sPod.Annotations[openshift_v1.DeploymentConfigAnnotation]; isPresent {
+		return name
+	}
+	return k8sPod.Labels[openshift_v1.DeploymentConfigLabel]
+}
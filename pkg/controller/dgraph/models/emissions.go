@@ -0,0 +1,178 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package models
+
+import (
+	"strings"
+	"sync"
+
+	log "github.com/Sirupsen/logrus"
+	api_v1 "k8s.io/api/core/v1"
+)
+
+// Labels holding region of a node
+const (
+	RegionLabelKey       = "failure-domain.beta.kubernetes.io/region"
+	StableRegionLabelKey = "topology.kubernetes.io/region"
+)
+
+// Default emission coefficients, taken from Cloud Carbon Footprint methodology for AWS.
+// Power is in Watts, carbon intensity in gCO2e per kWh.
+const (
+	DefaultMinWattsPerCPU        = 0.74
+	DefaultMaxWattsPerCPU        = 3.5
+	DefaultWattsPerGBMemory      = 0.392
+	DefaultCPUUtilization        = 0.5
+	DefaultPUE                   = 1.135
+	DefaultCarbonIntensityPerKWh = 475.0
+)
+
+// PowerCoefficients of an instance family
+type PowerCoefficients struct {
+	MinWattsPerCPU float64 `json:"minWattsPerCPU"`
+	MaxWattsPerCPU float64 `json:"maxWattsPerCPU"`
+}
+
+// EmissionCoefficients maps nodes to their power and carbon intensity
+type EmissionCoefficients struct {
+	PUE                    float64                      `json:"pue,omitempty"`
+	CPUUtilization         float64                      `json:"cpuUtilization,omitempty"`
+	WattsPerGBMemory       float64                      `json:"wattsPerGBMemory,omitempty"`
+	DefaultPower           PowerCoefficients            `json:"defaultPower,omitempty"`
+	InstanceFamilyPower    map[string]PowerCoefficients `json:"instanceFamilyPower,omitempty"`
+	DefaultCarbonIntensity float64                      `json:"defaultCarbonIntensity,omitempty"`
+	RegionCarbonIntensity  map[string]float64           `json:"regionCarbonIntensity,omitempty"`
+}
+
+var (
+	emissionsMu          sync.RWMutex
+	emissionCoefficients = DefaultEmissionCoefficients()
+)
+
+// DefaultEmissionCoefficients returns coefficients for AWS regions and instance families
+func DefaultEmissionCoefficients() EmissionCoefficients {
+	return EmissionCoefficients{
+		PUE:              DefaultPUE,
+		CPUUtilization:   DefaultCPUUtilization,
+		WattsPerGBMemory: DefaultWattsPerGBMemory,
+		DefaultPower:     PowerCoefficients{MinWattsPerCPU: DefaultMinWattsPerCPU, MaxWattsPerCPU: DefaultMaxWattsPerCPU},
+		InstanceFamilyPower: map[string]PowerCoefficients{
+			// graviton(arm) instances
+			"a1":  {MinWattsPerCPU: 0.47, MaxWattsPerCPU: 1.69},
+			"c6g": {MinWattsPerCPU: 0.47, MaxWattsPerCPU: 1.69},
+			"m6g": {MinWattsPerCPU: 0.47, MaxWattsPerCPU: 1.69},
+			"r6g": {MinWattsPerCPU: 0.47, MaxWattsPerCPU: 1.69},
+			"t4g": {MinWattsPerCPU: 0.47, MaxWattsPerCPU: 1.69},
+		},
+		DefaultCarbonIntensity: DefaultCarbonIntensityPerKWh,
+		RegionCarbonIntensity: map[string]float64{
+			"us-east-1":      379.069,
+			"us-east-2":      410.608,
+			"us-west-1":      189.323,
+			"us-west-2":      135.735,
+			"ca-central-1":   13.0,
+			"sa-east-1":      61.7,
+			"eu-west-1":      278.6,
+			"eu-west-2":      225.0,
+			"eu-west-3":      51.1,
+			"eu-central-1":   311.0,
+			"eu-north-1":     8.8,
+			"ap-south-1":     708.0,
+			"ap-southeast-1": 408.0,
+			"ap-southeast-2": 760.0,
+			"ap-northeast-1": 465.8,
+			"ap-northeast-2": 415.6,
+		},
+	}
+}
+
+// SetEmissionCoefficients sets coefficients used to compute carbon rates of nodes and pods.
+// Coefficients which are not set are taken from defaults.
+func SetEmissionCoefficients(coefficients EmissionCoefficients) {
+	defaults := DefaultEmissionCoefficients()
+	if coefficients.PUE <= 0 {
+		coefficients.PUE = defaults.PUE
+	}
+	if coefficients.CPUUtilization <= 0 {
+		coefficients.CPUUtilization = defaults.CPUUtilization
+	}
+	if coefficients.WattsPerGBMemory <= 0 {
+		coefficients.WattsPerGBMemory = defaults.WattsPerGBMemory
+	}
+	if coefficients.DefaultPower.MaxWattsPerCPU <= 0 {
+		coefficients.DefaultPower = defaults.DefaultPower
+	}
+	if coefficients.InstanceFamilyPower == nil {
+		coefficients.InstanceFamilyPower = defaults.InstanceFamilyPower
+	}
+	if coefficients.DefaultCarbonIntensity <= 0 {
+		coefficients.DefaultCarbonIntensity = defaults.DefaultCarbonIntensity
+	}
+	if coefficients.RegionCarbonIntensity == nil {
+		coefficients.RegionCarbonIntensity = defaults.RegionCarbonIntensity
+	}
+
+	emissionsMu.Lock()
+	defer emissionsMu.Unlock()
+	emissionCoefficients = coefficients
+	log.Infof("emission coefficients set, pue: %v, regions: %d, instance families: %d", coefficients.PUE,
+		len(coefficients.RegionCarbonIntensity), len(coefficients.InstanceFamilyPower))
+}
+
+// getCarbonRates returns gCO2e per cpu per hour and gCO2e per GB memory per hour for the node
+func getCarbonRates(node Node) (float64, float64) {
+	emissionsMu.RLock()
+	defer emissionsMu.RUnlock()
+
+	power := emissionCoefficients.DefaultPower
+	if familyPower, isPresent := emissionCoefficients.InstanceFamilyPower[getInstanceFamily(node.InstanceType)]; isPresent {
+		power = familyPower
+	}
+	carbonIntensity := emissionCoefficients.DefaultCarbonIntensity
+	if regionCarbonIntensity, isPresent := emissionCoefficients.RegionCarbonIntensity[node.Region]; isPresent {
+		carbonIntensity = regionCarbonIntensity
+	}
+
+	wattsPerCPU := power.MinWattsPerCPU + emissionCoefficients.CPUUtilization*(power.MaxWattsPerCPU-power.MinWattsPerCPU)
+	// Wh -> kWh
+	gramsPerWattHour := emissionCoefficients.PUE * carbonIntensity / 1000
+	return wattsPerCPU * gramsPerWattHour, emissionCoefficients.WattsPerGBMemory * gramsPerWattHour
+}
+
+// getCarbonRatesForNode returns gCO2e per cpu per hour and gCO2e per GB memory per hour
+func getCarbonRatesForNode(nodeName string) (float64, float64) {
+	node, err := retrieveNode(nodeName)
+	if err == nil {
+		return getCarbonRates(*node)
+	}
+	return getCarbonRates(Node{})
+}
+
+// getInstanceFamily returns family of the instance type, ex: m5 for m5.xlarge
+func getInstanceFamily(instanceType string) string {
+	return strings.SplitN(instanceType, ".", 2)[0]
+}
+
+// getRegion returns region of the node from its labels
+func getRegion(node api_v1.Node) string {
+	nodeLabels := node.GetLabels()
+	if region, isPresent := nodeLabels[StableRegionLabelKey]; isPresent {
+		return region
+	}
+	return nodeLabels[RegionLabelKey]
+}
@@ -0,0 +1,50 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package models
+
+import (
+	"math"
+	"testing"
+
+	"github.com/vmware/purser/test/utils"
+)
+
+func TestGetCarbonRates(t *testing.T) {
+	SetEmissionCoefficients(EmissionCoefficients{
+		PUE:                    1,
+		CPUUtilization:         0.5,
+		WattsPerGBMemory:       0.4,
+		DefaultPower:           PowerCoefficients{MinWattsPerCPU: 1, MaxWattsPerCPU: 3},
+		InstanceFamilyPower:    map[string]PowerCoefficients{"m6g": {MinWattsPerCPU: 0.5, MaxWattsPerCPU: 1.5}},
+		DefaultCarbonIntensity: 500,
+		RegionCarbonIntensity:  map[string]float64{"eu-north-1": 10},
+	})
+	defer SetEmissionCoefficients(DefaultEmissionCoefficients())
+
+	cpuCarbon, memoryCarbon := getCarbonRates(Node{InstanceType: "m5.large", Region: "us-east-1"})
+	assertAlmostEquals(t, 1.0, cpuCarbon)
+	assertAlmostEquals(t, 0.2, memoryCarbon)
+
+	cpuCarbon, memoryCarbon = getCarbonRates(Node{InstanceType: "m6g.large", Region: "eu-north-1"})
+	assertAlmostEquals(t, 0.01, cpuCarbon)
+	assertAlmostEquals(t, 0.004, memoryCarbon)
+}
+
+func assertAlmostEquals(t *testing.T, expected, got float64) {
+	utils.Assert(t, math.Abs(expected-got) < 1e-9, "expected: %v, got: %v", expected, got)
+}
@@ -0,0 +1,80 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package models
+
+import (
+	"fmt"
+
+	"github.com/vmware/purser/pkg/controller/dgraph"
+)
+
+// AddPodEnergy adds energy consumed by the pod in kWh to its total energy
+func AddPodEnergy(xid string, kWh float64) error {
+	uid := dgraph.GetUID(xid, IsPod)
+	if uid == "" {
+		return fmt.Errorf("pod: %s is not persisted yet", xid)
+	}
+	current, err := retrieveEnergy(uid)
+	if err != nil {
+		return err
+	}
+	pod := Pod{
+		ID:     dgraph.ID{UID: uid, Xid: xid},
+		Energy: current + kWh,
+	}
+	_, err = dgraph.MutateNode(pod, dgraph.UPDATE)
+	return err
+}
+
+// AddNodeEnergy adds energy consumed by the node in kWh to its total energy
+func AddNodeEnergy(xid string, kWh float64) error {
+	uid := dgraph.GetUID(xid, IsNode)
+	if uid == "" {
+		return fmt.Errorf("node: %s is not persisted yet", xid)
+	}
+	current, err := retrieveEnergy(uid)
+	if err != nil {
+		return err
+	}
+	node := Node{
+		ID:     dgraph.ID{UID: uid, Xid: xid},
+		Energy: current + kWh,
+	}
+	_, err = dgraph.MutateNode(node, dgraph.UPDATE)
+	return err
+}
+
+// retrieveEnergy returns total energy in kWh stored for the given uid
+func retrieveEnergy(uid string) (float64, error) {
+	query := `query {
+		resources(func: uid(` + uid + `)) {
+			energy
+		}
+	}`
+	type root struct {
+		Resources []struct {
+			Energy float64 `json:"energy"`
+		} `json:"resources"`
+	}
+	newRoot := root{}
+	err := dgraph.ExecuteQuery(query, &newRoot)
+	if err != nil || len(newRoot.Resources) < 1 {
+		return 0, err
+	}
+	return newRoot.Resources[0].Energy, nil
+}
@@ -0,0 +1,90 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package models
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	api_v1 "k8s.io/api/core/v1"
+)
+
+// ExtendedResourceWildcard at the end of a resource name in extended resource pricing matches all resources with the prefix
+const ExtendedResourceWildcard = "*"
+
+// ExtendedResourcePricing holds hourly prices of extended resources(ex: xilinx.com/fpga, smarter-devices/*)
+// Unit of prices should be USD($)-(per unit resource)-(per Hour)
+type ExtendedResourcePricing struct {
+	Prices map[string]float64 `json:"prices,omitempty"`
+}
+
+var (
+	extendedResourceMu     sync.RWMutex
+	extendedResourcePrices = map[string]float64{}
+	extendedResourcePrefix []string
+)
+
+// SetExtendedResourcePricing sets prices of extended resources. Names ending with * match all resources with the prefix,
+// exact names take precedence and the longest prefix is used otherwise.
+func SetExtendedResourcePricing(pricing ExtendedResourcePricing) {
+	extendedResourceMu.Lock()
+	defer extendedResourceMu.Unlock()
+	extendedResourcePrices = map[string]float64{}
+	extendedResourcePrefix = nil
+	for name, price := range pricing.Prices {
+		extendedResourcePrices[name] = price
+		if strings.HasSuffix(name, ExtendedResourceWildcard) {
+			extendedResourcePrefix = append(extendedResourcePrefix, name)
+		}
+	}
+	sort.Slice(extendedResourcePrefix, func(i, j int) bool {
+		return len(extendedResourcePrefix[i]) > len(extendedResourcePrefix[j])
+	})
+}
+
+// getExtendedResourcePrice returns hourly price of one unit of the resource, false if the resource is not priced
+func getExtendedResourcePrice(name string) (float64, bool) {
+	extendedResourceMu.RLock()
+	defer extendedResourceMu.RUnlock()
+	if price, isPresent := extendedResourcePrices[name]; isPresent {
+		return price, true
+	}
+	for _, pattern := range extendedResourcePrefix {
+		if strings.HasPrefix(name, strings.TrimSuffix(pattern, ExtendedResourceWildcard)) {
+			return extendedResourcePrices[pattern], true
+		}
+	}
+	return 0, false
+}
+
+// getExtendedResourcesPrice returns hourly price of priced extended resources in the given resources.
+// Kubernetes doesn't allow overcommit of extended resources, so requests are equal to limits.
+// GPUs and MIG devices are priced by GPU pricing and are skipped.
+func getExtendedResourcesPrice(resources api_v1.ResourceList) float64 {
+	price := 0.0
+	for name, quantity := range resources {
+		if _, isMIG := getMIGFraction(string(name)); isMIG || isGPUResource(string(name)) {
+			continue
+		}
+		if unitPrice, isPriced := getExtendedResourcePrice(string(name)); isPriced {
+			price += float64(quantity.MilliValue()) / 1000 * unitPrice
+		}
+	}
+	return price
+}
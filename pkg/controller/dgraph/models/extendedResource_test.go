@@ -0,0 +1,50 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package models
+
+import (
+	"testing"
+
+	"github.com/vmware/purser/test/utils"
+	api_v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestGetExtendedResourcesPrice(t *testing.T) {
+	SetExtendedResourcePricing(ExtendedResourcePricing{Prices: map[string]float64{
+		"xilinx.com/fpga":      1.5,
+		"smarter-devices/*":    0.1,
+		"smarter-devices/fuse": 0.2,
+	}})
+	defer SetExtendedResourcePricing(ExtendedResourcePricing{})
+
+	price, isPriced := getExtendedResourcePrice("smarter-devices/ttyUSB0")
+	utils.Assert(t, isPriced, "expected wildcard to match")
+	utils.Equals(t, 0.1, price)
+	price, _ = getExtendedResourcePrice("smarter-devices/fuse")
+	utils.Equals(t, 0.2, price)
+	_, isPriced = getExtendedResourcePrice("example.com/dongle")
+	utils.Assert(t, !isPriced, "expected unknown resource not to be priced")
+
+	resources := api_v1.ResourceList{
+		api_v1.ResourceCPU:                          resource.MustParse("2"),
+		api_v1.ResourceName("xilinx.com/fpga"):      resource.MustParse("2"),
+		api_v1.ResourceName("smarter-devices/fuse"): resource.MustParse("1"),
+	}
+	utils.Equals(t, 3.2, getExtendedResourcesPrice(resources))
+}
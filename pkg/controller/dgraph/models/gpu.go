@@ -0,0 +1,159 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package models
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"sync"
+
+	log "github.com/Sirupsen/logrus"
+	api_v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// Extended resources of GPU device plugins counted as GPUs by default
+const (
+	NvidiaGPUResource = "nvidia.com/gpu"
+	AMDGPUResource    = "amd.com/gpu"
+)
+
+// GPU sharing constants. MIG devices(ex: nvidia.com/mig-3g.20gb) count as their share of the compute slices of a GPU.
+// GPUs time-sliced by the NVIDIA device plugin are advertised as replicas of nvidia.com/gpu on the node.
+const (
+	MIGComputeSlicesPerGPU = 7
+	GPUReplicasLabelKey    = "nvidia.com/gpu.replicas"
+	GPUProductLabelKey     = "nvidia.com/gpu.product"
+	GPUReplicasAnnotation  = "purser.vmware.com/gpu-replicas"
+)
+
+var (
+	migResourcePattern = regexp.MustCompile(`^nvidia\.com/mig-(\d+)g\.\d+gb$`)
+	migProductPattern  = regexp.MustCompile(`-MIG-(\d+)g\.\d+gb$`)
+)
+
+var (
+	gpuMu        sync.RWMutex
+	gpuPrice     = DefaultGPUCostInFloat64
+	gpuResources = map[string]bool{NvidiaGPUResource: true, AMDGPUResource: true}
+)
+
+// SetGPUPricing sets hourly price of one GPU and extended resources(ex: gpu.intel.com/i915) counted as GPUs in
+// addition to nvidia.com/gpu and amd.com/gpu. Non positive price is ignored and default is retained.
+func SetGPUPricing(price float64, resources []string) {
+	gpuMu.Lock()
+	defer gpuMu.Unlock()
+	if price > 0 {
+		gpuPrice = price
+	}
+	gpuResources = map[string]bool{NvidiaGPUResource: true, AMDGPUResource: true}
+	for _, name := range resources {
+		if name != "" {
+			gpuResources[name] = true
+		}
+	}
+	log.Infof("gpu pricing: %v, gpu resources: %v", gpuPrice, gpuResources)
+}
+
+// getGPURate returns price per GPU per hour
+func getGPURate() float64 {
+	gpuMu.RLock()
+	defer gpuMu.RUnlock()
+	return gpuPrice
+}
+
+// isGPUResource returns true if the extended resource is counted as GPU
+func isGPUResource(name string) bool {
+	gpuMu.RLock()
+	defer gpuMu.RUnlock()
+	return gpuResources[name]
+}
+
+// getGPUs returns sum of GPU resources in the given resources, MIG devices are counted as fractions of a GPU.
+// Kubernetes doesn't allow overcommit of extended resources, so requests are equal to limits.
+func getGPUs(resources api_v1.ResourceList) *resource.Quantity {
+	gpus := &resource.Quantity{}
+	for name, quantity := range resources {
+		if isGPUResource(string(name)) {
+			gpus.Add(quantity)
+		} else if fraction, isMIG := getMIGFraction(string(name)); isMIG {
+			micro := math.Round(float64(quantity.MilliValue()) * 1000 * fraction)
+			gpus.Add(*resource.NewScaledQuantity(int64(micro), resource.Micro))
+		}
+	}
+	return gpus
+}
+
+// getGPUCount returns the number of GPUs in the quantity
+func getGPUCount(quantity *resource.Quantity) float64 {
+	return float64(quantity.ScaledValue(resource.Micro)) / 1e6
+}
+
+// getMIGFraction returns the fraction of a GPU which a device of the MIG resource(ex: nvidia.com/mig-1g.5gb) is
+func getMIGFraction(name string) (float64, bool) {
+	match := migResourcePattern.FindStringSubmatch(name)
+	if match == nil {
+		return 0, false
+	}
+	return getMIGSliceFraction(match[1])
+}
+
+// getMIGSliceFraction returns the fraction of a GPU which the given number of compute slices are
+func getMIGSliceFraction(slices string) (float64, bool) {
+	count, err := strconv.Atoi(slices)
+	if err != nil || count <= 0 {
+		return 0, false
+	}
+	return math.Min(float64(count)/MIGComputeSlicesPerGPU, 1), true
+}
+
+// getNodeGPUShare returns the fraction of a physical GPU which one nvidia.com/gpu of the node is, 1 if GPUs of the
+// node are not shared. Replicas of time-sliced GPUs are taken from the label of GPU feature discovery or the
+// annotation, MIG devices of the single strategy from the product label(ex: A100-SXM4-40GB-MIG-1g.5gb).
+func getNodeGPUShare(node api_v1.Node) float64 {
+	share := 1.0
+	replicas := node.GetLabels()[GPUReplicasLabelKey]
+	if value, isPresent := node.GetAnnotations()[GPUReplicasAnnotation]; isPresent {
+		replicas = value
+	}
+	if replicas != "" {
+		count, err := strconv.Atoi(replicas)
+		if err == nil && count > 1 {
+			share = 1 / float64(count)
+		} else if err != nil {
+			log.Warnf("invalid gpu replicas: %s of node: %s", replicas, node.Name)
+		}
+	}
+	if match := migProductPattern.FindStringSubmatch(node.GetLabels()[GPUProductLabelKey]); match != nil {
+		if fraction, isMIG := getMIGSliceFraction(match[1]); isMIG {
+			share *= fraction
+		}
+	}
+	return share
+}
+
+// getGPUPriceForNode returns price of one GPU requested by a pod on the given node, price of a GPU is split
+// among its replicas if GPUs of the node are shared
+func getGPUPriceForNode(nodeName string) float64 {
+	node, err := retrieveNode(nodeName)
+	if err == nil && node.GPUShare > 0 {
+		return getGPURate() * node.GPUShare
+	}
+	return getGPURate()
+}
@@ -0,0 +1,86 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package models
+
+import (
+	"testing"
+
+	"github.com/vmware/purser/test/utils"
+	api_v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestGetGPUs(t *testing.T) {
+	resources := api_v1.ResourceList{
+		api_v1.ResourceCPU:                        resource.MustParse("2"),
+		api_v1.ResourceName(NvidiaGPUResource):    resource.MustParse("2"),
+		api_v1.ResourceName("gpu.intel.com/i915"): resource.MustParse("1"),
+	}
+	utils.Equals(t, 2.0, getGPUCount(getGPUs(resources)))
+
+	SetGPUPricing(2.5, []string{"gpu.intel.com/i915"})
+	defer SetGPUPricing(DefaultGPUCostInFloat64, nil)
+	utils.Equals(t, 3.0, getGPUCount(getGPUs(resources)))
+	utils.Equals(t, 2.5, getGPURate())
+}
+
+func TestGetGPUsWithMIGDevices(t *testing.T) {
+	resources := api_v1.ResourceList{
+		api_v1.ResourceName(NvidiaGPUResource):       resource.MustParse("1"),
+		api_v1.ResourceName("nvidia.com/mig-1g.5gb"): resource.MustParse("7"),
+	}
+	utils.Equals(t, 2.0, getGPUCount(getGPUs(resources)))
+	utils.Equals(t, 0.142857, getGPUCount(getGPUs(api_v1.ResourceList{
+		api_v1.ResourceName("nvidia.com/mig-1g.5gb"): resource.MustParse("1"),
+	})))
+
+	fraction, isMIG := getMIGFraction("nvidia.com/mig-7g.40gb")
+	utils.Assert(t, isMIG, "mig resource not identified")
+	utils.Equals(t, 1.0, fraction)
+	_, isMIG = getMIGFraction("nvidia.com/gpu")
+	utils.Assert(t, !isMIG, "gpu identified as mig resource")
+}
+
+func TestGetNodeGPUShare(t *testing.T) {
+	node := api_v1.Node{}
+	utils.Equals(t, 1.0, getNodeGPUShare(node))
+
+	node.Labels = map[string]string{GPUReplicasLabelKey: "4"}
+	utils.Equals(t, 0.25, getNodeGPUShare(node))
+
+	node.Annotations = map[string]string{GPUReplicasAnnotation: "2"}
+	utils.Equals(t, 0.5, getNodeGPUShare(node))
+
+	node.Annotations = map[string]string{GPUReplicasAnnotation: "many"}
+	utils.Equals(t, 1.0, getNodeGPUShare(node))
+
+	node.Annotations = nil
+	node.Labels = map[string]string{GPUProductLabelKey: "A100-SXM4-40GB-MIG-1g.5gb"}
+	utils.Equals(t, 1.0/7, getNodeGPUShare(node))
+}
+
+func TestGetExtendedResourcesPriceSkipsGPUs(t *testing.T) {
+	SetExtendedResourcePricing(ExtendedResourcePricing{Prices: map[string]float64{NvidiaGPUResource: 1, "nvidia.com/mig-1g.5gb": 1, "xilinx.com/fpga": 0.5}})
+	defer SetExtendedResourcePricing(ExtendedResourcePricing{})
+	resources := api_v1.ResourceList{
+		api_v1.ResourceName(NvidiaGPUResource):       resource.MustParse("1"),
+		api_v1.ResourceName("xilinx.com/fpga"):       resource.MustParse("2"),
+		api_v1.ResourceName("nvidia.com/mig-1g.5gb"): resource.MustParse("1"),
+	}
+	utils.Equals(t, 1.0, getExtendedResourcesPrice(resources))
+}
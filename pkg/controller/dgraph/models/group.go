@@ -18,10 +18,13 @@
 package models
 
 import (
+	"time"
+
 	"github.com/Sirupsen/logrus"
 	"github.com/dgraph-io/dgo/protos/api"
 	groups_v1 "github.com/vmware/purser/pkg/apis/groups/v1"
 	"github.com/vmware/purser/pkg/controller/dgraph"
+	"github.com/vmware/purser/pkg/controller/forecast"
 	"github.com/vmware/purser/pkg/controller/utils"
 )
 
@@ -29,6 +32,9 @@ import (
 const (
 	IsGroup        = "isGroup"
 	groupXIDPrefix = "purser-group-"
+
+	// days of daily rollups used for forecasting
+	forecastHistoryDays = 56
 )
 
 // Group schema in dgraph
@@ -59,6 +65,10 @@ type Group struct {
 	LastLastMonthMemoryCost  float64 `json:"lastLastMonthMemoryCost,omitempty"`
 	LastLastMonthStorageCost float64 `json:"lastLastMonthStorageCost,omitempty"`
 	LastLastMonthCost        float64 `json:"lastLastMonthCost,omitempty"`
+	MtdCarbon                float64 `json:"mtdCarbon,omitempty"`
+	ProjectedCarbon          float64 `json:"projectedCarbon,omitempty"`
+	LastMonthCarbon          float64 `json:"lastMonthCarbon,omitempty"`
+	LastLastMonthCarbon      float64 `json:"lastLastMonthCarbon,omitempty"`
 }
 
 // CreateOrUpdateGroup updates group if it is already present in dgraph else it creates one
@@ -67,6 +77,10 @@ func CreateOrUpdateGroup(group *groups_v1.Group, podsCount int) (*api.Assigned,
 	uid := dgraph.GetUID(xid, IsGroup)
 
 	hoursRemainingInCurrentMonth := utils.GetHoursRemainingInCurrentMonth()
+	projectionFactor := 1.0
+	if uid != "" {
+		projectionFactor = getProjectionFactor(uid, group, hoursRemainingInCurrentMonth)
+	}
 	grp := Group{
 		ID:                   dgraph.ID{Xid: xid},
 		IsGroup:              true,
@@ -82,10 +96,10 @@ func CreateOrUpdateGroup(group *groups_v1.Group, podsCount int) (*api.Assigned,
 		MtdMemoryCost:        group.Spec.MTDCost.MemoryCost,
 		MtdStorageCost:       group.Spec.MTDCost.StorageCost,
 		MtdCost:              group.Spec.MTDCost.TotalCost,
-		ProjectedCPUCost:     group.Spec.MTDCost.CPUCost + group.Spec.PerHourCost.CPUCost*hoursRemainingInCurrentMonth,
-		ProjectedMemoryCost:  group.Spec.MTDCost.MemoryCost + group.Spec.PerHourCost.MemoryCost*hoursRemainingInCurrentMonth,
-		ProjectedStorageCost: group.Spec.MTDCost.StorageCost + group.Spec.PerHourCost.StorageCost*hoursRemainingInCurrentMonth,
-		ProjectedCost:        group.Spec.MTDCost.TotalCost + group.Spec.PerHourCost.TotalCost*hoursRemainingInCurrentMonth,
+		ProjectedCPUCost:     group.Spec.MTDCost.CPUCost + group.Spec.PerHourCost.CPUCost*hoursRemainingInCurrentMonth*projectionFactor,
+		ProjectedMemoryCost:  group.Spec.MTDCost.MemoryCost + group.Spec.PerHourCost.MemoryCost*hoursRemainingInCurrentMonth*projectionFactor,
+		ProjectedStorageCost: group.Spec.MTDCost.StorageCost + group.Spec.PerHourCost.StorageCost*hoursRemainingInCurrentMonth*projectionFactor,
+		ProjectedCost:        group.Spec.MTDCost.TotalCost + group.Spec.PerHourCost.TotalCost*hoursRemainingInCurrentMonth*projectionFactor,
 		LastMonthCPUCost:           group.Spec.LastMonthCost.CPUCost,
 		LastMonthMemoryCost:        group.Spec.LastMonthCost.MemoryCost,
 		LastMonthStorageCost:       group.Spec.LastMonthCost.StorageCost,
@@ -94,6 +108,10 @@ func CreateOrUpdateGroup(group *groups_v1.Group, podsCount int) (*api.Assigned,
 		LastLastMonthMemoryCost:        group.Spec.LastLastMonthCost.MemoryCost,
 		LastLastMonthStorageCost:       group.Spec.LastLastMonthCost.StorageCost,
 		LastLastMonthCost:              group.Spec.LastLastMonthCost.TotalCost,
+		MtdCarbon:            group.Spec.MTDCost.Carbon,
+		ProjectedCarbon:      group.Spec.MTDCost.Carbon + group.Spec.PerHourCost.Carbon*hoursRemainingInCurrentMonth,
+		LastMonthCarbon:      group.Spec.LastMonthCost.Carbon,
+		LastLastMonthCarbon:  group.Spec.LastLastMonthCost.Carbon,
 	}
 	if uid != "" {
 		grp.ID = dgraph.ID{Xid: xid, UID: uid}
@@ -101,6 +119,27 @@ func CreateOrUpdateGroup(group *groups_v1.Group, podsCount int) (*api.Assigned,
 	return dgraph.MutateNode(grp, dgraph.CREATE)
 }
 
+// getProjectionFactor stores the hourly cost of the group in its daily rollup and returns the ratio of cost of the rest
+// of the month forecasted with weekly seasonality to the linear projection of hourly cost, 1 if history is too short
+func getProjectionFactor(uid string, group *groups_v1.Group, hoursRemaining float64) float64 {
+	now := time.Now()
+	_, err := StoreGroupDailyCost(uid, group.Name, now, group.Spec.PerHourCost.TotalCost)
+	if err != nil {
+		logrus.Errorf("unable to store daily cost of group: %s, err: %v", group.Name, err)
+	}
+
+	linearCost := group.Spec.PerHourCost.TotalCost * hoursRemaining
+	if linearCost <= 0 {
+		return 1
+	}
+	history, err := RetrieveGroupDailyCosts(group.Name, now.AddDate(0, 0, -forecastHistoryDays))
+	if err != nil || len(history) < forecast.MinSeasonalDays {
+		return 1
+	}
+	model, _ := forecast.Fit(history)
+	return model.PredictRange(now, now.Add(time.Duration(hoursRemaining*float64(time.Hour)))) / linearCost
+}
+
 // DeleteGroup deletes group from dgraph
 func DeleteGroup(name string) {
 	xid := groupXIDPrefix + name
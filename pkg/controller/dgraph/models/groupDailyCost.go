@@ -0,0 +1,113 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package models
+
+import (
+	"time"
+
+	"github.com/dgraph-io/dgo/protos/api"
+	"github.com/vmware/purser/pkg/controller/dgraph"
+	"github.com/vmware/purser/pkg/controller/forecast"
+)
+
+// Dgraph Model Constants
+const (
+	IsGroupDailyCost = "isGroupDailyCost"
+	dayFormat        = "2006-01-02"
+)
+
+// GroupDailyCost is the daily rollup of cost of a group, cost is the average of hourly cost samples of the day for 24 hours
+type GroupDailyCost struct {
+	dgraph.ID
+	IsGroupDailyCost bool    `json:"isGroupDailyCost,omitempty"`
+	Group            *Group  `json:"group,omitempty"`
+	Day              string  `json:"day,omitempty"`
+	Samples          int     `json:"samples,omitempty"`
+	Cost             float64 `json:"cost,omitempty"`
+}
+
+// StoreGroupDailyCost adds a sample of hourly cost of the group to its rollup of the day
+func StoreGroupDailyCost(groupUID, groupName string, now time.Time, costPerHour float64) (*api.Assigned, error) {
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	xid := groupXIDPrefix + groupName + ":" + day.Format(dayFormat)
+	rollup, err := retrieveGroupDailyCost(xid)
+	if err != nil {
+		return nil, err
+	}
+
+	rollup.ID.Xid = xid
+	rollup.IsGroupDailyCost = true
+	rollup.Group = &Group{ID: dgraph.ID{UID: groupUID, Xid: groupXIDPrefix + groupName}}
+	rollup.Day = day.Format(time.RFC3339)
+	rollup.Cost = (rollup.Cost*float64(rollup.Samples) + costPerHour*24) / float64(rollup.Samples+1)
+	rollup.Samples++
+	return dgraph.MutateNode(rollup, dgraph.CREATE)
+}
+
+// RetrieveGroupDailyCosts returns daily costs of the group for days starting at or after since
+func RetrieveGroupDailyCosts(groupName string, since time.Time) ([]forecast.DailyCost, error) {
+	q := `query {
+		group(func: eq(xid, "` + groupXIDPrefix + groupName + `")) @filter(has(isGroup)) {
+			rollups: ~group @filter(has(isGroupDailyCost) AND ge(day, "` + since.Format(time.RFC3339) + `")) {
+				day
+				cost
+			}
+		}
+	}`
+	type root struct {
+		Group []struct {
+			Rollups []GroupDailyCost `json:"rollups"`
+		} `json:"group"`
+	}
+	newRoot := root{}
+	err := dgraph.ExecuteQuery(q, &newRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	dailyCosts := []forecast.DailyCost{}
+	for _, group := range newRoot.Group {
+		for _, rollup := range group.Rollups {
+			day, err := time.Parse(time.RFC3339, rollup.Day)
+			if err != nil {
+				continue
+			}
+			dailyCosts = append(dailyCosts, forecast.DailyCost{Day: day.In(since.Location()), Cost: rollup.Cost})
+		}
+	}
+	return dailyCosts, nil
+}
+
+func retrieveGroupDailyCost(xid string) (GroupDailyCost, error) {
+	q := `query {
+		rollup(func: eq(xid, "` + xid + `")) @filter(has(isGroupDailyCost)) {
+			uid
+			samples
+			cost
+		}
+	}`
+	type root struct {
+		Rollup []GroupDailyCost `json:"rollup"`
+	}
+	newRoot := root{}
+	err := dgraph.ExecuteQuery(q, &newRoot)
+	if err != nil || len(newRoot.Rollup) == 0 {
+		return GroupDailyCost{}, err
+	}
+	return newRoot.Rollup[0], nil
+}
@@ -0,0 +1,67 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package models
+
+import "regexp"
+
+// Helm labels, legacy labels(release, chart, heritage) are set by charts created before Helm 3
+const (
+	ManagedByLabel = "app.kubernetes.io/managed-by"
+	HelmChartLabel = "helm.sh/chart"
+	HeritageLabel  = "heritage"
+	ReleaseLabel   = "release"
+	ChartLabel     = "chart"
+	HelmManager    = "Helm"
+	TillerManager  = "Tiller"
+)
+
+// chartVersionRegex matches the version suffix of helm chart label, ex: -1.2.3 in nginx-1.2.3
+var chartVersionRegex = regexp.MustCompile(`-v?[0-9]+\.[0-9]+\.[0-9]+([-+][0-9A-Za-z.+-]*)?$`)
+
+// getHelmRelease returns the helm release(<namespace>/<release>) and chart(<name>-<version>) of an object
+// from its labels, empty strings if it isn't installed by helm
+func getHelmRelease(namespace string, labels map[string]string) (string, string) {
+	release := ""
+	switch {
+	case labels[ManagedByLabel] == HelmManager && labels[InstanceLabel] != "":
+		release = labels[InstanceLabel]
+	case (labels[HeritageLabel] == HelmManager || labels[HeritageLabel] == TillerManager) && labels[ReleaseLabel] != "":
+		release = labels[ReleaseLabel]
+	default:
+		return "", ""
+	}
+	chart := labels[HelmChartLabel]
+	if chart == "" {
+		chart = labels[ChartLabel]
+	}
+	return namespace + "/" + release, chart
+}
+
+// GetChartName returns name of the chart without its version, ex: nginx for nginx-1.2.3
+func GetChartName(chart string) string {
+	return chartVersionRegex.ReplaceAllString(chart, "")
+}
+
+// GetChartVersion returns version of the chart, ex: 1.2.3 for nginx-1.2.3
+func GetChartVersion(chart string) string {
+	name := GetChartName(chart)
+	if len(name) >= len(chart) {
+		return ""
+	}
+	return chart[len(name)+1:]
+}
@@ -0,0 +1,47 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package models
+
+import (
+	"testing"
+
+	"github.com/vmware/purser/test/utils"
+)
+
+func TestGetHelmRelease(t *testing.T) {
+	release, chart := getHelmRelease("default", map[string]string{InstanceLabel: "web"})
+	utils.Equals(t, "", release)
+	utils.Equals(t, "", chart)
+
+	release, chart = getHelmRelease("default", map[string]string{ManagedByLabel: HelmManager, InstanceLabel: "web", HelmChartLabel: "nginx-1.2.3"})
+	utils.Equals(t, "default/web", release)
+	utils.Equals(t, "nginx-1.2.3", chart)
+
+	release, chart = getHelmRelease("monitoring", map[string]string{HeritageLabel: TillerManager, ReleaseLabel: "prom", ChartLabel: "prometheus-operator-8.0.0"})
+	utils.Equals(t, "monitoring/prom", release)
+	utils.Equals(t, "prometheus-operator-8.0.0", chart)
+}
+
+func TestGetChartNameAndVersion(t *testing.T) {
+	utils.Equals(t, "prometheus-operator", GetChartName("prometheus-operator-8.0.0"))
+	utils.Equals(t, "8.0.0", GetChartVersion("prometheus-operator-8.0.0"))
+	utils.Equals(t, "cert-manager", GetChartName("cert-manager-v1.5.0-beta.1"))
+	utils.Equals(t, "v1.5.0-beta.1", GetChartVersion("cert-manager-v1.5.0-beta.1"))
+	utils.Equals(t, "custom", GetChartName("custom"))
+	utils.Equals(t, "", GetChartVersion("custom"))
+}
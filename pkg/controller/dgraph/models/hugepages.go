@@ -0,0 +1,66 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package models
+
+import (
+	"strings"
+	"sync"
+
+	log "github.com/Sirupsen/logrus"
+	api_v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+var (
+	hugepagesMu    sync.RWMutex
+	hugepagesPrice float64
+)
+
+// SetHugepagesPricing sets per GB hourly price of hugepages(hugepages-2Mi, hugepages-1Gi etc).
+// Non positive price prices hugepages same as memory of the node.
+func SetHugepagesPricing(price float64) {
+	hugepagesMu.Lock()
+	defer hugepagesMu.Unlock()
+	if price <= 0 {
+		hugepagesPrice = 0
+		return
+	}
+	hugepagesPrice = price
+	log.Infof("hugepages pricing: %v", hugepagesPrice)
+}
+
+// getHugepagesRate returns price per GB of hugepages, memoryPrice if no hugepages price is set
+func getHugepagesRate(memoryPrice float64) float64 {
+	hugepagesMu.RLock()
+	defer hugepagesMu.RUnlock()
+	if hugepagesPrice > 0 {
+		return hugepagesPrice
+	}
+	return memoryPrice
+}
+
+// getHugepages returns sum of hugepages of all page sizes in the given resources
+func getHugepages(resources api_v1.ResourceList) *resource.Quantity {
+	hugepages := &resource.Quantity{}
+	for name, quantity := range resources {
+		if strings.HasPrefix(string(name), api_v1.ResourceHugePagesPrefix) {
+			hugepages.Add(quantity)
+		}
+	}
+	return hugepages
+}
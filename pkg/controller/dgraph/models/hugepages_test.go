@@ -0,0 +1,44 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package models
+
+import (
+	"testing"
+
+	"github.com/vmware/purser/test/utils"
+	api_v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestGetHugepages(t *testing.T) {
+	resources := api_v1.ResourceList{
+		api_v1.ResourceMemory:                resource.MustParse("1Gi"),
+		api_v1.ResourceName("hugepages-2Mi"): resource.MustParse("512Mi"),
+		api_v1.ResourceName("hugepages-1Gi"): resource.MustParse("2Gi"),
+	}
+	got := getHugepages(resources)
+	utils.Equals(t, int64(2560*1024*1024), got.Value())
+	utils.Assert(t, getHugepages(api_v1.ResourceList{}).IsZero(), "expected no hugepages")
+}
+
+func TestGetHugepagesRate(t *testing.T) {
+	utils.Equals(t, 0.01, getHugepagesRate(0.01))
+	SetHugepagesPricing(0.02)
+	defer SetHugepagesPricing(0)
+	utils.Equals(t, 0.02, getHugepagesRate(0.01))
+}
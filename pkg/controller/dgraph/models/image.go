@@ -0,0 +1,57 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package models
+
+import "strings"
+
+// Image reference defaults, references without a registry are pulled from docker hub
+const (
+	DefaultRegistry      = "docker.io"
+	DefaultImageTag      = "latest"
+	officialImagesPrefix = "library/"
+)
+
+// ParseImage returns the registry, repository(<registry>/<path>) and tag of an image reference, ex: docker.io,
+// docker.io/library/nginx and 1.15 for nginx:1.15. Tag of references with only a digest is the digest.
+func ParseImage(image string) (string, string, string) {
+	name, tag := image, ""
+	if i := strings.Index(name, "@"); i >= 0 {
+		name, tag = name[:i], name[i+1:]
+	}
+	if i := strings.LastIndex(name, ":"); i > strings.LastIndex(name, "/") {
+		name, tag = name[:i], name[i+1:]
+	}
+	if tag == "" {
+		tag = DefaultImageTag
+	}
+
+	registry, path := DefaultRegistry, name
+	if parts := strings.SplitN(name, "/", 2); len(parts) == 2 && isRegistry(parts[0]) {
+		registry, path = parts[0], parts[1]
+	}
+	if registry == DefaultRegistry && !strings.Contains(path, "/") {
+		path = officialImagesPrefix + path
+	}
+	return registry, registry + "/" + path, tag
+}
+
+// isRegistry returns true if the first component of an image reference is a registry host rather than a
+// docker hub user, ex: gcr.io, localhost or registry:5000
+func isRegistry(component string) bool {
+	return strings.ContainsAny(component, ".:") || component == "localhost"
+}
@@ -0,0 +1,106 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package models
+
+import (
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	openshift_v1 "github.com/vmware/purser/pkg/apis/openshift/v1"
+	"github.com/vmware/purser/pkg/controller/dgraph"
+)
+
+// Dgraph Model Constants
+const (
+	IsImageStream = "isImageStream"
+)
+
+// ImageStream schema in dgraph
+type ImageStream struct {
+	dgraph.ID
+	IsImageStream bool       `json:"isImageStream,omitempty"`
+	Name          string     `json:"name,omitempty"`
+	StartTime     string     `json:"startTime,omitempty"`
+	EndTime       string     `json:"endTime,omitempty"`
+	Namespace     *Namespace `json:"namespace,omitempty"`
+	Repository    string     `json:"repository,omitempty"`
+	Type          string     `json:"type,omitempty"`
+}
+
+func createImageStreamObject(imageStream openshift_v1.ImageStream) ImageStream {
+	newImageStream := ImageStream{
+		Name:          "imagestream-" + imageStream.Name,
+		IsImageStream: true,
+		Type:          "imagestream",
+		ID:            dgraph.ID{Xid: imageStream.Namespace + ":" + imageStream.Name},
+		StartTime:     imageStream.GetCreationTimestamp().Time.Format(time.RFC3339),
+		Repository:    imageStream.Status.DockerImageRepository,
+	}
+	namespaceUID := CreateOrGetNamespaceByID(imageStream.Namespace)
+	if namespaceUID != "" {
+		newImageStream.Namespace = &Namespace{ID: dgraph.ID{UID: namespaceUID, Xid: imageStream.Namespace}}
+	}
+	imageStreamDeletionTimestamp := imageStream.GetDeletionTimestamp()
+	if !imageStreamDeletionTimestamp.IsZero() {
+		newImageStream.EndTime = imageStreamDeletionTimestamp.Time.Format(time.RFC3339)
+		newImageStream.Xid += newImageStream.EndTime
+		newImageStream.Name += "*" + newImageStream.EndTime
+	}
+	return newImageStream
+}
+
+// StoreImageStream create a new image stream in the Dgraph and updates if already present.
+func StoreImageStream(imageStream openshift_v1.ImageStream) (string, error) {
+	xid := imageStream.Namespace + ":" + imageStream.Name
+	uid := dgraph.GetUID(xid, IsImageStream)
+
+	newImageStream := createImageStreamObject(imageStream)
+	if uid != "" {
+		newImageStream.UID = uid
+	}
+	assigned, err := dgraph.MutateNode(newImageStream, dgraph.CREATE)
+	if err != nil {
+		return "", err
+	}
+	return assigned.Uids["blank-0"], nil
+}
+
+// CreateOrGetImageStreamByID returns the uid of image stream if exists,
+// otherwise creates the image stream and returns uid.
+func CreateOrGetImageStreamByID(xid string) string {
+	if xid == "" {
+		return ""
+	}
+	uid := dgraph.GetUID(xid, IsImageStream)
+
+	if uid != "" {
+		return uid
+	}
+
+	i := ImageStream{
+		ID:            dgraph.ID{Xid: xid},
+		Name:          xid,
+		IsImageStream: true,
+	}
+	assigned, err := dgraph.MutateNode(i, dgraph.CREATE)
+	if err != nil {
+		log.Errorf("unable to create image stream: %s, err: %v", xid, err)
+		return ""
+	}
+	return assigned.Uids["blank-0"]
+}
@@ -0,0 +1,40 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package models
+
+import (
+	"testing"
+
+	"github.com/vmware/purser/test/utils"
+)
+
+func TestParseImage(t *testing.T) {
+	for image, expected := range map[string][]string{
+		"nginx":                               {"docker.io", "docker.io/library/nginx", "latest"},
+		"nginx:1.15":                          {"docker.io", "docker.io/library/nginx", "1.15"},
+		"bitnami/redis:5.0":                   {"docker.io", "docker.io/bitnami/redis", "5.0"},
+		"gcr.io/google-containers/pause:3.1":  {"gcr.io", "gcr.io/google-containers/pause", "3.1"},
+		"localhost/app":                       {"localhost", "localhost/app", "latest"},
+		"registry:5000/team/app:v2":           {"registry:5000", "registry:5000/team/app", "v2"},
+		"quay.io/coreos/etcd@sha256:1a2b3c":   {"quay.io", "quay.io/coreos/etcd", "sha256:1a2b3c"},
+		"docker.io/library/alpine:3.8@sha256": {"docker.io", "docker.io/library/alpine", "3.8"},
+	} {
+		registry, repository, tag := ParseImage(image)
+		utils.Equals(t, expected, []string{registry, repository, tag})
+	}
+}
@@ -0,0 +1,89 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package models
+
+import (
+	"encoding/json"
+	"strings"
+	"sync"
+
+	log "github.com/Sirupsen/logrus"
+	api_v1 "k8s.io/api/core/v1"
+)
+
+// InfraTagAnnotationPrefix is the prefix of node annotations holding cloud resource tags of the node, ex:
+// tags.purser.vmware.com/cost-center=cc-42 set by Terraform along with the tags of the instance
+const InfraTagAnnotationPrefix = "tags.purser.vmware.com/"
+
+var (
+	infraTagMu   sync.RWMutex
+	infraTagKeys []string
+)
+
+// SetInfraTagKeys sets the keys of node labels or annotations which are recorded as infra tags of nodes in addition
+// to annotations with InfraTagAnnotationPrefix, ex: team or eks.amazonaws.com/nodegroup
+func SetInfraTagKeys(keys []string) {
+	infraTagMu.Lock()
+	defer infraTagMu.Unlock()
+	infraTagKeys = keys
+	log.Infof("infra tag keys: %v", infraTagKeys)
+}
+
+// getInfraTags returns the infra tags of the node encoded in JSON, empty string if it has none. Tags from
+// annotations with InfraTagAnnotationPrefix take precedence over configured keys. Values are redacted by keys of
+// the labels or annotations(see SetRedaction).
+func getInfraTags(node api_v1.Node) string {
+	tags := make(map[string]string)
+	labels, annotations := redactLabels(node.GetLabels()), redactLabels(node.GetAnnotations())
+	infraTagMu.RLock()
+	for _, key := range infraTagKeys {
+		if value, isPresent := labels[key]; isPresent {
+			tags[key] = value
+		} else if value, isPresent := annotations[key]; isPresent {
+			tags[key] = value
+		}
+	}
+	infraTagMu.RUnlock()
+	for key, value := range annotations {
+		if tagKey := strings.TrimPrefix(key, InfraTagAnnotationPrefix); tagKey != key && tagKey != "" {
+			tags[tagKey] = value
+		}
+	}
+	if len(tags) == 0 {
+		return ""
+	}
+	encoded, err := json.Marshal(tags)
+	if err != nil {
+		log.Errorf("unable to encode infra tags of node: %s, err: %v", node.Name, err)
+		return ""
+	}
+	return string(encoded)
+}
+
+// ParseInfraTags returns the infra tags stored with a node, nil if they can't be decoded
+func ParseInfraTags(tags string) map[string]string {
+	if tags == "" {
+		return nil
+	}
+	var parsed map[string]string
+	if err := json.Unmarshal([]byte(tags), &parsed); err != nil {
+		log.Errorf("unable to decode infra tags: %s, err: %v", tags, err)
+		return nil
+	}
+	return parsed
+}
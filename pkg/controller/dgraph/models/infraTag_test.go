@@ -0,0 +1,47 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package models
+
+import (
+	"testing"
+
+	"github.com/vmware/purser/test/utils"
+	api_v1 "k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGetInfraTags(t *testing.T) {
+	SetInfraTagKeys([]string{"team", "terraform-workspace", "missing"})
+	defer SetInfraTagKeys(nil)
+	utils.Equals(t, "", getInfraTags(api_v1.Node{}))
+
+	node := api_v1.Node{ObjectMeta: meta_v1.ObjectMeta{
+		Name:   "ip-10-0-1-5",
+		Labels: map[string]string{"team": "platform", "kubernetes.io/os": "linux"},
+		Annotations: map[string]string{
+			"terraform-workspace":                    "prod",
+			InfraTagAnnotationPrefix + "cost-center": "cc-42",
+			InfraTagAnnotationPrefix + "team":        "infra",
+			InfraTagAnnotationPrefix:                 "ignored",
+		},
+	}}
+	tags := getInfraTags(node)
+	utils.Equals(t, `{"cost-center":"cc-42","team":"infra","terraform-workspace":"prod"}`, tags)
+	utils.Equals(t, map[string]string{"cost-center": "cc-42", "team": "infra", "terraform-workspace": "prod"}, ParseInfraTags(tags))
+	utils.Assert(t, ParseInfraTags("{") == nil, "expected no tags")
+}
@@ -39,6 +39,7 @@ type Job struct {
 	EndTime   string     `json:"endTime,omitempty"`
 	Namespace *Namespace `json:"namespace,omitempty"`
 	Pods      []*Pod     `json:"pod,omitempty"`
+	CronJob   *CronJob   `json:"cronjob,omitempty"`
 	Type      string     `json:"type,omitempty"`
 }
 
@@ -54,6 +55,15 @@ func createJobObject(job batch_v1.Job) Job {
 	if namespaceUID != "" {
 		newJob.Namespace = &Namespace{ID: dgraph.ID{UID: namespaceUID, Xid: job.Namespace}}
 	}
+	for _, owner := range job.GetOwnerReferences() {
+		if owner.Kind != "CronJob" {
+			continue
+		}
+		cronJobUID := CreateOrGetCronJob(job.Namespace, owner.Name)
+		if cronJobUID != "" {
+			newJob.CronJob = &CronJob{ID: dgraph.ID{UID: cronJobUID, Xid: job.Namespace + ":" + owner.Name}}
+		}
+	}
 	jobDeletionTimestamp := job.GetDeletionTimestamp()
 	if !jobDeletionTimestamp.IsZero() {
 		newJob.EndTime = jobDeletionTimestamp.Time.Format(time.RFC3339)
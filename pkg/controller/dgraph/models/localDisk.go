@@ -0,0 +1,71 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package models
+
+import (
+	"sync"
+
+	log "github.com/Sirupsen/logrus"
+)
+
+// Default local disk pricing used for ephemeral storage of pods.
+// Disks of nodes default to the storage price, local disk of virtual nodes defaults to AWS Fargate ephemeral storage price.
+// Unit of rates should be USD($)-(per GB)-(per Hour)
+const (
+	DefaultLocalDiskCostInFloat64           = DefaultStorageCostInFloat64
+	DefaultServerlessLocalDiskCostInFloat64 = 0.000111
+)
+
+var (
+	localDiskMu    sync.RWMutex
+	localDiskPrice = DefaultLocalDiskCostInFloat64
+)
+
+// SetLocalDiskPricing sets per GB hourly price of local disk of nodes used for pricing ephemeral storage.
+// Non positive price is ignored and default is retained.
+func SetLocalDiskPricing(price float64) {
+	localDiskMu.Lock()
+	defer localDiskMu.Unlock()
+	if price > 0 {
+		localDiskPrice = price
+	}
+	log.Infof("local disk pricing: %v", localDiskPrice)
+}
+
+// getLocalDiskPriceForNode returns price per GB of ephemeral storage on the given node
+func getLocalDiskPriceForNode(nodeName string) float64 {
+	node, err := retrieveNode(nodeName)
+	if err == nil {
+		return getLocalDiskPriceFromNode(*node)
+	}
+	return getLocalDiskRate()
+}
+
+// getLocalDiskPriceFromNode returns price per GB of ephemeral storage, virtual nodes are priced using serverless pricing.
+func getLocalDiskPriceFromNode(node Node) float64 {
+	if node.IsVirtual {
+		return DefaultServerlessLocalDiskCostInFloat64
+	}
+	return getLocalDiskRate()
+}
+
+func getLocalDiskRate() float64 {
+	localDiskMu.RLock()
+	defer localDiskMu.RUnlock()
+	return localDiskPrice
+}
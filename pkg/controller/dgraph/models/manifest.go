@@ -0,0 +1,155 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/vmware/purser/pkg/controller/utils"
+
+	api_v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/yaml"
+)
+
+// manifestBufferSize is the number of bytes read to detect whether a manifest is JSON
+const manifestBufferSize = 4096
+
+// ManifestWorkload is a workload of a manifest with requests of each of its pods. PerNode is true if it runs a pod
+// on every node(DaemonSet), Replicas is the number of its pods otherwise.
+type ManifestWorkload struct {
+	Name          string
+	Kind          string
+	Replicas      int
+	PerNode       bool
+	CPURequest    float64
+	MemoryRequest float64
+	GPURequest    float64
+	GPUPrice      float64
+}
+
+type manifestObject struct {
+	Kind     string `json:"kind"`
+	Metadata struct {
+		Name string `json:"name"`
+	} `json:"metadata"`
+	Spec  json.RawMessage   `json:"spec"`
+	Items []json.RawMessage `json:"items"`
+}
+
+type manifestWorkloadSpec struct {
+	Replicas    *int32 `json:"replicas"`
+	Parallelism *int32 `json:"parallelism"`
+	Template    struct {
+		Spec api_v1.PodSpec `json:"spec"`
+	} `json:"template"`
+}
+
+// ParseManifest returns workloads of the YAML or JSON manifest, documents are workloads or Lists of objects.
+// Objects which do not run pods(ex: Service) are skipped, an error is returned if there are no workloads.
+func ParseManifest(data []byte) ([]ManifestWorkload, error) {
+	workloads := []ManifestWorkload{}
+	decoder := yaml.NewYAMLOrJSONDecoder(bytes.NewReader(data), manifestBufferSize)
+	for {
+		document := json.RawMessage{}
+		err := decoder.Decode(&document)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(document) == 0 || string(document) == "null" {
+			continue
+		}
+		documentWorkloads, err := parseManifestObject(document)
+		if err != nil {
+			return nil, err
+		}
+		workloads = append(workloads, documentWorkloads...)
+	}
+	if len(workloads) == 0 {
+		return nil, fmt.Errorf("manifest has no pods or workloads")
+	}
+	return workloads, nil
+}
+
+func parseManifestObject(data []byte) ([]ManifestWorkload, error) {
+	object := manifestObject{}
+	if err := json.Unmarshal(data, &object); err != nil {
+		return nil, err
+	}
+	workloads := []ManifestWorkload{}
+	switch object.Kind {
+	case "List":
+		for _, item := range object.Items {
+			itemWorkloads, err := parseManifestObject(item)
+			if err != nil {
+				return nil, err
+			}
+			workloads = append(workloads, itemWorkloads...)
+		}
+	case "Pod":
+		spec := api_v1.PodSpec{}
+		if err := json.Unmarshal(object.Spec, &spec); err != nil {
+			return nil, fmt.Errorf("invalid spec of %s %s: %v", object.Kind, object.Metadata.Name, err)
+		}
+		workloads = append(workloads, newManifestWorkload(object, spec))
+	case "Deployment", "ReplicaSet", "StatefulSet", "ReplicationController", "DeploymentConfig", "DaemonSet", "Job":
+		spec := manifestWorkloadSpec{}
+		if err := json.Unmarshal(object.Spec, &spec); err != nil {
+			return nil, fmt.Errorf("invalid spec of %s %s: %v", object.Kind, object.Metadata.Name, err)
+		}
+		replicas := spec.Replicas
+		if object.Kind == "Job" {
+			replicas = spec.Parallelism
+		}
+		workload := newManifestWorkload(object, spec.Template.Spec)
+		if replicas != nil {
+			workload.Replicas = int(*replicas)
+		}
+		workload.PerNode = object.Kind == "DaemonSet"
+		workloads = append(workloads, workload)
+	}
+	return workloads, nil
+}
+
+// newManifestWorkload returns the workload with a pod and requests of its pod spec
+func newManifestWorkload(object manifestObject, spec api_v1.PodSpec) ManifestWorkload {
+	cpuRequest := &resource.Quantity{}
+	memoryRequest := &resource.Quantity{}
+	gpuRequest := &resource.Quantity{}
+	for _, c := range spec.Containers {
+		res := getContainerResources(c)
+		utils.AddResourceAToResourceB(res.cpuRequest, cpuRequest)
+		utils.AddResourceAToResourceB(res.memoryRequest, memoryRequest)
+		utils.AddResourceAToResourceB(res.gpuRequest, gpuRequest)
+	}
+	return ManifestWorkload{
+		Name:          object.Metadata.Name,
+		Kind:          object.Kind,
+		Replicas:      1,
+		CPURequest:    utils.ConvertToFloat64CPU(cpuRequest),
+		MemoryRequest: utils.ConvertToFloat64GB(memoryRequest),
+		GPURequest:    getGPUCount(gpuRequest),
+		GPUPrice:      getGPURate(),
+	}
+}
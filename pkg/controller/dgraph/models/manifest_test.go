@@ -0,0 +1,96 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package models
+
+import (
+	"testing"
+
+	"github.com/vmware/purser/test/utils"
+)
+
+func TestParseManifest(t *testing.T) {
+	manifest := `{"kind": "List", "items": [
+		{"kind": "Deployment", "metadata": {"name": "web"}, "spec": {"replicas": 3, "template": {"spec": {"containers": [
+			{"name": "app", "resources": {"requests": {"cpu": "500m", "memory": "1Gi"}}},
+			{"name": "proxy", "resources": {"requests": {"cpu": "100m", "memory": "512Mi"}}}
+		]}}}},
+		{"kind": "Service", "metadata": {"name": "web"}, "spec": {"ports": [{"port": 80}]}},
+		{"kind": "DaemonSet", "metadata": {"name": "agent"}, "spec": {"template": {"spec": {"containers": [
+			{"name": "agent", "resources": {"requests": {"cpu": "200m"}}}
+		]}}}},
+		{"kind": "Job", "metadata": {"name": "train"}, "spec": {"parallelism": 2, "template": {"spec": {"containers": [
+			{"name": "train", "resources": {"requests": {"cpu": "4", "memory": "16Gi", "nvidia.com/gpu": "1"}}}
+		]}}}}
+	]}`
+	workloads, err := ParseManifest([]byte(manifest))
+	utils.Ok(t, err)
+	utils.Equals(t, 3, len(workloads))
+
+	utils.Equals(t, "web", workloads[0].Name)
+	utils.Equals(t, 3, workloads[0].Replicas)
+	utils.Equals(t, 0.6, workloads[0].CPURequest)
+	utils.Equals(t, 1.5, workloads[0].MemoryRequest)
+
+	utils.Equals(t, "DaemonSet", workloads[1].Kind)
+	utils.Assert(t, workloads[1].PerNode, "daemonset is not run on every node")
+
+	utils.Equals(t, 2, workloads[2].Replicas)
+	utils.Equals(t, 1.0, workloads[2].GPURequest)
+}
+
+func TestParseManifestWithYAMLDocuments(t *testing.T) {
+	manifest := `apiVersion: v1
+kind: Pod
+metadata:
+  name: debug
+spec:
+  containers:
+  - name: shell
+    resources:
+      requests:
+        cpu: 250m
+---
+apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: db
+spec:
+  replicas: 2
+  template:
+    spec:
+      containers:
+      - name: postgres
+        resources:
+          requests:
+            memory: 4Gi
+`
+	workloads, err := ParseManifest([]byte(manifest))
+	utils.Ok(t, err)
+	utils.Equals(t, []ManifestWorkload{
+		{Name: "debug", Kind: "Pod", Replicas: 1, CPURequest: 0.25, GPUPrice: getGPURate()},
+		{Name: "db", Kind: "StatefulSet", Replicas: 2, MemoryRequest: 4, GPUPrice: getGPURate()},
+	}, workloads)
+}
+
+func TestParseManifestWithoutWorkloads(t *testing.T) {
+	_, err := ParseManifest([]byte(`{"kind": "ConfigMap", "metadata": {"name": "config"}}`))
+	utils.Assert(t, err != nil, "manifest without workloads accepted")
+
+	_, err = ParseManifest([]byte(`{"kind": "Deployment", "spec": {"replicas": "three"}}`))
+	utils.Assert(t, err != nil, "invalid spec accepted")
+}
@@ -18,6 +18,8 @@
 package models
 
 import (
+	"fmt"
+	"strings"
 	"time"
 
 	log "github.com/Sirupsen/logrus"
@@ -40,6 +42,19 @@ type Namespace struct {
 	StartTime   string `json:"startTime,omitempty"`
 	EndTime     string `json:"endTime,omitempty"`
 	Type        string `json:"type,omitempty"`
+	Deleted     bool   `json:"deleted,omitempty"`
+}
+
+// softDeletedResource is a resource of a deleted namespace
+type softDeletedResource struct {
+	dgraph.ID
+	Deleted bool `json:"deleted,omitempty"`
+}
+
+// restoredResource is a resource of a deleted namespace moved to the live namespace with the same name
+type restoredResource struct {
+	dgraph.ID
+	Namespace *Namespace `json:"namespace,omitempty"`
 }
 
 func newNamespace(namespace api_v1.Namespace) Namespace {
@@ -55,6 +70,7 @@ func newNamespace(namespace api_v1.Namespace) Namespace {
 		ns.EndTime = nsDeletionTimestamp.Time.Format(time.RFC3339)
 		ns.Xid += ns.EndTime
 		ns.Name += "*" + ns.EndTime
+		ns.Deleted = true
 	}
 	return ns
 }
@@ -102,6 +118,126 @@ func StoreNamespace(namespace api_v1.Namespace) (string, error) {
 
 	if uid == "" {
 		log.Infof("Namespace with xid: (%s) persisted", xid)
+	} else if ns.Deleted {
+		if err := softDeleteNamespaceResources(uid); err != nil {
+			return "", err
+		}
 	}
 	return assigned.Uids["blank-0"], nil
 }
+
+// softDeleteNamespaceResources marks all resources of the namespace as deleted so that their cost history
+// is retained together with the namespace
+func softDeleteNamespaceResources(namespaceUID string) error {
+	q := `query {
+		var(func: uid(` + namespaceUID + `)) {
+			~namespace {
+				resources as uid
+			}
+		}
+		resources(func: uid(resources)) @filter(NOT has(deleted)) {
+			uid
+		}
+	}`
+	type root struct {
+		Resources []softDeletedResource `json:"resources"`
+	}
+	newRoot := root{}
+	if err := dgraph.ExecuteQuery(q, &newRoot); err != nil {
+		return err
+	}
+	if len(newRoot.Resources) == 0 {
+		return nil
+	}
+	for i := range newRoot.Resources {
+		newRoot.Resources[i].Deleted = true
+	}
+	_, err := dgraph.MutateNode(newRoot.Resources, dgraph.UPDATE)
+	if err == nil {
+		log.Infof("marked %d resources of deleted namespace (%s) as deleted", len(newRoot.Resources), namespaceUID)
+	}
+	return err
+}
+
+// RestoreNamespace moves cost history of deleted namespaces with the given name to the live namespace with that
+// name, restored resources are no longer marked as deleted. It returns the number of restored resources.
+func RestoreNamespace(name string) (int, error) {
+	liveUID := dgraph.GetUID(name, IsNamespace)
+	if liveUID == "" {
+		return 0, fmt.Errorf("namespace %s is not live, it has to be created again before restoring its history", name)
+	}
+	deletedNamespaces, err := retrieveDeletedNamespaces(name)
+	if err != nil {
+		return 0, err
+	}
+
+	restored := 0
+	for _, deletedNamespace := range deletedNamespaces {
+		var edges []map[string]interface{}
+		var resources []restoredResource
+		for _, resource := range deletedNamespace.Resources {
+			edges = append(edges, map[string]interface{}{
+				"uid":       resource.UID,
+				"namespace": map[string]string{"uid": deletedNamespace.UID},
+				"deleted":   nil,
+			})
+			resources = append(resources, restoredResource{
+				ID:        dgraph.ID{UID: resource.UID},
+				Namespace: &Namespace{ID: dgraph.ID{UID: liveUID}},
+			})
+		}
+		if len(resources) > 0 {
+			if _, err := dgraph.MutateNode(edges, dgraph.DELETE); err != nil {
+				return restored, err
+			}
+			if _, err := dgraph.MutateNode(resources, dgraph.UPDATE); err != nil {
+				return restored, err
+			}
+		}
+		if _, err := dgraph.MutateNode(dgraph.ID{UID: deletedNamespace.UID}, dgraph.DELETE); err != nil {
+			return restored, err
+		}
+		restored += len(resources)
+		log.Infof("restored %d resources of deleted namespace (%s) to namespace (%s)", len(resources), deletedNamespace.Name, name)
+	}
+	return restored, nil
+}
+
+type deletedNamespace struct {
+	dgraph.ID
+	Name      string      `json:"name,omitempty"`
+	Resources []dgraph.ID `json:"~namespace,omitempty"`
+}
+
+// retrieveDeletedNamespaces returns deleted namespaces with the given name with their resources
+func retrieveDeletedNamespaces(name string) ([]deletedNamespace, error) {
+	q := `query {
+		namespaces(func: has(isNamespace)) @filter(has(deleted)) {
+			uid
+			name
+			~namespace {
+				uid
+			}
+		}
+	}`
+	type root struct {
+		Namespaces []deletedNamespace `json:"namespaces"`
+	}
+	newRoot := root{}
+	if err := dgraph.ExecuteQuery(q, &newRoot); err != nil {
+		return nil, err
+	}
+	return filterDeletedNamespaces(newRoot.Namespaces, name), nil
+}
+
+// filterDeletedNamespaces returns namespaces deleted with the given name, their names are
+// "namespace-<name>*<end time>"
+func filterDeletedNamespaces(namespaces []deletedNamespace, name string) []deletedNamespace {
+	var matched []deletedNamespace
+	for _, ns := range namespaces {
+		if strings.HasPrefix(ns.Name, "namespace-"+name+"*") {
+			matched = append(matched, ns)
+		}
+	}
+	return matched
+}
@@ -0,0 +1,53 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/vmware/purser/pkg/controller/dgraph"
+	"github.com/vmware/purser/test/utils"
+
+	api_v1 "k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestNewNamespaceDeleted(t *testing.T) {
+	deletionTime := meta_v1.NewTime(time.Date(2019, 3, 1, 10, 0, 0, 0, time.UTC))
+	namespace := api_v1.Namespace{ObjectMeta: meta_v1.ObjectMeta{Name: "dev"}}
+	utils.Assert(t, !newNamespace(namespace).Deleted, "live namespace is marked as deleted")
+
+	namespace.DeletionTimestamp = &deletionTime
+	ns := newNamespace(namespace)
+	utils.Assert(t, ns.Deleted, "deleted namespace is not marked as deleted")
+	utils.Equals(t, "namespace-dev*"+ns.EndTime, ns.Name)
+}
+
+func TestFilterDeletedNamespaces(t *testing.T) {
+	namespaces := []deletedNamespace{
+		{ID: dgraph.ID{UID: "0x1"}, Name: "namespace-dev*2019-03-01T10:00:00Z"},
+		{ID: dgraph.ID{UID: "0x2"}, Name: "namespace-dev-test*2019-03-01T10:00:00Z"},
+		{ID: dgraph.ID{UID: "0x3"}, Name: "namespace-dev*2019-04-01T10:00:00Z"},
+	}
+	matched := filterDeletedNamespaces(namespaces, "dev")
+	utils.Equals(t, 2, len(matched))
+	utils.Equals(t, "0x1", matched[0].UID)
+	utils.Equals(t, "0x3", matched[1].UID)
+	utils.Assert(t, filterDeletedNamespaces(namespaces, "prod") == nil, "namespaces matched other name")
+}
@@ -35,12 +35,15 @@ const (
 	DefaultNodeOS        = "purser-default"
 	InstanceTypeLabelKey = "beta.kubernetes.io/instance-type"
 	OSLabelKey           = "beta.kubernetes.io/os"
+	ZoneLabelKey         = "failure-domain.beta.kubernetes.io/zone"
+	StableZoneLabelKey   = "topology.kubernetes.io/zone"
 )
 
 // Node schema in dgraph
 type Node struct {
 	dgraph.ID
 	IsNode         bool    `json:"isNode,omitempty"`
+	IsVirtual      bool    `json:"isVirtual,omitempty"`
 	Name           string  `json:"name,omitempty"`
 	StartTime      string  `json:"startTime,omitempty"`
 	EndTime        string  `json:"endTime,omitempty"`
@@ -50,8 +53,18 @@ type Node struct {
 	Type           string  `json:"type,omitempty"`
 	InstanceType   string  `json:"instanceType,omitempty"`
 	OS             string  `json:"os,omitempty"`
+	Region         string  `json:"region,omitempty"`
+	Zone           string  `json:"zone,omitempty"`
+	Taints         string  `json:"taints,omitempty"`
+	InfraTags      string  `json:"infraTags,omitempty"`
+	GPUShare       float64 `json:"gpuShare,omitempty"`
+	CapacityType   string  `json:"capacityType,omitempty"`
+	HourlyPrice    float64 `json:"hourlyPrice,omitempty"`
 	CPUPrice       float64 `json:"cpuPrice,omitempty"`
 	MemoryPrice    float64 `json:"memoryPrice,omitempty"`
+	CPUCarbon      float64 `json:"cpuCarbon,omitempty"`
+	MemoryCarbon   float64 `json:"memoryCarbon,omitempty"`
+	Energy         float64 `json:"energy,omitempty"`
 }
 
 func createNodeObject(node api_v1.Node) Node {
@@ -65,10 +78,26 @@ func createNodeObject(node api_v1.Node) Node {
 		MemoryCapacity: utils.ConvertToFloat64GB(node.Status.Capacity.Memory()),
 	}
 
+	// virtual nodes report fake capacity, so capacity is not stored and no idle cost is computed for them
+	if isVirtualNode(node) {
+		newNode.IsVirtual = true
+		newNode.CPUCapacity = 0
+		newNode.MemoryCapacity = 0
+	}
+
 	instanceType, os := getInstanceTypeAndOS(node)
 	newNode.InstanceType = instanceType
 	newNode.OS = os
-	log.Debugf("node: %s, instanceType: %s, os: %s", node.Name, newNode.InstanceType, newNode.OS)
+	newNode.Region = getRegion(node)
+	newNode.Zone = getZone(node)
+	newNode.Taints = getNodeTaints(node)
+	newNode.InfraTags = getInfraTags(node)
+	if share := getNodeGPUShare(node); share < 1 {
+		newNode.GPUShare = share
+	}
+	newNode.CapacityType = getCapacityType(node)
+	newNode.HourlyPrice = getNodeHourlyPrice(node)
+	log.Debugf("node: %s, instanceType: %s, os: %s, region: %s, zone: %s, capacityType: %s", node.Name, newNode.InstanceType, newNode.OS, newNode.Region, newNode.Zone, newNode.CapacityType)
 
 	nodeDeletionTimestamp := node.GetDeletionTimestamp()
 	if !nodeDeletionTimestamp.IsZero() {
@@ -107,11 +136,20 @@ func StoreNode(node api_v1.Node) (string, error) {
 	uid := dgraph.GetUID(xid, IsNode)
 
 	newNode := createNodeObject(node)
+	previousHourlyPrice := 0.0
 	if uid != "" {
 		newNode.UID = uid
+		previousHourlyPrice = retrieveNodeHourlyPrice(uid)
 	}
 
 	newNode.CPUPrice, newNode.MemoryPrice = getPricePerUnitResourceFromNodePrice(newNode)
+	newNode.CPUCarbon, newNode.MemoryCarbon = getCarbonRates(newNode)
+	if previousHourlyPrice > 0 && newNode.HourlyPrice == 0 {
+		// annotation is removed, hourlyPrice is deleted as omitempty doesn't clear it
+		if _, err := dgraph.MutateNode(map[string]interface{}{"uid": uid, "hourlyPrice": nil}, dgraph.DELETE); err != nil {
+			log.Errorf("unable to delete hourly price of node: %s, err: %v", xid, err)
+		}
+	}
 	assigned, err := dgraph.MutateNode(newNode, dgraph.CREATE)
 	if err != nil {
 		return "", err
@@ -119,6 +157,9 @@ func StoreNode(node api_v1.Node) (string, error) {
 
 	if uid == "" {
 		log.Infof("Node with xid: (%s) persisted", xid)
+	} else if newNode.HourlyPrice != previousHourlyPrice {
+		log.Infof("hourly price of node: %s changed from %v to %v", xid, previousHourlyPrice, newNode.HourlyPrice)
+		repricePodsOnNode(newNode)
 	}
 	return assigned.Uids["blank-0"], nil
 }
@@ -132,9 +173,18 @@ func getInstanceTypeAndOS(node api_v1.Node) (string, string) {
 	if value, isPresent := nodeLabels[InstanceTypeLabelKey]; isPresent {
 		instanceType = value
 	}
-	if value, isPresent := nodeLabels[OSLabelKey]; isPresent {
+	if value := getOSFromLabels(nodeLabels); value != "" {
 		os = value
 	}
 
 	return instanceType, os
 }
+
+// getZone returns the availability zone of a node from its topology labels
+func getZone(node api_v1.Node) string {
+	nodeLabels := node.GetLabels()
+	if zone, isPresent := nodeLabels[StableZoneLabelKey]; isPresent {
+		return zone
+	}
+	return nodeLabels[ZoneLabelKey]
+}
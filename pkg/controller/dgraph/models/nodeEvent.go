@@ -0,0 +1,88 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package models
+
+import (
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/vmware/purser/pkg/controller/dgraph"
+	api_v1 "k8s.io/api/core/v1"
+)
+
+// Dgraph Model Constants
+const (
+	IsNodeEvent        = "isNodeEvent"
+	NodeAdded          = "added"
+	NodeRemoved        = "removed"
+	nodeEventXIDPrefix = "nodeEvent-"
+)
+
+// NodeEvent records a node being added to or removed from the cluster, startTime is the time of the event.
+// Events are kept when the node itself is removed by retention so that churn can be analysed over long periods.
+type NodeEvent struct {
+	dgraph.ID
+	IsNodeEvent    bool    `json:"isNodeEvent,omitempty"`
+	Name           string  `json:"name,omitempty"`
+	NodeName       string  `json:"nodeName,omitempty"`
+	Event          string  `json:"event,omitempty"`
+	StartTime      string  `json:"startTime,omitempty"`
+	NodeStartTime  string  `json:"nodeStartTime,omitempty"`
+	InstanceType   string  `json:"instanceType,omitempty"`
+	CPUCapacity    float64 `json:"cpuCapacity,omitempty"`
+	MemoryCapacity float64 `json:"memoryCapacity,omitempty"`
+	CPUPrice       float64 `json:"cpuPrice,omitempty"`
+	MemoryPrice    float64 `json:"memoryPrice,omitempty"`
+}
+
+// StoreNodeEvent stores the added or removed event of the node if it isn't already stored. Added events happen at
+// the creation of the node and removed events at its deletion, or at eventTime if it has no deletion timestamp.
+func StoreNodeEvent(node api_v1.Node, event string, eventTime time.Time) error {
+	nodeStartTime := node.GetCreationTimestamp().Time.Format(time.RFC3339)
+	xid := nodeEventXIDPrefix + node.Name + "-" + nodeStartTime + "-" + event
+	if dgraph.GetUID(xid, IsNodeEvent) != "" {
+		return nil
+	}
+
+	nodeObject := createNodeObject(node)
+	nodeEvent := NodeEvent{
+		ID:             dgraph.ID{Xid: xid},
+		IsNodeEvent:    true,
+		Name:           xid,
+		NodeName:       node.Name,
+		Event:          event,
+		StartTime:      nodeStartTime,
+		NodeStartTime:  nodeStartTime,
+		InstanceType:   nodeObject.InstanceType,
+		CPUCapacity:    nodeObject.CPUCapacity,
+		MemoryCapacity: nodeObject.MemoryCapacity,
+	}
+	if event == NodeRemoved {
+		nodeEvent.StartTime = eventTime.Format(time.RFC3339)
+		if deletionTimestamp := node.GetDeletionTimestamp(); !deletionTimestamp.IsZero() {
+			nodeEvent.StartTime = deletionTimestamp.Time.Format(time.RFC3339)
+		}
+	}
+	nodeEvent.CPUPrice, nodeEvent.MemoryPrice = getPricePerUnitResourceFromNodePrice(nodeObject)
+
+	_, err := dgraph.MutateNode(nodeEvent, dgraph.CREATE)
+	if err == nil {
+		log.Infof("Node event with xid: (%s) persisted", xid)
+	}
+	return err
+}
@@ -0,0 +1,133 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package models
+
+import (
+	"strconv"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/vmware/purser/pkg/controller/dgraph"
+	api_v1 "k8s.io/api/core/v1"
+)
+
+// NodeHourlyPriceAnnotation is the node annotation setting the hourly price of the node, ex: amortized hardware cost
+// of on-prem nodes. It takes precedence over prices of pricing providers and capacity type discounts.
+const NodeHourlyPriceAnnotation = "purser.vmware.com/hourly-price"
+
+// getNodeHourlyPrice returns the hourly price annotated on the node, 0 if it isn't annotated or the price is invalid
+func getNodeHourlyPrice(node api_v1.Node) float64 {
+	value, isPresent := node.GetAnnotations()[NodeHourlyPriceAnnotation]
+	if !isPresent {
+		return 0
+	}
+	price, err := strconv.ParseFloat(value, 64)
+	if err != nil || price <= 0 {
+		log.Warnf("invalid hourly price: %q annotated on node: %s, it is ignored", value, node.Name)
+		return 0
+	}
+	return price
+}
+
+// getRatesFromHourlyPrice splits the annotated hourly price of the node between its cpus and memory
+func getRatesFromHourlyPrice(node Node) (*NodeRates, error) {
+	return getRatesFromNodePrice(node, NodePrice{InstanceType: NodeHourlyPriceAnnotation, Price: node.HourlyPrice})
+}
+
+// retrieveNodeHourlyPrice returns the hourly price stored for the node with given uid
+func retrieveNodeHourlyPrice(uid string) float64 {
+	query := `query {
+		nodes(func: uid(` + uid + `)) {
+			hourlyPrice
+		}
+	}`
+	type root struct {
+		Nodes []Node `json:"nodes"`
+	}
+	newRoot := root{}
+	err := dgraph.ExecuteQuery(query, &newRoot)
+	if err != nil || len(newRoot.Nodes) < 1 {
+		return 0
+	}
+	return newRoot.Nodes[0].HourlyPrice
+}
+
+// repricePodsOnNode updates prices of live pods on the node and of their containers which inherit pod prices.
+// It is called when the hourly price of the node changes as pods are priced only when they are stored.
+func repricePodsOnNode(node Node) {
+	query := `query {
+		nodes(func: uid(` + node.UID + `)) {
+			pods: ~node @filter(has(isPod) AND NOT has(endTime)) {
+				uid
+				cpuPrice
+				memoryPrice
+				containers: ~pod @filter(has(isContainer) AND NOT has(endTime)) {
+					uid
+					cpuPrice
+					memoryPrice
+				}
+			}
+		}
+	}`
+	type root struct {
+		Nodes []Node `json:"nodes"`
+	}
+	newRoot := root{}
+	err := dgraph.ExecuteQuery(query, &newRoot)
+	if err != nil {
+		log.Errorf("unable to retrieve pods of node: %s, err: %v", node.Name, err)
+		return
+	} else if len(newRoot.Nodes) < 1 || len(newRoot.Nodes[0].Pods) < 1 {
+		return
+	}
+
+	pods := []*Pod{}
+	containers := []*Container{}
+	for _, p := range newRoot.Nodes[0].Pods {
+		for _, c := range p.Containers {
+			// containers with overridden prices keep them
+			if c.CPUPrice == p.CPUPrice || c.MemoryPrice == p.MemoryPrice {
+				container := &Container{ID: dgraph.ID{UID: c.UID}, CPUPrice: c.CPUPrice, MemoryPrice: c.MemoryPrice}
+				if c.CPUPrice == p.CPUPrice {
+					container.CPUPrice = node.CPUPrice
+				}
+				if c.MemoryPrice == p.MemoryPrice {
+					container.MemoryPrice = node.MemoryPrice
+				}
+				containers = append(containers, container)
+			}
+		}
+		pods = append(pods, &Pod{
+			ID:             dgraph.ID{UID: p.UID},
+			CPUPrice:       node.CPUPrice,
+			MemoryPrice:    node.MemoryPrice,
+			HugepagesPrice: getHugepagesRate(node.MemoryPrice),
+		})
+	}
+
+	if _, err = dgraph.MutateNode(pods, dgraph.UPDATE); err != nil {
+		log.Errorf("unable to reprice pods of node: %s, err: %v", node.Name, err)
+		return
+	}
+	if len(containers) > 0 {
+		if _, err = dgraph.MutateNode(containers, dgraph.UPDATE); err != nil {
+			log.Errorf("unable to reprice containers of node: %s, err: %v", node.Name, err)
+			return
+		}
+	}
+	log.Infof("repriced %d pods of node: %s", len(pods), node.Name)
+}
@@ -0,0 +1,46 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package models
+
+import (
+	"testing"
+
+	"github.com/vmware/purser/test/utils"
+	api_v1 "k8s.io/api/core/v1"
+)
+
+func TestGetNodeHourlyPrice(t *testing.T) {
+	node := api_v1.Node{}
+	utils.Equals(t, 0.0, getNodeHourlyPrice(node))
+
+	node.Annotations = map[string]string{NodeHourlyPriceAnnotation: "0.192"}
+	utils.Equals(t, 0.192, getNodeHourlyPrice(node))
+
+	node.Annotations = map[string]string{NodeHourlyPriceAnnotation: "-1"}
+	utils.Equals(t, 0.0, getNodeHourlyPrice(node))
+
+	node.Annotations = map[string]string{NodeHourlyPriceAnnotation: "$0.19"}
+	utils.Equals(t, 0.0, getNodeHourlyPrice(node))
+}
+
+func TestGetPricePerUnitResourceFromHourlyPrice(t *testing.T) {
+	node := Node{Name: "node-1", CPUCapacity: 4, MemoryCapacity: 16, CapacityType: SpotCapacity, HourlyPrice: 0.192}
+	cpuPrice, memoryPrice := getPricePerUnitResourceFromNodePrice(node)
+	utils.Equals(t, 0.024, cpuPrice)
+	utils.Equals(t, 0.006, memoryPrice)
+}
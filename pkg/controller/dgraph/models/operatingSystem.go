@@ -0,0 +1,89 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package models
+
+import (
+	api_v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// Operating systems of pods and containers
+const (
+	LinuxOS          = "linux"
+	WindowsOS        = "windows"
+	StableOSLabelKey = "kubernetes.io/os"
+)
+
+// containerResources holds the effective requests and limits of a container
+type containerResources struct {
+	cpuRequest              *resource.Quantity
+	cpuLimit                *resource.Quantity
+	memoryRequest           *resource.Quantity
+	memoryLimit             *resource.Quantity
+	ephemeralStorageRequest *resource.Quantity
+	ephemeralStorageLimit   *resource.Quantity
+	hugepagesRequest        *resource.Quantity
+	hugepagesLimit          *resource.Quantity
+	gpuRequest              *resource.Quantity
+	extendedResourcePrice   float64
+}
+
+// getPodOS returns the operating system of the pod. The os node selector of the pod takes precedence,
+// otherwise os of the node on which pod is scheduled is used. Pods default to linux.
+func getPodOS(k8sPod api_v1.Pod) string {
+	if os := getOSFromLabels(k8sPod.Spec.NodeSelector); os != "" {
+		return os
+	}
+	if k8sPod.Spec.NodeName != "" {
+		node, err := retrieveNode("node-" + k8sPod.Spec.NodeName)
+		if err == nil && node.OS != "" && node.OS != DefaultNodeOS {
+			return node.OS
+		}
+	}
+	return LinuxOS
+}
+
+// getOSFromLabels returns value of beta or stable os label, empty string if neither is present
+func getOSFromLabels(labels map[string]string) string {
+	if value, isPresent := labels[OSLabelKey]; isPresent {
+		return value
+	}
+	if value, isPresent := labels[StableOSLabelKey]; isPresent {
+		return value
+	}
+	return ""
+}
+
+// getContainerResources returns requests and limits of a container, requests missing from the spec are already
+// defaulted to limits by the apiserver
+func getContainerResources(container api_v1.Container) containerResources {
+	requests := container.Resources.Requests
+	limits := container.Resources.Limits
+	return containerResources{
+		cpuRequest:              requests.Cpu(),
+		cpuLimit:                limits.Cpu(),
+		memoryRequest:           requests.Memory(),
+		memoryLimit:             limits.Memory(),
+		ephemeralStorageRequest: requests.StorageEphemeral(),
+		ephemeralStorageLimit:   limits.StorageEphemeral(),
+		hugepagesRequest:        getHugepages(requests),
+		hugepagesLimit:          getHugepages(limits),
+		gpuRequest:              getGPUs(requests),
+		extendedResourcePrice:   getExtendedResourcesPrice(requests),
+	}
+}
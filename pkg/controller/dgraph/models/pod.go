@@ -37,39 +37,102 @@ const (
 // Pod schema in dgraph
 type Pod struct {
 	dgraph.ID
-	IsPod          bool                     `json:"isPod,omitempty"`
-	Name           string                   `json:"name,omitempty"`
-	StartTime      string                   `json:"startTime,omitempty"`
-	EndTime        string                   `json:"endTime,omitempty"`
-	Containers     []*Container             `json:"containers,omitempty"`
-	Pods           []*Pod                   `json:"pod,omitempty"`
-	Count          float64                  `json:"pod|count,omitempty"`
-	Node           *Node                    `json:"node,omitempty"`
-	Namespace      *Namespace               `json:"namespace,omitempty"`
-	Deployment     *Deployment              `json:"deployment,omitempty"`
-	Replicaset     *Replicaset              `json:"replicaset,omitempty"`
-	Statefulset    *Statefulset             `json:"statefulset,omitempty"`
-	Daemonset      *Daemonset               `json:"daemonset,omitempty"`
-	Job            *Job                     `json:"job,omitempty"`
-	Pvcs           []*PersistentVolumeClaim `json:"pvc,omitempty"`
-	CPURequest     float64                  `json:"cpuRequest,omitempty"`
-	CPULimit       float64                  `json:"cpuLimit,omitempty"`
-	MemoryRequest  float64                  `json:"memoryRequest,omitempty"`
-	MemoryLimit    float64                  `json:"memoryLimit,omitempty"`
-	StorageRequest float64                  `json:"storageRequest,omitempty"`
-	Type           string                   `json:"type,omitempty"`
-	Cid            []Service                `json:"cid,omitempty"`
-	Labels         []*Label                 `json:"label,omitempty"`
-	CPUPrice       float64                  `json:"cpuPrice,omitempty"`
-	MemoryPrice    float64                  `json:"memoryPrice,omitempty"`
+	IsPod                   bool                     `json:"isPod,omitempty"`
+	Name                    string                   `json:"name,omitempty"`
+	StartTime               string                   `json:"startTime,omitempty"`
+	EndTime                 string                   `json:"endTime,omitempty"`
+	Phase                   string                   `json:"phase,omitempty"`
+	ScheduledTime           string                   `json:"scheduledTime,omitempty"`
+	RunningTime             string                   `json:"runningTime,omitempty"`
+	TerminatedTime          string                   `json:"terminatedTime,omitempty"`
+	Restarts                int                      `json:"restarts,omitempty"`
+	SchedulingConstraints   string                   `json:"schedulingConstraints,omitempty"`
+	Tolerations             string                   `json:"tolerations,omitempty"`
+	Containers              []*Container             `json:"containers,omitempty"`
+	Pods                    []*Pod                   `json:"pod,omitempty"`
+	Count                   float64                  `json:"pod|count,omitempty"`
+	RequestsPerSec          float64                  `json:"pod|rps,omitempty"`
+	Latency                 float64                  `json:"pod|latency,omitempty"`
+	SuccessRate             float64                  `json:"pod|successRate,omitempty"`
+	Dropped                 float64                  `json:"pod|dropped,omitempty"`
+	Ports                   string                   `json:"pod|ports,omitempty"`
+	Bytes                   float64                  `json:"pod|bytes,omitempty"`
+	WindowEnd               string                   `json:"pod|windowEnd,omitempty"`
+	ExternalBytes           float64                  `json:"externalBytes,omitempty"`
+	ExternalWindowEnd       string                   `json:"externalWindowEnd,omitempty"`
+	Node                    *Node                    `json:"node,omitempty"`
+	Namespace               *Namespace               `json:"namespace,omitempty"`
+	Deployment              *Deployment              `json:"deployment,omitempty"`
+	Replicaset              *Replicaset              `json:"replicaset,omitempty"`
+	Statefulset             *Statefulset             `json:"statefulset,omitempty"`
+	Daemonset               *Daemonset               `json:"daemonset,omitempty"`
+	Job                     *Job                     `json:"job,omitempty"`
+	DeploymentConfig        *DeploymentConfig        `json:"deploymentconfig,omitempty"`
+	Pvcs                    []*PersistentVolumeClaim `json:"pvc,omitempty"`
+	CPURequest              float64                  `json:"cpuRequest,omitempty"`
+	CPULimit                float64                  `json:"cpuLimit,omitempty"`
+	MemoryRequest           float64                  `json:"memoryRequest,omitempty"`
+	MemoryLimit             float64                  `json:"memoryLimit,omitempty"`
+	StorageRequest          float64                  `json:"storageRequest,omitempty"`
+	EphemeralStorageRequest float64                  `json:"ephemeralStorageRequest,omitempty"`
+	EphemeralStorageLimit   float64                  `json:"ephemeralStorageLimit,omitempty"`
+	HugepagesRequest        float64                  `json:"hugepagesRequest,omitempty"`
+	HugepagesLimit          float64                  `json:"hugepagesLimit,omitempty"`
+	GPURequest              float64                  `json:"gpuRequest,omitempty"`
+	Type                    string                   `json:"type,omitempty"`
+	Cid                     []Service                `json:"cid,omitempty"`
+	Labels                  []*Label                 `json:"label,omitempty"`
+	CPUPrice                float64                  `json:"cpuPrice,omitempty"`
+	MemoryPrice             float64                  `json:"memoryPrice,omitempty"`
+	StoragePrice            float64                  `json:"storagePrice,omitempty"`
+	EphemeralStoragePrice   float64                  `json:"ephemeralStoragePrice,omitempty"`
+	HugepagesPrice          float64                  `json:"hugepagesPrice,omitempty"`
+	GPUPrice                float64                  `json:"gpuPrice,omitempty"`
+	ExtendedResourcePrice   float64                  `json:"extendedResourcePrice,omitempty"`
+	IngressBandwidth        float64                  `json:"ingressBandwidth,omitempty"`
+	EgressBandwidth         float64                  `json:"egressBandwidth,omitempty"`
+	BandwidthPrice          float64                  `json:"bandwidthPrice,omitempty"`
+	CPUCarbon               float64                  `json:"cpuCarbon,omitempty"`
+	MemoryCarbon            float64                  `json:"memoryCarbon,omitempty"`
+	Energy                  float64                  `json:"energy,omitempty"`
+	CPUUsage                float64                  `json:"cpuUsage,omitempty"`
+	MemoryUsage             float64                  `json:"memoryUsage,omitempty"`
+	UsageSamples            int                      `json:"usageSamples,omitempty"`
+	OS                      string                   `json:"os,omitempty"`
+	Revision                string                   `json:"revision,omitempty"`
+	Application             string                   `json:"application,omitempty"`
+	ApplicationTool         string                   `json:"applicationTool,omitempty"`
+	HelmRelease             string                   `json:"helmRelease,omitempty"`
+	HelmChart               string                   `json:"helmChart,omitempty"`
+	SyntheticRequests       bool                     `json:"syntheticRequests,omitempty"`
+	SyntheticCPURequest     float64                  `json:"syntheticCpuRequest,omitempty"`
+	SyntheticMemoryRequest  float64                  `json:"syntheticMemoryRequest,omitempty"`
 }
 
 // Metrics ...
 type Metrics struct {
-	CPURequest    float64
-	CPULimit      float64
-	MemoryRequest float64
-	MemoryLimit   float64
+	CPURequest              float64
+	CPULimit                float64
+	MemoryRequest           float64
+	MemoryLimit             float64
+	EphemeralStorageRequest float64
+	EphemeralStorageLimit   float64
+	HugepagesRequest        float64
+	HugepagesLimit          float64
+	GPURequest              float64
+	ExtendedResourcePrice   float64
+}
+
+// PodInteractionMetrics holds telemetry of requests sent from a source pod to a destination pod
+type PodInteractionMetrics struct {
+	Count             float64
+	RequestsPerSecond float64
+	Latency           float64
+	SuccessRate       float64
+	Dropped           float64
+	Ports             string
+	Bytes             float64
+	WindowEnd         time.Time
 }
 
 // newPod creates a new node for the pod in the Dgraph
@@ -89,7 +152,7 @@ func newPod(k8sPod api_v1.Pod) (*api.Assigned, error) {
 	if namespaceUID != "" {
 		pod.Namespace = &Namespace{ID: dgraph.ID{UID: namespaceUID, Xid: k8sPod.Namespace}}
 	}
-	pod.Pvcs, pod.StorageRequest = getPodVolumes(k8sPod)
+	pod.Pvcs, pod.StorageRequest, pod.StoragePrice = getPodVolumes(k8sPod)
 	setPodOwners(&pod, k8sPod)
 	return dgraph.MutateNode(pod, dgraph.CREATE)
 }
@@ -126,21 +189,49 @@ func StorePod(k8sPod api_v1.Pod) error {
 		deleteContainersInTerminatedPod(podData.Containers, podDeletedTimestamp.Time)
 	} else {
 		namespaceUID := CreateOrGetNamespaceByID(k8sPod.Namespace)
-		containers, metrics := StoreAndRetrieveContainersAndMetrics(k8sPod, uid, namespaceUID)
+		os := getPodOS(k8sPod)
+		containers, metrics := StoreAndRetrieveContainersAndMetrics(k8sPod, uid, namespaceUID, os)
+		labels, annotations := redactLabels(k8sPod.Labels), redactLabels(k8sPod.Annotations)
 		pod = Pod{
-			ID:            dgraph.ID{Xid: xid, UID: uid},
-			Name:          "pod-" + k8sPod.Name,
-			Containers:    containers,
-			CPURequest:    metrics.CPURequest,
-			CPULimit:      metrics.CPULimit,
-			MemoryRequest: metrics.MemoryRequest,
-			MemoryLimit:   metrics.MemoryLimit,
+			ID:                      dgraph.ID{Xid: xid, UID: uid},
+			Name:                    "pod-" + k8sPod.Name,
+			Containers:              containers,
+			CPURequest:              metrics.CPURequest,
+			CPULimit:                metrics.CPULimit,
+			MemoryRequest:           metrics.MemoryRequest,
+			MemoryLimit:             metrics.MemoryLimit,
+			EphemeralStorageRequest: metrics.EphemeralStorageRequest,
+			EphemeralStorageLimit:   metrics.EphemeralStorageLimit,
+			HugepagesRequest:        metrics.HugepagesRequest,
+			HugepagesLimit:          metrics.HugepagesLimit,
+			GPURequest:              metrics.GPURequest,
+			ExtendedResourcePrice:   metrics.ExtendedResourcePrice,
+			OS:                      os,
+			Revision:                getRevision(annotations, labels),
 		}
-		populatePodLabels(&pod, k8sPod.Labels)
+		setSyntheticRequests(&pod, metrics, getUsageForSyntheticRequests(uid, metrics))
+		pod.IngressBandwidth = getBandwidth(annotations, IngressBandwidthAnnotation)
+		pod.EgressBandwidth = getBandwidth(annotations, EgressBandwidthAnnotation)
+		pod.BandwidthPrice = getBandwidthPrice(pod.IngressBandwidth, pod.EgressBandwidth)
+		pod.Application, pod.ApplicationTool = getApplication(labels)
+		pod.HelmRelease, pod.HelmChart = getHelmRelease(k8sPod.Namespace, labels)
+		populatePodLabels(&pod, labels)
+		setPodPhase(&pod, k8sPod)
+		pod.SchedulingConstraints = getSchedulingConstraints(k8sPod)
+		pod.Tolerations = getPodTolerations(k8sPod)
 	}
 
-	// store/update CPUPrice, MemoryPrice
+	// store/update CPUPrice, MemoryPrice of pod and its containers
 	pod.CPUPrice, pod.MemoryPrice = getPerUnitResourcePriceForNode("node-" + k8sPod.Spec.NodeName)
+	populateContainerPrices(&pod)
+	// store/update EphemeralStoragePrice
+	pod.EphemeralStoragePrice = getLocalDiskPriceForNode("node-" + k8sPod.Spec.NodeName)
+	// store/update HugepagesPrice
+	pod.HugepagesPrice = getHugepagesRate(pod.MemoryPrice)
+	// store/update GPUPrice
+	pod.GPUPrice = getGPUPriceForNode("node-" + k8sPod.Spec.NodeName)
+	// store/update CPUCarbon, MemoryCarbon
+	pod.CPUCarbon, pod.MemoryCarbon = getCarbonRatesForNode("node-" + k8sPod.Spec.NodeName)
 
 	_, err := dgraph.MutateNode(pod, dgraph.UPDATE)
 	return err
@@ -163,6 +254,38 @@ func StorePodsInteraction(sourcePodXID string, destinationPodsXIDs []string, cou
 	return err
 }
 
+// StorePodExternalTraffic stores bytes sent and received by the pod in the window ending at windowEnd from
+// destinations outside the cluster
+func StorePodExternalTraffic(podXID string, bytes float64, windowEnd time.Time) error {
+	uid := dgraph.GetUID(podXID, IsPod)
+	if uid == "" {
+		return fmt.Errorf("pod: %s is not persisted yet", podXID)
+	}
+	pod := Pod{
+		ID:                dgraph.ID{UID: uid, Xid: podXID},
+		ExternalBytes:     bytes,
+		ExternalWindowEnd: windowEnd.Format(time.RFC3339),
+	}
+	_, err := dgraph.MutateNode(pod, dgraph.UPDATE)
+	return err
+}
+
+// StorePodsInteractionWithMetrics store the pod interactions along with request count, requests per second, latency(ms), success rate and bytes transferred in Dgraph
+func StorePodsInteractionWithMetrics(sourcePodXID string, destinationPodsXIDs []string, metrics []PodInteractionMetrics) error {
+	uid := dgraph.GetUID(sourcePodXID, IsPod)
+	if uid == "" {
+		return fmt.Errorf("source pod: %s is not persisted yet", sourcePodXID)
+	}
+
+	pods := retrievePodsWithMetricsAsEdgeWeightFromPodsXIDs(destinationPodsXIDs, metrics)
+	source := Pod{
+		ID:   dgraph.ID{UID: uid, Xid: sourcePodXID},
+		Pods: pods,
+	}
+	_, err := dgraph.MutateNode(source, dgraph.UPDATE)
+	return err
+}
+
 func retrievePodsFromPodsXIDs(podsXIDs []string) []*Pod {
 	pods := []*Pod{}
 	for _, podXID := range podsXIDs {
@@ -197,6 +320,33 @@ func retrievePodsWithCountAsEdgeWeightFromPodsXIDs(podsXIDs []string, counts []f
 	return pods
 }
 
+func retrievePodsWithMetricsAsEdgeWeightFromPodsXIDs(podsXIDs []string, metrics []PodInteractionMetrics) []*Pod {
+	pods := []*Pod{}
+	for index, podXID := range podsXIDs {
+		podUID := dgraph.GetUID(podXID, IsPod)
+		if podUID == "" {
+			log.Debugf("Destination pod: %s is not persisted yet", podXID)
+			continue
+		}
+
+		pod := &Pod{
+			ID:             dgraph.ID{UID: podUID, Xid: podXID},
+			Count:          metrics[index].Count,
+			RequestsPerSec: metrics[index].RequestsPerSecond,
+			Latency:        metrics[index].Latency,
+			SuccessRate:    metrics[index].SuccessRate,
+			Dropped:        metrics[index].Dropped,
+			Ports:          metrics[index].Ports,
+			Bytes:          metrics[index].Bytes,
+		}
+		if !metrics[index].WindowEnd.IsZero() {
+			pod.WindowEnd = metrics[index].WindowEnd.Format(time.RFC3339)
+		}
+		pods = append(pods, pod)
+	}
+	return pods
+}
+
 func setPodOwners(pod *Pod, k8sPod api_v1.Pod) {
 	owners := k8sPod.GetObjectMeta().GetOwnerReferences()
 	for _, owner := range owners {
@@ -212,6 +362,8 @@ func setPodOwners(pod *Pod, k8sPod api_v1.Pod) {
 			updateJobAsPodOwner(pod, ownerXID)
 		case "DaemonSet":
 			updateDaemonsetAsPodOwner(pod, ownerXID)
+		case "ReplicationController":
+			updateDeploymentConfigAsPodOwner(pod, k8sPod)
 		default:
 			log.Error("Unknown owner type " + owner.Kind + " for pod.")
 		}
@@ -253,9 +405,27 @@ func updateDaemonsetAsPodOwner(pod *Pod, ownerXID string) {
 	}
 }
 
-func getPodVolumes(k8sPod api_v1.Pod) ([]*PersistentVolumeClaim, float64) {
+// updateDeploymentConfigAsPodOwner links the pod to its OpenShift deployment config, replication
+// controllers created by deployment configs are skipped in the hierarchy.
+func updateDeploymentConfigAsPodOwner(pod *Pod, k8sPod api_v1.Pod) {
+	dcName := getDeploymentConfigOfPod(k8sPod)
+	if dcName == "" {
+		log.Debugf("replication controller owning pod: %s is not part of a deployment config", k8sPod.Name)
+		return
+	}
+	ownerXID := k8sPod.Namespace + ":" + dcName
+	dcUID := CreateOrGetDeploymentConfigByID(ownerXID)
+	if dcUID != "" {
+		pod.DeploymentConfig = &DeploymentConfig{ID: dgraph.ID{UID: dcUID, Xid: ownerXID}}
+	}
+}
+
+// getPodVolumes returns pvcs of the pod, their total capacity(GB) and their storage price per GB per hour weighted by
+// capacity
+func getPodVolumes(k8sPod api_v1.Pod) ([]*PersistentVolumeClaim, float64, float64) {
 	podVolumes := []*PersistentVolumeClaim{}
 	storage := 0.0
+	storageCost := 0.0
 	for j := 0; j < len(k8sPod.Spec.Volumes); j++ {
 		vol := k8sPod.Spec.Volumes[j]
 		if vol.PersistentVolumeClaim != nil {
@@ -266,13 +436,24 @@ func getPodVolumes(k8sPod api_v1.Pod) ([]*PersistentVolumeClaim, float64) {
 				pvc, err := getPVCFromUID(pvcUID)
 				if err == nil {
 					storage += pvc.StorageCapacity
+					storageCost += pvc.StorageCapacity * getPVCStoragePrice(pvc)
 				} else {
 					log.Errorf("error while getting pvc from uid: (%v), error: (%v)", pvcUID, err)
 				}
 			}
 		}
 	}
-	return podVolumes, storage
+	if storage == 0 {
+		return podVolumes, storage, DefaultStorageCostInFloat64
+	}
+	return podVolumes, storage, storageCost / storage
+}
+
+func getPVCStoragePrice(pvc PersistentVolumeClaim) float64 {
+	if pvc.StoragePrice == 0 {
+		return DefaultStorageCostInFloat64
+	}
+	return pvc.StoragePrice
 }
 
 func populatePodLabels(pod *Pod, podLabels map[string]string) {
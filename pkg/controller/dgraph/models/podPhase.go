@@ -0,0 +1,100 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package models
+
+import (
+	"time"
+
+	api_v1 "k8s.io/api/core/v1"
+)
+
+// setPodPhase stores current phase of the pod, times of its Pending -> Running -> Terminated transitions and
+// restarts of its containers, times the pod hasn't reached yet are left empty
+func setPodPhase(pod *Pod, k8sPod api_v1.Pod) {
+	pod.Phase = string(k8sPod.Status.Phase)
+	pod.Restarts = getRestarts(k8sPod.Status)
+	pod.ScheduledTime = getScheduledTime(k8sPod.Status)
+	pod.RunningTime = getRunningTime(k8sPod.Status)
+	if k8sPod.Status.Phase == api_v1.PodSucceeded || k8sPod.Status.Phase == api_v1.PodFailed {
+		pod.TerminatedTime = getTerminatedTime(k8sPod.Status)
+	}
+}
+
+// getScheduledTime returns the time the pod was bound to a node
+func getScheduledTime(status api_v1.PodStatus) string {
+	for _, condition := range status.Conditions {
+		if condition.Type == api_v1.PodScheduled && condition.Status == api_v1.ConditionTrue {
+			return formatTime(condition.LastTransitionTime.Time)
+		}
+	}
+	return ""
+}
+
+// getRunningTime returns the time the first container of the pod started, start time of the pod
+// is used if the pod is running and none of its containers reports its start
+func getRunningTime(status api_v1.PodStatus) string {
+	var running time.Time
+	for _, container := range status.ContainerStatuses {
+		startedAt := getContainerStartedAt(container.State)
+		if !startedAt.IsZero() && (running.IsZero() || startedAt.Before(running)) {
+			running = startedAt
+		}
+	}
+	if running.IsZero() && status.Phase == api_v1.PodRunning && status.StartTime != nil {
+		running = status.StartTime.Time
+	}
+	return formatTime(running)
+}
+
+// getTerminatedTime returns the time the last container of the pod finished
+func getTerminatedTime(status api_v1.PodStatus) string {
+	var terminated time.Time
+	for _, container := range status.ContainerStatuses {
+		if container.State.Terminated != nil && container.State.Terminated.FinishedAt.Time.After(terminated) {
+			terminated = container.State.Terminated.FinishedAt.Time
+		}
+	}
+	return formatTime(terminated)
+}
+
+// getRestarts returns the number of restarts of all containers of the pod
+func getRestarts(status api_v1.PodStatus) int {
+	restarts := 0
+	for _, container := range status.ContainerStatuses {
+		restarts += int(container.RestartCount)
+	}
+	return restarts
+}
+
+func getContainerStartedAt(state api_v1.ContainerState) time.Time {
+	if state.Running != nil {
+		return state.Running.StartedAt.Time
+	}
+	if state.Terminated != nil {
+		return state.Terminated.StartedAt.Time
+	}
+	return time.Time{}
+}
+
+// formatTime returns the time in RFC3339, empty string for zero time
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
@@ -0,0 +1,69 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/vmware/purser/test/utils"
+	api_v1 "k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func getTestPodStatus(phase api_v1.PodPhase) api_v1.PodStatus {
+	at := func(hour int) meta_v1.Time {
+		return meta_v1.NewTime(time.Date(2018, 10, 1, hour, 0, 0, 0, time.UTC))
+	}
+	return api_v1.PodStatus{
+		Phase: phase,
+		Conditions: []api_v1.PodCondition{
+			{Type: api_v1.PodInitialized, Status: api_v1.ConditionTrue, LastTransitionTime: at(0)},
+			{Type: api_v1.PodScheduled, Status: api_v1.ConditionTrue, LastTransitionTime: at(1)},
+		},
+		ContainerStatuses: []api_v1.ContainerStatus{
+			{RestartCount: 2, State: api_v1.ContainerState{Terminated: &api_v1.ContainerStateTerminated{StartedAt: at(3), FinishedAt: at(5)}}},
+			{RestartCount: 1, State: api_v1.ContainerState{Terminated: &api_v1.ContainerStateTerminated{StartedAt: at(2), FinishedAt: at(4)}}},
+		},
+	}
+}
+
+func TestSetPodPhase(t *testing.T) {
+	pod := Pod{}
+	setPodPhase(&pod, api_v1.Pod{Status: getTestPodStatus(api_v1.PodSucceeded)})
+	utils.Equals(t, "Succeeded", pod.Phase)
+	utils.Equals(t, "2018-10-01T01:00:00Z", pod.ScheduledTime)
+	utils.Equals(t, "2018-10-01T02:00:00Z", pod.RunningTime)
+	utils.Equals(t, "2018-10-01T05:00:00Z", pod.TerminatedTime)
+	utils.Equals(t, 3, pod.Restarts)
+
+	running := Pod{}
+	setPodPhase(&running, api_v1.Pod{Status: getTestPodStatus(api_v1.PodRunning)})
+	utils.Equals(t, "", running.TerminatedTime)
+}
+
+func TestSetPodPhaseOfPendingPod(t *testing.T) {
+	pod := Pod{}
+	setPodPhase(&pod, api_v1.Pod{Status: api_v1.PodStatus{
+		Phase:      api_v1.PodPending,
+		Conditions: []api_v1.PodCondition{{Type: api_v1.PodScheduled, Status: api_v1.ConditionFalse, Reason: "Unschedulable"}},
+	}})
+	utils.Equals(t, "Pending", pod.Phase)
+	utils.Equals(t, "", pod.ScheduledTime)
+	utils.Equals(t, "", pod.RunningTime)
+}
@@ -0,0 +1,162 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package models
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/Sirupsen/logrus"
+)
+
+// Rate card pricing constants
+const (
+	// RateCardPricingProvider is the name of the default provider which uses rate card stored in dgraph
+	RateCardPricingProvider = "ratecard"
+	// NodePriceSplitRatio is the part of the price of a node assigned to its cpus when the rate card only has the
+	// price of the instance, the rest is assigned to its memory
+	NodePriceSplitRatio = 0.5
+	// RateCardOS is the operating system of rate card prices of nodes without os label
+	RateCardOS = "Linux"
+)
+
+// NodeRates structure
+// Unit of rates should be USD($)-(per unit resource)-(per Hour)
+type NodeRates struct {
+	CPUPrice    float64
+	MemoryPrice float64
+}
+
+// PricingProvider is implemented by every source of node prices(ex: cloud rate cards, static catalogs,
+// internal rate cards) so that they can be plugged in without modifying cost computation.
+type PricingProvider interface {
+	// Name returns the unique name used to select the provider in config
+	Name() string
+	// GetNodePrice returns per unit resource rates for the node, or an error if it can't price the node
+	GetNodePrice(node Node) (*NodeRates, error)
+}
+
+var (
+	providersMu       sync.RWMutex
+	pricingProviders  = make(map[string]PricingProvider)
+	selectedProviders = []string{RateCardPricingProvider}
+)
+
+func init() {
+	RegisterPricingProvider(rateCardProvider{})
+}
+
+// RegisterPricingProvider makes a pricing provider available for selection by its name.
+// Registering a provider with an existing name replaces it.
+func RegisterPricingProvider(provider PricingProvider) {
+	providersMu.Lock()
+	defer providersMu.Unlock()
+	pricingProviders[provider.Name()] = provider
+	logrus.Debugf("registered pricing provider: %s", provider.Name())
+}
+
+// SelectPricingProviders sets the ordered list of providers used for pricing nodes. Providers are
+// tried in the given order and the first price found is used, default prices are used if none succeeds.
+func SelectPricingProviders(names []string) error {
+	providersMu.Lock()
+	defer providersMu.Unlock()
+	if len(names) == 0 {
+		return fmt.Errorf("at least one pricing provider must be selected")
+	}
+	for _, name := range names {
+		if _, isPresent := pricingProviders[name]; !isPresent {
+			return fmt.Errorf("pricing provider: %s is not registered", name)
+		}
+	}
+	selectedProviders = names
+	logrus.Infof("selected pricing providers: %v", names)
+	return nil
+}
+
+// getNodeRates returns rates of the node from the first selected provider that can price it
+func getNodeRates(node Node) (float64, float64) {
+	providersMu.RLock()
+	defer providersMu.RUnlock()
+	for _, name := range selectedProviders {
+		rates, err := pricingProviders[name].GetNodePrice(node)
+		if err == nil && rates != nil {
+			return rates.CPUPrice, rates.MemoryPrice
+		}
+		logrus.Debugf("pricing provider: %s can't price node: %s, err: %v", name, node.Name, err)
+	}
+	return DefaultCPUCostInFloat64, DefaultMemCostInFloat64
+}
+
+// rateCardProvider prices nodes using node prices of the rate card stored in dgraph
+type rateCardProvider struct{}
+
+func (rateCardProvider) Name() string {
+	return RateCardPricingProvider
+}
+
+// GetNodePrice looks up the price of the instance type of the node and then of its machine family, ex: n1 for GCP
+// n1-standard-4, prices without per unit resource rates are split between cpus and memory of the node
+func (rateCardProvider) GetNodePrice(node Node) (*NodeRates, error) {
+	var err error
+	for _, xid := range getNodePriceXIDs(node) {
+		var nodePrice *NodePrice
+		nodePrice, err = retrieveNodePrice(xid)
+		if err == nil {
+			return getRatesFromNodePrice(node, *nodePrice)
+		}
+	}
+	return nil, err
+}
+
+// getNodePriceXIDs returns xids of rate card prices which can price the node in the order of preference. Cloud rate
+// cards have capitalized os names(ex: Linux), nodes without os label are priced as Linux.
+func getNodePriceXIDs(node Node) []string {
+	osNames := []string{node.OS}
+	if node.OS == DefaultNodeOS || node.OS == "" {
+		osNames = append(osNames, RateCardOS)
+	} else if title := strings.Title(node.OS); title != node.OS {
+		osNames = append(osNames, title)
+	}
+	instanceTypes := []string{node.InstanceType}
+	if index := strings.Index(node.InstanceType, "-"); index > 0 {
+		instanceTypes = append(instanceTypes, node.InstanceType[:index])
+	}
+	var xids []string
+	for _, instanceType := range instanceTypes {
+		for _, os := range osNames {
+			xids = append(xids, instanceType+"-"+os)
+		}
+	}
+	return xids
+}
+
+// getRatesFromNodePrice returns per unit resource rates of the node price, the price of the instance is split using
+// capacity of the node if the rate card has no per unit resource rates
+func getRatesFromNodePrice(node Node, nodePrice NodePrice) (*NodeRates, error) {
+	if nodePrice.PricePerCPU > 0 || nodePrice.PricePerMemory > 0 {
+		return &NodeRates{CPUPrice: nodePrice.PricePerCPU, MemoryPrice: nodePrice.PricePerMemory}, nil
+	}
+	if nodePrice.Price <= 0 || node.CPUCapacity <= 0 || node.MemoryCapacity <= 0 {
+		return nil, fmt.Errorf("node price: %s can't be split for node: %s", nodePrice.InstanceType, node.Name)
+	}
+	return &NodeRates{
+		CPUPrice:    NodePriceSplitRatio * nodePrice.Price / node.CPUCapacity,
+		MemoryPrice: (1 - NodePriceSplitRatio) * nodePrice.Price / node.MemoryCapacity,
+	}, nil
+}
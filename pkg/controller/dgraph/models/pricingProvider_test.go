@@ -0,0 +1,44 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package models
+
+import (
+	"testing"
+
+	"github.com/vmware/purser/test/utils"
+)
+
+func TestGetNodePriceXIDs(t *testing.T) {
+	utils.Equals(t, []string{"m5.large-linux", "m5.large-Linux"}, getNodePriceXIDs(Node{InstanceType: "m5.large", OS: "linux"}))
+	utils.Equals(t, []string{"n1-standard-4-purser-default", "n1-standard-4-Linux", "n1-purser-default", "n1-Linux"},
+		getNodePriceXIDs(Node{InstanceType: "n1-standard-4", OS: DefaultNodeOS}))
+}
+
+func TestGetRatesFromNodePrice(t *testing.T) {
+	node := Node{Name: "node-1", CPUCapacity: 4, MemoryCapacity: 16}
+	rates, err := getRatesFromNodePrice(node, NodePrice{PricePerCPU: 0.03, PricePerMemory: 0.004})
+	utils.Ok(t, err)
+	utils.Equals(t, &NodeRates{CPUPrice: 0.03, MemoryPrice: 0.004}, rates)
+
+	rates, err = getRatesFromNodePrice(node, NodePrice{InstanceType: "Standard_D4s_v3", Price: 0.192})
+	utils.Ok(t, err)
+	utils.Equals(t, &NodeRates{CPUPrice: 0.024, MemoryPrice: 0.006}, rates)
+
+	_, err = getRatesFromNodePrice(Node{Name: "node-2"}, NodePrice{InstanceType: "Standard_D4s_v3", Price: 0.192})
+	utils.Assert(t, err != nil, "expected error for node without capacity")
+}
@@ -38,13 +38,15 @@ const (
 // PersistentVolume schema in dgraph
 type PersistentVolume struct {
 	dgraph.ID
-	IsPersistentVolume bool    `json:"isPersistentVolume,omitempty"`
-	Name               string  `json:"name,omitempty"`
-	StartTime          string  `json:"startTime,omitempty"`
-	EndTime            string  `json:"endTime,omitempty"`
-	Type               string  `json:"type,omitempty"`
-	StorageCapacity    float64 `json:"storageCapacity,omitempty"`
-	StorageType        string  `json:"storageType,omitempty"`
+	IsPersistentVolume bool          `json:"isPersistentVolume,omitempty"`
+	Name               string        `json:"name,omitempty"`
+	StartTime          string        `json:"startTime,omitempty"`
+	EndTime            string        `json:"endTime,omitempty"`
+	Type               string        `json:"type,omitempty"`
+	StorageCapacity    float64       `json:"storageCapacity,omitempty"`
+	StorageType        string        `json:"storageType,omitempty"`
+	StorageClass       *StorageClass `json:"storageClass,omitempty"`
+	StoragePrice       float64       `json:"storagePrice,omitempty"`
 }
 
 func createPersistentVolumeObject(pv api_v1.PersistentVolume, client *kubernetes.Clientset) PersistentVolume {
@@ -59,6 +61,11 @@ func createPersistentVolumeObject(pv api_v1.PersistentVolume, client *kubernetes
 	newPv.StorageCapacity = utils.ConvertToFloat64GB(&capacity)
 	newPv.StorageType = utils.GetFinalStorageTypeOfPV(pv, client)
 	logrus.Debugf("PV: %s, storageType: %s", newPv.Name, newPv.StorageType)
+	newPv.StoragePrice = GetStorageClassPrice(pv.Spec.StorageClassName, newPv.StorageType)
+	storageClassUID := StoreStorageClass(pv.Spec.StorageClassName, newPv.StorageType)
+	if storageClassUID != "" {
+		newPv.StorageClass = &StorageClass{ID: dgraph.ID{UID: storageClassUID, Xid: pv.Spec.StorageClassName}}
+	}
 
 	deletionTimestamp := pv.GetDeletionTimestamp()
 	if !deletionTimestamp.IsZero() {
@@ -18,6 +18,7 @@
 package models
 
 import (
+	"fmt"
 	"time"
 
 	"log"
@@ -43,6 +44,12 @@ type PersistentVolumeClaim struct {
 	Type                    string            `json:"type,omitempty"`
 	StorageCapacity         float64           `json:"storageCapacity,omitempty"`
 	PersistentVolume        *PersistentVolume `json:"pv,omitempty"`
+	StorageUsed             float64           `json:"storageUsed,omitempty"`
+	StorageUsedPeak         float64           `json:"storageUsedPeak,omitempty"`
+	VolumeCapacity          float64           `json:"volumeCapacity,omitempty"`
+	UsageTime               string            `json:"usageTime,omitempty"`
+	StorageClass            *StorageClass     `json:"storageClass,omitempty"`
+	StoragePrice            float64           `json:"storagePrice,omitempty"`
 }
 
 func createPvcObject(pvc api_v1.PersistentVolumeClaim) PersistentVolumeClaim {
@@ -62,6 +69,12 @@ func createPvcObject(pvc api_v1.PersistentVolumeClaim) PersistentVolumeClaim {
 		newPvc.PersistentVolume = &PersistentVolume{ID: dgraph.ID{UID: pvUID}}
 	}
 
+	if pvc.Spec.StorageClassName != nil {
+		newPvc.StorageClass, newPvc.StoragePrice = getStorageClassForClaim(*pvc.Spec.StorageClassName)
+	} else {
+		newPvc.StoragePrice = DefaultStorageCostInFloat64
+	}
+
 	namespaceUID := CreateOrGetNamespaceByID(pvc.Namespace)
 	if namespaceUID != "" {
 		newPvc.Namespace = &Namespace{ID: dgraph.ID{UID: namespaceUID, Xid: pvc.Namespace}}
@@ -122,6 +135,7 @@ func getPVCFromUID(uid string) (PersistentVolumeClaim, error) {
 			name
 			type
 			storageCapacity
+			storagePrice
 		}
 	}`
 
@@ -135,3 +149,46 @@ func getPVCFromUID(uid string) (PersistentVolumeClaim, error) {
 	}
 	return newRoot.Pvcs[0], nil
 }
+
+// UpdatePersistentVolumeClaimUsage stores space used(GB) and capacity(GB) of the volume of the pvc as reported by
+// kubelet volume stats, and raises its peak usage if exceeded
+func UpdatePersistentVolumeClaimUsage(xid string, usedGB, capacityGB float64, usageTime time.Time) error {
+	uid := dgraph.GetUID(xid, IsPersistentVolumeClaim)
+	if uid == "" {
+		return fmt.Errorf("pvc: %s is not persisted yet", xid)
+	}
+	peak, err := retrieveStorageUsedPeak(uid)
+	if err != nil {
+		return err
+	}
+	if usedGB > peak {
+		peak = usedGB
+	}
+	pvc := PersistentVolumeClaim{
+		ID:              dgraph.ID{UID: uid, Xid: xid},
+		StorageUsed:     usedGB,
+		StorageUsedPeak: peak,
+		VolumeCapacity:  capacityGB,
+		UsageTime:       usageTime.Format(time.RFC3339),
+	}
+	_, err = dgraph.MutateNode(pvc, dgraph.UPDATE)
+	return err
+}
+
+// retrieveStorageUsedPeak returns the highest space used(GB) stored for the pvc with given uid
+func retrieveStorageUsedPeak(uid string) (float64, error) {
+	q := `query {
+		pvcs(func: uid(` + uid + `)) {
+			storageUsedPeak
+		}
+	}`
+	type root struct {
+		Pvcs []PersistentVolumeClaim `json:"pvcs"`
+	}
+	newRoot := root{}
+	err := dgraph.ExecuteQuery(q, &newRoot)
+	if err != nil || len(newRoot.Pvcs) < 1 {
+		return 0, err
+	}
+	return newRoot.Pvcs[0].StorageUsedPeak, nil
+}
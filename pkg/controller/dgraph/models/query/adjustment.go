@@ -0,0 +1,173 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"strings"
+	"sync"
+)
+
+// Cost types passed to cost adjustments
+const (
+	CPUCostType              = "cpu"
+	MemoryCostType           = "memory"
+	StorageCostType          = "storage"
+	GPUCostType              = "gpu"
+	ExtendedResourceCostType = "extendedResource"
+	BandwidthCostType        = "bandwidth"
+	TotalCostType            = "total"
+)
+
+// CostContext describes the cost being adjusted
+type CostContext struct {
+	ResourceType string
+	ResourceName string
+	CostType     string
+}
+
+// CostAdjustment is a post-processing hook applied on every computed cost before it is returned,
+// ex: internal overhead multipliers, taxes, rounding policies.
+type CostAdjustment interface {
+	// Name returns name of the adjustment used in logs
+	Name() string
+	// Adjust returns the adjusted value of the cost
+	Adjust(ctx CostContext, cost float64) float64
+}
+
+// BatchCostAdjustment is a CostAdjustment which adjusts many costs at once, ex: in one request to an external service.
+// Costs of a response are adjusted with one call of AdjustAll.
+type BatchCostAdjustment interface {
+	CostAdjustment
+	// AdjustAll returns the adjusted values of the costs in the order of ctxs
+	AdjustAll(ctxs []CostContext, costs []float64) []float64
+}
+
+var (
+	adjustmentsMu   sync.RWMutex
+	costAdjustments []CostAdjustment
+)
+
+// SetCostAdjustments sets the adjustments applied(in the given order) on computed costs
+func SetCostAdjustments(adjustments []CostAdjustment) {
+	adjustmentsMu.Lock()
+	defer adjustmentsMu.Unlock()
+	costAdjustments = adjustments
+}
+
+// adjustCost applies cost adjustments and then the cost rounding policy on the cost
+func adjustCost(ctx CostContext, cost float64) float64 {
+	adjustmentsMu.RLock()
+	defer adjustmentsMu.RUnlock()
+	if cost == 0 {
+		return cost
+	}
+	for _, adjustment := range costAdjustments {
+		cost = adjustment.Adjust(ctx, cost)
+	}
+	return roundCost(cost)
+}
+
+// responseCost is a cost of a response with its context
+type responseCost struct {
+	ctx  CostContext
+	cost *float64
+}
+
+// adjustCosts applies cost adjustments on costs of parent and its children
+func adjustCosts(parent *ParentWrapper) {
+	costs := []responseCost{
+		{CostContext{parent.Type, parent.Name, CPUCostType}, &parent.CPUCost},
+		{CostContext{parent.Type, parent.Name, MemoryCostType}, &parent.MemoryCost},
+		{CostContext{parent.Type, parent.Name, StorageCostType}, &parent.StorageCost},
+		{CostContext{parent.Type, parent.Name, StorageCostType}, &parent.EphemeralStorageCost},
+		{CostContext{parent.Type, parent.Name, MemoryCostType}, &parent.HugepagesCost},
+		{CostContext{parent.Type, parent.Name, GPUCostType}, &parent.GPUCost},
+		{CostContext{parent.Type, parent.Name, ExtendedResourceCostType}, &parent.ExtendedResourceCost},
+		{CostContext{parent.Type, parent.Name, BandwidthCostType}, &parent.BandwidthCost},
+		{CostContext{parent.Type, parent.Name, CPUCostType}, &parent.UtilizedCPUCost},
+		{CostContext{parent.Type, parent.Name, MemoryCostType}, &parent.UtilizedMemoryCost},
+	}
+	for index := range parent.Children {
+		child := &parent.Children[index]
+		costs = append(costs,
+			responseCost{CostContext{child.Type, child.Name, CPUCostType}, &child.CPUCost},
+			responseCost{CostContext{child.Type, child.Name, MemoryCostType}, &child.MemoryCost},
+			responseCost{CostContext{child.Type, child.Name, StorageCostType}, &child.StorageCost},
+			responseCost{CostContext{child.Type, child.Name, StorageCostType}, &child.EphemeralStorageCost},
+			responseCost{CostContext{child.Type, child.Name, MemoryCostType}, &child.HugepagesCost},
+			responseCost{CostContext{child.Type, child.Name, GPUCostType}, &child.GPUCost},
+			responseCost{CostContext{child.Type, child.Name, ExtendedResourceCostType}, &child.ExtendedResourceCost},
+			responseCost{CostContext{child.Type, child.Name, BandwidthCostType}, &child.BandwidthCost},
+			responseCost{CostContext{child.Type, child.Name, CPUCostType}, &child.UtilizedCPUCost},
+			responseCost{CostContext{child.Type, child.Name, MemoryCostType}, &child.UtilizedMemoryCost},
+		)
+	}
+	adjustResponseCosts(costs)
+}
+
+// adjustResponseCosts applies cost adjustments and then the cost rounding policy on the costs of a response like
+// adjustCost, batch adjustments get all costs which are not zero in one call
+func adjustResponseCosts(costs []responseCost) {
+	adjustmentsMu.RLock()
+	defer adjustmentsMu.RUnlock()
+	ctxs := []CostContext{}
+	values := []float64{}
+	for _, cost := range costs {
+		if *cost.cost != 0 {
+			ctxs = append(ctxs, cost.ctx)
+			values = append(values, *cost.cost)
+		}
+	}
+	if len(values) == 0 {
+		return
+	}
+	for _, adjustment := range costAdjustments {
+		if batch, isBatch := adjustment.(BatchCostAdjustment); isBatch {
+			if adjusted := batch.AdjustAll(ctxs, values); len(adjusted) == len(values) {
+				values = adjusted
+			} else {
+				log.Errorf("cost adjustment %s returned %d costs for %d costs", adjustment.Name(), len(adjusted), len(values))
+			}
+			continue
+		}
+		for index := range values {
+			values[index] = adjustment.Adjust(ctxs[index], values[index])
+		}
+	}
+	index := 0
+	for _, cost := range costs {
+		if *cost.cost != 0 {
+			*cost.cost = roundCost(values[index])
+			index++
+		}
+	}
+}
+
+// getCostTypeOfGroupMetric returns cost type of a group metric key(ex: lastMonthCPUCost), empty if it is not a cost
+func getCostTypeOfGroupMetric(key string) string {
+	lowerKey := strings.ToLower(key)
+	if !strings.Contains(lowerKey, "cost") {
+		return ""
+	}
+	for _, costType := range []string{CPUCostType, MemoryCostType, StorageCostType} {
+		if strings.Contains(lowerKey, costType) {
+			return costType
+		}
+	}
+	return ""
+}
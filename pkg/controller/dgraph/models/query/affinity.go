@@ -0,0 +1,355 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"context"
+	"sort"
+
+	"github.com/vmware/purser/pkg/controller/dgraph/models"
+)
+
+// HostnameTopologyKey is the topology key of node level (anti-)affinity
+const HostnameTopologyKey = "kubernetes.io/hostname"
+
+// zoneTopologyKeys are the topology keys of zone level (anti-)affinity
+var zoneTopologyKeys = map[string]bool{
+	"failure-domain.beta.kubernetes.io/zone": true,
+	"topology.kubernetes.io/zone":            true,
+}
+
+// AffinityGroupImpact is the extra capacity forced by a constraint shared by pods of a namespace, pods with the
+// same constraint are assumed to be selected by it. Name and Type are those of the workload of its first pod.
+// Only anti-affinity over nodes or zones is modeled, extra cost of other constraints is zero.
+type AffinityGroupImpact struct {
+	Name             string  `json:"name"`
+	Type             string  `json:"type"`
+	Namespace        string  `json:"namespace"`
+	Kind             string  `json:"kind"`
+	TopologyKey      string  `json:"topologyKey"`
+	Selector         string  `json:"selector,omitempty"`
+	Pods             int     `json:"pods"`
+	Modeled          bool    `json:"modeled"`
+	ExtraNodes       int     `json:"extraNodes"`
+	ExtraHourlyCost  float64 `json:"extraHourlyCost"`
+	ExtraMonthlyCost float64 `json:"extraMonthlyCost"`
+}
+
+// AffinityCostImpact compares the nodes needed to pack live pods on nodes of the cluster honoring their required
+// anti-affinity with the nodes needed ignoring it. Extra cost of a group is the cost of the constrained packing
+// above that of the packing without the constraint of the group, groups are sorted by it.
+type AffinityCostImpact struct {
+	Nodes                   int                   `json:"nodes"`
+	Pods                    int                   `json:"pods"`
+	ConstrainedPods         int                   `json:"constrainedPods"`
+	UnconstrainedNodes      int                   `json:"unconstrainedNodes"`
+	ConstrainedNodes        int                   `json:"constrainedNodes"`
+	UnplacedPods            int                   `json:"unplacedPods"`
+	UnconstrainedHourlyCost float64               `json:"unconstrainedHourlyCost"`
+	ConstrainedHourlyCost   float64               `json:"constrainedHourlyCost"`
+	ExtraNodes              int                   `json:"extraNodes"`
+	ExtraCPU                float64               `json:"extraCPU"`
+	ExtraMemory             float64               `json:"extraMemory"`
+	ExtraHourlyCost         float64               `json:"extraHourlyCost"`
+	ExtraMonthlyCost        float64               `json:"extraMonthlyCost"`
+	Groups                  []AffinityGroupImpact `json:"groups"`
+}
+
+// AffinityCostImpactWrapper structure
+type AffinityCostImpactWrapper struct {
+	Data AffinityCostImpact `json:"data"`
+}
+
+type affinityNode struct {
+	Name           string  `json:"name"`
+	Zone           string  `json:"zone"`
+	CPUCapacity    float64 `json:"cpuCapacity"`
+	MemoryCapacity float64 `json:"memoryCapacity"`
+	CPUPrice       float64 `json:"cpuPrice"`
+	MemoryPrice    float64 `json:"memoryPrice"`
+}
+
+type affinityPod struct {
+	triggeringPod
+	CPURequest            float64 `json:"cpuRequest"`
+	MemoryRequest         float64 `json:"memoryRequest"`
+	SchedulingConstraints string  `json:"schedulingConstraints"`
+}
+
+// packingPod is a pod to be packed, groups are indexes of the modeled groups constraining it
+type packingPod struct {
+	name   string
+	cpu    float64
+	memory float64
+	groups []int
+}
+
+// packedNode is a node opened by a packing with the requests and groups of pods placed on it
+type packedNode struct {
+	node   affinityNode
+	cpu    float64
+	memory float64
+}
+
+type packing struct {
+	nodes    []packedNode
+	unplaced int
+}
+
+// RetrieveAffinityCostImpact estimates the extra node capacity and cost which required pod anti-affinity of
+// live pods forces compared to packing them without constraints on nodes of the cluster.
+func RetrieveAffinityCostImpact(ctx context.Context) AffinityCostImpactWrapper {
+	root := struct {
+		Nodes []affinityNode `json:"nodes"`
+		Pods  []affinityPod  `json:"pods"`
+	}{}
+	err := executeQuery(ctx, getQueryForAffinityCostImpact(), &root)
+	if err != nil {
+		log.Errorf("unable to retrieve pods and nodes for affinity analysis, err: %v", err)
+		return AffinityCostImpactWrapper{}
+	}
+	return AffinityCostImpactWrapper{Data: computeAffinityCostImpact(root.Pods, root.Nodes)}
+}
+
+func getQueryForAffinityCostImpact() string {
+	owners := ``
+	for _, ownerType := range podOwnerPredicates {
+		owners += `
+			` + ownerType + ` {
+				name
+			}`
+	}
+	return `{
+		nodes(func: has(isNode)) @filter((NOT has(endTime)) AND (NOT has(isVirtual)) AND gt(cpuCapacity, 0)) {
+			name
+			zone
+			cpuCapacity
+			memoryCapacity
+			cpuPrice
+			memoryPrice
+		}
+		pods(func: has(isPod)) @filter((NOT has(endTime)) AND has(node)) {
+			name
+			cpuRequest
+			memoryRequest
+			schedulingConstraints
+			namespace {
+				name
+			}` + owners + `
+		}
+	}`
+}
+
+// computeAffinityCostImpact packs the pods honoring all constraints, none of them and all but the constraint of
+// each group, pods are packed first fit decreasing on nodes opened cheapest per CPU first
+func computeAffinityCostImpact(pods []affinityPod, nodes []affinityNode) AffinityCostImpact {
+	impact := AffinityCostImpact{Nodes: len(nodes), Pods: len(pods), Groups: []AffinityGroupImpact{}}
+	sortNodesForPacking(nodes)
+	packingPods, groups := getPackingPods(pods)
+	for _, pod := range packingPods {
+		if len(pod.groups) > 0 {
+			impact.ConstrainedPods++
+		}
+	}
+
+	ignoreAll := map[int]bool{}
+	for index := range groups {
+		ignoreAll[index] = true
+	}
+	unconstrained := packPods(packingPods, nodes, groups, ignoreAll)
+	constrained := packPods(packingPods, nodes, groups, map[int]bool{})
+	impact.UnconstrainedNodes = len(unconstrained.nodes)
+	impact.ConstrainedNodes = len(constrained.nodes)
+	impact.UnplacedPods = constrained.unplaced
+	impact.UnconstrainedHourlyCost = unconstrained.hourlyCost()
+	impact.ConstrainedHourlyCost = constrained.hourlyCost()
+	impact.ExtraNodes = impact.ConstrainedNodes - impact.UnconstrainedNodes
+	impact.ExtraCPU = constrained.cpuCapacity() - unconstrained.cpuCapacity()
+	impact.ExtraMemory = constrained.memoryCapacity() - unconstrained.memoryCapacity()
+	impact.ExtraHourlyCost = impact.ConstrainedHourlyCost - impact.UnconstrainedHourlyCost
+	impact.ExtraMonthlyCost = impact.ExtraHourlyCost * models.HoursInMonth
+
+	for index, group := range groups {
+		if group.Modeled {
+			withoutGroup := packPods(packingPods, nodes, groups, map[int]bool{index: true})
+			group.ExtraNodes = impact.ConstrainedNodes - len(withoutGroup.nodes)
+			group.ExtraHourlyCost = impact.ConstrainedHourlyCost - withoutGroup.hourlyCost()
+			group.ExtraMonthlyCost = group.ExtraHourlyCost * models.HoursInMonth
+		}
+		impact.Groups = append(impact.Groups, group)
+	}
+	sort.SliceStable(impact.Groups, func(i, j int) bool {
+		return impact.Groups[i].ExtraHourlyCost > impact.Groups[j].ExtraHourlyCost
+	})
+	return impact
+}
+
+// getPackingPods returns pods sorted by requests, largest first, and the groups of their constraints
+func getPackingPods(pods []affinityPod) ([]packingPod, []AffinityGroupImpact) {
+	var packingPods []packingPod
+	var groups []AffinityGroupImpact
+	groupIndexes := make(map[string]int)
+	for _, pod := range pods {
+		packing := packingPod{name: pod.Name, cpu: pod.CPURequest, memory: pod.MemoryRequest}
+		name, workloadType, namespace := getPodOwner(pod.triggeringPod)
+		for _, constraint := range models.ParseSchedulingConstraints(pod.SchedulingConstraints) {
+			key := namespace + "/" + constraint.Kind + "/" + constraint.TopologyKey + "/" + constraint.Selector
+			index, isPresent := groupIndexes[key]
+			if !isPresent {
+				index = len(groups)
+				groupIndexes[key] = index
+				groups = append(groups, AffinityGroupImpact{
+					Name:        name,
+					Type:        workloadType,
+					Namespace:   namespace,
+					Kind:        constraint.Kind,
+					TopologyKey: constraint.TopologyKey,
+					Selector:    constraint.Selector,
+					Modeled:     isModeledConstraint(constraint),
+				})
+			}
+			groups[index].Pods++
+			if groups[index].Modeled {
+				packing.groups = append(packing.groups, index)
+			}
+		}
+		packingPods = append(packingPods, packing)
+	}
+	sort.SliceStable(packingPods, func(i, j int) bool {
+		if packingPods[i].cpu != packingPods[j].cpu {
+			return packingPods[i].cpu > packingPods[j].cpu
+		}
+		if packingPods[i].memory != packingPods[j].memory {
+			return packingPods[i].memory > packingPods[j].memory
+		}
+		return packingPods[i].name < packingPods[j].name
+	})
+	return packingPods, groups
+}
+
+// isModeledConstraint returns true for anti-affinity over nodes or zones, affinity only co-locates pods
+func isModeledConstraint(constraint models.SchedulingConstraint) bool {
+	return constraint.Kind == models.PodAntiAffinityConstraint &&
+		(constraint.TopologyKey == HostnameTopologyKey || zoneTopologyKeys[constraint.TopologyKey])
+}
+
+// sortNodesForPacking orders nodes by hourly cost per CPU, larger nodes first for the same cost
+func sortNodesForPacking(nodes []affinityNode) {
+	sort.SliceStable(nodes, func(i, j int) bool {
+		costI, costJ := nodeHourlyCost(nodes[i])/nodes[i].CPUCapacity, nodeHourlyCost(nodes[j])/nodes[j].CPUCapacity
+		if costI != costJ {
+			return costI < costJ
+		}
+		if nodes[i].CPUCapacity != nodes[j].CPUCapacity {
+			return nodes[i].CPUCapacity > nodes[j].CPUCapacity
+		}
+		return nodes[i].Name < nodes[j].Name
+	})
+}
+
+// packPods places each pod on the first opened node it fits on without violating the anti-affinity of its groups,
+// opening the next node if none fits. Constraints of ignored groups are not honored.
+func packPods(pods []packingPod, nodes []affinityNode, groups []AffinityGroupImpact, ignored map[int]bool) packing {
+	var result packing
+	opened := make([]bool, len(nodes))
+	openedIndexes := []int{}
+	placed := make([]packedNode, len(nodes))
+	// occupied topology domains of each group
+	domains := make(map[int]map[string]bool)
+	for _, pod := range pods {
+		canPlace := func(index int) bool {
+			node := placed[index]
+			if node.cpu+pod.cpu > nodes[index].CPUCapacity || node.memory+pod.memory > nodes[index].MemoryCapacity {
+				return false
+			}
+			for _, group := range pod.groups {
+				if !ignored[group] && domains[group][getTopologyDomain(nodes[index], groups[group].TopologyKey)] {
+					return false
+				}
+			}
+			return true
+		}
+		target := -1
+		for _, index := range openedIndexes {
+			if canPlace(index) {
+				target = index
+				break
+			}
+		}
+		for index := 0; target < 0 && index < len(nodes); index++ {
+			if !opened[index] && canPlace(index) {
+				target = index
+				opened[index] = true
+				openedIndexes = append(openedIndexes, index)
+			}
+		}
+		if target < 0 {
+			result.unplaced++
+			continue
+		}
+		placed[target].node = nodes[target]
+		placed[target].cpu += pod.cpu
+		placed[target].memory += pod.memory
+		for _, group := range pod.groups {
+			if domains[group] == nil {
+				domains[group] = make(map[string]bool)
+			}
+			domains[group][getTopologyDomain(nodes[target], groups[group].TopologyKey)] = true
+		}
+	}
+	for _, index := range openedIndexes {
+		result.nodes = append(result.nodes, placed[index])
+	}
+	return result
+}
+
+// getTopologyDomain returns the domain of the node in the topology of the key
+func getTopologyDomain(node affinityNode, topologyKey string) string {
+	if topologyKey == HostnameTopologyKey {
+		return node.Name
+	}
+	return node.Zone
+}
+
+func nodeHourlyCost(node affinityNode) float64 {
+	return node.CPUCapacity*node.CPUPrice + node.MemoryCapacity*node.MemoryPrice
+}
+
+func (p packing) hourlyCost() float64 {
+	cost := 0.0
+	for _, node := range p.nodes {
+		cost += nodeHourlyCost(node.node)
+	}
+	return cost
+}
+
+func (p packing) cpuCapacity() float64 {
+	capacity := 0.0
+	for _, node := range p.nodes {
+		capacity += node.node.CPUCapacity
+	}
+	return capacity
+}
+
+func (p packing) memoryCapacity() float64 {
+	capacity := 0.0
+	for _, node := range p.nodes {
+		capacity += node.node.MemoryCapacity
+	}
+	return capacity
+}
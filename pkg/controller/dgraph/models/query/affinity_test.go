@@ -0,0 +1,89 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func mockDgraphForAffinityCostImpact() {
+	executeQuery = func(ctx context.Context, query string, root interface{}) error {
+		return json.Unmarshal([]byte(`{
+			"nodes": [
+				{"name": "node-1", "zone": "zone-a", "cpuCapacity": 4, "memoryCapacity": 16, "cpuPrice": 0.1, "memoryPrice": 0},
+				{"name": "node-2", "zone": "zone-a", "cpuCapacity": 4, "memoryCapacity": 16, "cpuPrice": 0.1, "memoryPrice": 0},
+				{"name": "node-3", "zone": "zone-b", "cpuCapacity": 4, "memoryCapacity": 16, "cpuPrice": 0.1, "memoryPrice": 0}
+			],
+			"pods": [
+				{"name": "pod-web-1", "cpuRequest": 1, "memoryRequest": 1, "namespace": {"name": "namespace-shop"}, "deployment": {"name": "deployment-web"},
+					"schedulingConstraints": "[{\"kind\":\"podAntiAffinity\",\"topologyKey\":\"kubernetes.io/hostname\",\"selector\":\"app=web\"}]"},
+				{"name": "pod-web-2", "cpuRequest": 1, "memoryRequest": 1, "namespace": {"name": "namespace-shop"}, "deployment": {"name": "deployment-web"},
+					"schedulingConstraints": "[{\"kind\":\"podAntiAffinity\",\"topologyKey\":\"kubernetes.io/hostname\",\"selector\":\"app=web\"}]"},
+				{"name": "pod-web-3", "cpuRequest": 1, "memoryRequest": 1, "namespace": {"name": "namespace-shop"}, "deployment": {"name": "deployment-web"},
+					"schedulingConstraints": "[{\"kind\":\"podAntiAffinity\",\"topologyKey\":\"kubernetes.io/hostname\",\"selector\":\"app=web\"}]"},
+				{"name": "pod-cache", "cpuRequest": 0.5, "memoryRequest": 1, "namespace": {"name": "namespace-shop"}, "statefulset": {"name": "statefulset-cache"},
+					"schedulingConstraints": "[{\"kind\":\"podAffinity\",\"topologyKey\":\"kubernetes.io/hostname\",\"selector\":\"app=web\"}]"}
+			]
+		}`), root)
+	}
+}
+
+// TestRetrieveAffinityCostImpact ...
+func TestRetrieveAffinityCostImpact(t *testing.T) {
+	mockDgraphForAffinityCostImpact()
+	got := RetrieveAffinityCostImpact(context.Background()).Data
+	assert.Equal(t, 3, got.Nodes)
+	assert.Equal(t, 4, got.Pods)
+	assert.Equal(t, 3, got.ConstrainedPods)
+	assert.Equal(t, 1, got.UnconstrainedNodes)
+	assert.Equal(t, 3, got.ConstrainedNodes)
+	assert.Equal(t, 0, got.UnplacedPods)
+	assert.Equal(t, 2, got.ExtraNodes)
+	assert.InDelta(t, 8, got.ExtraCPU, 0.0001)
+	assert.InDelta(t, 32, got.ExtraMemory, 0.0001)
+	assert.InDelta(t, 0.8, got.ExtraHourlyCost, 0.0001)
+	assert.InDelta(t, 576, got.ExtraMonthlyCost, 0.0001)
+
+	assert.Equal(t, 2, len(got.Groups))
+	assert.Equal(t, "deployment-web", got.Groups[0].Name)
+	assert.Equal(t, DeploymentType, got.Groups[0].Type)
+	assert.Equal(t, 3, got.Groups[0].Pods)
+	assert.True(t, got.Groups[0].Modeled)
+	assert.Equal(t, 2, got.Groups[0].ExtraNodes)
+	assert.Equal(t, "statefulset-cache", got.Groups[1].Name)
+	assert.False(t, got.Groups[1].Modeled)
+	assert.Equal(t, 0.0, got.Groups[1].ExtraHourlyCost)
+}
+
+// TestPackPodsWithZoneAntiAffinity ...
+func TestPackPodsWithZoneAntiAffinity(t *testing.T) {
+	nodes := []affinityNode{
+		{Name: "node-1", Zone: "zone-a", CPUCapacity: 4, MemoryCapacity: 16},
+		{Name: "node-2", Zone: "zone-a", CPUCapacity: 4, MemoryCapacity: 16},
+	}
+	groups := []AffinityGroupImpact{{TopologyKey: "topology.kubernetes.io/zone", Modeled: true}}
+	pods := []packingPod{{name: "pod-1", cpu: 1, groups: []int{0}}, {name: "pod-2", cpu: 1, groups: []int{0}}}
+
+	got := packPods(pods, nodes, groups, map[int]bool{})
+	assert.Equal(t, 1, len(got.nodes))
+	assert.Equal(t, 1, got.unplaced)
+}
@@ -0,0 +1,57 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"context"
+	"github.com/vmware/purser/pkg/controller/dgraph/models"
+	qb "github.com/vmware/purser/pkg/querybuilder"
+)
+
+// State query param
+const State = "state"
+
+// AlertsWrapper structure
+type AlertsWrapper struct {
+	Data []models.Alert `json:"data"`
+}
+
+// RetrieveAlerts returns alerts in the given state(firing or resolved) latest first, all alerts if state is empty
+func RetrieveAlerts(ctx context.Context, state string) AlertsWrapper {
+	type root struct {
+		Alerts []models.Alert `json:"alerts"`
+	}
+	newRoot := root{}
+	err := executeQuery(ctx, getQueryForAlerts(state), &newRoot)
+	if err != nil {
+		log.Errorf("unable to retrieve alerts, state: %s, err: %v", state, err)
+		return AlertsWrapper{}
+	}
+	return AlertsWrapper{Data: newRoot.Alerts}
+}
+
+func getQueryForAlerts(state string) string {
+	filter := qb.Filter{}
+	if state != All {
+		filter = qb.Eq(State, state)
+	}
+	return qb.New(
+		qb.Func("alerts", qb.Has("isAlert")).OrderDesc("startTime").Filter(filter).
+			Fields("name", "rule", "metric", "scope", "resource", "severity", "state", "alertValue", "threshold", "summary", "startTime", "endTime"),
+	).String()
+}
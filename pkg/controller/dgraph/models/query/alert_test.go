@@ -0,0 +1,42 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vmware/purser/pkg/controller/dgraph/models"
+)
+
+// TestRetrieveAlerts ...
+func TestRetrieveAlerts(t *testing.T) {
+	var got string
+	executeQuery = func(ctx context.Context, query string, root interface{}) error {
+		got = query
+		return json.Unmarshal([]byte(`{"alerts": [{"rule": "high-cost", "state": "firing", "alertValue": 120}]}`), root)
+	}
+	alerts := RetrieveAlerts(context.Background(), "firing")
+	assert.Contains(t, got, `@filter(eq(state, "firing"))`)
+	assert.Equal(t, []models.Alert{{Rule: "high-cost", State: "firing", Value: 120}}, alerts.Data)
+
+	RetrieveAlerts(context.Background(), All)
+	assert.NotContains(t, got, "@filter")
+}
@@ -0,0 +1,103 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	qb "github.com/vmware/purser/pkg/querybuilder"
+)
+
+// ApplicationType is the resource type of GitOps applications
+const ApplicationType = "application"
+
+// ApplicationCost is the cost of pods of an Argo CD or Flux application
+type ApplicationCost struct {
+	Name          string  `json:"name"`
+	Tool          string  `json:"tool"`
+	Pods          int     `json:"pods"`
+	CPUCost       float64 `json:"cpuCost"`
+	MemoryCost    float64 `json:"memoryCost"`
+	StorageCost   float64 `json:"storageCost"`
+	TotalCost     float64 `json:"totalCost"`
+	LastMonthCost float64 `json:"lastMonthCost"`
+}
+
+// ApplicationCostsWrapper structure
+type ApplicationCostsWrapper struct {
+	Data []ApplicationCost `json:"data"`
+}
+
+// RetrieveApplicationCosts returns month to date and last month cost of each GitOps application, applications
+// are sorted by their month to date cost, highest first
+func RetrieveApplicationCosts(ctx context.Context) ApplicationCostsWrapper {
+	type root struct {
+		Pods []struct {
+			UID             string `json:"uid"`
+			Application     string `json:"application"`
+			ApplicationTool string `json:"applicationTool"`
+		} `json:"pods"`
+	}
+	newRoot := root{}
+	err := executeQuery(ctx, getQueryForApplicationPods(), &newRoot)
+	if err != nil {
+		log.Errorf("unable to retrieve pods of applications, err: %v", err)
+		return ApplicationCostsWrapper{}
+	}
+
+	applications := make(map[string]*ApplicationCost)
+	podsOfApplications := make(map[string][]string)
+	for _, pod := range newRoot.Pods {
+		key := pod.ApplicationTool + ":" + pod.Application
+		if _, isPresent := applications[key]; !isPresent {
+			applications[key] = &ApplicationCost{Name: pod.Application, Tool: pod.ApplicationTool}
+		}
+		applications[key].Pods++
+		podsOfApplications[key] = append(podsOfApplications[key], pod.UID)
+	}
+
+	data := []ApplicationCost{}
+	for key, application := range applications {
+		metrics, err := retrieveMetricsOfPods(ctx, strings.Join(podsOfApplications[key], ", "), ApplicationType)
+		if err != nil {
+			log.Errorf("unable to retrieve metrics of application: %s, err: %v", application.Name, err)
+			continue
+		}
+		application.CPUCost = metrics.CostCPU
+		application.MemoryCost = metrics.CostMemory
+		application.StorageCost = metrics.CostStorage
+		application.TotalCost = metrics.CostCPU + metrics.CostMemory + metrics.CostStorage
+		application.LastMonthCost = metrics.LastMonthCPUCost + metrics.LastMonthMemoryCost + metrics.LastMonthStorageCost
+		data = append(data, *application)
+	}
+	sort.Slice(data, func(i, j int) bool {
+		if data[i].TotalCost != data[j].TotalCost {
+			return data[i].TotalCost > data[j].TotalCost
+		}
+		return data[i].Name < data[j].Name
+	})
+	return ApplicationCostsWrapper{Data: data}
+}
+
+func getQueryForApplicationPods() string {
+	return qb.New(
+		qb.Func("pods", qb.Has("application")).Filter(qb.Has("isPod")).Fields("uid", "application", "applicationTool"),
+	).String()
+}
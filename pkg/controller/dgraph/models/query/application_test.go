@@ -0,0 +1,49 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestRetrieveApplicationCosts ...
+func TestRetrieveApplicationCosts(t *testing.T) {
+	executeQuery = func(ctx context.Context, query string, root interface{}) error {
+		if strings.Contains(query, "has(application)") {
+			return json.Unmarshal([]byte(`{"pods": [
+				{"uid": "0x1", "application": "guestbook", "applicationTool": "argocd"},
+				{"uid": "0x2", "application": "flux-system/apps", "applicationTool": "flux"},
+				{"uid": "0x3", "application": "flux-system/apps", "applicationTool": "flux"}
+			]}`), root)
+		}
+		if strings.Contains(query, "0x1") {
+			return json.Unmarshal([]byte(`{"group": [{"cpuCost": 1}]}`), root)
+		}
+		return json.Unmarshal([]byte(`{"group": [{"cpuCost": 2}, {"storageCost": 1}, {"lastMonthMemoryCost": 5}]}`), root)
+	}
+	expected := []ApplicationCost{
+		{Name: "flux-system/apps", Tool: "flux", Pods: 2, CPUCost: 2, StorageCost: 1, TotalCost: 3, LastMonthCost: 5},
+		{Name: "guestbook", Tool: "argocd", Pods: 1, CPUCost: 1, TotalCost: 1},
+	}
+	assert.Equal(t, expected, RetrieveApplicationCosts(context.Background()).Data)
+}
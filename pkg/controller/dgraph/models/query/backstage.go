@@ -0,0 +1,307 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vmware/purser/pkg/controller/dgraph/models"
+	qb "github.com/vmware/purser/pkg/querybuilder"
+)
+
+// Constants of Backstage cost insights
+const (
+	// DefaultBackstageEntityLabel is the label of the Backstage kubernetes plugin linking pods to catalog entities
+	DefaultBackstageEntityLabel = "backstage.io/kubernetes-id"
+	// maxIntervalDays limits the days of daily costs of a request
+	maxIntervalDays = 731
+	dateLayout      = "2006-01-02"
+)
+
+var (
+	backstageMu          sync.RWMutex
+	backstageEntityLabel = DefaultBackstageEntityLabel
+
+	// intervalsRegex matches repeating intervals of Backstage(ex: R2/P30D/2020-09-01 or R2/P3M/2020-09-01)
+	intervalsRegex = regexp.MustCompile(`^R(\d+)/P(\d+)([DM])/(\d{4}-\d{2}-\d{2})$`)
+)
+
+// DateAggregation is the cost of a day(ex: 2020-09-01)
+type DateAggregation struct {
+	Date   string  `json:"date"`
+	Amount float64 `json:"amount"`
+}
+
+// ChangeStatistic is the change in cost of the last period compared to the one before, ratio is omitted if the
+// cost of the period before is 0
+type ChangeStatistic struct {
+	Ratio  *float64 `json:"ratio,omitempty"`
+	Amount float64  `json:"amount"`
+}
+
+// Trendline is the linear regression of daily costs over unix time in seconds of their dates
+type Trendline struct {
+	Slope     float64 `json:"slope"`
+	Intercept float64 `json:"intercept"`
+}
+
+// BackstageCost is the daily cost of a catalog entity in the shape of Cost of the Backstage cost insights plugin,
+// GroupedCosts has the daily cost of each value of the grouping label
+type BackstageCost struct {
+	ID           string                     `json:"id"`
+	Aggregation  []DateAggregation          `json:"aggregation"`
+	Change       ChangeStatistic            `json:"change"`
+	Trendline    Trendline                  `json:"trendline"`
+	GroupedCosts map[string][]BackstageCost `json:"groupedCosts,omitempty"`
+}
+
+// CostIntervals are Repetitions periods of Days or Months ending at End, given as ISO 8601 repeating interval
+type CostIntervals struct {
+	Repetitions int
+	Days        int
+	Months      int
+	End         time.Time
+}
+
+type backstagePod struct {
+	Name                  string  `json:"name"`
+	StartTime             string  `json:"startTime"`
+	EndTime               string  `json:"endTime"`
+	CPURequest            float64 `json:"cpuRequest"`
+	MemoryRequest         float64 `json:"memoryRequest"`
+	StorageRequest        float64 `json:"storageRequest"`
+	GPURequest            float64 `json:"gpuRequest"`
+	CPUPrice              float64 `json:"cpuPrice"`
+	MemoryPrice           float64 `json:"memoryPrice"`
+	GPUPrice              float64 `json:"gpuPrice"`
+	ExtendedResourcePrice float64 `json:"extendedResourcePrice"`
+	BandwidthPrice        float64 `json:"bandwidthPrice"`
+	Label                 []struct {
+		Value string `json:"value"`
+	} `json:"label"`
+}
+
+// ConfigureBackstage sets the label of pods whose value is the id of their Backstage catalog entity
+func ConfigureBackstage(label string) {
+	backstageMu.Lock()
+	defer backstageMu.Unlock()
+	if label != "" {
+		backstageEntityLabel = label
+	}
+	log.Infof("backstage entity label: %s", backstageEntityLabel)
+}
+
+// GetBackstageEntityID returns the id of the entity(ex: web) of an entity ref(ex: component:default/web)
+func GetBackstageEntityID(entityRef string) string {
+	entityRef = entityRef[strings.Index(entityRef, ":")+1:]
+	return entityRef[strings.LastIndex(entityRef, "/")+1:]
+}
+
+// ParseCostIntervals parses a repeating interval(ex: R2/P30D/2020-09-01) of Backstage cost insights
+func ParseCostIntervals(intervals string) (CostIntervals, error) {
+	matches := intervalsRegex.FindStringSubmatch(intervals)
+	if matches == nil {
+		return CostIntervals{}, fmt.Errorf("invalid intervals %s, expected R<repetitions>/P<duration>D or M/<end date>", intervals)
+	}
+	c := CostIntervals{}
+	c.Repetitions, _ = strconv.Atoi(matches[1])
+	duration, _ := strconv.Atoi(matches[2])
+	if matches[3] == "D" {
+		c.Days = duration
+	} else {
+		c.Months = duration
+	}
+	end, err := time.Parse(dateLayout, matches[4])
+	if err != nil {
+		return CostIntervals{}, fmt.Errorf("invalid end date of intervals %s", intervals)
+	}
+	c.End = end
+	if c.Repetitions < 1 || duration < 1 || c.End.Sub(c.Start()).Hours() > maxIntervalDays*24 {
+		return CostIntervals{}, fmt.Errorf("intervals %s should have at least one period and span at most %d days", intervals, maxIntervalDays)
+	}
+	return c, nil
+}
+
+// Start returns the start of the first period
+func (c CostIntervals) Start() time.Time {
+	return c.periodStart(c.Repetitions)
+}
+
+// periodStart returns the start of the period which is index periods before End
+func (c CostIntervals) periodStart(index int) time.Time {
+	return c.End.AddDate(0, -index*c.Months, -index*c.Days)
+}
+
+// RetrieveBackstageEntityCost returns daily costs of pods of the catalog entity(ex: component:default/web) in the
+// intervals, grouped by values of the label groupBy if it is given
+func RetrieveBackstageEntityCost(ctx context.Context, entityRef string, intervals CostIntervals, groupBy string) (BackstageCost, error) {
+	backstageMu.RLock()
+	label := backstageEntityLabel
+	backstageMu.RUnlock()
+
+	root := struct {
+		Entities []struct {
+			Pods []backstagePod `json:"pods"`
+		} `json:"entities"`
+	}{}
+	query, vars := getQueryForBackstagePods(label, GetBackstageEntityID(entityRef), intervals, groupBy)
+	if err := executeQueryWithVars(ctx, query, vars, &root); err != nil {
+		log.Errorf("unable to retrieve pods of backstage entity %s, err: %v", entityRef, err)
+		return BackstageCost{}, err
+	}
+	pods := []backstagePod{}
+	for _, entity := range root.Entities {
+		pods = append(pods, entity.Pods...)
+	}
+
+	now := time.Now()
+	cost := computeBackstageCost(entityRef, pods, intervals, now)
+	if groupBy != All {
+		groups := make(map[string][]backstagePod)
+		for _, pod := range pods {
+			value := Unlabeled
+			if len(pod.Label) > 0 {
+				value = pod.Label[0].Value
+			}
+			groups[value] = append(groups[value], pod)
+		}
+		groupCosts := []BackstageCost{}
+		for value, groupPods := range groups {
+			groupCosts = append(groupCosts, computeBackstageCost(value, groupPods, intervals, now))
+		}
+		sort.Slice(groupCosts, func(i, j int) bool {
+			return groupCosts[i].ID < groupCosts[j].ID
+		})
+		cost.GroupedCosts = map[string][]BackstageCost{groupBy: groupCosts}
+	}
+	return cost, nil
+}
+
+func getQueryForBackstagePods(label, entity string, intervals CostIntervals, groupBy string) (string, qb.Vars) {
+	vars := qb.Vars{"$label": label, "$entity": entity}
+	groupLabel := ``
+	if groupBy != All {
+		vars["$groupBy"] = groupBy
+		groupLabel = `
+				label @filter(eq(key, $groupBy)) {
+					value
+				}`
+	}
+	timeRange := TimeRange{Start: intervals.Start().Format(time.RFC3339), End: intervals.End.Format(time.RFC3339)}
+	return vars.Declaration() + ` {
+		entities(func: has(isLabel)) @filter(eq(key, $label) AND eq(value, $entity)) {
+			pods: ~label @filter(has(isPod) AND ` + getLiveFilterInRange(timeRange) + `) {
+				name
+				startTime
+				endTime
+				cpuRequest
+				memoryRequest
+				storageRequest
+				gpuRequest
+				cpuPrice
+				memoryPrice
+				gpuPrice
+				extendedResourcePrice
+				bandwidthPrice` + groupLabel + `
+			}
+		}
+	}`, vars
+}
+
+// computeBackstageCost adds the cost of each pod to the days of intervals it ran on(UTC) up to now
+func computeBackstageCost(id string, pods []backstagePod, intervals CostIntervals, now time.Time) BackstageCost {
+	start, end := intervals.Start(), intervals.End
+	amounts := make(map[string]float64)
+	for _, pod := range pods {
+		for day := start; day.Before(end) && day.Before(now); day = day.AddDate(0, 0, 1) {
+			dayEnd := day.AddDate(0, 0, 1)
+			if dayEnd.After(now) {
+				dayEnd = now
+			}
+			hours := getOverlapHours(pod.StartTime, pod.EndTime, day, dayEnd)
+			if hours == 0 {
+				continue
+			}
+			amounts[day.Format(dateLayout)] += adjustCost(CostContext{PodType, pod.Name, CPUCostType}, pod.CPURequest*pod.CPUPrice*hours) +
+				adjustCost(CostContext{PodType, pod.Name, MemoryCostType}, pod.MemoryRequest*pod.MemoryPrice*hours) +
+				adjustCost(CostContext{PodType, pod.Name, StorageCostType}, pod.StorageRequest*models.DefaultStorageCostInFloat64*hours) +
+				(pod.GPURequest*pod.GPUPrice+pod.ExtendedResourcePrice+pod.BandwidthPrice)*hours
+		}
+	}
+
+	cost := BackstageCost{ID: id, Aggregation: []DateAggregation{}}
+	for day := start; day.Before(end); day = day.AddDate(0, 0, 1) {
+		cost.Aggregation = append(cost.Aggregation, DateAggregation{Date: day.Format(dateLayout), Amount: amounts[day.Format(dateLayout)]})
+	}
+	cost.Change = computeChangeStatistic(cost.Aggregation, intervals)
+	cost.Trendline = computeTrendline(cost.Aggregation)
+	return cost
+}
+
+// computeChangeStatistic compares the cost of the last period with the cost of the period before it
+func computeChangeStatistic(aggregation []DateAggregation, intervals CostIntervals) ChangeStatistic {
+	lastStart := intervals.periodStart(1).Format(dateLayout)
+	previousStart := intervals.periodStart(2).Format(dateLayout)
+	var last, previous float64
+	for _, day := range aggregation {
+		if day.Date >= lastStart {
+			last += day.Amount
+		} else if day.Date >= previousStart {
+			previous += day.Amount
+		}
+	}
+	change := ChangeStatistic{Amount: last - previous}
+	if previous > 0 {
+		ratio := change.Amount / previous
+		change.Ratio = &ratio
+	}
+	return change
+}
+
+// computeTrendline returns the least squares line of daily amounts over unix time of their dates
+func computeTrendline(aggregation []DateAggregation) Trendline {
+	if len(aggregation) == 0 {
+		return Trendline{}
+	}
+	xs := make([]float64, len(aggregation))
+	var meanX, meanY float64
+	for i, day := range aggregation {
+		date, _ := time.Parse(dateLayout, day.Date)
+		xs[i] = float64(date.Unix())
+		meanX += xs[i] / float64(len(aggregation))
+		meanY += day.Amount / float64(len(aggregation))
+	}
+	// deviations from the means keep precision with large unix times
+	var covariance, variance float64
+	for i, day := range aggregation {
+		covariance += (xs[i] - meanX) * (day.Amount - meanY)
+		variance += (xs[i] - meanX) * (xs[i] - meanX)
+	}
+	if variance == 0 {
+		return Trendline{Intercept: meanY}
+	}
+	slope := covariance / variance
+	return Trendline{Slope: slope, Intercept: meanY - slope*meanX}
+}
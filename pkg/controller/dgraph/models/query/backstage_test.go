@@ -0,0 +1,103 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func mockDgraphForBackstagePods() {
+	executeQueryWithVars = func(ctx context.Context, query string, vars map[string]string, root interface{}) error {
+		return json.Unmarshal([]byte(`{"entities": [{"pods": [
+			{"name": "pod-web-1", "startTime": "2018-09-01T00:00:00Z", "cpuRequest": 1, "cpuPrice": 0.5, "label": [{"value": "frontend"}]},
+			{"name": "pod-web-2", "startTime": "2018-09-30T12:00:00Z", "endTime": "2018-10-01T12:00:00Z", "memoryRequest": 2, "memoryPrice": 0.25}
+		]}]}`), root)
+	}
+}
+
+// TestParseCostIntervals ...
+func TestParseCostIntervals(t *testing.T) {
+	intervals, err := ParseCostIntervals("R2/P30D/2020-09-01")
+	assert.Nil(t, err)
+	assert.Equal(t, CostIntervals{Repetitions: 2, Days: 30, End: time.Date(2020, 9, 1, 0, 0, 0, 0, time.UTC)}, intervals)
+	assert.Equal(t, time.Date(2020, 7, 3, 0, 0, 0, 0, time.UTC), intervals.Start())
+
+	intervals, err = ParseCostIntervals("R2/P3M/2020-09-01")
+	assert.Nil(t, err)
+	assert.Equal(t, time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC), intervals.Start())
+
+	for _, invalid := range []string{"", "R2/P30D", "R0/P30D/2020-09-01", "R2/P1Y/2020-09-01", "R30/P3M/2020-09-01", "R2/P30D/2020-13-01"} {
+		_, err = ParseCostIntervals(invalid)
+		assert.NotNil(t, err, invalid)
+	}
+}
+
+// TestGetBackstageEntityID ...
+func TestGetBackstageEntityID(t *testing.T) {
+	assert.Equal(t, "web", GetBackstageEntityID("component:default/web"))
+	assert.Equal(t, "web", GetBackstageEntityID("default/web"))
+	assert.Equal(t, "web", GetBackstageEntityID("web"))
+}
+
+// TestComputeBackstageCost ...
+func TestComputeBackstageCost(t *testing.T) {
+	intervals, _ := ParseCostIntervals("R2/P1D/2018-10-02")
+	pods := []backstagePod{
+		{Name: "pod-web-1", StartTime: "2018-09-01T00:00:00Z", CPURequest: 1, CPUPrice: 0.5},
+		{Name: "pod-web-2", StartTime: "2018-09-30T12:00:00Z", EndTime: "2018-10-01T12:00:00Z", MemoryRequest: 2, MemoryPrice: 0.25},
+	}
+	cost := computeBackstageCost("web", pods, intervals, time.Date(2018, 10, 1, 18, 0, 0, 0, time.UTC))
+	assert.Equal(t, "web", cost.ID)
+	assert.Equal(t, []DateAggregation{{Date: "2018-09-30", Amount: 18}, {Date: "2018-10-01", Amount: 15}}, cost.Aggregation)
+	assert.Equal(t, -3.0, cost.Change.Amount)
+	assert.InDelta(t, -1.0/6, *cost.Change.Ratio, 1e-9)
+	assert.InDelta(t, -3.0/86400, cost.Trendline.Slope, 1e-12)
+
+	cost = computeBackstageCost("web", nil, intervals, time.Now())
+	assert.Nil(t, cost.Change.Ratio)
+	assert.Equal(t, Trendline{}, cost.Trendline)
+}
+
+// TestRetrieveBackstageEntityCostGrouped ...
+func TestRetrieveBackstageEntityCostGrouped(t *testing.T) {
+	mockDgraphForBackstagePods()
+	intervals, _ := ParseCostIntervals("R2/P1D/2018-10-02")
+	cost, err := RetrieveBackstageEntityCost(context.Background(), "component:default/web", intervals, "app.kubernetes.io/component")
+	assert.Nil(t, err)
+	assert.Equal(t, "component:default/web", cost.ID)
+	groups := cost.GroupedCosts["app.kubernetes.io/component"]
+	assert.Equal(t, 2, len(groups))
+	assert.Equal(t, "frontend", groups[0].ID)
+	assert.Equal(t, Unlabeled, groups[1].ID)
+	assert.Equal(t, 12.0, groups[0].Aggregation[0].Amount)
+}
+
+// TestGetQueryForBackstagePods ...
+func TestGetQueryForBackstagePods(t *testing.T) {
+	intervals, _ := ParseCostIntervals("R2/P30D/2020-09-01")
+	query, vars := getQueryForBackstagePods(DefaultBackstageEntityLabel, "web", intervals, All)
+	assert.Equal(t, "web", vars["$entity"])
+	assert.Contains(t, query, `le(startTime, "2020-09-01T00:00:00Z")`)
+	assert.Contains(t, query, `gt(endTime, "2020-07-03T00:00:00Z")`)
+	assert.NotContains(t, query, `$groupBy`)
+}
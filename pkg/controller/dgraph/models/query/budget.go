@@ -0,0 +1,203 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+
+	"github.com/vmware/purser/pkg/controller/dgraph/models"
+	"github.com/vmware/purser/pkg/controller/utils"
+	qb "github.com/vmware/purser/pkg/querybuilder"
+)
+
+// Reasons of failures of budget checks
+const (
+	// BudgetExceeded is the failure of a namespace whose projected cost of the month exceeds its budget
+	BudgetExceeded = "BUDGET_EXCEEDED"
+	// RunRateExceeded is the failure of a namespace whose monthly cost at the current hourly cost exceeds its budget
+	RunRateExceeded = "RUN_RATE_EXCEEDED"
+	// UnplaceablePods is the failure of a manifest with pods which fit on no instance type of the cluster
+	UnplaceablePods = "UNPLACEABLE_PODS"
+)
+
+var (
+	budgetsMu sync.RWMutex
+	budgets   = map[string]float64{}
+)
+
+// BudgetFailure is a machine readable reason why a budget check failed
+type BudgetFailure struct {
+	Reason  string `json:"reason"`
+	Message string `json:"message"`
+}
+
+// BudgetCheck is the result of checking the monthly budget of a namespace for a manifest deployed in it. HourlyCost
+// is the cost of live pods of the namespace per hour. ProjectedCost is the month to date cost plus the hourly cost of
+// live pods and the manifest for the rest of the month, MonthlyRunRate is their hourly cost for a whole month.
+type BudgetCheck struct {
+	Namespace       string           `json:"namespace"`
+	Passed          bool             `json:"passed"`
+	HasBudget       bool             `json:"hasBudget"`
+	Budget          float64          `json:"budget"`
+	MonthToDateCost float64          `json:"monthToDateCost"`
+	HourlyCost      float64          `json:"hourlyCost"`
+	ManifestCost    float64          `json:"manifestCost"`
+	ProjectedCost   float64          `json:"projectedCost"`
+	MonthlyRunRate  float64          `json:"monthlyRunRate"`
+	RemainingBudget float64          `json:"remainingBudget"`
+	Failures        []BudgetFailure  `json:"failures"`
+	Estimate        ManifestEstimate `json:"estimate"`
+}
+
+// BudgetCheckWrapper structure
+type BudgetCheckWrapper struct {
+	Data BudgetCheck `json:"data"`
+}
+
+type budgetPod struct {
+	Name                  string  `json:"name"`
+	EndTime               string  `json:"endTime"`
+	CPU                   float64 `json:"cpu"`
+	Memory                float64 `json:"memory"`
+	Storage               float64 `json:"storage"`
+	GPU                   float64 `json:"gpu"`
+	CPUPrice              float64 `json:"cpuPrice"`
+	MemoryPrice           float64 `json:"memoryPrice"`
+	GPUPrice              float64 `json:"gpuPrice"`
+	ExtendedResourcePrice float64 `json:"extendedResourcePrice"`
+	BandwidthPrice        float64 `json:"bandwidthPrice"`
+	CPUCost               float64 `json:"cpuCost"`
+	MemoryCost            float64 `json:"memoryCost"`
+	StorageCost           float64 `json:"storageCost"`
+	GPUCost               float64 `json:"gpuCost"`
+	ExtendedResourceCost  float64 `json:"extendedResourceCost"`
+	BandwidthCost         float64 `json:"bandwidthCost"`
+}
+
+// ConfigureBudgets sets monthly budgets of namespaces from a comma separated list of namespace=budget(ex: dev=500),
+// the budget of namespace * applies to namespaces without a budget of their own
+func ConfigureBudgets(namespaceBudgets string) error {
+	parsed, err := utils.ParseNamespaceCosts(namespaceBudgets)
+	if err != nil {
+		return err
+	}
+	budgetsMu.Lock()
+	defer budgetsMu.Unlock()
+	budgets = parsed
+	log.Infof("namespace budgets: %v", budgets)
+	return nil
+}
+
+// GetNamespaceBudget returns the monthly budget of the namespace(ex: namespace-dev) and whether it has one
+func GetNamespaceBudget(namespace string) (float64, bool) {
+	budgetsMu.RLock()
+	defer budgetsMu.RUnlock()
+	return utils.GetNamespaceCost(budgets, strings.TrimPrefix(namespace, NamespaceType+"-"))
+}
+
+// CheckNamespaceBudget checks whether the namespace(ex: namespace-dev) stays within the monthly budget if the
+// workloads are deployed in it. The check passes if it has no budget and the cluster can run the workloads.
+func CheckNamespaceBudget(ctx context.Context, namespace string, budget float64, hasBudget bool, workloads []models.ManifestWorkload) (BudgetCheckWrapper, error) {
+	root := struct {
+		Namespace []struct {
+			Pods []budgetPod `json:"pods"`
+		} `json:"namespace"`
+	}{}
+	query, vars := getQueryForBudgetPods(namespace)
+	if err := executeQueryWithVars(ctx, query, vars, &root); err != nil {
+		log.Errorf("unable to retrieve pods of namespace %s, err: %v", namespace, err)
+		return BudgetCheckWrapper{}, err
+	}
+	pods := []budgetPod{}
+	for _, ns := range root.Namespace {
+		pods = append(pods, ns.Pods...)
+	}
+	estimate := EstimateManifestCost(ctx, workloads).Data
+	hoursRemaining := math.Max(utils.GetHoursRemainingInCurrentMonth(), 0)
+	return BudgetCheckWrapper{Data: computeBudgetCheck(namespace, budget, hasBudget, pods, estimate, hoursRemaining)}, nil
+}
+
+func getQueryForBudgetPods(namespace string) (string, qb.Vars) {
+	vars := qb.Vars{"$namespace": namespace}
+	monthRange := TimeRange{Start: utils.ConverTimeToRFC3339(utils.GetCurrentMonthStartTime())}
+	return vars.Declaration() + ` {
+		namespace(func: has(isNamespace)) @filter(eq(name, $namespace)) {
+			pods: ~namespace @filter(has(isPod) AND ` + getLiveFilterInRange(monthRange) + `) {
+				` + getQueryForMetricsComputationWithAlias("Pod") + `
+			}
+		}
+	}`, vars
+}
+
+// computeBudgetCheck adds month to date costs of pods and hourly costs of live pods of the namespace and checks
+// them with the estimate of the manifest against the budget
+func computeBudgetCheck(namespace string, budget float64, hasBudget bool, pods []budgetPod, estimate ManifestEstimate, hoursRemaining float64) BudgetCheck {
+	check := BudgetCheck{
+		Namespace:    namespace,
+		HasBudget:    hasBudget,
+		Budget:       budget,
+		ManifestCost: estimate.MonthlyCost,
+		Failures:     []BudgetFailure{},
+		Estimate:     estimate,
+	}
+	var cpuCost, memoryCost, storageCost, otherCost float64
+	for _, pod := range pods {
+		cpuCost += pod.CPUCost
+		memoryCost += pod.MemoryCost
+		storageCost += pod.StorageCost
+		otherCost += pod.GPUCost + pod.ExtendedResourceCost + pod.BandwidthCost
+		if pod.EndTime == "" {
+			check.HourlyCost += pod.CPU*pod.CPUPrice + pod.Memory*pod.MemoryPrice + pod.Storage*models.DefaultStorageCostInFloat64 +
+				pod.GPU*pod.GPUPrice + pod.ExtendedResourcePrice + pod.BandwidthPrice
+		}
+	}
+	check.MonthToDateCost = adjustCost(CostContext{NamespaceType, namespace, CPUCostType}, cpuCost) +
+		adjustCost(CostContext{NamespaceType, namespace, MemoryCostType}, memoryCost) +
+		adjustCost(CostContext{NamespaceType, namespace, StorageCostType}, storageCost) + otherCost
+	hourlyCost := check.HourlyCost + estimate.HourlyCost
+	check.ProjectedCost = check.MonthToDateCost + hourlyCost*hoursRemaining
+	check.MonthlyRunRate = hourlyCost * models.HoursInMonth
+
+	if hasBudget {
+		check.RemainingBudget = budget - check.ProjectedCost
+		if check.ProjectedCost > budget {
+			check.Failures = append(check.Failures, BudgetFailure{
+				Reason:  BudgetExceeded,
+				Message: fmt.Sprintf("projected cost %.2f of the month exceeds budget %.2f of %s", check.ProjectedCost, budget, namespace),
+			})
+		}
+		if check.MonthlyRunRate > budget {
+			check.Failures = append(check.Failures, BudgetFailure{
+				Reason:  RunRateExceeded,
+				Message: fmt.Sprintf("monthly run rate %.2f exceeds budget %.2f of %s", check.MonthlyRunRate, budget, namespace),
+			})
+		}
+	}
+	if estimate.RequiredCapacity.UnplaceablePods > 0 {
+		check.Failures = append(check.Failures, BudgetFailure{
+			Reason:  UnplaceablePods,
+			Message: fmt.Sprintf("%d pods of the manifest fit on no instance type of the cluster", estimate.RequiredCapacity.UnplaceablePods),
+		})
+	}
+	check.Passed = len(check.Failures) == 0
+	return check
+}
@@ -0,0 +1,67 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComputeBudgetCheck(t *testing.T) {
+	pods := []budgetPod{
+		{Name: "pod-web", CPU: 1, CPUPrice: 0.1, Memory: 2, MemoryPrice: 0.05, CPUCost: 30, MemoryCost: 10},
+		{Name: "pod-job", EndTime: "2018-10-02T00:00:00Z", CPU: 4, CPUPrice: 0.1, CPUCost: 20, GPUCost: 5},
+	}
+	estimate := ManifestEstimate{HourlyCost: 0.3, MonthlyCost: 216}
+
+	check := computeBudgetCheck("namespace-dev", 500, true, pods, estimate, 100)
+	assert.Equal(t, 65.0, check.MonthToDateCost)
+	assert.InDelta(t, 0.2, check.HourlyCost, 1e-9)
+	assert.InDelta(t, 115.0, check.ProjectedCost, 1e-9)
+	assert.InDelta(t, 360.0, check.MonthlyRunRate, 1e-9)
+	assert.InDelta(t, 385.0, check.RemainingBudget, 1e-9)
+	assert.True(t, check.Passed)
+	assert.Equal(t, 0, len(check.Failures))
+
+	check = computeBudgetCheck("namespace-dev", 100, true, pods, estimate, 100)
+	assert.False(t, check.Passed)
+	assert.Equal(t, BudgetExceeded, check.Failures[0].Reason)
+	assert.Equal(t, RunRateExceeded, check.Failures[1].Reason)
+}
+
+func TestComputeBudgetCheckWithoutBudget(t *testing.T) {
+	estimate := ManifestEstimate{MonthlyCost: 1000}
+	estimate.RequiredCapacity.UnplaceablePods = 2
+
+	check := computeBudgetCheck("namespace-dev", 0, false, nil, estimate, 100)
+	assert.False(t, check.Passed)
+	assert.Equal(t, []BudgetFailure{{Reason: UnplaceablePods, Message: "2 pods of the manifest fit on no instance type of the cluster"}}, check.Failures)
+}
+
+func TestGetNamespaceBudget(t *testing.T) {
+	assert.Nil(t, ConfigureBudgets("dev=100,*=1000"))
+	defer ConfigureBudgets("")
+	assert.NotNil(t, ConfigureBudgets("dev=lots"))
+
+	budget, hasBudget := GetNamespaceBudget("namespace-dev")
+	assert.True(t, hasBudget)
+	assert.Equal(t, 100.0, budget)
+	budget, _ = GetNamespaceBudget("namespace-prod")
+	assert.Equal(t, 1000.0, budget)
+}
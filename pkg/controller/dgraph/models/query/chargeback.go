@@ -0,0 +1,241 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	qb "github.com/vmware/purser/pkg/querybuilder"
+)
+
+// Unlabeled is the group of pods without the label of a chargeback report grouped by label
+const Unlabeled = "unlabeled"
+
+// Unassigned is the group of pods stored without cluster of a chargeback report grouped by cluster
+const Unassigned = "unassigned"
+
+// ChargebackCost is the cost of cpu, memory and storage requested by pods, TotalCost is their sum
+type ChargebackCost struct {
+	Pods        int     `json:"pods"`
+	CPUCost     float64 `json:"cpuCost"`
+	MemoryCost  float64 `json:"memoryCost"`
+	StorageCost float64 `json:"storageCost"`
+	TotalCost   float64 `json:"totalCost"`
+}
+
+// ChargebackRow is the cost of pods of a workload in a group, the pod itself if it has no workload. Names are without
+// type prefix(ex: web).
+type ChargebackRow struct {
+	Group     string `json:"group"`
+	Namespace string `json:"namespace"`
+	Workload  string `json:"workload"`
+	Type      string `json:"type"`
+	ChargebackCost
+}
+
+// ChargebackGroup is the cost of pods of a namespace, a value of the label, an owner workload(ex: shop/deployment/web)
+// or a cluster
+type ChargebackGroup struct {
+	Name string `json:"name"`
+	ChargebackCost
+}
+
+// ChargebackReport is the cost of pods existing between start and end grouped by namespace, label, owner or cluster,
+// groups are sorted by total cost and rows by group and total cost
+type ChargebackReport struct {
+	Start   string `json:"start"`
+	End     string `json:"end"`
+	GroupBy string `json:"groupBy"`
+	Label   string `json:"label,omitempty"`
+	ChargebackCost
+	Groups []ChargebackGroup `json:"groups"`
+	Rows   []ChargebackRow   `json:"rows"`
+}
+
+// ChargebackReportWrapper structure
+type ChargebackReportWrapper struct {
+	Data ChargebackReport `json:"data"`
+}
+
+type chargebackPod struct {
+	triggeringPod
+	Cluster     string  `json:"cluster"`
+	CPUCost     float64 `json:"cpuCost"`
+	MemoryCost  float64 `json:"memoryCost"`
+	StorageCost float64 `json:"storageCost"`
+	Label       []struct {
+		Value string `json:"value"`
+	} `json:"label"`
+}
+
+// RetrieveChargebackReport returns cost of pods per workload between start and end(month to date by default)
+// grouped by namespace, a value of the label(tenant label by default), the owner workload or the cluster
+func RetrieveChargebackReport(ctx context.Context, timeRange TimeRange, groupBy, label string) ChargebackReportWrapper {
+	if groupBy == All {
+		groupBy = Namespace
+	}
+	if groupBy != Label {
+		label = All
+	} else if label == All {
+		label = GetTenantLabel()
+	}
+	timeRange = getRangeFromMonthStart(timeRange)
+	root := struct {
+		Pods []chargebackPod `json:"pods"`
+	}{}
+	query, vars := getQueryForChargebackPods(timeRange, label)
+	err := executeQueryWithVars(ctx, query, vars, &root)
+	if err != nil {
+		log.Errorf("unable to retrieve costs of pods for chargeback, err: %v", err)
+		return ChargebackReportWrapper{}
+	}
+	report := computeChargebackReport(root.Pods, groupBy)
+	report.Start, report.End, report.Label = timeRange.Start, timeRange.End, label
+	return ChargebackReportWrapper{Data: report}
+}
+
+// getRangeFromMonthStart returns the time range ending now if it has no end and starting at the start of the
+// month of its end if it has no start
+func getRangeFromMonthStart(timeRange TimeRange) TimeRange {
+	end, err := time.Parse(time.RFC3339, timeRange.End)
+	if err != nil {
+		end = time.Now()
+		timeRange.End = end.Format(time.RFC3339)
+	}
+	if timeRange.Start == "" {
+		end = end.In(time.Local)
+		timeRange.Start = time.Date(end.Year(), end.Month(), 1, 0, 0, 0, 0, time.Local).Format(time.RFC3339)
+	}
+	return timeRange
+}
+
+func getQueryForChargebackPods(timeRange TimeRange, label string) (string, qb.Vars) {
+	vars := qb.Vars{}
+	labels := ``
+	if label != All {
+		vars["$label"] = label
+		labels = `
+			label @filter(eq(key, $label)) {
+				value
+			}`
+	}
+	owners := ``
+	for _, ownerType := range podOwnerPredicates {
+		owners += `
+			` + ownerType + ` {
+				name
+			}`
+	}
+	return vars.Declaration() + ` {
+		pods(func: has(isPod)) @filter(` + getLiveFilterInRange(timeRange) + `) {
+			` + getQueryForMetricsComputationWithAliasInRange("Chargeback", timeRange) + `
+			cluster
+			namespace {
+				name
+			}` + owners + labels + `
+		}
+	}`, vars
+}
+
+// computeChargebackReport sums adjusted costs of pods per group and workload
+func computeChargebackReport(pods []chargebackPod, groupBy string) ChargebackReport {
+	report := ChargebackReport{GroupBy: groupBy, Groups: []ChargebackGroup{}, Rows: []ChargebackRow{}}
+	groups := make(map[string]*ChargebackGroup)
+	rows := make(map[string]*ChargebackRow)
+	for _, pod := range pods {
+		name, workloadType, namespace := getPodOwner(pod.triggeringPod)
+		name = strings.TrimPrefix(name, workloadType+"-")
+		namespace = strings.TrimPrefix(namespace, NamespaceType+"-")
+		group := getChargebackGroup(pod, groupBy, name, workloadType, namespace)
+		if _, isPresent := groups[group]; !isPresent {
+			groups[group] = &ChargebackGroup{Name: group}
+		}
+		key := group + "/" + namespace + "/" + workloadType + "/" + name
+		if _, isPresent := rows[key]; !isPresent {
+			rows[key] = &ChargebackRow{Group: group, Namespace: namespace, Workload: name, Type: workloadType}
+		}
+
+		cost := ChargebackCost{
+			Pods:        1,
+			CPUCost:     adjustCost(CostContext{PodType, pod.Name, CPUCostType}, pod.CPUCost),
+			MemoryCost:  adjustCost(CostContext{PodType, pod.Name, MemoryCostType}, pod.MemoryCost),
+			StorageCost: adjustCost(CostContext{PodType, pod.Name, StorageCostType}, pod.StorageCost),
+		}
+		for _, total := range []*ChargebackCost{&report.ChargebackCost, &groups[group].ChargebackCost, &rows[key].ChargebackCost} {
+			total.add(cost)
+		}
+	}
+
+	for _, group := range groups {
+		report.Groups = append(report.Groups, *group)
+	}
+	sort.SliceStable(report.Groups, func(i, j int) bool {
+		if report.Groups[i].TotalCost != report.Groups[j].TotalCost {
+			return report.Groups[i].TotalCost > report.Groups[j].TotalCost
+		}
+		return report.Groups[i].Name < report.Groups[j].Name
+	})
+	groupOrder := make(map[string]int)
+	for i, group := range report.Groups {
+		groupOrder[group.Name] = i
+	}
+	for _, row := range rows {
+		report.Rows = append(report.Rows, *row)
+	}
+	sort.SliceStable(report.Rows, func(i, j int) bool {
+		first, second := report.Rows[i], report.Rows[j]
+		if first.Group != second.Group {
+			return groupOrder[first.Group] < groupOrder[second.Group]
+		}
+		if first.TotalCost != second.TotalCost {
+			return first.TotalCost > second.TotalCost
+		}
+		return first.Namespace+"/"+first.Workload < second.Namespace+"/"+second.Workload
+	})
+	return report
+}
+
+// getChargebackGroup returns the namespace of the pod, the value of its label, its owner workload or its cluster
+func getChargebackGroup(pod chargebackPod, groupBy, owner, ownerType, namespace string) string {
+	switch groupBy {
+	case Label:
+		if len(pod.Label) > 0 {
+			return pod.Label[0].Value
+		}
+		return Unlabeled
+	case Owner:
+		return namespace + "/" + ownerType + "/" + owner
+	case Cluster:
+		if pod.Cluster != "" {
+			return pod.Cluster
+		}
+		return Unassigned
+	}
+	return namespace
+}
+
+func (c *ChargebackCost) add(cost ChargebackCost) {
+	c.Pods += cost.Pods
+	c.CPUCost += cost.CPUCost
+	c.MemoryCost += cost.MemoryCost
+	c.StorageCost += cost.StorageCost
+	c.TotalCost += cost.CPUCost + cost.MemoryCost + cost.StorageCost
+}
@@ -0,0 +1,108 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func mockDgraphForChargebackPods() {
+	executeQueryWithVars = func(ctx context.Context, query string, vars map[string]string, root interface{}) error {
+		return json.Unmarshal([]byte(`{"pods": [
+			{"name": "pod-web-1", "cluster": "production", "cpuCost": 4, "memoryCost": 1, "storageCost": 1, "label": [{"value": "checkout"}],
+				"namespace": {"name": "namespace-shop"}, "replicaset": {"name": "replicaset-web-5d8f"}, "deployment": {"name": "deployment-web"}},
+			{"name": "pod-web-2", "cluster": "production", "cpuCost": 4, "memoryCost": 1, "label": [{"value": "checkout"}],
+				"namespace": {"name": "namespace-shop"}, "replicaset": {"name": "replicaset-web-5d8f"}, "deployment": {"name": "deployment-web"}},
+			{"name": "pod-db-0", "cpuCost": 2, "storageCost": 3, "namespace": {"name": "namespace-shop"}, "statefulset": {"name": "statefulset-db"}},
+			{"name": "pod-debug", "cluster": "staging", "cpuCost": 3, "label": [{"value": "checkout"}], "namespace": {"name": "namespace-etl"}}
+		]}`), root)
+	}
+}
+
+// TestRetrieveChargebackReportByNamespace ...
+func TestRetrieveChargebackReportByNamespace(t *testing.T) {
+	mockDgraphForChargebackPods()
+	got := RetrieveChargebackReport(context.Background(), TimeRange{Start: "2018-10-01T00:00:00Z", End: "2018-11-01T00:00:00Z"}, All, "tenant").Data
+	assert.Equal(t, "2018-10-01T00:00:00Z", got.Start)
+	assert.Equal(t, Namespace, got.GroupBy)
+	assert.Equal(t, All, got.Label)
+	assert.Equal(t, ChargebackCost{Pods: 4, CPUCost: 13, MemoryCost: 2, StorageCost: 4, TotalCost: 19}, got.ChargebackCost)
+	assert.Equal(t, []ChargebackGroup{
+		{Name: "shop", ChargebackCost: ChargebackCost{Pods: 3, CPUCost: 10, MemoryCost: 2, StorageCost: 4, TotalCost: 16}},
+		{Name: "etl", ChargebackCost: ChargebackCost{Pods: 1, CPUCost: 3, TotalCost: 3}},
+	}, got.Groups)
+	assert.Equal(t, []ChargebackRow{
+		{Group: "shop", Namespace: "shop", Workload: "web", Type: DeploymentType,
+			ChargebackCost: ChargebackCost{Pods: 2, CPUCost: 8, MemoryCost: 2, StorageCost: 1, TotalCost: 11}},
+		{Group: "shop", Namespace: "shop", Workload: "db", Type: StatefulsetType,
+			ChargebackCost: ChargebackCost{Pods: 1, CPUCost: 2, StorageCost: 3, TotalCost: 5}},
+		{Group: "etl", Namespace: "etl", Workload: "debug", Type: PodType,
+			ChargebackCost: ChargebackCost{Pods: 1, CPUCost: 3, TotalCost: 3}},
+	}, got.Rows)
+}
+
+// TestRetrieveChargebackReportByLabel ...
+func TestRetrieveChargebackReportByLabel(t *testing.T) {
+	mockDgraphForChargebackPods()
+	got := RetrieveChargebackReport(context.Background(), TimeRange{}, Label, "team").Data
+	assert.Equal(t, "team", got.Label)
+	assert.Equal(t, []ChargebackGroup{
+		{Name: "checkout", ChargebackCost: ChargebackCost{Pods: 3, CPUCost: 11, MemoryCost: 2, StorageCost: 1, TotalCost: 14}},
+		{Name: Unlabeled, ChargebackCost: ChargebackCost{Pods: 1, CPUCost: 2, StorageCost: 3, TotalCost: 5}},
+	}, got.Groups)
+	assert.Equal(t, 3, len(got.Rows))
+	assert.Equal(t, "debug", got.Rows[1].Workload)
+}
+
+// TestRetrieveChargebackReportByOwner ...
+func TestRetrieveChargebackReportByOwner(t *testing.T) {
+	mockDgraphForChargebackPods()
+	got := RetrieveChargebackReport(context.Background(), TimeRange{}, Owner, All).Data
+	assert.Equal(t, 3, len(got.Groups))
+	assert.Equal(t, "shop/deployment/web", got.Groups[0].Name)
+	assert.Equal(t, "shop/deployment/web", got.Rows[0].Group)
+}
+
+// TestRetrieveChargebackReportByCluster ...
+func TestRetrieveChargebackReportByCluster(t *testing.T) {
+	mockDgraphForChargebackPods()
+	got := RetrieveChargebackReport(context.Background(), TimeRange{}, Cluster, All).Data
+	assert.Equal(t, []ChargebackGroup{
+		{Name: "production", ChargebackCost: ChargebackCost{Pods: 2, CPUCost: 8, MemoryCost: 2, StorageCost: 1, TotalCost: 11}},
+		{Name: Unassigned, ChargebackCost: ChargebackCost{Pods: 1, CPUCost: 2, StorageCost: 3, TotalCost: 5}},
+		{Name: "staging", ChargebackCost: ChargebackCost{Pods: 1, CPUCost: 3, TotalCost: 3}},
+	}, got.Groups)
+	assert.Equal(t, "production", got.Rows[0].Group)
+}
+
+// TestGetQueryForChargebackPods ...
+func TestGetQueryForChargebackPods(t *testing.T) {
+	query, vars := getQueryForChargebackPods(TimeRange{Start: "2018-10-01T00:00:00Z", End: "2018-10-31T00:00:00Z"}, "team")
+	assert.Equal(t, "team", vars["$label"])
+	assert.Contains(t, query, `le(startTime, "2018-10-31T00:00:00Z")`)
+	assert.Contains(t, query, `gt(endTime, "2018-10-01T00:00:00Z")`)
+	assert.Contains(t, query, `label @filter(eq(key, $label))`)
+
+	query, vars = getQueryForChargebackPods(TimeRange{Start: "2018-10-01T00:00:00Z"}, All)
+	assert.Equal(t, 0, len(vars))
+	assert.NotContains(t, query, `label`)
+}
@@ -0,0 +1,240 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"context"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/vmware/purser/pkg/controller/dgraph/models"
+	qb "github.com/vmware/purser/pkg/querybuilder"
+)
+
+const (
+	// defaultChurnDays is the length of churn analysis window if no start is given
+	defaultChurnDays = 30
+	// mostlyIdleUtilization is the fraction of node capacity cost allocated to pods below which a node is mostly idle
+	mostlyIdleUtilization = 0.5
+)
+
+// NodeIdleCapacity is the capacity of a node during a churn window and how much of it was allocated to pods
+type NodeIdleCapacity struct {
+	Name         string  `json:"name"`
+	InstanceType string  `json:"instanceType"`
+	Hours        float64 `json:"hours"`
+	Utilization  float64 `json:"utilization"`
+	CapacityCost float64 `json:"capacityCost"`
+	IdleCost     float64 `json:"idleCost"`
+}
+
+// NodeChurn is the scale up/down activity of nodes between start and end and the cost of their idle capacity.
+// AverageLifetimeHours is computed from nodes removed in the window. MostlyIdleNodes are sorted by idle cost.
+type NodeChurn struct {
+	Start                string             `json:"start"`
+	End                  string             `json:"end"`
+	ScaleUps             int                `json:"scaleUps"`
+	ScaleDowns           int                `json:"scaleDowns"`
+	ScaleUpsPerDay       float64            `json:"scaleUpsPerDay"`
+	ScaleDownsPerDay     float64            `json:"scaleDownsPerDay"`
+	AverageLifetimeHours float64            `json:"averageLifetimeHours"`
+	CapacityCost         float64            `json:"capacityCost"`
+	AllocatedCost        float64            `json:"allocatedCost"`
+	IdleCost             float64            `json:"idleCost"`
+	MostlyIdleCost       float64            `json:"mostlyIdleCost"`
+	MostlyIdleNodes      []NodeIdleCapacity `json:"mostlyIdleNodes"`
+}
+
+// NodeChurnWrapper structure
+type NodeChurnWrapper struct {
+	Data NodeChurn `json:"data"`
+}
+
+type nodeEvent struct {
+	NodeName      string `json:"nodeName"`
+	Event         string `json:"event"`
+	StartTime     string `json:"startTime"`
+	NodeStartTime string `json:"nodeStartTime"`
+}
+
+type nodeCapacity struct {
+	Name           string        `json:"name"`
+	InstanceType   string        `json:"instanceType"`
+	StartTime      string        `json:"startTime"`
+	EndTime        string        `json:"endTime"`
+	CPUCapacity    float64       `json:"cpuCapacity"`
+	MemoryCapacity float64       `json:"memoryCapacity"`
+	CPUPrice       float64       `json:"cpuPrice"`
+	MemoryPrice    float64       `json:"memoryPrice"`
+	Pods           []podInWindow `json:"pods"`
+}
+
+type podInWindow struct {
+	StartTime     string  `json:"startTime"`
+	EndTime       string  `json:"endTime"`
+	CPURequest    float64 `json:"cpuRequest"`
+	MemoryRequest float64 `json:"memoryRequest"`
+}
+
+// RetrieveNodeChurn returns node churn between start and end(RFC3339), end defaults to now
+// and start to 30 days before end.
+func RetrieveNodeChurn(ctx context.Context, start, end string) NodeChurnWrapper {
+	endTime := time.Now().UTC()
+	if end != "" {
+		parsed, err := time.Parse(time.RFC3339, end)
+		if err != nil {
+			log.Errorf("invalid end time: %s, err: %v", end, err)
+			return NodeChurnWrapper{}
+		}
+		endTime = parsed
+	}
+	startTime := endTime.AddDate(0, 0, -defaultChurnDays)
+	if start != "" {
+		parsed, err := time.Parse(time.RFC3339, start)
+		if err != nil {
+			log.Errorf("invalid start time: %s, err: %v", start, err)
+			return NodeChurnWrapper{}
+		}
+		startTime = parsed
+	}
+
+	root := struct {
+		Events []nodeEvent    `json:"events"`
+		Nodes  []nodeCapacity `json:"nodes"`
+	}{}
+	err := executeQuery(ctx, getQueryForNodeChurn(startTime.Format(time.RFC3339), endTime.Format(time.RFC3339)), &root)
+	if err != nil {
+		log.Errorf("unable to retrieve node churn, err: %v", err)
+		return NodeChurnWrapper{}
+	}
+	return NodeChurnWrapper{Data: computeNodeChurn(root.Events, root.Nodes, startTime, endTime)}
+}
+
+func computeNodeChurn(events []nodeEvent, nodes []nodeCapacity, startTime, endTime time.Time) NodeChurn {
+	churn := NodeChurn{
+		Start:           startTime.Format(time.RFC3339),
+		End:             endTime.Format(time.RFC3339),
+		MostlyIdleNodes: []NodeIdleCapacity{},
+	}
+
+	lifetimeHours := 0.0
+	for _, event := range events {
+		switch event.Event {
+		case models.NodeAdded:
+			churn.ScaleUps++
+		case models.NodeRemoved:
+			churn.ScaleDowns++
+			lifetimeHours += getHoursBetween(event.NodeStartTime, event.StartTime)
+		}
+	}
+	if days := endTime.Sub(startTime).Hours() / 24; days > 0 {
+		churn.ScaleUpsPerDay = float64(churn.ScaleUps) / days
+		churn.ScaleDownsPerDay = float64(churn.ScaleDowns) / days
+	}
+	if churn.ScaleDowns > 0 {
+		churn.AverageLifetimeHours = lifetimeHours / float64(churn.ScaleDowns)
+	}
+
+	for _, node := range nodes {
+		capacity := computeNodeIdleCapacity(node, startTime, endTime)
+		if capacity.CapacityCost == 0 {
+			continue
+		}
+		churn.CapacityCost += capacity.CapacityCost
+		churn.AllocatedCost += capacity.CapacityCost - capacity.IdleCost
+		churn.IdleCost += capacity.IdleCost
+		if capacity.Utilization < mostlyIdleUtilization {
+			churn.MostlyIdleCost += capacity.IdleCost
+			churn.MostlyIdleNodes = append(churn.MostlyIdleNodes, capacity)
+		}
+	}
+	sort.Slice(churn.MostlyIdleNodes, func(i, j int) bool {
+		return churn.MostlyIdleNodes[i].IdleCost > churn.MostlyIdleNodes[j].IdleCost
+	})
+	return churn
+}
+
+// computeNodeIdleCapacity computes cost of node capacity and of requests of its pods while they overlap the window
+func computeNodeIdleCapacity(node nodeCapacity, startTime, endTime time.Time) NodeIdleCapacity {
+	hours := getOverlapHours(node.StartTime, node.EndTime, startTime, endTime)
+	capacity := NodeIdleCapacity{
+		Name:         node.Name,
+		InstanceType: node.InstanceType,
+		Hours:        hours,
+		CapacityCost: (node.CPUCapacity*node.CPUPrice + node.MemoryCapacity*node.MemoryPrice) * hours,
+	}
+	if capacity.CapacityCost == 0 {
+		return capacity
+	}
+
+	allocatedCost := 0.0
+	for _, pod := range node.Pods {
+		podHours := getOverlapHours(pod.StartTime, pod.EndTime, startTime, endTime)
+		allocatedCost += (pod.CPURequest*node.CPUPrice + pod.MemoryRequest*node.MemoryPrice) * podHours
+	}
+	allocatedCost = math.Min(allocatedCost, capacity.CapacityCost)
+	capacity.Utilization = allocatedCost / capacity.CapacityCost
+	capacity.IdleCost = capacity.CapacityCost - allocatedCost
+	return capacity
+}
+
+// getOverlapHours returns hours a resource with given start and end times (RFC3339, empty end if it is live)
+// existed between startTime and endTime
+func getOverlapHours(start, end string, startTime, endTime time.Time) float64 {
+	resourceStart, err := time.Parse(time.RFC3339, start)
+	if err != nil {
+		return 0
+	}
+	resourceEnd := endTime
+	if end != "" {
+		if parsed, err := time.Parse(time.RFC3339, end); err == nil && parsed.Before(endTime) {
+			resourceEnd = parsed
+		}
+	}
+	if resourceStart.Before(startTime) {
+		resourceStart = startTime
+	}
+	return math.Max(resourceEnd.Sub(resourceStart).Hours(), 0)
+}
+
+func getHoursBetween(start, end string) float64 {
+	startTime, err := time.Parse(time.RFC3339, start)
+	if err != nil {
+		return 0
+	}
+	endTime, err := time.Parse(time.RFC3339, end)
+	if err != nil {
+		return 0
+	}
+	return math.Max(endTime.Sub(startTime).Hours(), 0)
+}
+
+func getQueryForNodeChurn(start, end string) string {
+	overlapsWindow := qb.And(qb.Le("startTime", end), qb.Or(qb.Not(qb.Has("endTime")), qb.Gt("endTime", start)))
+	return qb.New(
+		qb.Func("events", qb.Has(models.IsNodeEvent)).
+			Filter(qb.And(qb.Ge("startTime", start), qb.Le("startTime", end))).
+			Fields("nodeName", "event", "startTime", "nodeStartTime"),
+		qb.Func("nodes", qb.Has(NodeCheck)).
+			Filter(qb.And(qb.Not(qb.Has("isVirtual")), overlapsWindow)).
+			Fields("name", "instanceType", "startTime", "endTime", "cpuCapacity", "memoryCapacity", "cpuPrice", "memoryPrice").
+			Child(qb.Edge("~node").Alias("pods").Filter(qb.And(qb.Has("isPod"), overlapsWindow)).
+				Fields("startTime", "endTime", "cpuRequest", "memoryRequest")),
+	).String()
+}
@@ -0,0 +1,74 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func mockDgraphForNodeChurn() {
+	executeQuery = func(ctx context.Context, query string, root interface{}) error {
+		return json.Unmarshal([]byte(`{
+			"events": [
+				{"nodeName": "node-a", "event": "added", "startTime": "2018-10-02T00:00:00Z", "nodeStartTime": "2018-10-02T00:00:00Z"},
+				{"nodeName": "node-a", "event": "removed", "startTime": "2018-10-03T00:00:00Z", "nodeStartTime": "2018-10-02T00:00:00Z"},
+				{"nodeName": "node-b", "event": "added", "startTime": "2018-10-05T00:00:00Z", "nodeStartTime": "2018-10-05T00:00:00Z"}
+			],
+			"nodes": [
+				{"name": "node-a*2018-10-03T00:00:00Z", "instanceType": "m5.large", "startTime": "2018-10-02T00:00:00Z", "endTime": "2018-10-03T00:00:00Z",
+				 "cpuCapacity": 2, "memoryCapacity": 8, "cpuPrice": 0.5, "memoryPrice": 0.125,
+				 "pods": [{"startTime": "2018-10-02T00:00:00Z", "endTime": "2018-10-02T12:00:00Z", "cpuRequest": 1, "memoryRequest": 4}]},
+				{"name": "node-c", "instanceType": "m5.large", "startTime": "2018-09-01T00:00:00Z",
+				 "cpuCapacity": 2, "memoryCapacity": 8, "cpuPrice": 0.5, "memoryPrice": 0.125,
+				 "pods": [{"startTime": "2018-09-01T00:00:00Z", "cpuRequest": 2, "memoryRequest": 8}]}
+			]
+		}`), root)
+	}
+}
+
+// TestRetrieveNodeChurn ...
+func TestRetrieveNodeChurn(t *testing.T) {
+	mockDgraphForNodeChurn()
+	churn := RetrieveNodeChurn(context.Background(), "2018-10-01T00:00:00Z", "2018-10-11T00:00:00Z").Data
+
+	assert.Equal(t, 2, churn.ScaleUps)
+	assert.Equal(t, 1, churn.ScaleDowns)
+	assert.InDelta(t, 0.2, churn.ScaleUpsPerDay, 1e-9)
+	assert.InDelta(t, 24.0, churn.AverageLifetimeHours, 1e-9)
+
+	// node-a: capacity 2$/h for 24h, pods allocate 1$/h for 12h
+	// node-c: capacity 2$/h for 240h, fully allocated
+	assert.InDelta(t, 48.0+480, churn.CapacityCost, 1e-9)
+	assert.InDelta(t, 36.0, churn.IdleCost, 1e-9)
+	assert.InDelta(t, 36.0, churn.MostlyIdleCost, 1e-9)
+	assert.Equal(t, 1, len(churn.MostlyIdleNodes))
+	assert.Equal(t, "node-a*2018-10-03T00:00:00Z", churn.MostlyIdleNodes[0].Name)
+	assert.InDelta(t, 0.25, churn.MostlyIdleNodes[0].Utilization, 1e-9)
+}
+
+// TestGetQueryForNodeChurn ...
+func TestGetQueryForNodeChurn(t *testing.T) {
+	query := getQueryForNodeChurn("2018-10-01T00:00:00Z", "2018-10-11T00:00:00Z")
+	assert.True(t, strings.Contains(query, `events(func: has(isNodeEvent)) @filter(ge(startTime, "2018-10-01T00:00:00Z") AND le(startTime, "2018-10-11T00:00:00Z"))`))
+	assert.True(t, strings.Contains(query, `pods: ~node @filter(has(isPod) AND le(startTime, "2018-10-11T00:00:00Z") AND (NOT has(endTime) OR gt(endTime, "2018-10-01T00:00:00Z")))`))
+}
@@ -18,34 +18,51 @@
 package query
 
 import (
-	"github.com/Sirupsen/logrus"
+	"context"
 	"github.com/vmware/purser/pkg/controller/dgraph"
+	"github.com/vmware/purser/pkg/logging"
 )
 
-var executeQuery = dgraph.ExecuteQuery
-var executeQueryRaw = dgraph.ExecuteQueryRaw
+var log = logging.Component("query")
+
+var executeQuery = dgraph.ExecuteReadQuery
+var executeQueryRaw = dgraph.ExecuteReadQueryRaw
+var executeQueryWithVars = dgraph.ExecuteReadQueryWithVars
+var executeQueryRawWithVars = dgraph.ExecuteReadQueryRawWithVars
 
 var allocatedAndCapacity *ParentWrapper
 
-func getClusterHierarchyQuery(view string) string {
+func getClusterHierarchyQuery(view, asOf, deleted string) string {
 	switch view {
 	case Physical:
-		return getHierarchyQueryForPhysicalResource()
+		return getHierarchyQueryForPhysicalResource(asOf)
 	case Logical:
-		return getHierarchyQueryForLogicalResource()
+		return getHierarchyQueryForLogicalResource(asOf, deleted)
 	default:
 		return ""
 	}
 }
 
 // RetrieveClusterHierarchy returns all namespaces if view is logical and returns all nodes with disks if view is physical
-func RetrieveClusterHierarchy(view string) JSONDataWrapper {
-	query := getClusterHierarchyQuery(view)
+func RetrieveClusterHierarchy(ctx context.Context, view string) JSONDataWrapper {
+	return RetrieveClusterHierarchyAsOf(ctx, view, All)
+}
+
+// RetrieveClusterHierarchyAsOf returns cluster hierarchy in the given view with resources which existed at asOf(RFC3339).
+// Empty asOf includes all resources.
+func RetrieveClusterHierarchyAsOf(ctx context.Context, view, asOf string) JSONDataWrapper {
+	return RetrieveClusterHierarchyWithDeleted(ctx, view, asOf, Include)
+}
+
+// RetrieveClusterHierarchyWithDeleted returns cluster hierarchy like RetrieveClusterHierarchyAsOf, in logical view
+// deleted namespaces are included, excluded or only retrieved if deleted is include, exclude or only.
+func RetrieveClusterHierarchyWithDeleted(ctx context.Context, view, asOf, deleted string) JSONDataWrapper {
+	query := getClusterHierarchyQuery(view, asOf, deleted)
 
 	parentRoot := ParentWrapper{}
-	err := executeQuery(query, &parentRoot)
+	err := executeQuery(ctx, query, &parentRoot)
 	if err != nil {
-		logrus.Errorf("Unable to execute query for retrieving cluster hierarchy: (%v)", err)
+		log.Errorf("Unable to execute query for retrieving cluster hierarchy: (%v)", err)
 		return JSONDataWrapper{}
 	}
 	root := JSONDataWrapper{
@@ -55,16 +72,16 @@ func RetrieveClusterHierarchy(view string) JSONDataWrapper {
 			Children: parentRoot.Children,
 		},
 	}
-	logrus.Debugf("data: (%v)", root.Data)
+	log.Debugf("data: (%v)", root.Data)
 	return root
 }
 
-func getClusterMetricsQuery(view string) string {
+func getClusterMetricsQuery(view, os string, timeRange TimeRange, deleted string) string {
 	switch view {
 	case Physical:
-		return getMetricsQueryForPhysicalResources()
+		return getMetricsQueryForPhysicalResources(os, timeRange)
 	case Logical:
-		return getMetricsQueryForLogicalResources()
+		return getMetricsQueryForLogicalResources(os, timeRange, deleted)
 	default:
 		return ""
 	}
@@ -72,29 +89,58 @@ func getClusterMetricsQuery(view string) string {
 
 // RetrieveClusterMetrics returns all namespaces with metrics if view is logical and
 // returns all nodes and disks with metrics if view is physical
-func RetrieveClusterMetrics(view string) JSONDataWrapper {
-	query := getClusterMetricsQuery(view)
+func RetrieveClusterMetrics(ctx context.Context, view string) JSONDataWrapper {
+	return RetrieveClusterMetricsForOS(ctx, view, All)
+}
+
+// RetrieveClusterMetricsForOS returns cluster metrics in the given view considering only pods (logical view)
+// or nodes (physical view) running the given os. Empty os includes all resources.
+func RetrieveClusterMetricsForOS(ctx context.Context, view, os string) JSONDataWrapper {
+	return RetrieveClusterMetricsAsOf(ctx, view, os, All)
+}
+
+// RetrieveClusterMetricsAsOf returns cluster metrics in the given view with resources which existed at asOf(RFC3339)
+// and their costs from start of the month of asOf until asOf. Empty asOf returns live resources with costs until now.
+func RetrieveClusterMetricsAsOf(ctx context.Context, view, os, asOf string) JSONDataWrapper {
+	return RetrieveClusterMetricsWithDeleted(ctx, view, os, asOf, Include)
+}
+
+// RetrieveClusterMetricsWithDeleted returns cluster metrics like RetrieveClusterMetricsAsOf, in logical view
+// deleted namespaces are included, excluded or only retrieved if deleted is include, exclude or only.
+func RetrieveClusterMetricsWithDeleted(ctx context.Context, view, os, asOf, deleted string) JSONDataWrapper {
+	return RetrieveClusterMetricsInRange(ctx, view, os, TimeRange{End: asOf}, deleted)
+}
+
+// RetrieveClusterMetricsInRange returns cluster metrics like RetrieveClusterMetricsWithDeleted with resources which
+// existed in the time range and their costs within it.
+func RetrieveClusterMetricsInRange(ctx context.Context, view, os string, timeRange TimeRange, deleted string) JSONDataWrapper {
+	query := getClusterMetricsQuery(view, os, timeRange, deleted)
 	parentRoot := ParentWrapper{}
-	err := executeQuery(query, &parentRoot)
+	err := executeQuery(ctx, query, &parentRoot)
 	calculateAggregateMetrics(&parentRoot)
 	if err != nil {
-		logrus.Errorf("Unable to execute query for retrieving cluster metrics: (%v)", err)
+		log.Errorf("Unable to execute query for retrieving cluster metrics: (%v)", err)
 		return JSONDataWrapper{}
 	}
 	root := JSONDataWrapper{
 		Data: ParentWrapper{
-			Name:        "cluster",
-			Type:        "cluster",
-			Children:    parentRoot.Children,
-			CPU:         parentRoot.CPU,
-			Memory:      parentRoot.Memory,
-			Storage:     parentRoot.Storage,
-			CPUCost:     parentRoot.CPUCost,
-			MemoryCost:  parentRoot.MemoryCost,
-			StorageCost: parentRoot.StorageCost,
+			Name:                 "cluster",
+			Type:                 "cluster",
+			Children:             parentRoot.Children,
+			CPU:                  parentRoot.CPU,
+			Memory:               parentRoot.Memory,
+			Storage:              parentRoot.Storage,
+			CPUCost:              parentRoot.CPUCost,
+			MemoryCost:           parentRoot.MemoryCost,
+			StorageCost:          parentRoot.StorageCost,
+			GPU:                  parentRoot.GPU,
+			GPUCost:              parentRoot.GPUCost,
+			ExtendedResourceCost: parentRoot.ExtendedResourceCost,
+			BandwidthCost:        parentRoot.BandwidthCost,
 		},
 	}
-	logrus.Debugf("data: (%v)", root.Data)
+	adjustCosts(&root.Data)
+	log.Debugf("data: (%v)", root.Data)
 	return root
 }
 
@@ -106,21 +152,25 @@ func calculateAggregateMetrics(objRoot *ParentWrapper) {
 		objRoot.CPUCost += obj.CPUCost
 		objRoot.MemoryCost += obj.MemoryCost
 		objRoot.StorageCost += obj.StorageCost
+		objRoot.GPU += obj.GPU
+		objRoot.GPUCost += obj.GPUCost
+		objRoot.ExtendedResourceCost += obj.ExtendedResourceCost
+		objRoot.BandwidthCost += obj.BandwidthCost
 	}
 }
 
 // PopulateClusterAllocationAndCapacity ...
-func PopulateClusterAllocationAndCapacity(jsonData *JSONDataWrapper) {
+func PopulateClusterAllocationAndCapacity(ctx context.Context, jsonData *JSONDataWrapper) {
 	if allocatedAndCapacity == nil {
-		ComputeClusterAllocationAndCapacity()
+		ComputeClusterAllocationAndCapacity(ctx)
 	}
 	populateCapacityData(*allocatedAndCapacity, jsonData)
 }
 
 // ComputeClusterAllocationAndCapacity returns allocated, capacity for cpu, memory and storage
-func ComputeClusterAllocationAndCapacity() {
-	allocation := RetrieveClusterMetrics(Logical)
-	capacity := RetrieveClusterMetrics(Physical)
+func ComputeClusterAllocationAndCapacity(ctx context.Context) {
+	allocation := RetrieveClusterMetrics(ctx, Logical)
+	capacity := RetrieveClusterMetrics(ctx, Physical)
 	allocatedAndCapacity = &ParentWrapper{
 		CPUAllocated:     allocation.Data.CPU,
 		MemoryAllocated:  allocation.Data.Memory,
@@ -132,9 +182,9 @@ func ComputeClusterAllocationAndCapacity() {
 }
 
 // PopulateNodeOrPVAllocationAndCapacity returns allocated, capacity for cpu, memory and storage
-func (r *Resource) PopulateNodeOrPVAllocationAndCapacity(jsonData *JSONDataWrapper) {
-	q := r.getQueryForResourceMetrics()
-	resourceData := getJSONDataFromQuery(q)
+func (r *Resource) PopulateNodeOrPVAllocationAndCapacity(ctx context.Context, jsonData *JSONDataWrapper) {
+	q, vars := r.getQueryForResourceMetrics(ctx)
+	resourceData := getJSONDataFromQuery(ctx, q, vars)
 	populateCapacityData(resourceData.Data, jsonData)
 }
 
@@ -18,6 +18,7 @@
 package query
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"testing"
@@ -35,8 +36,10 @@ func mockSecondsSinceMonthStart() {
 
 func removeMocks() {
 	secondsFromFirstOfCurrentMonth = getSecondsSinceMonthStart
-	executeQuery = dgraph.ExecuteQuery
-	executeQueryRaw = dgraph.ExecuteQueryRaw
+	executeQuery = dgraph.ExecuteReadQuery
+	executeQueryRaw = dgraph.ExecuteReadQueryRaw
+	executeQueryWithVars = dgraph.ExecuteReadQueryWithVars
+	executeQueryRawWithVars = dgraph.ExecuteReadQueryRawWithVars
 }
 
 // TestMain ...
@@ -48,7 +51,7 @@ func TestMain(m *testing.M) {
 }
 
 func mockDgraphForClusterQueries(queryType string) {
-	executeQuery = func(query string, root interface{}) error {
+	executeQuery = func(ctx context.Context, query string, root interface{}) error {
 		if query == "" {
 			return fmt.Errorf("unable to connect/retrieve data from dgraph")
 		}
@@ -103,12 +106,15 @@ func mockDgraphForClusterQueries(queryType string) {
 		dummyParentWrapper.Children = children
 		return nil
 	}
+	executeQueryWithVars = func(ctx context.Context, query string, vars map[string]string, root interface{}) error {
+		return executeQuery(ctx, query, root)
+	}
 }
 
 // TestRetrieveClusterHierarchyNoView ...
 func TestRetrieveClusterHierarchyNoView(t *testing.T) {
 	mockDgraphForClusterQueries(testHierarchy)
-	got := RetrieveClusterHierarchy("")
+	got := RetrieveClusterHierarchy(context.Background(), "")
 	expected := JSONDataWrapper{}
 	assert.Equal(t, expected, got)
 }
@@ -116,7 +122,7 @@ func TestRetrieveClusterHierarchyNoView(t *testing.T) {
 // TestRetrieveClusterHierarchyLogicalView ...
 func TestRetrieveClusterHierarchyLogicalView(t *testing.T) {
 	mockDgraphForClusterQueries(testHierarchy)
-	got := RetrieveClusterHierarchy(Logical)
+	got := RetrieveClusterHierarchy(context.Background(), Logical)
 	firstNamespace := Children{
 		Name: "namespace-first",
 		Type: NamespaceType,
@@ -136,10 +142,25 @@ func TestRetrieveClusterHierarchyLogicalView(t *testing.T) {
 	assert.Equal(t, expected, got)
 }
 
+// TestRetrieveClusterHierarchyDeleted ...
+func TestRetrieveClusterHierarchyDeleted(t *testing.T) {
+	var queries []string
+	executeQuery = func(ctx context.Context, query string, root interface{}) error {
+		queries = append(queries, query)
+		return nil
+	}
+	RetrieveClusterHierarchyWithDeleted(context.Background(), Logical, "", Include)
+	RetrieveClusterHierarchyWithDeleted(context.Background(), Logical, "", Exclude)
+	RetrieveClusterMetricsWithDeleted(context.Background(), Logical, "", "", Only)
+	assert.NotContains(t, queries[0], "deleted")
+	assert.Contains(t, queries[1], "@filter(has(isNamespace) AND NOT has(deleted))")
+	assert.Contains(t, queries[2], "@filter(has(isNamespace) AND has(deleted))")
+}
+
 // TestRetrieveClusterHierarchyPhysicalView ...
 func TestRetrieveClusterHierarchyPhysicalView(t *testing.T) {
 	mockDgraphForClusterQueries(testHierarchy)
-	got := RetrieveClusterHierarchy(Physical)
+	got := RetrieveClusterHierarchy(context.Background(), Physical)
 	firstNamespace := Children{
 		Name: "namespace-first",
 		Type: NamespaceType,
@@ -162,7 +183,7 @@ func TestRetrieveClusterHierarchyPhysicalView(t *testing.T) {
 // TestRetrieveClusterMetricsNoView ...
 func TestRetrieveClusterMetricsNoView(t *testing.T) {
 	mockDgraphForClusterQueries(testMetrics)
-	got := RetrieveClusterMetrics("")
+	got := RetrieveClusterMetrics(context.Background(), "")
 	expected := JSONDataWrapper{}
 	assert.Equal(t, expected, got)
 }
@@ -170,7 +191,7 @@ func TestRetrieveClusterMetricsNoView(t *testing.T) {
 // TestRetrieveClusterMetricsLogicalView ...
 func TestRetrieveClusterMetricsLogicalView(t *testing.T) {
 	mockDgraphForClusterQueries(testMetrics)
-	got := RetrieveClusterMetrics(Logical)
+	got := RetrieveClusterMetrics(context.Background(), Logical)
 	firstNamespaceWithMetrics := Children{
 		Name:        "namespace-first",
 		Type:        NamespaceType,
@@ -211,7 +232,7 @@ func TestRetrieveClusterMetricsLogicalView(t *testing.T) {
 // TestRetrieveClusterMetricsPhysicalView ...
 func TestRetrieveClusterMetricsPhysicalView(t *testing.T) {
 	mockDgraphForClusterQueries(testMetrics)
-	got := RetrieveClusterMetrics(Physical)
+	got := RetrieveClusterMetrics(context.Background(), Physical)
 	firstNamespaceWithMetrics := Children{
 		Name:        "namespace-first",
 		Type:        NamespaceType,
@@ -256,7 +277,7 @@ func TestPopulateClusterAllocationAndCapacityNil(t *testing.T) {
 	defer resetAllocatedAndCapacity(old)
 
 	data := &JSONDataWrapper{}
-	PopulateClusterAllocationAndCapacity(data)
+	PopulateClusterAllocationAndCapacity(context.Background(), data)
 	expected := JSONDataWrapper{
 		Data: ParentWrapper{
 			CPUAllocated:     1.2,
@@ -283,7 +304,7 @@ func TestPopulateClusterAllocationAndCapacity(t *testing.T) {
 	defer resetAllocatedAndCapacity(old)
 
 	data := &JSONDataWrapper{}
-	PopulateClusterAllocationAndCapacity(data)
+	PopulateClusterAllocationAndCapacity(context.Background(), data)
 	expected := getTestAllocatedCapacityData()
 	assert.Equal(t, expected, *data)
 }
@@ -300,7 +321,7 @@ func TestPopulateNodeOrPVAllocationAndCapacity(t *testing.T) {
 		Type:  NodeType,
 		Name:  testResourceName,
 	}
-	r.PopulateNodeOrPVAllocationAndCapacity(data)
+	r.PopulateNodeOrPVAllocationAndCapacity(context.Background(), data)
 	expected := getTestAllocatedCapacityData()
 	assert.Equal(t, expected, *data)
 }
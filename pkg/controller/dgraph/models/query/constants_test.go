@@ -26,17 +26,20 @@ const (
 	testPodUID                 = "0x3e283"
 	testPodXID                 = "purser:pod-purser-dgraph-0"
 
-	testHierarchy            = "hierarchy"
-	testMetrics              = "metrics"
-	testRetrieveAllGroups    = "retrieveAllGroups"
-	testRetrieveGroupMetrics = "retrieveGroupMetrics"
-	testRetrieveSubscribers  = "retrieveSubscribers"
-	testLabelFilterPods      = "labelFilterPods"
-	testAlivePods            = "alivePods"
-	testPodInteractions      = "podInteractions"
-	testPodPrices            = "podPrices"
-	testCapacity             = "capacityAllocation"
-	testWrongQuery           = "wrongQuery"
-	testCPUPrice             = 0.24
-	testMemoryPrice          = 0.1
+	testHierarchy             = "hierarchy"
+	testMetrics               = "metrics"
+	testRetrieveAllGroups     = "retrieveAllGroups"
+	testRetrieveGroupMetrics  = "retrieveGroupMetrics"
+	testRetrieveSubscribers   = "retrieveSubscribers"
+	testLabelFilterPods       = "labelFilterPods"
+	testAlivePods             = "alivePods"
+	testPodInteractions       = "podInteractions"
+	testPodPrices             = "podPrices"
+	testCapacity              = "capacityAllocation"
+	testWrongQuery            = "wrongQuery"
+	testCPUPrice              = 0.24
+	testMemoryPrice           = 0.1
+	testEphemeralStoragePrice = 0.0002
+	testHugepagesPrice        = 0.012
+	testGPUPrice              = 1.2
 )
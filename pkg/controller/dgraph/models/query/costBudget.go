@@ -0,0 +1,101 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"context"
+	"strings"
+
+	"github.com/vmware/purser/pkg/controller/dgraph/models"
+	"github.com/vmware/purser/pkg/controller/utils"
+)
+
+type costBudgetPod struct {
+	Name        string       `json:"name"`
+	Namespace   *resourceRef `json:"namespace"`
+	CPUCost     float64      `json:"cpuCost"`
+	MemoryCost  float64      `json:"memoryCost"`
+	StorageCost float64      `json:"storageCost"`
+	GPUCost     float64      `json:"gpuCost"`
+	Label       []struct {
+		Key   string `json:"key"`
+		Value string `json:"value"`
+	} `json:"label"`
+}
+
+// RetrieveMonthToDateCost returns the month to date cost of pods of the namespace(without type prefix, all namespaces
+// if empty) having all labels of the selector, adjustments of pod costs are applied
+func RetrieveMonthToDateCost(ctx context.Context, namespace string, selector map[string]string) (float64, error) {
+	root := struct {
+		Pods []costBudgetPod `json:"pods"`
+	}{}
+	err := executeQuery(ctx, getQueryForMonthToDatePods(), &root)
+	if err != nil {
+		log.Errorf("unable to retrieve month to date costs of pods, err: %v", err)
+		return 0, err
+	}
+	return computeMonthToDateCost(root.Pods, namespace, selector), nil
+}
+
+func getQueryForMonthToDatePods() string {
+	monthRange := TimeRange{Start: utils.ConverTimeToRFC3339(utils.GetCurrentMonthStartTime())}
+	return `{
+		pods(func: has(isPod)) @filter(` + getLiveFilterInRange(monthRange) + `) {
+			` + getQueryForMetricsComputationWithAliasInRange("Pod", monthRange) + `
+			namespace {
+				name
+			}
+			label {
+				key
+				value
+			}
+		}
+	}`
+}
+
+// computeMonthToDateCost sums adjusted costs of pods matching the namespace and the selector
+func computeMonthToDateCost(pods []costBudgetPod, namespace string, selector map[string]string) float64 {
+	cost := 0.0
+	for _, pod := range pods {
+		if namespace != "" && (pod.Namespace == nil || strings.TrimPrefix(pod.Namespace.Name, NamespaceType+"-") != namespace) {
+			continue
+		}
+		if !matchesSelector(pod, selector) {
+			continue
+		}
+		cost += adjustCost(CostContext{PodType, pod.Name, CPUCostType}, pod.CPUCost) +
+			adjustCost(CostContext{PodType, pod.Name, MemoryCostType}, pod.MemoryCost) +
+			adjustCost(CostContext{PodType, pod.Name, StorageCostType}, pod.StorageCost) + pod.GPUCost
+	}
+	return cost
+}
+
+func matchesSelector(pod costBudgetPod, selector map[string]string) bool {
+	labels := make(map[string]string)
+	for _, label := range pod.Label {
+		labels[label.Key] = label.Value
+	}
+	for key, value := range selector {
+		// dropped labels are not stored so selectors with them match no pod
+		value, _ = models.RedactLabelValue(key, value)
+		if podValue, isPresent := labels[key]; !isPresent || podValue != value {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,59 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testCostBudgetPods = `{
+	"pods": [
+		{"name": "pod-web", "namespace": {"name": "namespace-shop"}, "cpuCost": 30, "memoryCost": 10, "gpuCost": 5,
+		 "label": [{"key": "team", "value": "payments"}]},
+		{"name": "pod-db", "namespace": {"name": "namespace-shop"}, "cpuCost": 20, "storageCost": 15},
+		{"name": "pod-batch", "namespace": {"name": "namespace-jobs"}, "cpuCost": 100,
+		 "label": [{"key": "team", "value": "payments"}, {"key": "app", "value": "batch"}]}
+	]
+}`
+
+func TestComputeMonthToDateCost(t *testing.T) {
+	root := struct {
+		Pods []costBudgetPod `json:"pods"`
+	}{}
+	assert.Nil(t, json.Unmarshal([]byte(testCostBudgetPods), &root))
+	assert.Equal(t, 180.0, computeMonthToDateCost(root.Pods, "", nil))
+	assert.Equal(t, 80.0, computeMonthToDateCost(root.Pods, "shop", nil))
+	assert.Equal(t, 145.0, computeMonthToDateCost(root.Pods, "", map[string]string{"team": "payments"}))
+	assert.Equal(t, 45.0, computeMonthToDateCost(root.Pods, "shop", map[string]string{"team": "payments"}))
+	assert.Equal(t, 100.0, computeMonthToDateCost(root.Pods, "", map[string]string{"team": "payments", "app": "batch"}))
+	assert.Equal(t, 0.0, computeMonthToDateCost(root.Pods, "shop", map[string]string{"team": "search"}))
+}
+
+func TestRetrieveMonthToDateCost(t *testing.T) {
+	executeQuery = func(ctx context.Context, query string, root interface{}) error {
+		return json.Unmarshal([]byte(testCostBudgetPods), root)
+	}
+
+	cost, err := RetrieveMonthToDateCost(context.Background(), "jobs", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 100.0, cost)
+}
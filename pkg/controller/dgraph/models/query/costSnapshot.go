@@ -0,0 +1,257 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/vmware/purser/pkg/controller/dgraph"
+	"github.com/vmware/purser/pkg/controller/dgraph/models"
+	qb "github.com/vmware/purser/pkg/querybuilder"
+)
+
+// DefaultCostTimeSeriesDays is the number of days of cost time series without start
+const DefaultCostTimeSeriesDays = 30
+
+var storeCostSnapshots = models.StoreCostSnapshots
+
+var (
+	snapshotMu          sync.Mutex
+	lastCompletedDay    time.Time
+	costSnapshotsByType = map[string]bool{PodType: true, NamespaceType: true, NodeType: true}
+)
+
+// CostPoint is the cost of a resource over a day starting at Day
+type CostPoint struct {
+	Day         string  `json:"day"`
+	CPUCost     float64 `json:"cpuCost"`
+	MemoryCost  float64 `json:"memoryCost"`
+	StorageCost float64 `json:"storageCost"`
+	TotalCost   float64 `json:"totalCost"`
+}
+
+// CostSeries is the daily cost of a resource sorted by day, days without snapshots of the resource are left out.
+// Namespace is the namespace of pods.
+type CostSeries struct {
+	Name      string      `json:"name"`
+	Namespace string      `json:"namespace,omitempty"`
+	TotalCost float64     `json:"totalCost"`
+	Points    []CostPoint `json:"points"`
+}
+
+// CostTimeSeries is the daily cost of resources of a type between start and end, series are sorted by total cost
+type CostTimeSeries struct {
+	Start  string       `json:"start"`
+	End    string       `json:"end"`
+	Type   string       `json:"type"`
+	Series []CostSeries `json:"series"`
+}
+
+// CostTimeSeriesWrapper structure
+type CostTimeSeriesWrapper struct {
+	Data CostTimeSeries `json:"data"`
+}
+
+// StoreDailyCostSnapshots stores cost of pods, namespaces and nodes of today so far and of yesterday once it is
+// complete, so time series of past days don't need month to date queries
+func StoreDailyCostSnapshots() {
+	snapshotMu.Lock()
+	defer snapshotMu.Unlock()
+
+	ctx := dgraph.WithOwnCluster(context.Background())
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	yesterday := today.AddDate(0, 0, -1)
+	if !lastCompletedDay.Equal(yesterday) && storeCostSnapshotsOfDay(ctx, yesterday, today) {
+		lastCompletedDay = yesterday
+	}
+	storeCostSnapshotsOfDay(ctx, today, now)
+}
+
+// storeCostSnapshotsOfDay stores cost of resources between the start of the day and end, it returns false on failure
+func storeCostSnapshotsOfDay(ctx context.Context, day, end time.Time) bool {
+	timeRange := TimeRange{Start: day.Format(time.RFC3339), End: end.Format(time.RFC3339)}
+	root := struct {
+		Pods []chargebackPod `json:"pods"`
+	}{}
+	query, vars := getQueryForChargebackPods(timeRange, All)
+	if err := executeQueryWithVars(ctx, query, vars, &root); err != nil {
+		log.Errorf("unable to retrieve costs of pods for snapshots of %s, err: %v", timeRange.Start, err)
+		return false
+	}
+	nodes := RetrieveClusterNodes(ctx, timeRange).Data.Children
+
+	snapshots := computeCostSnapshots(root.Pods, nodes)
+	if err := storeCostSnapshots(day, snapshots); err != nil {
+		log.Errorf("unable to store cost snapshots of %s, err: %v", timeRange.Start, err)
+		return false
+	}
+	log.Debugf("stored %d cost snapshots of %s", len(snapshots), timeRange.Start)
+	return true
+}
+
+// computeCostSnapshots returns adjusted cost of pods, their cost summed per namespace and cost of nodes
+func computeCostSnapshots(pods []chargebackPod, nodes []Children) []models.CostSnapshot {
+	snapshots := []models.CostSnapshot{}
+	namespaces := make(map[string]*models.CostSnapshot)
+	var namespaceNames []string
+	for _, pod := range pods {
+		namespace := ""
+		if pod.Namespace != nil {
+			namespace = pod.Namespace.Name
+		}
+		snapshot := models.CostSnapshot{
+			ResourceType: PodType,
+			ResourceName: pod.Name,
+			Namespace:    namespace,
+			CPUCost:      adjustCost(CostContext{PodType, pod.Name, CPUCostType}, pod.CPUCost),
+			MemoryCost:   adjustCost(CostContext{PodType, pod.Name, MemoryCostType}, pod.MemoryCost),
+			StorageCost:  adjustCost(CostContext{PodType, pod.Name, StorageCostType}, pod.StorageCost),
+		}
+		snapshots = append(snapshots, snapshot)
+		if namespace == "" {
+			continue
+		}
+		if _, isPresent := namespaces[namespace]; !isPresent {
+			namespaces[namespace] = &models.CostSnapshot{ResourceType: NamespaceType, ResourceName: namespace}
+			namespaceNames = append(namespaceNames, namespace)
+		}
+		namespaces[namespace].CPUCost += snapshot.CPUCost
+		namespaces[namespace].MemoryCost += snapshot.MemoryCost
+		namespaces[namespace].StorageCost += snapshot.StorageCost
+	}
+	sort.Strings(namespaceNames)
+	for _, name := range namespaceNames {
+		snapshots = append(snapshots, *namespaces[name])
+	}
+	for _, node := range nodes {
+		snapshots = append(snapshots, models.CostSnapshot{
+			ResourceType: NodeType,
+			ResourceName: node.Name,
+			CPUCost:      node.CPUCost,
+			MemoryCost:   node.MemoryCost,
+			StorageCost:  node.StorageCost,
+		})
+	}
+	return snapshots
+}
+
+// RetrieveCostTimeSeries returns daily cost of pods, namespaces or nodes from snapshots of days between start and
+// end, the last 30 days by default. Name restricts it to one resource and namespace to pods of the namespace.
+func RetrieveCostTimeSeries(ctx context.Context, resourceType, name, namespace string, timeRange TimeRange) CostTimeSeriesWrapper {
+	timeRange = getCostTimeSeriesRange(timeRange)
+	root := struct {
+		Snapshots []models.CostSnapshot `json:"snapshots"`
+	}{}
+	query, vars := getQueryForCostSnapshots(resourceType, name, namespace, timeRange)
+	err := executeQueryWithVars(ctx, query, vars, &root)
+	if err != nil {
+		log.Errorf("unable to retrieve cost snapshots of %s, err: %v", resourceType, err)
+		return CostTimeSeriesWrapper{}
+	}
+	return CostTimeSeriesWrapper{Data: CostTimeSeries{
+		Start:  timeRange.Start,
+		End:    timeRange.End,
+		Type:   resourceType,
+		Series: computeCostSeries(root.Snapshots),
+	}}
+}
+
+// IsCostSnapshotType returns true if daily cost of resources of the type is snapshotted
+func IsCostSnapshotType(resourceType string) bool {
+	return costSnapshotsByType[resourceType]
+}
+
+// getCostTimeSeriesRange returns the time range ending now if it has no end and starting 30 days before its end if
+// it has no start, the start is moved to the start of its day so the snapshot of that day is included
+func getCostTimeSeriesRange(timeRange TimeRange) TimeRange {
+	end, err := time.Parse(time.RFC3339, timeRange.End)
+	if err != nil {
+		end = time.Now()
+		timeRange.End = end.Format(time.RFC3339)
+	}
+	start, err := time.Parse(time.RFC3339, timeRange.Start)
+	if err != nil {
+		start = end.AddDate(0, 0, -DefaultCostTimeSeriesDays)
+	}
+	start = start.In(time.Local)
+	timeRange.Start = time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.Local).Format(time.RFC3339)
+	return timeRange
+}
+
+func getQueryForCostSnapshots(resourceType, name, namespace string, timeRange TimeRange) (string, qb.Vars) {
+	vars := qb.Vars{"$type": resourceType, "$start": timeRange.Start, "$end": timeRange.End}
+	filter := `has(isCostSnapshot) AND ge(day, $start) AND lt(day, $end)`
+	if name != All {
+		vars["$name"] = name
+		filter += ` AND eq(resourceName, $name)`
+	}
+	if namespace != All {
+		vars["$namespace"] = namespace
+		filter += ` AND eq(snapshotNamespace, $namespace)`
+	}
+	return vars.Declaration() + ` {
+		snapshots(func: eq(resourceType, $type)) @filter(` + filter + `) {
+			resourceName
+			snapshotNamespace
+			day
+			cpuCost
+			memoryCost
+			storageCost
+			cost
+		}
+	}`, vars
+}
+
+// computeCostSeries groups snapshots per resource
+func computeCostSeries(snapshots []models.CostSnapshot) []CostSeries {
+	series := []CostSeries{}
+	indices := make(map[string]int)
+	for _, snapshot := range snapshots {
+		key := snapshot.Namespace + "/" + snapshot.ResourceName
+		index, isPresent := indices[key]
+		if !isPresent {
+			index = len(series)
+			indices[key] = index
+			series = append(series, CostSeries{Name: snapshot.ResourceName, Namespace: snapshot.Namespace})
+		}
+		series[index].TotalCost += snapshot.Cost
+		series[index].Points = append(series[index].Points, CostPoint{
+			Day:         snapshot.Day,
+			CPUCost:     snapshot.CPUCost,
+			MemoryCost:  snapshot.MemoryCost,
+			StorageCost: snapshot.StorageCost,
+			TotalCost:   snapshot.Cost,
+		})
+	}
+
+	for _, resource := range series {
+		points := resource.Points
+		sort.SliceStable(points, func(i, j int) bool { return points[i].Day < points[j].Day })
+	}
+	sort.SliceStable(series, func(i, j int) bool {
+		if series[i].TotalCost != series[j].TotalCost {
+			return series[i].TotalCost > series[j].TotalCost
+		}
+		return series[i].Namespace+"/"+series[i].Name < series[j].Namespace+"/"+series[j].Name
+	})
+	return series
+}
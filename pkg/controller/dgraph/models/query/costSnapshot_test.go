@@ -0,0 +1,112 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vmware/purser/pkg/controller/dgraph/models"
+)
+
+func TestComputeCostSnapshots(t *testing.T) {
+	pods := []chargebackPod{}
+	err := json.Unmarshal([]byte(`[
+		{"name": "pod-web-1", "cpuCost": 4, "memoryCost": 1, "namespace": {"name": "namespace-shop"}},
+		{"name": "pod-db-0", "cpuCost": 2, "storageCost": 3, "namespace": {"name": "namespace-shop"}},
+		{"name": "pod-etl", "cpuCost": 3, "namespace": {"name": "namespace-etl"}}
+	]`), &pods)
+	assert.NoError(t, err)
+	nodes := []Children{{Name: "node-a", CPUCost: 10, MemoryCost: 5, StorageCost: 1}}
+
+	assert.Equal(t, []models.CostSnapshot{
+		{ResourceType: PodType, ResourceName: "pod-web-1", Namespace: "namespace-shop", CPUCost: 4, MemoryCost: 1},
+		{ResourceType: PodType, ResourceName: "pod-db-0", Namespace: "namespace-shop", CPUCost: 2, StorageCost: 3},
+		{ResourceType: PodType, ResourceName: "pod-etl", Namespace: "namespace-etl", CPUCost: 3},
+		{ResourceType: NamespaceType, ResourceName: "namespace-etl", CPUCost: 3},
+		{ResourceType: NamespaceType, ResourceName: "namespace-shop", CPUCost: 6, MemoryCost: 1, StorageCost: 3},
+		{ResourceType: NodeType, ResourceName: "node-a", CPUCost: 10, MemoryCost: 5, StorageCost: 1},
+	}, computeCostSnapshots(pods, nodes))
+}
+
+func TestRetrieveCostTimeSeries(t *testing.T) {
+	var gotQuery string
+	var gotVars map[string]string
+	executeQueryWithVars = func(ctx context.Context, query string, vars map[string]string, root interface{}) error {
+		gotQuery, gotVars = query, vars
+		return json.Unmarshal([]byte(`{"snapshots": [
+			{"resourceName": "namespace-shop", "day": "2018-10-02T00:00:00Z", "cpuCost": 2, "cost": 2},
+			{"resourceName": "namespace-etl", "day": "2018-10-01T00:00:00Z", "cpuCost": 1, "memoryCost": 1, "cost": 2},
+			{"resourceName": "namespace-shop", "day": "2018-10-01T00:00:00Z", "cpuCost": 1, "storageCost": 1, "cost": 2}
+		]}`), root)
+	}
+
+	got := RetrieveCostTimeSeries(context.Background(), NamespaceType, All, All, TimeRange{Start: "2018-10-01T00:00:00Z", End: "2018-10-03T00:00:00Z"}).Data
+	assert.Equal(t, map[string]string{"$type": NamespaceType, "$start": got.Start, "$end": "2018-10-03T00:00:00Z"}, gotVars)
+	assert.False(t, strings.Contains(gotQuery, "resourceName, $name"))
+	assert.Equal(t, NamespaceType, got.Type)
+	assert.Equal(t, []CostSeries{
+		{Name: "namespace-shop", TotalCost: 4, Points: []CostPoint{
+			{Day: "2018-10-01T00:00:00Z", CPUCost: 1, StorageCost: 1, TotalCost: 2},
+			{Day: "2018-10-02T00:00:00Z", CPUCost: 2, TotalCost: 2},
+		}},
+		{Name: "namespace-etl", TotalCost: 2, Points: []CostPoint{
+			{Day: "2018-10-01T00:00:00Z", CPUCost: 1, MemoryCost: 1, TotalCost: 2},
+		}},
+	}, got.Series)
+
+	RetrieveCostTimeSeries(context.Background(), PodType, "pod-web-1", "namespace-shop", TimeRange{})
+	assert.Equal(t, "pod-web-1", gotVars["$name"])
+	assert.Equal(t, "namespace-shop", gotVars["$namespace"])
+	assert.True(t, strings.Contains(gotQuery, "eq(snapshotNamespace, $namespace)"))
+}
+
+func TestGetCostTimeSeriesRange(t *testing.T) {
+	end := time.Date(2018, 10, 31, 15, 30, 0, 0, time.Local)
+	got := getCostTimeSeriesRange(TimeRange{End: end.Format(time.RFC3339)})
+	assert.Equal(t, time.Date(2018, 10, 1, 0, 0, 0, 0, time.Local).Format(time.RFC3339), got.Start)
+	assert.Equal(t, end.Format(time.RFC3339), got.End)
+
+	start := time.Date(2018, 10, 10, 12, 0, 0, 0, time.Local)
+	got = getCostTimeSeriesRange(TimeRange{Start: start.Format(time.RFC3339), End: end.Format(time.RFC3339)})
+	assert.Equal(t, time.Date(2018, 10, 10, 0, 0, 0, 0, time.Local).Format(time.RFC3339), got.Start)
+}
+
+func TestStoreDailyCostSnapshots(t *testing.T) {
+	mockDgraphForChargebackPods()
+	executeQuery = func(ctx context.Context, query string, root interface{}) error {
+		return json.Unmarshal([]byte(`{"children": [{"name": "node-a", "type": "node", "cpuCost": 10}]}`), root)
+	}
+	var days []time.Time
+	storeCostSnapshots = func(day time.Time, snapshots []models.CostSnapshot) error {
+		days = append(days, day)
+		return nil
+	}
+	defer func() { storeCostSnapshots = models.StoreCostSnapshots }()
+	lastCompletedDay = time.Time{}
+
+	StoreDailyCostSnapshots()
+	StoreDailyCostSnapshots()
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	assert.Equal(t, []time.Time{today.AddDate(0, 0, -1), today, today}, days)
+}
@@ -0,0 +1,285 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/vmware/purser/pkg/controller/dgraph/models"
+	qb "github.com/vmware/purser/pkg/querybuilder"
+)
+
+// DedicatedPoolTeam is the share of a dedicated pool owned by a team, idle cost of the pool is split among its
+// teams proportionally to the cost of their requests
+type DedicatedPoolTeam struct {
+	Name            string  `json:"name"`
+	Pods            int     `json:"pods"`
+	CPURequest      float64 `json:"cpuRequest"`
+	MemoryRequest   float64 `json:"memoryRequest"`
+	UsedHourlyCost  float64 `json:"usedHourlyCost"`
+	IdleHourlyCost  float64 `json:"idleHourlyCost"`
+	IdleMonthlyCost float64 `json:"idleMonthlyCost"`
+}
+
+// DedicatedPool is a set of live nodes with the same NoSchedule and NoExecute taints. Pods tolerating every taint
+// of the pool by key own it and are grouped into teams, pods tolerating all taints(ex: daemonsets) use the pool
+// but don't own it. Idle cost of a pool without teams is unattributed.
+type DedicatedPool struct {
+	Name                   string              `json:"name"`
+	Taints                 []string            `json:"taints"`
+	Nodes                  int                 `json:"nodes"`
+	Pods                   int                 `json:"pods"`
+	CPUCapacity            float64             `json:"cpuCapacity"`
+	MemoryCapacity         float64             `json:"memoryCapacity"`
+	CPURequest             float64             `json:"cpuRequest"`
+	MemoryRequest          float64             `json:"memoryRequest"`
+	CPUUtilization         float64             `json:"cpuUtilization"`
+	MemoryUtilization      float64             `json:"memoryUtilization"`
+	HourlyCost             float64             `json:"hourlyCost"`
+	UsedHourlyCost         float64             `json:"usedHourlyCost"`
+	IdleHourlyCost         float64             `json:"idleHourlyCost"`
+	IdleMonthlyCost        float64             `json:"idleMonthlyCost"`
+	UnattributedHourlyCost float64             `json:"unattributedHourlyCost"`
+	Teams                  []DedicatedPoolTeam `json:"teams"`
+}
+
+// DedicatedPools are the dedicated pools of the cluster sorted by idle cost, pods are grouped into teams by
+// the value of Label, by their namespace(namespace-<name>) if they don't have it
+type DedicatedPools struct {
+	Label           string          `json:"label"`
+	IdleHourlyCost  float64         `json:"idleHourlyCost"`
+	IdleMonthlyCost float64         `json:"idleMonthlyCost"`
+	Pools           []DedicatedPool `json:"pools"`
+}
+
+// DedicatedPoolsWrapper structure
+type DedicatedPoolsWrapper struct {
+	Data DedicatedPools `json:"data"`
+}
+
+type poolNode struct {
+	affinityNode
+	Taints string `json:"taints"`
+}
+
+type poolPod struct {
+	Name          string       `json:"name"`
+	CPURequest    float64      `json:"cpuRequest"`
+	MemoryRequest float64      `json:"memoryRequest"`
+	Tolerations   string       `json:"tolerations"`
+	Node          *resourceRef `json:"node"`
+	Namespace     *resourceRef `json:"namespace"`
+	Labels        []struct {
+		Value string `json:"value"`
+	} `json:"label"`
+}
+
+// RetrieveDedicatedPools returns utilization and idle cost of node pools dedicated via taints, idle cost of each
+// pool is attributed to the teams whose tolerating pods own it. Teams are values of label, of the tenant label
+// if it is empty.
+func RetrieveDedicatedPools(ctx context.Context, label string) DedicatedPoolsWrapper {
+	if label == "" || label == All {
+		label = GetTenantLabel()
+	}
+	root := struct {
+		Nodes []poolNode `json:"nodes"`
+		Pods  []poolPod  `json:"pods"`
+	}{}
+	query, vars := getQueryForDedicatedPools(label)
+	err := executeQueryWithVars(ctx, query, vars, &root)
+	if err != nil {
+		log.Errorf("unable to retrieve nodes and pods for dedicated pools, err: %v", err)
+		return DedicatedPoolsWrapper{}
+	}
+	return DedicatedPoolsWrapper{Data: computeDedicatedPools(label, root.Nodes, root.Pods)}
+}
+
+func getQueryForDedicatedPools(label string) (string, qb.Vars) {
+	vars := qb.Vars{"$label": label}
+	return vars.Declaration() + ` {
+		nodes(func: has(isNode)) @filter(has(taints) AND (NOT has(endTime)) AND (NOT has(isVirtual)) AND gt(cpuCapacity, 0)) {
+			name
+			taints
+			cpuCapacity
+			memoryCapacity
+			cpuPrice
+			memoryPrice
+		}
+		pods(func: has(isPod)) @filter((NOT has(endTime)) AND has(node)) {
+			name
+			cpuRequest
+			memoryRequest
+			tolerations
+			node {
+				name
+			}
+			namespace {
+				name
+			}
+			label @filter(eq(key, $label)) {
+				value
+			}
+		}
+	}`, vars
+}
+
+// computeDedicatedPools groups the tainted nodes into pools by their taints and prices requests of pods on them
+// with the prices of their node
+func computeDedicatedPools(label string, nodes []poolNode, pods []poolPod) DedicatedPools {
+	data := DedicatedPools{Label: label, Pools: []DedicatedPool{}}
+	pools := make(map[string]*DedicatedPool)
+	poolTaints := make(map[string][]models.NodeTaint)
+	nodesByName := make(map[string]poolNode)
+	poolOfNode := make(map[string]string)
+	for _, node := range nodes {
+		taints := models.ParseNodeTaints(node.Taints)
+		if len(taints) == 0 {
+			continue
+		}
+		name := getPoolName(taints)
+		pool, isPresent := pools[name]
+		if !isPresent {
+			pool = &DedicatedPool{Name: name, Taints: strings.Split(name, ","), Teams: []DedicatedPoolTeam{}}
+			pools[name] = pool
+			poolTaints[name] = taints
+		}
+		pool.Nodes++
+		pool.CPUCapacity += node.CPUCapacity
+		pool.MemoryCapacity += node.MemoryCapacity
+		pool.HourlyCost += nodeHourlyCost(node.affinityNode)
+		nodesByName[node.Name] = node
+		poolOfNode[node.Name] = name
+	}
+
+	teams := make(map[string]map[string]*DedicatedPoolTeam)
+	for _, pod := range pods {
+		if pod.Node == nil {
+			continue
+		}
+		name, isPresent := poolOfNode[pod.Node.Name]
+		if !isPresent {
+			continue
+		}
+		node := nodesByName[pod.Node.Name]
+		cost := pod.CPURequest*node.CPUPrice + pod.MemoryRequest*node.MemoryPrice
+		pool := pools[name]
+		pool.Pods++
+		pool.CPURequest += pod.CPURequest
+		pool.MemoryRequest += pod.MemoryRequest
+		pool.UsedHourlyCost += cost
+		if !ownsPool(models.ParsePodTolerations(pod.Tolerations), poolTaints[name]) {
+			continue
+		}
+		teamName := getPodTeam(pod)
+		if teams[name] == nil {
+			teams[name] = make(map[string]*DedicatedPoolTeam)
+		}
+		team, isPresent := teams[name][teamName]
+		if !isPresent {
+			team = &DedicatedPoolTeam{Name: teamName}
+			teams[name][teamName] = team
+		}
+		team.Pods++
+		team.CPURequest += pod.CPURequest
+		team.MemoryRequest += pod.MemoryRequest
+		team.UsedHourlyCost += cost
+	}
+
+	for name, pool := range pools {
+		if pool.CPUCapacity > 0 {
+			pool.CPUUtilization = pool.CPURequest / pool.CPUCapacity
+		}
+		if pool.MemoryCapacity > 0 {
+			pool.MemoryUtilization = pool.MemoryRequest / pool.MemoryCapacity
+		}
+		if idle := pool.HourlyCost - pool.UsedHourlyCost; idle > 0 {
+			pool.IdleHourlyCost = idle
+		}
+		pool.IdleMonthlyCost = pool.IdleHourlyCost * models.HoursInMonth
+
+		used := make(map[string]float64)
+		for teamName, team := range teams[name] {
+			used[teamName] = team.UsedHourlyCost
+		}
+		allocated := allocateSharedCost(used, pool.IdleHourlyCost)
+		for teamName, team := range teams[name] {
+			team.IdleHourlyCost = allocated[teamName]
+			team.IdleMonthlyCost = team.IdleHourlyCost * models.HoursInMonth
+			pool.Teams = append(pool.Teams, *team)
+		}
+		if len(pool.Teams) == 0 {
+			pool.UnattributedHourlyCost = pool.IdleHourlyCost
+		}
+		sort.SliceStable(pool.Teams, func(i, j int) bool {
+			if pool.Teams[i].IdleHourlyCost != pool.Teams[j].IdleHourlyCost {
+				return pool.Teams[i].IdleHourlyCost > pool.Teams[j].IdleHourlyCost
+			}
+			return pool.Teams[i].Name < pool.Teams[j].Name
+		})
+		data.IdleHourlyCost += pool.IdleHourlyCost
+		data.Pools = append(data.Pools, *pool)
+	}
+	data.IdleMonthlyCost = data.IdleHourlyCost * models.HoursInMonth
+	sort.SliceStable(data.Pools, func(i, j int) bool {
+		if data.Pools[i].IdleHourlyCost != data.Pools[j].IdleHourlyCost {
+			return data.Pools[i].IdleHourlyCost > data.Pools[j].IdleHourlyCost
+		}
+		return data.Pools[i].Name < data.Pools[j].Name
+	})
+	return data
+}
+
+// getPoolName returns the taints of the pool in kubectl form separated by commas, taints are stored sorted
+func getPoolName(taints []models.NodeTaint) string {
+	names := []string{}
+	for _, taint := range taints {
+		names = append(names, taint.String())
+	}
+	return strings.Join(names, ",")
+}
+
+// ownsPool returns true if every taint of the pool is tolerated by a toleration of its key, tolerations of all
+// keys are used by system pods which run everywhere and don't claim a pool
+func ownsPool(tolerations []models.PodToleration, taints []models.NodeTaint) bool {
+	for _, taint := range taints {
+		tolerated := false
+		for _, toleration := range tolerations {
+			if toleration.Key != "" && toleration.Tolerates(taint) {
+				tolerated = true
+				break
+			}
+		}
+		if !tolerated {
+			return false
+		}
+	}
+	return true
+}
+
+// getPodTeam returns the value of the team label of the pod, its namespace if it doesn't have it
+func getPodTeam(pod poolPod) string {
+	if len(pod.Labels) > 0 && pod.Labels[0].Value != "" {
+		return pod.Labels[0].Value
+	}
+	if pod.Namespace != nil {
+		return pod.Namespace.Name
+	}
+	return ""
+}
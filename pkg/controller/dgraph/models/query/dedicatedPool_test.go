@@ -0,0 +1,102 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vmware/purser/pkg/controller/dgraph/models"
+)
+
+func mockDgraphForDedicatedPools() {
+	executeQueryWithVars = func(ctx context.Context, query string, vars map[string]string, root interface{}) error {
+		return json.Unmarshal([]byte(`{
+			"nodes": [
+				{"name": "node-gpu-1", "cpuCapacity": 4, "memoryCapacity": 16, "cpuPrice": 0.1, "memoryPrice": 0,
+					"taints": "[{\"key\":\"team\",\"value\":\"ml\",\"effect\":\"NoSchedule\"}]"},
+				{"name": "node-gpu-2", "cpuCapacity": 4, "memoryCapacity": 16, "cpuPrice": 0.1, "memoryPrice": 0,
+					"taints": "[{\"key\":\"team\",\"value\":\"ml\",\"effect\":\"NoSchedule\"}]"},
+				{"name": "node-batch", "cpuCapacity": 2, "memoryCapacity": 8, "cpuPrice": 0.1, "memoryPrice": 0,
+					"taints": "[{\"key\":\"dedicated\",\"value\":\"batch\",\"effect\":\"NoExecute\"}]"},
+				{"name": "node-plain", "cpuCapacity": 2, "memoryCapacity": 8, "cpuPrice": 0.1, "memoryPrice": 0}
+			],
+			"pods": [
+				{"name": "pod-train-1", "cpuRequest": 2, "memoryRequest": 4, "node": {"name": "node-gpu-1"}, "namespace": {"name": "namespace-research"},
+					"tolerations": "[{\"key\":\"team\",\"operator\":\"Equal\",\"value\":\"ml\",\"effect\":\"NoSchedule\"}]", "label": [{"value": "ml-team"}]},
+				{"name": "pod-train-2", "cpuRequest": 1, "memoryRequest": 2, "node": {"name": "node-gpu-2"}, "namespace": {"name": "namespace-research"},
+					"tolerations": "[{\"key\":\"team\",\"operator\":\"Exists\"}]"},
+				{"name": "pod-agent", "cpuRequest": 1, "memoryRequest": 1, "node": {"name": "node-gpu-1"}, "namespace": {"name": "namespace-kube-system"},
+					"tolerations": "[{\"operator\":\"Exists\"}]"},
+				{"name": "pod-web", "cpuRequest": 1, "memoryRequest": 1, "node": {"name": "node-plain"}, "namespace": {"name": "namespace-shop"}}
+			]
+		}`), root)
+	}
+}
+
+// TestRetrieveDedicatedPools ...
+func TestRetrieveDedicatedPools(t *testing.T) {
+	mockDgraphForDedicatedPools()
+	got := RetrieveDedicatedPools(context.Background(), "").Data
+	assert.Equal(t, DefaultTenantLabel, got.Label)
+	assert.InDelta(t, 0.6, got.IdleHourlyCost, 0.0001)
+	assert.InDelta(t, 0.6*models.HoursInMonth, got.IdleMonthlyCost, 0.0001)
+	assert.Len(t, got.Pools, 2)
+
+	ml := got.Pools[0]
+	assert.Equal(t, "team=ml:NoSchedule", ml.Name)
+	assert.Equal(t, []string{"team=ml:NoSchedule"}, ml.Taints)
+	assert.Equal(t, 2, ml.Nodes)
+	assert.Equal(t, 3, ml.Pods)
+	assert.InDelta(t, 0.5, ml.CPUUtilization, 0.0001)
+	assert.InDelta(t, 0.8, ml.HourlyCost, 0.0001)
+	assert.InDelta(t, 0.4, ml.UsedHourlyCost, 0.0001)
+	assert.InDelta(t, 0.4, ml.IdleHourlyCost, 0.0001)
+	assert.InDelta(t, 0, ml.UnattributedHourlyCost, 0.0001)
+	assert.Len(t, ml.Teams, 2)
+	assert.Equal(t, "ml-team", ml.Teams[0].Name)
+	assert.InDelta(t, 0.4*2/3, ml.Teams[0].IdleHourlyCost, 0.0001)
+	assert.Equal(t, "namespace-research", ml.Teams[1].Name)
+	assert.InDelta(t, 0.4/3, ml.Teams[1].IdleHourlyCost, 0.0001)
+
+	batch := got.Pools[1]
+	assert.Equal(t, "dedicated=batch:NoExecute", batch.Name)
+	assert.Equal(t, 0, batch.Pods)
+	assert.InDelta(t, 0.2, batch.IdleHourlyCost, 0.0001)
+	assert.InDelta(t, 0.2, batch.UnattributedHourlyCost, 0.0001)
+	assert.Len(t, batch.Teams, 0)
+}
+
+// TestGetQueryForDedicatedPools ...
+func TestGetQueryForDedicatedPools(t *testing.T) {
+	query, vars := getQueryForDedicatedPools("example.com/team")
+	assert.True(t, strings.Contains(query, `label @filter(eq(key, $label))`))
+	assert.Equal(t, "example.com/team", vars["$label"])
+	assert.True(t, strings.Contains(query, "has(taints)"))
+}
+
+// TestOwnsPool ...
+func TestOwnsPool(t *testing.T) {
+	taints := []models.NodeTaint{{Key: "team", Value: "ml", Effect: "NoSchedule"}, {Key: "gpu", Effect: "NoExecute"}}
+	assert.True(t, ownsPool([]models.PodToleration{{Key: "team", Value: "ml"}, {Key: "gpu", Operator: "Exists"}}, taints))
+	assert.False(t, ownsPool([]models.PodToleration{{Key: "team", Value: "ml"}}, taints))
+	assert.False(t, ownsPool([]models.PodToleration{{Operator: "Exists"}}, taints))
+}
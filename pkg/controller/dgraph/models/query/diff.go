@@ -0,0 +1,285 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"context"
+	"math"
+	"sort"
+	"time"
+
+	qb "github.com/vmware/purser/pkg/querybuilder"
+)
+
+// Workload change constants
+const (
+	Created = "created"
+	Deleted = "deleted"
+	Resized = "resized"
+)
+
+// workloadChecks are the workloads compared by diff, their pods are linked to them by the predicate of their type
+var workloadChecks = map[string]string{
+	DeploymentType:       DeploymentCheck,
+	DeploymentConfigType: DeploymentConfigCheck,
+	StatefulsetType:      StatefulsetCheck,
+	DaemonsetType:        DaemonsetCheck,
+	JobType:              JobCheck,
+	ReplicasetType:       ReplicasetCheck,
+}
+
+// WorkloadChange is a workload created, deleted or resized between start and end of a diff,
+// CPU and Memory are sums of requests of its pods
+type WorkloadChange struct {
+	Name         string  `json:"name"`
+	Type         string  `json:"type"`
+	Namespace    string  `json:"namespace"`
+	Change       string  `json:"change"`
+	CPUBefore    float64 `json:"cpuBefore"`
+	CPUAfter     float64 `json:"cpuAfter"`
+	MemoryBefore float64 `json:"memoryBefore"`
+	MemoryAfter  float64 `json:"memoryAfter"`
+}
+
+// NamespaceDiff is the change in cost of a namespace and the number of its workload changes
+type NamespaceDiff struct {
+	Name          string  `json:"name"`
+	CostBefore    float64 `json:"costBefore"`
+	CostAfter     float64 `json:"costAfter"`
+	CostChange    float64 `json:"costChange"`
+	PercentChange float64 `json:"percentChange"`
+	Created       int     `json:"created"`
+	Deleted       int     `json:"deleted"`
+	Resized       int     `json:"resized"`
+}
+
+// ClusterDiff structure, costs are month to date costs at start and end
+type ClusterDiff struct {
+	Start         string           `json:"start"`
+	End           string           `json:"end"`
+	CostBefore    float64          `json:"costBefore"`
+	CostAfter     float64          `json:"costAfter"`
+	CostChange    float64          `json:"costChange"`
+	PercentChange float64          `json:"percentChange"`
+	Namespaces    []NamespaceDiff  `json:"namespaces"`
+	Workloads     []WorkloadChange `json:"workloads"`
+}
+
+// ClusterDiffWrapper structure
+type ClusterDiffWrapper struct {
+	Data ClusterDiff `json:"data"`
+}
+
+type workloadAtTimes struct {
+	Name      string `json:"name"`
+	Type      string `json:"type"`
+	StartTime string `json:"startTime"`
+	EndTime   string `json:"endTime"`
+	Namespace *struct {
+		Name string `json:"name"`
+	} `json:"namespace"`
+	Before []podRequests `json:"before"`
+	After  []podRequests `json:"after"`
+}
+
+type podRequests struct {
+	CPURequest    float64 `json:"cpuRequest"`
+	MemoryRequest float64 `json:"memoryRequest"`
+}
+
+// RetrieveClusterDiff compares the cluster at start and end. It returns the workloads created, deleted or whose
+// pod requests changed in between, and the change in month to date cost of each namespace.
+func RetrieveClusterDiff(ctx context.Context, start, end string) ClusterDiffWrapper {
+	startTime, err := time.Parse(time.RFC3339, start)
+	if err != nil {
+		log.Errorf("invalid start time: %s, err: %v", start, err)
+		return ClusterDiffWrapper{}
+	}
+	endTime, err := time.Parse(time.RFC3339, end)
+	if err != nil {
+		log.Errorf("invalid end time: %s, err: %v", end, err)
+		return ClusterDiffWrapper{}
+	}
+
+	workloads, err := retrieveWorkloadChanges(ctx, start, end, startTime, endTime)
+	if err != nil {
+		log.Errorf("unable to retrieve workload changes, err: %v", err)
+		return ClusterDiffWrapper{}
+	}
+	costsBefore := getNamespaceCosts(RetrieveClusterMetricsAsOf(ctx, Logical, "", start))
+	costsAfter := getNamespaceCosts(RetrieveClusterMetricsAsOf(ctx, Logical, "", end))
+
+	data := ClusterDiff{Start: start, End: end, Workloads: workloads}
+	namespaces := make(map[string]*NamespaceDiff)
+	getNamespace := func(name string) *NamespaceDiff {
+		if _, isPresent := namespaces[name]; !isPresent {
+			namespaces[name] = &NamespaceDiff{Name: name}
+		}
+		return namespaces[name]
+	}
+	for name, cost := range costsBefore {
+		getNamespace(name).CostBefore = cost
+		data.CostBefore += cost
+	}
+	for name, cost := range costsAfter {
+		getNamespace(name).CostAfter = cost
+		data.CostAfter += cost
+	}
+	for _, workload := range workloads {
+		namespace := getNamespace(workload.Namespace)
+		switch workload.Change {
+		case Created:
+			namespace.Created++
+		case Deleted:
+			namespace.Deleted++
+		case Resized:
+			namespace.Resized++
+		}
+	}
+
+	data.Namespaces = []NamespaceDiff{}
+	for _, namespace := range namespaces {
+		namespace.CostChange = namespace.CostAfter - namespace.CostBefore
+		namespace.PercentChange = getPercentChange(namespace.CostBefore, namespace.CostAfter)
+		data.Namespaces = append(data.Namespaces, *namespace)
+	}
+	sort.Slice(data.Namespaces, func(i, j int) bool {
+		first, second := math.Abs(data.Namespaces[i].CostChange), math.Abs(data.Namespaces[j].CostChange)
+		if first != second {
+			return first > second
+		}
+		return data.Namespaces[i].Name < data.Namespaces[j].Name
+	})
+	data.CostChange = data.CostAfter - data.CostBefore
+	data.PercentChange = getPercentChange(data.CostBefore, data.CostAfter)
+	return ClusterDiffWrapper{Data: data}
+}
+
+// retrieveWorkloadChanges returns workloads which existed at start or end and were created, deleted or resized in between
+func retrieveWorkloadChanges(ctx context.Context, start, end string, startTime, endTime time.Time) ([]WorkloadChange, error) {
+	root := make(map[string][]workloadAtTimes)
+	err := executeQuery(ctx, getQueryForWorkloadsBetween(start, end), &root)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := []WorkloadChange{}
+	for _, workloads := range root {
+		for _, workload := range workloads {
+			change := WorkloadChange{
+				Name: workload.Name,
+				Type: workload.Type,
+			}
+			if workload.Namespace != nil {
+				change.Namespace = workload.Namespace.Name
+			}
+			change.CPUBefore, change.MemoryBefore = sumPodRequests(workload.Before)
+			change.CPUAfter, change.MemoryAfter = sumPodRequests(workload.After)
+
+			existedAtStart := existsAt(workload.StartTime, workload.EndTime, startTime)
+			existsAtEnd := existsAt(workload.StartTime, workload.EndTime, endTime)
+			switch {
+			case !existedAtStart && existsAtEnd:
+				change.Change = Created
+			case existedAtStart && !existsAtEnd:
+				change.Change = Deleted
+			case existedAtStart && existsAtEnd && (isChanged(change.CPUBefore, change.CPUAfter) || isChanged(change.MemoryBefore, change.MemoryAfter)):
+				change.Change = Resized
+			default:
+				continue
+			}
+			changes = append(changes, change)
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].Namespace != changes[j].Namespace {
+			return changes[i].Namespace < changes[j].Namespace
+		}
+		return changes[i].Name < changes[j].Name
+	})
+	return changes, nil
+}
+
+// existsAt checks whether a resource with given start and end times (RFC3339, empty end if it is live) existed at t
+func existsAt(start, end string, t time.Time) bool {
+	startTime, err := time.Parse(time.RFC3339, start)
+	if err != nil || startTime.After(t) {
+		return false
+	}
+	if end == "" {
+		return true
+	}
+	endTime, err := time.Parse(time.RFC3339, end)
+	return err != nil || endTime.After(t)
+}
+
+func sumPodRequests(pods []podRequests) (float64, float64) {
+	cpu, memory := 0.0, 0.0
+	for _, pod := range pods {
+		cpu += pod.CPURequest
+		memory += pod.MemoryRequest
+	}
+	return cpu, memory
+}
+
+func isChanged(before, after float64) bool {
+	return math.Abs(after-before) > 1e-9
+}
+
+func getPercentChange(before, after float64) float64 {
+	if before == 0 {
+		return 0
+	}
+	return (after - before) / before * 100
+}
+
+// getNamespaceCosts returns total cost of each namespace in cluster metrics
+func getNamespaceCosts(metrics JSONDataWrapper) map[string]float64 {
+	costs := make(map[string]float64)
+	for _, namespace := range metrics.Data.Children {
+		costs[namespace.Name] = namespace.CPUCost + namespace.MemoryCost + namespace.StorageCost
+	}
+	return costs
+}
+
+func getQueryForWorkloadsBetween(start, end string) string {
+	types := []string{}
+	for workloadType := range workloadChecks {
+		types = append(types, workloadType)
+	}
+	sort.Strings(types)
+
+	query := qb.New()
+	for _, workloadType := range types {
+		filter := qb.And(qb.Le("startTime", end), qb.Or(qb.Not(qb.Has("endTime")), qb.Gt("endTime", start)))
+		if workloadType == ReplicasetType {
+			// replicasets of deployments are compared as part of their deployment
+			filter = qb.And(filter, qb.Not(qb.Has("deployment")))
+		}
+		query.Block(qb.Func(workloadType, qb.Has(workloadChecks[workloadType])).Filter(filter).
+			Fields("name", "type", "startTime", "endTime").
+			Child(
+				qb.Edge("namespace").Fields("name"),
+				qb.Edge("~"+workloadType).Alias("before").Filter(qb.And(qb.Has("isPod"), getAsOfCondition(start))).
+					Fields("cpuRequest", "memoryRequest"),
+				qb.Edge("~"+workloadType).Alias("after").Filter(qb.And(qb.Has("isPod"), getAsOfCondition(end))).
+					Fields("cpuRequest", "memoryRequest"),
+			))
+	}
+	return query.String()
+}
@@ -0,0 +1,97 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testDiffStart = "2018-10-31T00:00:00Z"
+	testDiffEnd   = "2018-11-30T00:00:00Z"
+)
+
+func mockDgraphForDiff() {
+	executeQuery = func(ctx context.Context, query string, root interface{}) error {
+		if _, isMetrics := root.(*ParentWrapper); isMetrics {
+			if strings.Contains(query, testDiffStart) {
+				return json.Unmarshal([]byte(`{"children": [{"name": "namespace-default", "cpuCost": 10, "memoryCost": 5}, {"name": "namespace-old", "cpuCost": 5}]}`), root)
+			}
+			return json.Unmarshal([]byte(`{"children": [{"name": "namespace-default", "cpuCost": 15, "memoryCost": 5}, {"name": "namespace-new", "cpuCost": 10}]}`), root)
+		}
+		return json.Unmarshal([]byte(`{
+			"deployment": [
+				{"name": "deployment-web", "type": "deployment", "startTime": "2018-10-01T00:00:00Z", "namespace": {"name": "namespace-default"},
+				 "before": [{"cpuRequest": 1, "memoryRequest": 1}], "after": [{"cpuRequest": 1, "memoryRequest": 1}, {"cpuRequest": 1, "memoryRequest": 1}]},
+				{"name": "deployment-api", "type": "deployment", "startTime": "2018-10-01T00:00:00Z", "namespace": {"name": "namespace-default"},
+				 "before": [{"cpuRequest": 1}], "after": [{"cpuRequest": 1}]}
+			],
+			"statefulset": [
+				{"name": "statefulset-db", "type": "statefulset", "startTime": "2018-11-10T00:00:00Z", "namespace": {"name": "namespace-new"},
+				 "after": [{"cpuRequest": 2}]}
+			],
+			"job": [
+				{"name": "job-batch", "type": "job", "startTime": "2018-10-01T00:00:00Z", "endTime": "2018-11-02T00:00:00Z", "namespace": {"name": "namespace-old"},
+				 "before": [{"cpuRequest": 4}]}
+			]
+		}`), root)
+	}
+}
+
+// TestRetrieveClusterDiff ...
+func TestRetrieveClusterDiff(t *testing.T) {
+	mockDgraphForDiff()
+	diff := RetrieveClusterDiff(context.Background(), testDiffStart, testDiffEnd).Data
+
+	expectedWorkloads := []WorkloadChange{
+		{Name: "deployment-web", Type: DeploymentType, Namespace: "namespace-default", Change: Resized, CPUBefore: 1, CPUAfter: 2, MemoryBefore: 1, MemoryAfter: 2},
+		{Name: "statefulset-db", Type: StatefulsetType, Namespace: "namespace-new", Change: Created, CPUAfter: 2},
+		{Name: "job-batch", Type: JobType, Namespace: "namespace-old", Change: Deleted, CPUBefore: 4},
+	}
+	assert.Equal(t, expectedWorkloads, diff.Workloads)
+
+	expectedNamespaces := []NamespaceDiff{
+		{Name: "namespace-new", CostAfter: 10, CostChange: 10, Created: 1},
+		{Name: "namespace-default", CostBefore: 15, CostAfter: 20, CostChange: 5, PercentChange: 100.0 / 3, Resized: 1},
+		{Name: "namespace-old", CostBefore: 5, CostChange: -5, PercentChange: -100, Deleted: 1},
+	}
+	assert.Equal(t, len(expectedNamespaces), len(diff.Namespaces))
+	for i, namespace := range expectedNamespaces {
+		assert.Equal(t, namespace.Name, diff.Namespaces[i].Name)
+		assert.InDelta(t, namespace.CostChange, diff.Namespaces[i].CostChange, 1e-9)
+		assert.InDelta(t, namespace.PercentChange, diff.Namespaces[i].PercentChange, 1e-9)
+		assert.Equal(t, namespace.Created, diff.Namespaces[i].Created)
+		assert.Equal(t, namespace.Deleted, diff.Namespaces[i].Deleted)
+		assert.Equal(t, namespace.Resized, diff.Namespaces[i].Resized)
+	}
+	assert.InDelta(t, 20.0, diff.CostBefore, 1e-9)
+	assert.InDelta(t, 30.0, diff.CostAfter, 1e-9)
+	assert.InDelta(t, 50.0, diff.PercentChange, 1e-9)
+}
+
+// TestGetQueryForWorkloadsBetween ...
+func TestGetQueryForWorkloadsBetween(t *testing.T) {
+	got := getQueryForWorkloadsBetween(testDiffStart, testDiffEnd)
+	assert.Contains(t, got, `replicaset(func: has(isReplicaset)) @filter(le(startTime, "`+testDiffEnd+`") AND (NOT has(endTime) OR gt(endTime, "`+testDiffStart+`")) AND NOT has(deployment))`)
+	assert.Contains(t, got, `before: ~deployment @filter(has(isPod) AND le(startTime, "`+testDiffStart+`")`)
+}
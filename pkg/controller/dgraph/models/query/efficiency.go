@@ -0,0 +1,171 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"context"
+	"sort"
+	"time"
+)
+
+// Efficiency is the cost of cpu and memory requested(allocated) by pods compared to the cost of their usage,
+// idle cost is the allocated cost which is not used
+type Efficiency struct {
+	Pods           int     `json:"pods"`
+	AllocatedCost  float64 `json:"allocatedCost"`
+	UtilizedCost   float64 `json:"utilizedCost"`
+	IdleCost       float64 `json:"idleCost"`
+	IdlePercentage float64 `json:"idlePercentage"`
+}
+
+// WorkloadEfficiency is the efficiency of pods of a workload, the pod itself if it has no workload
+type WorkloadEfficiency struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+	Efficiency
+}
+
+// NamespaceEfficiency is the efficiency of pods of a namespace, workloads are sorted by idle cost
+type NamespaceEfficiency struct {
+	Name string `json:"name"`
+	Efficiency
+	Workloads []WorkloadEfficiency `json:"workloads"`
+}
+
+// CostEfficiency is the efficiency of pods with usage samples in the time range per namespace, namespaces are
+// sorted by idle cost
+type CostEfficiency struct {
+	Efficiency
+	Namespaces []NamespaceEfficiency `json:"namespaces"`
+}
+
+// CostEfficiencyWrapper structure
+type CostEfficiencyWrapper struct {
+	Data CostEfficiency `json:"data"`
+}
+
+type efficiencyPod struct {
+	triggeringPod
+	CPUCost            float64 `json:"cpuCost"`
+	MemoryCost         float64 `json:"memoryCost"`
+	UtilizedCPUCost    float64 `json:"utilizedCPUCost"`
+	UtilizedMemoryCost float64 `json:"utilizedMemoryCost"`
+}
+
+// RetrieveCostEfficiency returns allocated, utilized and idle cost of cpu and memory of pods per namespace and
+// workload. Costs are computed over the time range, month to date by default. Only pods with usage samples are
+// considered, so it needs pod usage collection.
+func RetrieveCostEfficiency(ctx context.Context, timeRange TimeRange) CostEfficiencyWrapper {
+	root := struct {
+		Pods []efficiencyPod `json:"pods"`
+	}{}
+	err := executeQuery(ctx, getQueryForEfficiencyPods(timeRange), &root)
+	if err != nil {
+		log.Errorf("unable to retrieve usage of pods, err: %v", err)
+		return CostEfficiencyWrapper{}
+	}
+	return CostEfficiencyWrapper{Data: computeCostEfficiency(root.Pods)}
+}
+
+func getQueryForEfficiencyPods(timeRange TimeRange) string {
+	filterRange := timeRange
+	if filterRange.Start == "" {
+		end, err := time.Parse(time.RFC3339, timeRange.End)
+		if err != nil {
+			end = time.Now()
+		}
+		filterRange.Start = time.Date(end.Year(), end.Month(), 1, 0, 0, 0, 0, time.Local).Format(time.RFC3339)
+	}
+	owners := ``
+	for _, ownerType := range podOwnerPredicates {
+		owners += `
+			` + ownerType + ` {
+				name
+			}`
+	}
+	return `{
+		pods(func: has(isPod)) @filter(has(usageSamples)` + getTimeRangeFilter(filterRange) + `) {
+			` + getQueryForMetricsComputationWithAliasInRange("Efficiency", timeRange) + `
+			namespace {
+				name
+			}` + owners + `
+		}
+	}`
+}
+
+// computeCostEfficiency sums adjusted allocated and utilized costs of pods per namespace and workload
+func computeCostEfficiency(pods []efficiencyPod) CostEfficiency {
+	efficiency := CostEfficiency{Namespaces: []NamespaceEfficiency{}}
+	namespaces := make(map[string]*NamespaceEfficiency)
+	workloads := make(map[string]map[string]*WorkloadEfficiency)
+	for _, pod := range pods {
+		name, workloadType, namespaceName := getPodOwner(pod.triggeringPod)
+		if _, isPresent := namespaces[namespaceName]; !isPresent {
+			namespaces[namespaceName] = &NamespaceEfficiency{Name: namespaceName}
+			workloads[namespaceName] = make(map[string]*WorkloadEfficiency)
+		}
+		key := workloadType + "/" + name
+		if _, isPresent := workloads[namespaceName][key]; !isPresent {
+			workloads[namespaceName][key] = &WorkloadEfficiency{Name: name, Type: workloadType}
+		}
+
+		allocated := adjustCost(CostContext{PodType, pod.Name, CPUCostType}, pod.CPUCost) +
+			adjustCost(CostContext{PodType, pod.Name, MemoryCostType}, pod.MemoryCost)
+		utilized := adjustCost(CostContext{PodType, pod.Name, CPUCostType}, pod.UtilizedCPUCost) +
+			adjustCost(CostContext{PodType, pod.Name, MemoryCostType}, pod.UtilizedMemoryCost)
+		for _, total := range []*Efficiency{&efficiency.Efficiency, &namespaces[namespaceName].Efficiency, &workloads[namespaceName][key].Efficiency} {
+			total.Pods++
+			total.AllocatedCost += allocated
+			total.UtilizedCost += utilized
+		}
+	}
+
+	for namespaceName, namespace := range namespaces {
+		for _, workload := range workloads[namespaceName] {
+			workload.computeIdleCost()
+			namespace.Workloads = append(namespace.Workloads, *workload)
+		}
+		sort.SliceStable(namespace.Workloads, func(i, j int) bool {
+			if namespace.Workloads[i].IdleCost != namespace.Workloads[j].IdleCost {
+				return namespace.Workloads[i].IdleCost > namespace.Workloads[j].IdleCost
+			}
+			return namespace.Workloads[i].Name < namespace.Workloads[j].Name
+		})
+		namespace.computeIdleCost()
+		efficiency.Namespaces = append(efficiency.Namespaces, *namespace)
+	}
+	sort.SliceStable(efficiency.Namespaces, func(i, j int) bool {
+		if efficiency.Namespaces[i].IdleCost != efficiency.Namespaces[j].IdleCost {
+			return efficiency.Namespaces[i].IdleCost > efficiency.Namespaces[j].IdleCost
+		}
+		return efficiency.Namespaces[i].Name < efficiency.Namespaces[j].Name
+	})
+	efficiency.computeIdleCost()
+	return efficiency
+}
+
+// computeIdleCost sets idle cost and its percentage of allocated cost, idle cost is 0 if usage exceeds requests
+func (e *Efficiency) computeIdleCost() {
+	e.IdleCost = e.AllocatedCost - e.UtilizedCost
+	if e.IdleCost < 0 {
+		e.IdleCost = 0
+	}
+	if e.AllocatedCost > 0 {
+		e.IdlePercentage = e.IdleCost / e.AllocatedCost * 100
+	}
+}
@@ -0,0 +1,75 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func mockDgraphForEfficiencyPods() {
+	executeQuery = func(ctx context.Context, query string, root interface{}) error {
+		if !strings.Contains(query, `@filter(has(usageSamples)`) {
+			return json.Unmarshal([]byte(`{"pods": []}`), root)
+		}
+		return json.Unmarshal([]byte(`{"pods": [
+			{"name": "pod-web-1", "cpuCost": 4, "memoryCost": 1, "utilizedCPUCost": 1, "utilizedMemoryCost": 0.5,
+				"namespace": {"name": "namespace-shop"}, "replicaset": {"name": "replicaset-web-5d8f"}, "deployment": {"name": "deployment-web"}},
+			{"name": "pod-web-2", "cpuCost": 4, "memoryCost": 1, "utilizedCPUCost": 1.5, "utilizedMemoryCost": 0.5,
+				"namespace": {"name": "namespace-shop"}, "replicaset": {"name": "replicaset-web-5d8f"}, "deployment": {"name": "deployment-web"}},
+			{"name": "pod-db-0", "cpuCost": 2, "utilizedCPUCost": 2.5, "namespace": {"name": "namespace-shop"}, "statefulset": {"name": "statefulset-db"}},
+			{"name": "pod-debug", "cpuCost": 3, "namespace": {"name": "namespace-etl"}}
+		]}`), root)
+	}
+}
+
+// TestRetrieveCostEfficiency ...
+func TestRetrieveCostEfficiency(t *testing.T) {
+	mockDgraphForEfficiencyPods()
+	got := RetrieveCostEfficiency(context.Background(), TimeRange{}).Data
+	assert.Equal(t, Efficiency{Pods: 4, AllocatedCost: 15, UtilizedCost: 6, IdleCost: 9, IdlePercentage: 60}, got.Efficiency)
+	assert.Equal(t, 2, len(got.Namespaces))
+
+	assert.Equal(t, NamespaceEfficiency{
+		Name:       "namespace-shop",
+		Efficiency: Efficiency{Pods: 3, AllocatedCost: 12, UtilizedCost: 6, IdleCost: 6, IdlePercentage: 50},
+		Workloads: []WorkloadEfficiency{
+			{Name: "deployment-web", Type: DeploymentType, Efficiency: Efficiency{Pods: 2, AllocatedCost: 10, UtilizedCost: 3.5, IdleCost: 6.5, IdlePercentage: 65}},
+			{Name: "statefulset-db", Type: StatefulsetType, Efficiency: Efficiency{Pods: 1, AllocatedCost: 2, UtilizedCost: 2.5}},
+		},
+	}, got.Namespaces[0])
+	assert.Equal(t, NamespaceEfficiency{
+		Name:       "namespace-etl",
+		Efficiency: Efficiency{Pods: 1, AllocatedCost: 3, IdleCost: 3, IdlePercentage: 100},
+		Workloads: []WorkloadEfficiency{
+			{Name: "pod-debug", Type: PodType, Efficiency: Efficiency{Pods: 1, AllocatedCost: 3, IdleCost: 3, IdlePercentage: 100}},
+		},
+	}, got.Namespaces[1])
+}
+
+// TestGetQueryForEfficiencyPodsInRange ...
+func TestGetQueryForEfficiencyPodsInRange(t *testing.T) {
+	query := getQueryForEfficiencyPods(TimeRange{Start: "2018-10-01T00:00:00Z", End: "2018-10-31T00:00:00Z"})
+	assert.Contains(t, query, `le(startTime, "2018-10-31T00:00:00Z")`)
+	assert.Contains(t, query, `gt(endTime, "2018-10-01T00:00:00Z")`)
+	assert.Contains(t, query, `utilizedCPUCost: math(cpuUsageEfficiency * durationInHoursEfficiency * pricePerCPUEfficiency)`)
+}
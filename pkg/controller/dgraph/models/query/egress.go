@@ -0,0 +1,265 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vmware/purser/pkg/controller/dgraph/models"
+	qb "github.com/vmware/purser/pkg/querybuilder"
+)
+
+// Classes of traffic of pods, traffic between pods is intra-zone if their nodes are in the same zone(or a zone is
+// unknown) and cross-zone otherwise, traffic with destinations outside the cluster is external
+const (
+	IntraZoneTraffic = "intraZone"
+	CrossZoneTraffic = "crossZone"
+	ExternalTraffic  = "external"
+)
+
+// Default prices of traffic in USD per GB, typical data transfer prices of cloud providers
+const (
+	DefaultIntraZoneEgressPrice = 0.0
+	DefaultCrossZoneEgressPrice = 0.01
+	DefaultExternalEgressPrice  = 0.09
+)
+
+// EgressTrafficMaxAge is the age of the end of the last telemetry window after which its traffic is not counted,
+// discovery stores traffic of the last hour every 59 minutes
+const EgressTrafficMaxAge = 90 * time.Minute
+
+const bytesInGB = 1000 * 1000 * 1000
+
+var (
+	egressMu     sync.RWMutex
+	egressPrices = map[string]float64{
+		IntraZoneTraffic: DefaultIntraZoneEgressPrice,
+		CrossZoneTraffic: DefaultCrossZoneEgressPrice,
+		ExternalTraffic:  DefaultExternalEgressPrice,
+	}
+)
+
+// SetEgressPricing sets prices in USD per GB of intra-zone, cross-zone and external traffic of pods.
+// Negative prices are ignored and defaults are retained.
+func SetEgressPricing(intraZone, crossZone, external float64) {
+	egressMu.Lock()
+	defer egressMu.Unlock()
+	for class, price := range map[string]float64{IntraZoneTraffic: intraZone, CrossZoneTraffic: crossZone, ExternalTraffic: external} {
+		if price >= 0 {
+			egressPrices[class] = price
+		}
+	}
+	log.Infof("egress pricing per GB, intra zone: %v, cross zone: %v, external: %v",
+		egressPrices[IntraZoneTraffic], egressPrices[CrossZoneTraffic], egressPrices[ExternalTraffic])
+}
+
+func getEgressPrice(class string) float64 {
+	egressMu.RLock()
+	defer egressMu.RUnlock()
+	return egressPrices[class]
+}
+
+// EgressTraffic is the traffic(GB) of a class in the last telemetry window with its cost
+type EgressTraffic struct {
+	GB   float64 `json:"gb"`
+	Cost float64 `json:"cost"`
+}
+
+// NamespaceEgressCost is the traffic of pods of a namespace in the last telemetry window per class. HourlyCost is the
+// cost of the traffic and MonthlyCost its estimate over a month.
+type NamespaceEgressCost struct {
+	Name        string        `json:"name"`
+	IntraZone   EgressTraffic `json:"intraZone"`
+	CrossZone   EgressTraffic `json:"crossZone"`
+	External    EgressTraffic `json:"external"`
+	HourlyCost  float64       `json:"hourlyCost"`
+	MonthlyCost float64       `json:"monthlyCost"`
+}
+
+// EgressCosts structure, Name is the namespace if costs are restricted to it, otherwise cluster
+type EgressCosts struct {
+	Name        string                `json:"name"`
+	HourlyCost  float64               `json:"hourlyCost"`
+	MonthlyCost float64               `json:"monthlyCost"`
+	Namespaces  []NamespaceEgressCost `json:"namespaces"`
+}
+
+// EgressCostsWrapper structure
+type EgressCostsWrapper struct {
+	Data EgressCosts `json:"data"`
+}
+
+type egressNode struct {
+	Zone string `json:"zone"`
+}
+
+type egressPod struct {
+	Name              string      `json:"name"`
+	ExternalBytes     float64     `json:"externalBytes"`
+	ExternalWindowEnd string      `json:"externalWindowEnd"`
+	Node              *egressNode `json:"node"`
+	Namespace         *struct {
+		Name string `json:"name"`
+	} `json:"namespace"`
+	Pods []struct {
+		Bytes     float64     `json:"pod|bytes"`
+		WindowEnd string      `json:"pod|windowEnd"`
+		Node      *egressNode `json:"node"`
+	} `json:"pod"`
+}
+
+// RetrieveEgressCosts estimates cost of network traffic sent and received by live pods per namespace from bytes of
+// their interactions in the last telemetry window, highest cost first. Traffic is attributed to the namespace of the
+// source pod. Costs are restricted to pods of the namespace if name is a namespace. Bytes of interactions which are
+// not reported in the last window, or of a window older than EgressTrafficMaxAge, are not counted.
+func RetrieveEgressCosts(ctx context.Context, name string) EgressCostsWrapper {
+	if name != All && !strings.HasPrefix(name, NamespaceType+"-") {
+		log.Errorf("unable to retrieve egress costs, %s is not a namespace", name)
+		return EgressCostsWrapper{}
+	}
+	type root struct {
+		Pods []egressPod `json:"pods"`
+	}
+	newRoot := root{}
+	query, vars := getQueryForEgressCosts(name)
+	err := executeQueryWithVars(ctx, query, vars, &newRoot)
+	if err != nil {
+		log.Errorf("unable to retrieve egress costs, err: %v", err)
+		return EgressCostsWrapper{}
+	}
+	return EgressCostsWrapper{Data: computeEgressCosts(name, newRoot.Pods, time.Now())}
+}
+
+// computeEgressCosts adds traffic of the last window of each pod to its namespace by class
+func computeEgressCosts(name string, pods []egressPod, now time.Time) EgressCosts {
+	data := EgressCosts{Name: name, Namespaces: []NamespaceEgressCost{}}
+	if data.Name == All {
+		data.Name = "cluster"
+	}
+	windowEnd := getLastWindowEnd(pods)
+	if windowEnd.IsZero() || now.Sub(windowEnd) > EgressTrafficMaxAge {
+		return data
+	}
+	costs := make(map[string]*NamespaceEgressCost)
+	for _, pod := range pods {
+		if pod.Namespace == nil {
+			continue
+		}
+		if _, isPresent := costs[pod.Namespace.Name]; !isPresent {
+			costs[pod.Namespace.Name] = &NamespaceEgressCost{Name: pod.Namespace.Name}
+		}
+		cost := costs[pod.Namespace.Name]
+		if isInWindow(pod.ExternalWindowEnd, windowEnd) {
+			cost.External.GB += pod.ExternalBytes / bytesInGB
+		}
+		for _, destination := range pod.Pods {
+			if !isInWindow(destination.WindowEnd, windowEnd) {
+				continue
+			}
+			if getTrafficClass(pod.Node, destination.Node) == CrossZoneTraffic {
+				cost.CrossZone.GB += destination.Bytes / bytesInGB
+			} else {
+				cost.IntraZone.GB += destination.Bytes / bytesInGB
+			}
+		}
+	}
+
+	for _, cost := range costs {
+		if cost.IntraZone.GB+cost.CrossZone.GB+cost.External.GB == 0 {
+			continue
+		}
+		cost.IntraZone.Cost = cost.IntraZone.GB * getEgressPrice(IntraZoneTraffic)
+		cost.CrossZone.Cost = cost.CrossZone.GB * getEgressPrice(CrossZoneTraffic)
+		cost.External.Cost = cost.External.GB * getEgressPrice(ExternalTraffic)
+		cost.HourlyCost = cost.IntraZone.Cost + cost.CrossZone.Cost + cost.External.Cost
+		cost.MonthlyCost = cost.HourlyCost * models.HoursInMonth
+		data.HourlyCost += cost.HourlyCost
+		data.MonthlyCost += cost.MonthlyCost
+		data.Namespaces = append(data.Namespaces, *cost)
+	}
+	sort.Slice(data.Namespaces, func(i, j int) bool {
+		if data.Namespaces[i].HourlyCost != data.Namespaces[j].HourlyCost {
+			return data.Namespaces[i].HourlyCost > data.Namespaces[j].HourlyCost
+		}
+		return data.Namespaces[i].Name < data.Namespaces[j].Name
+	})
+	return data
+}
+
+// getLastWindowEnd returns the end of the last telemetry window in which traffic of the pods is stored, zero time if
+// none is stored
+func getLastWindowEnd(pods []egressPod) time.Time {
+	last := time.Time{}
+	setLast := func(value string) {
+		if end, err := time.Parse(time.RFC3339, value); err == nil && end.After(last) {
+			last = end
+		}
+	}
+	for _, pod := range pods {
+		setLast(pod.ExternalWindowEnd)
+		for _, destination := range pod.Pods {
+			setLast(destination.WindowEnd)
+		}
+	}
+	return last
+}
+
+// isInWindow returns whether traffic stored with value as end of its window is of the window ending at windowEnd
+func isInWindow(value string, windowEnd time.Time) bool {
+	end, err := time.Parse(time.RFC3339, value)
+	return err == nil && end.Equal(windowEnd)
+}
+
+// getTrafficClass returns the class of traffic between pods on the nodes
+func getTrafficClass(source, destination *egressNode) string {
+	if source == nil || destination == nil || source.Zone == "" || destination.Zone == "" || source.Zone == destination.Zone {
+		return IntraZoneTraffic
+	}
+	return CrossZoneTraffic
+}
+
+func getQueryForEgressCosts(name string) (string, qb.Vars) {
+	namespace := ""
+	if name != All {
+		namespace = name
+	}
+	vars := getNamespaceVars(nil, namespace)
+	return vars.Declaration() + ` {
+		` + getNamespaceVar(namespace) + `
+		pods(func: has(isPod)) @filter(NOT has(endTime) AND (has(pod) OR has(externalBytes))` + getNamespaceFilter(namespace) + `) {
+			name
+			externalBytes
+			externalWindowEnd
+			node {
+				zone
+			}
+			namespace {
+				name
+			}
+			pod @facets(bytes, windowEnd) {
+				node {
+					zone
+				}
+			}
+		}
+	}`, vars
+}
@@ -0,0 +1,99 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func mockDgraphForEgressCosts() {
+	windowEnd := time.Now().Add(-10 * time.Minute).Format(time.RFC3339)
+	previousWindowEnd := time.Now().Add(-69 * time.Minute).Format(time.RFC3339)
+	executeQueryWithVars = func(ctx context.Context, query string, vars map[string]string, root interface{}) error {
+		return json.Unmarshal([]byte(`{"pods": [
+			{"name": "pod-web", "externalBytes": 2000000000, "externalWindowEnd": "`+windowEnd+`", "node": {"zone": "us-east-1a"}, "namespace": {"name": "namespace-shop"},
+				"pod": [
+					{"pod|bytes": 1000000000, "pod|windowEnd": "`+windowEnd+`", "node": {"zone": "us-east-1a"}},
+					{"pod|bytes": 3000000000, "pod|windowEnd": "`+windowEnd+`", "node": {"zone": "us-east-1b"}},
+					{"pod|bytes": 5000000000, "pod|windowEnd": "`+previousWindowEnd+`", "node": {"zone": "us-east-1b"}}
+				]},
+			{"name": "pod-cart", "node": {"zone": "us-east-1b"}, "namespace": {"name": "namespace-shop"},
+				"pod": [{"pod|bytes": 1000000000, "pod|windowEnd": "`+windowEnd+`", "node": {}}]},
+			{"name": "pod-report", "externalBytes": 1000000000, "externalWindowEnd": "`+windowEnd+`", "namespace": {"name": "namespace-batch"}},
+			{"name": "pod-idle", "externalBytes": 1000000000, "externalWindowEnd": "`+previousWindowEnd+`", "namespace": {"name": "namespace-idle"}},
+			{"name": "pod-orphan", "externalBytes": 1000000000, "externalWindowEnd": "`+windowEnd+`"}
+		]}`), root)
+	}
+}
+
+// TestRetrieveEgressCosts ...
+func TestRetrieveEgressCosts(t *testing.T) {
+	mockDgraphForEgressCosts()
+	SetEgressPricing(0.001, 0.01, 0.09)
+	defer SetEgressPricing(DefaultIntraZoneEgressPrice, DefaultCrossZoneEgressPrice, DefaultExternalEgressPrice)
+	got := RetrieveEgressCosts(context.Background(), All).Data
+
+	assert.Equal(t, "cluster", got.Name)
+	assert.Equal(t, 2, len(got.Namespaces))
+	shop := got.Namespaces[0]
+	assert.Equal(t, "namespace-shop", shop.Name)
+	assert.InDelta(t, 2, shop.IntraZone.GB, 0.0001)
+	assert.InDelta(t, 0.002, shop.IntraZone.Cost, 0.0001)
+	assert.InDelta(t, 3, shop.CrossZone.GB, 0.0001)
+	assert.InDelta(t, 0.03, shop.CrossZone.Cost, 0.0001)
+	assert.InDelta(t, 2, shop.External.GB, 0.0001)
+	assert.InDelta(t, 0.18, shop.External.Cost, 0.0001)
+	assert.InDelta(t, 0.212, shop.HourlyCost, 0.0001)
+	assert.InDelta(t, 0.212*720, shop.MonthlyCost, 0.001)
+	assert.Equal(t, "namespace-batch", got.Namespaces[1].Name)
+	assert.InDelta(t, 0.302, got.HourlyCost, 0.0001)
+}
+
+// TestRetrieveEgressCostsOfNamespace ...
+func TestRetrieveEgressCostsOfNamespace(t *testing.T) {
+	query, vars := getQueryForEgressCosts("namespace-shop")
+	assert.Equal(t, "namespace-shop", vars["$namespace"])
+	assert.Contains(t, query, "uid(namespaceResources)")
+	assert.Contains(t, query, "pod @facets(bytes, windowEnd)")
+
+	assert.Equal(t, EgressCostsWrapper{}, RetrieveEgressCosts(context.Background(), "pod-web"))
+}
+
+// TestComputeEgressCostsOfStaleWindow ...
+func TestComputeEgressCostsOfStaleWindow(t *testing.T) {
+	windowEnd := time.Date(2026, 10, 14, 10, 0, 0, 0, time.UTC)
+	pods := []egressPod{{
+		Name:              "pod-web",
+		ExternalBytes:     1000000000,
+		ExternalWindowEnd: windowEnd.Format(time.RFC3339),
+		Namespace: &struct {
+			Name string `json:"name"`
+		}{Name: "namespace-shop"},
+	}}
+	got := computeEgressCosts(All, pods, windowEnd.Add(EgressTrafficMaxAge))
+	assert.Equal(t, 1, len(got.Namespaces))
+	assert.InDelta(t, 1, got.Namespaces[0].External.GB, 0.0001)
+
+	got = computeEgressCosts(All, pods, windowEnd.Add(EgressTrafficMaxAge+time.Minute))
+	assert.Equal(t, EgressCosts{Name: "cluster", Namespaces: []NamespaceEgressCost{}}, got)
+}
@@ -0,0 +1,126 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/vmware/purser/pkg/controller/dgraph/models"
+)
+
+// WorkloadEstimate is the projected cost of the pods of a workload of a manifest. Pods are priced with cpu and
+// memory prices of the instance type of the cluster cheapest per CPU that fits them, default prices if none fits.
+type WorkloadEstimate struct {
+	Name         string  `json:"name"`
+	Kind         string  `json:"kind"`
+	Replicas     int     `json:"replicas"`
+	InstanceType string  `json:"instanceType,omitempty"`
+	CPU          float64 `json:"cpu"`
+	Memory       float64 `json:"memory"`
+	GPU          float64 `json:"gpu"`
+	HourlyCost   float64 `json:"hourlyCost"`
+	MonthlyCost  float64 `json:"monthlyCost"`
+}
+
+// ManifestEstimate is the projected cost of workloads of a manifest on the cluster. RequiredCapacity is the node
+// capacity their pods need if the cluster has no free capacity, pods of DaemonSets run on existing nodes.
+type ManifestEstimate struct {
+	Nodes            int                `json:"nodes"`
+	CPU              float64            `json:"cpu"`
+	Memory           float64            `json:"memory"`
+	GPU              float64            `json:"gpu"`
+	HourlyCost       float64            `json:"hourlyCost"`
+	MonthlyCost      float64            `json:"monthlyCost"`
+	RequiredCapacity CapacityEstimate   `json:"requiredCapacity"`
+	Workloads        []WorkloadEstimate `json:"workloads"`
+}
+
+// ManifestEstimateWrapper structure
+type ManifestEstimateWrapper struct {
+	Data ManifestEstimate `json:"data"`
+}
+
+// EstimateManifestCost returns the projected monthly cost of the workloads on live nodes of the cluster
+func EstimateManifestCost(ctx context.Context, workloads []models.ManifestWorkload) ManifestEstimateWrapper {
+	root := struct {
+		Nodes []pendingNode `json:"nodes"`
+	}{}
+	err := executeQuery(ctx, getQueryForEstimateNodes(), &root)
+	if err != nil {
+		log.Errorf("unable to retrieve nodes, err: %v", err)
+		return ManifestEstimateWrapper{}
+	}
+	return ManifestEstimateWrapper{Data: computeManifestEstimate(workloads, root.Nodes)}
+}
+
+func getQueryForEstimateNodes() string {
+	return `{
+		nodes(func: has(isNode)) @filter((NOT has(endTime)) AND (NOT has(isVirtual)) AND gt(cpuCapacity, 0)) {
+			name
+			instanceType
+			cpuCapacity
+			memoryCapacity
+			cpuPrice
+			memoryPrice
+		}
+	}`
+}
+
+// computeManifestEstimate prices pods of each workload on the node template fitting them and packs pods which
+// need new nodes on the template fitting the largest of them
+func computeManifestEstimate(workloads []models.ManifestWorkload, nodes []pendingNode) ManifestEstimate {
+	data := ManifestEstimate{Nodes: len(nodes), Workloads: []WorkloadEstimate{}}
+	pods := []packingPod{}
+	for _, workload := range workloads {
+		replicas := workload.Replicas
+		if workload.PerNode {
+			replicas = len(nodes)
+		}
+		pod := packingPod{name: workload.Name, cpu: workload.CPURequest, memory: workload.MemoryRequest}
+		estimate := WorkloadEstimate{
+			Name:     workload.Name,
+			Kind:     workload.Kind,
+			Replicas: replicas,
+			CPU:      workload.CPURequest * float64(replicas),
+			Memory:   workload.MemoryRequest * float64(replicas),
+			GPU:      workload.GPURequest * float64(replicas),
+		}
+		cpuPrice, memoryPrice := models.DefaultCPUCostInFloat64, models.DefaultMemCostInFloat64
+		if template, isFound := getNodeTemplate([]packingPod{pod}, nodes); isFound {
+			estimate.InstanceType = template.InstanceType
+			cpuPrice, memoryPrice = template.CPUPrice, template.MemoryPrice
+		}
+		estimate.HourlyCost = estimate.CPU*cpuPrice + estimate.Memory*memoryPrice + estimate.GPU*workload.GPUPrice
+		estimate.MonthlyCost = estimate.HourlyCost * models.HoursInMonth
+
+		data.CPU += estimate.CPU
+		data.Memory += estimate.Memory
+		data.GPU += estimate.GPU
+		data.HourlyCost += estimate.HourlyCost
+		data.MonthlyCost += estimate.MonthlyCost
+		data.Workloads = append(data.Workloads, estimate)
+		if !workload.PerNode {
+			for index := 0; index < replicas; index++ {
+				pods = append(pods, packingPod{name: workload.Name + "-" + strconv.Itoa(index), cpu: pod.cpu, memory: pod.memory})
+			}
+		}
+	}
+	estimateRequiredCapacity(&data.RequiredCapacity, pods, nodes)
+	return data
+}
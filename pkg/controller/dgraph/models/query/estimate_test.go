@@ -0,0 +1,71 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/vmware/purser/pkg/controller/dgraph/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func mockDgraphForEstimateNodes() {
+	executeQuery = func(ctx context.Context, query string, root interface{}) error {
+		return json.Unmarshal([]byte(`{"nodes": [
+			{"name": "node-1", "instanceType": "m5.large", "cpuCapacity": 2, "memoryCapacity": 8, "cpuPrice": 0.03, "memoryPrice": 0.004},
+			{"name": "node-2", "instanceType": "m5.large", "cpuCapacity": 2, "memoryCapacity": 8, "cpuPrice": 0.03, "memoryPrice": 0.004},
+			{"name": "node-3", "instanceType": "m5.2xlarge", "cpuCapacity": 8, "memoryCapacity": 32, "cpuPrice": 0.035, "memoryPrice": 0.005}
+		]}`), root)
+	}
+}
+
+// TestEstimateManifestCost ...
+func TestEstimateManifestCost(t *testing.T) {
+	mockDgraphForEstimateNodes()
+	workloads := []models.ManifestWorkload{
+		{Name: "web", Kind: "Deployment", Replicas: 3, CPURequest: 1, MemoryRequest: 2},
+		{Name: "agent", Kind: "DaemonSet", PerNode: true, CPURequest: 0.5, MemoryRequest: 1},
+		{Name: "train", Kind: "Job", Replicas: 1, CPURequest: 4, MemoryRequest: 16, GPURequest: 1, GPUPrice: 1},
+	}
+	got := EstimateManifestCost(context.Background(), workloads).Data
+
+	assert.Equal(t, 3, got.Nodes)
+	web := got.Workloads[0]
+	assert.Equal(t, 3, web.Replicas)
+	assert.Equal(t, "m5.large", web.InstanceType)
+	assert.InDelta(t, 0.114, web.HourlyCost, 1e-9)
+	assert.InDelta(t, 82.08, web.MonthlyCost, 1e-9)
+	assert.Equal(t, 3, got.Workloads[1].Replicas)
+	assert.Equal(t, "m5.2xlarge", got.Workloads[2].InstanceType)
+	assert.Equal(t, 1.0, got.GPU)
+	assert.Equal(t, got.Workloads[0].HourlyCost+got.Workloads[1].HourlyCost+got.Workloads[2].HourlyCost, got.HourlyCost)
+
+	// the largest pod needs the 2xlarge instance type, which fits all 4 pods of the deployment and the job
+	assert.Equal(t, "m5.2xlarge", got.RequiredCapacity.InstanceType)
+	assert.Equal(t, 1, got.RequiredCapacity.Nodes)
+}
+
+// TestEstimateManifestCostWithoutNodes ...
+func TestEstimateManifestCostWithoutNodes(t *testing.T) {
+	got := computeManifestEstimate([]models.ManifestWorkload{{Name: "web", Kind: "Pod", Replicas: 1, CPURequest: 1, MemoryRequest: 1}}, nil)
+	assert.Equal(t, models.DefaultCPUCostInFloat64+models.DefaultMemCostInFloat64, got.HourlyCost)
+	assert.Equal(t, 1, got.RequiredCapacity.UnplaceablePods)
+}
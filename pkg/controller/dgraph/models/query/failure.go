@@ -0,0 +1,183 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// crashLoopRestarts is how many times containers of a pod have to restart for the pod to be crash looping
+const crashLoopRestarts = 3
+
+// FailureWorkloadCost is the cost of failed and crash looping pods of a workload, the pod itself if it has no workload
+type FailureWorkloadCost struct {
+	Name             string  `json:"name"`
+	Type             string  `json:"type"`
+	FailedPods       int     `json:"failedPods"`
+	CrashLoopingPods int     `json:"crashLoopingPods"`
+	Restarts         int     `json:"restarts"`
+	Cost             float64 `json:"cost"`
+}
+
+// FailureNamespaceCost is the cost consumed by failed and crash looping pods of a namespace, workloads are sorted by cost
+type FailureNamespaceCost struct {
+	Name             string                `json:"name"`
+	FailedPods       int                   `json:"failedPods"`
+	FailedCost       float64               `json:"failedCost"`
+	CrashLoopingPods int                   `json:"crashLoopingPods"`
+	CrashLoopingCost float64               `json:"crashLoopingCost"`
+	Restarts         int                   `json:"restarts"`
+	Cost             float64               `json:"cost"`
+	Workloads        []FailureWorkloadCost `json:"workloads"`
+}
+
+// FailureCosts is the cost of failed and crash looping pods in the time range per namespace, namespaces are
+// sorted by cost
+type FailureCosts struct {
+	Cost       float64                `json:"cost"`
+	Namespaces []FailureNamespaceCost `json:"namespaces"`
+}
+
+// FailureCostsWrapper structure
+type FailureCostsWrapper struct {
+	Data FailureCosts `json:"data"`
+}
+
+type failedPod struct {
+	triggeringPod
+	Phase                string  `json:"phase"`
+	Restarts             int     `json:"restarts"`
+	CPUCost              float64 `json:"cpuCost"`
+	MemoryCost           float64 `json:"memoryCost"`
+	StorageCost          float64 `json:"storageCost"`
+	GPUCost              float64 `json:"gpuCost"`
+	ExtendedResourceCost float64 `json:"extendedResourceCost"`
+	BandwidthCost        float64 `json:"bandwidthCost"`
+}
+
+// RetrieveFailureCosts returns the cost of pods which failed or whose containers restarted at least 3 times,
+// per namespace and workload. Costs are computed over the time range, month to date by default.
+func RetrieveFailureCosts(ctx context.Context, timeRange TimeRange) FailureCostsWrapper {
+	root := struct {
+		Pods []failedPod `json:"pods"`
+	}{}
+	err := executeQuery(ctx, getQueryForFailedPods(timeRange), &root)
+	if err != nil {
+		log.Errorf("unable to retrieve failed pods, err: %v", err)
+		return FailureCostsWrapper{}
+	}
+	return FailureCostsWrapper{Data: computeFailureCosts(root.Pods)}
+}
+
+func getQueryForFailedPods(timeRange TimeRange) string {
+	filterRange := timeRange
+	if filterRange.Start == "" {
+		end, err := time.Parse(time.RFC3339, timeRange.End)
+		if err != nil {
+			end = time.Now()
+		}
+		filterRange.Start = time.Date(end.Year(), end.Month(), 1, 0, 0, 0, 0, time.Local).Format(time.RFC3339)
+	}
+	owners := ``
+	for _, ownerType := range podOwnerPredicates {
+		owners += `
+			` + ownerType + ` {
+				name
+			}`
+	}
+	return `{
+		pods(func: has(isPod)) @filter((eq(phase, "Failed") OR ge(restarts, ` + strconv.Itoa(crashLoopRestarts) + `))` + getTimeRangeFilter(filterRange) + `) {
+			` + getQueryForMetricsComputationWithAliasInRange("Failure", timeRange) + `
+			phase
+			restarts
+			namespace {
+				name
+			}` + owners + `
+		}
+	}`
+}
+
+// computeFailureCosts sums adjusted cost of failed and crash looping pods per namespace and workload, failed pods
+// which also restarted are counted as failed
+func computeFailureCosts(pods []failedPod) FailureCosts {
+	costs := FailureCosts{Namespaces: []FailureNamespaceCost{}}
+	namespaces := make(map[string]*FailureNamespaceCost)
+	workloads := make(map[string]map[string]*FailureWorkloadCost)
+	for _, pod := range pods {
+		name, workloadType, namespaceName := getPodOwner(pod.triggeringPod)
+		if _, isPresent := namespaces[namespaceName]; !isPresent {
+			namespaces[namespaceName] = &FailureNamespaceCost{Name: namespaceName}
+			workloads[namespaceName] = make(map[string]*FailureWorkloadCost)
+		}
+		namespace := namespaces[namespaceName]
+		key := workloadType + "/" + name
+		if _, isPresent := workloads[namespaceName][key]; !isPresent {
+			workloads[namespaceName][key] = &FailureWorkloadCost{Name: name, Type: workloadType}
+		}
+		workload := workloads[namespaceName][key]
+
+		cost := getFailedPodCost(pod)
+		if pod.Phase == "Failed" {
+			namespace.FailedPods++
+			namespace.FailedCost += cost
+			workload.FailedPods++
+		} else {
+			namespace.CrashLoopingPods++
+			namespace.CrashLoopingCost += cost
+			workload.CrashLoopingPods++
+		}
+		namespace.Restarts += pod.Restarts
+		namespace.Cost += cost
+		workload.Restarts += pod.Restarts
+		workload.Cost += cost
+		costs.Cost += cost
+	}
+
+	for namespaceName, namespace := range namespaces {
+		for _, workload := range workloads[namespaceName] {
+			namespace.Workloads = append(namespace.Workloads, *workload)
+		}
+		sort.SliceStable(namespace.Workloads, func(i, j int) bool {
+			if namespace.Workloads[i].Cost != namespace.Workloads[j].Cost {
+				return namespace.Workloads[i].Cost > namespace.Workloads[j].Cost
+			}
+			return namespace.Workloads[i].Name < namespace.Workloads[j].Name
+		})
+		costs.Namespaces = append(costs.Namespaces, *namespace)
+	}
+	sort.SliceStable(costs.Namespaces, func(i, j int) bool {
+		if costs.Namespaces[i].Cost != costs.Namespaces[j].Cost {
+			return costs.Namespaces[i].Cost > costs.Namespaces[j].Cost
+		}
+		return costs.Namespaces[i].Name < costs.Namespaces[j].Name
+	})
+	return costs
+}
+
+// getFailedPodCost returns adjusted cost of the pod
+func getFailedPodCost(pod failedPod) float64 {
+	return adjustCost(CostContext{PodType, pod.Name, CPUCostType}, pod.CPUCost) +
+		adjustCost(CostContext{PodType, pod.Name, MemoryCostType}, pod.MemoryCost) +
+		adjustCost(CostContext{PodType, pod.Name, StorageCostType}, pod.StorageCost) +
+		adjustCost(CostContext{PodType, pod.Name, GPUCostType}, pod.GPUCost) +
+		adjustCost(CostContext{PodType, pod.Name, ExtendedResourceCostType}, pod.ExtendedResourceCost) +
+		adjustCost(CostContext{PodType, pod.Name, BandwidthCostType}, pod.BandwidthCost)
+}
@@ -0,0 +1,84 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func mockDgraphForFailedPods() {
+	executeQuery = func(ctx context.Context, query string, root interface{}) error {
+		if !strings.Contains(query, `@filter((eq(phase, "Failed") OR ge(restarts, 3))`) {
+			return json.Unmarshal([]byte(`{"pods": []}`), root)
+		}
+		return json.Unmarshal([]byte(`{"pods": [
+			{"name": "pod-batch-1", "phase": "Failed", "cpuCost": 2, "namespace": {"name": "namespace-etl"}, "job": {"name": "job-batch"}},
+			{"name": "pod-batch-2", "phase": "Failed", "restarts": 1, "cpuCost": 1, "namespace": {"name": "namespace-etl"}, "job": {"name": "job-batch"}},
+			{"name": "pod-web-1", "phase": "Running", "restarts": 12, "cpuCost": 4, "memoryCost": 1, "namespace": {"name": "namespace-shop"},
+				"replicaset": {"name": "replicaset-web-5d8f"}, "deployment": {"name": "deployment-web"}},
+			{"name": "pod-debug", "phase": "Running", "restarts": 3, "cpuCost": 0.5, "namespace": {"name": "namespace-etl"}}
+		]}`), root)
+	}
+}
+
+// TestRetrieveFailureCosts ...
+func TestRetrieveFailureCosts(t *testing.T) {
+	mockDgraphForFailedPods()
+	got := RetrieveFailureCosts(context.Background(), TimeRange{}).Data
+	assert.Equal(t, 8.5, got.Cost)
+	assert.Equal(t, 2, len(got.Namespaces))
+
+	assert.Equal(t, FailureNamespaceCost{
+		Name:             "namespace-shop",
+		CrashLoopingPods: 1,
+		CrashLoopingCost: 5,
+		Restarts:         12,
+		Cost:             5,
+		Workloads: []FailureWorkloadCost{
+			{Name: "deployment-web", Type: DeploymentType, CrashLoopingPods: 1, Restarts: 12, Cost: 5},
+		},
+	}, got.Namespaces[0])
+	assert.Equal(t, FailureNamespaceCost{
+		Name:             "namespace-etl",
+		FailedPods:       2,
+		FailedCost:       3,
+		CrashLoopingPods: 1,
+		CrashLoopingCost: 0.5,
+		Restarts:         4,
+		Cost:             3.5,
+		Workloads: []FailureWorkloadCost{
+			{Name: "job-batch", Type: JobType, FailedPods: 2, Restarts: 1, Cost: 3},
+			{Name: "pod-debug", Type: PodType, CrashLoopingPods: 1, Restarts: 3, Cost: 0.5},
+		},
+	}, got.Namespaces[1])
+}
+
+// TestGetQueryForFailedPodsInRange ...
+func TestGetQueryForFailedPodsInRange(t *testing.T) {
+	query := getQueryForFailedPods(TimeRange{Start: "2018-10-01T00:00:00Z", End: "2018-10-31T00:00:00Z"})
+	assert.Contains(t, query, `le(startTime, "2018-10-31T00:00:00Z")`)
+	assert.Contains(t, query, `gt(endTime, "2018-10-01T00:00:00Z")`)
+
+	monthToDate := getQueryForFailedPods(TimeRange{End: "2018-10-15T00:00:00Z"})
+	assert.Contains(t, monthToDate, `le(startTime, "2018-10-15T00:00:00Z")`)
+}
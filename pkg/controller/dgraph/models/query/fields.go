@@ -0,0 +1,198 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// fieldPattern is a field of responses given by its key(ex: image) or the keys of the objects containing it
+// (ex: labels.value), it matches fields whose path ends with those keys
+type fieldPattern []string
+
+var (
+	fieldPolicyMu sync.RWMutex
+	allowedFields []fieldPattern
+	deniedFields  []fieldPattern
+)
+
+// ConfigureFieldPolicy sets the fields which responses of the API may contain. Denied fields are removed with
+// their content, if allowed fields are given values(strings, numbers, booleans) of other fields are removed too,
+// objects and lists are kept. Fields are keys(ex: image) or keys joined by dots(ex: labels.value) matching the end
+// of the path of a field. Empty lists return all fields.
+func ConfigureFieldPolicy(allowed, denied []string) error {
+	allowedPatterns, err := parseFieldPatterns(allowed)
+	if err != nil {
+		return err
+	}
+	deniedPatterns, err := parseFieldPatterns(denied)
+	if err != nil {
+		return err
+	}
+	fieldPolicyMu.Lock()
+	defer fieldPolicyMu.Unlock()
+	allowedFields, deniedFields = allowedPatterns, deniedPatterns
+	log.Infof("fields of API responses, allowed: %v, denied: %v", allowed, denied)
+	return nil
+}
+
+// HasFieldPolicy returns true if fields of responses are filtered
+func HasFieldPolicy() bool {
+	fieldPolicyMu.RLock()
+	defer fieldPolicyMu.RUnlock()
+	return len(allowedFields) > 0 || len(deniedFields) > 0
+}
+
+// FilterFields removes fields which are not allowed(see ConfigureFieldPolicy) from the JSON response
+func FilterFields(data []byte) ([]byte, error) {
+	fieldPolicyMu.RLock()
+	allowed, denied := allowedFields, deniedFields
+	fieldPolicyMu.RUnlock()
+	if len(allowed) == 0 && len(denied) == 0 {
+		return data, nil
+	}
+
+	var value interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	// numbers are kept as they are instead of being rounded to float64
+	decoder.UseNumber()
+	if err := decoder.Decode(&value); err != nil {
+		return nil, fmt.Errorf("unable to decode response to filter its fields: %v", err)
+	}
+	return json.Marshal(filterFieldValue(value, nil, allowed, denied))
+}
+
+// FilterResult removes fields which are not allowed from the result, a pointer to a value encoded to JSON by the
+// API(ex: a report), for transports which don't write JSON(ex: csv exports and gRPC messages). Removed fields are
+// left empty.
+func FilterResult(result interface{}) error {
+	if !HasFieldPolicy() {
+		return nil
+	}
+	data, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("unable to encode result to filter its fields: %v", err)
+	}
+	data, err = FilterFields(data)
+	if err != nil {
+		return err
+	}
+	value := reflect.ValueOf(result).Elem()
+	value.Set(reflect.Zero(value.Type()))
+	return json.Unmarshal(data, result)
+}
+
+// IsFieldAllowed returns true if responses keep the value(string, number or boolean) at the path of keys
+// (ex: data, rows, cpuCost), ex: to select columns of csv exports
+func IsFieldAllowed(path ...string) bool {
+	fieldPolicyMu.RLock()
+	allowed, denied := allowedFields, deniedFields
+	fieldPolicyMu.RUnlock()
+	return !isFieldDenied(path, denied) && (len(allowed) == 0 || matchesAnyField(path, allowed))
+}
+
+// IsFieldDenied returns true if responses don't contain the field at the path of keys, objects and lists are only
+// removed if they are denied
+func IsFieldDenied(path ...string) bool {
+	fieldPolicyMu.RLock()
+	denied := deniedFields
+	fieldPolicyMu.RUnlock()
+	return isFieldDenied(path, denied)
+}
+
+// isFieldDenied returns true if the field or one of the objects containing it is denied
+func isFieldDenied(path []string, denied []fieldPattern) bool {
+	for end := 1; end <= len(path); end++ {
+		if matchesAnyField(path[:end], denied) {
+			return true
+		}
+	}
+	return false
+}
+
+func filterFieldValue(value interface{}, path []string, allowed, denied []fieldPattern) interface{} {
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		for key, child := range typed {
+			childPath := append(append([]string{}, path...), key)
+			if matchesAnyField(childPath, denied) {
+				delete(typed, key)
+				continue
+			}
+			switch child.(type) {
+			case map[string]interface{}, []interface{}:
+				typed[key] = filterFieldValue(child, childPath, allowed, denied)
+			default:
+				if len(allowed) > 0 && !matchesAnyField(childPath, allowed) {
+					delete(typed, key)
+				}
+			}
+		}
+	case []interface{}:
+		// elements of a list have the path of the list
+		for index, element := range typed {
+			typed[index] = filterFieldValue(element, path, allowed, denied)
+		}
+	}
+	return value
+}
+
+func matchesAnyField(path []string, patterns []fieldPattern) bool {
+	for _, pattern := range patterns {
+		if pattern.matches(path) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p fieldPattern) matches(path []string) bool {
+	if len(p) > len(path) {
+		return false
+	}
+	offset := len(path) - len(p)
+	for index, key := range p {
+		if path[offset+index] != key {
+			return false
+		}
+	}
+	return true
+}
+
+func parseFieldPatterns(fields []string) ([]fieldPattern, error) {
+	var patterns []fieldPattern
+	for _, field := range fields {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		pattern := fieldPattern(strings.Split(field, "."))
+		for _, key := range pattern {
+			if key == "" {
+				return nil, fmt.Errorf("invalid field: %q, keys of a field can't be empty", field)
+			}
+		}
+		patterns = append(patterns, pattern)
+	}
+	return patterns, nil
+}
@@ -0,0 +1,87 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilterFieldsDenied(t *testing.T) {
+	assert.NoError(t, ConfigureFieldPolicy(nil, []string{"annotations", "label.value"}))
+	defer resetFieldPolicy(t)
+
+	data := []byte(`{"name":"pod-a","cpu":0.12345678901234567,"annotations":{"token":"secret"},` +
+		`"label":[{"key":"app","value":"web"}]}`)
+	filtered, err := FilterFields(data)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"name":"pod-a","cpu":0.12345678901234567,"label":[{"key":"app"}]}`, string(filtered))
+}
+
+func TestFilterFieldsAllowed(t *testing.T) {
+	assert.NoError(t, ConfigureFieldPolicy([]string{"name", "children.cpu"}, []string{"secret"}))
+	defer resetFieldPolicy(t)
+
+	data := []byte(`{"name":"cluster","cpu":1,"children":[{"name":"node-a","cpu":2,"image":"x",` +
+		`"secret":{"name":"s"}}]}`)
+	filtered, err := FilterFields(data)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"name":"cluster","children":[{"name":"node-a","cpu":2}]}`, string(filtered))
+}
+
+func TestFilterFieldsWithoutPolicy(t *testing.T) {
+	assert.False(t, HasFieldPolicy())
+	data := []byte(`{"name":"pod-a"}`)
+	filtered, err := FilterFields(data)
+	assert.NoError(t, err)
+	assert.Equal(t, data, filtered)
+}
+
+func TestFilterResult(t *testing.T) {
+	assert.NoError(t, ConfigureFieldPolicy(nil, []string{"memoryCost"}))
+	defer resetFieldPolicy(t)
+
+	result := JSONDataWrapper{Data: ParentWrapper{Name: "pod-a", CPUCost: 1, MemoryCost: 2,
+		Children: []Children{{Name: "container-a", MemoryCost: 2}}}}
+	assert.NoError(t, FilterResult(&result))
+	assert.Equal(t, JSONDataWrapper{Data: ParentWrapper{Name: "pod-a", CPUCost: 1,
+		Children: []Children{{Name: "container-a"}}}}, result)
+}
+
+func TestIsFieldAllowed(t *testing.T) {
+	assert.True(t, IsFieldAllowed("data", "rows", "cpuCost"))
+	assert.NoError(t, ConfigureFieldPolicy([]string{"name", "rows.cpuCost"}, []string{"tags"}))
+	defer resetFieldPolicy(t)
+
+	assert.True(t, IsFieldAllowed("data", "rows", "cpuCost"))
+	assert.False(t, IsFieldAllowed("data", "rows", "memoryCost"))
+	assert.False(t, IsFieldAllowed("data", "tags", "name"))
+	assert.True(t, IsFieldDenied("data", "tags"))
+	assert.False(t, IsFieldDenied("data", "rows"))
+}
+
+func TestConfigureFieldPolicyInvalid(t *testing.T) {
+	assert.Error(t, ConfigureFieldPolicy([]string{"label..value"}, nil))
+	assert.False(t, HasFieldPolicy())
+}
+
+func resetFieldPolicy(t *testing.T) {
+	assert.NoError(t, ConfigureFieldPolicy(nil, nil))
+	assert.False(t, HasFieldPolicy())
+}
@@ -0,0 +1,306 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vmware/purser/pkg/controller/dgraph/models"
+)
+
+// Values of columns of FOCUS rows which are the same for all charges of the cluster
+const (
+	FOCUSProviderName    = "Kubernetes"
+	FOCUSServiceName     = "Kubernetes"
+	FOCUSCurrency        = "USD"
+	FOCUSChargeCategory  = "Usage"
+	FOCUSChargeFrequency = "Usage-Based"
+	FOCUSPricingCategory = "Standard"
+	FOCUSResourceType    = "Pod"
+	// DefaultFOCUSBillingAccount is the billing account of rows if it is not configured
+	DefaultFOCUSBillingAccount = "kubernetes"
+)
+
+var (
+	focusMu             sync.RWMutex
+	focusBillingAccount = DefaultFOCUSBillingAccount
+)
+
+// FOCUSRow is a charge in the FinOps Open Cost and Usage Specification(FOCUS 1.0) schema, the cost of a resource(cpu,
+// memory, storage or gpu) requested by a pod in the charge period. ListCost is the cost before adjustments and
+// BilledCost, EffectiveCost and ContractedCost are the adjusted cost. Columns prefixed by x_ are custom columns with
+// the kubernetes dimensions of the pod.
+type FOCUSRow struct {
+	BilledCost          float64           `json:"BilledCost"`
+	BillingAccountID    string            `json:"BillingAccountId"`
+	BillingAccountName  string            `json:"BillingAccountName"`
+	BillingCurrency     string            `json:"BillingCurrency"`
+	BillingPeriodStart  string            `json:"BillingPeriodStart"`
+	BillingPeriodEnd    string            `json:"BillingPeriodEnd"`
+	ChargeCategory      string            `json:"ChargeCategory"`
+	ChargeDescription   string            `json:"ChargeDescription"`
+	ChargeFrequency     string            `json:"ChargeFrequency"`
+	ChargePeriodStart   string            `json:"ChargePeriodStart"`
+	ChargePeriodEnd     string            `json:"ChargePeriodEnd"`
+	ConsumedQuantity    float64           `json:"ConsumedQuantity"`
+	ConsumedUnit        string            `json:"ConsumedUnit"`
+	ContractedCost      float64           `json:"ContractedCost"`
+	ContractedUnitPrice float64           `json:"ContractedUnitPrice"`
+	EffectiveCost       float64           `json:"EffectiveCost"`
+	InvoiceIssuerName   string            `json:"InvoiceIssuerName"`
+	ListCost            float64           `json:"ListCost"`
+	ListUnitPrice       float64           `json:"ListUnitPrice"`
+	PricingCategory     string            `json:"PricingCategory"`
+	PricingQuantity     float64           `json:"PricingQuantity"`
+	PricingUnit         string            `json:"PricingUnit"`
+	ProviderName        string            `json:"ProviderName"`
+	PublisherName       string            `json:"PublisherName"`
+	RegionID            string            `json:"RegionId"`
+	RegionName          string            `json:"RegionName"`
+	ResourceID          string            `json:"ResourceId"`
+	ResourceName        string            `json:"ResourceName"`
+	ResourceType        string            `json:"ResourceType"`
+	ServiceCategory     string            `json:"ServiceCategory"`
+	ServiceName         string            `json:"ServiceName"`
+	SkuID               string            `json:"SkuId"`
+	SubAccountID        string            `json:"SubAccountId"`
+	SubAccountName      string            `json:"SubAccountName"`
+	Tags                map[string]string `json:"Tags"`
+	Namespace           string            `json:"x_Namespace"`
+	Node                string            `json:"x_Node"`
+	Workload            string            `json:"x_Workload"`
+	WorkloadType        string            `json:"x_WorkloadType"`
+}
+
+// FOCUSReport is the charges of pods existing between Start and End
+type FOCUSReport struct {
+	Start string     `json:"start"`
+	End   string     `json:"end"`
+	Rows  []FOCUSRow `json:"rows"`
+}
+
+// FOCUSReportWrapper structure
+type FOCUSReportWrapper struct {
+	Data FOCUSReport `json:"data"`
+}
+
+type focusPod struct {
+	triggeringPod
+	StartTime   string  `json:"startTime"`
+	EndTime     string  `json:"endTime"`
+	Hours       float64 `json:"hours"`
+	CPU         float64 `json:"cpu"`
+	Memory      float64 `json:"memory"`
+	Storage     float64 `json:"storage"`
+	GPU         float64 `json:"gpu"`
+	CPUPrice    float64 `json:"cpuPrice"`
+	MemoryPrice float64 `json:"memoryPrice"`
+	GPUPrice    float64 `json:"gpuPrice"`
+	CPUCost     float64 `json:"cpuCost"`
+	MemoryCost  float64 `json:"memoryCost"`
+	StorageCost float64 `json:"storageCost"`
+	GPUCost     float64 `json:"gpuCost"`
+	Node        *struct {
+		Name   string `json:"name"`
+		Region string `json:"region"`
+	} `json:"node"`
+	Label []struct {
+		Key   string `json:"key"`
+		Value string `json:"value"`
+	} `json:"label"`
+}
+
+// focusCharge is the quantity, price and cost of a resource of a pod
+type focusCharge struct {
+	sku             string
+	description     string
+	serviceCategory string
+	unit            string
+	quantity        float64
+	price           float64
+	cost            float64
+}
+
+// ConfigureFOCUS sets the billing account of rows of FOCUS exports(ex: name of the cluster)
+func ConfigureFOCUS(billingAccount string) {
+	focusMu.Lock()
+	defer focusMu.Unlock()
+	if billingAccount != "" {
+		focusBillingAccount = billingAccount
+	}
+}
+
+func getFOCUSBillingAccount() string {
+	focusMu.RLock()
+	defer focusMu.RUnlock()
+	return focusBillingAccount
+}
+
+// RetrieveFOCUSReport returns charges of cpu, memory, storage and gpu requested by pods existing between start and
+// end(month to date by default) in the FOCUS schema
+func RetrieveFOCUSReport(ctx context.Context, timeRange TimeRange) FOCUSReportWrapper {
+	timeRange = getRangeFromMonthStart(timeRange)
+	root := struct {
+		Pods []focusPod `json:"pods"`
+	}{}
+	err := executeQuery(ctx, getQueryForFOCUSPods(timeRange), &root)
+	if err != nil {
+		log.Errorf("unable to retrieve costs of pods for FOCUS export, err: %v", err)
+		return FOCUSReportWrapper{}
+	}
+	return FOCUSReportWrapper{Data: computeFOCUSReport(root.Pods, timeRange)}
+}
+
+func getQueryForFOCUSPods(timeRange TimeRange) string {
+	owners := ``
+	for _, ownerType := range podOwnerPredicates {
+		owners += `
+			` + ownerType + ` {
+				name
+			}`
+	}
+	return `{
+		pods(func: has(isPod)) @filter(` + getLiveFilterInRange(timeRange) + `) {
+			` + getQueryForMetricsComputationWithAliasInRange("FOCUS", timeRange) + `
+			hours: math(durationInHoursFOCUS)
+			namespace {
+				name
+			}
+			node {
+				name
+				region
+			}
+			label {
+				key
+				value
+			}` + owners + `
+		}
+	}`
+}
+
+// computeFOCUSReport returns a row for each resource with a cost of each pod, rows are sorted by
+// resource id and sku
+func computeFOCUSReport(pods []focusPod, timeRange TimeRange) FOCUSReport {
+	report := FOCUSReport{Start: timeRange.Start, End: timeRange.End, Rows: []FOCUSRow{}}
+	rangeStart, _ := time.Parse(time.RFC3339, timeRange.Start)
+	rangeEnd, _ := time.Parse(time.RFC3339, timeRange.End)
+	billingPeriodStart, billingPeriodEnd := getBillingPeriod(rangeStart, rangeEnd)
+	account := getFOCUSBillingAccount()
+	for _, pod := range pods {
+		owner, ownerType, namespace := getPodOwner(pod.triggeringPod)
+		namespace = strings.TrimPrefix(namespace, NamespaceType+"-")
+		name := strings.TrimPrefix(pod.Name, PodType+"-")
+		chargeStart, chargeEnd := getChargePeriod(pod.StartTime, pod.EndTime, rangeStart, rangeEnd)
+		row := FOCUSRow{
+			BillingAccountID:   account,
+			BillingAccountName: account,
+			BillingCurrency:    FOCUSCurrency,
+			BillingPeriodStart: billingPeriodStart,
+			BillingPeriodEnd:   billingPeriodEnd,
+			ChargeCategory:     FOCUSChargeCategory,
+			ChargeFrequency:    FOCUSChargeFrequency,
+			ChargePeriodStart:  chargeStart,
+			ChargePeriodEnd:    chargeEnd,
+			InvoiceIssuerName:  FOCUSProviderName,
+			PricingCategory:    FOCUSPricingCategory,
+			ProviderName:       FOCUSProviderName,
+			PublisherName:      FOCUSProviderName,
+			ResourceID:         namespace + "/" + name,
+			ResourceName:       name,
+			ResourceType:       FOCUSResourceType,
+			ServiceName:        FOCUSServiceName,
+			SubAccountID:       namespace,
+			SubAccountName:     namespace,
+			Tags:               map[string]string{},
+			Namespace:          namespace,
+			Workload:           strings.TrimPrefix(owner, ownerType+"-"),
+			WorkloadType:       ownerType,
+		}
+		if pod.Node != nil {
+			row.Node = strings.TrimPrefix(pod.Node.Name, NodeType+"-")
+			row.RegionID, row.RegionName = pod.Node.Region, pod.Node.Region
+		}
+		for _, label := range pod.Label {
+			row.Tags[label.Key] = label.Value
+		}
+		for _, charge := range getFOCUSCharges(pod) {
+			if charge.cost == 0 {
+				continue
+			}
+			chargeRow := row
+			chargeRow.SkuID = charge.sku
+			chargeRow.ChargeDescription = charge.description + " requested by pod " + name + " in namespace " + namespace
+			chargeRow.ServiceCategory = charge.serviceCategory
+			chargeRow.ConsumedQuantity, chargeRow.PricingQuantity = charge.quantity, charge.quantity
+			chargeRow.ConsumedUnit, chargeRow.PricingUnit = charge.unit, charge.unit
+			chargeRow.ListUnitPrice = charge.price
+			chargeRow.ListCost = charge.cost
+			effectiveCost := adjustCost(CostContext{PodType, pod.Name, charge.sku}, charge.cost)
+			chargeRow.BilledCost, chargeRow.EffectiveCost, chargeRow.ContractedCost = effectiveCost, effectiveCost, effectiveCost
+			if charge.quantity > 0 {
+				chargeRow.ContractedUnitPrice = effectiveCost / charge.quantity
+			}
+			report.Rows = append(report.Rows, chargeRow)
+		}
+	}
+	sort.SliceStable(report.Rows, func(i, j int) bool {
+		if report.Rows[i].ResourceID != report.Rows[j].ResourceID {
+			return report.Rows[i].ResourceID < report.Rows[j].ResourceID
+		}
+		return report.Rows[i].SkuID < report.Rows[j].SkuID
+	})
+	return report
+}
+
+// getFOCUSCharges returns the charges of cpu(core hours), memory(GB hours), storage(GB hours) and gpu(GPU hours)
+// requested by the pod, sku of a charge is its cost type
+func getFOCUSCharges(pod focusPod) []focusCharge {
+	return []focusCharge{
+		{CPUCostType, "CPU", "Compute", "Core-Hours", pod.CPU * pod.Hours, pod.CPUPrice, pod.CPUCost},
+		{MemoryCostType, "Memory", "Compute", "GB-Hours", pod.Memory * pod.Hours, pod.MemoryPrice, pod.MemoryCost},
+		{StorageCostType, "Storage", "Storage", "GB-Hours", pod.Storage * pod.Hours, models.DefaultStorageCostInFloat64, pod.StorageCost},
+		{GPUCostType, "GPU", "Compute", "GPU-Hours", pod.GPU * pod.Hours, pod.GPUPrice, pod.GPUCost},
+	}
+}
+
+// getChargePeriod returns the part of the lifetime of the pod within the range
+func getChargePeriod(podStart, podEnd string, rangeStart, rangeEnd time.Time) (string, string) {
+	start, end := rangeStart, rangeEnd
+	if t, err := time.Parse(time.RFC3339, podStart); err == nil && t.After(start) {
+		start = t
+	}
+	if t, err := time.Parse(time.RFC3339, podEnd); err == nil && t.Before(end) {
+		end = t
+	}
+	return start.UTC().Format(time.RFC3339), end.UTC().Format(time.RFC3339)
+}
+
+// getBillingPeriod returns the calendar months(UTC) covering the range, end is exclusive
+func getBillingPeriod(rangeStart, rangeEnd time.Time) (string, string) {
+	rangeStart, rangeEnd = rangeStart.UTC(), rangeEnd.UTC()
+	start := time.Date(rangeStart.Year(), rangeStart.Month(), 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(rangeEnd.Year(), rangeEnd.Month(), 1, 0, 0, 0, 0, time.UTC)
+	if !end.Equal(rangeEnd) || !end.After(start) {
+		end = end.AddDate(0, 1, 0)
+	}
+	return start.Format(time.RFC3339), end.Format(time.RFC3339)
+}
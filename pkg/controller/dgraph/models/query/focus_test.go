@@ -0,0 +1,85 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetrieveFOCUSReport(t *testing.T) {
+	executeQuery = func(ctx context.Context, query string, root interface{}) error {
+		return json.Unmarshal([]byte(`{
+			"pods": [
+				{"name": "pod-web-1", "namespace": {"name": "namespace-shop"}, "deployment": {"name": "deployment-web"},
+				 "node": {"name": "node-a", "region": "us-east-1"}, "label": [{"key": "team", "value": "payments"}],
+				 "startTime": "2018-09-20T00:00:00Z", "hours": 240, "cpu": 0.5, "cpuPrice": 0.02, "cpuCost": 2.4,
+				 "memory": 2, "memoryPrice": 0.005, "memoryCost": 2.4},
+				{"name": "pod-backup", "namespace": {"name": "namespace-shop"}, "startTime": "2018-10-02T00:00:00Z",
+				 "endTime": "2018-10-03T00:00:00Z", "hours": 24, "storage": 10, "storageCost": 0.0333}
+			]
+		}`), root)
+	}
+
+	report := RetrieveFOCUSReport(context.Background(), TimeRange{Start: "2018-10-01T00:00:00Z", End: "2018-10-11T00:00:00Z"}).Data
+	assert.Equal(t, 3, len(report.Rows))
+
+	storage := report.Rows[0]
+	assert.Equal(t, "shop/backup", storage.ResourceID)
+	assert.Equal(t, StorageCostType, storage.SkuID)
+	assert.Equal(t, "Storage", storage.ServiceCategory)
+	assert.Equal(t, "2018-10-02T00:00:00Z", storage.ChargePeriodStart)
+	assert.Equal(t, "2018-10-03T00:00:00Z", storage.ChargePeriodEnd)
+	assert.Equal(t, 240.0, storage.ConsumedQuantity)
+	assert.Equal(t, PodType, storage.WorkloadType)
+
+	cpu := report.Rows[1]
+	assert.Equal(t, "shop/web-1", cpu.ResourceID)
+	assert.Equal(t, CPUCostType, cpu.SkuID)
+	assert.Equal(t, "CPU requested by pod web-1 in namespace shop", cpu.ChargeDescription)
+	assert.Equal(t, "Core-Hours", cpu.ConsumedUnit)
+	assert.Equal(t, 120.0, cpu.ConsumedQuantity)
+	assert.Equal(t, 0.02, cpu.ListUnitPrice)
+	assert.Equal(t, 2.4, cpu.ListCost)
+	assert.Equal(t, 2.4, cpu.EffectiveCost)
+	assert.Equal(t, "2018-10-01T00:00:00Z", cpu.ChargePeriodStart)
+	assert.Equal(t, "2018-10-01T00:00:00Z", cpu.BillingPeriodStart)
+	assert.Equal(t, "2018-11-01T00:00:00Z", cpu.BillingPeriodEnd)
+	assert.Equal(t, DefaultFOCUSBillingAccount, cpu.BillingAccountID)
+	assert.Equal(t, "shop", cpu.SubAccountID)
+	assert.Equal(t, "us-east-1", cpu.RegionID)
+	assert.Equal(t, "a", cpu.Node)
+	assert.Equal(t, "web", cpu.Workload)
+	assert.Equal(t, DeploymentType, cpu.WorkloadType)
+	assert.Equal(t, map[string]string{"team": "payments"}, cpu.Tags)
+	assert.Equal(t, MemoryCostType, report.Rows[2].SkuID)
+}
+
+func TestGetBillingPeriod(t *testing.T) {
+	start, end := getBillingPeriod(time.Date(2018, 10, 5, 0, 0, 0, 0, time.UTC), time.Date(2018, 11, 1, 0, 0, 0, 0, time.UTC))
+	assert.Equal(t, "2018-10-01T00:00:00Z", start)
+	assert.Equal(t, "2018-11-01T00:00:00Z", end)
+
+	start, end = getBillingPeriod(time.Date(2018, 10, 5, 0, 0, 0, 0, time.UTC), time.Date(2018, 11, 3, 0, 0, 0, 0, time.UTC))
+	assert.Equal(t, "2018-10-01T00:00:00Z", start)
+	assert.Equal(t, "2018-12-01T00:00:00Z", end)
+}
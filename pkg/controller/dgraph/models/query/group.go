@@ -18,9 +18,8 @@
 package query
 
 import (
+	"context"
 	"github.com/vmware/purser/pkg/controller/dgraph/models"
-
-	"github.com/Sirupsen/logrus"
 )
 
 // GroupMetrics structure
@@ -47,6 +46,10 @@ type GroupMetrics struct {
 	LastLastMonthCPUCost     float64
 	LastLastMonthMemoryCost  float64
 	LastLastMonthStorageCost float64
+	Carbon                   float64
+	CarbonPerHour            float64
+	LastMonthCarbon          float64
+	LastLastMonthCarbon      float64
 	PodsCount                int
 }
 
@@ -60,11 +63,11 @@ type groupJSONMetrics struct {
 
 // RetrieveGroupsData returns list of models.Group objects in json format
 // error is not nil if any failure is encountered
-func RetrieveGroupsData() ([]models.Group, error) {
+func RetrieveGroupsData(ctx context.Context) ([]models.Group, error) {
 	query := getQueryForAllGroupsData()
 
 	newRoot := groupsRoot{}
-	err := executeQuery(query, &newRoot)
+	err := executeQuery(ctx, query, &newRoot)
 	if err != nil {
 		return []models.Group{}, err
 	}
@@ -72,32 +75,40 @@ func RetrieveGroupsData() ([]models.Group, error) {
 }
 
 // RetrieveGroupMetricsFromPodUIDs ...
-func RetrieveGroupMetricsFromPodUIDs(podsUIDs string) (GroupMetrics, error) {
+func RetrieveGroupMetricsFromPodUIDs(ctx context.Context, podsUIDs string) (GroupMetrics, error) {
+	return retrieveMetricsOfPods(ctx, podsUIDs, "group")
+}
+
+// retrieveMetricsOfPods returns metrics of a set of pods, costs are adjusted as costs of the given resource type
+func retrieveMetricsOfPods(ctx context.Context, podsUIDs, resourceType string) (GroupMetrics, error) {
 	query := getQueryForGroupMetrics(podsUIDs)
 
 	newRoot := groupJSONMetrics{}
-	err := executeQuery(query, &newRoot)
+	err := executeQuery(ctx, query, &newRoot)
 	if err != nil {
 		return GroupMetrics{}, err
 	}
-	return convertToGroupMetrics(newRoot.JSONMetrics), nil
+	return convertToGroupMetrics(newRoot.JSONMetrics, resourceType), nil
 }
 
-func convertToGroupMetrics(jsonMetrics []map[string]float64) GroupMetrics {
+func convertToGroupMetrics(jsonMetrics []map[string]float64, resourceType string) GroupMetrics {
 	var groupMetrics GroupMetrics
 	for _, data := range jsonMetrics {
 		for key, value := range data {
+			if costType := getCostTypeOfGroupMetric(key); costType != "" {
+				value = adjustCost(CostContext{ResourceType: resourceType, CostType: costType}, value)
+			}
 			populateMetric(&groupMetrics, key, value)
 			break
 		}
 	}
-	logrus.Debugf("JSON metrics: (%v), Group metrics: (%v)", jsonMetrics, groupMetrics)
+	log.Debugf("JSON metrics: (%v), Group metrics: (%v)", jsonMetrics, groupMetrics)
 	return groupMetrics
 }
 
 // nolint: gocyclo
 func populateMetric(groupMetrics *GroupMetrics, key string, value float64) {
-	logrus.Debugf("key: %s", key)
+	log.Debugf("key: %s", key)
 	switch key {
 	case "pitCPU":
 		groupMetrics.PITCpu = value
@@ -143,6 +154,14 @@ func populateMetric(groupMetrics *GroupMetrics, key string, value float64) {
 		groupMetrics.LastLastMonthMemoryCost = value
 	case "lastLastMonthStorageCost":
 		groupMetrics.LastLastMonthStorageCost = value
+	case "carbon":
+		groupMetrics.Carbon = value
+	case "carbonPerHour":
+		groupMetrics.CarbonPerHour = value
+	case "lastMonthCarbon":
+		groupMetrics.LastMonthCarbon = value
+	case "lastLastMonthCarbon":
+		groupMetrics.LastLastMonthCarbon = value
 	case "livePods":
 		groupMetrics.PodsCount = int(value)
 	}
@@ -18,6 +18,7 @@
 package query
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
@@ -35,7 +36,7 @@ func mockMetricsMap(key string, value float64) map[string]float64 {
 }
 
 func mockDgraphForGroupQueries(queryType string) {
-	executeQuery = func(query string, root interface{}) error {
+	executeQuery = func(ctx context.Context, query string, root interface{}) error {
 		if queryType == testRetrieveAllGroups {
 			dummyGroupList, ok := root.(*groupsRoot)
 			if !ok {
@@ -88,14 +89,14 @@ func mockDgraphForGroupQueries(queryType string) {
 // TestRetrieveGroupsDataWithDgraphError ...
 func TestRetrieveGroupsDataWithDgraphError(t *testing.T) {
 	mockDgraphForGroupQueries(testWrongQuery)
-	_, err := RetrieveGroupsData()
+	_, err := RetrieveGroupsData(context.Background())
 	assert.Error(t, err)
 }
 
 // TestRetrieveGroupsData ...
 func TestRetrieveGroupsData(t *testing.T) {
 	mockDgraphForGroupQueries(testRetrieveAllGroups)
-	got, err := RetrieveGroupsData()
+	got, err := RetrieveGroupsData(context.Background())
 	expected := []models.Group{{
 		Name:           "group-purser",
 		PodsCount:      3,
@@ -117,14 +118,14 @@ func TestRetrieveGroupsData(t *testing.T) {
 // TestGroupMetricsFromPodUIDsWithDgraphError ...
 func TestGroupMetricsFromPodUIDsWithDgraphError(t *testing.T) {
 	mockDgraphForGroupQueries(testWrongQuery)
-	_, err := RetrieveGroupMetricsFromPodUIDs("")
+	_, err := RetrieveGroupMetricsFromPodUIDs(context.Background(), "")
 	assert.Error(t, err)
 }
 
 // TestGroupMetricsFromPodUIDs ...
 func TestGroupMetricsFromPodUIDs(t *testing.T) {
 	mockDgraphForGroupQueries(testRetrieveGroupMetrics)
-	got, err := RetrieveGroupMetricsFromPodUIDs(testPodUIDList)
+	got, err := RetrieveGroupMetricsFromPodUIDs(context.Background(), testPodUIDList)
 	expected := GroupMetrics{
 		PITCpu:         1.3,
 		PITMemory:      2.4,
@@ -0,0 +1,153 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/vmware/purser/pkg/controller/dgraph/models"
+	qb "github.com/vmware/purser/pkg/querybuilder"
+)
+
+// Helm resource types
+const (
+	HelmReleaseType = "helmrelease"
+	HelmChartType   = "helmchart"
+)
+
+// HelmCost is the cost of pods of a helm release or of all releases of a chart,
+// Releases is set for charts, Chart for releases
+type HelmCost struct {
+	Name          string   `json:"name"`
+	Chart         string   `json:"chart,omitempty"`
+	Versions      []string `json:"versions,omitempty"`
+	Releases      []string `json:"releases,omitempty"`
+	Pods          int      `json:"pods"`
+	CPUCost       float64  `json:"cpuCost"`
+	MemoryCost    float64  `json:"memoryCost"`
+	StorageCost   float64  `json:"storageCost"`
+	TotalCost     float64  `json:"totalCost"`
+	LastMonthCost float64  `json:"lastMonthCost"`
+}
+
+// HelmCostsWrapper structure
+type HelmCostsWrapper struct {
+	Data []HelmCost `json:"data"`
+}
+
+type helmPod struct {
+	UID         string `json:"uid"`
+	HelmRelease string `json:"helmRelease"`
+	HelmChart   string `json:"helmChart"`
+}
+
+// RetrieveHelmReleaseCosts returns month to date and last month cost of each helm release(<namespace>/<release>)
+func RetrieveHelmReleaseCosts(ctx context.Context) HelmCostsWrapper {
+	return retrieveHelmCosts(ctx, HelmReleaseType, func(pod helmPod) string {
+		return pod.HelmRelease
+	})
+}
+
+// RetrieveHelmChartCosts returns month to date and last month cost of each helm chart summed over its releases and versions
+func RetrieveHelmChartCosts(ctx context.Context) HelmCostsWrapper {
+	return retrieveHelmCosts(ctx, HelmChartType, func(pod helmPod) string {
+		return models.GetChartName(pod.HelmChart)
+	})
+}
+
+func retrieveHelmCosts(ctx context.Context, resourceType string, keyOf func(helmPod) string) HelmCostsWrapper {
+	type root struct {
+		Pods []helmPod `json:"pods"`
+	}
+	newRoot := root{}
+	err := executeQuery(ctx, getQueryForHelmPods(), &newRoot)
+	if err != nil {
+		log.Errorf("unable to retrieve pods of helm releases, err: %v", err)
+		return HelmCostsWrapper{}
+	}
+
+	costs := make(map[string]*HelmCost)
+	podsOfKeys := make(map[string][]string)
+	for _, pod := range newRoot.Pods {
+		key := keyOf(pod)
+		if key == "" {
+			continue
+		}
+		if _, isPresent := costs[key]; !isPresent {
+			costs[key] = &HelmCost{Name: key}
+		}
+		addHelmPod(costs[key], resourceType, pod)
+		podsOfKeys[key] = append(podsOfKeys[key], pod.UID)
+	}
+
+	data := []HelmCost{}
+	for key, cost := range costs {
+		metrics, err := retrieveMetricsOfPods(ctx, strings.Join(podsOfKeys[key], ", "), resourceType)
+		if err != nil {
+			log.Errorf("unable to retrieve metrics of %s: %s, err: %v", resourceType, key, err)
+			continue
+		}
+		cost.CPUCost = metrics.CostCPU
+		cost.MemoryCost = metrics.CostMemory
+		cost.StorageCost = metrics.CostStorage
+		cost.TotalCost = metrics.CostCPU + metrics.CostMemory + metrics.CostStorage
+		cost.LastMonthCost = metrics.LastMonthCPUCost + metrics.LastMonthMemoryCost + metrics.LastMonthStorageCost
+		sort.Strings(cost.Versions)
+		sort.Strings(cost.Releases)
+		data = append(data, *cost)
+	}
+	sort.Slice(data, func(i, j int) bool {
+		if data[i].TotalCost != data[j].TotalCost {
+			return data[i].TotalCost > data[j].TotalCost
+		}
+		return data[i].Name < data[j].Name
+	})
+	return HelmCostsWrapper{Data: data}
+}
+
+// addHelmPod counts the pod in the cost of its release or chart and records its chart or release
+func addHelmPod(cost *HelmCost, resourceType string, pod helmPod) {
+	cost.Pods++
+	if resourceType == HelmReleaseType {
+		cost.Chart = pod.HelmChart
+		return
+	}
+	if version := models.GetChartVersion(pod.HelmChart); version != "" && !containsString(cost.Versions, version) {
+		cost.Versions = append(cost.Versions, version)
+	}
+	if !containsString(cost.Releases, pod.HelmRelease) {
+		cost.Releases = append(cost.Releases, pod.HelmRelease)
+	}
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func getQueryForHelmPods() string {
+	return qb.New(
+		qb.Func("pods", qb.Has("helmRelease")).Filter(qb.Has("isPod")).Fields("uid", "helmRelease", "helmChart"),
+	).String()
+}
@@ -0,0 +1,64 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func mockDgraphForHelm() {
+	executeQuery = func(ctx context.Context, query string, root interface{}) error {
+		if strings.Contains(query, "has(helmRelease)") {
+			return json.Unmarshal([]byte(`{"pods": [
+				{"uid": "0x1", "helmRelease": "default/web", "helmChart": "nginx-1.2.3"},
+				{"uid": "0x2", "helmRelease": "staging/web", "helmChart": "nginx-1.3.0"},
+				{"uid": "0x3", "helmRelease": "monitoring/prom", "helmChart": "prometheus-8.0.0"}
+			]}`), root)
+		}
+		if strings.Contains(query, "0x3") {
+			return json.Unmarshal([]byte(`{"group": [{"cpuCost": 5}]}`), root)
+		}
+		return json.Unmarshal([]byte(`{"group": [{"cpuCost": 1}]}`), root)
+	}
+}
+
+// TestRetrieveHelmReleaseCosts ...
+func TestRetrieveHelmReleaseCosts(t *testing.T) {
+	mockDgraphForHelm()
+	expected := []HelmCost{
+		{Name: "monitoring/prom", Chart: "prometheus-8.0.0", Pods: 1, CPUCost: 5, TotalCost: 5},
+		{Name: "default/web", Chart: "nginx-1.2.3", Pods: 1, CPUCost: 1, TotalCost: 1},
+		{Name: "staging/web", Chart: "nginx-1.3.0", Pods: 1, CPUCost: 1, TotalCost: 1},
+	}
+	assert.Equal(t, expected, RetrieveHelmReleaseCosts(context.Background()).Data)
+}
+
+// TestRetrieveHelmChartCosts ...
+func TestRetrieveHelmChartCosts(t *testing.T) {
+	mockDgraphForHelm()
+	expected := []HelmCost{
+		{Name: "prometheus", Versions: []string{"8.0.0"}, Releases: []string{"monitoring/prom"}, Pods: 1, CPUCost: 5, TotalCost: 5},
+		{Name: "nginx", Versions: []string{"1.2.3", "1.3.0"}, Releases: []string{"default/web", "staging/web"}, Pods: 2, CPUCost: 1, TotalCost: 1},
+	}
+	assert.Equal(t, expected, RetrieveHelmChartCosts(context.Background()).Data)
+}
@@ -19,13 +19,26 @@ package query
 
 import (
 	"fmt"
+	"regexp"
+	"strings"
+	"time"
 
 	"github.com/vmware/purser/pkg/controller/dgraph/models"
 	"github.com/vmware/purser/pkg/controller/utils"
+	qb "github.com/vmware/purser/pkg/querybuilder"
 )
 
 var secondsFromFirstOfCurrentMonth = getSecondsSinceMonthStart
 
+// names are interpolated in some dgraph queries so only characters of k8s object names, resource type prefixes(ex: pod-)
+// and deletion timestamps(ex: *2018-10-10T10:10:10Z) are allowed in names of APIs.
+var nameRegex = regexp.MustCompile(`^[A-Za-z0-9]([A-Za-z0-9\-._:*+]*)?$`)
+
+// IsValidName returns true if name has only characters allowed in names of resources of APIs
+func IsValidName(name string) bool {
+	return nameRegex.MatchString(name)
+}
+
 func getSecondsSinceMonthStart() string {
 	return fmt.Sprintf("%f", utils.GetSecondsSince(utils.GetCurrentMonthStartTime()))
 }
@@ -46,32 +59,67 @@ func getSecondsSinceForOtherMonths() map[string]string {
 	return secondsSince
 }
 
-func getQueryForMetricsComputationWithAliasAndVariables(suffix string) string {
+func getQueryForMetricsComputationWithAliasAndVariablesInRange(suffix string, timeRange TimeRange) string {
 	return `name
 			type
 			cpu: cpu` + suffix + ` as cpuRequest
 			memory: memory` + suffix + ` as memoryRequest
 			storage: storage` + suffix + ` as storageRequest
-			` + getQueryForTimeComputation(suffix) + `
-			` + getQueryForCostWithPriceWithAliasAndVariables(suffix)
+			ephemeralStorage: ephemeralStorage` + suffix + ` as ephemeralStorageRequest
+			hugepages: hugepages` + suffix + ` as hugepagesRequest
+			` + getQueryForTimeComputationInRange(suffix, timeRange) + `
+			` + getQueryForCostWithPriceWithAliasAndVariables(suffix) + `
+			gpu: gpu` + suffix + ` as gpuRequest
+			pricePerGPU` + suffix + ` as gpuPrice
+			gpuCost: gpuCost` + suffix + ` as math(gpu` + suffix + ` * durationInHours` + suffix + ` * pricePerGPU` + suffix + `)
+			cpuUsage: cpuUsage` + suffix + ` as cpuUsage
+			memoryUsage: memoryUsage` + suffix + ` as memoryUsage
+			utilizedCPUCost: utilizedCPUCost` + suffix + ` as math(cpuUsage` + suffix + ` * durationInHours` + suffix + ` * pricePerCPU` + suffix + `)
+			utilizedMemoryCost: utilizedMemoryCost` + suffix + ` as math(memoryUsage` + suffix + ` * durationInHours` + suffix + ` * pricePerMemory` + suffix + `)
+			syntheticRequests`
 }
 
 func getQueryForMetricsComputationWithAlias(suffix string) string {
+	return getQueryForMetricsComputationWithAliasAsOf(suffix, "")
+}
+
+func getQueryForMetricsComputationWithAliasAsOf(suffix, asOf string) string {
+	return getQueryForMetricsComputationWithAliasInRange(suffix, TimeRange{End: asOf})
+}
+
+func getQueryForMetricsComputationWithAliasInRange(suffix string, timeRange TimeRange) string {
 	return `name
 			type
 			cpu: cpu` + suffix + ` as cpuRequest
 			memory: memory` + suffix + ` as memoryRequest
 			storage: storage` + suffix + ` as storageRequest
-			` + getQueryForTimeComputation(suffix) + `
-			` + getQueryForCostWithPriceWithAlias(suffix)
+			` + getQueryForTimeComputationInRange(suffix, timeRange) + `
+			` + getQueryForCostWithPriceWithAlias(suffix) + `
+			gpu: gpu` + suffix + ` as gpuRequest
+			pricePerGPU` + suffix + ` as gpuPrice
+			gpuCost: math(gpu` + suffix + ` * durationInHours` + suffix + ` * pricePerGPU` + suffix + `)
+			cpuUsage: cpuUsage` + suffix + ` as cpuUsage
+			memoryUsage: memoryUsage` + suffix + ` as memoryUsage
+			utilizedCPUCost: math(cpuUsage` + suffix + ` * durationInHours` + suffix + ` * pricePerCPU` + suffix + `)
+			utilizedMemoryCost: math(memoryUsage` + suffix + ` * durationInHours` + suffix + ` * pricePerMemory` + suffix + `)
+			syntheticRequests`
 }
 
-func getQueryForMetricsComputation(suffix string) string {
+func getQueryForMetricsComputationInRange(suffix string, timeRange TimeRange) string {
 	return `cpu` + suffix + ` as cpuRequest
 			memory` + suffix + ` as memoryRequest
 			storage` + suffix + ` as storageRequest
-			` + getQueryForTimeComputation(suffix) + `
-			` + getQueryForCostWithPrice(suffix)
+			ephemeralStorage` + suffix + ` as ephemeralStorageRequest
+			hugepages` + suffix + ` as hugepagesRequest
+			` + getQueryForTimeComputationInRange(suffix, timeRange) + `
+			` + getQueryForCostWithPrice(suffix) + `
+			gpu` + suffix + ` as gpuRequest
+			pricePerGPU` + suffix + ` as gpuPrice
+			gpuCost` + suffix + ` as math(gpu` + suffix + ` * durationInHours` + suffix + ` * pricePerGPU` + suffix + `)
+			cpuUsage` + suffix + ` as cpuUsage
+			memoryUsage` + suffix + ` as memoryUsage
+			utilizedCPUCost` + suffix + ` as math(cpuUsage` + suffix + ` * durationInHours` + suffix + ` * pricePerCPU` + suffix + `)
+			utilizedMemoryCost` + suffix + ` as math(memoryUsage` + suffix + ` * durationInHours` + suffix + ` * pricePerMemory` + suffix + `)`
 }
 
 func getQueryForTimeComputation(suffix string) string {
@@ -85,28 +133,158 @@ func getQueryForTimeComputation(suffix string) string {
 			durationInHours` + suffix + ` as math(cond(secondsSinceStart` + suffix + ` > secondsSinceEnd` + suffix + `, (secondsSinceStart` + suffix + ` - secondsSinceEnd` + suffix + `) / 3600, 0.0))`
 }
 
+// getQueryForTimeComputationAsOf computes duration of resources from start of the month of asOf(RFC3339) until asOf,
+// resources terminated after asOf are considered running at asOf. Empty asOf computes duration until now.
+func getQueryForTimeComputationAsOf(suffix, asOf string) string {
+	return getQueryForTimeComputationInRange(suffix, TimeRange{End: asOf})
+}
+
+// getQueryForTimeComputationInRange computes duration of resources between start and end(RFC3339) of the time range,
+// resources terminated after end are considered running at end. Start defaults to start of the month of end and
+// end defaults to now, so an empty time range computes duration from start of the current month until now.
+func getQueryForTimeComputationInRange(suffix string, timeRange TimeRange) string {
+	endTime, err := time.Parse(time.RFC3339, timeRange.End)
+	isEnd := err == nil
+	startTime, err := time.Parse(time.RFC3339, timeRange.Start)
+	isStart := err == nil
+	if !isStart && !isEnd {
+		return getQueryForTimeComputation(suffix)
+	}
+	if !isEnd {
+		endTime = time.Now()
+	}
+	if !isStart {
+		endTime = endTime.In(time.Local)
+		startTime = time.Date(endTime.Year(), endTime.Month(), 1, 0, 0, 0, 0, time.Local)
+	}
+	secondsSinceRangeStart := fmt.Sprintf("%f", utils.GetSecondsSince(startTime))
+	secondsSinceEndComputation := `cond(isTerminated` + suffix + ` == 0, 0.0, since(et` + suffix + `))`
+	if isEnd {
+		secondsSinceRangeEnd := fmt.Sprintf("%f", utils.GetSecondsSince(endTime))
+		secondsSinceEndComputation = `cond(isTerminated` + suffix + ` == 0, ` + secondsSinceRangeEnd + `, max(since(et` + suffix + `), ` + secondsSinceRangeEnd + `))`
+	}
+	return `st` + suffix + ` as startTime
+			stSeconds` + suffix + ` as math(since(st` + suffix + `))
+			secondsSinceStart` + suffix + ` as math(cond(stSeconds` + suffix + ` > ` + secondsSinceRangeStart + `, ` + secondsSinceRangeStart + `, stSeconds` + suffix + `))
+			et` + suffix + ` as endTime
+			isTerminated` + suffix + ` as count(endTime)
+			secondsSinceEnd` + suffix + ` as math(` + secondsSinceEndComputation + `)
+			durationInHours` + suffix + ` as math(cond(secondsSinceStart` + suffix + ` > secondsSinceEnd` + suffix + `, (secondsSinceStart` + suffix + ` - secondsSinceEnd` + suffix + `) / 3600, 0.0))`
+}
+
+// getQueryForStoragePrice declares the storage price of the storage class of the resource, resources stored before
+// storage classes were priced have none
+func getQueryForStoragePrice(suffix string) string {
+	return `isStoragePriced` + suffix + ` as count(storagePrice)
+			pricePerStorage` + suffix + ` as storagePrice`
+}
+
+// getStoragePrice returns the storage price declared by getQueryForStoragePrice, DefaultStorageCostInFloat64 if the
+// resource has none
+func getStoragePrice(suffix string) string {
+	return `cond(isStoragePriced` + suffix + ` == 0, ` + formatPrice(models.DefaultStorageCostInFloat64) + `, pricePerStorage` + suffix + `)`
+}
+
+// getQueryForSyntheticRequests declares the synthetic cpu and memory requests of BestEffort pods(see
+// models.SetBestEffortRequests), other resources have none
+func getQueryForSyntheticRequests(suffix string) string {
+	return `isSyntheticCPU` + suffix + ` as count(syntheticCpuRequest)
+			syntheticCPU` + suffix + ` as syntheticCpuRequest
+			isSyntheticMemory` + suffix + ` as count(syntheticMemoryRequest)
+			syntheticMemory` + suffix + ` as syntheticMemoryRequest`
+}
+
+// getCPUForCost returns the cpu costed for the resource, the synthetic cpu request declared by
+// getQueryForSyntheticRequests if it has one
+func getCPUForCost(suffix string) string {
+	return `cond(isSyntheticCPU` + suffix + ` == 0, cpu` + suffix + `, syntheticCPU` + suffix + `)`
+}
+
+// getMemoryForCost returns the memory costed for the resource, the synthetic memory request declared by
+// getQueryForSyntheticRequests if it has one
+func getMemoryForCost(suffix string) string {
+	return `cond(isSyntheticMemory` + suffix + ` == 0, memory` + suffix + `, syntheticMemory` + suffix + `)`
+}
+
+// getQueryForLocalResourcePrice declares the ephemeral storage and hugepages prices of the pod, pods stored before
+// local resources were priced have none
+func getQueryForLocalResourcePrice(suffix string) string {
+	return `isEphemeralStoragePriced` + suffix + ` as count(ephemeralStoragePrice)
+			pricePerEphemeralStorage` + suffix + ` as ephemeralStoragePrice
+			isHugepagesPriced` + suffix + ` as count(hugepagesPrice)
+			pricePerHugepages` + suffix + ` as hugepagesPrice`
+}
+
+// getEphemeralStoragePrice returns the ephemeral storage price declared by getQueryForLocalResourcePrice,
+// DefaultLocalDiskCostInFloat64 if the pod has none
+func getEphemeralStoragePrice(suffix string) string {
+	return `cond(isEphemeralStoragePriced` + suffix + ` == 0, ` + formatPrice(models.DefaultLocalDiskCostInFloat64) + `, pricePerEphemeralStorage` + suffix + `)`
+}
+
+// getHugepagesPrice returns the hugepages price declared by getQueryForLocalResourcePrice, the memory price if the
+// pod has none
+func getHugepagesPrice(suffix string) string {
+	return `cond(isHugepagesPriced` + suffix + ` == 0, pricePerMemory` + suffix + `, pricePerHugepages` + suffix + `)`
+}
+
 func getQueryForCostWithPriceWithAliasAndVariables(suffix string) string {
 	return `pricePerCPU` + suffix + ` as cpuPrice
 			pricePerMemory` + suffix + ` as memoryPrice
-			cpuCost: cpuCost` + suffix + ` as math(cpu` + suffix + ` * durationInHours` + suffix + ` * pricePerCPU` + suffix + `)
-			memoryCost: memoryCost` + suffix + ` as math(memory` + suffix + ` * durationInHours` + suffix + ` * pricePerMemory` + suffix + `)
-			storageCost: storageCost` + suffix + ` as math(storage` + suffix + ` * durationInHours` + suffix + ` * ` + models.DefaultStorageCostPerGBPerHour + `)`
+			` + getQueryForStoragePrice(suffix) + `
+			` + getQueryForSyntheticRequests(suffix) + `
+			cpuCost: cpuCost` + suffix + ` as math(` + getCPUForCost(suffix) + ` * durationInHours` + suffix + ` * pricePerCPU` + suffix + `)
+			memoryCost: memoryCost` + suffix + ` as math(` + getMemoryForCost(suffix) + ` * durationInHours` + suffix + ` * pricePerMemory` + suffix + `)
+			storageCost: storageCost` + suffix + ` as math(storage` + suffix + ` * durationInHours` + suffix + ` * ` + getStoragePrice(suffix) + `)
+			` + getQueryForLocalResourcePrice(suffix) + `
+			ephemeralStorageCost: ephemeralStorageCost` + suffix + ` as math(ephemeralStorage` + suffix + ` * durationInHours` + suffix + ` * ` + getEphemeralStoragePrice(suffix) + `)
+			hugepagesCost: hugepagesCost` + suffix + ` as math(hugepages` + suffix + ` * durationInHours` + suffix + ` * ` + getHugepagesPrice(suffix) + `)
+			pricePerExtendedResources` + suffix + ` as extendedResourcePrice
+			extendedResourceCost: extendedResourceCost` + suffix + ` as math(pricePerExtendedResources` + suffix + ` * durationInHours` + suffix + `)
+			pricePerBandwidth` + suffix + ` as bandwidthPrice
+			bandwidthCost: bandwidthCost` + suffix + ` as math(pricePerBandwidth` + suffix + ` * durationInHours` + suffix + `)
+			carbonPerCPU` + suffix + ` as cpuCarbon
+			carbonPerMemory` + suffix + ` as memoryCarbon
+			carbon: carbon` + suffix + ` as math((` + getCPUForCost(suffix) + ` * carbonPerCPU` + suffix + ` + ` + getMemoryForCost(suffix) + ` * carbonPerMemory` + suffix + `) * durationInHours` + suffix + `)
+			energy: energyKWh` + suffix + ` as energy`
 }
 
 func getQueryForCostWithPriceWithAlias(suffix string) string {
 	return `pricePerCPU` + suffix + ` as cpuPrice
 			pricePerMemory` + suffix + ` as memoryPrice
-			cpuCost: math(cpu` + suffix + ` * durationInHours` + suffix + ` * pricePerCPU` + suffix + `)
-			memoryCost: math(memory` + suffix + ` * durationInHours` + suffix + ` * pricePerMemory` + suffix + `)
-			storageCost: math(storage` + suffix + ` * durationInHours` + suffix + ` * ` + models.DefaultStorageCostPerGBPerHour + `)`
+			` + getQueryForStoragePrice(suffix) + `
+			` + getQueryForSyntheticRequests(suffix) + `
+			cpuCost: math(` + getCPUForCost(suffix) + ` * durationInHours` + suffix + ` * pricePerCPU` + suffix + `)
+			memoryCost: math(` + getMemoryForCost(suffix) + ` * durationInHours` + suffix + ` * pricePerMemory` + suffix + `)
+			storageCost: math(storage` + suffix + ` * durationInHours` + suffix + ` * ` + getStoragePrice(suffix) + `)
+			pricePerExtendedResources` + suffix + ` as extendedResourcePrice
+			extendedResourceCost: math(pricePerExtendedResources` + suffix + ` * durationInHours` + suffix + `)
+			pricePerBandwidth` + suffix + ` as bandwidthPrice
+			bandwidthCost: math(pricePerBandwidth` + suffix + ` * durationInHours` + suffix + `)
+			carbonPerCPU` + suffix + ` as cpuCarbon
+			carbonPerMemory` + suffix + ` as memoryCarbon
+			carbon: math((` + getCPUForCost(suffix) + ` * carbonPerCPU` + suffix + ` + ` + getMemoryForCost(suffix) + ` * carbonPerMemory` + suffix + `) * durationInHours` + suffix + `)
+			energy`
 }
 
 func getQueryForCostWithPrice(suffix string) string {
 	return `pricePerCPU` + suffix + ` as cpuPrice
 			pricePerMemory` + suffix + ` as memoryPrice
-			cpuCost` + suffix + ` as math(cpu` + suffix + ` * durationInHours` + suffix + ` * pricePerCPU` + suffix + `)
-			memoryCost` + suffix + ` as math(memory` + suffix + ` * durationInHours` + suffix + ` * pricePerMemory` + suffix + `)
-			storageCost` + suffix + ` as math(storage` + suffix + ` * durationInHours` + suffix + ` * ` + models.DefaultStorageCostPerGBPerHour + `)`
+			` + getQueryForStoragePrice(suffix) + `
+			` + getQueryForSyntheticRequests(suffix) + `
+			cpuCost` + suffix + ` as math(` + getCPUForCost(suffix) + ` * durationInHours` + suffix + ` * pricePerCPU` + suffix + `)
+			memoryCost` + suffix + ` as math(` + getMemoryForCost(suffix) + ` * durationInHours` + suffix + ` * pricePerMemory` + suffix + `)
+			storageCost` + suffix + ` as math(storage` + suffix + ` * durationInHours` + suffix + ` * ` + getStoragePrice(suffix) + `)
+			` + getQueryForLocalResourcePrice(suffix) + `
+			ephemeralStorageCost` + suffix + ` as math(ephemeralStorage` + suffix + ` * durationInHours` + suffix + ` * ` + getEphemeralStoragePrice(suffix) + `)
+			hugepagesCost` + suffix + ` as math(hugepages` + suffix + ` * durationInHours` + suffix + ` * ` + getHugepagesPrice(suffix) + `)
+			pricePerExtendedResources` + suffix + ` as extendedResourcePrice
+			extendedResourceCost` + suffix + ` as math(pricePerExtendedResources` + suffix + ` * durationInHours` + suffix + `)
+			pricePerBandwidth` + suffix + ` as bandwidthPrice
+			bandwidthCost` + suffix + ` as math(pricePerBandwidth` + suffix + ` * durationInHours` + suffix + `)
+			carbonPerCPU` + suffix + ` as cpuCarbon
+			carbonPerMemory` + suffix + ` as memoryCarbon
+			carbon` + suffix + ` as math((` + getCPUForCost(suffix) + ` * carbonPerCPU` + suffix + ` + ` + getMemoryForCost(suffix) + ` * carbonPerMemory` + suffix + `) * durationInHours` + suffix + `)
+			energyKWh` + suffix + ` as energy`
 }
 
 func getQueryForAggregatingChildMetricsWithAlias(childSuffix string) string {
@@ -117,7 +295,17 @@ func getQueryForAggregatingChildMetricsWithAlias(childSuffix string) string {
 			storage: sum(val(storage` + childSuffix + `))
 			cpuCost: sum(val(cpuCost` + childSuffix + `))
 			memoryCost: sum(val(memoryCost` + childSuffix + `))
-			storageCost: sum(val(storageCost` + childSuffix + `))`
+			storageCost: sum(val(storageCost` + childSuffix + `))
+			ephemeralStorageCost: sum(val(ephemeralStorageCost` + childSuffix + `))
+			hugepagesCost: sum(val(hugepagesCost` + childSuffix + `))
+			gpu: sum(val(gpu` + childSuffix + `))
+			gpuCost: sum(val(gpuCost` + childSuffix + `))
+			extendedResourceCost: sum(val(extendedResourceCost` + childSuffix + `))
+			bandwidthCost: sum(val(bandwidthCost` + childSuffix + `))
+			carbon: sum(val(carbon` + childSuffix + `))
+			energy: sum(val(energyKWh` + childSuffix + `))
+			utilizedCPUCost: sum(val(utilizedCPUCost` + childSuffix + `))
+			utilizedMemoryCost: sum(val(utilizedMemoryCost` + childSuffix + `))`
 }
 
 func getQueryForAggregatingChildMetrics(parentSuffix, childSuffix string) string {
@@ -126,7 +314,17 @@ func getQueryForAggregatingChildMetrics(parentSuffix, childSuffix string) string
 			storage` + parentSuffix + ` as sum(val(storage` + childSuffix + `))
 			cpuCost` + parentSuffix + ` as sum(val(cpuCost` + childSuffix + `))
 			memoryCost` + parentSuffix + ` as sum(val(memoryCost` + childSuffix + `))
-			storageCost` + parentSuffix + ` as sum(val(storageCost` + childSuffix + `))`
+			storageCost` + parentSuffix + ` as sum(val(storageCost` + childSuffix + `))
+			ephemeralStorageCost` + parentSuffix + ` as sum(val(ephemeralStorageCost` + childSuffix + `))
+			hugepagesCost` + parentSuffix + ` as sum(val(hugepagesCost` + childSuffix + `))
+			gpu` + parentSuffix + ` as sum(val(gpu` + childSuffix + `))
+			gpuCost` + parentSuffix + ` as sum(val(gpuCost` + childSuffix + `))
+			extendedResourceCost` + parentSuffix + ` as sum(val(extendedResourceCost` + childSuffix + `))
+			bandwidthCost` + parentSuffix + ` as sum(val(bandwidthCost` + childSuffix + `))
+			carbon` + parentSuffix + ` as sum(val(carbon` + childSuffix + `))
+			energyKWh` + parentSuffix + ` as sum(val(energyKWh` + childSuffix + `))
+			utilizedCPUCost` + parentSuffix + ` as sum(val(utilizedCPUCost` + childSuffix + `))
+			utilizedMemoryCost` + parentSuffix + ` as sum(val(utilizedMemoryCost` + childSuffix + `))`
 }
 
 func getQueryFromSubQueryWithAlias(suffix string) string {
@@ -137,29 +335,177 @@ func getQueryFromSubQueryWithAlias(suffix string) string {
 			storage: val(storage` + suffix + `)
 			cpuCost: val(cpuCost` + suffix + `)
 			memoryCost: val(memoryCost` + suffix + `)
-			storageCost: val(storageCost` + suffix + `)`
+			storageCost: val(storageCost` + suffix + `)
+			ephemeralStorageCost: val(ephemeralStorageCost` + suffix + `)
+			hugepagesCost: val(hugepagesCost` + suffix + `)
+			gpu: val(gpu` + suffix + `)
+			gpuCost: val(gpuCost` + suffix + `)
+			extendedResourceCost: val(extendedResourceCost` + suffix + `)
+			bandwidthCost: val(bandwidthCost` + suffix + `)
+			carbon: val(carbon` + suffix + `)
+			energy: val(energyKWh` + suffix + `)
+			utilizedCPUCost: val(utilizedCPUCost` + suffix + `)
+			utilizedMemoryCost: val(utilizedMemoryCost` + suffix + `)`
+}
+
+// getOSFilter returns the condition to be added to pod or node filters to restrict them to the given os,
+// empty string if os is not given
+func getOSFilter(os string) string {
+	if os == "" {
+		return ""
+	}
+	return " AND " + qb.Eq("os", os).String()
+}
+
+// getDeletedFilter returns the condition to be added to namespace filters to exclude deleted namespaces or to
+// retrieve only them, empty string if deleted namespaces are included
+func getDeletedFilter(deleted string) string {
+	switch deleted {
+	case Exclude:
+		return " AND " + qb.Not(qb.Has("deleted")).String()
+	case Only:
+		return " AND " + qb.Has("deleted").String()
+	default:
+		return ""
+	}
+}
+
+// getAsOfCondition returns the condition restricting resources to those existing at asOf, empty if asOf is not given
+func getAsOfCondition(asOf string) qb.Filter {
+	if asOf == "" {
+		return qb.Filter{}
+	}
+	return qb.And(qb.Le("startTime", asOf), qb.Or(qb.Not(qb.Has("endTime")), qb.Gt("endTime", asOf)))
+}
+
+// getNamespaceVar returns a block collecting uids of resources of the namespace $namespace in namespaceResources,
+// empty string if no namespace is given
+func getNamespaceVar(namespace string) string {
+	if namespace == "" {
+		return ""
+	}
+	return `var(func: has(isNamespace)) @filter(eq(name, $namespace)) {
+			namespaceResources as ~namespace
+		}`
+}
+
+// getNamespaceFilter returns the condition to be added to filters to restrict resources to those collected by
+// getNamespaceVar, empty string if no namespace is given
+func getNamespaceFilter(namespace string) string {
+	if namespace == "" {
+		return ""
+	}
+	return " AND uid(namespaceResources)"
+}
+
+// getNamespaceVars returns vars with $namespace set to the namespace if it is given
+func getNamespaceVars(vars qb.Vars, namespace string) qb.Vars {
+	if namespace == "" {
+		return vars
+	}
+	if vars == nil {
+		vars = qb.Vars{}
+	}
+	vars["$namespace"] = namespace
+	return vars
+}
+
+// getAsOfFilter returns the condition to be added to filters to restrict resources to those existing at asOf,
+// empty string if asOf is not given
+func getAsOfFilter(asOf string) string {
+	if asOf == "" {
+		return ""
+	}
+	return " AND " + getAsOfCondition(asOf).String()
+}
+
+// getLiveFilter returns the condition restricting resources to live ones, or to those existing at asOf if it is given
+func getLiveFilter(asOf string) string {
+	if asOf == "" {
+		return "(NOT has(endTime))"
+	}
+	return strings.TrimPrefix(getAsOfFilter(asOf), " AND ")
+}
+
+// getTimeRangeCondition returns the condition restricting resources to those existing at some time in the time range,
+// which is the condition for existing at end if no start is given
+func getTimeRangeCondition(timeRange TimeRange) qb.Filter {
+	if timeRange.Start == "" {
+		return getAsOfCondition(timeRange.End)
+	}
+	startCondition := qb.Or(qb.Not(qb.Has("endTime")), qb.Gt("endTime", timeRange.Start))
+	if timeRange.End == "" {
+		return startCondition
+	}
+	return qb.And(qb.Le("startTime", timeRange.End), startCondition)
+}
+
+// getTimeRangeFilter returns the condition to be added to filters to restrict resources to those existing in the
+// time range, empty string if the time range is empty
+func getTimeRangeFilter(timeRange TimeRange) string {
+	if timeRange.Start == "" {
+		return getAsOfFilter(timeRange.End)
+	}
+	return " AND (" + getTimeRangeCondition(timeRange).String() + ")"
+}
+
+// getLiveFilterInRange returns the condition restricting resources to live ones, or to those existing in the time
+// range if it is given
+func getLiveFilterInRange(timeRange TimeRange) string {
+	if timeRange.Start == "" {
+		return getLiveFilter(timeRange.End)
+	}
+	return "(" + getTimeRangeCondition(timeRange).String() + ")"
+}
+
+// getAsOfDirective returns filter directive restricting resources to those existing at asOf,
+// empty string if asOf is not given
+func getAsOfDirective(asOf string) string {
+	if asOf == "" {
+		return ""
+	}
+	return "@filter(" + getLiveFilter(asOf) + ")"
+}
+
+// getChildFilter returns the child filter of the resource restricted to children existing in the time range of the resource
+func (r *Resource) getChildFilter() string {
+	timeRange := r.getTimeRange()
+	if timeRange == (TimeRange{}) || !strings.HasPrefix(r.ChildFilter, "@filter(") || !strings.HasSuffix(r.ChildFilter, ")") {
+		return r.ChildFilter
+	}
+	condition := strings.TrimSuffix(strings.TrimPrefix(r.ChildFilter, "@filter("), ")")
+	return "@filter((" + condition + ")" + getTimeRangeFilter(timeRange) + ")"
 }
 
-func (r *Resource) getQueryForPodParentMetrics() string {
-	return `query {
-		parent(func: has(` + r.Check + `)) @filter(eq(name, "` + r.Name + `")) {
-			children: ~` + r.Type + ` @filter(has(isPod)) {
-				` + getQueryForMetricsComputationWithAliasAndVariables("Pod") + `
+// getQueryForPodParentMetrics returns the query of the resource owning pods(ex: a statefulset) with costs of its pods,
+// it is looked up in the namespace of the resource if it has one
+func (r *Resource) getQueryForPodParentMetrics() (string, qb.Vars) {
+	vars := getNamespaceVars(qb.Vars{"$name": r.Name}, r.Namespace)
+	return vars.Declaration() + ` {
+		` + getNamespaceVar(r.Namespace) + `
+		parent(func: has(` + r.Check + `)) @filter(eq(name, $name)` + getNamespaceFilter(r.Namespace) + `) {
+			children: ~` + r.Type + ` @filter(has(isPod)` + getTimeRangeFilter(r.getTimeRange()) + `) {
+				` + getQueryForMetricsComputationWithAliasAndVariablesInRange("Pod", r.getTimeRange()) + `
 			}
 			` + getQueryForAggregatingChildMetricsWithAlias("Pod") + `
 		}
-	}`
+	}`, vars
 }
 
-func (r *Resource) getQueryForHierarchy() string {
-	return `query {
-		parent(func: has(` + r.Check + `)) @filter(eq(name, "` + r.Name + `")) {
+// getQueryForHierarchy returns the resource with its children, only if it is in the namespace of the resource when
+// it has one
+func (r *Resource) getQueryForHierarchy() (string, qb.Vars) {
+	vars := getNamespaceVars(qb.Vars{"$name": r.Name}, r.Namespace)
+	return vars.Declaration() + ` {
+		` + getNamespaceVar(r.Namespace) + `
+		parent(func: has(` + r.Check + `)) @filter(eq(name, $name)` + getNamespaceFilter(r.Namespace) + `) {
 			name
 			type
-			children: ~` + r.Type + ` ` + r.ChildFilter + ` {
+			children: ~` + r.Type + r.Page.getEdgeArguments() + ` ` + r.getChildFilter() + ` {
+				` + r.Page.getUIDField() + `
 				name
 				type
 			}
 		}
-	}`
+	}`, vars
 }
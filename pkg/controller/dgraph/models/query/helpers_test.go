@@ -18,10 +18,12 @@
 package query
 
 import (
+	"regexp"
 	"strconv"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/vmware/purser/pkg/controller/dgraph/models"
 )
 
 // TestGetSecondsSinceMonthStart ...
@@ -33,3 +35,136 @@ func TestGetSecondsSinceMonthStart(t *testing.T) {
 	assert.False(t, gotFloat > maxSecondsInAMonth, "secondsSinceMonthStart can't be greater than 2678400")
 	assert.False(t, gotFloat < 0, "secondsSinceMonthStart can't be less than 0")
 }
+
+// TestGetChildFilterAsOf ...
+func TestGetChildFilterAsOf(t *testing.T) {
+	r := Resource{ChildFilter: IsPodFilter}
+	assert.Equal(t, IsPodFilter, r.getChildFilter())
+
+	r.AsOf = "2018-10-01T00:00:00Z"
+	expected := `@filter((has(isPod)) AND le(startTime, "2018-10-01T00:00:00Z") AND (NOT has(endTime) OR gt(endTime, "2018-10-01T00:00:00Z")))`
+	assert.Equal(t, expected, r.getChildFilter())
+}
+
+// withoutSeconds replaces seconds since start of the month, which change between calls, in the query
+func withoutSeconds(query string) string {
+	return regexp.MustCompile(`[0-9]+\.[0-9]+`).ReplaceAllString(query, "seconds")
+}
+
+// TestGetQueryForTimeComputationAsOf ...
+func TestGetQueryForTimeComputationAsOf(t *testing.T) {
+	assert.Equal(t, withoutSeconds(getQueryForTimeComputation("Pod")), withoutSeconds(getQueryForTimeComputationAsOf("Pod", "")))
+
+	got := getQueryForTimeComputationAsOf("Pod", "2018-10-15T00:00:00Z")
+	assert.NotEqual(t, getQueryForTimeComputation("Pod"), got)
+	assert.Contains(t, got, "max(since(etPod)")
+}
+
+// TestGetQueryForTimeComputationInRange ...
+func TestGetQueryForTimeComputationInRange(t *testing.T) {
+	assert.Equal(t, withoutSeconds(getQueryForTimeComputation("Pod")), withoutSeconds(getQueryForTimeComputationInRange("Pod", TimeRange{})))
+
+	got := getQueryForTimeComputationInRange("Pod", TimeRange{Start: "2018-10-03T00:00:00Z"})
+	assert.Contains(t, got, "cond(isTerminatedPod == 0, 0.0, since(etPod))")
+	assert.NotEqual(t, getQueryForTimeComputation("Pod"), got)
+
+	got = getQueryForTimeComputationInRange("Pod", TimeRange{Start: "2018-10-03T00:00:00Z", End: "2018-10-17T00:00:00Z"})
+	assert.Contains(t, got, "max(since(etPod)")
+	assert.NotEqual(t, getQueryForTimeComputationAsOf("Pod", "2018-10-17T00:00:00Z"), got)
+}
+
+// TestGetChildFilterInRange ...
+func TestGetChildFilterInRange(t *testing.T) {
+	r := Resource{ChildFilter: IsPodFilter, Start: "2018-10-03T00:00:00Z"}
+	expected := `@filter((has(isPod)) AND (NOT has(endTime) OR gt(endTime, "2018-10-03T00:00:00Z")))`
+	assert.Equal(t, expected, r.getChildFilter())
+
+	r.AsOf = "2018-10-17T00:00:00Z"
+	expected = `@filter((has(isPod)) AND (le(startTime, "2018-10-17T00:00:00Z") AND (NOT has(endTime) OR gt(endTime, "2018-10-03T00:00:00Z"))))`
+	assert.Equal(t, expected, r.getChildFilter())
+
+	r.End = "2018-10-10T00:00:00Z"
+	assert.Equal(t, TimeRange{Start: "2018-10-03T00:00:00Z", End: "2018-10-10T00:00:00Z"}, r.getTimeRange())
+}
+
+// TestGetQueryForMetricsComputationWithGPU ...
+func TestGetQueryForMetricsComputationWithGPU(t *testing.T) {
+	got := getQueryForMetricsComputationInRange("NamespacePod", TimeRange{})
+	assert.Contains(t, got, "gpuNamespacePod as gpuRequest")
+	assert.Contains(t, got, "gpuCostNamespacePod as math(gpuNamespacePod * durationInHoursNamespacePod * pricePerGPUNamespacePod)")
+
+	got = getQueryForAggregatingChildMetrics("Namespace", "NamespacePod")
+	assert.Contains(t, got, "gpuCostNamespace as sum(val(gpuCostNamespacePod))")
+	assert.Contains(t, getQueryFromSubQueryWithAlias("Namespace"), "gpuCost: val(gpuCostNamespace)")
+}
+
+// TestGetQueryForMetricsComputationWithUsage ...
+func TestGetQueryForMetricsComputationWithUsage(t *testing.T) {
+	got := getQueryForMetricsComputationInRange("NamespacePod", TimeRange{})
+	assert.Contains(t, got, "cpuUsageNamespacePod as cpuUsage")
+	assert.Contains(t, got, "utilizedCPUCostNamespacePod as math(cpuUsageNamespacePod * durationInHoursNamespacePod * pricePerCPUNamespacePod)")
+	assert.Contains(t, got, "utilizedMemoryCostNamespacePod as math(memoryUsageNamespacePod * durationInHoursNamespacePod * pricePerMemoryNamespacePod)")
+
+	got = getQueryForMetricsComputationWithAliasInRange("Pod", TimeRange{})
+	assert.Contains(t, got, "utilizedCPUCost: math(cpuUsagePod * durationInHoursPod * pricePerCPUPod)")
+
+	got = getQueryForAggregatingChildMetrics("Namespace", "NamespacePod")
+	assert.Contains(t, got, "utilizedCPUCostNamespace as sum(val(utilizedCPUCostNamespacePod))")
+	assert.Contains(t, getQueryFromSubQueryWithAlias("Namespace"), "utilizedMemoryCost: val(utilizedMemoryCostNamespace)")
+}
+
+// TestGetQueryForMetricsComputationWithStoragePrice ...
+func TestGetQueryForMetricsComputationWithStoragePrice(t *testing.T) {
+	got := getQueryForMetricsComputationInRange("NamespacePod", TimeRange{})
+	assert.Contains(t, got, "isStoragePricedNamespacePod as count(storagePrice)")
+	assert.Contains(t, got, "storageCostNamespacePod as math(storageNamespacePod * durationInHoursNamespacePod * cond(isStoragePricedNamespacePod == 0, "+formatPrice(models.DefaultStorageCostInFloat64)+", pricePerStorageNamespacePod))")
+
+	query, _ := getQueryForPVCMetrics("pvc-data", TimeRange{})
+	assert.Contains(t, query, "storageCost: math(storage * durationInHours * cond(isStoragePriced == 0, ")
+}
+
+// TestGetQueryForMetricsComputationWithLocalResources ...
+func TestGetQueryForMetricsComputationWithLocalResources(t *testing.T) {
+	got := getQueryForMetricsComputationInRange("NamespacePod", TimeRange{})
+	assert.Contains(t, got, "ephemeralStorageNamespacePod as ephemeralStorageRequest")
+	assert.Contains(t, got, "ephemeralStorageCostNamespacePod as math(ephemeralStorageNamespacePod * durationInHoursNamespacePod * cond(isEphemeralStoragePricedNamespacePod == 0, "+formatPrice(models.DefaultLocalDiskCostInFloat64)+", pricePerEphemeralStorageNamespacePod))")
+	assert.Contains(t, got, "hugepagesCostNamespacePod as math(hugepagesNamespacePod * durationInHoursNamespacePod * cond(isHugepagesPricedNamespacePod == 0, pricePerMemoryNamespacePod, pricePerHugepagesNamespacePod))")
+
+	got = getQueryForMetricsComputationWithAliasAndVariablesInRange("Pod", TimeRange{})
+	assert.Contains(t, got, "ephemeralStorageCost: ephemeralStorageCostPod as math(")
+	assert.Contains(t, got, "hugepagesCost: hugepagesCostPod as math(")
+
+	got = getQueryForAggregatingChildMetrics("Namespace", "NamespacePod")
+	assert.Contains(t, got, "ephemeralStorageCostNamespace as sum(val(ephemeralStorageCostNamespacePod))")
+	assert.Contains(t, got, "hugepagesCostNamespace as sum(val(hugepagesCostNamespacePod))")
+
+	got = getQueryForAggregatingChildMetricsWithAlias("Pod")
+	assert.Contains(t, got, "ephemeralStorageCost: sum(val(ephemeralStorageCostPod))")
+	assert.Contains(t, got, "hugepagesCost: sum(val(hugepagesCostPod))")
+
+	got = getQueryFromSubQueryWithAlias("Namespace")
+	assert.Contains(t, got, "ephemeralStorageCost: val(ephemeralStorageCostNamespace)")
+	assert.Contains(t, got, "hugepagesCost: val(hugepagesCostNamespace)")
+
+	query, _ := getQueryForNamespaceMetrics("default", "", TimeRange{})
+	assert.Contains(t, query, "hugepagesCostNamespaceChild as math(hugepagesCostSumReplicasetSimplePod + ")
+	assert.Contains(t, query, "ephemeralStorageCostNamespace as sum(val(ephemeralStorageCostNamespaceChild))")
+}
+
+// TestGetQueryForMetricsComputationWithSyntheticRequests ...
+func TestGetQueryForMetricsComputationWithSyntheticRequests(t *testing.T) {
+	assert.Contains(t, getQueryForMetricsComputationWithAliasInRange("Pod", TimeRange{}), "syntheticRequests")
+	assert.Contains(t, getQueryForMetricsComputationWithAliasAndVariablesInRange("Pod", TimeRange{}), "syntheticRequests")
+
+	query := getQueryForMetricsComputationWithAliasInRange("Pod", TimeRange{})
+	assert.Contains(t, query, "cpu: cpuPod as cpuRequest")
+	assert.Contains(t, query, "cpuCost: math(cond(isSyntheticCPUPod == 0, cpuPod, syntheticCPUPod) * durationInHoursPod * pricePerCPUPod)")
+	assert.Contains(t, query, "memoryCost: math(cond(isSyntheticMemoryPod == 0, memoryPod, syntheticMemoryPod) * durationInHoursPod * pricePerMemoryPod)")
+}
+
+// TestIsValidName ...
+func TestIsValidName(t *testing.T) {
+	assert.True(t, IsValidName("pod-web-1*2018-10-10T10:10:10Z"))
+	assert.False(t, IsValidName(`pod") { uid }`))
+	assert.False(t, IsValidName(""))
+}
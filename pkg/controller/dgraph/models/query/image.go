@@ -0,0 +1,171 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"context"
+	"sort"
+
+	"github.com/vmware/purser/pkg/controller/dgraph/models"
+)
+
+// Image constants, costs are grouped by image repository(ex: docker.io/library/nginx) or by registry(ex: gcr.io)
+const (
+	ImageType    = "image"
+	RegistryType = "registry"
+	Repository   = "repository"
+	Registry     = "registry"
+)
+
+// ImageCost is the month to date cost of containers of an image repository or registry. Containers is the
+// number of containers which ran this month, Running is the number of live containers.
+type ImageCost struct {
+	Name         string   `json:"name"`
+	Registry     string   `json:"registry,omitempty"`
+	Tags         []string `json:"tags,omitempty"`
+	Repositories []string `json:"repositories,omitempty"`
+	Containers   int      `json:"containers"`
+	Running      int      `json:"running"`
+	CPUCost      float64  `json:"cpuCost"`
+	MemoryCost   float64  `json:"memoryCost"`
+	TotalCost    float64  `json:"totalCost"`
+}
+
+// ImageCostsWrapper structure
+type ImageCostsWrapper struct {
+	Data []ImageCost `json:"data"`
+}
+
+type imageContainer struct {
+	ImageRegistry   string  `json:"imageRegistry"`
+	ImageRepository string  `json:"imageRepository"`
+	ImageTag        string  `json:"imageTag"`
+	EndTime         string  `json:"endTime"`
+	Hours           float64 `json:"hours"`
+	CPU             float64 `json:"cpu"`
+	Memory          float64 `json:"memory"`
+	CPUPrice        float64 `json:"cpuPrice"`
+	MemoryPrice     float64 `json:"memoryPrice"`
+	Pod             *struct {
+		CPUPrice    float64 `json:"cpuPrice"`
+		MemoryPrice float64 `json:"memoryPrice"`
+	} `json:"pod"`
+}
+
+// RetrieveImageCosts returns month to date cost of containers grouped by image repository, or by registry if
+// groupBy is registry, highest cost first. Containers are priced with their own prices(price overrides) if
+// present, otherwise with the prices of their pod.
+func RetrieveImageCosts(ctx context.Context, groupBy string) ImageCostsWrapper {
+	type root struct {
+		Containers []imageContainer `json:"containers"`
+	}
+	newRoot := root{}
+	err := executeQuery(ctx, getQueryForImageContainers(), &newRoot)
+	if err != nil {
+		log.Errorf("unable to retrieve images of containers, err: %v", err)
+		return ImageCostsWrapper{}
+	}
+
+	resourceType, keyOf := ImageType, func(c imageContainer) string { return c.ImageRepository }
+	if groupBy == Registry {
+		resourceType, keyOf = RegistryType, func(c imageContainer) string { return c.ImageRegistry }
+	}
+	costs := make(map[string]*ImageCost)
+	for _, container := range newRoot.Containers {
+		if container.Hours == 0 && container.EndTime != "" {
+			continue
+		}
+		key := keyOf(container)
+		if _, isPresent := costs[key]; !isPresent {
+			costs[key] = &ImageCost{Name: key}
+		}
+		addImageContainer(costs[key], resourceType, container)
+	}
+
+	data := []ImageCost{}
+	for _, cost := range costs {
+		cost.CPUCost = adjustCost(CostContext{resourceType, cost.Name, CPUCostType}, cost.CPUCost)
+		cost.MemoryCost = adjustCost(CostContext{resourceType, cost.Name, MemoryCostType}, cost.MemoryCost)
+		cost.TotalCost = cost.CPUCost + cost.MemoryCost
+		sort.Strings(cost.Tags)
+		sort.Strings(cost.Repositories)
+		data = append(data, *cost)
+	}
+	sort.Slice(data, func(i, j int) bool {
+		if data[i].TotalCost != data[j].TotalCost {
+			return data[i].TotalCost > data[j].TotalCost
+		}
+		return data[i].Name < data[j].Name
+	})
+	return ImageCostsWrapper{Data: data}
+}
+
+// addImageContainer adds cost of the container to its image repository or registry and records its tag or repository
+func addImageContainer(cost *ImageCost, resourceType string, container imageContainer) {
+	cost.Containers++
+	if container.EndTime == "" {
+		cost.Running++
+	}
+	cpuPrice, memoryPrice := getImageContainerPrices(container)
+	cost.CPUCost += container.CPU * container.Hours * cpuPrice
+	cost.MemoryCost += container.Memory * container.Hours * memoryPrice
+
+	if resourceType == RegistryType {
+		if !containsString(cost.Repositories, container.ImageRepository) {
+			cost.Repositories = append(cost.Repositories, container.ImageRepository)
+		}
+		return
+	}
+	cost.Registry = container.ImageRegistry
+	if !containsString(cost.Tags, container.ImageTag) {
+		cost.Tags = append(cost.Tags, container.ImageTag)
+	}
+}
+
+// getImageContainerPrices returns price per cpu and per GB of memory of the container, prices of its pod if it
+// has none and default prices if its pod has none either
+func getImageContainerPrices(container imageContainer) (float64, float64) {
+	if container.CPUPrice != 0 {
+		return container.CPUPrice, container.MemoryPrice
+	}
+	if container.Pod != nil && container.Pod.CPUPrice != 0 {
+		return container.Pod.CPUPrice, container.Pod.MemoryPrice
+	}
+	return models.DefaultCPUCostInFloat64, models.DefaultMemCostInFloat64
+}
+
+func getQueryForImageContainers() string {
+	return `query {
+		containers(func: has(imageRepository)) @filter(has(isContainer)) {
+			imageRegistry
+			imageRepository
+			imageTag
+			endTime
+			` + getQueryForTimeComputation("Container") + `
+			hours: val(durationInHoursContainer)
+			cpu: cpuRequest
+			memory: memoryRequest
+			cpuPrice
+			memoryPrice
+			pod {
+				cpuPrice
+				memoryPrice
+			}
+		}
+	}`
+}
@@ -0,0 +1,75 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func mockDgraphForImageCosts() {
+	executeQuery = func(ctx context.Context, query string, root interface{}) error {
+		return json.Unmarshal([]byte(`{"containers": [
+			{"imageRegistry": "docker.io", "imageRepository": "docker.io/library/nginx", "imageTag": "1.15", "hours": 10, "cpu": 1, "memory": 2, "pod": {"cpuPrice": 0.05, "memoryPrice": 0.02}},
+			{"imageRegistry": "docker.io", "imageRepository": "docker.io/library/nginx", "imageTag": "1.14", "endTime": "2018-10-05T00:00:00Z", "hours": 20, "cpu": 0.5, "memory": 1},
+			{"imageRegistry": "docker.io", "imageRepository": "docker.io/library/nginx", "imageTag": "1.13", "endTime": "2018-09-05T00:00:00Z", "hours": 0, "cpu": 1, "memory": 1},
+			{"imageRegistry": "gcr.io", "imageRepository": "gcr.io/google-containers/pause", "imageTag": "3.1", "hours": 100, "cpu": 0.1, "memory": 0.1, "cpuPrice": 0.1, "memoryPrice": 0.1},
+			{"imageRegistry": "docker.io", "imageRepository": "docker.io/bitnami/redis", "imageTag": "5.0", "hours": 10, "cpu": 1, "memory": 1}
+		]}`), root)
+	}
+}
+
+// TestRetrieveImageCosts ...
+func TestRetrieveImageCosts(t *testing.T) {
+	mockDgraphForImageCosts()
+	got := RetrieveImageCosts(context.Background(), "").Data
+
+	assert.Equal(t, 3, len(got))
+	assert.Equal(t, "gcr.io/google-containers/pause", got[0].Name)
+	assert.InDelta(t, 2, got[0].TotalCost, 0.0001)
+
+	assert.Equal(t, "docker.io/library/nginx", got[1].Name)
+	assert.Equal(t, "docker.io", got[1].Registry)
+	assert.Equal(t, []string{"1.14", "1.15"}, got[1].Tags)
+	assert.Equal(t, 2, got[1].Containers)
+	assert.Equal(t, 1, got[1].Running)
+	assert.InDelta(t, 0.5+0.24, got[1].CPUCost, 0.0001)
+	assert.InDelta(t, 0.4+0.2, got[1].MemoryCost, 0.0001)
+	assert.InDelta(t, 1.34, got[1].TotalCost, 0.0001)
+
+	assert.Equal(t, "docker.io/bitnami/redis", got[2].Name)
+	assert.InDelta(t, 0.34, got[2].TotalCost, 0.0001)
+}
+
+// TestRetrieveImageCostsByRegistry ...
+func TestRetrieveImageCostsByRegistry(t *testing.T) {
+	mockDgraphForImageCosts()
+	got := RetrieveImageCosts(context.Background(), Registry).Data
+
+	assert.Equal(t, 2, len(got))
+	assert.Equal(t, "gcr.io", got[0].Name)
+	assert.Equal(t, "docker.io", got[1].Name)
+	assert.Equal(t, []string{"docker.io/bitnami/redis", "docker.io/library/nginx"}, got[1].Repositories)
+	assert.Equal(t, 3, got[1].Containers)
+	assert.Equal(t, 2, got[1].Running)
+	assert.InDelta(t, 1.68, got[1].TotalCost, 0.0001)
+	assert.Nil(t, got[1].Tags)
+}
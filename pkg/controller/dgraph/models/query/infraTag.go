@@ -0,0 +1,210 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/vmware/purser/pkg/controller/dgraph/models"
+	qb "github.com/vmware/purser/pkg/querybuilder"
+)
+
+// Infra tag constants, costs of pods are grouped by value of an infra tag of their nodes
+const (
+	InfraTagType = "infraTag"
+	Key          = "key"
+	Untagged     = "untagged"
+)
+
+// InfraTagCost is the month to date cost of pods which ran on nodes with a value of the tag. Nodes is the number
+// of live nodes with it and Share is its fraction of the total cost.
+type InfraTagCost struct {
+	Value       string  `json:"value"`
+	Nodes       int     `json:"nodes"`
+	Pods        int     `json:"pods"`
+	CPUCost     float64 `json:"cpuCost"`
+	MemoryCost  float64 `json:"memoryCost"`
+	StorageCost float64 `json:"storageCost"`
+	TotalCost   float64 `json:"totalCost"`
+	Share       float64 `json:"share"`
+}
+
+// InfraTagCosts structure, Name is the namespace if costs are restricted to it, otherwise cluster. Keys are the
+// tag keys of live nodes.
+type InfraTagCosts struct {
+	Name      string         `json:"name"`
+	Key       string         `json:"key"`
+	Keys      []string       `json:"keys"`
+	TotalCost float64        `json:"totalCost"`
+	Values    []InfraTagCost `json:"values"`
+}
+
+// InfraTagCostsWrapper structure
+type InfraTagCostsWrapper struct {
+	Data InfraTagCosts `json:"data"`
+}
+
+type infraTagNode struct {
+	InfraTags string `json:"infraTags"`
+}
+
+type infraTagPod struct {
+	Name                 string        `json:"name"`
+	EndTime              string        `json:"endTime"`
+	CPUCost              float64       `json:"cpuCost"`
+	MemoryCost           float64       `json:"memoryCost"`
+	StorageCost          float64       `json:"storageCost"`
+	GPUCost              float64       `json:"gpuCost"`
+	ExtendedResourceCost float64       `json:"extendedResourceCost"`
+	BandwidthCost        float64       `json:"bandwidthCost"`
+	Node                 *infraTagNode `json:"node"`
+}
+
+// RetrieveInfraTagCosts returns month to date cost of pods grouped by value of the infra tag key of their nodes,
+// highest cost first, so that they can be reconciled with cost allocation tags of the cloud bill. Costs are
+// restricted to pods of the namespace if name is a namespace, otherwise values whose nodes ran no pods are
+// included too. Nodes without the tag are reported under untagged.
+func RetrieveInfraTagCosts(ctx context.Context, name, key string) InfraTagCostsWrapper {
+	if name != All && !strings.HasPrefix(name, NamespaceType+"-") {
+		log.Errorf("unable to retrieve infra tag costs, %s is not a namespace", name)
+		return InfraTagCostsWrapper{}
+	}
+	type root struct {
+		Nodes     []infraTagNode `json:"nodes"`
+		Pods      []infraTagPod  `json:"pods"`
+		Namespace []struct {
+			Pods []infraTagPod `json:"pods"`
+		} `json:"namespace"`
+	}
+	newRoot := root{}
+	query, vars := getQueryForInfraTagCosts(name)
+	err := executeQueryWithVars(ctx, query, vars, &newRoot)
+	if err != nil {
+		log.Errorf("unable to retrieve costs of infra tags, err: %v", err)
+		return InfraTagCostsWrapper{}
+	}
+	pods := newRoot.Pods
+	if name != All {
+		pods = nil
+		for _, namespace := range newRoot.Namespace {
+			pods = append(pods, namespace.Pods...)
+		}
+	}
+
+	data := InfraTagCosts{Name: name, Key: key, Keys: []string{}, Values: []InfraTagCost{}}
+	if data.Name == All {
+		data.Name = "cluster"
+	}
+	costs := make(map[string]*InfraTagCost)
+	getInfraTagCost := func(node infraTagNode) *InfraTagCost {
+		value, isTagged := models.ParseInfraTags(node.InfraTags)[key]
+		if !isTagged {
+			value = Untagged
+		}
+		if _, isPresent := costs[value]; !isPresent {
+			costs[value] = &InfraTagCost{Value: value}
+		}
+		return costs[value]
+	}
+	for _, node := range newRoot.Nodes {
+		getInfraTagCost(node).Nodes++
+		for tagKey := range models.ParseInfraTags(node.InfraTags) {
+			if !containsString(data.Keys, tagKey) {
+				data.Keys = append(data.Keys, tagKey)
+			}
+		}
+	}
+	sort.Strings(data.Keys)
+	for _, pod := range pods {
+		node := infraTagNode{}
+		if pod.Node != nil {
+			node = *pod.Node
+		}
+		addInfraTagPod(getInfraTagCost(node), pod)
+	}
+
+	for _, cost := range costs {
+		if cost.Pods == 0 && (cost.Nodes == 0 || name != All) {
+			continue
+		}
+		cost.CPUCost = adjustCost(CostContext{InfraTagType, cost.Value, CPUCostType}, cost.CPUCost)
+		cost.MemoryCost = adjustCost(CostContext{InfraTagType, cost.Value, MemoryCostType}, cost.MemoryCost)
+		cost.StorageCost = adjustCost(CostContext{InfraTagType, cost.Value, StorageCostType}, cost.StorageCost)
+		cost.TotalCost += cost.CPUCost + cost.MemoryCost + cost.StorageCost
+		data.TotalCost += cost.TotalCost
+		data.Values = append(data.Values, *cost)
+	}
+	for i := range data.Values {
+		if data.TotalCost > 0 {
+			data.Values[i].Share = data.Values[i].TotalCost / data.TotalCost
+		}
+	}
+	sort.Slice(data.Values, func(i, j int) bool {
+		if data.Values[i].TotalCost != data.Values[j].TotalCost {
+			return data.Values[i].TotalCost > data.Values[j].TotalCost
+		}
+		return data.Values[i].Value < data.Values[j].Value
+	})
+	return InfraTagCostsWrapper{Data: data}
+}
+
+// addInfraTagPod adds cost of the pod to the value of the tag of its node, TotalCost holds the adjusted costs of
+// GPUs, extended resources and bandwidth until cpu, memory and storage costs are adjusted. Pods terminated before
+// this month are not added.
+func addInfraTagPod(cost *InfraTagCost, pod infraTagPod) {
+	if pod.EndTime != "" && pod.CPUCost+pod.MemoryCost+pod.StorageCost+pod.GPUCost+pod.ExtendedResourceCost+pod.BandwidthCost == 0 {
+		return
+	}
+	cost.Pods++
+	cost.CPUCost += pod.CPUCost
+	cost.MemoryCost += pod.MemoryCost
+	cost.StorageCost += pod.StorageCost
+	cost.TotalCost += adjustCost(CostContext{PodType, pod.Name, GPUCostType}, pod.GPUCost) +
+		adjustCost(CostContext{PodType, pod.Name, ExtendedResourceCostType}, pod.ExtendedResourceCost) +
+		adjustCost(CostContext{PodType, pod.Name, BandwidthCostType}, pod.BandwidthCost)
+}
+
+func getQueryForInfraTagCosts(name string) (string, qb.Vars) {
+	vars := qb.Vars{}
+	pods := `pods(func: has(isPod)) {
+			` + getQueryForInfraTagPod() + `
+		}`
+	if name != All {
+		vars["$name"] = name
+		pods = `namespace(func: has(isNamespace)) @filter(eq(name, $name)) {
+			pods: ~namespace @filter(has(isPod)) {
+				` + getQueryForInfraTagPod() + `
+			}
+		}`
+	}
+	return vars.Declaration() + ` {
+		nodes(func: has(isNode)) @filter(NOT has(endTime)) {
+			infraTags
+		}
+		` + pods + `
+	}`, vars
+}
+
+func getQueryForInfraTagPod() string {
+	return getQueryForMetricsComputationWithAlias("Pod") + `
+			node {
+				infraTags
+			}`
+}
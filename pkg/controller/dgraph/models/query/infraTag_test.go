@@ -0,0 +1,100 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func mockDgraphForInfraTagCosts() {
+	executeQueryWithVars = func(ctx context.Context, query string, vars map[string]string, root interface{}) error {
+		nodes := `"nodes": [
+			{"infraTags": "{\"cost-center\":\"cc-42\",\"team\":\"platform\"}"},
+			{"infraTags": "{\"cost-center\":\"cc-42\"}"},
+			{"infraTags": "{\"cost-center\":\"cc-7\",\"terraform-workspace\":\"prod\"}"},
+			{}
+		]`
+		if strings.Contains(query, `namespace(func: has(isNamespace)) @filter(eq(name, $name))`) && vars["$name"] == "namespace-default" {
+			return json.Unmarshal([]byte(`{`+nodes+`, "namespace": [{"pods": [
+				{"name": "pod-web", "cpuCost": 1, "memoryCost": 1, "node": {"infraTags": "{\"cost-center\":\"cc-7\"}"}}
+			]}]}`), root)
+		}
+		return json.Unmarshal([]byte(`{`+nodes+`, "pods": [
+			{"name": "pod-web", "cpuCost": 1, "memoryCost": 1, "node": {"infraTags": "{\"cost-center\":\"cc-7\"}"}},
+			{"name": "pod-db", "cpuCost": 2, "memoryCost": 2, "storageCost": 1, "bandwidthCost": 1, "node": {"infraTags": "{\"cost-center\":\"cc-42\"}"}},
+			{"name": "pod-old", "endTime": "2018-09-01T00:00:00Z", "node": {"infraTags": "{\"cost-center\":\"cc-42\"}"}},
+			{"name": "pod-batch", "cpuCost": 0.5, "memoryCost": 0.5, "endTime": "2018-10-02T00:00:00Z", "node": {}},
+			{"name": "pod-pending"}
+		]}`), root)
+	}
+}
+
+// TestRetrieveInfraTagCosts ...
+func TestRetrieveInfraTagCosts(t *testing.T) {
+	mockDgraphForInfraTagCosts()
+	got := RetrieveInfraTagCosts(context.Background(), All, "cost-center").Data
+
+	assert.Equal(t, "cluster", got.Name)
+	assert.Equal(t, "cost-center", got.Key)
+	assert.Equal(t, []string{"cost-center", "team", "terraform-workspace"}, got.Keys)
+	assert.Equal(t, 9.0, got.TotalCost)
+	assert.Equal(t, 3, len(got.Values))
+	assert.Equal(t, InfraTagCost{
+		Value:       "cc-42",
+		Nodes:       2,
+		Pods:        1,
+		CPUCost:     2,
+		MemoryCost:  2,
+		StorageCost: 1,
+		TotalCost:   6,
+		Share:       6.0 / 9,
+	}, got.Values[0])
+	assert.Equal(t, "cc-7", got.Values[1].Value)
+	assert.Equal(t, Untagged, got.Values[2].Value)
+	assert.Equal(t, 1, got.Values[2].Nodes)
+	assert.Equal(t, 2, got.Values[2].Pods)
+}
+
+// TestRetrieveInfraTagCostsOfUnknownKey ...
+func TestRetrieveInfraTagCostsOfUnknownKey(t *testing.T) {
+	mockDgraphForInfraTagCosts()
+	got := RetrieveInfraTagCosts(context.Background(), All, "owner").Data
+
+	assert.Equal(t, 1, len(got.Values))
+	assert.Equal(t, Untagged, got.Values[0].Value)
+	assert.Equal(t, 4, got.Values[0].Nodes)
+	assert.Equal(t, 1.0, got.Values[0].Share)
+}
+
+// TestRetrieveInfraTagCostsOfNamespace ...
+func TestRetrieveInfraTagCostsOfNamespace(t *testing.T) {
+	mockDgraphForInfraTagCosts()
+	got := RetrieveInfraTagCosts(context.Background(), "namespace-default", "cost-center").Data
+
+	assert.Equal(t, "namespace-default", got.Name)
+	assert.Equal(t, 1, len(got.Values))
+	assert.Equal(t, "cc-7", got.Values[0].Value)
+	assert.Equal(t, 1.0, got.Values[0].Share)
+
+	assert.Equal(t, InfraTagCostsWrapper{}, RetrieveInfraTagCosts(context.Background(), "deployment-web", "cost-center"))
+}
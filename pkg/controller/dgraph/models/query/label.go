@@ -17,6 +17,11 @@
 
 package query
 
+import (
+	"github.com/vmware/purser/pkg/controller/dgraph/models"
+	qb "github.com/vmware/purser/pkg/querybuilder"
+)
+
 // CreateFilterFromListOfLabels will return a filter logic like
 // (eq(key, "k1") AND eq(value, "v1")) OR (eq(key, "k1") AND eq(value, "v1")) OR (eq(key, "k1") AND eq(value, "v1"))
 func CreateFilterFromListOfLabels(labels map[string][]string) string {
@@ -36,7 +41,9 @@ func CreateFilterFromListOfLabels(labels map[string][]string) string {
 	return filter
 }
 
-// createFilterFromLabel takes key: k1, value: v1 and returns (eq(key, "k1") AND eq(value, "v1"))
+// createFilterFromLabel takes key: k1, value: v1 and returns (eq(key, "k1") AND eq(value, "v1")), the value is
+// redacted like values of stored labels
 func createFilterFromLabel(key, value string) string {
-	return `(eq(key, "` + key + `") AND eq(value, "` + value + `"))`
+	value, _ = models.RedactLabelValue(key, value)
+	return "(" + qb.And(qb.Eq("key", key), qb.Eq("value", value)).String() + ")"
 }
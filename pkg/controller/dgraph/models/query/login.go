@@ -18,37 +18,38 @@
 package query
 
 import (
-	"github.com/Sirupsen/logrus"
+	"context"
 	"github.com/vmware/purser/pkg/controller/dgraph"
+	qb "github.com/vmware/purser/pkg/querybuilder"
 
 	"golang.org/x/crypto/bcrypt"
 )
 
 // Authenticate performs user authentication for service access
-func Authenticate(username, inputPassword string) bool {
+func Authenticate(ctx context.Context, username, inputPassword string) bool {
 	if !validateUsername(username) {
 		return false
 	}
-	login, err := getLoginCredentials(username)
+	login, err := getLoginCredentials(ctx, username)
 	if err != nil {
-		logrus.Error(err)
+		log.Error(err)
 		return false
 	}
 	return comparePasswords(login.Password, []byte(inputPassword))
 }
 
 // UpdatePassword updates stored password with new one for the given username in Dgraph
-func UpdatePassword(username, oldPassword, newPassword string) bool {
-	if Authenticate(username, oldPassword) {
-		login, err := getLoginCredentials(username)
+func UpdatePassword(ctx context.Context, username, oldPassword, newPassword string) bool {
+	if Authenticate(ctx, username, oldPassword) {
+		login, err := getLoginCredentials(ctx, username)
 		if err != nil {
-			logrus.Error(err)
+			log.Error(err)
 			return false
 		}
 		if err = hashAndUpdatePassword(&login, newPassword); err == nil {
 			return true
 		}
-		logrus.Error(err)
+		log.Error(err)
 	}
 	return false
 }
@@ -64,9 +65,10 @@ func hashAndUpdatePassword(login *dgraph.Login, newPassword string) error {
 }
 
 // getLoginCredentials returns a struct of hashed password and username.
-func getLoginCredentials(username string) (dgraph.Login, error) {
-	q := `query {
-		login(func: has(isLogin)) @filter(eq(username, ` + username + `)) {
+func getLoginCredentials(ctx context.Context, username string) (dgraph.Login, error) {
+	vars := qb.Vars{"$username": username}
+	q := vars.Declaration() + ` {
+		login(func: has(isLogin)) @filter(eq(username, $username)) {
 			uid
 			username
 			password
@@ -76,7 +78,7 @@ func getLoginCredentials(username string) (dgraph.Login, error) {
 		LoginList []dgraph.Login `json:"login"`
 	}
 	newRoot := root{}
-	if err := executeQuery(q, &newRoot); err != nil || newRoot.LoginList == nil {
+	if err := executeQueryWithVars(ctx, q, vars, &newRoot); err != nil || newRoot.LoginList == nil {
 		return dgraph.Login{}, err
 	}
 	return newRoot.LoginList[0], nil
@@ -89,7 +91,7 @@ func validateUsername(username string) bool {
 func comparePasswords(hashedPwd string, plainPwd []byte) bool {
 	byteHash := []byte(hashedPwd)
 	if err := bcrypt.CompareHashAndPassword(byteHash, plainPwd); err != nil {
-		logrus.Error(err)
+		log.Error(err)
 		return false
 	}
 	return true
@@ -0,0 +1,126 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+// Page selects a page of results ordered by uid, zero First returns all results. After is the uid of the last result
+// of the previous page(cursor) and Offset is the number of results skipped after it.
+type Page struct {
+	First  int
+	Offset int
+	After  string
+}
+
+// PageInfo describes a returned page, Next is the value of after for the next page and empty on the last page
+type PageInfo struct {
+	First  int    `json:"first,omitempty"`
+	Offset int    `json:"offset,omitempty"`
+	After  string `json:"after,omitempty"`
+	Next   string `json:"next,omitempty"`
+}
+
+// IsEmpty checks whether no page is selected
+func (p Page) IsEmpty() bool {
+	return p == Page{}
+}
+
+// getArguments returns pagination arguments of a dgraph block, ex: first: 100, after: 0x1a
+func (p Page) getArguments() string {
+	arguments := []string{}
+	if p.First > 0 {
+		arguments = append(arguments, "first: "+strconv.Itoa(p.First))
+	}
+	if p.Offset > 0 {
+		arguments = append(arguments, "offset: "+strconv.Itoa(p.Offset))
+	}
+	if p.After != "" {
+		arguments = append(arguments, "after: "+p.After)
+	}
+	return strings.Join(arguments, ", ")
+}
+
+// getRootArguments returns pagination arguments to be appended to the func of a root block, ex: , first: 100
+func (p Page) getRootArguments() string {
+	if p.IsEmpty() {
+		return ""
+	}
+	return ", " + p.getArguments()
+}
+
+// getEdgeArguments returns pagination arguments to be appended to the predicate of an edge block, ex:  (first: 100)
+func (p Page) getEdgeArguments() string {
+	if p.IsEmpty() {
+		return ""
+	}
+	return " (" + p.getArguments() + ")"
+}
+
+// getUIDField returns uid if results are paged so that the next page can be asked after the last of them
+func (p Page) getUIDField() string {
+	if p.IsEmpty() {
+		return ""
+	}
+	return "uid"
+}
+
+// getPageInfo returns the page info of a page of count results whose last one has lastUID, nil if no page is
+// selected. A full page may be followed by more results and has next.
+func (p Page) getPageInfo(count int, lastUID string) *PageInfo {
+	if p.IsEmpty() {
+		return nil
+	}
+	info := &PageInfo{First: p.First, Offset: p.Offset, After: p.After}
+	if p.First > 0 && count == p.First {
+		info.Next = lastUID
+	}
+	return info
+}
+
+// pagedPods wraps a page of pods in raw json together with its page info
+type pagedPods struct {
+	Pods []json.RawMessage `json:"pods"`
+	Page *PageInfo         `json:"page,omitempty"`
+}
+
+// addPageInfo adds page info to a raw json result of pods, the result is returned as is if no page is selected
+func addPageInfo(result []byte, page Page) ([]byte, error) {
+	if page.IsEmpty() {
+		return result, nil
+	}
+	paged := pagedPods{}
+	if err := json.Unmarshal(result, &paged); err != nil {
+		return nil, err
+	}
+	lastUID := ""
+	if len(paged.Pods) > 0 {
+		last := struct {
+			UID string `json:"uid"`
+		}{}
+		if err := json.Unmarshal(paged.Pods[len(paged.Pods)-1], &last); err != nil {
+			return nil, err
+		}
+		lastUID = last.UID
+	}
+	paged.Page = page.getPageInfo(len(paged.Pods), lastUID)
+	return json.Marshal(paged)
+}
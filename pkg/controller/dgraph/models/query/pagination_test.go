@@ -0,0 +1,101 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestPageArguments ...
+func TestPageArguments(t *testing.T) {
+	assert.Equal(t, "", Page{}.getRootArguments())
+	assert.Equal(t, "", Page{}.getEdgeArguments())
+	assert.Equal(t, "", Page{}.getUIDField())
+
+	page := Page{First: 100, Offset: 10, After: "0x1a"}
+	assert.Equal(t, ", first: 100, offset: 10, after: 0x1a", page.getRootArguments())
+	assert.Equal(t, " (first: 100, offset: 10, after: 0x1a)", page.getEdgeArguments())
+	assert.Equal(t, "uid", page.getUIDField())
+}
+
+// TestGetPageInfo ...
+func TestGetPageInfo(t *testing.T) {
+	assert.Nil(t, Page{}.getPageInfo(2, "0x2"))
+
+	page := Page{First: 2, After: "0x1"}
+	assert.Equal(t, &PageInfo{First: 2, After: "0x1", Next: "0x3"}, page.getPageInfo(2, "0x3"))
+	assert.Equal(t, &PageInfo{First: 2, After: "0x1"}, page.getPageInfo(1, "0x3"))
+}
+
+// TestAddPageInfo ...
+func TestAddPageInfo(t *testing.T) {
+	result := []byte(`{"pods":[{"uid":"0x1","name":"pod-a"},{"uid":"0x2","name":"pod-b"}]}`)
+	got, err := addPageInfo(result, Page{})
+	assert.NoError(t, err)
+	assert.Equal(t, result, got)
+
+	got, err = addPageInfo(result, Page{First: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, `{"pods":[{"uid":"0x1","name":"pod-a"},{"uid":"0x2","name":"pod-b"}],"page":{"first":2,"next":"0x2"}}`, string(got))
+
+	got, err = addPageInfo([]byte(`{"pods":[]}`), Page{First: 2, After: "0x2"})
+	assert.NoError(t, err)
+	assert.Equal(t, `{"pods":[],"page":{"first":2,"after":"0x2"}}`, string(got))
+
+	_, err = addPageInfo([]byte(`{"pods":`), Page{First: 2})
+	assert.Error(t, err)
+}
+
+// TestRetrievePodsInteractionsPage ...
+func TestRetrievePodsInteractionsPage(t *testing.T) {
+	executeQueryRawWithVars = func(ctx context.Context, query string, vars map[string]string) ([]byte, error) {
+		if !strings.Contains(query, "pods(func: has(isPod), first: 1, after: 0x1)") || !strings.Contains(query, "uid") {
+			return []byte(`{"pods":[]}`), nil
+		}
+		return []byte(`{"pods":[{"uid":"0x2","name":"pod-b"}]}`), nil
+	}
+	got := RetrievePodsInteractionsRaw(context.Background(), All, All, true, Page{First: 1, After: "0x1"})
+	assert.Equal(t, `{"pods":[{"uid":"0x2","name":"pod-b"}],"page":{"first":1,"after":"0x1","next":"0x2"}}`, string(got))
+
+	executeQueryWithVars = func(ctx context.Context, query string, vars map[string]string, root interface{}) error {
+		result, err := executeQueryRawWithVars(ctx, query, vars)
+		if err != nil {
+			return err
+		}
+		return json.Unmarshal(result, root)
+	}
+	interactions, err := RetrievePodsInteractionsPage(context.Background(), All, true, Page{First: 1, After: "0x1"})
+	assert.NoError(t, err)
+	assert.Equal(t, PodInteractions{
+		Pods: []PodInteraction{{UID: "0x2", Name: "pod-b"}},
+		Page: &PageInfo{First: 1, After: "0x1", Next: "0x2"},
+	}, interactions)
+}
+
+// TestGetQueryForHierarchyWithPage ...
+func TestGetQueryForHierarchyWithPage(t *testing.T) {
+	r := Resource{Check: NodeCheck, Type: NodeType, Name: "node-1", ChildFilter: IsPodFilter, Page: Page{First: 50, After: "0x1a"}}
+	query, _ := r.getQueryForHierarchy()
+	assert.Contains(t, query, "children: ~node (first: 50, after: 0x1a) @filter(has(isPod)) {")
+	assert.Contains(t, query, "uid")
+}
@@ -0,0 +1,319 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/vmware/purser/pkg/controller/dgraph/models"
+	qb "github.com/vmware/purser/pkg/querybuilder"
+)
+
+// MinDuration is the query parameter of the minimum time a pod was unschedulable to be reported
+const MinDuration = "minDuration"
+
+// DefaultMinUnscheduledDuration is the minimum unscheduled time of reported pods if none is given
+const DefaultMinUnscheduledDuration = 5 * time.Minute
+
+// UnschedulablePod is a pod which waited for a node, Unscheduled is true if it is still waiting. DelayCost is the
+// cost of its requests over the time it waited, the value of capacity it was denied.
+type UnschedulablePod struct {
+	Name             string  `json:"name"`
+	Workload         string  `json:"workload"`
+	Type             string  `json:"type"`
+	Namespace        string  `json:"namespace"`
+	StartTime        string  `json:"startTime"`
+	ScheduledTime    string  `json:"scheduledTime,omitempty"`
+	Unscheduled      bool    `json:"unscheduled"`
+	UnscheduledHours float64 `json:"unscheduledHours"`
+	CPURequest       float64 `json:"cpuRequest"`
+	MemoryRequest    float64 `json:"memoryRequest"`
+	GPURequest       float64 `json:"gpuRequest"`
+	HourlyCost       float64 `json:"hourlyCost"`
+	DelayCost        float64 `json:"delayCost"`
+}
+
+// CapacityEstimate is the node capacity needed to schedule pods which are still unscheduled. Pods are packed by
+// their cpu and memory requests on nodes of the instance type of the cluster cheapest per CPU that fits the largest
+// of them, pods larger than every instance type are unplaceable.
+type CapacityEstimate struct {
+	InstanceType    string  `json:"instanceType,omitempty"`
+	NodeCPU         float64 `json:"nodeCPU"`
+	NodeMemory      float64 `json:"nodeMemory"`
+	Nodes           int     `json:"nodes"`
+	CPU             float64 `json:"cpu"`
+	Memory          float64 `json:"memory"`
+	GPU             float64 `json:"gpu"`
+	HourlyCost      float64 `json:"hourlyCost"`
+	MonthlyCost     float64 `json:"monthlyCost"`
+	UnplaceablePods int     `json:"unplaceablePods"`
+}
+
+// PendingPods are pods unschedulable for at least MinDuration between Start and End, sorted by delay cost
+type PendingPods struct {
+	Start            string             `json:"start"`
+	End              string             `json:"end"`
+	MinDuration      string             `json:"minDuration"`
+	UnscheduledPods  int                `json:"unscheduledPods"`
+	UnscheduledHours float64            `json:"unscheduledHours"`
+	DelayCost        float64            `json:"delayCost"`
+	RequiredCapacity CapacityEstimate   `json:"requiredCapacity"`
+	Pods             []UnschedulablePod `json:"pods"`
+}
+
+// PendingPodsWrapper structure
+type PendingPodsWrapper struct {
+	Data PendingPods `json:"data"`
+}
+
+type pendingPod struct {
+	triggeringPod
+	Phase         string  `json:"phase"`
+	StartTime     string  `json:"startTime"`
+	ScheduledTime string  `json:"scheduledTime"`
+	EndTime       string  `json:"endTime"`
+	CPURequest    float64 `json:"cpuRequest"`
+	MemoryRequest float64 `json:"memoryRequest"`
+	GPURequest    float64 `json:"gpuRequest"`
+	CPUPrice      float64 `json:"cpuPrice"`
+	MemoryPrice   float64 `json:"memoryPrice"`
+	GPUPrice      float64 `json:"gpuPrice"`
+}
+
+type pendingNode struct {
+	affinityNode
+	InstanceType string `json:"instanceType"`
+}
+
+// RetrievePendingPods returns pods which were unschedulable for at least minDuration(default 5m) between start and
+// end(RFC3339, last 30 days by default) with the cost of their delay, and the node capacity pods which are still
+// unscheduled need.
+func RetrievePendingPods(ctx context.Context, start, end, minDuration string) PendingPodsWrapper {
+	endTime := time.Now().UTC()
+	if end != "" {
+		parsed, err := time.Parse(time.RFC3339, end)
+		if err != nil {
+			log.Errorf("invalid end time: %s, err: %v", end, err)
+			return PendingPodsWrapper{}
+		}
+		endTime = parsed
+	}
+	startTime := endTime.AddDate(0, 0, -defaultChurnDays)
+	if start != "" {
+		parsed, err := time.Parse(time.RFC3339, start)
+		if err != nil {
+			log.Errorf("invalid start time: %s, err: %v", start, err)
+			return PendingPodsWrapper{}
+		}
+		startTime = parsed
+	}
+	minUnscheduled := DefaultMinUnscheduledDuration
+	if minDuration != "" {
+		parsed, err := time.ParseDuration(minDuration)
+		if err != nil {
+			log.Errorf("invalid min duration: %s, err: %v", minDuration, err)
+			return PendingPodsWrapper{}
+		}
+		minUnscheduled = parsed
+	}
+
+	root := struct {
+		Pods  []pendingPod  `json:"pods"`
+		Nodes []pendingNode `json:"nodes"`
+	}{}
+	query, vars := getQueryForPendingPods(startTime, endTime)
+	err := executeQueryWithVars(ctx, query, vars, &root)
+	if err != nil {
+		log.Errorf("unable to retrieve pending pods, err: %v", err)
+		return PendingPodsWrapper{}
+	}
+	return PendingPodsWrapper{Data: computePendingPods(root.Pods, root.Nodes, startTime, endTime, minUnscheduled)}
+}
+
+// getQueryForPendingPods returns pods which may have waited for a node in the time range and live nodes. Scheduled
+// time isn't indexed, so unscheduled intervals are checked against the range after retrieval.
+func getQueryForPendingPods(startTime, endTime time.Time) (string, qb.Vars) {
+	vars := qb.Vars{"$start": startTime.Format(time.RFC3339), "$end": endTime.Format(time.RFC3339)}
+	owners := ``
+	for _, ownerType := range podOwnerPredicates {
+		owners += `
+			` + ownerType + ` {
+				name
+			}`
+	}
+	return vars.Declaration() + ` {
+		pods(func: has(isPod)) @filter(le(startTime, $end) AND (NOT has(endTime) OR ge(endTime, $start)) AND (has(scheduledTime) OR eq(phase, "` + PendingPhase + `"))) {
+			name
+			phase
+			startTime
+			scheduledTime
+			endTime
+			cpuRequest
+			memoryRequest
+			gpuRequest
+			cpuPrice
+			memoryPrice
+			gpuPrice
+			namespace {
+				name
+			}` + owners + `
+		}
+		nodes(func: has(isNode)) @filter((NOT has(endTime)) AND (NOT has(isVirtual)) AND gt(cpuCapacity, 0)) {
+			name
+			instanceType
+			cpuCapacity
+			memoryCapacity
+			cpuPrice
+			memoryPrice
+		}
+	}`, vars
+}
+
+// computePendingPods returns pods whose wait for a node ended in the time range and lasted at least minUnscheduled.
+// Pods never scheduled waited until they were deleted or until end if they are still pending.
+func computePendingPods(pods []pendingPod, nodes []pendingNode, startTime, endTime time.Time, minUnscheduled time.Duration) PendingPods {
+	data := PendingPods{
+		Start:       startTime.Format(time.RFC3339),
+		End:         endTime.Format(time.RFC3339),
+		MinDuration: minUnscheduled.String(),
+		Pods:        []UnschedulablePod{},
+	}
+	var unscheduled []packingPod
+	for _, pod := range pods {
+		podStart := parseTime(pod.StartTime)
+		if podStart.IsZero() {
+			continue
+		}
+		waitEnd, isWaiting := parseTime(pod.ScheduledTime), false
+		if waitEnd.IsZero() {
+			if pod.Phase != PendingPhase {
+				continue
+			}
+			waitEnd, isWaiting = parseTime(pod.EndTime), pod.EndTime == ""
+		}
+		if waitEnd.IsZero() || waitEnd.After(endTime) {
+			waitEnd = endTime
+		}
+		if waitEnd.Before(startTime) || waitEnd.Sub(podStart) < minUnscheduled {
+			continue
+		}
+		name, workloadType, namespace := getPodOwner(pod.triggeringPod)
+		hours := waitEnd.Sub(podStart).Hours()
+		hourlyCost := getHourlyCost(timelinePod{
+			Name:          pod.Name,
+			CPURequest:    pod.CPURequest,
+			MemoryRequest: pod.MemoryRequest,
+			GPURequest:    pod.GPURequest,
+			CPUPrice:      pod.CPUPrice,
+			MemoryPrice:   pod.MemoryPrice,
+			GPUPrice:      pod.GPUPrice,
+		})
+		result := UnschedulablePod{
+			Name:             pod.Name,
+			Workload:         name,
+			Type:             workloadType,
+			Namespace:        namespace,
+			StartTime:        pod.StartTime,
+			ScheduledTime:    pod.ScheduledTime,
+			Unscheduled:      isWaiting,
+			UnscheduledHours: hours,
+			CPURequest:       pod.CPURequest,
+			MemoryRequest:    pod.MemoryRequest,
+			GPURequest:       pod.GPURequest,
+			HourlyCost:       hourlyCost,
+			DelayCost:        hourlyCost * hours,
+		}
+		data.Pods = append(data.Pods, result)
+		data.UnscheduledHours += hours
+		data.DelayCost += result.DelayCost
+		if isWaiting {
+			data.UnscheduledPods++
+			data.RequiredCapacity.CPU += pod.CPURequest
+			data.RequiredCapacity.Memory += pod.MemoryRequest
+			data.RequiredCapacity.GPU += pod.GPURequest
+			unscheduled = append(unscheduled, packingPod{name: pod.Name, cpu: pod.CPURequest, memory: pod.MemoryRequest})
+		}
+	}
+	sort.SliceStable(data.Pods, func(i, j int) bool {
+		if data.Pods[i].DelayCost != data.Pods[j].DelayCost {
+			return data.Pods[i].DelayCost > data.Pods[j].DelayCost
+		}
+		return data.Pods[i].Name < data.Pods[j].Name
+	})
+	estimateRequiredCapacity(&data.RequiredCapacity, unscheduled, nodes)
+	return data
+}
+
+// estimateRequiredCapacity packs the pods first fit decreasing on new nodes of the chosen instance type
+func estimateRequiredCapacity(estimate *CapacityEstimate, pods []packingPod, nodes []pendingNode) {
+	if len(pods) == 0 {
+		return
+	}
+	sort.SliceStable(pods, func(i, j int) bool {
+		if pods[i].cpu != pods[j].cpu {
+			return pods[i].cpu > pods[j].cpu
+		}
+		if pods[i].memory != pods[j].memory {
+			return pods[i].memory > pods[j].memory
+		}
+		return pods[i].name < pods[j].name
+	})
+	template, isFound := getNodeTemplate(pods, nodes)
+	if !isFound {
+		estimate.UnplaceablePods = len(pods)
+		return
+	}
+	candidates := make([]affinityNode, len(pods))
+	for index := range candidates {
+		candidates[index] = template.affinityNode
+	}
+	packed := packPods(pods, candidates, nil, nil)
+	estimate.InstanceType = template.InstanceType
+	estimate.NodeCPU = template.CPUCapacity
+	estimate.NodeMemory = template.MemoryCapacity
+	estimate.Nodes = len(packed.nodes)
+	estimate.UnplaceablePods = packed.unplaced
+	estimate.HourlyCost = packed.hourlyCost()
+	estimate.MonthlyCost = estimate.HourlyCost * models.HoursInMonth
+}
+
+// getNodeTemplate returns a node of the instance type cheapest per CPU which fits the largest pod that fits on any
+// instance type of the cluster, pods are sorted largest first
+func getNodeTemplate(pods []packingPod, nodes []pendingNode) (pendingNode, bool) {
+	templates := []affinityNode{}
+	nodeOfTemplate := make(map[string]pendingNode)
+	isSeen := make(map[string]bool)
+	for _, node := range nodes {
+		if !isSeen[node.InstanceType] {
+			isSeen[node.InstanceType] = true
+			nodeOfTemplate[node.Name] = node
+			templates = append(templates, node.affinityNode)
+		}
+	}
+	sortNodesForPacking(templates)
+	for _, pod := range pods {
+		for _, template := range templates {
+			if pod.cpu <= template.CPUCapacity && pod.memory <= template.MemoryCapacity {
+				return nodeOfTemplate[template.Name], true
+			}
+		}
+	}
+	return pendingNode{}, false
+}
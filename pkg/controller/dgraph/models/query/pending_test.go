@@ -0,0 +1,117 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	qb "github.com/vmware/purser/pkg/querybuilder"
+)
+
+func mockDgraphForPendingPods() {
+	executeQueryWithVars = func(ctx context.Context, query string, vars map[string]string, root interface{}) error {
+		return json.Unmarshal([]byte(`{
+			"pods": [
+				{"name": "pod-web-1", "phase": "Running", "startTime": "2018-10-05T00:00:00Z", "scheduledTime": "2018-10-05T02:00:00Z",
+					"cpuRequest": 1, "cpuPrice": 0.05, "namespace": {"name": "namespace-shop"}, "deployment": {"name": "deployment-web"}},
+				{"name": "pod-batch-1", "phase": "Pending", "startTime": "2018-10-10T00:00:00Z", "cpuRequest": 3, "memoryRequest": 4,
+					"cpuPrice": 0.05, "namespace": {"name": "namespace-batch"}, "job": {"name": "job-report"}},
+				{"name": "pod-batch-2", "phase": "Pending", "startTime": "2018-10-10T06:00:00Z", "cpuRequest": 2, "memoryRequest": 2,
+					"cpuPrice": 0.05, "namespace": {"name": "namespace-batch"}, "job": {"name": "job-report"}},
+				{"name": "pod-huge", "phase": "Pending", "startTime": "2018-10-10T11:00:00Z", "cpuRequest": 16, "cpuPrice": 0.05,
+					"namespace": {"name": "namespace-batch"}},
+				{"name": "pod-quick", "phase": "Running", "startTime": "2018-10-05T00:00:00Z", "scheduledTime": "2018-10-05T00:01:00Z",
+					"cpuRequest": 1, "cpuPrice": 0.05, "namespace": {"name": "namespace-shop"}},
+				{"name": "pod-old", "phase": "Running", "startTime": "2018-09-20T00:00:00Z", "scheduledTime": "2018-09-20T05:00:00Z",
+					"cpuRequest": 1, "cpuPrice": 0.05, "namespace": {"name": "namespace-shop"}},
+				{"name": "pod-deleted", "phase": "Pending", "startTime": "2018-10-09T00:00:00Z", "endTime": "2018-10-09T01:00:00Z",
+					"cpuRequest": 1, "cpuPrice": 0.05, "namespace": {"name": "namespace-shop"}}
+			],
+			"nodes": [
+				{"name": "node-a", "instanceType": "m5.large", "cpuCapacity": 2, "memoryCapacity": 8, "cpuPrice": 0.05, "memoryPrice": 0},
+				{"name": "node-b", "instanceType": "m5.xlarge", "cpuCapacity": 4, "memoryCapacity": 16, "cpuPrice": 0.05, "memoryPrice": 0},
+				{"name": "node-c", "instanceType": "m5.xlarge", "cpuCapacity": 4, "memoryCapacity": 16, "cpuPrice": 0.05, "memoryPrice": 0}
+			]
+		}`), root)
+	}
+}
+
+// TestRetrievePendingPods ...
+func TestRetrievePendingPods(t *testing.T) {
+	mockDgraphForPendingPods()
+	got := RetrievePendingPods(context.Background(), "2018-10-01T00:00:00Z", "2018-10-10T12:00:00Z", "").Data
+	assert.Equal(t, "5m0s", got.MinDuration)
+	assert.Equal(t, 3, got.UnscheduledPods)
+	assert.InDelta(t, 22, got.UnscheduledHours, 0.0001)
+	assert.InDelta(t, 3.35, got.DelayCost, 0.0001)
+
+	names := []string{}
+	for _, pod := range got.Pods {
+		names = append(names, pod.Name)
+	}
+	assert.Equal(t, []string{"pod-batch-1", "pod-huge", "pod-batch-2", "pod-web-1", "pod-deleted"}, names)
+	assert.Equal(t, "job-report", got.Pods[0].Workload)
+	assert.Equal(t, JobType, got.Pods[0].Type)
+	assert.True(t, got.Pods[0].Unscheduled)
+	assert.InDelta(t, 12, got.Pods[0].UnscheduledHours, 0.0001)
+	assert.InDelta(t, 1.8, got.Pods[0].DelayCost, 0.0001)
+	assert.False(t, got.Pods[3].Unscheduled)
+	assert.InDelta(t, 2, got.Pods[3].UnscheduledHours, 0.0001)
+	assert.False(t, got.Pods[4].Unscheduled)
+
+	capacity := got.RequiredCapacity
+	assert.Equal(t, "m5.xlarge", capacity.InstanceType)
+	assert.Equal(t, 2, capacity.Nodes)
+	assert.Equal(t, 1, capacity.UnplaceablePods)
+	assert.InDelta(t, 21, capacity.CPU, 0.0001)
+	assert.InDelta(t, 6, capacity.Memory, 0.0001)
+	assert.InDelta(t, 0.4, capacity.HourlyCost, 0.0001)
+}
+
+// TestRetrievePendingPodsWithMinDuration ...
+func TestRetrievePendingPodsWithMinDuration(t *testing.T) {
+	mockDgraphForPendingPods()
+	got := RetrievePendingPods(context.Background(), "2018-10-01T00:00:00Z", "2018-10-10T12:00:00Z", "6h").Data
+	assert.Len(t, got.Pods, 2)
+	assert.Equal(t, 2, got.UnscheduledPods)
+
+	assert.Equal(t, PendingPods{}, RetrievePendingPods(context.Background(), "", "", "soon").Data)
+}
+
+// TestGetQueryForPendingPods ...
+func TestGetQueryForPendingPods(t *testing.T) {
+	end := time.Date(2018, 10, 10, 0, 0, 0, 0, time.UTC)
+	query, vars := getQueryForPendingPods(end.AddDate(0, 0, -1), end)
+	assert.True(t, strings.Contains(query, `le(startTime, $end)`))
+	assert.True(t, strings.Contains(query, `ge(endTime, $start)`))
+	assert.Equal(t, qb.Vars{"$start": "2018-10-09T00:00:00Z", "$end": "2018-10-10T00:00:00Z"}, vars)
+	assert.True(t, strings.Contains(query, `eq(phase, "Pending")`))
+}
+
+// TestEstimateRequiredCapacityWithoutNodes ...
+func TestEstimateRequiredCapacityWithoutNodes(t *testing.T) {
+	estimate := CapacityEstimate{}
+	estimateRequiredCapacity(&estimate, []packingPod{{name: "pod-1", cpu: 1}}, nil)
+	assert.Equal(t, 1, estimate.UnplaceablePods)
+	assert.Equal(t, 0, estimate.Nodes)
+}
@@ -18,8 +18,12 @@
 package query
 
 import (
-	"github.com/Sirupsen/logrus"
+	"context"
+	"fmt"
+	"strings"
+
 	"github.com/vmware/purser/pkg/controller/dgraph/models"
+	qb "github.com/vmware/purser/pkg/querybuilder"
 )
 
 type podRoot struct {
@@ -28,95 +32,234 @@ type podRoot struct {
 
 // RetrieveAllLivePods will return all pods without endTime in dgraph. Error is returned if any
 // failure is encountered in the process.
-func RetrieveAllLivePods() []models.Pod {
-	query := getAllLivePodsQuery()
+func RetrieveAllLivePods(ctx context.Context) []models.Pod {
+	return RetrieveLivePodsInNamespace(ctx, All)
+}
+
+// RetrieveLivePodsInNamespace returns pods without endTime of the namespace(ex: namespace-default), pods of all
+// namespaces if it is empty
+func RetrieveLivePodsInNamespace(ctx context.Context, namespace string) []models.Pod {
+	query, vars := getLivePodsQuery(namespace)
 	newRoot := podRoot{}
-	err := executeQuery(query, &newRoot)
+	err := executeQueryWithVars(ctx, query, vars, &newRoot)
 	if err != nil {
-		logrus.Errorf("unable to retrieve all live pods: %v", err)
+		log.Errorf("unable to retrieve all live pods: %v", err)
 		return nil
 	}
 	return newRoot.Pods
 }
 
-// RetrievePodsInteractions returns inbound and outbound interactions of a pod
-func RetrievePodsInteractions(name string, isOrphan bool) []byte {
-	var query string
+// PodInteraction is a pod with the pods it sends traffic to(Outbound) and receives traffic from(Inbound)
+type PodInteraction struct {
+	UID      string        `json:"uid,omitempty"`
+	Name     string        `json:"name"`
+	Outbound []Interaction `json:"outbound,omitempty"`
+	Inbound  []Interaction `json:"inbound,omitempty"`
+}
+
+// Interaction is a pod interacting with another pod, Count is the number of connections between them
+type Interaction struct {
+	Name  string  `json:"name"`
+	Count float64 `json:"pod|count,omitempty"`
+}
+
+// PodInteractions is a list of pods with their interactions, Page is set if the pods are paged
+type PodInteractions struct {
+	Pods []PodInteraction `json:"pods"`
+	Page *PageInfo        `json:"page,omitempty"`
+}
+
+// RetrievePodsInteractions returns inbound and outbound interactions of a pod, of all pods if name is empty
+func RetrievePodsInteractions(ctx context.Context, name string, isOrphan bool) ([]PodInteraction, error) {
+	interactions, err := RetrievePodsInteractionsPage(ctx, name, isOrphan, Page{})
+	return interactions.Pods, err
+}
+
+// RetrievePodsInteractionsPage returns interactions like RetrievePodsInteractions, interactions of all pods are
+// paged with the page if it is given. Paged pods have their uid and the page info is set in the result.
+func RetrievePodsInteractionsPage(ctx context.Context, name string, isOrphan bool, page Page) (PodInteractions, error) {
+	return RetrievePodsInteractionsInNamespace(ctx, name, All, isOrphan, page)
+}
+
+// RetrievePodsInteractionsInNamespace returns interactions like RetrievePodsInteractionsPage of pods of the
+// namespace(ex: namespace-default) with other pods of the namespace, all namespaces if it is empty
+func RetrievePodsInteractionsInNamespace(ctx context.Context, name, namespace string, isOrphan bool, page Page) (PodInteractions, error) {
+	query, vars := getQueryForPodsInteractions(name, namespace, isOrphan, page)
+	interactions := PodInteractions{}
+	err := executeQueryWithVars(ctx, query, vars, &interactions)
+	if err != nil {
+		return PodInteractions{}, fmt.Errorf("unable to retrieve pods interactions, name: %v, namespace: %v, isOrphan: %v, err: %v", name, namespace, isOrphan, err)
+	}
 	if name == All {
-		if isOrphan {
-			query = `query {
-				pods(func: has(isPod)) {
-					name
-					outbound: pod {
-						name
-					}
-					inbound: ~pod @filter(has(isPod)) {
-						name
-					}
-				}
-			}`
-		} else {
-			query = `query {
-				pods(func: has(isPod)) @filter(has(pod)) {
-					name
-					outbound: pod {
-						name
-					}
-					inbound: ~pod @filter(has(isPod)) {
-						name
-					}
-				}
-			}`
+		lastUID := ""
+		if len(interactions.Pods) > 0 {
+			lastUID = interactions.Pods[len(interactions.Pods)-1].UID
 		}
-	} else {
-		query = `query {
-			pods(func: has(isPod)) @filter(eq(name, "` + name + `")) {
-				name
-				outbound: pod {
+		interactions.Page = page.getPageInfo(len(interactions.Pods), lastUID)
+	}
+	return interactions, nil
+}
+
+// RetrievePodsInteractionsRaw returns interactions like RetrievePodsInteractionsInNamespace as the raw json result
+// with page info added, nil in case of errors. It is kept for the /interactions/pod endpoint.
+func RetrievePodsInteractionsRaw(ctx context.Context, name, namespace string, isOrphan bool, page Page) []byte {
+	query, vars := getQueryForPodsInteractions(name, namespace, isOrphan, page)
+	result, err := executeQueryRawWithVars(ctx, query, vars)
+	if err != nil {
+		log.Errorf("Error while retrieving query for pods interactions. Name: (%v), namespace: (%v), isOrphan: (%v), error: (%v)", name, namespace, isOrphan, err)
+		return nil
+	}
+	if name != All {
+		return result
+	}
+	result, err = addPageInfo(result, page)
+	if err != nil {
+		log.Errorf("unable to add page info to pods interactions, err: %v", err)
+		return nil
+	}
+	return result
+}
+
+func getQueryForPodsInteractions(name, namespace string, isOrphan bool, page Page) (string, qb.Vars) {
+	interactions := `name
+				outbound: pod @facets` + getInteractionsFilter(namespace, "") + ` {
 					name
 				}
-				inbound: ~pod @filter(has(isPod)) {
+				inbound: ~pod @facets` + getInteractionsFilter(namespace, "has(isPod)") + ` {
 					name
-				}
+				}`
+	if name != All {
+		vars := getNamespaceVars(qb.Vars{"$name": name}, namespace)
+		return vars.Declaration() + ` {
+			` + getNamespaceVar(namespace) + `
+			pods(func: has(isPod)) @filter(eq(name, $name)` + getNamespaceFilter(namespace) + `) {
+				` + interactions + `
 			}
-		}`
+		}`, vars
 	}
 
-	result, err := executeQueryRaw(query)
-	if err != nil {
-		logrus.Errorf("Error while retrieving query for pods interactions. Name: (%v), isOrphan: (%v), error: (%v)", name, isOrphan, err)
-		return nil
+	filter := ""
+	if !isOrphan {
+		filter = "has(pod)"
 	}
-	return result
+	if namespace != "" {
+		filter = strings.TrimPrefix(filter+getNamespaceFilter(namespace), " AND ")
+	}
+	if filter != "" {
+		filter = " @filter(" + filter + ")"
+	}
+	vars := getNamespaceVars(nil, namespace)
+	return vars.Declaration() + ` {
+			` + getNamespaceVar(namespace) + `
+			pods(func: has(isPod)` + page.getRootArguments() + `)` + filter + ` {
+				` + page.getUIDField() + `
+				` + interactions + `
+			}
+		}`, vars
 }
 
-func getPricePerResourceForPod(name string) (float64, float64) {
-	query := `query {
-		pods(func: has(isPod)) @filter(eq(name, "` + name + `")) {
+// getInteractionsFilter returns the filter of interactions with the condition, interactions are restricted to
+// pods of the namespace if it is given
+func getInteractionsFilter(namespace, condition string) string {
+	if namespace != "" {
+		condition = strings.TrimPrefix(condition+getNamespaceFilter(namespace), " AND ")
+	}
+	if condition == "" {
+		return ""
+	}
+	return " @filter(" + condition + ")"
+}
+
+func getPricePerResourceForPod(ctx context.Context, name, namespace string) (float64, float64) {
+	vars := getNamespaceVars(qb.Vars{"$name": name}, namespace)
+	query := vars.Declaration() + ` {
+		` + getNamespaceVar(namespace) + `
+		pods(func: has(isPod)) @filter(eq(name, $name)` + getNamespaceFilter(namespace) + `) {
 			cpuPrice
 			memoryPrice
 		}
 	}`
 	newRoot := podRoot{}
-	err := executeQuery(query, &newRoot)
+	err := executeQueryWithVars(ctx, query, vars, &newRoot)
 	if err != nil || len(newRoot.Pods) < 1 {
-		logrus.Errorf("err: %v", err)
+		log.Errorf("err: %v", err)
 		return models.DefaultCPUCostInFloat64, models.DefaultMemCostInFloat64
 	}
 	pod := newRoot.Pods[0]
 	return pod.CPUPrice, pod.MemoryPrice
 }
 
+// getPricePerLocalResourceForPod returns price per GB of ephemeral storage and hugepages of the pod.
+// Pods which are not priced yet get default local disk price and the given memory price for hugepages.
+func getPricePerLocalResourceForPod(ctx context.Context, name, namespace string, memoryPrice float64) (float64, float64) {
+	vars := getNamespaceVars(qb.Vars{"$name": name}, namespace)
+	query := vars.Declaration() + ` {
+		` + getNamespaceVar(namespace) + `
+		pods(func: has(isPod)) @filter(eq(name, $name)` + getNamespaceFilter(namespace) + `) {
+			ephemeralStoragePrice
+			hugepagesPrice
+		}
+	}`
+	ephemeralStoragePrice, hugepagesPrice := models.DefaultLocalDiskCostInFloat64, memoryPrice
+	newRoot := podRoot{}
+	err := executeQueryWithVars(ctx, query, vars, &newRoot)
+	if err != nil || len(newRoot.Pods) < 1 {
+		log.Debugf("local resource prices of pod: %s not found, err: %v", name, err)
+		return ephemeralStoragePrice, hugepagesPrice
+	}
+	pod := newRoot.Pods[0]
+	if pod.EphemeralStoragePrice != 0 {
+		ephemeralStoragePrice = pod.EphemeralStoragePrice
+	}
+	if pod.HugepagesPrice != 0 {
+		hugepagesPrice = pod.HugepagesPrice
+	}
+	return ephemeralStoragePrice, hugepagesPrice
+}
+
+// getPricePerGPUForPod returns price per GPU of the pod, pods which are not priced yet get default GPU price
+func getPricePerGPUForPod(ctx context.Context, name, namespace string) float64 {
+	vars := getNamespaceVars(qb.Vars{"$name": name}, namespace)
+	query := vars.Declaration() + ` {
+		` + getNamespaceVar(namespace) + `
+		pods(func: has(isPod)) @filter(eq(name, $name)` + getNamespaceFilter(namespace) + `) {
+			gpuPrice
+		}
+	}`
+	newRoot := podRoot{}
+	err := executeQueryWithVars(ctx, query, vars, &newRoot)
+	if err != nil || len(newRoot.Pods) < 1 || newRoot.Pods[0].GPUPrice == 0 {
+		log.Debugf("gpu price of pod: %s not found, err: %v", name, err)
+		return models.DefaultGPUCostInFloat64
+	}
+	return newRoot.Pods[0].GPUPrice
+}
+
 // RetrievePodsInteractionsForAllLivePodsWithCount returns all pods in the dgraph
-func RetrievePodsInteractionsForAllLivePodsWithCount() ([]models.Pod, error) {
-	q := `query {
-		pods(func: has(isPod)) @filter((NOT has(endTime))) {
+func RetrievePodsInteractionsForAllLivePodsWithCount(ctx context.Context) ([]models.Pod, error) {
+	return RetrievePodsInteractionsForLivePodsWithCountPage(ctx, Page{})
+}
+
+// RetrievePodsInteractionsForLivePodsWithCountPage returns a page of live pods with their interactions, pods have
+// their uid so that the uid of the last pod can be given as after of the next page
+func RetrievePodsInteractionsForLivePodsWithCountPage(ctx context.Context, page Page) ([]models.Pod, error) {
+	return RetrievePodsInteractionsForLivePodsWithCountInNamespace(ctx, All, page)
+}
+
+// RetrievePodsInteractionsForLivePodsWithCountInNamespace returns a page of live pods of the namespace(ex:
+// namespace-default) with their interactions with pods and services of the namespace, all namespaces if it is empty
+func RetrievePodsInteractionsForLivePodsWithCountInNamespace(ctx context.Context, namespace string, page Page) ([]models.Pod, error) {
+	vars := getNamespaceVars(nil, namespace)
+	q := vars.Declaration() + ` {
+		` + getNamespaceVar(namespace) + `
+		pods(func: has(isPod)` + page.getRootArguments() + `) @filter((NOT has(endTime))` + getNamespaceFilter(namespace) + `) {
+			` + page.getUIDField() + `
 			name
-			pod {
+			pod` + getInteractionsFilter(namespace, "") + ` {
 				name
 				count
 			}
-			cid: ~pod @filter(has(isService)) {
+			cid: ~pod @filter(has(isService)` + getNamespaceFilter(namespace) + `) {
 				name
 			}
 		}
@@ -126,7 +269,7 @@ func RetrievePodsInteractionsForAllLivePodsWithCount() ([]models.Pod, error) {
 		Pods []models.Pod `json:"pods"`
 	}
 	newRoot := root{}
-	err := executeQuery(q, &newRoot)
+	err := executeQueryWithVars(ctx, q, vars, &newRoot)
 	if err != nil {
 		return nil, err
 	}
@@ -134,10 +277,10 @@ func RetrievePodsInteractionsForAllLivePodsWithCount() ([]models.Pod, error) {
 }
 
 // RetrievePodsUIDsByLabelsFilter returns pods satisfying the filter conditions for labels (OR logic only)
-func RetrievePodsUIDsByLabelsFilter(labelFilter string) ([]string, error) {
+func RetrievePodsUIDsByLabelsFilter(ctx context.Context, labelFilter string) ([]string, error) {
 	q := getQueryForPodsWithLabelFilter(labelFilter)
 	newRoot := podRoot{}
-	err := executeQuery(q, &newRoot)
+	err := executeQuery(ctx, q, &newRoot)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,96 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"context"
+	"strings"
+)
+
+// PodCost is the month to date cost of a live pod, names are without type prefix(ex: web-1)
+type PodCost struct {
+	Name        string  `json:"name"`
+	Namespace   string  `json:"namespace"`
+	Node        string  `json:"node"`
+	CPUCost     float64 `json:"cpuCost"`
+	MemoryCost  float64 `json:"memoryCost"`
+	StorageCost float64 `json:"storageCost"`
+	GPUCost     float64 `json:"gpuCost"`
+}
+
+type costPod struct {
+	Name        string  `json:"name"`
+	CPUCost     float64 `json:"cpuCost"`
+	MemoryCost  float64 `json:"memoryCost"`
+	StorageCost float64 `json:"storageCost"`
+	GPUCost     float64 `json:"gpuCost"`
+	Namespace   *struct {
+		Name string `json:"name"`
+	} `json:"namespace"`
+	Node *struct {
+		Name string `json:"name"`
+	} `json:"node"`
+}
+
+// RetrieveLivePodCosts returns month to date costs of live pods with adjustments applied
+func RetrieveLivePodCosts(ctx context.Context) []PodCost {
+	root := struct {
+		Pods []costPod `json:"pods"`
+	}{}
+	err := executeQuery(ctx, getQueryForLivePodCosts(), &root)
+	if err != nil {
+		log.Errorf("unable to retrieve costs of pods, err: %v", err)
+		return nil
+	}
+	return toPodCosts(root.Pods)
+}
+
+func getQueryForLivePodCosts() string {
+	return `{
+		pods(func: has(isPod)) @filter(NOT has(endTime)) {
+			` + getQueryForMetricsComputationWithAlias("Pod") + `
+			namespace {
+				name
+			}
+			node {
+				name
+			}
+		}
+	}`
+}
+
+func toPodCosts(pods []costPod) []PodCost {
+	costs := []PodCost{}
+	for _, pod := range pods {
+		cost := PodCost{
+			Name:        strings.TrimPrefix(pod.Name, PodType+"-"),
+			CPUCost:     adjustCost(CostContext{PodType, pod.Name, CPUCostType}, pod.CPUCost),
+			MemoryCost:  adjustCost(CostContext{PodType, pod.Name, MemoryCostType}, pod.MemoryCost),
+			StorageCost: adjustCost(CostContext{PodType, pod.Name, StorageCostType}, pod.StorageCost),
+			GPUCost:     adjustCost(CostContext{PodType, pod.Name, GPUCostType}, pod.GPUCost),
+		}
+		if pod.Namespace != nil {
+			cost.Namespace = strings.TrimPrefix(pod.Namespace.Name, NamespaceType+"-")
+		}
+		if pod.Node != nil {
+			cost.Node = strings.TrimPrefix(pod.Node.Name, NodeType+"-")
+		}
+		costs = append(costs, cost)
+	}
+	return costs
+}
@@ -0,0 +1,38 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToPodCosts(t *testing.T) {
+	pods := []costPod{}
+	assert.NoError(t, json.Unmarshal([]byte(`[
+		{"name": "pod-web-1", "cpuCost": 2, "memoryCost": 1, "namespace": {"name": "namespace-shop"}, "node": {"name": "node-n1"}},
+		{"name": "pod-pending", "storageCost": 0.5, "gpuCost": 3, "namespace": {"name": "namespace-shop"}}
+	]`), &pods))
+
+	assert.Equal(t, []PodCost{
+		{Name: "web-1", Namespace: "shop", Node: "n1", CPUCost: 2, MemoryCost: 1},
+		{Name: "pending", Namespace: "shop", StorageCost: 0.5, GPUCost: 3},
+	}, toPodCosts(pods))
+}
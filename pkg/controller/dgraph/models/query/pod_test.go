@@ -18,6 +18,7 @@
 package query
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
@@ -29,7 +30,7 @@ import (
 )
 
 func mockDgraphForPodQueries(queryType string) {
-	executeQuery = func(query string, root interface{}) error {
+	executeQuery = func(ctx context.Context, query string, root interface{}) error {
 		if queryType == testLabelFilterPods {
 			dummyPodList, ok := root.(*podRoot)
 			if !ok {
@@ -59,7 +60,14 @@ func mockDgraphForPodQueries(queryType string) {
 		return fmt.Errorf("no data found")
 	}
 
-	executeQueryRaw = func(query string) ([]byte, error) {
+	executeQueryWithVars = func(ctx context.Context, query string, vars map[string]string, root interface{}) error {
+		return executeQuery(ctx, query, root)
+	}
+
+	executeQueryRaw = func(ctx context.Context, query string) ([]byte, error) {
+		return nil, fmt.Errorf("pod interactions err")
+	}
+	executeQueryRawWithVars = func(ctx context.Context, query string, vars map[string]string) ([]byte, error) {
 		return nil, fmt.Errorf("pod interactions err")
 	}
 }
@@ -73,7 +81,7 @@ func TestRetrievePodsUIDsByLabelsFilterWithError(t *testing.T) {
 	labels["k1"] = []string{"v1"}
 	inputLabelFilter := CreateFilterFromListOfLabels(labels)
 
-	_, err := RetrievePodsUIDsByLabelsFilter(inputLabelFilter)
+	_, err := RetrievePodsUIDsByLabelsFilter(context.Background(), inputLabelFilter)
 	assert.Error(t, err)
 }
 
@@ -86,7 +94,7 @@ func TestRetrievePodsUIDsByLabelsFilter(t *testing.T) {
 	labels["k1"] = []string{"v1"}
 	inputLabelFilter := CreateFilterFromListOfLabels(labels)
 
-	got, err := RetrievePodsUIDsByLabelsFilter(inputLabelFilter)
+	got, err := RetrievePodsUIDsByLabelsFilter(context.Background(), inputLabelFilter)
 	expected := []string{testPodUID}
 	assert.NoError(t, err)
 	assert.Equal(t, expected, got)
@@ -95,14 +103,14 @@ func TestRetrievePodsUIDsByLabelsFilter(t *testing.T) {
 // TestRetrieveAllLivePodsWithDgraphError ...
 func TestRetrieveAllLivePodsWithDgraphError(t *testing.T) {
 	mockDgraphForPodQueries(testWrongQuery)
-	got := RetrieveAllLivePods()
+	got := RetrieveAllLivePods(context.Background())
 	assert.Nil(t, got)
 }
 
 // TestRetrieveAllLivePods ...
 func TestRetrieveAllLivePods(t *testing.T) {
 	mockDgraphForPodQueries(testAlivePods)
-	got := RetrieveAllLivePods()
+	got := RetrieveAllLivePods(context.Background())
 	expected := []models.Pod{
 		{
 			ID:   dgraph.ID{UID: testPodUID, Xid: testPodXID},
@@ -114,19 +122,24 @@ func TestRetrieveAllLivePods(t *testing.T) {
 
 func TestPodInteractionsErrorCase(t *testing.T) {
 	mockDgraphForPodQueries(testPodInteractions)
-	gotAllOrphan := RetrievePodsInteractions("", true)
-	gotAllNonOrphan := RetrievePodsInteractions("", false)
-	gotWithName := RetrievePodsInteractions(testPodName, false)
-	_, err := RetrievePodsInteractionsForAllLivePodsWithCount()
+	gotAllOrphan, errAllOrphan := RetrievePodsInteractions(context.Background(), "", true)
+	gotAllNonOrphan, errAllNonOrphan := RetrievePodsInteractions(context.Background(), "", false)
+	gotWithName, errWithName := RetrievePodsInteractions(context.Background(), testPodName, false)
+	gotRaw := RetrievePodsInteractionsRaw(context.Background(), testPodName, All, false, Page{})
+	_, err := RetrievePodsInteractionsForAllLivePodsWithCount(context.Background())
 	assert.Nil(t, gotAllOrphan)
 	assert.Nil(t, gotAllNonOrphan)
 	assert.Nil(t, gotWithName)
+	assert.Nil(t, gotRaw)
+	assert.Error(t, errAllOrphan)
+	assert.Error(t, errAllNonOrphan)
+	assert.Error(t, errWithName)
 	assert.Error(t, err)
 }
 
 func TestGetPricePerResourceForPodWithError(t *testing.T) {
 	mockDgraphForResourceQueries(testWrongQuery, testPodName, PodType)
-	gotCPUPrice, gotMemoryPrice := getPricePerResourceForPod(testPodName)
+	gotCPUPrice, gotMemoryPrice := getPricePerResourceForPod(context.Background(), testPodName, All)
 	expectedCPUPrice, expectedMemoryPrice := models.DefaultCPUCostInFloat64, models.DefaultMemCostInFloat64
 	assert.Equal(t, expectedCPUPrice, gotCPUPrice)
 	assert.Equal(t, expectedMemoryPrice, gotMemoryPrice)
@@ -134,8 +147,65 @@ func TestGetPricePerResourceForPodWithError(t *testing.T) {
 
 func TestGetPricePerResourceForPod(t *testing.T) {
 	mockDgraphForResourceQueries(testPodPrices, testPodName, PodType)
-	gotCPUPrice, gotMemoryPrice := getPricePerResourceForPod(testPodName)
+	gotCPUPrice, gotMemoryPrice := getPricePerResourceForPod(context.Background(), testPodName, All)
 	expectedCPUPrice, expectedMemoryPrice := testCPUPrice, testMemoryPrice
 	assert.Equal(t, expectedCPUPrice, gotCPUPrice)
 	assert.Equal(t, expectedMemoryPrice, gotMemoryPrice)
 }
+
+func TestGetPricePerLocalResourceForPodWithError(t *testing.T) {
+	mockDgraphForResourceQueries(testWrongQuery, testPodName, PodType)
+	gotEphemeralStoragePrice, gotHugepagesPrice := getPricePerLocalResourceForPod(context.Background(), testPodName, All, testMemoryPrice)
+	assert.Equal(t, models.DefaultLocalDiskCostInFloat64, gotEphemeralStoragePrice)
+	assert.Equal(t, testMemoryPrice, gotHugepagesPrice)
+}
+
+func TestGetPricePerLocalResourceForPod(t *testing.T) {
+	mockDgraphForResourceQueries(testPodPrices, testPodName, PodType)
+	gotEphemeralStoragePrice, gotHugepagesPrice := getPricePerLocalResourceForPod(context.Background(), testPodName, All, testMemoryPrice)
+	assert.Equal(t, testEphemeralStoragePrice, gotEphemeralStoragePrice)
+	assert.Equal(t, testHugepagesPrice, gotHugepagesPrice)
+}
+
+func TestGetPricePerGPUForPod(t *testing.T) {
+	mockDgraphForResourceQueries(testPodPrices, testPodName, PodType)
+	assert.Equal(t, testGPUPrice, getPricePerGPUForPod(context.Background(), testPodName, All))
+
+	mockDgraphForResourceQueries(testWrongQuery, testPodName, PodType)
+	assert.Equal(t, models.DefaultGPUCostInFloat64, getPricePerGPUForPod(context.Background(), testPodName, All))
+}
+
+// TestGetQueryForPodsInteractionsInNamespace ...
+func TestGetQueryForPodsInteractionsInNamespace(t *testing.T) {
+	query, vars := getQueryForPodsInteractions(All, "namespace-dev", false, Page{First: 10})
+	assert.Equal(t, "namespace-dev", vars["$namespace"])
+	assert.Contains(t, query, "query q($namespace: string) {")
+	assert.Contains(t, query, "namespaceResources as ~namespace")
+	assert.Contains(t, query, "pods(func: has(isPod), first: 10) @filter(has(pod) AND uid(namespaceResources)) {")
+	assert.Contains(t, query, "outbound: pod @facets @filter(uid(namespaceResources)) {")
+	assert.Contains(t, query, "inbound: ~pod @facets @filter(has(isPod) AND uid(namespaceResources)) {")
+
+	orphan, _ := getQueryForPodsInteractions(All, "namespace-dev", true, Page{})
+	assert.Contains(t, orphan, "pods(func: has(isPod)) @filter(uid(namespaceResources)) {")
+}
+
+// TestGetQueryForPodsInteractionsInAllNamespaces ...
+func TestGetQueryForPodsInteractionsInAllNamespaces(t *testing.T) {
+	query, vars := getQueryForPodsInteractions(All, All, true, Page{})
+	assert.Equal(t, 0, len(vars))
+	assert.Contains(t, query, "pods(func: has(isPod)) {")
+	assert.Contains(t, query, "outbound: pod @facets {")
+	assert.Contains(t, query, "inbound: ~pod @facets @filter(has(isPod)) {")
+	assert.NotContains(t, query, "namespaceResources")
+
+	named, vars := getQueryForPodsInteractions(testPodName, All, false, Page{})
+	assert.Equal(t, testPodName, vars["$name"])
+	assert.Contains(t, named, "pods(func: has(isPod)) @filter(eq(name, $name)) {")
+}
+
+// TestGetLivePodsQueryInNamespace ...
+func TestGetLivePodsQueryInNamespace(t *testing.T) {
+	query, vars := getLivePodsQuery("namespace-dev")
+	assert.Equal(t, "namespace-dev", vars["$namespace"])
+	assert.Contains(t, query, "pods(func: has(isPod)) @filter(NOT has(endTime) AND uid(namespaceResources)) {")
+}
@@ -0,0 +1,105 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"sync"
+)
+
+// Rounding modes
+const (
+	RoundNearest = "nearest"
+	RoundUp      = "up"
+	RoundDown    = "down"
+)
+
+// DefaultPricePrecision is the number of decimals of prices interpolated in query math
+const DefaultPricePrecision = 11
+
+// RoundingPolicy rounds values to Precision decimals in the given Mode(nearest, up or down)
+type RoundingPolicy struct {
+	Precision int
+	Mode      string
+}
+
+// NewRoundingPolicy returns the policy after validating precision and mode, empty mode rounds to nearest
+func NewRoundingPolicy(precision int, mode string) (RoundingPolicy, error) {
+	if precision < 0 {
+		return RoundingPolicy{}, fmt.Errorf("rounding precision should not be negative, given: %v", precision)
+	}
+	if mode == "" {
+		mode = RoundNearest
+	}
+	if mode != RoundNearest && mode != RoundUp && mode != RoundDown {
+		return RoundingPolicy{}, fmt.Errorf("unknown rounding mode: %s", mode)
+	}
+	return RoundingPolicy{Precision: precision, Mode: mode}, nil
+}
+
+// Round returns the value rounded by the policy
+func (p RoundingPolicy) Round(value float64) float64 {
+	scale := math.Pow(10, float64(p.Precision))
+	switch p.Mode {
+	case RoundUp:
+		return math.Ceil(value*scale) / scale
+	case RoundDown:
+		return math.Floor(value*scale) / scale
+	}
+	return math.Round(value*scale) / scale
+}
+
+var (
+	precisionMu sync.RWMutex
+	pricePolicy = RoundingPolicy{Precision: DefaultPricePrecision, Mode: RoundNearest}
+	costPolicy  *RoundingPolicy
+)
+
+// SetPricePolicy sets the precision and rounding of prices used in query math
+func SetPricePolicy(policy RoundingPolicy) {
+	precisionMu.Lock()
+	defer precisionMu.Unlock()
+	pricePolicy = policy
+}
+
+// SetCostPolicy sets the rounding applied on every cost returned by APIs after cost adjustments,
+// nil returns costs unrounded
+func SetCostPolicy(policy *RoundingPolicy) {
+	precisionMu.Lock()
+	defer precisionMu.Unlock()
+	costPolicy = policy
+}
+
+// formatPrice returns the price rounded to the price precision for interpolation in query math
+func formatPrice(price float64) string {
+	precisionMu.RLock()
+	defer precisionMu.RUnlock()
+	return strconv.FormatFloat(pricePolicy.Round(price), 'f', pricePolicy.Precision, 64)
+}
+
+// roundCost rounds the cost with the cost policy if one is set
+func roundCost(cost float64) float64 {
+	precisionMu.RLock()
+	defer precisionMu.RUnlock()
+	if costPolicy == nil {
+		return cost
+	}
+	return costPolicy.Round(cost)
+}
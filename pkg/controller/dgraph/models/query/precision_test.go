@@ -0,0 +1,101 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestRoundingPolicy ...
+func TestRoundingPolicy(t *testing.T) {
+	policy, err := NewRoundingPolicy(2, "")
+	assert.Nil(t, err)
+	assert.Equal(t, RoundNearest, policy.Mode)
+	assert.Equal(t, 1.24, policy.Round(1.235))
+	assert.Equal(t, 1.24, RoundingPolicy{2, RoundUp}.Round(1.231))
+	assert.Equal(t, 1.23, RoundingPolicy{2, RoundDown}.Round(1.239))
+
+	_, err = NewRoundingPolicy(-1, RoundUp)
+	assert.NotNil(t, err)
+	_, err = NewRoundingPolicy(2, "half-even")
+	assert.NotNil(t, err)
+}
+
+// TestFormatPrice ...
+func TestFormatPrice(t *testing.T) {
+	assert.Equal(t, "0.00013888888", formatPrice(0.00013888888))
+	assert.Equal(t, "0.02400000000", formatPrice(0.024))
+
+	SetPricePolicy(RoundingPolicy{4, RoundUp})
+	defer SetPricePolicy(RoundingPolicy{DefaultPricePrecision, RoundNearest})
+	assert.Equal(t, "0.0002", formatPrice(0.00013888888))
+}
+
+// TestAdjustCostWithCostPolicy ...
+func TestAdjustCostWithCostPolicy(t *testing.T) {
+	ctx := CostContext{PodType, "pod-web", CPUCostType}
+	assert.Equal(t, 1.23456, adjustCost(ctx, 1.23456))
+
+	SetCostPolicy(&RoundingPolicy{2, RoundNearest})
+	defer SetCostPolicy(nil)
+	assert.Equal(t, 1.23, adjustCost(ctx, 1.23456))
+}
+
+type batchAdjustment struct {
+	calls int
+}
+
+func (b *batchAdjustment) Name() string {
+	return "batch"
+}
+
+func (b *batchAdjustment) Adjust(ctx CostContext, cost float64) float64 {
+	return cost * 2
+}
+
+func (b *batchAdjustment) AdjustAll(ctxs []CostContext, costs []float64) []float64 {
+	b.calls++
+	adjusted := []float64{}
+	for index := range costs {
+		adjusted = append(adjusted, b.Adjust(ctxs[index], costs[index]))
+	}
+	return adjusted
+}
+
+// TestAdjustCostsWithBatchAdjustment ...
+func TestAdjustCostsWithBatchAdjustment(t *testing.T) {
+	adjustment := &batchAdjustment{}
+	SetCostAdjustments([]CostAdjustment{adjustment})
+	defer SetCostAdjustments(nil)
+	SetCostPolicy(&RoundingPolicy{2, RoundNearest})
+	defer SetCostPolicy(nil)
+
+	parent := ParentWrapper{Name: "namespace-shop", Type: NamespaceType, CPUCost: 1.2345, Children: []Children{
+		{Name: "pod-web", Type: PodType, CPUCost: 0.5, MemoryCost: 0.25},
+		{Name: "pod-cart", Type: PodType},
+	}}
+	adjustCosts(&parent)
+	assert.Equal(t, 1, adjustment.calls)
+	assert.Equal(t, 2.47, parent.CPUCost)
+	assert.Equal(t, 0.0, parent.MemoryCost)
+	assert.Equal(t, 1.0, parent.Children[0].CPUCost)
+	assert.Equal(t, 0.5, parent.Children[0].MemoryCost)
+	assert.Equal(t, 0.0, parent.Children[1].CPUCost)
+}
@@ -19,15 +19,18 @@ package query
 
 import (
 	"github.com/vmware/purser/pkg/controller/dgraph/models"
+	qb "github.com/vmware/purser/pkg/querybuilder"
 )
 
-// DeploymentMetrics query
-func getQueryForDeploymentMetrics(name string) string {
-	return `query {
-		dep as var(func: has(isDeployment)) @filter(eq(name, "` + name + `")) {
+// DeploymentMetrics query, the deployment is looked up in the namespace if it is given
+func getQueryForDeploymentMetrics(name, namespace string, timeRange TimeRange) (string, qb.Vars) {
+	vars := getNamespaceVars(qb.Vars{"$name": name}, namespace)
+	return vars.Declaration() + ` {
+		` + getNamespaceVar(namespace) + `
+		dep as var(func: has(isDeployment)) @filter(eq(name, $name)` + getNamespaceFilter(namespace) + `) {
 			~deployment @filter(has(isReplicaset)) {
-				~replicaset @filter(has(isPod)) {
-					` + getQueryForMetricsComputation("ReplicasetPod") + `
+				~replicaset @filter(has(isPod)` + getTimeRangeFilter(timeRange) + `) {
+					` + getQueryForMetricsComputationInRange("ReplicasetPod", timeRange) + `
 				}
 				` + getQueryForAggregatingChildMetrics("DeploymentReplicaset", "ReplicasetPod") + `
 			}
@@ -40,83 +43,138 @@ func getQueryForDeploymentMetrics(name string) string {
 			}
 			` + getQueryFromSubQueryWithAlias("Deployment") + `
 		}
-	}`
+	}`, vars
 }
 
-// PodMetrics query
-func getQueryForPodMetrics(name, cpuPrice, memoryPrice string) string {
-	return `query {
-		parent(func: has(isPod)) @filter(eq(name, "` + name + `")) {
+// getQueryForCronJobMetrics returns query of the cronjob with its jobs as children, costs of pods of all jobs
+// existing in the time range(ex: deleted jobs of previous runs) are rolled up to their job and the cronjob
+func getQueryForCronJobMetrics(name, namespace string, timeRange TimeRange) (string, qb.Vars) {
+	vars := getNamespaceVars(qb.Vars{"$name": name}, namespace)
+	return vars.Declaration() + ` {
+		` + getNamespaceVar(namespace) + `
+		cronJob as var(func: has(isCronJob)) @filter(eq(name, $name)` + getNamespaceFilter(namespace) + `) {
+			~cronjob @filter(has(isJob)` + getTimeRangeFilter(timeRange) + `) {
+				~job @filter(has(isPod)` + getTimeRangeFilter(timeRange) + `) {
+					` + getQueryForMetricsComputationInRange("JobPod", timeRange) + `
+				}
+				` + getQueryForAggregatingChildMetrics("CronJobJob", "JobPod") + `
+			}
+			` + getQueryForAggregatingChildMetrics("CronJob", "CronJobJob") + `
+		}
+
+		parent(func: uid(cronJob)) {
+			children: ~cronjob @filter(has(isJob)` + getTimeRangeFilter(timeRange) + `) {
+				` + getQueryFromSubQueryWithAlias("CronJobJob") + `
+			}
+			` + getQueryFromSubQueryWithAlias("CronJob") + `
+		}
+	}`, vars
+}
+
+// PodMetrics query, containers are priced with their own prices(price overrides) if present, otherwise with
+// the given prices of the pod. The pod is looked up in the namespace if it is given.
+func getQueryForPodMetrics(name, namespace string, timeRange TimeRange, cpuPrice, memoryPrice, ephemeralStoragePrice, hugepagesPrice, gpuPrice string) (string, qb.Vars) {
+	vars := getNamespaceVars(qb.Vars{"$name": name}, namespace)
+	return vars.Declaration() + ` {
+		` + getNamespaceVar(namespace) + `
+		parent(func: has(isPod)) @filter(eq(name, $name)` + getNamespaceFilter(namespace) + `) {
 			children: ~pod @filter(has(isContainer)) {
 				name
 				type
-				` + getQueryForTimeComputation("Container") + `
+				` + getQueryForTimeComputationInRange("Container", timeRange) + `
 				cpu: cpu as cpuRequest
 				memory: memory as memoryRequest
-				cpuCost: math(cpu * durationInHoursContainer * ` + cpuPrice + `)
-				memoryCost: math(memory * durationInHoursContainer * ` + memoryPrice + `)
+				ephemeralStorage: ephemeralStorage as ephemeralStorageRequest
+				hugepages: hugepages as hugepagesRequest
+				isPricedContainer as count(cpuPrice)
+				pricePerCPUContainer as cpuPrice
+				pricePerMemoryContainer as memoryPrice
+				cpuCost: math(cpu * durationInHoursContainer * cond(isPricedContainer == 0, ` + cpuPrice + `, pricePerCPUContainer))
+				memoryCost: math(memory * durationInHoursContainer * cond(isPricedContainer == 0, ` + memoryPrice + `, pricePerMemoryContainer))
+				ephemeralStorageCost: math(ephemeralStorage * durationInHoursContainer * ` + ephemeralStoragePrice + `)
+				hugepagesCost: math(hugepages * durationInHoursContainer * ` + hugepagesPrice + `)
+				gpu: gpu as gpuRequest
+				gpuCost: math(gpu * durationInHoursContainer * ` + gpuPrice + `)
+				pricePerExtendedResources as extendedResourcePrice
+				extendedResourceCost: math(pricePerExtendedResources * durationInHoursContainer)
 			}
-			` + getQueryForMetricsComputationWithAlias("Pod") + `
+			` + getQueryForMetricsComputationWithAliasInRange("Pod", timeRange) + `
+			ephemeralStorage: ephemeralStoragePod as ephemeralStorageRequest
+			ephemeralStorageCost: math(ephemeralStoragePod * durationInHoursPod * ` + ephemeralStoragePrice + `)
+			hugepages: hugepagesPod as hugepagesRequest
+			hugepagesCost: math(hugepagesPod * durationInHoursPod * ` + hugepagesPrice + `)
 		}
-	}`
+	}`, vars
 }
 
-// ContainerMetrics query
-func getQueryForContainerMetrics(name string) string {
-	return `query {
-		parent(func: has(isContainer)) @filter(eq(name, "` + name + `")) {
+// ContainerMetrics query, the container is looked up in the namespace if it is given
+func getQueryForContainerMetrics(name, namespace string, timeRange TimeRange) (string, qb.Vars) {
+	vars := getNamespaceVars(qb.Vars{"$name": name}, namespace)
+	return vars.Declaration() + ` {
+		` + getNamespaceVar(namespace) + `
+		parent(func: has(isContainer)) @filter(eq(name, $name)` + getNamespaceFilter(namespace) + `) {
 			name
 			type
 			cpu: cpu as cpuRequest
 			memory: memory as memoryRequest
-			` + getQueryForTimeComputation("") + `
-			cpuCost: math(cpu * durationInHours * ` + models.DefaultCPUCostPerCPUPerHour + `)
-			memoryCost: math(memory * durationInHours * ` + models.DefaultMemCostPerGBPerHour + `)
+			gpu: gpu as gpuRequest
+			` + getQueryForTimeComputationInRange("", timeRange) + `
+			cpuCost: math(cpu * durationInHours * ` + formatPrice(models.DefaultCPUCostInFloat64) + `)
+			memoryCost: math(memory * durationInHours * ` + formatPrice(models.DefaultMemCostInFloat64) + `)
+			gpuCost: math(gpu * durationInHours * ` + formatPrice(models.DefaultGPUCostInFloat64) + `)
 		}
-	}`
+	}`, vars
 }
 
 // PVMetrics query
-func getQueryForPVMetrics(name string) string {
-	return `query {
-		parent(func: has(isPersistentVolume)) @filter(eq(name, "` + name + `")) {
-			children: ~pv @filter(has(isPersistentVolumeClaim)) {
+func getQueryForPVMetrics(name string, timeRange TimeRange) (string, qb.Vars) {
+	vars := qb.Vars{"$name": name}
+	return vars.Declaration() + ` {
+		parent(func: has(isPersistentVolume)) @filter(eq(name, $name)) {
+			children: ~pv @filter(has(isPersistentVolumeClaim)` + getTimeRangeFilter(timeRange) + `) {
 				name
 				type
 				storage: pvcStorage as storageCapacity
-				` + getQueryForTimeComputation("PVC") + `
-				storageCost: math(pvcStorage * durationInHoursPVC * ` + models.DefaultStorageCostPerGBPerHour + `)
+				` + getQueryForTimeComputationInRange("PVC", timeRange) + `
+				` + getQueryForStoragePrice("PVC") + `
+				storageCost: math(pvcStorage * durationInHoursPVC * ` + getStoragePrice("PVC") + `)
 			}
 			name
 			type
 			storage: storage as storageCapacity
 			storageCapacity
-			` + getQueryForTimeComputation("") + `
-			storageCost: math(storage * durationInHours * ` + models.DefaultStorageCostPerGBPerHour + `)
+			` + getQueryForTimeComputationInRange("", timeRange) + `
+			` + getQueryForStoragePrice("") + `
+			storageCost: math(storage * durationInHours * ` + getStoragePrice("") + `)
 			storageAllocated: sum(val(pvcStorage))
         }
-    }`
+    }`, vars
 }
 
 // PVCMetrics query
-func getQueryForPVCMetrics(name string) string {
-	return `query {
-		parent(func: has(isPersistentVolumeClaim)) @filter(eq(name, "` + name + `")) {
+func getQueryForPVCMetrics(name string, timeRange TimeRange) (string, qb.Vars) {
+	vars := qb.Vars{"$name": name}
+	return vars.Declaration() + ` {
+		parent(func: has(isPersistentVolumeClaim)) @filter(eq(name, $name)) {
 			name
 			type
 			storage: storage as storageCapacity
-			` + getQueryForTimeComputation("") + `
-			storageCost: math(storage * durationInHours * ` + models.DefaultStorageCostPerGBPerHour + `)
+			` + getQueryForTimeComputationInRange("", timeRange) + `
+			` + getQueryForStoragePrice("") + `
+			storageCost: math(storage * durationInHours * ` + getStoragePrice("") + `)
         }
-    }`
+    }`, vars
 }
 
-// NodeMetrics query
-func getQueryForNodeMetrics(name string) string {
-	return `query {
-		parent(func: has(isNode)) @filter(eq(name, "` + name + `")) {
-			children: ~node @filter(has(isPod)) {
-				` + getQueryForMetricsComputationWithAlias("Pod") + `
+// NodeMetrics query, utilized costs of the node are the sums of utilized costs of its pods
+func getQueryForNodeMetrics(name string, timeRange TimeRange) (string, qb.Vars) {
+	vars := qb.Vars{"$name": name}
+	return vars.Declaration() + ` {
+		parent(func: has(isNode)) @filter(eq(name, $name)) {
+			children: ~node @filter(has(isPod)` + getTimeRangeFilter(timeRange) + `) {
+				` + getQueryForMetricsComputationWithAliasInRange("Pod", timeRange) + `
+				utilizedCPUCostPod as math(cpuUsagePod * durationInHoursPod * pricePerCPUPod)
+				utilizedMemoryCostPod as math(memoryUsagePod * durationInHoursPod * pricePerMemoryPod)
 			}
 			name
 			type
@@ -125,52 +183,70 @@ func getQueryForNodeMetrics(name string) string {
 			storage: storage as sum(val(storagePod))
 			cpuAllocated: sum(val(cpuPod))
 			memoryAllocated: sum(val(memoryPod))
+			utilizedCPUCost: sum(val(utilizedCPUCostPod))
+			utilizedMemoryCost: sum(val(utilizedMemoryCostPod))
 			cpuCapacity
 			memoryCapacity
-			` + getQueryForTimeComputation("") + `
+			` + getQueryForTimeComputationInRange("", timeRange) + `
 			` + getQueryForCostWithPriceWithAlias("") + `
 		}
-	}`
+	}`, vars
 }
 
 // NamespaceMetrics query
-func getQueryForNamespaceMetrics(name string) string {
-	return `query {
-		ns as var(func: has(isNamespace)) @filter(eq(name, "` + name + `")) {
-			childs as ~namespace @filter(has(isDeployment) OR has(isStatefulset) OR has(isJob) OR has(isDaemonset) OR (has(isReplicaset) AND (NOT has(deployment)))) {
+func getQueryForNamespaceMetrics(name, os string, timeRange TimeRange) (string, qb.Vars) {
+	podFilter := getOSFilter(os) + getTimeRangeFilter(timeRange)
+	vars := qb.Vars{"$name": name}
+	return vars.Declaration() + ` {
+		ns as var(func: has(isNamespace)) @filter(eq(name, $name)) {
+			childs as ~namespace @filter(has(isDeployment) OR has(isStatefulset) OR has(isJob) OR has(isDaemonset) OR has(isDeploymentConfig) OR (has(isReplicaset) AND (NOT has(deployment)))) {
 				name
 				type
 				~deployment @filter(has(isReplicaset)) {
 					name
 					type
-					~replicaset @filter(has(isPod)) {
-						` + getQueryForMetricsComputation("ReplicasetPod") + `
+					~replicaset @filter(has(isPod)` + podFilter + `) {
+						` + getQueryForMetricsComputationInRange("ReplicasetPod", timeRange) + `
 			        }
 					` + getQueryForAggregatingChildMetrics("DeploymentReplicaset", "ReplicasetPod") + `
                 }
-				~statefulset @filter(has(isPod)) {
-					` + getQueryForMetricsComputation("StatefulsetPod") + `
+				~statefulset @filter(has(isPod)` + podFilter + `) {
+					` + getQueryForMetricsComputationInRange("StatefulsetPod", timeRange) + `
+                }
+				~job @filter(has(isPod)` + podFilter + `) {
+					` + getQueryForMetricsComputationInRange("JobPod", timeRange) + `
                 }
-				~job @filter(has(isPod)) {
-					` + getQueryForMetricsComputation("JobPod") + `
+				~daemonset @filter(has(isPod)` + podFilter + `) {
+					` + getQueryForMetricsComputationInRange("DaemonsetPod", timeRange) + `
                 }
-				~daemonset @filter(has(isPod)) {
-					` + getQueryForMetricsComputation("DaemonsetPod") + `
+				~replicaset @filter(has(isPod)` + podFilter + `) {
+					` + getQueryForMetricsComputationInRange("ReplicasetSimplePod", timeRange) + `
                 }
-				~replicaset @filter(has(isPod)) {
-					` + getQueryForMetricsComputation("ReplicasetSimplePod") + `
+				~deploymentconfig @filter(has(isPod)` + podFilter + `) {
+					` + getQueryForMetricsComputationInRange("DeploymentconfigPod", timeRange) + `
                 }
 				` + getQueryForAggregatingChildMetrics("SumReplicasetSimplePod", "ReplicasetSimplePod") + `
 				` + getQueryForAggregatingChildMetrics("SumDaemonsetPod", "DaemonsetPod") + `
 				` + getQueryForAggregatingChildMetrics("SumJobPod", "JobPod") + `
 				` + getQueryForAggregatingChildMetrics("SumStatefulsetPod", "StatefulsetPod") + `
 				` + getQueryForAggregatingChildMetrics("SumDeploymentReplicaset", "DeploymentReplicaset") + `
-				cpuNamespaceChild as math(cpu` + "SumReplicasetSimplePod" + ` + cpu` + "SumDaemonsetPod" + ` + cpu` + "SumJobPod" + ` + cpu` + "SumStatefulsetPod" + ` + cpu` + "SumDeploymentReplicaset" + `)
-				memoryNamespaceChild as math(memory` + "SumReplicasetSimplePod" + ` + memory` + "SumDaemonsetPod" + ` + memory` + "SumJobPod" + ` + memory` + "SumStatefulsetPod" + ` + memory` + "SumDeploymentReplicaset" + `)
-				storageNamespaceChild as math(storage` + "SumReplicasetSimplePod" + ` + storage` + "SumDaemonsetPod" + ` + storage` + "SumJobPod" + ` + storage` + "SumStatefulsetPod" + ` + storage` + "SumDeploymentReplicaset" + `)
-				cpuCostNamespaceChild as math(cpuCost` + "SumReplicasetSimplePod" + ` + cpuCost` + "SumDaemonsetPod" + ` + cpuCost` + "SumJobPod" + ` + cpuCost` + "SumStatefulsetPod" + ` + cpuCost` + "SumDeploymentReplicaset" + `)
-				memoryCostNamespaceChild as math(memoryCost` + "SumReplicasetSimplePod" + ` + memoryCost` + "SumDaemonsetPod" + ` + memoryCost` + "SumJobPod" + ` + memoryCost` + "SumStatefulsetPod" + ` + memoryCost` + "SumDeploymentReplicaset" + `)
-				storageCostNamespaceChild as math(storageCost` + "SumReplicasetSimplePod" + ` + storageCost` + "SumDaemonsetPod" + ` + storageCost` + "SumJobPod" + ` + storageCost` + "SumStatefulsetPod" + ` + storageCost` + "SumDeploymentReplicaset" + `)
+				` + getQueryForAggregatingChildMetrics("SumDeploymentconfigPod", "DeploymentconfigPod") + `
+				cpuNamespaceChild as math(cpu` + "SumReplicasetSimplePod" + ` + cpu` + "SumDaemonsetPod" + ` + cpu` + "SumJobPod" + ` + cpu` + "SumStatefulsetPod" + ` + cpu` + "SumDeploymentReplicaset" + ` + cpu` + "SumDeploymentconfigPod" + `)
+				memoryNamespaceChild as math(memory` + "SumReplicasetSimplePod" + ` + memory` + "SumDaemonsetPod" + ` + memory` + "SumJobPod" + ` + memory` + "SumStatefulsetPod" + ` + memory` + "SumDeploymentReplicaset" + ` + memory` + "SumDeploymentconfigPod" + `)
+				storageNamespaceChild as math(storage` + "SumReplicasetSimplePod" + ` + storage` + "SumDaemonsetPod" + ` + storage` + "SumJobPod" + ` + storage` + "SumStatefulsetPod" + ` + storage` + "SumDeploymentReplicaset" + ` + storage` + "SumDeploymentconfigPod" + `)
+				cpuCostNamespaceChild as math(cpuCost` + "SumReplicasetSimplePod" + ` + cpuCost` + "SumDaemonsetPod" + ` + cpuCost` + "SumJobPod" + ` + cpuCost` + "SumStatefulsetPod" + ` + cpuCost` + "SumDeploymentReplicaset" + ` + cpuCost` + "SumDeploymentconfigPod" + `)
+				memoryCostNamespaceChild as math(memoryCost` + "SumReplicasetSimplePod" + ` + memoryCost` + "SumDaemonsetPod" + ` + memoryCost` + "SumJobPod" + ` + memoryCost` + "SumStatefulsetPod" + ` + memoryCost` + "SumDeploymentReplicaset" + ` + memoryCost` + "SumDeploymentconfigPod" + `)
+				storageCostNamespaceChild as math(storageCost` + "SumReplicasetSimplePod" + ` + storageCost` + "SumDaemonsetPod" + ` + storageCost` + "SumJobPod" + ` + storageCost` + "SumStatefulsetPod" + ` + storageCost` + "SumDeploymentReplicaset" + ` + storageCost` + "SumDeploymentconfigPod" + `)
+				ephemeralStorageCostNamespaceChild as math(ephemeralStorageCost` + "SumReplicasetSimplePod" + ` + ephemeralStorageCost` + "SumDaemonsetPod" + ` + ephemeralStorageCost` + "SumJobPod" + ` + ephemeralStorageCost` + "SumStatefulsetPod" + ` + ephemeralStorageCost` + "SumDeploymentReplicaset" + ` + ephemeralStorageCost` + "SumDeploymentconfigPod" + `)
+				hugepagesCostNamespaceChild as math(hugepagesCost` + "SumReplicasetSimplePod" + ` + hugepagesCost` + "SumDaemonsetPod" + ` + hugepagesCost` + "SumJobPod" + ` + hugepagesCost` + "SumStatefulsetPod" + ` + hugepagesCost` + "SumDeploymentReplicaset" + ` + hugepagesCost` + "SumDeploymentconfigPod" + `)
+				gpuNamespaceChild as math(gpu` + "SumReplicasetSimplePod" + ` + gpu` + "SumDaemonsetPod" + ` + gpu` + "SumJobPod" + ` + gpu` + "SumStatefulsetPod" + ` + gpu` + "SumDeploymentReplicaset" + ` + gpu` + "SumDeploymentconfigPod" + `)
+				gpuCostNamespaceChild as math(gpuCost` + "SumReplicasetSimplePod" + ` + gpuCost` + "SumDaemonsetPod" + ` + gpuCost` + "SumJobPod" + ` + gpuCost` + "SumStatefulsetPod" + ` + gpuCost` + "SumDeploymentReplicaset" + ` + gpuCost` + "SumDeploymentconfigPod" + `)
+				extendedResourceCostNamespaceChild as math(extendedResourceCost` + "SumReplicasetSimplePod" + ` + extendedResourceCost` + "SumDaemonsetPod" + ` + extendedResourceCost` + "SumJobPod" + ` + extendedResourceCost` + "SumStatefulsetPod" + ` + extendedResourceCost` + "SumDeploymentReplicaset" + ` + extendedResourceCost` + "SumDeploymentconfigPod" + `)
+				bandwidthCostNamespaceChild as math(bandwidthCost` + "SumReplicasetSimplePod" + ` + bandwidthCost` + "SumDaemonsetPod" + ` + bandwidthCost` + "SumJobPod" + ` + bandwidthCost` + "SumStatefulsetPod" + ` + bandwidthCost` + "SumDeploymentReplicaset" + ` + bandwidthCost` + "SumDeploymentconfigPod" + `)
+				carbonNamespaceChild as math(carbon` + "SumReplicasetSimplePod" + ` + carbon` + "SumDaemonsetPod" + ` + carbon` + "SumJobPod" + ` + carbon` + "SumStatefulsetPod" + ` + carbon` + "SumDeploymentReplicaset" + ` + carbon` + "SumDeploymentconfigPod" + `)
+				energyKWhNamespaceChild as math(energyKWh` + "SumReplicasetSimplePod" + ` + energyKWh` + "SumDaemonsetPod" + ` + energyKWh` + "SumJobPod" + ` + energyKWh` + "SumStatefulsetPod" + ` + energyKWh` + "SumDeploymentReplicaset" + ` + energyKWh` + "SumDeploymentconfigPod" + `)
+				utilizedCPUCostNamespaceChild as math(utilizedCPUCost` + "SumReplicasetSimplePod" + ` + utilizedCPUCost` + "SumDaemonsetPod" + ` + utilizedCPUCost` + "SumJobPod" + ` + utilizedCPUCost` + "SumStatefulsetPod" + ` + utilizedCPUCost` + "SumDeploymentReplicaset" + ` + utilizedCPUCost` + "SumDeploymentconfigPod" + `)
+				utilizedMemoryCostNamespaceChild as math(utilizedMemoryCost` + "SumReplicasetSimplePod" + ` + utilizedMemoryCost` + "SumDaemonsetPod" + ` + utilizedMemoryCost` + "SumJobPod" + ` + utilizedMemoryCost` + "SumStatefulsetPod" + ` + utilizedMemoryCost` + "SumDeploymentReplicaset" + ` + utilizedMemoryCost` + "SumDeploymentconfigPod" + `)
 			}
 			` + getQueryForAggregatingChildMetrics("Namespace", "NamespaceChild") + `
 		}
@@ -181,15 +257,15 @@ func getQueryForNamespaceMetrics(name string) string {
 			}
 			` + getQueryFromSubQueryWithAlias("Namespace") + `
         }
-    }`
+    }`, vars
 }
 
-// LogicalResourcesMetrics query
-func getMetricsQueryForLogicalResources() string {
+// LogicalResourcesMetrics query, pods existing in the time range are considered if it is given
+func getMetricsQueryForLogicalResources(os string, timeRange TimeRange, deleted string) string {
 	return `query {
-			ns as var(func: has(isNamespace)) {
-				~namespace @filter(has(isPod) AND (NOT has(endTime))) {
-					` + getQueryForMetricsComputation("NamespacePod") + `
+			ns as var(func: has(isNamespace)) @filter(has(isNamespace)` + getDeletedFilter(deleted) + `) {
+				~namespace @filter(has(isPod) AND ` + getLiveFilterInRange(timeRange) + getOSFilter(os) + `) {
+					` + getQueryForMetricsComputationInRange("NamespacePod", timeRange) + `
 				}
 				` + getQueryForAggregatingChildMetrics("Namespace", "NamespacePod") + `
 			}
@@ -200,35 +276,41 @@ func getMetricsQueryForLogicalResources() string {
 		}`
 }
 
-// PhysicalResourcesMetrics query
-func getMetricsQueryForPhysicalResources() string {
+// PhysicalResourcesMetrics query, nodes and volumes existing in the time range are considered if it is given
+func getMetricsQueryForPhysicalResources(os string, timeRange TimeRange) string {
+	resourceFilter := `(has(isNode) OR has(isPersistentVolume))`
+	if os != "" {
+		// persistent volumes are not bound to an os, so only nodes are retrieved
+		resourceFilter = `has(isNode)` + getOSFilter(os)
+	}
 	return `query {
-			children(func: has(name)) @filter((has(isNode) OR has(isPersistentVolume)) AND (NOT has(endTime))) {
+			children(func: has(name)) @filter(` + resourceFilter + ` AND ` + getLiveFilterInRange(timeRange) + `) {
 				name
 			type
 			cpu: cpu as cpuCapacity
 			memory: memory as memoryCapacity
 			storage: storage as storageCapacity
-			` + getQueryForTimeComputation("") + `
+			` + getQueryForTimeComputationInRange("", timeRange) + `
 			` + getQueryForCostWithPriceWithAlias("") + `
 			}
 		}`
 }
 
-// LogicalResourcesHierarchy query
-func getHierarchyQueryForLogicalResource() string {
+// LogicalResourcesHierarchy query, only namespaces existing at asOf are retrieved if it is given
+// and deleted namespaces are included, excluded or only retrieved as given by deleted
+func getHierarchyQueryForLogicalResource(asOf, deleted string) string {
 	return `query {
-			children(func: has(isNamespace)) {
+			children(func: has(isNamespace)) @filter(has(isNamespace)` + getAsOfFilter(asOf) + getDeletedFilter(deleted) + `) {
 				name
 				type
 			}
 		}`
 }
 
-// PhysicalResourcesHierarchy query
-func getHierarchyQueryForPhysicalResource() string {
+// PhysicalResourcesHierarchy query, only nodes and volumes existing at asOf are retrieved if it is given
+func getHierarchyQueryForPhysicalResource(asOf string) string {
 	return `query {
-			children(func: has(name)) @filter(has(isNode) OR has(isPersistentVolume)) {
+			children(func: has(name)) @filter((has(isNode) OR has(isPersistentVolume))` + getAsOfFilter(asOf) + `) {
 				name
 				type
 			}
@@ -266,6 +348,10 @@ func getQueryForAllGroupsData() string {
 			lastLastMonthMemoryCost
 			lastLastMonthStorageCost
 			lastLastMonthCost
+			mtdCarbon
+			projectedCarbon
+			lastMonthCarbon
+			lastLastMonthCarbon
 		}
 	}`
 }
@@ -312,19 +398,26 @@ func getQueryForGroupMetrics(podsUIDs string) string {
 			pricePerMemory as memoryPrice
 			podCpuCost as math(mtdPodCPU * pricePerCPU)
 			podMemoryCost as math(mtdPodMemory * pricePerMemory)
-			podStorageCost as math(mtdPvcStorage * ` + models.DefaultStorageCostPerGBPerHour + `)
+			podStorageCost as math(mtdPvcStorage * ` + formatPrice(models.DefaultStorageCostInFloat64) + `)
 			podLiveCPUCostPerHour as math(pitPodCPU * pricePerCPU)
 			podLiveMemoryCostPerHour as math(pitPodMemory * pricePerMemory)
-			podLiveStorageCostPerHour as math(pitPvcStorage * ` + models.DefaultStorageCostPerGBPerHour + `)
+			podLiveStorageCostPerHour as math(pitPvcStorage * ` + formatPrice(models.DefaultStorageCostInFloat64) + `)
 			podCPUCostPerHour as math(podCpu * pricePerCPU)
 			podMemoryCostPerHour as math(podMemory * pricePerMemory)
-			podStorageCostPerHour as math(pvcStorage * ` + models.DefaultStorageCostPerGBPerHour + `)
+			podStorageCostPerHour as math(pvcStorage * ` + formatPrice(models.DefaultStorageCostInFloat64) + `)
 			podCPUCostLastMonth as math(podCPUCostPerHour * lastMonthTrueDurationInHours)
 			podMemoryCostLastMonth as math(podMemoryCostPerHour * lastMonthTrueDurationInHours)
 			podStorageCostLastMonth as math(podStorageCostPerHour * lastMonthTrueDurationInHours)
 			podCPUCostLastLastMonth as math(podCPUCostPerHour * lastLastMonthTrueDurationInHours)
 			podMemoryCostLastLastMonth as math(podMemoryCostPerHour * lastLastMonthTrueDurationInHours)
 			podStorageCostLastLastMonth as math(podStorageCostPerHour * lastLastMonthTrueDurationInHours)
+			carbonPerCPU as cpuCarbon
+			carbonPerMemory as memoryCarbon
+			podCarbon as math(mtdPodCPU * carbonPerCPU + mtdPodMemory * carbonPerMemory)
+			podLiveCarbonPerHour as math(pitPodCPU * carbonPerCPU + pitPodMemory * carbonPerMemory)
+			podCarbonPerHour as math(podCpu * carbonPerCPU + podMemory * carbonPerMemory)
+			podCarbonLastMonth as math(podCarbonPerHour * lastMonthTrueDurationInHours)
+			podCarbonLastLastMonth as math(podCarbonPerHour * lastLastMonthTrueDurationInHours)
 		}
 		
 		group() {
@@ -350,6 +443,10 @@ func getQueryForGroupMetrics(podsUIDs string) string {
 			lastLastMonthCPUCost: sum(val(podCPUCostLastLastMonth))
 			lastLastMonthMemoryCost: sum(val(podMemoryCostLastLastMonth))
 			lastLastMonthStorageCost: sum(val(podStorageCostLastLastMonth))
+			carbon: sum(val(podCarbon))
+			carbonPerHour: sum(val(podLiveCarbonPerHour))
+			lastMonthCarbon: sum(val(podCarbonLastMonth))
+			lastLastMonthCarbon: sum(val(podCarbonLastLastMonth))
 			livePods: sum(val(isAlive))
 		}
 	}`
@@ -367,14 +464,16 @@ func getQueryForSubscribersRetrieval() string {
 	}`
 }
 
-func getAllLivePodsQuery() string {
-	return `query {
-		pods(func: has(isPod)) @filter(NOT has(endTime)) {
+func getLivePodsQuery(namespace string) (string, qb.Vars) {
+	vars := getNamespaceVars(nil, namespace)
+	return vars.Declaration() + ` {
+		` + getNamespaceVar(namespace) + `
+		pods(func: has(isPod)) @filter(NOT has(endTime)` + getNamespaceFilter(namespace) + `) {
 			uid
 			xid
 			name
 		}
-	}`
+	}`, vars
 }
 
 func getQueryForPodsWithLabelFilter(labelFilter string) string {
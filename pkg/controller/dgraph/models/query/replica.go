@@ -0,0 +1,225 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	qb "github.com/vmware/purser/pkg/querybuilder"
+)
+
+// Reasons of a replica being an outlier among replicas of its workload
+const (
+	// HighCostReason is given if month to date cost of the replica is much higher than that of a typical replica
+	HighCostReason = "highCost"
+	// ExpensiveNodeReason is given if hourly cost of the replica is much higher, ex: it runs on a pricier node
+	ExpensiveNodeReason = "expensiveNode"
+	// LongRunningReason is given if the replica ran much longer this month, ex: it is not replaced on rollouts
+	LongRunningReason = "longRunning"
+)
+
+// outlierRatio is how many times the median a value of a replica has to be for the replica to be flagged
+const outlierRatio = 1.5
+
+// ReplicaCost is the month to date cost of a pod of a workload, ratios are relative to the median of the
+// replicas of the workload
+type ReplicaCost struct {
+	Name            string   `json:"name"`
+	Node            string   `json:"node,omitempty"`
+	StartTime       string   `json:"startTime,omitempty"`
+	EndTime         string   `json:"endTime,omitempty"`
+	Hours           float64  `json:"hours"`
+	Cost            float64  `json:"cost"`
+	HourlyCost      float64  `json:"hourlyCost"`
+	CostRatio       float64  `json:"costRatio"`
+	HourlyCostRatio float64  `json:"hourlyCostRatio"`
+	HoursRatio      float64  `json:"hoursRatio"`
+	Outlier         bool     `json:"outlier"`
+	Reasons         []string `json:"reasons,omitempty"`
+}
+
+// ReplicaCostVariance compares the costs of pods of a workload, replicas whose cost, hourly cost or hours are
+// at least 1.5 times the median are outliers. ExcessCost is the cost of outliers above the median cost.
+type ReplicaCostVariance struct {
+	Name             string        `json:"name"`
+	Type             string        `json:"type"`
+	MedianCost       float64       `json:"medianCost"`
+	MedianHourlyCost float64       `json:"medianHourlyCost"`
+	MedianHours      float64       `json:"medianHours"`
+	Outliers         int           `json:"outliers"`
+	ExcessCost       float64       `json:"excessCost"`
+	Replicas         []ReplicaCost `json:"replicas"`
+}
+
+// ReplicaCostVarianceWrapper structure
+type ReplicaCostVarianceWrapper struct {
+	Data ReplicaCostVariance `json:"data"`
+}
+
+type replicaPod struct {
+	Name                 string  `json:"name"`
+	StartTime            string  `json:"startTime"`
+	EndTime              string  `json:"endTime"`
+	Hours                float64 `json:"hours"`
+	CPUCost              float64 `json:"cpuCost"`
+	MemoryCost           float64 `json:"memoryCost"`
+	StorageCost          float64 `json:"storageCost"`
+	GPUCost              float64 `json:"gpuCost"`
+	ExtendedResourceCost float64 `json:"extendedResourceCost"`
+	BandwidthCost        float64 `json:"bandwidthCost"`
+	Node                 *struct {
+		Name string `json:"name"`
+	} `json:"node"`
+}
+
+// RetrieveReplicaCostVariance returns month to date cost of each pod of the workload, most expensive first,
+// and flags pods costing much more than the others.
+func RetrieveReplicaCostVariance(ctx context.Context, name string) ReplicaCostVarianceWrapper {
+	workloadType := strings.SplitN(name, "-", 2)[0]
+	if _, isWorkload := workloadChecks[workloadType]; !isWorkload {
+		log.Errorf("unable to retrieve replica costs, %s is not a workload", name)
+		return ReplicaCostVarianceWrapper{}
+	}
+	root := struct {
+		Workload []struct {
+			Pods []replicaPod `json:"pods"`
+		} `json:"workload"`
+	}{}
+	query, vars := getQueryForReplicaCosts(name, workloadType)
+	err := executeQueryWithVars(ctx, query, vars, &root)
+	if err != nil || len(root.Workload) == 0 {
+		log.Errorf("unable to retrieve pods of workload: %s, err: %v", name, err)
+		return ReplicaCostVarianceWrapper{}
+	}
+
+	var replicas []ReplicaCost
+	for _, pod := range root.Workload[0].Pods {
+		replicas = append(replicas, getReplicaCost(pod))
+	}
+	data := computeReplicaCostVariance(replicas)
+	data.Name = name
+	data.Type = workloadType
+	return ReplicaCostVarianceWrapper{Data: data}
+}
+
+func getQueryForReplicaCosts(name, workloadType string) (string, qb.Vars) {
+	vars := qb.Vars{"$name": name}
+	return vars.Declaration() + ` {
+		workload(func: has(` + workloadChecks[workloadType] + `)) @filter(eq(name, $name)) {
+			pods: ~` + workloadType + ` @filter(has(isPod)) {
+				` + getQueryForMetricsComputationWithAlias("Replica") + `
+				hours: val(durationInHoursReplica)
+				node {
+					name
+				}
+			}
+		}
+	}`, vars
+}
+
+// getReplicaCost returns adjusted cost of the pod
+func getReplicaCost(pod replicaPod) ReplicaCost {
+	cost := adjustCost(CostContext{PodType, pod.Name, CPUCostType}, pod.CPUCost) +
+		adjustCost(CostContext{PodType, pod.Name, MemoryCostType}, pod.MemoryCost) +
+		adjustCost(CostContext{PodType, pod.Name, StorageCostType}, pod.StorageCost) +
+		adjustCost(CostContext{PodType, pod.Name, GPUCostType}, pod.GPUCost) +
+		adjustCost(CostContext{PodType, pod.Name, ExtendedResourceCostType}, pod.ExtendedResourceCost) +
+		adjustCost(CostContext{PodType, pod.Name, BandwidthCostType}, pod.BandwidthCost)
+	replica := ReplicaCost{
+		Name:      pod.Name,
+		StartTime: pod.StartTime,
+		EndTime:   pod.EndTime,
+		Hours:     pod.Hours,
+		Cost:      cost,
+	}
+	if pod.Node != nil {
+		replica.Node = pod.Node.Name
+	}
+	if pod.Hours > 0 {
+		replica.HourlyCost = cost / pod.Hours
+	}
+	return replica
+}
+
+// computeReplicaCostVariance compares each replica with the median replica, a workload needs at least
+// two replicas for any of them to be an outlier
+func computeReplicaCostVariance(replicas []ReplicaCost) ReplicaCostVariance {
+	variance := ReplicaCostVariance{Replicas: []ReplicaCost{}}
+	var costs, hourlyCosts, hours []float64
+	for _, replica := range replicas {
+		costs = append(costs, replica.Cost)
+		hourlyCosts = append(hourlyCosts, replica.HourlyCost)
+		hours = append(hours, replica.Hours)
+	}
+	variance.MedianCost = median(costs)
+	variance.MedianHourlyCost = median(hourlyCosts)
+	variance.MedianHours = median(hours)
+
+	for _, replica := range replicas {
+		replica.CostRatio = ratio(replica.Cost, variance.MedianCost)
+		replica.HourlyCostRatio = ratio(replica.HourlyCost, variance.MedianHourlyCost)
+		replica.HoursRatio = ratio(replica.Hours, variance.MedianHours)
+		if len(replicas) > 1 {
+			if replica.CostRatio >= outlierRatio {
+				replica.Reasons = append(replica.Reasons, HighCostReason)
+			}
+			if replica.HourlyCostRatio >= outlierRatio {
+				replica.Reasons = append(replica.Reasons, ExpensiveNodeReason)
+			}
+			if replica.HoursRatio >= outlierRatio {
+				replica.Reasons = append(replica.Reasons, LongRunningReason)
+			}
+		}
+		if len(replica.Reasons) > 0 {
+			replica.Outlier = true
+			variance.Outliers++
+			if replica.Cost > variance.MedianCost {
+				variance.ExcessCost += replica.Cost - variance.MedianCost
+			}
+		}
+		variance.Replicas = append(variance.Replicas, replica)
+	}
+	sort.SliceStable(variance.Replicas, func(i, j int) bool {
+		return variance.Replicas[i].Cost > variance.Replicas[j].Cost
+	})
+	return variance
+}
+
+// median returns the median of values, 0 if there are none
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]float64{}, values...)
+	sort.Float64s(sorted)
+	middle := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[middle-1] + sorted[middle]) / 2
+	}
+	return sorted[middle]
+}
+
+// ratio returns value / base, 0 if base isn't positive
+func ratio(value, base float64) float64 {
+	if base <= 0 {
+		return 0
+	}
+	return value / base
+}
@@ -0,0 +1,95 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func mockDgraphForReplicaCosts() {
+	executeQueryWithVars = func(ctx context.Context, query string, vars map[string]string, root interface{}) error {
+		if !strings.Contains(query, `workload(func: has(isDeployment)) @filter(eq(name, $name))`) || vars["$name"] != "deployment-web" {
+			return json.Unmarshal([]byte(`{"workload": []}`), root)
+		}
+		return json.Unmarshal([]byte(`{"workload": [{"pods": [
+			{"name": "pod-web-1", "startTime": "2018-10-01T00:00:00Z", "hours": 10, "cpuCost": 1, "memoryCost": 1, "node": {"name": "node-small-1"}},
+			{"name": "pod-web-2", "startTime": "2018-10-01T00:00:00Z", "hours": 10, "cpuCost": 1.5, "memoryCost": 0.5, "node": {"name": "node-small-2"}},
+			{"name": "pod-web-3", "startTime": "2018-10-01T00:00:00Z", "hours": 10, "cpuCost": 4, "memoryCost": 2, "node": {"name": "node-large-1"}},
+			{"name": "pod-web-4", "startTime": "2018-09-01T00:00:00Z", "endTime": "2018-10-11T00:00:00Z", "hours": 40, "cpuCost": 4, "memoryCost": 4}
+		]}]}`), root)
+	}
+}
+
+// TestRetrieveReplicaCostVariance ...
+func TestRetrieveReplicaCostVariance(t *testing.T) {
+	mockDgraphForReplicaCosts()
+	got := RetrieveReplicaCostVariance(context.Background(), "deployment-web").Data
+	assert.Equal(t, "deployment-web", got.Name)
+	assert.Equal(t, DeploymentType, got.Type)
+	assert.Equal(t, 4.0, got.MedianCost)
+	assert.Equal(t, 0.2, got.MedianHourlyCost)
+	assert.Equal(t, 10.0, got.MedianHours)
+	assert.Equal(t, 2, got.Outliers)
+	assert.Equal(t, 6.0, got.ExcessCost)
+
+	assert.Equal(t, 4, len(got.Replicas))
+	assert.Equal(t, ReplicaCost{
+		Name:            "pod-web-4",
+		StartTime:       "2018-09-01T00:00:00Z",
+		EndTime:         "2018-10-11T00:00:00Z",
+		Hours:           40,
+		Cost:            8,
+		HourlyCost:      0.2,
+		CostRatio:       2,
+		HourlyCostRatio: 1,
+		HoursRatio:      4,
+		Outlier:         true,
+		Reasons:         []string{HighCostReason, LongRunningReason},
+	}, got.Replicas[0])
+	assert.Equal(t, "pod-web-3", got.Replicas[1].Name)
+	assert.Equal(t, "node-large-1", got.Replicas[1].Node)
+	assert.Equal(t, []string{HighCostReason, ExpensiveNodeReason}, got.Replicas[1].Reasons)
+	assert.False(t, got.Replicas[2].Outlier)
+	assert.False(t, got.Replicas[3].Outlier)
+
+	assert.Equal(t, ReplicaCostVarianceWrapper{}, RetrieveReplicaCostVariance(context.Background(), "deployment-api"))
+	assert.Equal(t, ReplicaCostVarianceWrapper{}, RetrieveReplicaCostVariance(context.Background(), "pod-web-1"))
+}
+
+// TestComputeReplicaCostVarianceSingleReplica ...
+func TestComputeReplicaCostVarianceSingleReplica(t *testing.T) {
+	got := computeReplicaCostVariance([]ReplicaCost{{Name: "pod-web-1", Hours: 10, Cost: 2, HourlyCost: 0.2}})
+	assert.Equal(t, 0, got.Outliers)
+	assert.Equal(t, 1.0, got.Replicas[0].CostRatio)
+
+	got = computeReplicaCostVariance(nil)
+	assert.Equal(t, []ReplicaCost{}, got.Replicas)
+	assert.Equal(t, 0.0, got.MedianCost)
+}
+
+// TestMedian ...
+func TestMedian(t *testing.T) {
+	assert.Equal(t, 0.0, median(nil))
+	assert.Equal(t, 2.0, median([]float64{3, 1, 2}))
+	assert.Equal(t, 2.5, median([]float64{4, 1, 3, 2}))
+}
@@ -18,9 +18,8 @@
 package query
 
 import (
-	"strconv"
-
-	"github.com/Sirupsen/logrus"
+	"context"
+	qb "github.com/vmware/purser/pkg/querybuilder"
 )
 
 // Cluster resource constants
@@ -29,6 +28,9 @@ const (
 	ContainerType  = "container"
 	IsProcFilter   = "@filter(has(isProc))"
 
+	CronJobCheck = "isCronJob"
+	CronJobType  = "cronjob"
+
 	DaemonsetCheck = "isDaemonset"
 	DaemonsetType  = "daemonset"
 	IsPodFilter    = "@filter(has(isPod))"
@@ -37,12 +39,15 @@ const (
 	DeploymentType     = "deployment"
 	IsReplicasetFilter = "@filter(has(isReplicaset))"
 
+	DeploymentConfigCheck = "isDeploymentConfig"
+	DeploymentConfigType  = "deploymentconfig"
+
 	JobCheck = "isJob"
 	JobType  = "job"
 
 	NamespaceCheck       = "isNamespace"
 	NamespaceType        = "namespace"
-	NamespaceChildFilter = "@filter(has(isDeployment) OR has(isStatefulset) OR has(isJob) OR has(isDaemonset) OR (has(isReplicaset) AND (NOT has(deployment))))"
+	NamespaceChildFilter = "@filter(has(isDeployment) OR has(isStatefulset) OR has(isJob) OR has(isDaemonset) OR has(isDeploymentConfig) OR (has(isReplicaset) AND (NOT has(deployment))))"
 
 	NodeCheck = "isNode"
 	NodeType  = "node"
@@ -71,61 +76,150 @@ type Resource struct {
 	Type        string
 	Name        string
 	ChildFilter string
+	OS          string
+	AsOf        string
+	Start       string
+	End         string
+	Match       string
+	GroupBy     string
+	// Namespace restricts pods, containers and workloads(ex: deployments) to those of the namespace(ex:
+	// namespace-default), their names are unique only within their namespace
+	Namespace string
+	// Page pages children of the resource in its hierarchy
+	Page Page
+	// CostMode is the cost reported as cpu and memory cost, cost of requests(default) or cost of the usage of pods
+	CostMode string
+}
+
+// TimeRange is the interval between Start and End(RFC3339) over which costs are computed, empty Start means
+// start of the month of End and empty End means now
+type TimeRange struct {
+	Start string
+	End   string
 }
 
 // RetrieveResourceHierarchy returns hierarchy for a given resource
-func (r *Resource) RetrieveResourceHierarchy() JSONDataWrapper {
+func (r *Resource) RetrieveResourceHierarchy(ctx context.Context) JSONDataWrapper {
 	if r.Name == All {
-		logrus.Errorf("wrong type of query, empty name is given")
+		log.Errorf("wrong type of query, empty name is given")
+		return JSONDataWrapper{}
+	}
+	if !r.resolveName(ctx) {
 		return JSONDataWrapper{}
 	}
-	query := r.getQueryForHierarchy()
-	return getJSONDataFromQuery(query)
+	query, vars := r.getQueryForHierarchy()
+	root := getJSONDataFromQuery(ctx, query, vars)
+	lastUID := ""
+	if children := root.Data.Children; len(children) > 0 {
+		lastUID = children[len(children)-1].UID
+	}
+	root.Page = r.Page.getPageInfo(len(root.Data.Children), lastUID)
+	return root
 }
 
 // RetrieveResourceMetrics returns metrics for a given resource
-func (r *Resource) RetrieveResourceMetrics() JSONDataWrapper {
+func (r *Resource) RetrieveResourceMetrics(ctx context.Context) JSONDataWrapper {
 	if r.Name == All {
-		logrus.Errorf("wrong type of query, empty name is given")
+		log.Errorf("wrong type of query, empty name is given")
 		return JSONDataWrapper{}
 	}
-	query := r.getQueryForResourceMetrics()
-	return getJSONDataFromQuery(query)
+	if !r.resolveName(ctx) {
+		return JSONDataWrapper{}
+	}
+	query, vars := r.getQueryForResourceMetrics(ctx)
+	root := getJSONDataFromQuery(ctx, query, vars)
+	if r.Type == NamespaceType && r.GroupBy == Kind {
+		r.groupNamespaceChildren(ctx, &root.Data)
+	}
+	if r.CostMode == Usage {
+		useUtilizedCosts(&root.Data)
+	}
+	return root
+}
+
+// useUtilizedCosts replaces cpu and memory costs of the resource and its children with the costs of their usage,
+// resources without usage samples have no utilized costs
+func useUtilizedCosts(parent *ParentWrapper) {
+	parent.CPUCost = parent.UtilizedCPUCost
+	parent.MemoryCost = parent.UtilizedMemoryCost
+	for index := range parent.Children {
+		child := &parent.Children[index]
+		child.CPUCost = child.UtilizedCPUCost
+		child.MemoryCost = child.UtilizedMemoryCost
+	}
+	for index := range parent.Groups {
+		group := &parent.Groups[index]
+		group.CPUCost = group.UtilizedCPUCost
+		group.MemoryCost = group.UtilizedMemoryCost
+		for workloadIndex := range group.Workloads {
+			workload := &group.Workloads[workloadIndex]
+			workload.CPUCost = workload.UtilizedCPUCost
+			workload.MemoryCost = workload.UtilizedMemoryCost
+		}
+	}
+}
+
+// resolveName replaces the name with the stored name which best matches it if a non exact match is asked
+func (r *Resource) resolveName(ctx context.Context) bool {
+	name, err := ResolveName(ctx, r.Check, r.Type, r.Name, r.Match)
+	if err != nil {
+		log.Errorf("unable to resolve %s name: %s, err: %v", r.Type, r.Name, err)
+		return false
+	}
+	r.Name = name
+	return true
+}
+
+// getTimeRange returns the time range over which costs of the resource are computed, it ends at asOf if no end is given
+func (r *Resource) getTimeRange() TimeRange {
+	end := r.End
+	if end == "" {
+		end = r.AsOf
+	}
+	return TimeRange{Start: r.Start, End: end}
 }
 
-func (r *Resource) getQueryForResourceMetrics() string {
+func (r *Resource) getQueryForResourceMetrics(ctx context.Context) (string, qb.Vars) {
+	timeRange := r.getTimeRange()
 	switch r.Type {
 	case DeploymentType:
-		return getQueryForDeploymentMetrics(r.Name)
+		return getQueryForDeploymentMetrics(r.Name, r.Namespace, timeRange)
+	case CronJobType:
+		return getQueryForCronJobMetrics(r.Name, r.Namespace, timeRange)
 	case NamespaceType:
-		return getQueryForNamespaceMetrics(r.Name)
+		return getQueryForNamespaceMetrics(r.Name, r.OS, timeRange)
 	case NodeType:
-		return getQueryForNodeMetrics(r.Name)
+		return getQueryForNodeMetrics(r.Name, timeRange)
 	case PVType:
-		return getQueryForPVMetrics(r.Name)
+		return getQueryForPVMetrics(r.Name, timeRange)
 	case PVCType:
-		return getQueryForPVCMetrics(r.Name)
+		return getQueryForPVCMetrics(r.Name, timeRange)
 	case ContainerType:
-		return getQueryForContainerMetrics(r.Name)
+		return getQueryForContainerMetrics(r.Name, r.Namespace, timeRange)
 	case PodType:
-		cpuPriceInFloat64, memoryPriceInFloat64 := getPricePerResourceForPod(r.Name)
-		cpuPrice := strconv.FormatFloat(cpuPriceInFloat64, 'f', 11, 64)
-		memoryPrice := strconv.FormatFloat(memoryPriceInFloat64, 'f', 11, 64)
-		return getQueryForPodMetrics(r.Name, cpuPrice, memoryPrice)
+		cpuPriceInFloat64, memoryPriceInFloat64 := getPricePerResourceForPod(ctx, r.Name, r.Namespace)
+		cpuPrice := formatPrice(cpuPriceInFloat64)
+		memoryPrice := formatPrice(memoryPriceInFloat64)
+		ephemeralStoragePriceInFloat64, hugepagesPriceInFloat64 := getPricePerLocalResourceForPod(ctx, r.Name, r.Namespace, memoryPriceInFloat64)
+		ephemeralStoragePrice := formatPrice(ephemeralStoragePriceInFloat64)
+		hugepagesPrice := formatPrice(hugepagesPriceInFloat64)
+		gpuPrice := formatPrice(getPricePerGPUForPod(ctx, r.Name, r.Namespace))
+		return getQueryForPodMetrics(r.Name, r.Namespace, timeRange, cpuPrice, memoryPrice, ephemeralStoragePrice, hugepagesPrice, gpuPrice)
 	}
 	return r.getQueryForPodParentMetrics()
 }
 
-// getJSONDataFromQuery executes query and wraps the data in a desired structure(JSONDataWrapper)
-func getJSONDataFromQuery(query string) JSONDataWrapper {
+// getJSONDataFromQuery executes query with the variables and wraps the data in a desired structure(JSONDataWrapper)
+func getJSONDataFromQuery(ctx context.Context, query string, vars qb.Vars) JSONDataWrapper {
 	parentRoot := ParentWrapper{}
-	err := executeQuery(query, &parentRoot)
+	err := executeQueryWithVars(ctx, query, vars, &parentRoot)
 	if err != nil || len(parentRoot.Parent) == 0 {
-		logrus.Errorf("Unable to execute query, err: (%v)", err)
+		log.Errorf("Unable to execute query, err: (%v)", err)
 		return JSONDataWrapper{}
 	}
 	root := JSONDataWrapper{
 		Data: parentRoot.Parent[0],
 	}
+	adjustCosts(&root.Data)
 	return root
 }
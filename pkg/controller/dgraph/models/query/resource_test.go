@@ -18,7 +18,9 @@
 package query
 
 import (
+	"context"
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/vmware/purser/pkg/controller/dgraph/models"
@@ -27,15 +29,21 @@ import (
 )
 
 func mockDgraphForResourceQueries(queryType, resourceName, resourceType string) {
-	executeQuery = func(query string, root interface{}) error {
+	executeQueryWithVars = func(ctx context.Context, query string, vars map[string]string, root interface{}) error {
+		if vars["$name"] != resourceName {
+			return fmt.Errorf("wrong name received: %s", vars["$name"])
+		}
 		if queryType == testPodPrices {
 			newRoot, ok := root.(*podRoot)
 			if !ok {
 				return fmt.Errorf("wrong pod root received")
 			}
 			pod := models.Pod{
-				CPUPrice:    testCPUPrice,
-				MemoryPrice: testMemoryPrice,
+				CPUPrice:              testCPUPrice,
+				MemoryPrice:           testMemoryPrice,
+				EphemeralStoragePrice: testEphemeralStoragePrice,
+				HugepagesPrice:        testHugepagesPrice,
+				GPUPrice:              testGPUPrice,
 			}
 			newRoot.Pods = []models.Pod{pod}
 			return nil
@@ -110,7 +118,7 @@ func TestRetrieveResourceHierarchyWithNameEmpty(t *testing.T) {
 		Name:        "",
 		ChildFilter: IsPodFilter,
 	}
-	got := input.RetrieveResourceHierarchy()
+	got := input.RetrieveResourceHierarchy(context.Background())
 	expected := JSONDataWrapper{}
 	assert.Equal(t, expected, got)
 }
@@ -125,7 +133,7 @@ func TestRetrieveResourceHierarchy(t *testing.T) {
 		Name:        testDaemonsetName,
 		ChildFilter: IsPodFilter,
 	}
-	got := input.RetrieveResourceHierarchy()
+	got := input.RetrieveResourceHierarchy(context.Background())
 
 	firstPod := Children{
 		Name: "pod-purser-1",
@@ -155,11 +163,39 @@ func TestRetrieveResourceHierarchyWithDgraphError(t *testing.T) {
 		Name:        testDaemonsetName,
 		ChildFilter: IsPodFilter,
 	}
-	got := input.RetrieveResourceHierarchy()
+	got := input.RetrieveResourceHierarchy(context.Background())
 	expected := JSONDataWrapper{}
 	assert.Equal(t, expected, got)
 }
 
+// TestRetrieveResourceHierarchyOfOtherNamespace ...
+func TestRetrieveResourceHierarchyOfOtherNamespace(t *testing.T) {
+	executeQueryWithVars = func(ctx context.Context, query string, vars map[string]string, root interface{}) error {
+		if !strings.Contains(query, "@filter(eq(name, $name) AND uid(namespaceResources))") {
+			return fmt.Errorf("namespace filter missing: %s", query)
+		}
+		// pod-purser-1 is in namespace-prod, so a query scoped to another namespace matches nothing
+		if vars["$namespace"] != "namespace-prod" {
+			return nil
+		}
+		root.(*ParentWrapper).Parent = []ParentWrapper{{Name: vars["$name"], Type: PodType}}
+		return nil
+	}
+
+	input := &Resource{
+		Check:       PodCheck,
+		Type:        PodType,
+		Name:        "pod-purser-1",
+		ChildFilter: IsContainerFilter,
+		Namespace:   "namespace-dev",
+	}
+	assert.Equal(t, JSONDataWrapper{}, input.RetrieveResourceHierarchy(context.Background()))
+
+	input.Namespace = "namespace-prod"
+	expected := JSONDataWrapper{Data: ParentWrapper{Name: "pod-purser-1", Type: PodType}}
+	assert.Equal(t, expected, input.RetrieveResourceHierarchy(context.Background()))
+}
+
 // TestRetrieveResourceMetricsWithNameEmpty ...
 func TestRetrieveResourceMetricsWithNameEmpty(t *testing.T) {
 	input := &Resource{
@@ -167,7 +203,7 @@ func TestRetrieveResourceMetricsWithNameEmpty(t *testing.T) {
 		Type:  DaemonsetType,
 		Name:  "",
 	}
-	got := input.RetrieveResourceMetrics()
+	got := input.RetrieveResourceMetrics(context.Background())
 	expected := JSONDataWrapper{}
 	assert.Equal(t, expected, got)
 }
@@ -181,7 +217,7 @@ func TestRetrieveDaemonsetMetrics(t *testing.T) {
 		Type:  DaemonsetType,
 		Name:  testDaemonsetName,
 	}
-	got := input.RetrieveResourceMetrics()
+	got := input.RetrieveResourceMetrics(context.Background())
 
 	expected := getExpectedTestMetrics(testDaemonsetName, DaemonsetType)
 	assert.Equal(t, expected, got)
@@ -196,7 +232,7 @@ func TestRetrieveDeploymentMetrics(t *testing.T) {
 		Type:  DeploymentType,
 		Name:  testResourceName,
 	}
-	got := input.RetrieveResourceMetrics()
+	got := input.RetrieveResourceMetrics(context.Background())
 
 	expected := getExpectedTestMetrics(testResourceName, DeploymentType)
 	assert.Equal(t, expected, got)
@@ -211,7 +247,7 @@ func TestRetrieveNamespacetMetrics(t *testing.T) {
 		Type:  NamespaceType,
 		Name:  testResourceName,
 	}
-	got := input.RetrieveResourceMetrics()
+	got := input.RetrieveResourceMetrics(context.Background())
 
 	expected := getExpectedTestMetrics(testResourceName, NamespaceType)
 	assert.Equal(t, expected, got)
@@ -226,7 +262,7 @@ func TestRetrievePVMetrics(t *testing.T) {
 		Type:  PVType,
 		Name:  testResourceName,
 	}
-	got := input.RetrieveResourceMetrics()
+	got := input.RetrieveResourceMetrics(context.Background())
 
 	expected := getExpectedTestMetrics(testResourceName, PVType)
 	assert.Equal(t, expected, got)
@@ -241,7 +277,7 @@ func TestRetrievePVCMetrics(t *testing.T) {
 		Type:  PVCType,
 		Name:  testResourceName,
 	}
-	got := input.RetrieveResourceMetrics()
+	got := input.RetrieveResourceMetrics(context.Background())
 
 	expected := getExpectedTestMetrics(testResourceName, PVCType)
 	assert.Equal(t, expected, got)
@@ -256,7 +292,7 @@ func TestRetrieveContainerMetrics(t *testing.T) {
 		Type:  ContainerType,
 		Name:  testResourceName,
 	}
-	got := input.RetrieveResourceMetrics()
+	got := input.RetrieveResourceMetrics(context.Background())
 
 	expected := getExpectedTestMetrics(testResourceName, ContainerType)
 	assert.Equal(t, expected, got)
@@ -271,12 +307,48 @@ func TestRetrieveNodeMetrics(t *testing.T) {
 		Type:  NodeType,
 		Name:  testResourceName,
 	}
-	got := input.RetrieveResourceMetrics()
+	got := input.RetrieveResourceMetrics(context.Background())
 
 	expected := getExpectedTestMetrics(testResourceName, NodeType)
 	assert.Equal(t, expected, got)
 }
 
+// TestRetrieveNodeMetricsWithUsageCostMode ...
+func TestRetrieveNodeMetricsWithUsageCostMode(t *testing.T) {
+	mockDgraphForResourceQueries(testMetrics, testResourceName, NodeType)
+
+	input := &Resource{
+		Check:    NodeCheck,
+		Type:     NodeType,
+		Name:     testResourceName,
+		CostMode: Usage,
+	}
+	got := input.RetrieveResourceMetrics(context.Background())
+
+	assert.Equal(t, 0.0, got.Data.CPUCost)
+	assert.Equal(t, 0.0, got.Data.Children[0].MemoryCost)
+	assert.Equal(t, 0.1, got.Data.Children[0].StorageCost)
+}
+
+// TestUseUtilizedCosts ...
+func TestUseUtilizedCosts(t *testing.T) {
+	parent := ParentWrapper{
+		CPUCost:            2,
+		MemoryCost:         1,
+		UtilizedCPUCost:    0.5,
+		UtilizedMemoryCost: 0.25,
+		Children:           []Children{{CPUCost: 2, MemoryCost: 1, StorageCost: 3, UtilizedCPUCost: 0.5, UtilizedMemoryCost: 0.25}},
+		Groups:             []WorkloadGroup{{Children: Children{CPUCost: 4, UtilizedCPUCost: 1}, Workloads: []Children{{CPUCost: 4, UtilizedCPUCost: 1}}}},
+	}
+	useUtilizedCosts(&parent)
+
+	assert.Equal(t, 0.5, parent.CPUCost)
+	assert.Equal(t, 0.25, parent.MemoryCost)
+	assert.Equal(t, Children{CPUCost: 0.5, MemoryCost: 0.25, StorageCost: 3, UtilizedCPUCost: 0.5, UtilizedMemoryCost: 0.25}, parent.Children[0])
+	assert.Equal(t, 1.0, parent.Groups[0].CPUCost)
+	assert.Equal(t, 1.0, parent.Groups[0].Workloads[0].CPUCost)
+}
+
 // TestRetrievePodMetrics ...
 func TestRetrievePodMetrics(t *testing.T) {
 	mockDgraphForResourceQueries(testMetrics, testPodName, PodType)
@@ -286,7 +358,7 @@ func TestRetrievePodMetrics(t *testing.T) {
 		Type:  PodType,
 		Name:  testPodName,
 	}
-	got := input.RetrieveResourceMetrics()
+	got := input.RetrieveResourceMetrics(context.Background())
 
 	expected := getExpectedTestMetrics(testPodName, PodType)
 	assert.Equal(t, expected, got)
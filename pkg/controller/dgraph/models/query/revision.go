@@ -0,0 +1,163 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	qb "github.com/vmware/purser/pkg/querybuilder"
+)
+
+// Revision constants
+const (
+	RevisionType    = "revision"
+	UnknownRevision = "unknown"
+)
+
+// RevisionCost is the cost of pods of a workload running a revision, FirstSeen and LastSeen are
+// start time of its first pod and end time of its last pod(empty if any of its pods is live)
+type RevisionCost struct {
+	Revision      string  `json:"revision"`
+	FirstSeen     string  `json:"firstSeen"`
+	LastSeen      string  `json:"lastSeen,omitempty"`
+	Pods          int     `json:"pods"`
+	CPUCost       float64 `json:"cpuCost"`
+	MemoryCost    float64 `json:"memoryCost"`
+	StorageCost   float64 `json:"storageCost"`
+	TotalCost     float64 `json:"totalCost"`
+	LastMonthCost float64 `json:"lastMonthCost"`
+}
+
+// RevisionCosts structure
+type RevisionCosts struct {
+	Name      string         `json:"name"`
+	Type      string         `json:"type"`
+	Revisions []RevisionCost `json:"revisions"`
+}
+
+// RevisionCostsWrapper structure
+type RevisionCostsWrapper struct {
+	Data RevisionCosts `json:"data"`
+}
+
+type revisionPod struct {
+	UID        string `json:"uid"`
+	Revision   string `json:"revision"`
+	StartTime  string `json:"startTime"`
+	EndTime    string `json:"endTime"`
+	Replicaset *struct {
+		Revision string `json:"revision"`
+	} `json:"replicaset"`
+}
+
+// RetrieveRevisionCosts returns cost of each revision of the workload, revision of a pod is its commit or version
+// annotation(or label), or the revision of its deployment's replicaset if it has none.
+func RetrieveRevisionCosts(ctx context.Context, name string) RevisionCostsWrapper {
+	workloadType := strings.SplitN(name, "-", 2)[0]
+	if _, isWorkload := workloadChecks[workloadType]; !isWorkload {
+		log.Errorf("unable to retrieve revision costs, %s is not a workload", name)
+		return RevisionCostsWrapper{}
+	}
+	pods, err := retrievePodsOfWorkload(ctx, name, workloadType)
+	if err != nil {
+		log.Errorf("unable to retrieve pods of workload: %s, err: %v", name, err)
+		return RevisionCostsWrapper{}
+	}
+
+	revisions := make(map[string]*RevisionCost)
+	podsOfRevisions := make(map[string][]string)
+	for _, pod := range pods {
+		revision := getRevisionOfPod(pod)
+		if _, isPresent := revisions[revision]; !isPresent {
+			revisions[revision] = &RevisionCost{Revision: revision, FirstSeen: pod.StartTime, LastSeen: pod.EndTime}
+		}
+		updateRevisionTimes(revisions[revision], pod)
+		revisions[revision].Pods++
+		podsOfRevisions[revision] = append(podsOfRevisions[revision], pod.UID)
+	}
+
+	data := RevisionCosts{Name: name, Type: workloadType, Revisions: []RevisionCost{}}
+	for revision, revisionCost := range revisions {
+		metrics, err := retrieveMetricsOfPods(ctx, strings.Join(podsOfRevisions[revision], ", "), RevisionType)
+		if err != nil {
+			log.Errorf("unable to retrieve metrics of revision: %s, err: %v", revision, err)
+			continue
+		}
+		revisionCost.CPUCost = metrics.CostCPU
+		revisionCost.MemoryCost = metrics.CostMemory
+		revisionCost.StorageCost = metrics.CostStorage
+		revisionCost.TotalCost = metrics.CostCPU + metrics.CostMemory + metrics.CostStorage
+		revisionCost.LastMonthCost = metrics.LastMonthCPUCost + metrics.LastMonthMemoryCost + metrics.LastMonthStorageCost
+		data.Revisions = append(data.Revisions, *revisionCost)
+	}
+	sort.Slice(data.Revisions, func(i, j int) bool {
+		return data.Revisions[i].FirstSeen < data.Revisions[j].FirstSeen
+	})
+	return RevisionCostsWrapper{Data: data}
+}
+
+func getRevisionOfPod(pod revisionPod) string {
+	if pod.Revision != "" {
+		return pod.Revision
+	}
+	if pod.Replicaset != nil && pod.Replicaset.Revision != "" {
+		return pod.Replicaset.Revision
+	}
+	return UnknownRevision
+}
+
+// updateRevisionTimes widens first and last seen times of the revision to include the pod
+func updateRevisionTimes(revision *RevisionCost, pod revisionPod) {
+	if pod.StartTime < revision.FirstSeen {
+		revision.FirstSeen = pod.StartTime
+	}
+	if revision.LastSeen != "" && (pod.EndTime == "" || pod.EndTime > revision.LastSeen) {
+		revision.LastSeen = pod.EndTime
+	}
+}
+
+func retrievePodsOfWorkload(ctx context.Context, name, workloadType string) ([]revisionPod, error) {
+	type root struct {
+		Workload []struct {
+			Pods []revisionPod `json:"pods"`
+		} `json:"workload"`
+	}
+	newRoot := root{}
+	query := getQueryForPodsOfWorkload(name, workloadType)
+	err := executeQueryWithVars(ctx, query.String(), query.Vars(), &newRoot)
+	if err != nil {
+		return nil, err
+	}
+	if len(newRoot.Workload) == 0 {
+		return nil, fmt.Errorf("workload %s is not found", name)
+	}
+	return newRoot.Workload[0].Pods, nil
+}
+
+func getQueryForPodsOfWorkload(name, workloadType string) *qb.Query {
+	return qb.New(
+		qb.Func("workload", qb.Has(workloadChecks[workloadType])).Filter(qb.EqParam("name", "$name")).Child(
+			qb.Edge("~"+workloadType).Alias("pods").Filter(qb.Has("isPod")).
+				Fields("uid", "revision", "startTime", "endTime").
+				Child(qb.Edge("replicaset").Fields("revision")),
+		),
+	).Param("$name", name)
+}
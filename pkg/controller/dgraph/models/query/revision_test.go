@@ -0,0 +1,63 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func mockDgraphForRevisions() {
+	executeQueryWithVars = func(ctx context.Context, query string, vars map[string]string, root interface{}) error {
+		if strings.Contains(query, "workload(func: has(isDeployment))") && vars["$name"] == "deployment-web" {
+			return json.Unmarshal([]byte(`{"workload": [{"pods": [
+				{"uid": "0x1", "startTime": "2018-10-01T00:00:00Z", "endTime": "2018-10-05T00:00:00Z", "replicaset": {"revision": "1"}},
+				{"uid": "0x2", "startTime": "2018-10-02T00:00:00Z", "endTime": "2018-10-06T00:00:00Z", "replicaset": {"revision": "1"}},
+				{"uid": "0x3", "revision": "9f3c2e1", "startTime": "2018-10-05T00:00:00Z", "replicaset": {"revision": "2"}}
+			]}]}`), root)
+		}
+		return json.Unmarshal([]byte(`{"workload": []}`), root)
+	}
+	executeQuery = func(ctx context.Context, query string, root interface{}) error {
+		if strings.Contains(query, "0x3") {
+			return json.Unmarshal([]byte(`{"group": [{"cpuCost": 3}, {"memoryCost": 1}]}`), root)
+		}
+		return json.Unmarshal([]byte(`{"group": [{"cpuCost": 2}, {"lastMonthCPUCost": 4}]}`), root)
+	}
+}
+
+// TestRetrieveRevisionCosts ...
+func TestRetrieveRevisionCosts(t *testing.T) {
+	mockDgraphForRevisions()
+	got := RetrieveRevisionCosts(context.Background(), "deployment-web")
+	expected := RevisionCosts{
+		Name: "deployment-web",
+		Type: DeploymentType,
+		Revisions: []RevisionCost{
+			{Revision: "1", FirstSeen: "2018-10-01T00:00:00Z", LastSeen: "2018-10-06T00:00:00Z", Pods: 2, CPUCost: 2, TotalCost: 2, LastMonthCost: 4},
+			{Revision: "9f3c2e1", FirstSeen: "2018-10-05T00:00:00Z", Pods: 1, CPUCost: 3, MemoryCost: 1, TotalCost: 4},
+		},
+	}
+	assert.Equal(t, expected, got.Data)
+
+	assert.Equal(t, RevisionCostsWrapper{}, RetrieveRevisionCosts(context.Background(), "pod-web"))
+}
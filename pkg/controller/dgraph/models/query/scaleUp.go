@@ -0,0 +1,256 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/vmware/purser/pkg/controller/dgraph/models"
+	qb "github.com/vmware/purser/pkg/querybuilder"
+)
+
+// scaleUpCorrelationWindow is how long before a node is added a scale up has to be triggered to be its cause,
+// scale ups nominating the node are its cause irrespective of time
+const scaleUpCorrelationWindow = 15 * time.Minute
+
+// podOwnerPredicates are the edges linking pods to workloads, in the order of preference of the owner of a pod
+var podOwnerPredicates = []string{DeploymentConfigType, DeploymentType, StatefulsetType, DaemonsetType, JobType, ReplicasetType}
+
+// ScaleUpWorkloadCost is the cost of nodes added by scale ups which pods of the workload triggered,
+// cost of a node is split equally among the pods which triggered it
+type ScaleUpWorkloadCost struct {
+	Name          string  `json:"name"`
+	Type          string  `json:"type"`
+	Namespace     string  `json:"namespace"`
+	TriggeredPods int     `json:"triggeredPods"`
+	Nodes         int     `json:"nodes"`
+	Cost          float64 `json:"cost"`
+}
+
+// ScaleUpCosts is the cost of nodes added between start and end while they existed in the window and
+// its attribution to the workloads that triggered them. Workloads are sorted by cost.
+type ScaleUpCosts struct {
+	Start            string                `json:"start"`
+	End              string                `json:"end"`
+	NodesAdded       int                   `json:"nodesAdded"`
+	AttributedNodes  int                   `json:"attributedNodes"`
+	AddedCost        float64               `json:"addedCost"`
+	AttributedCost   float64               `json:"attributedCost"`
+	UnattributedCost float64               `json:"unattributedCost"`
+	Workloads        []ScaleUpWorkloadCost `json:"workloads"`
+}
+
+// ScaleUpCostsWrapper structure
+type ScaleUpCostsWrapper struct {
+	Data ScaleUpCosts `json:"data"`
+}
+
+type addedNode struct {
+	NodeName       string  `json:"nodeName"`
+	Event          string  `json:"event"`
+	StartTime      string  `json:"startTime"`
+	NodeStartTime  string  `json:"nodeStartTime"`
+	CPUCapacity    float64 `json:"cpuCapacity"`
+	MemoryCapacity float64 `json:"memoryCapacity"`
+	CPUPrice       float64 `json:"cpuPrice"`
+	MemoryPrice    float64 `json:"memoryPrice"`
+}
+
+type scaleUpTrigger struct {
+	Source    string          `json:"source"`
+	Target    string          `json:"target"`
+	StartTime string          `json:"startTime"`
+	Pod       []triggeringPod `json:"pod"`
+}
+
+type triggeringPod struct {
+	Name             string       `json:"name"`
+	Namespace        *resourceRef `json:"namespace"`
+	Deployment       *resourceRef `json:"deployment"`
+	Replicaset       *resourceRef `json:"replicaset"`
+	Statefulset      *resourceRef `json:"statefulset"`
+	Daemonset        *resourceRef `json:"daemonset"`
+	Job              *resourceRef `json:"job"`
+	DeploymentConfig *resourceRef `json:"deploymentconfig"`
+}
+
+type resourceRef struct {
+	Name string `json:"name"`
+}
+
+// RetrieveScaleUpCosts attributes cost of nodes added between start and end(RFC3339, last 30 days by default)
+// to the workloads whose pending pods triggered the scale ups which added them.
+func RetrieveScaleUpCosts(ctx context.Context, start, end string) ScaleUpCostsWrapper {
+	endTime := time.Now().UTC()
+	if end != "" {
+		parsed, err := time.Parse(time.RFC3339, end)
+		if err != nil {
+			log.Errorf("invalid end time: %s, err: %v", end, err)
+			return ScaleUpCostsWrapper{}
+		}
+		endTime = parsed
+	}
+	startTime := endTime.AddDate(0, 0, -defaultChurnDays)
+	if start != "" {
+		parsed, err := time.Parse(time.RFC3339, start)
+		if err != nil {
+			log.Errorf("invalid start time: %s, err: %v", start, err)
+			return ScaleUpCostsWrapper{}
+		}
+		startTime = parsed
+	}
+
+	root := struct {
+		Added    []addedNode      `json:"added"`
+		Removed  []addedNode      `json:"removed"`
+		ScaleUps []scaleUpTrigger `json:"scaleUps"`
+	}{}
+	err := executeQuery(ctx, getQueryForScaleUps(startTime, endTime), &root)
+	if err != nil {
+		log.Errorf("unable to retrieve scale ups, err: %v", err)
+		return ScaleUpCostsWrapper{}
+	}
+	return ScaleUpCostsWrapper{Data: attributeScaleUpCosts(root.Added, root.Removed, root.ScaleUps, startTime, endTime)}
+}
+
+func attributeScaleUpCosts(added, removed []addedNode, scaleUps []scaleUpTrigger, startTime, endTime time.Time) ScaleUpCosts {
+	costs := ScaleUpCosts{
+		Start:      startTime.Format(time.RFC3339),
+		End:        endTime.Format(time.RFC3339),
+		NodesAdded: len(added),
+		Workloads:  []ScaleUpWorkloadCost{},
+	}
+	removedAt := make(map[string]string)
+	for _, node := range removed {
+		removedAt[node.NodeName+node.NodeStartTime] = node.StartTime
+	}
+
+	workloads := make(map[string]*ScaleUpWorkloadCost)
+	for _, node := range added {
+		hours := getOverlapHours(node.StartTime, removedAt[node.NodeName+node.NodeStartTime], startTime, endTime)
+		cost := (node.CPUCapacity*node.CPUPrice + node.MemoryCapacity*node.MemoryPrice) * hours
+		costs.AddedCost += cost
+
+		pods := getTriggeringPods(node, scaleUps)
+		if len(pods) == 0 {
+			costs.UnattributedCost += cost
+			continue
+		}
+		costs.AttributedNodes++
+		costs.AttributedCost += cost
+		isCounted := make(map[string]bool)
+		for _, pod := range pods {
+			name, workloadType, namespace := getPodOwner(pod)
+			key := workloadType + "/" + namespace + "/" + name
+			if _, isPresent := workloads[key]; !isPresent {
+				workloads[key] = &ScaleUpWorkloadCost{Name: name, Type: workloadType, Namespace: namespace}
+			}
+			workload := workloads[key]
+			workload.TriggeredPods++
+			workload.Cost += cost / float64(len(pods))
+			if !isCounted[key] {
+				workload.Nodes++
+				isCounted[key] = true
+			}
+		}
+	}
+
+	for _, workload := range workloads {
+		costs.Workloads = append(costs.Workloads, *workload)
+	}
+	sort.Slice(costs.Workloads, func(i, j int) bool {
+		if costs.Workloads[i].Cost != costs.Workloads[j].Cost {
+			return costs.Workloads[i].Cost > costs.Workloads[j].Cost
+		}
+		return costs.Workloads[i].Name < costs.Workloads[j].Name
+	})
+	return costs
+}
+
+// getTriggeringPods returns pods of scale ups which nominated the node, or else of scale ups triggered
+// within the correlation window before the node was added
+func getTriggeringPods(node addedNode, scaleUps []scaleUpTrigger) []triggeringPod {
+	nominated, correlated := []triggeringPod{}, []triggeringPod{}
+	nodeTime, err := time.Parse(time.RFC3339, node.StartTime)
+	if err != nil {
+		return nil
+	}
+	for _, scaleUp := range scaleUps {
+		if scaleUp.Source == models.KarpenterSource && scaleUp.Target == node.NodeName {
+			nominated = append(nominated, scaleUp.Pod...)
+			continue
+		}
+		triggerTime, err := time.Parse(time.RFC3339, scaleUp.StartTime)
+		if err != nil || triggerTime.After(nodeTime) || nodeTime.Sub(triggerTime) > scaleUpCorrelationWindow {
+			continue
+		}
+		correlated = append(correlated, scaleUp.Pod...)
+	}
+	if len(nominated) > 0 {
+		return nominated
+	}
+	return correlated
+}
+
+// getPodOwner returns name and type of the workload of the pod, the pod itself if it has no workload
+func getPodOwner(pod triggeringPod) (string, string, string) {
+	namespace := ""
+	if pod.Namespace != nil {
+		namespace = pod.Namespace.Name
+	}
+	owners := map[string]*resourceRef{
+		DeploymentConfigType: pod.DeploymentConfig,
+		DeploymentType:       pod.Deployment,
+		StatefulsetType:      pod.Statefulset,
+		DaemonsetType:        pod.Daemonset,
+		JobType:              pod.Job,
+		ReplicasetType:       pod.Replicaset,
+	}
+	for _, ownerType := range podOwnerPredicates {
+		if owner := owners[ownerType]; owner != nil {
+			return owner.Name, ownerType, namespace
+		}
+	}
+	return pod.Name, PodType, namespace
+}
+
+func getQueryForScaleUps(startTime, endTime time.Time) string {
+	start, end := startTime.Format(time.RFC3339), endTime.Format(time.RFC3339)
+	// scale ups adding nodes at start are triggered before it
+	triggerStart := startTime.Add(-scaleUpCorrelationWindow).Format(time.RFC3339)
+	nodeFields := []string{"nodeName", "event", "startTime", "nodeStartTime", "cpuCapacity", "memoryCapacity", "cpuPrice", "memoryPrice"}
+
+	pod := qb.Edge("pod").Filter(qb.Has("isPod")).Fields("name").Child(qb.Edge("namespace").Fields("name"))
+	for _, ownerType := range podOwnerPredicates {
+		pod.Child(qb.Edge(ownerType).Fields("name"))
+	}
+	return qb.New(
+		qb.Func("added", qb.Eq("event", models.NodeAdded)).
+			Filter(qb.And(qb.Has(models.IsNodeEvent), qb.Ge("startTime", start), qb.Le("startTime", end))).
+			Fields(nodeFields...),
+		qb.Func("removed", qb.Eq("event", models.NodeRemoved)).
+			Filter(qb.And(qb.Has(models.IsNodeEvent), qb.Ge("startTime", start))).
+			Fields(nodeFields...),
+		qb.Func("scaleUps", qb.Has(models.IsScaleUp)).
+			Filter(qb.And(qb.Ge("startTime", triggerStart), qb.Le("startTime", end))).
+			Fields("source", "target", "startTime").
+			Child(pod),
+	).String()
+}
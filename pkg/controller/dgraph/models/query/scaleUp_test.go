@@ -0,0 +1,84 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func mockDgraphForScaleUps() {
+	executeQuery = func(ctx context.Context, query string, root interface{}) error {
+		return json.Unmarshal([]byte(`{
+			"added": [
+				{"nodeName": "ip-1", "event": "added", "startTime": "2018-10-02T00:10:00Z", "nodeStartTime": "2018-10-02T00:10:00Z",
+				 "cpuCapacity": 2, "memoryCapacity": 8, "cpuPrice": 0.5, "memoryPrice": 0.125},
+				{"nodeName": "ip-2", "event": "added", "startTime": "2018-10-03T00:00:00Z", "nodeStartTime": "2018-10-03T00:00:00Z",
+				 "cpuCapacity": 2, "memoryCapacity": 8, "cpuPrice": 0.5, "memoryPrice": 0.125},
+				{"nodeName": "ip-3", "event": "added", "startTime": "2018-10-04T00:00:00Z", "nodeStartTime": "2018-10-04T00:00:00Z",
+				 "cpuCapacity": 2, "memoryCapacity": 8, "cpuPrice": 0.5, "memoryPrice": 0.125}
+			],
+			"removed": [
+				{"nodeName": "ip-1", "event": "removed", "startTime": "2018-10-02T10:10:00Z", "nodeStartTime": "2018-10-02T00:10:00Z"},
+				{"nodeName": "ip-2", "event": "removed", "startTime": "2018-10-03T04:00:00Z", "nodeStartTime": "2018-10-03T00:00:00Z"},
+				{"nodeName": "ip-3", "event": "removed", "startTime": "2018-10-04T01:00:00Z", "nodeStartTime": "2018-10-04T00:00:00Z"}
+			],
+			"scaleUps": [
+				{"source": "cluster-autoscaler", "target": "workers", "startTime": "2018-10-02T00:05:00Z",
+				 "pod": [{"name": "pod-web-1", "namespace": {"name": "namespace-default"}, "deployment": {"name": "deployment-web"}, "replicaset": {"name": "replicaset-web-1"}}]},
+				{"source": "cluster-autoscaler", "target": "workers", "startTime": "2018-10-02T00:06:00Z",
+				 "pod": [{"name": "pod-batch-1", "namespace": {"name": "namespace-default"}, "job": {"name": "job-batch"}}]},
+				{"source": "karpenter", "target": "ip-2", "startTime": "2018-10-02T23:00:00Z",
+				 "pod": [{"name": "pod-web-2", "namespace": {"name": "namespace-default"}, "deployment": {"name": "deployment-web"}}]}
+			]
+		}`), root)
+	}
+}
+
+// TestRetrieveScaleUpCosts ...
+func TestRetrieveScaleUpCosts(t *testing.T) {
+	mockDgraphForScaleUps()
+	costs := RetrieveScaleUpCosts(context.Background(), "2018-10-01T00:00:00Z", "2018-10-11T00:00:00Z").Data
+
+	// every node costs 2$/h, ip-1 existed for 10h, ip-2 for 4h and ip-3 for 1h without a scale up
+	assert.Equal(t, 3, costs.NodesAdded)
+	assert.Equal(t, 2, costs.AttributedNodes)
+	assert.InDelta(t, 30.0, costs.AddedCost, 1e-9)
+	assert.InDelta(t, 28.0, costs.AttributedCost, 1e-9)
+	assert.InDelta(t, 2.0, costs.UnattributedCost, 1e-9)
+
+	assert.Equal(t, 2, len(costs.Workloads))
+	assert.Equal(t, ScaleUpWorkloadCost{Name: "deployment-web", Type: DeploymentType, Namespace: "namespace-default", TriggeredPods: 2, Nodes: 2, Cost: 18}, costs.Workloads[0])
+	assert.Equal(t, ScaleUpWorkloadCost{Name: "job-batch", Type: JobType, Namespace: "namespace-default", TriggeredPods: 1, Nodes: 1, Cost: 10}, costs.Workloads[1])
+}
+
+// TestGetQueryForScaleUps ...
+func TestGetQueryForScaleUps(t *testing.T) {
+	var query string
+	executeQuery = func(ctx context.Context, q string, root interface{}) error {
+		query = q
+		return nil
+	}
+	RetrieveScaleUpCosts(context.Background(), "2018-10-01T00:00:00Z", "2018-10-11T00:00:00Z")
+	assert.True(t, strings.Contains(query, `scaleUps(func: has(isScaleUp)) @filter(ge(startTime, "2018-09-30T23:45:00Z") AND le(startTime, "2018-10-11T00:00:00Z"))`))
+	assert.True(t, strings.Contains(query, `added(func: eq(event, "added")) @filter(has(isNodeEvent) AND ge(startTime, "2018-10-01T00:00:00Z")`))
+}
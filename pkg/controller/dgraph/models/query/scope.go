@@ -0,0 +1,129 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"context"
+)
+
+// ClusterCost is the cost of cpu, memory and storage of a cluster
+type ClusterCost struct {
+	CPUCost     float64 `json:"cpuCost"`
+	MemoryCost  float64 `json:"memoryCost"`
+	StorageCost float64 `json:"storageCost"`
+	TotalCost   float64 `json:"totalCost"`
+}
+
+// ClusterCostWrapper structure
+type ClusterCostWrapper struct {
+	Data ClusterCost `json:"data"`
+}
+
+// ClusterTotals is the cost of resources allocated to namespaces, of the capacity of nodes and volumes and of the
+// capacity which is not allocated(idle) in the time range, with allocated and capacity units at its end
+type ClusterTotals struct {
+	Start            string      `json:"start"`
+	End              string      `json:"end"`
+	Allocated        ClusterCost `json:"allocated"`
+	Capacity         ClusterCost `json:"capacity"`
+	Idle             ClusterCost `json:"idle"`
+	CPUAllocated     float64     `json:"cpuAllocated"`
+	MemoryAllocated  float64     `json:"memoryAllocated"`
+	StorageAllocated float64     `json:"storageAllocated"`
+	CPUCapacity      float64     `json:"cpuCapacity"`
+	MemoryCapacity   float64     `json:"memoryCapacity"`
+	StorageCapacity  float64     `json:"storageCapacity"`
+}
+
+// ClusterTotalsWrapper structure
+type ClusterTotalsWrapper struct {
+	Data ClusterTotals `json:"data"`
+}
+
+// RetrieveClusterNodes returns nodes of the cluster with their capacity and its cost in the time range, persistent
+// volumes of the physical view are left out
+func RetrieveClusterNodes(ctx context.Context, timeRange TimeRange) JSONDataWrapper {
+	parentRoot := ParentWrapper{}
+	err := executeQuery(ctx, getClusterMetricsQuery(Physical, All, timeRange, Include), &parentRoot)
+	if err != nil {
+		log.Errorf("Unable to execute query for retrieving cluster nodes: (%v)", err)
+		return JSONDataWrapper{}
+	}
+	nodes := ParentWrapper{Name: "cluster", Type: "cluster", Children: []Children{}}
+	for _, child := range parentRoot.Children {
+		if child.Type == NodeType {
+			nodes.Children = append(nodes.Children, child)
+		}
+	}
+	calculateAggregateMetrics(&nodes)
+	adjustCosts(&nodes)
+	return JSONDataWrapper{Data: nodes}
+}
+
+// RetrieveClusterTotals returns allocated, capacity and idle cost of the cluster over the time range, month to date
+// by default
+func RetrieveClusterTotals(ctx context.Context, timeRange TimeRange) ClusterTotalsWrapper {
+	timeRange = getRangeFromMonthStart(timeRange)
+	allocation := RetrieveClusterMetricsInRange(ctx, Logical, All, timeRange, Include)
+	capacity := RetrieveClusterMetricsInRange(ctx, Physical, All, timeRange, Include)
+	return ClusterTotalsWrapper{Data: computeClusterTotals(timeRange, allocation.Data, capacity.Data)}
+}
+
+// RetrieveClusterIdleCost returns the cost of capacity of the cluster not allocated to pods over the time range,
+// month to date by default
+func RetrieveClusterIdleCost(ctx context.Context, timeRange TimeRange) ClusterCostWrapper {
+	return ClusterCostWrapper{Data: RetrieveClusterTotals(ctx, timeRange).Data.Idle}
+}
+
+func computeClusterTotals(timeRange TimeRange, allocation, capacity ParentWrapper) ClusterTotals {
+	totals := ClusterTotals{
+		Start:            timeRange.Start,
+		End:              timeRange.End,
+		Allocated:        newClusterCost(allocation.CPUCost, allocation.MemoryCost, allocation.StorageCost),
+		Capacity:         newClusterCost(capacity.CPUCost, capacity.MemoryCost, capacity.StorageCost),
+		CPUAllocated:     allocation.CPU,
+		MemoryAllocated:  allocation.Memory,
+		StorageAllocated: allocation.Storage,
+		CPUCapacity:      capacity.CPU,
+		MemoryCapacity:   capacity.Memory,
+		StorageCapacity:  capacity.Storage,
+	}
+	// allocation can exceed capacity with overcommitted nodes or pending pods, idle cost is 0 then
+	totals.Idle = newClusterCost(
+		nonNegative(totals.Capacity.CPUCost-totals.Allocated.CPUCost),
+		nonNegative(totals.Capacity.MemoryCost-totals.Allocated.MemoryCost),
+		nonNegative(totals.Capacity.StorageCost-totals.Allocated.StorageCost),
+	)
+	return totals
+}
+
+func newClusterCost(cpuCost, memoryCost, storageCost float64) ClusterCost {
+	return ClusterCost{
+		CPUCost:     cpuCost,
+		MemoryCost:  memoryCost,
+		StorageCost: storageCost,
+		TotalCost:   cpuCost + memoryCost + storageCost,
+	}
+}
+
+func nonNegative(value float64) float64 {
+	if value < 0 {
+		return 0
+	}
+	return value
+}
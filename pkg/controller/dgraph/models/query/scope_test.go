@@ -0,0 +1,81 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func mockDgraphForScopeQueries() {
+	executeQuery = func(ctx context.Context, query string, root interface{}) error {
+		parentRoot, ok := root.(*ParentWrapper)
+		if !ok {
+			return fmt.Errorf("wrong root received")
+		}
+		if strings.Contains(query, "isNode") {
+			parentRoot.Children = []Children{
+				{Name: "node-first", Type: NodeType, CPU: 4, Memory: 16, CPUCost: 10, MemoryCost: 6},
+				{Name: "pv-first", Type: PVType, Storage: 100, StorageCost: 2},
+			}
+			return nil
+		}
+		parentRoot.Children = []Children{
+			{Name: "namespace-first", Type: NamespaceType, CPU: 2, Memory: 20, Storage: 10, CPUCost: 4, MemoryCost: 7, StorageCost: 0.5},
+		}
+		return nil
+	}
+}
+
+func TestRetrieveClusterNodes(t *testing.T) {
+	mockDgraphForScopeQueries()
+	defer removeMocks()
+
+	got := RetrieveClusterNodes(context.Background(), TimeRange{})
+	assert.Equal(t, "cluster", got.Data.Name)
+	assert.Equal(t, 1, len(got.Data.Children))
+	assert.Equal(t, "node-first", got.Data.Children[0].Name)
+	assert.Equal(t, 10.0, got.Data.CPUCost)
+	assert.Equal(t, 0.0, got.Data.StorageCost)
+}
+
+func TestRetrieveClusterTotals(t *testing.T) {
+	mockDgraphForScopeQueries()
+	defer removeMocks()
+
+	got := RetrieveClusterTotals(context.Background(), TimeRange{Start: "2018-10-01T00:00:00Z", End: "2018-10-15T00:00:00Z"})
+	expected := ClusterTotals{
+		Start:            "2018-10-01T00:00:00Z",
+		End:              "2018-10-15T00:00:00Z",
+		Allocated:        ClusterCost{CPUCost: 4, MemoryCost: 7, StorageCost: 0.5, TotalCost: 11.5},
+		Capacity:         ClusterCost{CPUCost: 10, MemoryCost: 6, StorageCost: 2, TotalCost: 18},
+		Idle:             ClusterCost{CPUCost: 6, MemoryCost: 0, StorageCost: 1.5, TotalCost: 7.5},
+		CPUAllocated:     2,
+		MemoryAllocated:  20,
+		StorageAllocated: 10,
+		CPUCapacity:      4,
+		MemoryCapacity:   16,
+		StorageCapacity:  100,
+	}
+	assert.Equal(t, expected, got.Data)
+	assert.Equal(t, expected.Idle, RetrieveClusterIdleCost(context.Background(), TimeRange{Start: expected.Start, End: expected.End}).Data)
+}
@@ -0,0 +1,169 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Name match modes of resource lookups, names are matched exactly by default.
+const (
+	ExactMatch      = "exact"
+	IgnoreCaseMatch = "ignoreCase"
+	PartialMatch    = "partial"
+	FuzzyMatch      = "fuzzy"
+
+	// MinRegexpMatchLength is the shortest name which can be matched using the trigram index of name
+	MinRegexpMatchLength = 3
+)
+
+type matchedResource struct {
+	Name    string `json:"name"`
+	EndTime string `json:"endTime,omitempty"`
+}
+
+// ResolveName returns the stored name of resource of given check and type(ex: isPod, pod) which best matches
+// the given name with match mode. Live resources are preferred over deleted ones and closer names over farther.
+func ResolveName(ctx context.Context, check, resourceType, name, match string) (string, error) {
+	if match == "" || match == ExactMatch {
+		return name, nil
+	}
+	query, err := getQueryForNameMatch(check, resourceType, name, match)
+	if err != nil {
+		return "", err
+	}
+	root := struct {
+		Resources []matchedResource `json:"resources"`
+	}{}
+	err = executeQuery(ctx, query, &root)
+	if err != nil {
+		return "", err
+	}
+	matches := rankNameMatches(root.Resources, resourceType, name, match)
+	if len(matches) == 0 {
+		return "", fmt.Errorf("no %s matches name %s", resourceType, name)
+	}
+	return matches[0], nil
+}
+
+func getQueryForNameMatch(check, resourceType, name, match string) (string, error) {
+	switch match {
+	case IgnoreCaseMatch, PartialMatch:
+		if len(name) < MinRegexpMatchLength {
+			return "", fmt.Errorf("name %s is shorter than %d characters", name, MinRegexpMatchLength)
+		}
+		return `query {
+			resources(func: regexp(name, /` + getNameRegexp(resourceType, name, match) + `/i)) @filter(has(` + check + `)) {
+				name
+				endTime
+			}
+		}`, nil
+	case FuzzyMatch:
+		return `query {
+			resources(func: has(` + check + `)) @filter(NOT has(endTime)) {
+				name
+			}
+		}`, nil
+	}
+	return "", fmt.Errorf("unknown match mode: %s", match)
+}
+
+// getNameRegexp returns regexp matching the name with or without type prefix(ex: pod-) for ignoreCase match
+// and any name containing it for partial match.
+func getNameRegexp(resourceType, name, match string) string {
+	quoted := regexp.QuoteMeta(strings.TrimPrefix(strings.ToLower(name), resourceType+"-"))
+	if match == PartialMatch {
+		return quoted
+	}
+	return `^(` + resourceType + `-)?` + quoted + `$`
+}
+
+// rankNameMatches returns names of matched resources sorted by whether they are deleted and then by
+// edit distance to the given name ignoring case and type prefix. Fuzzy matches farther than a third
+// of the name length are dropped.
+func rankNameMatches(resources []matchedResource, resourceType, name, match string) []string {
+	target := strings.TrimPrefix(strings.ToLower(name), resourceType+"-")
+	maxDistance := len(target) / 3
+	if maxDistance < 1 {
+		maxDistance = 1
+	}
+
+	distances := make(map[string]int)
+	var names []string
+	for _, resource := range resources {
+		candidate := strings.TrimPrefix(strings.ToLower(resource.Name), resourceType+"-")
+		distance := levenshtein(candidate, target)
+		if match == FuzzyMatch && distance > maxDistance {
+			continue
+		}
+		if isDeletedName(resource) {
+			// any live resource wins over a deleted one
+			distance += len(candidate) + len(target) + 1
+		}
+		distances[resource.Name] = distance
+		names = append(names, resource.Name)
+	}
+	sort.SliceStable(names, func(i, j int) bool {
+		if distances[names[i]] != distances[names[j]] {
+			return distances[names[i]] < distances[names[j]]
+		}
+		return names[i] < names[j]
+	})
+	log.Debugf("resources matching %s with %s match: %v", name, match, names)
+	return names
+}
+
+// isDeletedName returns true if resource is deleted, names of deleted pods and services are suffixed with *endTime
+func isDeletedName(resource matchedResource) bool {
+	return resource.EndTime != "" || strings.Contains(resource.Name, "*")
+}
+
+// levenshtein returns the number of single byte insertions, deletions or substitutions to change a into b
+func levenshtein(a, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = minOf(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
+
+func minOf(values ...int) int {
+	min := values[0]
+	for _, value := range values[1:] {
+		if value < min {
+			min = value
+		}
+	}
+	return min
+}
@@ -0,0 +1,108 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func mockDgraphForNameMatch(response string) *string {
+	var received string
+	executeQuery = func(ctx context.Context, query string, root interface{}) error {
+		received = query
+		return json.Unmarshal([]byte(response), root)
+	}
+	return &received
+}
+
+// TestResolveNameExact ...
+func TestResolveNameExact(t *testing.T) {
+	query := mockDgraphForNameMatch(`{}`)
+	got, err := ResolveName(context.Background(), DeploymentCheck, DeploymentType, "Frontend", "")
+	assert.Nil(t, err)
+	assert.Equal(t, "Frontend", got)
+	got, err = ResolveName(context.Background(), DeploymentCheck, DeploymentType, "Frontend", ExactMatch)
+	assert.Nil(t, err)
+	assert.Equal(t, "Frontend", got)
+	assert.Equal(t, "", *query)
+}
+
+// TestResolveNameIgnoreCase ...
+func TestResolveNameIgnoreCase(t *testing.T) {
+	query := mockDgraphForNameMatch(`{"resources": [{"name": "deployment-frontend"}]}`)
+	got, err := ResolveName(context.Background(), DeploymentCheck, DeploymentType, "Frontend", IgnoreCaseMatch)
+	assert.Nil(t, err)
+	assert.Equal(t, "deployment-frontend", got)
+	assert.True(t, strings.Contains(*query, `regexp(name, /^(deployment-)?frontend$/i)) @filter(has(isDeployment))`))
+
+	_, err = ResolveName(context.Background(), DeploymentCheck, DeploymentType, "fe", IgnoreCaseMatch)
+	assert.NotNil(t, err)
+	_, err = ResolveName(context.Background(), DeploymentCheck, DeploymentType, "Frontend", "regex")
+	assert.NotNil(t, err)
+}
+
+// TestResolveNamePartial ...
+func TestResolveNamePartial(t *testing.T) {
+	query := mockDgraphForNameMatch(`{"resources": [
+		{"name": "pod-frontend-2*2018-10-10T10:10:10Z", "endTime": "2018-10-10T10:10:10Z"},
+		{"name": "pod-frontend-canary-1"},
+		{"name": "pod-frontend-1"}
+	]}`)
+	got, err := ResolveName(context.Background(), PodCheck, PodType, "front.end", PartialMatch)
+	assert.Nil(t, err)
+	assert.Equal(t, "pod-frontend-1", got)
+	assert.True(t, strings.Contains(*query, `regexp(name, /front\.end/i)`))
+
+	mockDgraphForNameMatch(`{"resources": []}`)
+	_, err = ResolveName(context.Background(), PodCheck, PodType, "frontend", PartialMatch)
+	assert.NotNil(t, err)
+}
+
+// TestResolveNameFuzzy ...
+func TestResolveNameFuzzy(t *testing.T) {
+	query := mockDgraphForNameMatch(`{"resources": [
+		{"name": "deployment-backend"},
+		{"name": "deployment-frontnd"},
+		{"name": "deployment-frontend"}
+	]}`)
+	got, err := ResolveName(context.Background(), DeploymentCheck, DeploymentType, "deployment-Frontend", FuzzyMatch)
+	assert.Nil(t, err)
+	assert.Equal(t, "deployment-frontend", got)
+	assert.True(t, strings.Contains(*query, `resources(func: has(isDeployment)) @filter(NOT has(endTime))`))
+
+	got, err = ResolveName(context.Background(), DeploymentCheck, DeploymentType, "FrontNd", FuzzyMatch)
+	assert.Nil(t, err)
+	assert.Equal(t, "deployment-frontnd", got)
+
+	_, err = ResolveName(context.Background(), DeploymentCheck, DeploymentType, "database", FuzzyMatch)
+	assert.NotNil(t, err)
+}
+
+// TestLevenshtein ...
+func TestLevenshtein(t *testing.T) {
+	assert.Equal(t, 0, levenshtein("purser", "purser"))
+	assert.Equal(t, 6, levenshtein("", "purser"))
+	assert.Equal(t, 1, levenshtein("purser", "puser"))
+	assert.Equal(t, 2, levenshtein("frontend", "frontedn"))
+	assert.Equal(t, 3, levenshtein("kitten", "sitting"))
+}
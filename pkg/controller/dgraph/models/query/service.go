@@ -0,0 +1,108 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"context"
+	qb "github.com/vmware/purser/pkg/querybuilder"
+)
+
+// ServiceType is the type of service resource
+const ServiceType = "service"
+
+// UnitCost structure
+type UnitCost struct {
+	StartTime         string  `json:"startTime,omitempty"`
+	EndTime           string  `json:"endTime,omitempty"`
+	Requests          float64 `json:"requests"`
+	Cost              float64 `json:"cost"`
+	CostPer1kRequests float64 `json:"costPer1kRequests"`
+}
+
+// ServiceUnitCosts structure
+type ServiceUnitCosts struct {
+	Name      string     `json:"name,omitempty"`
+	Type      string     `json:"type,omitempty"`
+	UnitCosts []UnitCost `json:"unitCosts,omitempty"`
+}
+
+// ServiceUnitCostsWrapper structure
+type ServiceUnitCostsWrapper struct {
+	Data ServiceUnitCosts `json:"data,omitempty"`
+}
+
+// RetrieveServiceUnitCosts returns hourly cost per 1k requests of the service, start and end(RFC3339) are optional
+func RetrieveServiceUnitCosts(ctx context.Context, name, start, end string) ServiceUnitCostsWrapper {
+	return RetrieveServiceUnitCostsInNamespace(ctx, name, All, start, end)
+}
+
+// RetrieveServiceUnitCostsInNamespace returns unit costs like RetrieveServiceUnitCosts of the service of the
+// namespace(ex: namespace-default), the service is looked up in all namespaces if it is empty
+func RetrieveServiceUnitCostsInNamespace(ctx context.Context, name, namespace, start, end string) ServiceUnitCostsWrapper {
+	if name == All {
+		log.Errorf("wrong type of query, empty name is given")
+		return ServiceUnitCostsWrapper{}
+	}
+	query, vars := getQueryForServiceUnitCosts(name, namespace, start, end)
+
+	type root struct {
+		Parent []ServiceUnitCosts `json:"parent"`
+	}
+	newRoot := root{}
+	err := executeQueryWithVars(ctx, query, vars, &newRoot)
+	if err != nil || len(newRoot.Parent) == 0 {
+		log.Errorf("Unable to execute query, err: (%v)", err)
+		return ServiceUnitCostsWrapper{}
+	}
+	data := newRoot.Parent[0]
+	for index := range data.UnitCosts {
+		unitCost := &data.UnitCosts[index]
+		unitCost.Cost = adjustCost(CostContext{ServiceType, data.Name, TotalCostType}, unitCost.Cost)
+		if unitCost.Requests > 0 {
+			unitCost.CostPer1kRequests = unitCost.Cost / unitCost.Requests * 1000
+		}
+	}
+	return ServiceUnitCostsWrapper{Data: data}
+}
+
+func getQueryForServiceUnitCosts(name, namespace, start, end string) (string, qb.Vars) {
+	vars := getNamespaceVars(qb.Vars{"$name": name}, namespace)
+	filter := `has(isServiceUnitCost)`
+	if start != "" {
+		vars["$start"] = start
+		filter += ` AND ge(endTime, $start)`
+	}
+	if end != "" {
+		vars["$end"] = end
+		filter += ` AND le(endTime, $end)`
+	}
+	return vars.Declaration() + ` {
+		` + getNamespaceVar(namespace) + `
+		parent(func: has(isService)) @filter(eq(name, $name)` + getNamespaceFilter(namespace) + `) {
+			name
+			type
+			unitCosts: ~service @filter(` + filter + `) (orderasc: endTime) {
+				startTime
+				endTime
+				requests
+				cost
+				costPer1kRequests
+			}
+		}
+	}`, vars
+}
@@ -0,0 +1,79 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	qb "github.com/vmware/purser/pkg/querybuilder"
+)
+
+func mockDgraphForServiceUnitCosts() {
+	executeQueryWithVars = func(ctx context.Context, query string, vars map[string]string, root interface{}) error {
+		data := `{"parent": [{"name": "service-frontend", "type": "service", "unitCosts": [
+			{"startTime": "2018-10-10T10:00:00Z", "endTime": "2018-10-10T11:00:00Z", "requests": 4000, "cost": 0.2, "costPer1kRequests": 0.05},
+			{"startTime": "2018-10-10T11:00:00Z", "endTime": "2018-10-10T12:00:00Z", "cost": 0.2}]}]}`
+		return json.Unmarshal([]byte(data), root)
+	}
+}
+
+// TestRetrieveServiceUnitCosts ...
+func TestRetrieveServiceUnitCosts(t *testing.T) {
+	mockDgraphForServiceUnitCosts()
+	got := RetrieveServiceUnitCosts(context.Background(), "service-frontend", "", "")
+	expected := ServiceUnitCostsWrapper{
+		Data: ServiceUnitCosts{
+			Name: "service-frontend",
+			Type: ServiceType,
+			UnitCosts: []UnitCost{
+				{StartTime: "2018-10-10T10:00:00Z", EndTime: "2018-10-10T11:00:00Z", Requests: 4000, Cost: 0.2, CostPer1kRequests: 0.05},
+				{StartTime: "2018-10-10T11:00:00Z", EndTime: "2018-10-10T12:00:00Z", Cost: 0.2},
+			},
+		},
+	}
+	assert.Equal(t, expected, got)
+}
+
+// TestRetrieveServiceUnitCostsWithEmptyName ...
+func TestRetrieveServiceUnitCostsWithEmptyName(t *testing.T) {
+	executeQueryWithVars = func(ctx context.Context, query string, vars map[string]string, root interface{}) error {
+		return fmt.Errorf("query should not be executed")
+	}
+	assert.Equal(t, ServiceUnitCostsWrapper{}, RetrieveServiceUnitCosts(context.Background(), All, "", ""))
+}
+
+// TestGetQueryForServiceUnitCosts ...
+func TestGetQueryForServiceUnitCosts(t *testing.T) {
+	query, vars := getQueryForServiceUnitCosts("service-frontend", All, "2018-10-10T00:00:00Z", "")
+	assert.Equal(t, qb.Vars{"$name": "service-frontend", "$start": "2018-10-10T00:00:00Z"}, vars)
+	assert.True(t, strings.HasPrefix(query, "query q($name: string, $start: string) {"))
+	assert.True(t, strings.Contains(query, "unitCosts: ~service @filter(has(isServiceUnitCost) AND ge(endTime, $start))"))
+}
+
+// TestGetQueryForServiceUnitCostsInNamespace ...
+func TestGetQueryForServiceUnitCostsInNamespace(t *testing.T) {
+	query, vars := getQueryForServiceUnitCosts("service-frontend", "namespace-shop", "", "")
+	assert.Equal(t, qb.Vars{"$name": "service-frontend", "$namespace": "namespace-shop"}, vars)
+	assert.True(t, strings.Contains(query, "var(func: has(isNamespace)) @filter(eq(name, $namespace)) {"))
+	assert.True(t, strings.Contains(query, "parent(func: has(isService)) @filter(eq(name, $name) AND uid(namespaceResources)) {"))
+}
@@ -0,0 +1,104 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	qb "github.com/vmware/purser/pkg/querybuilder"
+)
+
+// kindChecks maps kinds of resources watched by the controller to the predicate marking them in Dgraph
+var kindChecks = map[string]string{
+	"Pod":                   PodCheck,
+	"Node":                  NodeCheck,
+	"PersistentVolume":      PVCheck,
+	"PersistentVolumeClaim": PVCCheck,
+	"Service":               "isService",
+	"ReplicaSet":            ReplicasetCheck,
+	"DaemonSet":             DaemonsetCheck,
+	"Deployment":            DeploymentCheck,
+	"StatefulSet":           StatefulsetCheck,
+	"Job":                   JobCheck,
+	"Namespace":             NamespaceCheck,
+	"Group":                 "isGroup",
+	"Subscriber":            "isSubscriber",
+	"DeploymentConfig":      DeploymentConfigCheck,
+	"Route":                 "isRoute",
+	"ImageStream":           "isImageStream",
+}
+
+// RetrieveTrackedObjects returns the number of live(without end time) objects stored in Dgraph for each kind
+// watched by the controller
+func RetrieveTrackedObjects(ctx context.Context) (map[string]int, error) {
+	var kinds []string
+	for kind := range kindChecks {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+
+	query := qb.New()
+	for _, kind := range kinds {
+		query.Block(qb.Func(strings.ToLower(kind), qb.Has(kindChecks[kind])).
+			Filter(qb.Not(qb.Has("endTime"))).
+			Fields("count(uid)"))
+	}
+	root := make(map[string][]struct {
+		Count int `json:"count"`
+	})
+	if err := executeQuery(ctx, query.String(), &root); err != nil {
+		return nil, err
+	}
+
+	tracked := make(map[string]int)
+	for _, kind := range kinds {
+		if counts := root[strings.ToLower(kind)]; len(counts) > 0 {
+			tracked[kind] = counts[0].Count
+		}
+	}
+	return tracked, nil
+}
+
+// LiveResource is a resource without end time stored in Dgraph
+type LiveResource struct {
+	UID  string `json:"uid"`
+	Xid  string `json:"xid"`
+	Name string `json:"name"`
+}
+
+// RetrieveLiveResources returns resources of the kind(ex: Pod) without end time
+func RetrieveLiveResources(ctx context.Context, kind string) ([]LiveResource, error) {
+	check, isPresent := kindChecks[kind]
+	if !isPresent {
+		return nil, fmt.Errorf("unknown kind: %s", kind)
+	}
+	query := qb.New(
+		qb.Func("resources", qb.Has(check)).Filter(qb.Not(qb.Has("endTime"))).Fields("uid", "xid", "name"),
+	)
+	type root struct {
+		Resources []LiveResource `json:"resources"`
+	}
+	newRoot := root{}
+	if err := executeQuery(ctx, query.String(), &newRoot); err != nil {
+		return nil, err
+	}
+	return newRoot.Resources, nil
+}
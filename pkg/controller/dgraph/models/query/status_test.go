@@ -0,0 +1,63 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestRetrieveTrackedObjects ...
+func TestRetrieveTrackedObjects(t *testing.T) {
+	var received string
+	executeQuery = func(ctx context.Context, query string, root interface{}) error {
+		received = query
+		return json.Unmarshal([]byte(`{"pod": [{"count": 12}], "node": [{"count": 3}], "service": []}`), root)
+	}
+	got, err := RetrieveTrackedObjects(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]int{"Pod": 12, "Node": 3}, got)
+	assert.True(t, strings.Contains(received, "persistentvolumeclaim(func: has(isPersistentVolumeClaim)) @filter(NOT has(endTime)) {\n\t\tcount(uid)\n\t}"))
+
+	executeQuery = func(ctx context.Context, query string, root interface{}) error {
+		return fmt.Errorf("dgraph unavailable")
+	}
+	_, err = RetrieveTrackedObjects(context.Background())
+	assert.NotNil(t, err)
+}
+
+// TestRetrieveLiveResources ...
+func TestRetrieveLiveResources(t *testing.T) {
+	var received string
+	executeQuery = func(ctx context.Context, query string, root interface{}) error {
+		received = query
+		return json.Unmarshal([]byte(`{"resources": [{"uid": "0x1", "xid": "default:web", "name": "deployment-web"}]}`), root)
+	}
+	got, err := RetrieveLiveResources(context.Background(), "Deployment")
+	assert.Nil(t, err)
+	assert.Equal(t, []LiveResource{{UID: "0x1", Xid: "default:web", Name: "deployment-web"}}, got)
+	assert.True(t, strings.Contains(received, "resources(func: has(isDeployment)) @filter(NOT has(endTime))"))
+
+	_, err = RetrieveLiveResources(context.Background(), "CronJob")
+	assert.NotNil(t, err)
+}
@@ -18,6 +18,7 @@
 package query
 
 import (
+	"context"
 	"github.com/vmware/purser/pkg/controller/dgraph/models"
 )
 
@@ -26,10 +27,10 @@ type subscriberRoot struct {
 }
 
 // RetrieveSubscribers gets all live subscribers
-func RetrieveSubscribers() ([]models.SubscriberCRD, error) {
+func RetrieveSubscribers(ctx context.Context) ([]models.SubscriberCRD, error) {
 	q := getQueryForSubscribersRetrieval()
 	newRoot := subscriberRoot{}
-	err := executeQuery(q, &newRoot)
+	err := executeQuery(ctx, q, &newRoot)
 	if err != nil {
 		return nil, err
 	}
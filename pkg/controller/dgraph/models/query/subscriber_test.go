@@ -18,6 +18,7 @@
 package query
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
@@ -27,7 +28,7 @@ import (
 )
 
 func mockDgraphForSubscriberQueries(queryType string) {
-	executeQuery = func(query string, root interface{}) error {
+	executeQuery = func(ctx context.Context, query string, root interface{}) error {
 		dummySubscriberList, ok := root.(*subscriberRoot)
 		if !ok {
 			return fmt.Errorf("wrong root received")
@@ -51,14 +52,14 @@ func mockDgraphForSubscriberQueries(queryType string) {
 // TestRetrieveSubscribersWithDgraphError ...
 func TestRetrieveSubscribersWithDgraphError(t *testing.T) {
 	mockDgraphForSubscriberQueries(testWrongQuery)
-	_, err := RetrieveSubscribers()
+	_, err := RetrieveSubscribers(context.Background())
 	assert.Error(t, err)
 }
 
 // TestRetrieveSubscribers ...
 func TestRetrieveSubscribers(t *testing.T) {
 	mockDgraphForSubscriberQueries(testRetrieveSubscribers)
-	got, err := RetrieveSubscribers()
+	got, err := RetrieveSubscribers(context.Background())
 	expected := []models.SubscriberCRD{{
 		Name: "subscriber-purser",
 		Spec: models.SubscriberSpec{
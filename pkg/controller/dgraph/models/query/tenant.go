@@ -0,0 +1,270 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vmware/purser/pkg/controller/utils"
+	qb "github.com/vmware/purser/pkg/querybuilder"
+)
+
+// Tenant constants
+const (
+	TenantType         = "tenant"
+	DefaultTenantLabel = "tenant"
+	Label              = "label"
+)
+
+// Periods of tenant cost series
+const (
+	CurrentMonth  = "currentMonth"
+	LastMonth     = "lastMonth"
+	LastLastMonth = "lastLastMonth"
+)
+
+var (
+	tenancyMu        sync.RWMutex
+	tenantLabel      = DefaultTenantLabel
+	sharedNamespaces = []string{"kube-system"}
+)
+
+// TenantCost is the cost of a tenant in a month, SharedCost is its share of cost of shared pods
+type TenantCost struct {
+	Period     string  `json:"period"`
+	Start      string  `json:"start"`
+	DirectCost float64 `json:"directCost"`
+	SharedCost float64 `json:"sharedCost"`
+	TotalCost  float64 `json:"totalCost"`
+}
+
+// Tenant structure
+type Tenant struct {
+	Name  string       `json:"name"`
+	Costs []TenantCost `json:"costs"`
+}
+
+// TenantCosts structure
+type TenantCosts struct {
+	Label            string   `json:"label"`
+	SharedNamespaces []string `json:"sharedNamespaces,omitempty"`
+	Tenants          []Tenant `json:"tenants"`
+}
+
+// TenantCostsWrapper structure
+type TenantCostsWrapper struct {
+	Data TenantCosts `json:"data"`
+}
+
+// ConfigureTenancy sets the default tenant label and namespaces whose pods are shared by all tenants
+func ConfigureTenancy(label string, namespaces []string) {
+	tenancyMu.Lock()
+	defer tenancyMu.Unlock()
+	if label != "" {
+		tenantLabel = label
+	}
+	sharedNamespaces = namespaces
+	log.Infof("tenant label: %s, shared namespaces: %v", tenantLabel, sharedNamespaces)
+}
+
+// GetTenantLabel returns the configured tenant label
+func GetTenantLabel() string {
+	tenancyMu.RLock()
+	defer tenancyMu.RUnlock()
+	return tenantLabel
+}
+
+// RetrieveTenantCosts returns monthly costs of each value of the tenant label. Cost of pods in shared
+// namespaces without the label is allocated to tenants proportionally to their direct cost.
+func RetrieveTenantCosts(ctx context.Context, label string) TenantCostsWrapper {
+	tenancyMu.RLock()
+	if label == All {
+		label = tenantLabel
+	}
+	namespaces := sharedNamespaces
+	tenancyMu.RUnlock()
+
+	tenantPods, err := retrieveTenantPods(ctx, label)
+	if err != nil {
+		log.Errorf("unable to retrieve pods of tenants, label: %s, err: %v", label, err)
+		return TenantCostsWrapper{}
+	}
+	sharedPods, err := retrieveSharedPods(ctx, namespaces, tenantPods)
+	if err != nil {
+		log.Errorf("unable to retrieve shared pods, namespaces: %v, err: %v", namespaces, err)
+	}
+
+	directCosts := make(map[string]map[string]float64)
+	for tenant, pods := range tenantPods {
+		directCosts[tenant] = retrieveMonthlyCosts(ctx, pods)
+	}
+	sharedCosts := retrieveMonthlyCosts(ctx, sharedPods)
+
+	data := TenantCosts{Label: label, SharedNamespaces: namespaces, Tenants: []Tenant{}}
+	periods := getTenantPeriods()
+	allocated := make(map[string]map[string]float64)
+	for _, period := range periods {
+		direct := make(map[string]float64)
+		for tenant, costs := range directCosts {
+			direct[tenant] = costs[period.Period]
+		}
+		allocated[period.Period] = allocateSharedCost(direct, sharedCosts[period.Period])
+	}
+	for tenant, costs := range directCosts {
+		series := []TenantCost{}
+		for _, period := range periods {
+			shared := allocated[period.Period][tenant]
+			series = append(series, TenantCost{
+				Period:     period.Period,
+				Start:      period.Start,
+				DirectCost: costs[period.Period],
+				SharedCost: shared,
+				TotalCost:  costs[period.Period] + shared,
+			})
+		}
+		data.Tenants = append(data.Tenants, Tenant{Name: tenant, Costs: series})
+	}
+	return TenantCostsWrapper{Data: data}
+}
+
+// allocateSharedCost splits shared cost among tenants proportionally to their direct cost,
+// evenly if none of them has direct cost
+func allocateSharedCost(direct map[string]float64, shared float64) map[string]float64 {
+	allocated := make(map[string]float64)
+	if len(direct) == 0 || shared == 0 {
+		return allocated
+	}
+	total := 0.0
+	for _, cost := range direct {
+		total += cost
+	}
+	for tenant, cost := range direct {
+		if total > 0 {
+			allocated[tenant] = shared * cost / total
+		} else {
+			allocated[tenant] = shared / float64(len(direct))
+		}
+	}
+	return allocated
+}
+
+func getTenantPeriods() []TenantCost {
+	monthStart := utils.GetCurrentMonthStartTime()
+	return []TenantCost{
+		{Period: LastLastMonth, Start: monthStart.AddDate(0, -2, 0).Format(time.RFC3339)},
+		{Period: LastMonth, Start: monthStart.AddDate(0, -1, 0).Format(time.RFC3339)},
+		{Period: CurrentMonth, Start: monthStart.Format(time.RFC3339)},
+	}
+}
+
+// retrieveMonthlyCosts returns total cost of pods in each period
+func retrieveMonthlyCosts(ctx context.Context, podsUIDs []string) map[string]float64 {
+	costs := make(map[string]float64)
+	if len(podsUIDs) == 0 {
+		return costs
+	}
+	metrics, err := retrieveMetricsOfPods(ctx, strings.Join(podsUIDs, ", "), TenantType)
+	if err != nil {
+		log.Errorf("unable to retrieve metrics of pods: %v", err)
+		return costs
+	}
+	costs[CurrentMonth] = metrics.CostCPU + metrics.CostMemory + metrics.CostStorage
+	costs[LastMonth] = metrics.LastMonthCPUCost + metrics.LastMonthMemoryCost + metrics.LastMonthStorageCost
+	costs[LastLastMonth] = metrics.LastLastMonthCPUCost + metrics.LastLastMonthMemoryCost + metrics.LastLastMonthStorageCost
+	return costs
+}
+
+// retrieveTenantPods returns uids of pods of each value of the label
+func retrieveTenantPods(ctx context.Context, label string) (map[string][]string, error) {
+	type root struct {
+		Tenants []struct {
+			Value string `json:"value"`
+			Pods  []struct {
+				UID string `json:"uid"`
+			} `json:"pods"`
+		} `json:"tenants"`
+	}
+	newRoot := root{}
+	err := executeQuery(ctx, getQueryForTenantPods(label), &newRoot)
+	if err != nil {
+		return nil, err
+	}
+	tenantPods := make(map[string][]string)
+	for _, tenant := range newRoot.Tenants {
+		for _, pod := range tenant.Pods {
+			tenantPods[tenant.Value] = append(tenantPods[tenant.Value], pod.UID)
+		}
+	}
+	return tenantPods, nil
+}
+
+// retrieveSharedPods returns uids of pods in shared namespaces which don't belong to any tenant
+func retrieveSharedPods(ctx context.Context, namespaces []string, tenantPods map[string][]string) ([]string, error) {
+	if len(namespaces) == 0 {
+		return nil, nil
+	}
+	type root struct {
+		Namespaces []struct {
+			Pods []struct {
+				UID string `json:"uid"`
+			} `json:"pods"`
+		} `json:"namespaces"`
+	}
+	newRoot := root{}
+	err := executeQuery(ctx, getQueryForPodsOfNamespaces(namespaces), &newRoot)
+	if err != nil {
+		return nil, err
+	}
+	isTenantPod := make(map[string]bool)
+	for _, pods := range tenantPods {
+		for _, uid := range pods {
+			isTenantPod[uid] = true
+		}
+	}
+	sharedPods := []string{}
+	for _, namespace := range newRoot.Namespaces {
+		for _, pod := range namespace.Pods {
+			if !isTenantPod[pod.UID] {
+				sharedPods = append(sharedPods, pod.UID)
+			}
+		}
+	}
+	return sharedPods, nil
+}
+
+func getQueryForTenantPods(label string) string {
+	return qb.New(
+		qb.Func("tenants", qb.Has("isLabel")).Filter(qb.Eq("key", label)).
+			Fields("value").
+			Child(qb.Edge("~label").Alias("pods").Filter(qb.Has("isPod")).Fields("uid")),
+	).String()
+}
+
+func getQueryForPodsOfNamespaces(namespaces []string) string {
+	filters := []qb.Filter{}
+	for _, namespace := range namespaces {
+		filters = append(filters, qb.Eq("name", "namespace-"+namespace))
+	}
+	return qb.New(
+		qb.Func("namespaces", qb.Has("isNamespace")).Filter(qb.Or(filters...)).
+			Child(qb.Edge("~namespace").Alias("pods").Filter(qb.Has("isPod")).Fields("uid")),
+	).String()
+}
@@ -0,0 +1,36 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestAllocateSharedCost ...
+func TestAllocateSharedCost(t *testing.T) {
+	got := allocateSharedCost(map[string]float64{"acme": 30, "globex": 10}, 8)
+	assert.Equal(t, map[string]float64{"acme": 6, "globex": 2}, got)
+
+	got = allocateSharedCost(map[string]float64{"acme": 0, "globex": 0}, 8)
+	assert.Equal(t, map[string]float64{"acme": 4, "globex": 4}, got)
+
+	got = allocateSharedCost(map[string]float64{}, 8)
+	assert.Equal(t, map[string]float64{}, got)
+}
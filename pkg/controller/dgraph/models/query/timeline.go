@@ -0,0 +1,329 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/vmware/purser/pkg/controller/dgraph/models"
+	qb "github.com/vmware/purser/pkg/querybuilder"
+)
+
+// Lifecycle phases of a pod in its timeline
+const (
+	// UnscheduledPhase is the time a pod is pending without a node, ex: it is unschedulable
+	UnscheduledPhase = "Unscheduled"
+	// PendingPhase is the time a pod is bound to a node but none of its containers started
+	PendingPhase = "Pending"
+	RunningPhase = "Running"
+	// TerminatedPhase is the time a pod completed (or failed) until it is deleted
+	TerminatedPhase = "Terminated"
+)
+
+// timelinePhases are the phases in the order a pod goes through them
+var timelinePhases = []string{UnscheduledPhase, PendingPhase, RunningPhase, TerminatedPhase}
+
+// PhaseInterval is a time interval a pod spent in a phase, cost is that of the resources it requested
+// during the interval. EndTime is empty if the pod is still in the phase.
+type PhaseInterval struct {
+	Phase     string  `json:"phase"`
+	StartTime string  `json:"startTime"`
+	EndTime   string  `json:"endTime,omitempty"`
+	Hours     float64 `json:"hours"`
+	Cost      float64 `json:"cost"`
+}
+
+// PhaseTotal is the time and cost of pods in a phase
+type PhaseTotal struct {
+	Phase string  `json:"phase"`
+	Hours float64 `json:"hours"`
+	Cost  float64 `json:"cost"`
+}
+
+// PodTimeline is the lifecycle of a pod, PendingCost is the cost of the pod before its containers
+// started, unscheduled time included
+type PodTimeline struct {
+	Name           string          `json:"name"`
+	Phase          string          `json:"phase,omitempty"`
+	StartTime      string          `json:"startTime,omitempty"`
+	ScheduledTime  string          `json:"scheduledTime,omitempty"`
+	RunningTime    string          `json:"runningTime,omitempty"`
+	TerminatedTime string          `json:"terminatedTime,omitempty"`
+	EndTime        string          `json:"endTime,omitempty"`
+	HourlyCost     float64         `json:"hourlyCost"`
+	PendingHours   float64         `json:"pendingHours"`
+	PendingCost    float64         `json:"pendingCost"`
+	Timeline       []PhaseInterval `json:"timeline"`
+	Phases         []PhaseTotal    `json:"phases"`
+}
+
+// WorkloadTimeline is the lifecycle of pods of a workload, phases are totals over its pods
+type WorkloadTimeline struct {
+	Name         string        `json:"name"`
+	Type         string        `json:"type"`
+	PendingHours float64       `json:"pendingHours"`
+	PendingCost  float64       `json:"pendingCost"`
+	Phases       []PhaseTotal  `json:"phases"`
+	Pods         []PodTimeline `json:"pods"`
+}
+
+// PodTimelineWrapper structure
+type PodTimelineWrapper struct {
+	Data PodTimeline `json:"data"`
+}
+
+// WorkloadTimelineWrapper structure
+type WorkloadTimelineWrapper struct {
+	Data WorkloadTimeline `json:"data"`
+}
+
+type timelinePod struct {
+	Name                  string  `json:"name"`
+	Phase                 string  `json:"phase"`
+	StartTime             string  `json:"startTime"`
+	ScheduledTime         string  `json:"scheduledTime"`
+	RunningTime           string  `json:"runningTime"`
+	TerminatedTime        string  `json:"terminatedTime"`
+	EndTime               string  `json:"endTime"`
+	CPURequest            float64 `json:"cpuRequest"`
+	MemoryRequest         float64 `json:"memoryRequest"`
+	StorageRequest        float64 `json:"storageRequest"`
+	GPURequest            float64 `json:"gpuRequest"`
+	CPUPrice              float64 `json:"cpuPrice"`
+	MemoryPrice           float64 `json:"memoryPrice"`
+	GPUPrice              float64 `json:"gpuPrice"`
+	ExtendedResourcePrice float64 `json:"extendedResourcePrice"`
+	BandwidthPrice        float64 `json:"bandwidthPrice"`
+}
+
+const timelinePodFields = `name
+			phase
+			startTime
+			scheduledTime
+			runningTime
+			terminatedTime
+			endTime
+			cpuRequest
+			memoryRequest
+			storageRequest
+			gpuRequest
+			cpuPrice
+			memoryPrice
+			gpuPrice
+			extendedResourcePrice
+			bandwidthPrice`
+
+type phaseTransition struct {
+	phase string
+	time  time.Time
+}
+
+// RetrievePodTimeline returns the time and cost of the pod in each phase of its lifecycle
+func RetrievePodTimeline(ctx context.Context, name string) PodTimelineWrapper {
+	root := struct {
+		Pods []timelinePod `json:"pods"`
+	}{}
+	vars := qb.Vars{"$name": name}
+	query := vars.Declaration() + ` {
+		pods(func: has(isPod)) @filter(eq(name, $name)) {
+			` + timelinePodFields + `
+		}
+	}`
+	err := executeQueryWithVars(ctx, query, vars, &root)
+	if err != nil || len(root.Pods) == 0 {
+		log.Errorf("unable to retrieve timeline of pod: %s, err: %v", name, err)
+		return PodTimelineWrapper{}
+	}
+	return PodTimelineWrapper{Data: getPodTimeline(root.Pods[0], time.Now())}
+}
+
+// RetrieveWorkloadTimeline returns the timelines of pods of the workload and the time and cost they spent
+// in each phase, cost while pending and unschedulable included
+func RetrieveWorkloadTimeline(ctx context.Context, name string) WorkloadTimelineWrapper {
+	workloadType := strings.SplitN(name, "-", 2)[0]
+	if _, isWorkload := workloadChecks[workloadType]; !isWorkload {
+		log.Errorf("unable to retrieve timeline, %s is not a workload", name)
+		return WorkloadTimelineWrapper{}
+	}
+	root := struct {
+		Workload []struct {
+			Pods []timelinePod `json:"pods"`
+		} `json:"workload"`
+	}{}
+	vars := qb.Vars{"$name": name}
+	query := vars.Declaration() + ` {
+		workload(func: has(` + workloadChecks[workloadType] + `)) @filter(eq(name, $name)) {
+			pods: ~` + workloadType + ` @filter(has(isPod)) {
+				` + timelinePodFields + `
+			}
+		}
+	}`
+	err := executeQueryWithVars(ctx, query, vars, &root)
+	if err != nil || len(root.Workload) == 0 {
+		log.Errorf("unable to retrieve pods of workload: %s, err: %v", name, err)
+		return WorkloadTimelineWrapper{}
+	}
+
+	now := time.Now()
+	data := WorkloadTimeline{Name: name, Type: workloadType, Pods: []PodTimeline{}}
+	totals := map[string]*PhaseTotal{}
+	for _, pod := range root.Workload[0].Pods {
+		timeline := getPodTimeline(pod, now)
+		data.Pods = append(data.Pods, timeline)
+		data.PendingHours += timeline.PendingHours
+		data.PendingCost += timeline.PendingCost
+		for _, phase := range timeline.Phases {
+			addToPhaseTotal(totals, phase)
+		}
+	}
+	data.Phases = getPhaseTotals(totals)
+	return WorkloadTimelineWrapper{Data: data}
+}
+
+// getPodTimeline splits the lifetime of the pod into intervals of its phases, pods still alive are
+// considered in their last phase until now
+func getPodTimeline(pod timelinePod, now time.Time) PodTimeline {
+	timeline := PodTimeline{
+		Name:           pod.Name,
+		Phase:          pod.Phase,
+		StartTime:      pod.StartTime,
+		ScheduledTime:  pod.ScheduledTime,
+		RunningTime:    pod.RunningTime,
+		TerminatedTime: pod.TerminatedTime,
+		EndTime:        pod.EndTime,
+		HourlyCost:     getHourlyCost(pod),
+		Timeline:       []PhaseInterval{},
+	}
+	end, err := time.Parse(time.RFC3339, pod.EndTime)
+	isAlive := err != nil
+	if isAlive {
+		end = now
+	}
+
+	totals := map[string]*PhaseTotal{}
+	transitions := getPhaseTransitions(pod)
+	for index, transition := range transitions {
+		intervalEnd := end
+		isLast := index == len(transitions)-1
+		if !isLast && transitions[index+1].time.Before(end) {
+			intervalEnd = transitions[index+1].time
+		}
+		if !intervalEnd.After(transition.time) {
+			continue
+		}
+		hours := intervalEnd.Sub(transition.time).Hours()
+		interval := PhaseInterval{
+			Phase:     transition.phase,
+			StartTime: transition.time.Format(time.RFC3339),
+			Hours:     hours,
+			Cost:      getIntervalCost(pod, hours),
+		}
+		if !isLast || !isAlive {
+			interval.EndTime = intervalEnd.Format(time.RFC3339)
+		}
+		timeline.Timeline = append(timeline.Timeline, interval)
+		addToPhaseTotal(totals, PhaseTotal{Phase: interval.Phase, Hours: interval.Hours, Cost: interval.Cost})
+		if interval.Phase == UnscheduledPhase || interval.Phase == PendingPhase {
+			timeline.PendingHours += interval.Hours
+			timeline.PendingCost += interval.Cost
+		}
+	}
+	timeline.Phases = getPhaseTotals(totals)
+	return timeline
+}
+
+// getPhaseTransitions returns the phases the pod went through with their start times. Pods stored before
+// phases were recorded have no transition times and are considered running since their start.
+func getPhaseTransitions(pod timelinePod) []phaseTransition {
+	startTime, err := time.Parse(time.RFC3339, pod.StartTime)
+	if err != nil {
+		return nil
+	}
+	first := RunningPhase
+	if pod.ScheduledTime != "" || pod.Phase == PendingPhase {
+		first = UnscheduledPhase
+	} else if pod.RunningTime != "" {
+		// scheduling of the pod isn't recorded
+		first = PendingPhase
+	}
+	transitions := []phaseTransition{{first, startTime}}
+	for _, next := range []phaseTransition{
+		{PendingPhase, parseTime(pod.ScheduledTime)},
+		{RunningPhase, parseTime(pod.RunningTime)},
+		{TerminatedPhase, parseTime(pod.TerminatedTime)},
+	} {
+		last := transitions[len(transitions)-1]
+		if next.time.IsZero() || next.phase == last.phase {
+			continue
+		}
+		if next.time.Before(last.time) {
+			next.time = last.time
+		}
+		transitions = append(transitions, next)
+	}
+	return transitions
+}
+
+// getHourlyCost returns the adjusted cost of an hour of the resources requested by the pod
+func getHourlyCost(pod timelinePod) float64 {
+	return getIntervalCost(pod, 1)
+}
+
+// getIntervalCost returns the adjusted cost of the resources requested by the pod for the given hours
+func getIntervalCost(pod timelinePod, hours float64) float64 {
+	return adjustCost(CostContext{PodType, pod.Name, CPUCostType}, pod.CPURequest*pod.CPUPrice*hours) +
+		adjustCost(CostContext{PodType, pod.Name, MemoryCostType}, pod.MemoryRequest*pod.MemoryPrice*hours) +
+		adjustCost(CostContext{PodType, pod.Name, StorageCostType}, pod.StorageRequest*models.DefaultStorageCostInFloat64*hours) +
+		adjustCost(CostContext{PodType, pod.Name, GPUCostType}, pod.GPURequest*pod.GPUPrice*hours) +
+		adjustCost(CostContext{PodType, pod.Name, ExtendedResourceCostType}, pod.ExtendedResourcePrice*hours) +
+		adjustCost(CostContext{PodType, pod.Name, BandwidthCostType}, pod.BandwidthPrice*hours)
+}
+
+func addToPhaseTotal(totals map[string]*PhaseTotal, phase PhaseTotal) {
+	total, isPresent := totals[phase.Phase]
+	if !isPresent {
+		total = &PhaseTotal{Phase: phase.Phase}
+		totals[phase.Phase] = total
+	}
+	total.Hours += phase.Hours
+	total.Cost += phase.Cost
+}
+
+// getPhaseTotals returns totals of all phases in lifecycle order, phases without time are zero
+func getPhaseTotals(totals map[string]*PhaseTotal) []PhaseTotal {
+	var phases []PhaseTotal
+	for _, phase := range timelinePhases {
+		total := PhaseTotal{Phase: phase}
+		if totals[phase] != nil {
+			total = *totals[phase]
+		}
+		phases = append(phases, total)
+	}
+	return phases
+}
+
+// parseTime returns zero time if t isn't in RFC3339
+func parseTime(t string) time.Time {
+	parsed, err := time.Parse(time.RFC3339, t)
+	if err != nil {
+		return time.Time{}
+	}
+	return parsed
+}
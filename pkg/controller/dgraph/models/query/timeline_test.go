@@ -0,0 +1,123 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var timelineNow = time.Date(2018, 10, 2, 0, 0, 0, 0, time.UTC)
+
+func mockDgraphForWorkloadTimeline() {
+	executeQueryWithVars = func(ctx context.Context, query string, vars map[string]string, root interface{}) error {
+		if !strings.Contains(query, `workload(func: has(isJob)) @filter(eq(name, $name))`) || vars["$name"] != "job-batch" {
+			return json.Unmarshal([]byte(`{"workload": []}`), root)
+		}
+		return json.Unmarshal([]byte(`{"workload": [{"pods": [
+			{"name": "pod-batch-1", "phase": "Succeeded", "startTime": "2018-10-01T00:00:00Z", "scheduledTime": "2018-10-01T02:00:00Z",
+				"runningTime": "2018-10-01T03:00:00Z", "terminatedTime": "2018-10-01T05:00:00Z", "endTime": "2018-10-01T06:00:00Z", "cpuRequest": 1, "cpuPrice": 1},
+			{"name": "pod-batch-2", "phase": "Pending", "startTime": "2018-10-01T00:00:00Z", "cpuRequest": 2, "cpuPrice": 1, "endTime": "2018-10-01T04:00:00Z"}
+		]}]}`), root)
+	}
+}
+
+// TestGetPodTimeline ...
+func TestGetPodTimeline(t *testing.T) {
+	got := getPodTimeline(timelinePod{
+		Name:          "pod-web",
+		Phase:         "Running",
+		StartTime:     "2018-10-01T00:00:00Z",
+		ScheduledTime: "2018-10-01T01:00:00Z",
+		RunningTime:   "2018-10-01T01:30:00Z",
+		CPURequest:    1,
+		CPUPrice:      0.5,
+		MemoryRequest: 2,
+		MemoryPrice:   0.25,
+	}, timelineNow)
+	assert.Equal(t, 1.0, got.HourlyCost)
+	assert.Equal(t, 1.5, got.PendingHours)
+	assert.Equal(t, 1.5, got.PendingCost)
+	assert.Equal(t, []PhaseInterval{
+		{Phase: UnscheduledPhase, StartTime: "2018-10-01T00:00:00Z", EndTime: "2018-10-01T01:00:00Z", Hours: 1, Cost: 1},
+		{Phase: PendingPhase, StartTime: "2018-10-01T01:00:00Z", EndTime: "2018-10-01T01:30:00Z", Hours: 0.5, Cost: 0.5},
+		{Phase: RunningPhase, StartTime: "2018-10-01T01:30:00Z", Hours: 22.5, Cost: 22.5},
+	}, got.Timeline)
+	assert.Equal(t, []PhaseTotal{
+		{Phase: UnscheduledPhase, Hours: 1, Cost: 1},
+		{Phase: PendingPhase, Hours: 0.5, Cost: 0.5},
+		{Phase: RunningPhase, Hours: 22.5, Cost: 22.5},
+		{Phase: TerminatedPhase},
+	}, got.Phases)
+}
+
+// TestGetPodTimelineWithoutPhases ...
+func TestGetPodTimelineWithoutPhases(t *testing.T) {
+	got := getPodTimeline(timelinePod{
+		Name:      "pod-web",
+		StartTime: "2018-10-01T00:00:00Z",
+		EndTime:   "2018-10-01T10:00:00Z",
+	}, timelineNow)
+	assert.Equal(t, []PhaseInterval{
+		{Phase: RunningPhase, StartTime: "2018-10-01T00:00:00Z", EndTime: "2018-10-01T10:00:00Z", Hours: 10},
+	}, got.Timeline)
+	assert.Equal(t, 0.0, got.PendingHours)
+}
+
+// TestGetPhaseTransitions ...
+func TestGetPhaseTransitions(t *testing.T) {
+	assert.Equal(t, 0, len(getPhaseTransitions(timelinePod{})))
+
+	unscheduled := getPhaseTransitions(timelinePod{Phase: "Pending", StartTime: "2018-10-01T00:00:00Z"})
+	assert.Equal(t, 1, len(unscheduled))
+	assert.Equal(t, UnscheduledPhase, unscheduled[0].phase)
+
+	notScheduled := getPhaseTransitions(timelinePod{Phase: "Running", StartTime: "2018-10-01T00:00:00Z", RunningTime: "2018-10-01T00:01:00Z"})
+	assert.Equal(t, 2, len(notScheduled))
+	assert.Equal(t, PendingPhase, notScheduled[0].phase)
+	assert.Equal(t, RunningPhase, notScheduled[1].phase)
+
+	// transitions recorded before pod start are clamped to the start
+	clamped := getPhaseTransitions(timelinePod{Phase: "Running", StartTime: "2018-10-01T00:01:00Z", ScheduledTime: "2018-10-01T00:00:00Z"})
+	assert.Equal(t, clamped[0].time, clamped[1].time)
+}
+
+// TestRetrieveWorkloadTimeline ...
+func TestRetrieveWorkloadTimeline(t *testing.T) {
+	mockDgraphForWorkloadTimeline()
+	got := RetrieveWorkloadTimeline(context.Background(), "job-batch").Data
+	assert.Equal(t, "job-batch", got.Name)
+	assert.Equal(t, JobType, got.Type)
+	assert.Equal(t, 2, len(got.Pods))
+	assert.Equal(t, 7.0, got.PendingHours)
+	assert.Equal(t, 11.0, got.PendingCost)
+	assert.Equal(t, []PhaseTotal{
+		{Phase: UnscheduledPhase, Hours: 6, Cost: 10},
+		{Phase: PendingPhase, Hours: 1, Cost: 1},
+		{Phase: RunningPhase, Hours: 2, Cost: 2},
+		{Phase: TerminatedPhase, Hours: 1, Cost: 1},
+	}, got.Phases)
+
+	assert.Equal(t, WorkloadTimeline{}, RetrieveWorkloadTimeline(context.Background(), "node-1").Data)
+	assert.Equal(t, WorkloadTimeline{}, RetrieveWorkloadTimeline(context.Background(), "job-missing").Data)
+}
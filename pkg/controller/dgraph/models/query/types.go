@@ -19,48 +19,113 @@ package query
 
 // Constants used in query parameters
 const (
-	All      = ""
-	Name     = "name"
-	Orphan   = "orphan"
-	View     = "view"
-	Physical = "physical"
-	Logical  = "logical"
-	False    = "false"
+	All       = ""
+	Name      = "name"
+	Namespace = "namespace"
+	Orphan    = "orphan"
+	View      = "view"
+	Physical  = "physical"
+	Logical   = "logical"
+	False     = "false"
+	True      = "true"
+	Start     = "start"
+	End       = "end"
+	First     = "first"
+	Offset    = "offset"
+	After     = "after"
+	OS        = "os"
+	Linux     = "linux"
+	Windows   = "windows"
+	AsOf      = "asOf"
+	Predicate = "predicate"
+	Match     = "match"
+	GroupBy   = "groupBy"
+	Kind      = "kind"
+	Include   = "include"
+	Exclude   = "exclude"
+	Only      = "only"
+	CostMode  = "costMode"
+	Request   = "request"
+	Usage     = "usage"
+	Budget    = "budget"
+	Owner     = "owner"
+	Format    = "format"
+	CSV       = "csv"
+	JSON      = "json"
+	Entity    = "entity"
+	Intervals = "intervals"
+	Type      = "type"
+	Component = "component"
+	Level     = "level"
+	Cluster   = "cluster"
 )
 
 // Children structure
 type Children struct {
-	Name        string  `json:"name,omitempty"`
-	Type        string  `json:"type,omitempty"`
-	CPU         float64 `json:"cpu,omitempty"`
-	Memory      float64 `json:"memory,omitempty"`
-	Storage     float64 `json:"storage,omitempty"`
-	CPUCost     float64 `json:"cpuCost,omitempty"`
-	MemoryCost  float64 `json:"memoryCost,omitempty"`
-	StorageCost float64 `json:"storageCost,omitempty"`
+	UID                  string  `json:"uid,omitempty"`
+	Name                 string  `json:"name,omitempty"`
+	Type                 string  `json:"type,omitempty"`
+	CPU                  float64 `json:"cpu,omitempty"`
+	Memory               float64 `json:"memory,omitempty"`
+	Storage              float64 `json:"storage,omitempty"`
+	CPUCost              float64 `json:"cpuCost,omitempty"`
+	MemoryCost           float64 `json:"memoryCost,omitempty"`
+	StorageCost          float64 `json:"storageCost,omitempty"`
+	EphemeralStorage     float64 `json:"ephemeralStorage,omitempty"`
+	EphemeralStorageCost float64 `json:"ephemeralStorageCost,omitempty"`
+	Hugepages            float64 `json:"hugepages,omitempty"`
+	HugepagesCost        float64 `json:"hugepagesCost,omitempty"`
+	GPU                  float64 `json:"gpu,omitempty"`
+	GPUCost              float64 `json:"gpuCost,omitempty"`
+	ExtendedResourceCost float64 `json:"extendedResourceCost,omitempty"`
+	BandwidthCost        float64 `json:"bandwidthCost,omitempty"`
+	Carbon               float64 `json:"carbon,omitempty"`
+	Energy               float64 `json:"energy,omitempty"`
+	CPUUsage             float64 `json:"cpuUsage,omitempty"`
+	MemoryUsage          float64 `json:"memoryUsage,omitempty"`
+	UtilizedCPUCost      float64 `json:"utilizedCPUCost,omitempty"`
+	UtilizedMemoryCost   float64 `json:"utilizedMemoryCost,omitempty"`
+	SyntheticRequests    bool    `json:"syntheticRequests,omitempty"`
 }
 
 // ParentWrapper structure
 type ParentWrapper struct {
-	Name             string          `json:"name,omitempty"`
-	Type             string          `json:"type,omitempty"`
-	Children         []Children      `json:"children,omitempty"`
-	Parent           []ParentWrapper `json:"parent,omitempty"`
-	CPU              float64         `json:"cpu,omitempty"`
-	Memory           float64         `json:"memory,omitempty"`
-	Storage          float64         `json:"storage,omitempty"`
-	CPUCost          float64         `json:"cpuCost,omitempty"`
-	MemoryCost       float64         `json:"memoryCost,omitempty"`
-	StorageCost      float64         `json:"storageCost,omitempty"`
-	CPUAllocated     float64         `json:"cpuAllocated,omitempty"`
-	MemoryAllocated  float64         `json:"memoryAllocated,omitempty"`
-	StorageAllocated float64         `json:"storageAllocated,omitempty"`
-	CPUCapacity      float64         `json:"cpuCapacity,omitempty"`
-	MemoryCapacity   float64         `json:"memoryCapacity,omitempty"`
-	StorageCapacity  float64         `json:"storageCapacity,omitempty"`
+	Name                 string          `json:"name,omitempty"`
+	Type                 string          `json:"type,omitempty"`
+	Children             []Children      `json:"children,omitempty"`
+	Groups               []WorkloadGroup `json:"groups,omitempty"`
+	Parent               []ParentWrapper `json:"parent,omitempty"`
+	CPU                  float64         `json:"cpu,omitempty"`
+	Memory               float64         `json:"memory,omitempty"`
+	Storage              float64         `json:"storage,omitempty"`
+	CPUCost              float64         `json:"cpuCost,omitempty"`
+	MemoryCost           float64         `json:"memoryCost,omitempty"`
+	StorageCost          float64         `json:"storageCost,omitempty"`
+	EphemeralStorage     float64         `json:"ephemeralStorage,omitempty"`
+	EphemeralStorageCost float64         `json:"ephemeralStorageCost,omitempty"`
+	Hugepages            float64         `json:"hugepages,omitempty"`
+	HugepagesCost        float64         `json:"hugepagesCost,omitempty"`
+	GPU                  float64         `json:"gpu,omitempty"`
+	GPUCost              float64         `json:"gpuCost,omitempty"`
+	ExtendedResourceCost float64         `json:"extendedResourceCost,omitempty"`
+	BandwidthCost        float64         `json:"bandwidthCost,omitempty"`
+	Carbon               float64         `json:"carbon,omitempty"`
+	Energy               float64         `json:"energy,omitempty"`
+	CPUUsage             float64         `json:"cpuUsage,omitempty"`
+	MemoryUsage          float64         `json:"memoryUsage,omitempty"`
+	UtilizedCPUCost      float64         `json:"utilizedCPUCost,omitempty"`
+	UtilizedMemoryCost   float64         `json:"utilizedMemoryCost,omitempty"`
+	CPUAllocated         float64         `json:"cpuAllocated,omitempty"`
+	MemoryAllocated      float64         `json:"memoryAllocated,omitempty"`
+	StorageAllocated     float64         `json:"storageAllocated,omitempty"`
+	CPUCapacity          float64         `json:"cpuCapacity,omitempty"`
+	MemoryCapacity       float64         `json:"memoryCapacity,omitempty"`
+	StorageCapacity      float64         `json:"storageCapacity,omitempty"`
+	SyntheticRequests    bool            `json:"syntheticRequests,omitempty"`
 }
 
 // JSONDataWrapper structure
 type JSONDataWrapper struct {
 	Data ParentWrapper `json:"data,omitempty"`
+	Page *PageInfo     `json:"page,omitempty"`
 }
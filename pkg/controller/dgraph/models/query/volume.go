@@ -0,0 +1,151 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"context"
+	"math"
+	"sort"
+
+	"github.com/vmware/purser/pkg/controller/dgraph/models"
+	qb "github.com/vmware/purser/pkg/querybuilder"
+)
+
+// Volume sizing constants
+const (
+	// OverProvisionedUtilization is the peak utilization of provisioned size below which a volume is over-provisioned
+	OverProvisionedUtilization = 0.5
+	// VolumeHeadroom is the fraction of peak usage added to the recommended size
+	VolumeHeadroom = 0.2
+)
+
+// VolumeUsage is the usage of the volume of a pvc, sizes are in GB. MonthlySavings is the storage cost saved
+// in a month if the volume is resized to RecommendedSize.
+type VolumeUsage struct {
+	Name            string  `json:"name"`
+	Namespace       string  `json:"namespace"`
+	Provisioned     float64 `json:"provisioned"`
+	Used            float64 `json:"used"`
+	PeakUsed        float64 `json:"peakUsed"`
+	Utilization     float64 `json:"utilization"`
+	RecommendedSize float64 `json:"recommendedSize"`
+	MonthlyCost     float64 `json:"monthlyCost"`
+	MonthlySavings  float64 `json:"monthlySavings"`
+	UsageTime       string  `json:"usageTime"`
+}
+
+// OverProvisionedVolumes structure
+type OverProvisionedVolumes struct {
+	Volumes        []VolumeUsage `json:"volumes"`
+	Provisioned    float64       `json:"provisioned"`
+	PeakUsed       float64       `json:"peakUsed"`
+	MonthlySavings float64       `json:"monthlySavings"`
+}
+
+// OverProvisionedVolumesWrapper structure
+type OverProvisionedVolumesWrapper struct {
+	Data OverProvisionedVolumes `json:"data"`
+}
+
+type pvcUsage struct {
+	Name      string `json:"name"`
+	Namespace *struct {
+		Name string `json:"name"`
+	} `json:"namespace"`
+	StorageCapacity float64 `json:"storageCapacity"`
+	StorageUsed     float64 `json:"storageUsed"`
+	StorageUsedPeak float64 `json:"storageUsedPeak"`
+	VolumeCapacity  float64 `json:"volumeCapacity"`
+	UsageTime       string  `json:"usageTime"`
+	StoragePrice    float64 `json:"storagePrice"`
+}
+
+// RetrieveOverProvisionedVolumes returns live pvcs whose peak usage reported by kubelet volume stats is below
+// OverProvisionedUtilization of their provisioned size, with the savings if resized, highest savings first
+func RetrieveOverProvisionedVolumes(ctx context.Context) OverProvisionedVolumesWrapper {
+	type root struct {
+		Pvcs []pvcUsage `json:"pvcs"`
+	}
+	newRoot := root{}
+	err := executeQuery(ctx, getQueryForVolumeUsage(), &newRoot)
+	if err != nil {
+		log.Errorf("unable to retrieve usage of volumes, err: %v", err)
+		return OverProvisionedVolumesWrapper{}
+	}
+
+	data := OverProvisionedVolumes{Volumes: []VolumeUsage{}}
+	for _, pvc := range newRoot.Pvcs {
+		volume := getVolumeUsage(pvc)
+		if volume.Utilization >= OverProvisionedUtilization || volume.MonthlySavings <= 0 {
+			continue
+		}
+		data.Volumes = append(data.Volumes, volume)
+		data.Provisioned += volume.Provisioned
+		data.PeakUsed += volume.PeakUsed
+		data.MonthlySavings += volume.MonthlySavings
+	}
+	sort.Slice(data.Volumes, func(i, j int) bool {
+		return data.Volumes[i].MonthlySavings > data.Volumes[j].MonthlySavings
+	})
+	return OverProvisionedVolumesWrapper{Data: data}
+}
+
+func getQueryForVolumeUsage() string {
+	return qb.New(
+		qb.Func("pvcs", qb.Has(PVCCheck)).Filter(qb.And(qb.Has("storageUsed"), qb.Not(qb.Has("endTime")))).
+			Fields("name", "storageCapacity", "storageUsed", "storageUsedPeak", "volumeCapacity", "usageTime", "storagePrice").
+			Child(qb.Edge("namespace").Fields("name")),
+	).String()
+}
+
+// getVolumeUsage computes utilization of provisioned size(capacity of the volume if the pvc has none) and the
+// recommended size, peak usage with VolumeHeadroom rounded up to a GB
+func getVolumeUsage(pvc pvcUsage) VolumeUsage {
+	volume := VolumeUsage{
+		Name:        pvc.Name,
+		Provisioned: pvc.StorageCapacity,
+		Used:        pvc.StorageUsed,
+		PeakUsed:    math.Max(pvc.StorageUsedPeak, pvc.StorageUsed),
+		UsageTime:   pvc.UsageTime,
+	}
+	if pvc.Namespace != nil {
+		volume.Namespace = pvc.Namespace.Name
+	}
+	if volume.Provisioned == 0 {
+		volume.Provisioned = pvc.VolumeCapacity
+	}
+	if volume.Provisioned == 0 {
+		return volume
+	}
+	volume.Utilization = volume.PeakUsed / volume.Provisioned
+	volume.RecommendedSize = math.Max(1, math.Ceil(volume.PeakUsed*(1+VolumeHeadroom)))
+	volume.MonthlyCost = getMonthlyStorageCost(pvc.Name, volume.Provisioned, pvc.StoragePrice)
+	if volume.RecommendedSize < volume.Provisioned {
+		volume.MonthlySavings = volume.MonthlyCost - getMonthlyStorageCost(pvc.Name, volume.RecommendedSize, pvc.StoragePrice)
+	}
+	return volume
+}
+
+// getMonthlyStorageCost prices the size with the storage price of the pvc, DefaultStorageCostInFloat64 if it has none
+func getMonthlyStorageCost(name string, size, price float64) float64 {
+	if price == 0 {
+		price = models.DefaultStorageCostInFloat64
+	}
+	cost := size * price * models.HoursInMonth
+	return adjustCost(CostContext{PVCType, name, StorageCostType}, cost)
+}
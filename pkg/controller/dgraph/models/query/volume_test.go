@@ -0,0 +1,64 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func mockDgraphForVolumeUsage() {
+	executeQuery = func(ctx context.Context, query string, root interface{}) error {
+		return json.Unmarshal([]byte(`{"pvcs": [
+			{"name": "pvc-data", "namespace": {"name": "default"}, "storageCapacity": 100, "storageUsed": 8, "storageUsedPeak": 10, "usageTime": "2018-10-14T10:00:00Z"},
+			{"name": "pvc-logs", "namespace": {"name": "default"}, "volumeCapacity": 20, "storageUsed": 2.5, "storageUsedPeak": 2},
+			{"name": "pvc-cache", "namespace": {"name": "default"}, "storageCapacity": 10, "storageUsed": 6, "storageUsedPeak": 6},
+			{"name": "pvc-tiny", "namespace": {"name": "default"}, "storageCapacity": 1, "storageUsed": 0.1},
+			{"name": "pvc-unbound", "storageUsed": 1}
+		]}`), root)
+	}
+}
+
+// TestRetrieveOverProvisionedVolumes ...
+func TestRetrieveOverProvisionedVolumes(t *testing.T) {
+	mockDgraphForVolumeUsage()
+	got := RetrieveOverProvisionedVolumes(context.Background()).Data
+
+	assert.Equal(t, 2, len(got.Volumes))
+	data := got.Volumes[0]
+	assert.Equal(t, "pvc-data", data.Name)
+	assert.Equal(t, "default", data.Namespace)
+	assert.Equal(t, 0.1, data.Utilization)
+	assert.Equal(t, 12.0, data.RecommendedSize)
+	assert.InDelta(t, 10, data.MonthlyCost, 0.001)
+	assert.InDelta(t, 8.8, data.MonthlySavings, 0.001)
+	assert.Equal(t, "2018-10-14T10:00:00Z", data.UsageTime)
+
+	logs := got.Volumes[1]
+	assert.Equal(t, "pvc-logs", logs.Name)
+	assert.Equal(t, 20.0, logs.Provisioned)
+	assert.Equal(t, 2.5, logs.PeakUsed)
+	assert.Equal(t, 3.0, logs.RecommendedSize)
+
+	assert.Equal(t, 120.0, got.Provisioned)
+	assert.Equal(t, 12.5, got.PeakUsed)
+	assert.InDelta(t, 8.8+1.7, got.MonthlySavings, 0.001)
+}
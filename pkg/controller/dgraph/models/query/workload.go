@@ -0,0 +1,196 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"context"
+	qb "github.com/vmware/purser/pkg/querybuilder"
+)
+
+// barePodFilter selects pods which are not owned by any workload
+const barePodFilter = "has(isPod) AND NOT has(replicaset) AND NOT has(statefulset) AND NOT has(job) AND NOT has(daemonset) AND NOT has(deploymentconfig)"
+
+// workloadKinds are types of namespace children in the order their groups are returned with the group name,
+// replicasets and pods are only those not owned by a deployment and by any workload respectively
+var workloadKinds = []struct {
+	kind string
+	name string
+}{
+	{DeploymentType, "Deployments"},
+	{StatefulsetType, "StatefulSets"},
+	{DaemonsetType, "DaemonSets"},
+	{JobType, "Jobs"},
+	{DeploymentConfigType, "DeploymentConfigs"},
+	{ReplicasetType, "ReplicaSets"},
+	{PodType, "Pods"},
+}
+
+// WorkloadGroup is a group of namespace children of the same kind with their subtotal metrics
+type WorkloadGroup struct {
+	Children
+	Count     int        `json:"count"`
+	Workloads []Children `json:"children"`
+}
+
+// RetrieveDeploymentMetrics returns cost of the deployment(ex: deployment-web) between start and end of the time
+// range with its replicasets as children, costs of pods are rolled up to their replicaset and the deployment. The
+// deployment is looked up in the namespace(ex: namespace-shop) if it is given.
+func RetrieveDeploymentMetrics(ctx context.Context, name, namespace string, timeRange TimeRange) JSONDataWrapper {
+	return retrieveWorkloadMetrics(ctx, DeploymentCheck, DeploymentType, name, namespace, timeRange)
+}
+
+// RetrieveStatefulSetMetrics returns cost of the statefulset between start and end of the time range with its pods
+// as children, like RetrieveDeploymentMetrics
+func RetrieveStatefulSetMetrics(ctx context.Context, name, namespace string, timeRange TimeRange) JSONDataWrapper {
+	return retrieveWorkloadMetrics(ctx, StatefulsetCheck, StatefulsetType, name, namespace, timeRange)
+}
+
+// RetrieveDaemonSetMetrics returns cost of the daemonset between start and end of the time range with its pods as
+// children, like RetrieveDeploymentMetrics
+func RetrieveDaemonSetMetrics(ctx context.Context, name, namespace string, timeRange TimeRange) JSONDataWrapper {
+	return retrieveWorkloadMetrics(ctx, DaemonsetCheck, DaemonsetType, name, namespace, timeRange)
+}
+
+// RetrieveJobMetrics returns cost of the job between start and end of the time range with its pods as children,
+// like RetrieveDeploymentMetrics
+func RetrieveJobMetrics(ctx context.Context, name, namespace string, timeRange TimeRange) JSONDataWrapper {
+	return retrieveWorkloadMetrics(ctx, JobCheck, JobType, name, namespace, timeRange)
+}
+
+// RetrieveCronJobMetrics returns cost of the cronjob(ex: cronjob-backup) between start and end of the time range
+// with its jobs as children, costs of pods of every job run in the time range are rolled up to their job and the
+// cronjob like RetrieveDeploymentMetrics, so a time range of a month gives the monthly cost of the schedule
+func RetrieveCronJobMetrics(ctx context.Context, name, namespace string, timeRange TimeRange) JSONDataWrapper {
+	return retrieveWorkloadMetrics(ctx, CronJobCheck, CronJobType, name, namespace, timeRange)
+}
+
+func retrieveWorkloadMetrics(ctx context.Context, check, resourceType, name, namespace string, timeRange TimeRange) JSONDataWrapper {
+	resource := Resource{
+		Check:     check,
+		Type:      resourceType,
+		Name:      name,
+		Namespace: namespace,
+		Start:     timeRange.Start,
+		End:       timeRange.End,
+	}
+	return resource.RetrieveResourceMetrics(ctx)
+}
+
+// groupNamespaceChildren replaces children of the namespace with groups of children by kind, pods which are not
+// owned by any workload are added as another group and their metrics are added to the namespace
+func (r *Resource) groupNamespaceChildren(ctx context.Context, namespace *ParentWrapper) {
+	if namespace.Name == "" {
+		return
+	}
+	query, vars := getQueryForNamespaceBarePodMetrics(r.Name, r.OS, r.AsOf)
+	barePods := getJSONDataFromQuery(ctx, query, vars).Data.Children
+	namespace.Groups = groupChildrenByKind(append(namespace.Children, barePods...))
+	namespace.Children = nil
+	for _, group := range namespace.Groups {
+		if group.Type == PodType {
+			addGroupMetricsToParent(namespace, group.Children)
+		}
+	}
+}
+
+// getQueryForNamespaceBarePodMetrics returns query for metrics of pods of the namespace which are not owned by any workload
+func getQueryForNamespaceBarePodMetrics(name, os, asOf string) (string, qb.Vars) {
+	vars := qb.Vars{"$name": name}
+	return vars.Declaration() + ` {
+		parent(func: has(isNamespace)) @filter(eq(name, $name)) {
+			name
+			type
+			children: ~namespace @filter(` + barePodFilter + getOSFilter(os) + getAsOfFilter(asOf) + `) {
+				` + getQueryForMetricsComputationWithAliasAsOf("BarePod", asOf) + `
+			}
+		}
+	}`, vars
+}
+
+// groupChildrenByKind returns non empty groups of children by their type in the order of workloadKinds,
+// children of other types are grouped after them by type
+func groupChildrenByKind(children []Children) []WorkloadGroup {
+	groups := []WorkloadGroup{}
+	indices := make(map[string]int)
+	for _, workloadKind := range workloadKinds {
+		indices[workloadKind.kind] = len(groups)
+		groups = append(groups, WorkloadGroup{Children: Children{Name: workloadKind.name, Type: workloadKind.kind}, Workloads: []Children{}})
+	}
+	for _, child := range children {
+		index, isPresent := indices[child.Type]
+		if !isPresent {
+			index = len(groups)
+			indices[child.Type] = index
+			groups = append(groups, WorkloadGroup{Children: Children{Name: child.Type, Type: child.Type}, Workloads: []Children{}})
+		}
+		group := &groups[index]
+		group.Count++
+		group.Workloads = append(group.Workloads, child)
+		addChildMetrics(&group.Children, child)
+	}
+
+	nonEmpty := []WorkloadGroup{}
+	for _, group := range groups {
+		if group.Count > 0 {
+			nonEmpty = append(nonEmpty, group)
+		}
+	}
+	return nonEmpty
+}
+
+func addChildMetrics(total *Children, child Children) {
+	total.CPU += child.CPU
+	total.Memory += child.Memory
+	total.Storage += child.Storage
+	total.CPUCost += child.CPUCost
+	total.MemoryCost += child.MemoryCost
+	total.StorageCost += child.StorageCost
+	total.EphemeralStorage += child.EphemeralStorage
+	total.EphemeralStorageCost += child.EphemeralStorageCost
+	total.Hugepages += child.Hugepages
+	total.HugepagesCost += child.HugepagesCost
+	total.GPU += child.GPU
+	total.GPUCost += child.GPUCost
+	total.ExtendedResourceCost += child.ExtendedResourceCost
+	total.BandwidthCost += child.BandwidthCost
+	total.Carbon += child.Carbon
+	total.Energy += child.Energy
+	total.UtilizedCPUCost += child.UtilizedCPUCost
+	total.UtilizedMemoryCost += child.UtilizedMemoryCost
+}
+
+func addGroupMetricsToParent(parent *ParentWrapper, group Children) {
+	parent.CPU += group.CPU
+	parent.Memory += group.Memory
+	parent.Storage += group.Storage
+	parent.CPUCost += group.CPUCost
+	parent.MemoryCost += group.MemoryCost
+	parent.StorageCost += group.StorageCost
+	parent.EphemeralStorage += group.EphemeralStorage
+	parent.EphemeralStorageCost += group.EphemeralStorageCost
+	parent.Hugepages += group.Hugepages
+	parent.HugepagesCost += group.HugepagesCost
+	parent.GPU += group.GPU
+	parent.GPUCost += group.GPUCost
+	parent.ExtendedResourceCost += group.ExtendedResourceCost
+	parent.BandwidthCost += group.BandwidthCost
+	parent.Carbon += group.Carbon
+	parent.Energy += group.Energy
+	parent.UtilizedCPUCost += group.UtilizedCPUCost
+	parent.UtilizedMemoryCost += group.UtilizedMemoryCost
+}
@@ -0,0 +1,157 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func mockDgraphForNamespaceWorkloads() {
+	executeQueryWithVars = func(ctx context.Context, query string, vars map[string]string, root interface{}) error {
+		if strings.Contains(query, barePodFilter) {
+			return json.Unmarshal([]byte(`{"parent": [{"name": "namespace-default", "type": "namespace", "children": [
+				{"name": "pod-debug", "type": "pod", "cpu": 0.5, "cpuCost": 1.5}
+			]}]}`), root)
+		}
+		return json.Unmarshal([]byte(`{"parent": [{"name": "namespace-default", "type": "namespace", "cpu": 3, "cpuCost": 6, "memoryCost": 2, "children": [
+			{"name": "statefulset-db", "type": "statefulset", "cpu": 1, "cpuCost": 2},
+			{"name": "deployment-web", "type": "deployment", "cpu": 1, "cpuCost": 2, "memoryCost": 1},
+			{"name": "deployment-api", "type": "deployment", "cpu": 1, "cpuCost": 2, "memoryCost": 1}
+		]}]}`), root)
+	}
+}
+
+// TestRetrieveNamespaceMetricsGroupedByKind ...
+func TestRetrieveNamespaceMetricsGroupedByKind(t *testing.T) {
+	mockDgraphForNamespaceWorkloads()
+	resource := Resource{Check: NamespaceCheck, Type: NamespaceType, Name: "namespace-default", GroupBy: Kind}
+	got := resource.RetrieveResourceMetrics(context.Background()).Data
+
+	assert.Nil(t, got.Children)
+	assert.Equal(t, 3.5, got.CPU)
+	assert.Equal(t, 7.5, got.CPUCost)
+	assert.Equal(t, 2.0, got.MemoryCost)
+	assert.Equal(t, []WorkloadGroup{
+		{
+			Children: Children{Name: "Deployments", Type: DeploymentType, CPU: 2, CPUCost: 4, MemoryCost: 2},
+			Count:    2,
+			Workloads: []Children{
+				{Name: "deployment-web", Type: DeploymentType, CPU: 1, CPUCost: 2, MemoryCost: 1},
+				{Name: "deployment-api", Type: DeploymentType, CPU: 1, CPUCost: 2, MemoryCost: 1},
+			},
+		},
+		{
+			Children:  Children{Name: "StatefulSets", Type: StatefulsetType, CPU: 1, CPUCost: 2},
+			Count:     1,
+			Workloads: []Children{{Name: "statefulset-db", Type: StatefulsetType, CPU: 1, CPUCost: 2}},
+		},
+		{
+			Children:  Children{Name: "Pods", Type: PodType, CPU: 0.5, CPUCost: 1.5},
+			Count:     1,
+			Workloads: []Children{{Name: "pod-debug", Type: PodType, CPU: 0.5, CPUCost: 1.5}},
+		},
+	}, got.Groups)
+
+	resource.GroupBy = ""
+	ungrouped := resource.RetrieveResourceMetrics(context.Background()).Data
+	assert.Equal(t, 3, len(ungrouped.Children))
+	assert.Nil(t, ungrouped.Groups)
+	assert.Equal(t, 6.0, ungrouped.CPUCost)
+}
+
+// TestGroupChildrenByKind ...
+func TestGroupChildrenByKind(t *testing.T) {
+	assert.Equal(t, []WorkloadGroup{}, groupChildrenByKind(nil))
+
+	groups := groupChildrenByKind([]Children{{Name: "cronjob-backup", Type: "cronjob", Carbon: 2}, {Name: "job-backup", Type: JobType, Energy: 1}})
+	assert.Equal(t, 2, len(groups))
+	assert.Equal(t, "Jobs", groups[0].Name)
+	assert.Equal(t, 1.0, groups[0].Energy)
+	assert.Equal(t, "cronjob", groups[1].Name)
+	assert.Equal(t, 2.0, groups[1].Carbon)
+}
+
+// TestGetQueryForNamespaceBarePodMetrics ...
+func TestGetQueryForNamespaceBarePodMetrics(t *testing.T) {
+	query, vars := getQueryForNamespaceBarePodMetrics("namespace-default", Linux, "")
+	assert.Equal(t, "namespace-default", vars["$name"])
+	assert.True(t, strings.Contains(query, `parent(func: has(isNamespace)) @filter(eq(name, $name)) {`))
+	assert.True(t, strings.Contains(query, `children: ~namespace @filter(`+barePodFilter+` AND eq(os, "linux")) {`))
+	assert.True(t, strings.Contains(query, "cpu: cpuBarePod as cpuRequest"))
+}
+
+// TestRetrieveWorkloadMetrics ...
+func TestRetrieveWorkloadMetrics(t *testing.T) {
+	retrievers := map[string]func(context.Context, string, string, TimeRange) JSONDataWrapper{
+		DeploymentType:  RetrieveDeploymentMetrics,
+		StatefulsetType: RetrieveStatefulSetMetrics,
+		DaemonsetType:   RetrieveDaemonSetMetrics,
+		JobType:         RetrieveJobMetrics,
+	}
+	for resourceType, retrieve := range retrievers {
+		mockDgraphForResourceQueries(testMetrics, testResourceName, resourceType)
+		got := retrieve(context.Background(), testResourceName, "", TimeRange{})
+		assert.Equal(t, getExpectedTestMetrics(testResourceName, resourceType), got, resourceType)
+	}
+}
+
+// TestRetrieveWorkloadMetricsInNamespace ...
+func TestRetrieveWorkloadMetricsInNamespace(t *testing.T) {
+	var queries []string
+	executeQueryWithVars = func(ctx context.Context, query string, vars map[string]string, root interface{}) error {
+		assert.Equal(t, "namespace-shop", vars["$namespace"])
+		queries = append(queries, query)
+		return nil
+	}
+	timeRange := TimeRange{Start: "2018-10-01T00:00:00Z", End: "2018-11-01T00:00:00Z"}
+	RetrieveDeploymentMetrics(context.Background(), "deployment-web", "namespace-shop", timeRange)
+	RetrieveJobMetrics(context.Background(), "job-backup", "namespace-shop", timeRange)
+
+	assert.Equal(t, 2, len(queries))
+	assert.Contains(t, queries[0], `dep as var(func: has(isDeployment)) @filter(eq(name, $name) AND uid(namespaceResources))`)
+	assert.Contains(t, queries[1], `parent(func: has(isJob)) @filter(eq(name, $name) AND uid(namespaceResources))`)
+	for _, query := range queries {
+		assert.Contains(t, query, `namespaceResources as ~namespace`)
+		assert.Contains(t, query, `le(startTime, "2018-11-01T00:00:00Z")`)
+	}
+}
+
+// TestRetrieveCronJobMetrics ...
+func TestRetrieveCronJobMetrics(t *testing.T) {
+	mockDgraphForResourceQueries(testMetrics, "cronjob-backup", CronJobType)
+	got := RetrieveCronJobMetrics(context.Background(), "cronjob-backup", "", TimeRange{})
+	assert.Equal(t, getExpectedTestMetrics("cronjob-backup", CronJobType), got)
+}
+
+// TestGetQueryForCronJobMetrics ...
+func TestGetQueryForCronJobMetrics(t *testing.T) {
+	timeRange := TimeRange{Start: "2018-10-01T00:00:00Z", End: "2018-11-01T00:00:00Z"}
+	query, vars := getQueryForCronJobMetrics("cronjob-backup", "namespace-shop", timeRange)
+	assert.Equal(t, "cronjob-backup", vars["$name"])
+	assert.Equal(t, "namespace-shop", vars["$namespace"])
+	assert.Contains(t, query, `cronJob as var(func: has(isCronJob)) @filter(eq(name, $name) AND uid(namespaceResources))`)
+	assert.Contains(t, query, `~cronjob @filter(has(isJob) AND (le(startTime, "2018-11-01T00:00:00Z")`)
+	assert.Contains(t, query, `~job @filter(has(isPod) AND (le(startTime, "2018-11-01T00:00:00Z")`)
+	assert.Contains(t, query, `cpuCostCronJob as sum(val(cpuCostCronJobJob))`)
+	assert.Contains(t, query, `parent(func: uid(cronJob))`)
+}
@@ -0,0 +1,229 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	qb "github.com/vmware/purser/pkg/querybuilder"
+)
+
+// Zone constants, costs of pods are grouped by availability zone or by region of their nodes
+const (
+	ZoneType    = "zone"
+	RegionType  = "region"
+	Zone        = "zone"
+	Region      = "region"
+	UnknownZone = "unknown"
+)
+
+// ZoneCost is the month to date cost of pods which ran on nodes of a zone(or region). Nodes is the number of
+// live nodes in it and Share is its fraction of the total cost.
+type ZoneCost struct {
+	Name        string   `json:"name"`
+	Region      string   `json:"region,omitempty"`
+	Zones       []string `json:"zones,omitempty"`
+	Nodes       int      `json:"nodes"`
+	Pods        int      `json:"pods"`
+	CPUCost     float64  `json:"cpuCost"`
+	MemoryCost  float64  `json:"memoryCost"`
+	StorageCost float64  `json:"storageCost"`
+	TotalCost   float64  `json:"totalCost"`
+	Share       float64  `json:"share"`
+}
+
+// ZoneCosts structure, Name is the namespace if costs are restricted to it, otherwise cluster
+type ZoneCosts struct {
+	Name      string     `json:"name"`
+	GroupBy   string     `json:"groupBy"`
+	TotalCost float64    `json:"totalCost"`
+	Zones     []ZoneCost `json:"zones"`
+}
+
+// ZoneCostsWrapper structure
+type ZoneCostsWrapper struct {
+	Data ZoneCosts `json:"data"`
+}
+
+type zoneNode struct {
+	Zone   string `json:"zone"`
+	Region string `json:"region"`
+}
+
+type zonePod struct {
+	Name                 string    `json:"name"`
+	EndTime              string    `json:"endTime"`
+	CPUCost              float64   `json:"cpuCost"`
+	MemoryCost           float64   `json:"memoryCost"`
+	StorageCost          float64   `json:"storageCost"`
+	GPUCost              float64   `json:"gpuCost"`
+	ExtendedResourceCost float64   `json:"extendedResourceCost"`
+	BandwidthCost        float64   `json:"bandwidthCost"`
+	Node                 *zoneNode `json:"node"`
+}
+
+// RetrieveZoneCosts returns month to date cost of pods grouped by zone of their nodes, or by region if groupBy
+// is region, highest cost first. Costs are restricted to pods of the namespace if name is a namespace, otherwise
+// zones whose nodes ran no pods are included too. Nodes without zone or region labels are reported under unknown.
+func RetrieveZoneCosts(ctx context.Context, name, groupBy string) ZoneCostsWrapper {
+	if name != All && !strings.HasPrefix(name, NamespaceType+"-") {
+		log.Errorf("unable to retrieve zone costs, %s is not a namespace", name)
+		return ZoneCostsWrapper{}
+	}
+	type root struct {
+		Nodes     []zoneNode `json:"nodes"`
+		Pods      []zonePod  `json:"pods"`
+		Namespace []struct {
+			Pods []zonePod `json:"pods"`
+		} `json:"namespace"`
+	}
+	newRoot := root{}
+	query, vars := getQueryForZoneCosts(name)
+	err := executeQueryWithVars(ctx, query, vars, &newRoot)
+	if err != nil {
+		log.Errorf("unable to retrieve costs of zones, err: %v", err)
+		return ZoneCostsWrapper{}
+	}
+	pods := newRoot.Pods
+	if name != All {
+		pods = nil
+		for _, namespace := range newRoot.Namespace {
+			pods = append(pods, namespace.Pods...)
+		}
+	}
+
+	resourceType, keyOf := ZoneType, func(node zoneNode) string { return node.Zone }
+	if groupBy == Region {
+		resourceType, keyOf = RegionType, func(node zoneNode) string { return node.Region }
+	}
+	data := ZoneCosts{Name: name, GroupBy: resourceType, Zones: []ZoneCost{}}
+	if data.Name == All {
+		data.Name = "cluster"
+	}
+	costs := make(map[string]*ZoneCost)
+	getZoneCost := func(node zoneNode) *ZoneCost {
+		key := keyOf(node)
+		if key == "" {
+			key = UnknownZone
+		}
+		if _, isPresent := costs[key]; !isPresent {
+			costs[key] = &ZoneCost{Name: key}
+		}
+		return costs[key]
+	}
+	for _, node := range newRoot.Nodes {
+		cost := getZoneCost(node)
+		cost.Nodes++
+		addZoneOrRegion(cost, resourceType, node)
+	}
+	for _, pod := range pods {
+		node := zoneNode{}
+		if pod.Node != nil {
+			node = *pod.Node
+		}
+		if cost := getZoneCost(node); addZonePod(cost, pod) {
+			addZoneOrRegion(cost, resourceType, node)
+		}
+	}
+
+	for _, cost := range costs {
+		if cost.Pods == 0 && (cost.Nodes == 0 || name != All) {
+			continue
+		}
+		cost.CPUCost = adjustCost(CostContext{resourceType, cost.Name, CPUCostType}, cost.CPUCost)
+		cost.MemoryCost = adjustCost(CostContext{resourceType, cost.Name, MemoryCostType}, cost.MemoryCost)
+		cost.StorageCost = adjustCost(CostContext{resourceType, cost.Name, StorageCostType}, cost.StorageCost)
+		cost.TotalCost += cost.CPUCost + cost.MemoryCost + cost.StorageCost
+		sort.Strings(cost.Zones)
+		data.TotalCost += cost.TotalCost
+		data.Zones = append(data.Zones, *cost)
+	}
+	for i := range data.Zones {
+		if data.TotalCost > 0 {
+			data.Zones[i].Share = data.Zones[i].TotalCost / data.TotalCost
+		}
+	}
+	sort.Slice(data.Zones, func(i, j int) bool {
+		if data.Zones[i].TotalCost != data.Zones[j].TotalCost {
+			return data.Zones[i].TotalCost > data.Zones[j].TotalCost
+		}
+		return data.Zones[i].Name < data.Zones[j].Name
+	})
+	return ZoneCostsWrapper{Data: data}
+}
+
+// addZonePod adds cost of the pod to its zone, TotalCost holds the adjusted costs of GPUs, extended resources
+// and bandwidth until cpu, memory and storage costs are adjusted. Pods terminated before this month are not added.
+func addZonePod(cost *ZoneCost, pod zonePod) bool {
+	if pod.EndTime != "" && pod.CPUCost+pod.MemoryCost+pod.StorageCost+pod.GPUCost+pod.ExtendedResourceCost+pod.BandwidthCost == 0 {
+		return false
+	}
+	cost.Pods++
+	cost.CPUCost += pod.CPUCost
+	cost.MemoryCost += pod.MemoryCost
+	cost.StorageCost += pod.StorageCost
+	cost.TotalCost += adjustCost(CostContext{PodType, pod.Name, GPUCostType}, pod.GPUCost) +
+		adjustCost(CostContext{PodType, pod.Name, ExtendedResourceCostType}, pod.ExtendedResourceCost) +
+		adjustCost(CostContext{PodType, pod.Name, BandwidthCostType}, pod.BandwidthCost)
+	return true
+}
+
+// addZoneOrRegion records the region of a zone or the zone of a region
+func addZoneOrRegion(cost *ZoneCost, resourceType string, node zoneNode) {
+	if resourceType == ZoneType {
+		if cost.Region == "" {
+			cost.Region = node.Region
+		}
+		return
+	}
+	if node.Zone != "" && !containsString(cost.Zones, node.Zone) {
+		cost.Zones = append(cost.Zones, node.Zone)
+	}
+}
+
+func getQueryForZoneCosts(name string) (string, qb.Vars) {
+	vars := qb.Vars{}
+	pods := `pods(func: has(isPod)) {
+			` + getQueryForZonePod() + `
+		}`
+	if name != All {
+		vars["$name"] = name
+		pods = `namespace(func: has(isNamespace)) @filter(eq(name, $name)) {
+			pods: ~namespace @filter(has(isPod)) {
+				` + getQueryForZonePod() + `
+			}
+		}`
+	}
+	return vars.Declaration() + ` {
+		nodes(func: has(isNode)) @filter(NOT has(endTime)) {
+			zone
+			region
+		}
+		` + pods + `
+	}`, vars
+}
+
+func getQueryForZonePod() string {
+	return getQueryForMetricsComputationWithAlias("Pod") + `
+			node {
+				zone
+				region
+			}`
+}
@@ -0,0 +1,106 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func mockDgraphForZoneCosts() {
+	executeQueryWithVars = func(ctx context.Context, query string, vars map[string]string, root interface{}) error {
+		nodes := `"nodes": [
+			{"zone": "us-east-1a", "region": "us-east-1"},
+			{"zone": "us-east-1a", "region": "us-east-1"},
+			{"zone": "us-east-1b", "region": "us-east-1"},
+			{"zone": "eu-west-1a", "region": "eu-west-1"},
+			{}
+		]`
+		if strings.Contains(query, `namespace(func: has(isNamespace)) @filter(eq(name, $name))`) && vars["$name"] == "namespace-default" {
+			return json.Unmarshal([]byte(`{`+nodes+`, "namespace": [{"pods": [
+				{"name": "pod-web", "cpuCost": 1, "memoryCost": 1, "node": {"zone": "us-east-1b", "region": "us-east-1"}}
+			]}]}`), root)
+		}
+		return json.Unmarshal([]byte(`{`+nodes+`, "pods": [
+			{"name": "pod-web", "cpuCost": 1, "memoryCost": 1, "node": {"zone": "us-east-1b", "region": "us-east-1"}},
+			{"name": "pod-db", "cpuCost": 2, "memoryCost": 2, "storageCost": 1, "bandwidthCost": 1, "node": {"zone": "us-east-1a", "region": "us-east-1"}},
+			{"name": "pod-old", "endTime": "2018-09-01T00:00:00Z", "node": {"zone": "us-east-1a", "region": "us-east-1"}},
+			{"name": "pod-batch", "cpuCost": 0.5, "memoryCost": 0.5, "endTime": "2018-10-02T00:00:00Z", "node": {}},
+			{"name": "pod-pending"}
+		]}`), root)
+	}
+}
+
+// TestRetrieveZoneCosts ...
+func TestRetrieveZoneCosts(t *testing.T) {
+	mockDgraphForZoneCosts()
+	got := RetrieveZoneCosts(context.Background(), All, "").Data
+
+	assert.Equal(t, "cluster", got.Name)
+	assert.Equal(t, ZoneType, got.GroupBy)
+	assert.Equal(t, 9.0, got.TotalCost)
+	assert.Equal(t, 4, len(got.Zones))
+	assert.Equal(t, ZoneCost{
+		Name:        "us-east-1a",
+		Region:      "us-east-1",
+		Nodes:       2,
+		Pods:        1,
+		CPUCost:     2,
+		MemoryCost:  2,
+		StorageCost: 1,
+		TotalCost:   6,
+		Share:       6.0 / 9,
+	}, got.Zones[0])
+	assert.Equal(t, "us-east-1b", got.Zones[1].Name)
+	assert.Equal(t, UnknownZone, got.Zones[2].Name)
+	assert.Equal(t, 1, got.Zones[2].Nodes)
+	assert.Equal(t, 2, got.Zones[2].Pods)
+	assert.Equal(t, "eu-west-1a", got.Zones[3].Name)
+	assert.Equal(t, 0, got.Zones[3].Pods)
+	assert.Equal(t, 0.0, got.Zones[3].Share)
+}
+
+// TestRetrieveZoneCostsByRegion ...
+func TestRetrieveZoneCostsByRegion(t *testing.T) {
+	mockDgraphForZoneCosts()
+	got := RetrieveZoneCosts(context.Background(), All, Region).Data
+
+	assert.Equal(t, RegionType, got.GroupBy)
+	assert.Equal(t, 3, len(got.Zones))
+	assert.Equal(t, "us-east-1", got.Zones[0].Name)
+	assert.Equal(t, []string{"us-east-1a", "us-east-1b"}, got.Zones[0].Zones)
+	assert.Equal(t, 3, got.Zones[0].Nodes)
+	assert.Equal(t, 8.0, got.Zones[0].TotalCost)
+}
+
+// TestRetrieveZoneCostsOfNamespace ...
+func TestRetrieveZoneCostsOfNamespace(t *testing.T) {
+	mockDgraphForZoneCosts()
+	got := RetrieveZoneCosts(context.Background(), "namespace-default", "").Data
+
+	assert.Equal(t, "namespace-default", got.Name)
+	assert.Equal(t, 1, len(got.Zones))
+	assert.Equal(t, "us-east-1b", got.Zones[0].Name)
+	assert.Equal(t, 1.0, got.Zones[0].Share)
+
+	assert.Equal(t, ZoneCostsWrapper{}, RetrieveZoneCosts(context.Background(), "deployment-web", ""))
+}
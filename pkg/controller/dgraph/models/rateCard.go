@@ -135,6 +135,11 @@ func retrieveNode(name string) (*Node, error) {
 			memoryCapacity
 			instanceType
 			os
+			region
+			isVirtual
+			gpuShare
+			capacityType
+			hourlyPrice
         }
     }`
 	type root struct {
@@ -186,11 +191,21 @@ func getPerUnitResourcePriceForNode(nodeName string) (float64, float64) {
 	return DefaultCPUCostInFloat64, DefaultMemCostInFloat64
 }
 
+// getPricePerUnitResourceFromNodePrice returns price per cpu and price per memory using selected pricing providers.
+// Virtual nodes are priced using serverless pricing. Providers return on-demand prices, spot and reserved nodes
+// are discounted.
 func getPricePerUnitResourceFromNodePrice(node Node) (float64, float64) {
-	nodePriceXID := node.InstanceType + "-" + node.OS
-	nodePrice, err := retrieveNodePrice(nodePriceXID)
-	if err == nil {
-		return nodePrice.PricePerCPU, nodePrice.PricePerMemory
+	if node.IsVirtual {
+		return getServerlessRates()
 	}
-	return DefaultCPUCostInFloat64, DefaultMemCostInFloat64
+	if node.HourlyPrice > 0 {
+		rates, err := getRatesFromHourlyPrice(node)
+		if err == nil {
+			return rates.CPUPrice, rates.MemoryPrice
+		}
+		logrus.Warnf("%v, pricing it by pricing providers", err)
+	}
+	cpuPrice, memoryPrice := getNodeRates(node)
+	factor := getCapacityTypeFactor(node.CapacityType)
+	return cpuPrice * factor, memoryPrice * factor
 }
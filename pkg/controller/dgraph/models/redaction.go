@@ -0,0 +1,104 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package models
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"sync"
+
+	log "github.com/Sirupsen/logrus"
+)
+
+// RedactedValuePrefix is the prefix of hashed values of labels and annotations
+const RedactedValuePrefix = "redacted-"
+
+var (
+	redactionMu   sync.RWMutex
+	hashedKeys    *regexp.Regexp
+	droppedKeys   *regexp.Regexp
+	redactionSalt []byte
+)
+
+// SetRedaction sets regular expressions matching keys of labels and annotations whose values are hashed or dropped
+// before they are stored, ex: ^(owner|contact)$ or ^vault\.hashicorp\.com/. Hashed values are the HMAC-SHA256 of the
+// value with the salt so they can still be grouped and selected, dropped labels are not stored. Empty expressions
+// redact nothing.
+func SetRedaction(hashPattern, dropPattern, salt string) error {
+	hashed, err := compileRedactionPattern(hashPattern)
+	if err != nil {
+		return err
+	}
+	dropped, err := compileRedactionPattern(dropPattern)
+	if err != nil {
+		return err
+	}
+	redactionMu.Lock()
+	defer redactionMu.Unlock()
+	hashedKeys, droppedKeys, redactionSalt = hashed, dropped, []byte(salt)
+	log.Infof("hashed label and annotation keys: %q, dropped keys: %q", hashPattern, dropPattern)
+	return nil
+}
+
+// RedactLabelValue returns the value of the label or annotation as it is stored and false if it is dropped
+func RedactLabelValue(key, value string) (string, bool) {
+	redactionMu.RLock()
+	defer redactionMu.RUnlock()
+	if droppedKeys != nil && droppedKeys.MatchString(key) {
+		return "", false
+	}
+	if hashedKeys != nil && hashedKeys.MatchString(key) {
+		mac := hmac.New(sha256.New, redactionSalt)
+		mac.Write([]byte(value))
+		return RedactedValuePrefix + hex.EncodeToString(mac.Sum(nil)), true
+	}
+	return value, true
+}
+
+// redactLabels returns labels or annotations without dropped ones and with hashed values, labels are returned as
+// they are if none is redacted
+func redactLabels(labels map[string]string) map[string]string {
+	redactionMu.RLock()
+	isRedacted := hashedKeys != nil || droppedKeys != nil
+	redactionMu.RUnlock()
+	if !isRedacted {
+		return labels
+	}
+
+	redacted := make(map[string]string, len(labels))
+	for key, value := range labels {
+		if value, isKept := RedactLabelValue(key, value); isKept {
+			redacted[key] = value
+		}
+	}
+	return redacted
+}
+
+func compileRedactionPattern(pattern string) (*regexp.Regexp, error) {
+	if pattern == "" {
+		return nil, nil
+	}
+	compiled, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid redaction pattern: %s, err: %v", pattern, err)
+	}
+	return compiled, nil
+}
@@ -0,0 +1,60 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package models
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/vmware/purser/test/utils"
+)
+
+func TestRedactLabels(t *testing.T) {
+	utils.Ok(t, SetRedaction(`^(owner|contact)$`, `^vault\.hashicorp\.com/`, "salt"))
+	defer func() { utils.Ok(t, SetRedaction("", "", "")) }()
+
+	redacted := redactLabels(map[string]string{
+		"app":                                    "web",
+		"owner":                                  "jane@example.com",
+		"vault.hashicorp.com/agent-inject-token": "s.abc",
+	})
+	utils.Equals(t, 2, len(redacted))
+	utils.Equals(t, "web", redacted["app"])
+	utils.Assert(t, strings.HasPrefix(redacted["owner"], RedactedValuePrefix), "value of owner is not hashed: %s", redacted["owner"])
+	utils.Assert(t, !strings.Contains(redacted["owner"], "jane"), "hashed value contains the value: %s", redacted["owner"])
+
+	hashed, isKept := RedactLabelValue("owner", "jane@example.com")
+	utils.Assert(t, isKept, "hashed label is dropped")
+	utils.Equals(t, redacted["owner"], hashed)
+	other, _ := RedactLabelValue("contact", "john@example.com")
+	utils.Assert(t, other != hashed, "different values have the same hash")
+	_, isKept = RedactLabelValue("vault.hashicorp.com/role", "web")
+	utils.Assert(t, !isKept, "dropped label is kept")
+}
+
+func TestRedactLabelsWithoutRedaction(t *testing.T) {
+	labels := map[string]string{"owner": "jane@example.com"}
+	utils.Equals(t, labels, redactLabels(labels))
+	value, isKept := RedactLabelValue("owner", "jane@example.com")
+	utils.Assert(t, isKept, "label is dropped")
+	utils.Equals(t, "jane@example.com", value)
+}
+
+func TestSetRedactionInvalid(t *testing.T) {
+	utils.Assert(t, SetRedaction("(", "", "") != nil, "invalid pattern is accepted")
+}
@@ -41,6 +41,7 @@ type Replicaset struct {
 	Deployment   *Deployment `json:"deployment,omitempty"`
 	Pods         []*Pod      `json:"pod,omitempty"`
 	Type         string      `json:"type,omitempty"`
+	Revision     string      `json:"revision,omitempty"`
 }
 
 func createReplicasetObject(replicaset ext_v1beta1.ReplicaSet) Replicaset {
@@ -50,6 +51,7 @@ func createReplicasetObject(replicaset ext_v1beta1.ReplicaSet) Replicaset {
 		Type:         "replicaset",
 		ID:           dgraph.ID{Xid: replicaset.Namespace + ":" + replicaset.Name},
 		StartTime:    replicaset.GetCreationTimestamp().Time.Format(time.RFC3339),
+		Revision:     replicaset.Annotations[DeploymentRevisionAnnotation],
 	}
 	namespaceUID := CreateOrGetNamespaceByID(replicaset.Namespace)
 	if namespaceUID != "" {
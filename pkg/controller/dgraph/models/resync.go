@@ -0,0 +1,52 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package models
+
+import (
+	"time"
+
+	"github.com/vmware/purser/pkg/controller/dgraph"
+)
+
+// closedResource is a resource whose deletion event was missed, name and xid get its end time as on deletion events
+type closedResource struct {
+	dgraph.ID
+	Name    string `json:"name,omitempty"`
+	EndTime string `json:"endTime,omitempty"`
+	Deleted bool   `json:"deleted,omitempty"`
+}
+
+// CloseResource sets end time of a live resource of the kind which is no longer in the cluster,
+// containers of pods are closed too and namespaces are soft deleted with their resources
+func CloseResource(kind, uid, xid, name string, endTime time.Time) error {
+	if kind == "Pod" {
+		deleteContainersInTerminatedPod(RetrievePodWithContainers(xid).Containers, endTime)
+	}
+	et := endTime.Format(time.RFC3339)
+	resource := closedResource{
+		ID:      dgraph.ID{UID: uid, Xid: xid + et},
+		Name:    name + "*" + et,
+		EndTime: et,
+		Deleted: kind == "Namespace",
+	}
+	_, err := dgraph.MutateNode(resource, dgraph.UPDATE)
+	if err != nil || !resource.Deleted {
+		return err
+	}
+	return softDeleteNamespaceResources(uid)
+}
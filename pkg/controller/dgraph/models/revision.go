@@ -0,0 +1,51 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package models
+
+// Revision annotations and labels, commit annotations are preferred over version labels
+const (
+	DeploymentRevisionAnnotation = "deployment.kubernetes.io/revision"
+	VersionLabel                 = "app.kubernetes.io/version"
+)
+
+// commitKeys are well known annotations (or labels) holding the git commit an object is built from
+var commitKeys = []string{
+	"app.kubernetes.io/commit",
+	"org.opencontainers.image.revision",
+	"git-commit",
+	"git-sha",
+	"gitCommit",
+	"commit",
+}
+
+// getRevision returns the git commit or version of an object from its annotations and labels,
+// empty string if none of them is present
+func getRevision(annotations, labels map[string]string) string {
+	for _, key := range commitKeys {
+		if commit := annotations[key]; commit != "" {
+			return commit
+		}
+		if commit := labels[key]; commit != "" {
+			return commit
+		}
+	}
+	if version := labels[VersionLabel]; version != "" {
+		return version
+	}
+	return annotations[VersionLabel]
+}
@@ -0,0 +1,31 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package models
+
+import (
+	"testing"
+
+	"github.com/vmware/purser/test/utils"
+)
+
+func TestGetRevision(t *testing.T) {
+	utils.Equals(t, "", getRevision(nil, nil))
+	utils.Equals(t, "1.2.0", getRevision(nil, map[string]string{VersionLabel: "1.2.0"}))
+	utils.Equals(t, "9f3c2e1", getRevision(map[string]string{"git-commit": "9f3c2e1"}, map[string]string{VersionLabel: "1.2.0"}))
+	utils.Equals(t, "a1b2c3d", getRevision(nil, map[string]string{"git-sha": "a1b2c3d", VersionLabel: "1.2.0"}))
+}
@@ -0,0 +1,97 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package models
+
+import (
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	openshift_v1 "github.com/vmware/purser/pkg/apis/openshift/v1"
+	"github.com/vmware/purser/pkg/controller/dgraph"
+)
+
+// Dgraph Model Constants
+const (
+	IsRoute = "isRoute"
+)
+
+// Route schema in dgraph
+type Route struct {
+	dgraph.ID
+	IsRoute   bool       `json:"isRoute,omitempty"`
+	Name      string     `json:"name,omitempty"`
+	StartTime string     `json:"startTime,omitempty"`
+	EndTime   string     `json:"endTime,omitempty"`
+	Host      string     `json:"host,omitempty"`
+	Namespace *Namespace `json:"namespace,omitempty"`
+	Services  []*Service `json:"service,omitempty"`
+	Type      string     `json:"type,omitempty"`
+}
+
+func createRouteObject(route openshift_v1.Route) Route {
+	newRoute := Route{
+		Name:      "route-" + route.Name,
+		IsRoute:   true,
+		Type:      "route",
+		ID:        dgraph.ID{Xid: route.Namespace + ":" + route.Name},
+		StartTime: route.GetCreationTimestamp().Time.Format(time.RFC3339),
+		Host:      route.Spec.Host,
+		Services:  getRouteServices(route),
+	}
+	namespaceUID := CreateOrGetNamespaceByID(route.Namespace)
+	if namespaceUID != "" {
+		newRoute.Namespace = &Namespace{ID: dgraph.ID{UID: namespaceUID, Xid: route.Namespace}}
+	}
+	routeDeletionTimestamp := route.GetDeletionTimestamp()
+	if !routeDeletionTimestamp.IsZero() {
+		newRoute.EndTime = routeDeletionTimestamp.Time.Format(time.RFC3339)
+		newRoute.Xid += newRoute.EndTime
+		newRoute.Name += "*" + newRoute.EndTime
+	}
+	return newRoute
+}
+
+// StoreRoute create a new route in the Dgraph and updates if already present.
+func StoreRoute(route openshift_v1.Route) (string, error) {
+	xid := route.Namespace + ":" + route.Name
+	uid := dgraph.GetUID(xid, IsRoute)
+
+	newRoute := createRouteObject(route)
+	if uid != "" {
+		newRoute.UID = uid
+	}
+	assigned, err := dgraph.MutateNode(newRoute, dgraph.CREATE)
+	if err != nil {
+		return "", err
+	}
+	return assigned.Uids["blank-0"], nil
+}
+
+// getRouteServices returns the persisted services to which route sends traffic
+func getRouteServices(route openshift_v1.Route) []*Service {
+	backends := append([]openshift_v1.RouteTargetReference{route.Spec.To}, route.Spec.AlternateBackends...)
+	servicesXIDs := []string{}
+	for _, backend := range backends {
+		if backend.Kind != "Service" {
+			log.Debugf("unsupported backend kind: %s of route: %s", backend.Kind, route.Name)
+			continue
+		}
+		servicesXIDs = append(servicesXIDs, route.Namespace+":"+backend.Name)
+	}
+	return retrieveServicesFromServicesXIDs(servicesXIDs)
+}
@@ -0,0 +1,68 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package models
+
+import (
+	log "github.com/Sirupsen/logrus"
+	"github.com/vmware/purser/pkg/controller/dgraph"
+)
+
+// Dgraph Model Constants
+const (
+	IsScaleUp        = "isScaleUp"
+	scaleUpXIDPrefix = "scaleUp-"
+)
+
+// Sources of scale ups
+const (
+	ClusterAutoscalerSource = "cluster-autoscaler"
+	KarpenterSource         = "karpenter"
+)
+
+// ScaleUp is a scale up triggered by a pending pod, reported by cluster-autoscaler or a node provisioner.
+// Target is the node group scaled up or the node nominated for the pod, startTime is when it was triggered.
+type ScaleUp struct {
+	dgraph.ID
+	IsScaleUp bool   `json:"isScaleUp,omitempty"`
+	Name      string `json:"name,omitempty"`
+	Source    string `json:"source,omitempty"`
+	Target    string `json:"target,omitempty"`
+	StartTime string `json:"startTime,omitempty"`
+	Pod       *Pod   `json:"pod,omitempty"`
+}
+
+// StoreScaleUp stores the scale up identified by id(uid of its k8s event) if it isn't already stored and
+// links it to the pod with xid podXid if the pod is present.
+func StoreScaleUp(scaleUp ScaleUp, id, podXid string) error {
+	xid := scaleUpXIDPrefix + id
+	if dgraph.GetUID(xid, IsScaleUp) != "" {
+		return nil
+	}
+	scaleUp.ID = dgraph.ID{Xid: xid}
+	scaleUp.IsScaleUp = true
+	scaleUp.Name = xid
+	if podUID := dgraph.GetUID(podXid, IsPod); podUID != "" {
+		scaleUp.Pod = &Pod{ID: dgraph.ID{UID: podUID, Xid: podXid}}
+	}
+
+	_, err := dgraph.MutateNode(scaleUp, dgraph.CREATE)
+	if err == nil {
+		log.Debugf("scale up with xid: (%s) persisted", xid)
+	}
+	return err
+}
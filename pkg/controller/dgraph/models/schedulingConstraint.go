@@ -0,0 +1,89 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package models
+
+import (
+	"encoding/json"
+
+	log "github.com/Sirupsen/logrus"
+	api_v1 "k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Kinds of scheduling constraints of pods
+const (
+	PodAffinityConstraint     = "podAffinity"
+	PodAntiAffinityConstraint = "podAntiAffinity"
+)
+
+// SchedulingConstraint is a required inter-pod affinity or anti-affinity term of a pod, Selector is the label
+// selector of the term in its string form
+type SchedulingConstraint struct {
+	Kind        string `json:"kind"`
+	TopologyKey string `json:"topologyKey"`
+	Selector    string `json:"selector,omitempty"`
+}
+
+// getSchedulingConstraints returns the required affinity and anti-affinity terms of the pod encoded in JSON,
+// empty string if it has none. Preferred terms don't force placement and are not stored.
+func getSchedulingConstraints(k8sPod api_v1.Pod) string {
+	affinity := k8sPod.Spec.Affinity
+	if affinity == nil {
+		return ""
+	}
+	var constraints []SchedulingConstraint
+	if affinity.PodAffinity != nil {
+		constraints = appendConstraints(constraints, PodAffinityConstraint, affinity.PodAffinity.RequiredDuringSchedulingIgnoredDuringExecution)
+	}
+	if affinity.PodAntiAffinity != nil {
+		constraints = appendConstraints(constraints, PodAntiAffinityConstraint, affinity.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution)
+	}
+	if len(constraints) == 0 {
+		return ""
+	}
+	encoded, err := json.Marshal(constraints)
+	if err != nil {
+		log.Errorf("unable to encode scheduling constraints of pod: %s, err: %v", k8sPod.Name, err)
+		return ""
+	}
+	return string(encoded)
+}
+
+func appendConstraints(constraints []SchedulingConstraint, kind string, terms []api_v1.PodAffinityTerm) []SchedulingConstraint {
+	for _, term := range terms {
+		constraints = append(constraints, SchedulingConstraint{
+			Kind:        kind,
+			TopologyKey: term.TopologyKey,
+			Selector:    meta_v1.FormatLabelSelector(term.LabelSelector),
+		})
+	}
+	return constraints
+}
+
+// ParseSchedulingConstraints returns the scheduling constraints stored with a pod, nil if they can't be decoded
+func ParseSchedulingConstraints(constraints string) []SchedulingConstraint {
+	if constraints == "" {
+		return nil
+	}
+	var parsed []SchedulingConstraint
+	if err := json.Unmarshal([]byte(constraints), &parsed); err != nil {
+		log.Errorf("unable to decode scheduling constraints: %s, err: %v", constraints, err)
+		return nil
+	}
+	return parsed
+}
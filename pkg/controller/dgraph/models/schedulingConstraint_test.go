@@ -0,0 +1,52 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package models
+
+import (
+	"testing"
+
+	"github.com/vmware/purser/test/utils"
+	api_v1 "k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGetSchedulingConstraints(t *testing.T) {
+	utils.Equals(t, "", getSchedulingConstraints(api_v1.Pod{}))
+
+	selector := &meta_v1.LabelSelector{MatchLabels: map[string]string{"app": "web"}}
+	pod := api_v1.Pod{Spec: api_v1.PodSpec{Affinity: &api_v1.Affinity{
+		PodAntiAffinity: &api_v1.PodAntiAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: []api_v1.PodAffinityTerm{
+				{LabelSelector: selector, TopologyKey: "kubernetes.io/hostname"},
+			},
+			PreferredDuringSchedulingIgnoredDuringExecution: []api_v1.WeightedPodAffinityTerm{
+				{Weight: 100, PodAffinityTerm: api_v1.PodAffinityTerm{LabelSelector: selector, TopologyKey: "failure-domain.beta.kubernetes.io/zone"}},
+			},
+		},
+	}}}
+	constraints := getSchedulingConstraints(pod)
+	utils.Equals(t, `[{"kind":"podAntiAffinity","topologyKey":"kubernetes.io/hostname","selector":"app=web"}]`, constraints)
+	utils.Equals(t, []SchedulingConstraint{
+		{Kind: PodAntiAffinityConstraint, TopologyKey: "kubernetes.io/hostname", Selector: "app=web"},
+	}, ParseSchedulingConstraints(constraints))
+}
+
+func TestParseSchedulingConstraintsWithInvalidValue(t *testing.T) {
+	utils.Assert(t, ParseSchedulingConstraints("") == nil, "expected no constraints")
+	utils.Assert(t, ParseSchedulingConstraints("[{") == nil, "expected no constraints")
+}
@@ -0,0 +1,92 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dgraph-io/dgo/protos/api"
+	"github.com/vmware/purser/pkg/controller/dgraph"
+)
+
+// Dgraph Model Constants
+const (
+	IsServiceUnitCost = "isServiceUnitCost"
+)
+
+// ServiceUnitCost is the cost of a service and the requests it served in an interval
+type ServiceUnitCost struct {
+	dgraph.ID
+	IsServiceUnitCost bool     `json:"isServiceUnitCost,omitempty"`
+	Service           *Service `json:"service,omitempty"`
+	StartTime         string   `json:"startTime,omitempty"`
+	EndTime           string   `json:"endTime,omitempty"`
+	Requests          float64  `json:"requests,omitempty"`
+	Cost              float64  `json:"cost,omitempty"`
+	CostPer1kRequests float64  `json:"costPer1kRequests,omitempty"`
+}
+
+// StoreServiceUnitCost stores cost and requests of the service in the interval ending at the given time
+func StoreServiceUnitCost(serviceXID string, end time.Time, window time.Duration, requests, cost float64) (*api.Assigned, error) {
+	uid := dgraph.GetUID(serviceXID, IsService)
+	if uid == "" {
+		return nil, fmt.Errorf("service: %s is not persisted yet", serviceXID)
+	}
+	endTime := end.Format(time.RFC3339)
+	unitCost := ServiceUnitCost{
+		ID:                dgraph.ID{Xid: serviceXID + ":" + endTime},
+		IsServiceUnitCost: true,
+		Service:           &Service{ID: dgraph.ID{UID: uid, Xid: serviceXID}},
+		StartTime:         end.Add(-window).Format(time.RFC3339),
+		EndTime:           endTime,
+		Requests:          requests,
+		Cost:              cost,
+	}
+	if requests > 0 {
+		unitCost.CostPer1kRequests = cost / requests * 1000
+	}
+	return dgraph.MutateNode(unitCost, dgraph.CREATE)
+}
+
+// RetrieveLiveServicesWithPods returns live services with resources and prices of their live pods
+func RetrieveLiveServicesWithPods() ([]Service, error) {
+	const q = `query {
+		services(func: has(isService)) @filter(NOT has(endTime)) {
+			xid
+			pod @filter(NOT has(endTime)) {
+				xid
+				cpuRequest
+				memoryRequest
+				storageRequest
+				cpuPrice
+				memoryPrice
+			}
+		}
+	}`
+
+	type root struct {
+		Services []Service `json:"services"`
+	}
+	newRoot := root{}
+	err := dgraph.ExecuteQuery(q, &newRoot)
+	if err != nil {
+		return nil, err
+	}
+	return newRoot.Services, nil
+}
@@ -0,0 +1,136 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package models
+
+import (
+	"sync"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/vmware/purser/pkg/controller/dgraph"
+)
+
+// Dgraph Model Constants
+const (
+	IsStorageClass = "isStorageClass"
+)
+
+// StorageClass schema in dgraph, VolumeType is the final type of disks of the class(ex: gp3) and StoragePrice the
+// price of its volumes
+type StorageClass struct {
+	dgraph.ID
+	IsStorageClass bool    `json:"isStorageClass,omitempty"`
+	Name           string  `json:"name,omitempty"`
+	Type           string  `json:"type,omitempty"`
+	VolumeType     string  `json:"volumeType,omitempty"`
+	StoragePrice   float64 `json:"storagePrice,omitempty"`
+}
+
+// StoragePricing holds prices of volumes per storage class name(ex: fast-ssd) or volume type(ex: gp3, io2, standard)
+// Unit of prices should be USD($)-(per GB)-(per Hour)
+type StoragePricing struct {
+	Prices map[string]float64 `json:"prices,omitempty"`
+}
+
+var (
+	storagePricingMu sync.RWMutex
+	storagePrices    = map[string]float64{}
+)
+
+// SetStoragePricing sets prices of volumes per storage class or volume type
+func SetStoragePricing(pricing StoragePricing) {
+	storagePricingMu.Lock()
+	defer storagePricingMu.Unlock()
+	storagePrices = map[string]float64{}
+	for name, price := range pricing.Prices {
+		storagePrices[name] = price
+	}
+}
+
+// GetStorageClassPrice returns price per GB per hour of volumes of the storage class, the price of the class name if
+// it is set, otherwise of its volume type, otherwise DefaultStorageCostInFloat64
+func GetStorageClassPrice(className, volumeType string) float64 {
+	storagePricingMu.RLock()
+	defer storagePricingMu.RUnlock()
+	if price, isPresent := storagePrices[className]; isPresent && className != "" {
+		return price
+	}
+	if price, isPresent := storagePrices[volumeType]; isPresent && volumeType != "" {
+		return price
+	}
+	return DefaultStorageCostInFloat64
+}
+
+// StoreStorageClass creates the storage class in the Dgraph or updates its volume type and price, returns its uid
+func StoreStorageClass(name, volumeType string) string {
+	if name == "" {
+		return ""
+	}
+	storageClass := StorageClass{
+		ID:             dgraph.ID{Xid: name},
+		Name:           "storageclass-" + name,
+		IsStorageClass: true,
+		Type:           "storageclass",
+		VolumeType:     volumeType,
+		StoragePrice:   GetStorageClassPrice(name, volumeType),
+	}
+	uid := dgraph.GetUID(name, IsStorageClass)
+	if uid != "" {
+		storageClass.UID = uid
+	}
+	assigned, err := dgraph.MutateNode(storageClass, dgraph.CREATE)
+	if err != nil {
+		logrus.Errorf("unable to store storage class: %s, err: %v", name, err)
+		return uid
+	}
+	if uid != "" {
+		return uid
+	}
+	return assigned.Uids["blank-0"]
+}
+
+// getStorageClassForClaim returns the storage class of a pvc with its price, the volume type of the class is the one
+// stored with its volumes. Classes without volumes yet are created and priced by their name.
+func getStorageClassForClaim(name string) (*StorageClass, float64) {
+	if name == "" {
+		return nil, DefaultStorageCostInFloat64
+	}
+	query := `query Me($xid:string) {
+		class(func: eq(xid, $xid)) @filter(has(isStorageClass)) {
+			uid
+			volumeType
+		}
+	}`
+	type root struct {
+		Classes []StorageClass `json:"class"`
+	}
+	newRoot := root{}
+	err := dgraph.ExecuteQueryWithVars(query, map[string]string{"$xid": name}, &newRoot)
+	if err != nil {
+		logrus.Errorf("unable to get storage class: %s, err: %v", name, err)
+	}
+	if len(newRoot.Classes) > 0 {
+		stored := newRoot.Classes[0]
+		return &StorageClass{ID: dgraph.ID{UID: stored.UID, Xid: name}}, GetStorageClassPrice(name, stored.VolumeType)
+	}
+	if err == nil {
+		if uid := StoreStorageClass(name, ""); uid != "" {
+			return &StorageClass{ID: dgraph.ID{UID: uid, Xid: name}}, GetStorageClassPrice(name, "")
+		}
+	}
+	return nil, GetStorageClassPrice(name, "")
+}
@@ -0,0 +1,44 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package models
+
+import (
+	"testing"
+
+	"github.com/vmware/purser/test/utils"
+)
+
+func TestGetStorageClassPrice(t *testing.T) {
+	SetStoragePricing(StoragePricing{Prices: map[string]float64{
+		"gp3":      0.00011,
+		"io2":      0.00017,
+		"fast-ssd": 0.0002,
+	}})
+	defer SetStoragePricing(StoragePricing{})
+
+	utils.Equals(t, 0.0002, GetStorageClassPrice("fast-ssd", "io2"))
+	utils.Equals(t, 0.00017, GetStorageClassPrice("premium", "io2"))
+	utils.Equals(t, 0.00011, GetStorageClassPrice("gp3", ""))
+	utils.Equals(t, DefaultStorageCostInFloat64, GetStorageClassPrice("standard", "pd-standard"))
+	utils.Equals(t, DefaultStorageCostInFloat64, GetStorageClassPrice("", ""))
+}
+
+func TestGetPVCStoragePrice(t *testing.T) {
+	utils.Equals(t, 0.0002, getPVCStoragePrice(PersistentVolumeClaim{StoragePrice: 0.0002}))
+	utils.Equals(t, DefaultStorageCostInFloat64, getPVCStoragePrice(PersistentVolumeClaim{}))
+}
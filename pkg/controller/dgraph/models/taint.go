@@ -0,0 +1,135 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package models
+
+import (
+	"encoding/json"
+	"sort"
+
+	log "github.com/Sirupsen/logrus"
+	api_v1 "k8s.io/api/core/v1"
+)
+
+// NodeTaint is a taint of a node which repels pods not tolerating it
+type NodeTaint struct {
+	Key    string `json:"key"`
+	Value  string `json:"value,omitempty"`
+	Effect string `json:"effect"`
+}
+
+// PodToleration is a toleration of a pod, empty Key with operator Exists tolerates every taint
+type PodToleration struct {
+	Key      string `json:"key,omitempty"`
+	Operator string `json:"operator,omitempty"`
+	Value    string `json:"value,omitempty"`
+	Effect   string `json:"effect,omitempty"`
+}
+
+// String returns the taint in the kubectl form key=value:effect
+func (t NodeTaint) String() string {
+	if t.Value == "" {
+		return t.Key + ":" + t.Effect
+	}
+	return t.Key + "=" + t.Value + ":" + t.Effect
+}
+
+// Tolerates returns true if the toleration matches the taint
+func (t PodToleration) Tolerates(taint NodeTaint) bool {
+	if t.Effect != "" && t.Effect != taint.Effect {
+		return false
+	}
+	if t.Key != "" && t.Key != taint.Key {
+		return false
+	}
+	if t.Operator == string(api_v1.TolerationOpExists) {
+		return true
+	}
+	return t.Key != "" && t.Value == taint.Value
+}
+
+// getNodeTaints returns the NoSchedule and NoExecute taints of the node sorted and encoded in JSON, empty string
+// if it has none. PreferNoSchedule taints don't keep pods away and are not stored.
+func getNodeTaints(node api_v1.Node) string {
+	var taints []NodeTaint
+	for _, taint := range node.Spec.Taints {
+		if taint.Effect != api_v1.TaintEffectNoSchedule && taint.Effect != api_v1.TaintEffectNoExecute {
+			continue
+		}
+		taints = append(taints, NodeTaint{Key: taint.Key, Value: taint.Value, Effect: string(taint.Effect)})
+	}
+	if len(taints) == 0 {
+		return ""
+	}
+	sort.Slice(taints, func(i, j int) bool {
+		return taints[i].String() < taints[j].String()
+	})
+	encoded, err := json.Marshal(taints)
+	if err != nil {
+		log.Errorf("unable to encode taints of node: %s, err: %v", node.Name, err)
+		return ""
+	}
+	return string(encoded)
+}
+
+// getPodTolerations returns the tolerations of the pod encoded in JSON, empty string if it has none
+func getPodTolerations(k8sPod api_v1.Pod) string {
+	if len(k8sPod.Spec.Tolerations) == 0 {
+		return ""
+	}
+	tolerations := []PodToleration{}
+	for _, toleration := range k8sPod.Spec.Tolerations {
+		tolerations = append(tolerations, PodToleration{
+			Key:      toleration.Key,
+			Operator: string(toleration.Operator),
+			Value:    toleration.Value,
+			Effect:   string(toleration.Effect),
+		})
+	}
+	encoded, err := json.Marshal(tolerations)
+	if err != nil {
+		log.Errorf("unable to encode tolerations of pod: %s, err: %v", k8sPod.Name, err)
+		return ""
+	}
+	return string(encoded)
+}
+
+// ParseNodeTaints returns the taints stored with a node, nil if they can't be decoded
+func ParseNodeTaints(taints string) []NodeTaint {
+	if taints == "" {
+		return nil
+	}
+	var parsed []NodeTaint
+	if err := json.Unmarshal([]byte(taints), &parsed); err != nil {
+		log.Errorf("unable to decode node taints: %s, err: %v", taints, err)
+		return nil
+	}
+	return parsed
+}
+
+// ParsePodTolerations returns the tolerations stored with a pod, nil if they can't be decoded
+func ParsePodTolerations(tolerations string) []PodToleration {
+	if tolerations == "" {
+		return nil
+	}
+	var parsed []PodToleration
+	if err := json.Unmarshal([]byte(tolerations), &parsed); err != nil {
+		log.Errorf("unable to decode pod tolerations: %s, err: %v", tolerations, err)
+		return nil
+	}
+	return parsed
+}
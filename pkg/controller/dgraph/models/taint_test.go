@@ -0,0 +1,64 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package models
+
+import (
+	"testing"
+
+	"github.com/vmware/purser/test/utils"
+	api_v1 "k8s.io/api/core/v1"
+)
+
+func TestGetNodeTaints(t *testing.T) {
+	utils.Equals(t, "", getNodeTaints(api_v1.Node{}))
+
+	node := api_v1.Node{Spec: api_v1.NodeSpec{Taints: []api_v1.Taint{
+		{Key: "team", Value: "ml", Effect: api_v1.TaintEffectNoSchedule},
+		{Key: "dedicated", Effect: api_v1.TaintEffectNoExecute},
+		{Key: "spot", Value: "true", Effect: api_v1.TaintEffectPreferNoSchedule},
+	}}}
+	taints := getNodeTaints(node)
+	utils.Equals(t, `[{"key":"dedicated","effect":"NoExecute"},{"key":"team","value":"ml","effect":"NoSchedule"}]`, taints)
+	utils.Equals(t, []NodeTaint{
+		{Key: "dedicated", Effect: "NoExecute"},
+		{Key: "team", Value: "ml", Effect: "NoSchedule"},
+	}, ParseNodeTaints(taints))
+	utils.Equals(t, "team=ml:NoSchedule", ParseNodeTaints(taints)[1].String())
+}
+
+func TestGetPodTolerations(t *testing.T) {
+	utils.Equals(t, "", getPodTolerations(api_v1.Pod{}))
+
+	pod := api_v1.Pod{Spec: api_v1.PodSpec{Tolerations: []api_v1.Toleration{
+		{Key: "team", Operator: api_v1.TolerationOpEqual, Value: "ml", Effect: api_v1.TaintEffectNoSchedule},
+	}}}
+	tolerations := getPodTolerations(pod)
+	utils.Equals(t, `[{"key":"team","operator":"Equal","value":"ml","effect":"NoSchedule"}]`, tolerations)
+	utils.Equals(t, []PodToleration{{Key: "team", Operator: "Equal", Value: "ml", Effect: "NoSchedule"}}, ParsePodTolerations(tolerations))
+	utils.Assert(t, ParsePodTolerations("[{") == nil, "expected no tolerations")
+}
+
+func TestTolerates(t *testing.T) {
+	taint := NodeTaint{Key: "team", Value: "ml", Effect: "NoSchedule"}
+	utils.Assert(t, PodToleration{Key: "team", Value: "ml"}.Tolerates(taint), "equal toleration doesn't tolerate")
+	utils.Assert(t, PodToleration{Key: "team", Operator: "Exists", Effect: "NoSchedule"}.Tolerates(taint), "exists toleration doesn't tolerate")
+	utils.Assert(t, PodToleration{Operator: "Exists"}.Tolerates(taint), "wildcard toleration doesn't tolerate")
+	utils.Assert(t, !PodToleration{Key: "team", Value: "web"}.Tolerates(taint), "toleration of other value tolerates")
+	utils.Assert(t, !PodToleration{Key: "team", Operator: "Exists", Effect: "NoExecute"}.Tolerates(taint), "toleration of other effect tolerates")
+	utils.Assert(t, !PodToleration{Key: "dedicated", Operator: "Exists"}.Tolerates(taint), "toleration of other key tolerates")
+}
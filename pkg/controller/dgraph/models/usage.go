@@ -0,0 +1,76 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package models
+
+import (
+	"fmt"
+
+	"github.com/vmware/purser/pkg/controller/dgraph"
+)
+
+// UpdatePodUsage adds a sample of cpu(cores) and memory(GB) used by the pod to its average usage, synthetic requests
+// of the pod derived from its usage are updated with it
+func UpdatePodUsage(xid string, cpu, memoryGB float64) error {
+	uid := dgraph.GetUID(xid, IsPod)
+	if uid == "" {
+		return fmt.Errorf("pod: %s is not persisted yet", xid)
+	}
+	current, err := retrievePodUsage(uid)
+	if err != nil {
+		return err
+	}
+	pod := Pod{
+		ID:           dgraph.ID{UID: uid, Xid: xid},
+		CPUUsage:     addSampleToAverage(current.CPUUsage, current.UsageSamples, cpu),
+		MemoryUsage:  addSampleToAverage(current.MemoryUsage, current.UsageSamples, memoryGB),
+		UsageSamples: current.UsageSamples + 1,
+	}
+	if current.SyntheticRequests && isUsageBasedBestEffortRequests() {
+		pod.SyntheticRequests = true
+		pod.SyntheticCPURequest, pod.SyntheticMemoryRequest = pod.CPUUsage, pod.MemoryUsage
+	}
+	_, err = dgraph.MutateNode(pod, dgraph.UPDATE)
+	return err
+}
+
+// addSampleToAverage returns the average of samples after adding the sample to them
+func addSampleToAverage(average float64, samples int, sample float64) float64 {
+	return (average*float64(samples) + sample) / float64(samples+1)
+}
+
+// retrievePodUsage returns average usage and number of usage samples stored for the pod with given uid, and whether
+// it has synthetic requests
+func retrievePodUsage(uid string) (Pod, error) {
+	query := `query {
+		pods(func: uid(` + uid + `)) {
+			cpuUsage
+			memoryUsage
+			usageSamples
+			syntheticRequests
+		}
+	}`
+	type root struct {
+		Pods []Pod `json:"pods"`
+	}
+	newRoot := root{}
+	err := dgraph.ExecuteQuery(query, &newRoot)
+	if err != nil || len(newRoot.Pods) < 1 {
+		return Pod{}, err
+	}
+	return newRoot.Pods[0], nil
+}
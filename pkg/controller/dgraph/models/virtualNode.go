@@ -0,0 +1,86 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package models
+
+import (
+	"sync"
+
+	log "github.com/Sirupsen/logrus"
+	api_v1 "k8s.io/api/core/v1"
+)
+
+// Labels and taints identifying nodes backed by virtual kubelet(ex: Azure ACI, AWS Fargate)
+const (
+	VirtualKubeletLabelKey     = "type"
+	VirtualKubeletLabelValue   = "virtual-kubelet"
+	VirtualKubeletTaintKey     = "virtual-kubelet.io/provider"
+	FargateComputeTypeLabelKey = "eks.amazonaws.com/compute-type"
+	FargateComputeType         = "fargate"
+)
+
+// Default serverless pricing, taken from AWS Fargate on-demand prices
+// Unit of rates should be USD($)-(per unit resource)-(per Hour)
+const (
+	DefaultServerlessCPUCostInFloat64 = 0.04048
+	DefaultServerlessMemCostInFloat64 = 0.004445
+)
+
+var (
+	serverlessMu          sync.RWMutex
+	serverlessCPUPrice    = DefaultServerlessCPUCostInFloat64
+	serverlessMemoryPrice = DefaultServerlessMemCostInFloat64
+)
+
+// SetServerlessPricing sets per vCPU and per GB hourly prices used for pods running on virtual nodes.
+// Non positive prices are ignored and defaults are retained.
+func SetServerlessPricing(cpuPrice, memoryPrice float64) {
+	serverlessMu.Lock()
+	defer serverlessMu.Unlock()
+	if cpuPrice > 0 {
+		serverlessCPUPrice = cpuPrice
+	}
+	if memoryPrice > 0 {
+		serverlessMemoryPrice = memoryPrice
+	}
+	log.Infof("serverless pricing, cpu: %v, memory: %v", serverlessCPUPrice, serverlessMemoryPrice)
+}
+
+// getServerlessRates returns price per cpu and price per memory for pods on virtual nodes
+func getServerlessRates() (float64, float64) {
+	serverlessMu.RLock()
+	defer serverlessMu.RUnlock()
+	return serverlessCPUPrice, serverlessMemoryPrice
+}
+
+// isVirtualNode returns true if the node is backed by virtual kubelet. Such nodes report
+// fake capacity and their pods are billed individually by the serverless backend.
+func isVirtualNode(node api_v1.Node) bool {
+	nodeLabels := node.GetLabels()
+	if nodeLabels[VirtualKubeletLabelKey] == VirtualKubeletLabelValue {
+		return true
+	}
+	if nodeLabels[FargateComputeTypeLabelKey] == FargateComputeType {
+		return true
+	}
+	for _, taint := range node.Spec.Taints {
+		if taint.Key == VirtualKubeletTaintKey {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,424 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dgraph
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultMutationLogMaxSize is the default size in bytes of the mutation log above which mutations are not queued
+const DefaultMutationLogMaxSize = 100 * 1024 * 1024
+
+// ErrQueued is returned by MutateNode when dgraph is unreachable, or mutations queued before are not replayed yet,
+// and the mutation is queued in the mutation log to be replayed once dgraph is reachable again
+var ErrQueued = errors.New("dgraph is unavailable, mutation is queued for replay")
+
+// errMutationLogFull is returned when queueing a mutation would make the mutation log larger than its max size
+var errMutationLogFull = errors.New("mutation log is full")
+
+// queuedMutation is a line of the mutation log
+type queuedMutation struct {
+	Type   string          `json:"type"`
+	Data   json.RawMessage `json:"data"`
+	Queued string          `json:"queued"`
+}
+
+// mutationLog is a file to which mutations of the controller are appended while dgraph is unreachable, one JSON
+// object per line in the order of the mutations. It is synced to disk after each mutation so that queued mutations
+// survive a restart of the controller.
+type mutationLog struct {
+	mu      sync.Mutex
+	path    string
+	file    *os.File
+	maxSize int64
+	size    int64
+	queued  int
+	// isReplaying is set while a replay is triggered or running so that appends trigger at most one replay
+	isReplaying bool
+	// replayMu serializes replays so that a mutation is not sent by two of them
+	replayMu sync.Mutex
+}
+
+var (
+	mutations = &mutationLog{maxSize: DefaultMutationLogMaxSize}
+
+	// replaced in tests
+	mutate = mutateJSON
+	getUID = GetUID
+)
+
+// SetMutationLog sets the file in which mutations are queued while dgraph is unreachable, they are replayed in order
+// when the circuit breaker closes and by ReplayMutations. Mutations left in the file by a previous run of the
+// controller are kept for replay. Empty path disables queueing, mutations then fail while dgraph is unreachable.
+func SetMutationLog(path string) error {
+	if err := mutations.open(path); err != nil {
+		return err
+	}
+	if path == "" {
+		breaker.setOnClose(nil)
+	} else {
+		breaker.setOnClose(func() {
+			go ReplayMutations()
+		})
+	}
+	return nil
+}
+
+// SetMutationLogMaxSize sets the size in bytes above which mutations are not queued in the mutation log, they fail
+// as if there is no mutation log until it is replayed. Sizes less than 1 are ignored.
+func SetMutationLogMaxSize(size int64) {
+	if size < 1 {
+		log.Errorf("mutation log max size must be positive: %d", size)
+		return
+	}
+	mutations.mu.Lock()
+	defer mutations.mu.Unlock()
+	mutations.maxSize = size
+}
+
+// QueuedMutations returns the number of mutations in the mutation log waiting to be replayed
+func QueuedMutations() int {
+	mutations.mu.Lock()
+	defer mutations.mu.Unlock()
+	return mutations.queued
+}
+
+// ReplayMutations sends mutations queued in the mutation log to dgraph in order, it stops at the first mutation
+// failing because dgraph is unreachable and keeps it and the following ones in the log. Mutations failing with other
+// errors are dropped. It returns the number of replayed mutations.
+func ReplayMutations() int {
+	if !IsAvailable() {
+		return 0
+	}
+	replayed := 0
+	for {
+		count, isComplete, err := mutations.replay(replayMutation)
+		replayed += count
+		if err != nil {
+			log.Errorf("unable to replay queued mutations, replayed: %d, err: %v", replayed, err)
+			break
+		}
+		// mutations queued while the log was replayed are replayed too
+		if !isComplete || count == 0 || QueuedMutations() == 0 {
+			break
+		}
+	}
+	if replayed > 0 {
+		log.Infof("replayed %d queued mutations, %d left", replayed, QueuedMutations())
+	}
+	return replayed
+}
+
+// queueMutation appends the mutation to the mutation log if it is enabled and the mutation failed because dgraph is
+// unreachable or mutations are already queued, returns whether it is queued. Mutations are queued behind queued ones
+// instead of being sent so that older queued mutations(ex: the creation of a pod) do not overwrite newer ones(ex: the
+// end time of the pod) when they are replayed.
+func queueMutation(mutateType string, data []byte, err error) bool {
+	if !mutations.isEnabled() || (err != nil && !isQueueable(err)) || (err == nil && QueuedMutations() == 0) {
+		return false
+	}
+	if queueErr := mutations.append(mutateType, data); queueErr != nil {
+		log.Errorf("unable to queue mutation, err: %v", queueErr)
+		return false
+	}
+	// while dgraph is unavailable the log is replayed when the circuit breaker closes
+	if err == nil && IsAvailable() {
+		mutations.triggerReplay()
+	}
+	return true
+}
+
+// replayMutation sends the queued mutation, nodes which were created while dgraph was unreachable are created
+// without their uid so it is looked up again to not create them twice
+func replayMutation(queued queuedMutation) error {
+	data := []byte(queued.Data)
+	if queued.Type != DELETE {
+		data = resolveUIDs(data)
+	}
+	_, err := mutate(queued.Type, data)
+	return err
+}
+
+// resolveUIDs sets the uid of nodes of the mutation having an xid and a type(ex: isPod) but no uid if they are
+// stored in dgraph. The data is returned unchanged if it is not a JSON object or a list of objects.
+func resolveUIDs(data []byte) []byte {
+	var nodes []map[string]interface{}
+	isList := json.Unmarshal(data, &nodes) == nil
+	if !isList {
+		var node map[string]interface{}
+		if err := json.Unmarshal(data, &node); err != nil || node == nil {
+			return data
+		}
+		nodes = []map[string]interface{}{node}
+	}
+
+	isResolved := false
+	for _, node := range nodes {
+		xid, hasXid := node["xid"].(string)
+		if !hasXid || node["uid"] != nil {
+			continue
+		}
+		if nodeType := getNodeType(node); nodeType != "" {
+			if uid := getUID(xid, nodeType); uid != "" {
+				node["uid"] = uid
+				isResolved = true
+			}
+		}
+	}
+	if !isResolved {
+		return data
+	}
+
+	var resolved []byte
+	var err error
+	if isList {
+		resolved, err = json.Marshal(nodes)
+	} else {
+		resolved, err = json.Marshal(nodes[0])
+	}
+	if err != nil {
+		return data
+	}
+	return resolved
+}
+
+// getNodeType returns the predicate marking the type of the node(ex: isPod), empty string if it has none
+func getNodeType(node map[string]interface{}) string {
+	for predicate, value := range node {
+		if isSet, isBool := value.(bool); isBool && isSet && strings.HasPrefix(predicate, "is") {
+			return predicate
+		}
+	}
+	return ""
+}
+
+// isQueueable returns whether the mutation failed because dgraph is unreachable
+func isQueueable(err error) bool {
+	return err == ErrUnavailable || isTransient(err)
+}
+
+func (l *mutationLog) open(path string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.file != nil {
+		if err := l.file.Close(); err != nil {
+			log.Errorf("unable to close mutation log: %s, err: %v", l.path, err)
+		}
+		l.file = nil
+	}
+	l.path = path
+	l.size = 0
+	l.queued = 0
+	if path == "" {
+		return nil
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		l.path = ""
+		return err
+	}
+	l.file = file
+	queued, err := l.read()
+	if err != nil {
+		return err
+	}
+	if len(queued) > 0 {
+		log.Infof("%d mutations of a previous run are queued in mutation log: %s", len(queued), path)
+	}
+	l.queued = len(queued)
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+	l.size = info.Size()
+	return nil
+}
+
+func (l *mutationLog) isEnabled() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.file != nil
+}
+
+// append adds the mutation at the end of the log
+func (l *mutationLog) append(mutateType string, data []byte) error {
+	line, err := json.Marshal(queuedMutation{Type: mutateType, Data: data, Queued: now().Format(time.RFC3339)})
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.file == nil {
+		return errors.New("mutation log is disabled")
+	}
+	if l.size+int64(len(line)) > l.maxSize {
+		return errMutationLogFull
+	}
+	if _, err := l.file.Write(line); err != nil {
+		return err
+	}
+	l.size += int64(len(line))
+	l.queued++
+	return l.file.Sync()
+}
+
+// triggerReplay replays the log in the background unless a replay is triggered already
+func (l *mutationLog) triggerReplay() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.isReplaying {
+		return
+	}
+	l.isReplaying = true
+	go func() {
+		ReplayMutations()
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		l.isReplaying = false
+	}()
+}
+
+// replay sends the queued mutations in order until one fails because dgraph is unreachable, the log is rewritten
+// with the mutations which are not sent. It returns the number of sent mutations and whether all of them are sent.
+// The log is not locked while mutations are sent so that mutations are queued during the replay, they are kept
+// behind the mutations which are not sent.
+func (l *mutationLog) replay(send func(queuedMutation) error) (int, bool, error) {
+	l.replayMu.Lock()
+	defer l.replayMu.Unlock()
+	queued, err := l.snapshot()
+	if err != nil || len(queued) == 0 {
+		return 0, err == nil, err
+	}
+
+	replayed := 0
+	for replayed < len(queued) {
+		err := send(queued[replayed])
+		if err != nil && isQueueable(err) {
+			break
+		}
+		if err != nil {
+			log.Errorf("dropping queued mutation of %s, err: %v", queued[replayed].Queued, err)
+		}
+		replayed++
+	}
+	if replayed == 0 {
+		return 0, false, nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.file == nil {
+		return replayed, replayed == len(queued), nil
+	}
+	current, err := l.read()
+	if err != nil {
+		return replayed, false, err
+	}
+	// the log starts with the snapshot, mutations after it are appended during the replay
+	if replayed > len(current) {
+		replayed = len(current)
+	}
+	return replayed, replayed == len(queued), l.rewrite(current[replayed:])
+}
+
+// snapshot returns the mutations in the log, none if it is disabled
+func (l *mutationLog) snapshot() ([]queuedMutation, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.file == nil {
+		return nil, nil
+	}
+	return l.read()
+}
+
+// read returns the mutations in the log, lines which are not valid mutations(ex: a line partially written when the
+// controller stopped) are skipped
+func (l *mutationLog) read() ([]queuedMutation, error) {
+	file, err := os.Open(l.path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	queued := []queuedMutation{}
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 64*1024*1024)
+	for scanner.Scan() {
+		mutation := queuedMutation{}
+		if err := json.Unmarshal(scanner.Bytes(), &mutation); err != nil || mutation.Type == "" {
+			log.Errorf("skipping invalid line of mutation log: %s, err: %v", l.path, err)
+			continue
+		}
+		queued = append(queued, mutation)
+	}
+	return queued, scanner.Err()
+}
+
+// rewrite replaces the log with the mutations, the new log is renamed over the old one so that a crash keeps one of them
+func (l *mutationLog) rewrite(queued []queuedMutation) error {
+	tmpPath := l.path + ".tmp"
+	file, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	writer := bufio.NewWriter(file)
+	for _, mutation := range queued {
+		line, err := json.Marshal(mutation)
+		if err != nil {
+			file.Close()
+			return err
+		}
+		writer.Write(append(line, '\n'))
+	}
+	if err := writer.Flush(); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpPath, l.path); err != nil {
+		return err
+	}
+
+	if err := l.file.Close(); err != nil {
+		log.Errorf("unable to close mutation log: %s, err: %v", l.path, err)
+	}
+	l.file, err = os.OpenFile(l.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	info, err := l.file.Stat()
+	if err != nil {
+		return err
+	}
+	l.size = info.Size()
+	l.queued = len(queued)
+	return nil
+}
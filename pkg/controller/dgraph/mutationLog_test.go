@@ -0,0 +1,222 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dgraph
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dgraph-io/dgo/protos/api"
+	"github.com/vmware/purser/test/utils"
+)
+
+type testNode struct {
+	ID
+	IsPod bool   `json:"isPod,omitempty"`
+	Name  string `json:"name,omitempty"`
+}
+
+func mockMutationLog(t *testing.T) (string, func()) {
+	dir, err := ioutil.TempDir("", "purser")
+	utils.Ok(t, err)
+	path := filepath.Join(dir, "mutations.log")
+	utils.Ok(t, SetMutationLog(path))
+	return path, func() {
+		utils.Ok(t, SetMutationLog(""))
+		mutate = mutateJSON
+		getUID = GetUID
+		os.RemoveAll(dir)
+	}
+}
+
+func TestMutateNodeQueuesWhileUnavailable(t *testing.T) {
+	_, _, restoreClock := mockClock()
+	defer restoreClock()
+	path, restore := mockMutationLog(t)
+	defer restore()
+	SetCircuitBreaker(1, time.Minute)
+	breaker.record(false)
+	mutate = func(mutateType string, bytes []byte) (*api.Assigned, error) {
+		return nil, ErrUnavailable
+	}
+
+	_, err := MutateNode(testNode{ID: ID{Xid: "default:pod-1"}, IsPod: true, Name: "pod-1"}, CREATE)
+	utils.Equals(t, ErrQueued, err)
+	_, err = MutateNode(testNode{ID: ID{UID: "0x1"}}, DELETE)
+	utils.Equals(t, ErrQueued, err)
+
+	queued, err := ioutil.ReadFile(path)
+	utils.Ok(t, err)
+	lines := strings.Split(strings.TrimSpace(string(queued)), "\n")
+	utils.Equals(t, 2, len(lines))
+	utils.Assert(t, strings.Contains(lines[0], `"type":"create"`), "expected create, got: %s", lines[0])
+	utils.Assert(t, strings.Contains(lines[1], `"type":"delete"`), "expected delete, got: %s", lines[1])
+}
+
+func TestReplayMutationsKeepsUnsentMutations(t *testing.T) {
+	_, restore := mockMutationLog(t)
+	defer restore()
+	utils.Ok(t, mutations.append(CREATE, []byte(`{"xid":"default:pod-1","isPod":true}`)))
+	utils.Ok(t, mutations.append(CREATE, []byte(`{"xid":"default:pod-2","isPod":true}`)))
+	utils.Ok(t, mutations.append(DELETE, []byte(`{"uid":"0x3"}`)))
+
+	getUID = func(xid, nodeType string) string {
+		if xid == "default:pod-1" && nodeType == "isPod" {
+			return "0x1"
+		}
+		return ""
+	}
+	sent := []string{}
+	mutate = func(mutateType string, bytes []byte) (*api.Assigned, error) {
+		if len(sent) == 1 {
+			sent = append(sent, "unavailable")
+			return nil, ErrUnavailable
+		}
+		sent = append(sent, string(bytes))
+		return &api.Assigned{}, nil
+	}
+	utils.Equals(t, 1, ReplayMutations())
+	utils.Equals(t, []string{`{"isPod":true,"uid":"0x1","xid":"default:pod-1"}`, "unavailable"}, sent)
+
+	sent = []string{}
+	mutate = func(mutateType string, bytes []byte) (*api.Assigned, error) {
+		sent = append(sent, mutateType+" "+string(bytes))
+		return &api.Assigned{}, nil
+	}
+	utils.Equals(t, 2, ReplayMutations())
+	utils.Equals(t, []string{`create {"xid":"default:pod-2","isPod":true}`, `delete {"uid":"0x3"}`}, sent)
+	utils.Equals(t, 0, ReplayMutations())
+}
+
+func TestReplayMutationsKeepsMutationsQueuedDuringReplay(t *testing.T) {
+	_, restore := mockMutationLog(t)
+	defer restore()
+	utils.Ok(t, mutations.append(DELETE, []byte(`{"uid":"0x1"}`)))
+	utils.Ok(t, mutations.append(DELETE, []byte(`{"uid":"0x2"}`)))
+
+	sent := []string{}
+	mutate = func(mutateType string, bytes []byte) (*api.Assigned, error) {
+		if len(sent) == 1 {
+			// the log is not locked while mutations are sent
+			utils.Equals(t, 2, QueuedMutations())
+			utils.Ok(t, mutations.append(DELETE, []byte(`{"uid":"0x3"}`)))
+			sent = append(sent, "unavailable")
+			return nil, ErrUnavailable
+		}
+		sent = append(sent, string(bytes))
+		return &api.Assigned{}, nil
+	}
+	utils.Equals(t, 1, ReplayMutations())
+	utils.Equals(t, 2, QueuedMutations())
+
+	sent = []string{}
+	mutate = func(mutateType string, bytes []byte) (*api.Assigned, error) {
+		sent = append(sent, string(bytes))
+		return &api.Assigned{}, nil
+	}
+	utils.Equals(t, 2, ReplayMutations())
+	utils.Equals(t, []string{`{"uid":"0x2"}`, `{"uid":"0x3"}`}, sent)
+}
+
+func TestReplayMutationsOnBreakerClose(t *testing.T) {
+	clock, _, restoreClock := mockClock()
+	defer restoreClock()
+	_, restore := mockMutationLog(t)
+	defer restore()
+	SetCircuitBreaker(1, time.Minute)
+	utils.Ok(t, mutations.append(CREATE, []byte(`{"name":"pod-1"}`)))
+
+	replayed := make(chan string, 1)
+	mutate = func(mutateType string, bytes []byte) (*api.Assigned, error) {
+		replayed <- string(bytes)
+		return &api.Assigned{}, nil
+	}
+	breaker.record(false)
+	utils.Assert(t, !IsAvailable(), "expected breaker to be open")
+	*clock = clock.Add(2 * time.Minute)
+	utils.Assert(t, breaker.allow(), "expected probe to be allowed")
+	breaker.record(true)
+
+	select {
+	case data := <-replayed:
+		utils.Equals(t, `{"name":"pod-1"}`, data)
+	case <-time.After(time.Second):
+		t.Fatal("queued mutation is not replayed")
+	}
+}
+
+func TestMutateNodeQueuesBehindQueuedMutations(t *testing.T) {
+	_, restore := mockMutationLog(t)
+	defer restore()
+	utils.Ok(t, mutations.append(CREATE, []byte(`{"name":"pod-1","startTime":"2018-10-01T00:00:00Z"}`)))
+
+	replayed := make(chan string, 2)
+	mutate = func(mutateType string, bytes []byte) (*api.Assigned, error) {
+		replayed <- string(bytes)
+		return &api.Assigned{}, nil
+	}
+	_, err := MutateNode(testNode{Name: "pod-1"}, UPDATE)
+	utils.Equals(t, ErrQueued, err)
+
+	for _, expected := range []string{`{"name":"pod-1","startTime":"2018-10-01T00:00:00Z"}`, `{"name":"pod-1"}`} {
+		select {
+		case data := <-replayed:
+			utils.Equals(t, expected, data)
+		case <-time.After(time.Second):
+			t.Fatal("queued mutation is not replayed")
+		}
+	}
+	for start := time.Now(); QueuedMutations() > 0 && time.Since(start) < time.Second; {
+		time.Sleep(time.Millisecond)
+	}
+	utils.Equals(t, 0, QueuedMutations())
+	assigned, err := MutateNode(testNode{Name: "pod-2"}, CREATE)
+	utils.Ok(t, err)
+	utils.Assert(t, assigned != nil, "expected mutation to be sent")
+}
+
+func TestSetMutationLogKeepsMutationsOfPreviousRun(t *testing.T) {
+	path, restore := mockMutationLog(t)
+	defer restore()
+	utils.Ok(t, mutations.append(CREATE, []byte(`{"name":"pod-1"}`)))
+	utils.Ok(t, ioutil.WriteFile(path+".partial", nil, 0600))
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0600)
+	utils.Ok(t, err)
+	// a line partially written when the controller stopped
+	_, err = file.WriteString(`{"type":"create","da`)
+	utils.Ok(t, err)
+	utils.Ok(t, file.Close())
+
+	utils.Ok(t, SetMutationLog(path))
+	utils.Equals(t, 1, QueuedMutations())
+}
+
+func TestMutationLogMaxSize(t *testing.T) {
+	_, restore := mockMutationLog(t)
+	defer restore()
+	defer SetMutationLogMaxSize(DefaultMutationLogMaxSize)
+	SetMutationLogMaxSize(100)
+
+	utils.Ok(t, mutations.append(CREATE, []byte(`{"name":"pod-1"}`)))
+	utils.Equals(t, errMutationLogFull, mutations.append(CREATE, []byte(`{"name":"pod-2"}`)))
+	utils.Equals(t, 1, QueuedMutations())
+}
@@ -18,67 +18,173 @@
 package dgraph
 
 import (
-	"github.com/vmware/purser/pkg/controller/utils"
-
-	log "github.com/Sirupsen/logrus"
+	"encoding/json"
+	"sort"
+	"strings"
 	"time"
+
+	"github.com/vmware/purser/pkg/controller/utils"
 )
 
 type resource struct {
 	ID
 }
 
+// removableResource is a resource of a retention run with the uids of resources linking to it by each uid
+// predicate, those edges are removed with it so that retained resources don't link to removed ones
+type removableResource struct {
+	UID     string
+	inbound map[string][]ID
+}
+
+// UnmarshalJSON reads the uid and the reverse edges(ex: ~pod) of the resource
+func (r *removableResource) UnmarshalJSON(data []byte) error {
+	fields := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	r.inbound = make(map[string][]ID)
+	for key, value := range fields {
+		if key == "uid" {
+			if err := json.Unmarshal(value, &r.UID); err != nil {
+				return err
+			}
+			continue
+		}
+		if !strings.HasPrefix(key, "~") {
+			continue
+		}
+		var subjects []ID
+		if err := json.Unmarshal(value, &subjects); err != nil {
+			return err
+		}
+		r.inbound[strings.TrimPrefix(key, "~")] = subjects
+	}
+	return nil
+}
+
+// Retention of deleted resources in months before the start of current month, resources of deleted namespaces
+// are marked as deleted and retained for deletedNamespaceRetentionMonths. A positive retentionWindow replaces the
+// months of deleted resources and pods.
+var (
+	resourceRetentionMonths         = 0
+	podRetentionMonths              = 2
+	deletedNamespaceRetentionMonths = 12
+	retentionWindow                 time.Duration
+)
+
+// DefaultRetentionInterval is the default interval between retention runs
+const DefaultRetentionInterval = 24 * time.Hour
+
+// SetRetention sets number of months(before the start of current month) for which deleted resources and
+// deleted pods are retained in dgraph.
+func SetRetention(resourceMonths, podMonths int) {
+	if resourceMonths < 0 || podMonths < 0 {
+		log.Errorf("retention months can't be negative, resources: %d, pods: %d", resourceMonths, podMonths)
+		return
+	}
+	resourceRetentionMonths = resourceMonths
+	podRetentionMonths = podMonths
+}
+
+// SetRetentionWindow sets the duration(ex: 90 days) until now for which deleted resources, deleted pods and daily
+// costs of groups are retained in dgraph, it replaces the months of SetRetention. 0 keeps retention in months and
+// daily costs of groups.
+func SetRetentionWindow(window time.Duration) {
+	if window < 0 {
+		log.Errorf("retention window can't be negative: %v", window)
+		return
+	}
+	retentionWindow = window
+}
+
+// SetDeletedNamespaceRetention sets number of months(before the start of current month) for which deleted
+// namespaces and their resources are retained in dgraph.
+func SetDeletedNamespaceRetention(months int) {
+	if months < 0 {
+		log.Errorf("retention months of deleted namespaces can't be negative: %d", months)
+		return
+	}
+	deletedNamespaceRetentionMonths = months
+}
+
+// RetentionResult is the number of deleted resources, pods and resources of deleted namespaces removed by a
+// retention run, with the number of edges of retained resources to them and of daily costs of groups and cost
+// snapshots removed
+type RetentionResult struct {
+	Resources                 int `json:"resources"`
+	Pods                      int `json:"pods"`
+	DeletedNamespaceResources int `json:"deletedNamespaceResources"`
+	Edges                     int `json:"edges"`
+	DailyCosts                int `json:"dailyCosts"`
+}
+
 // RemoveResourcesInactive deletes all resources which have their deletion time stamp before
-// the start of current month.
+// the retention period(see SetRetention and SetRetentionWindow).
 func RemoveResourcesInactive() {
-	err := removeOldDeletedResources()
+	result, err := RunRetention()
 	if err != nil {
-		log.Println(err)
+		log.Error(err)
+		return
 	}
+	log.Infof("retention removed %d resources, %d pods, %d resources of deleted namespaces, %d edges and %d daily costs",
+		result.Resources, result.Pods, result.DeletedNamespaceResources, result.Edges, result.DailyCosts)
+}
 
-	err = removeOldDeletedPods()
+// RunRetention removes deleted resources and pods which are older than the retention period, with the edges of
+// retained resources to them, and returns how many of them are removed.
+func RunRetention() (RetentionResult, error) {
+	result := RetentionResult{}
+	resources, edges, err := removeOldResources("deleted resources", `NOT(has(isPod)) AND NOT(has(deleted))`, getRetentionStart(resourceRetentionMonths))
+	result.Resources, result.Edges = resources, result.Edges+edges
 	if err != nil {
-		log.Error(err)
+		return result, err
 	}
-}
 
-func removeOldDeletedResources() error {
-	uids, err := retrieveResourcesWithEndTimeBeforeCurrentMonthStart()
+	pods, edges, err := removeOldResources("deleted pods", `has(isPod) AND NOT(has(deleted))`, getRetentionStart(podRetentionMonths))
+	result.Pods, result.Edges = pods, result.Edges+edges
 	if err != nil {
-		return err
+		return result, err
 	}
-	if len(uids) == 0 {
-		log.Println("No old deleted resources are present in dgraph")
-		return nil
+
+	namespaceResources, edges, err := removeOldResources("resources of deleted namespaces", `has(deleted)`, getRetentionStartTime(deletedNamespaceRetentionMonths))
+	result.DeletedNamespaceResources, result.Edges = namespaceResources, result.Edges+edges
+	if err != nil {
+		return result, err
 	}
 
-	_, err = MutateNode(uids, DELETE)
-	return err
+	if retentionWindow > 0 {
+		result.DailyCosts, err = removeOldDailyCosts(now().Add(-retentionWindow))
+	}
+	return result, err
 }
 
-func removeOldDeletedPods() error {
-	uids, err := retrievePodsWithEndTimeBeforeThreeMonths()
+// removeOldResources removes resources matching the filter which ended before the time with the edges of other
+// resources to them, it returns the number of removed resources and edges
+func removeOldResources(description, filter string, before time.Time) (int, int, error) {
+	resources, err := retrieveResourcesEndedBefore(filter, before)
 	if err != nil {
-		return err
+		return 0, 0, err
 	}
-	if len(uids) == 0 {
-		log.Println("No old deleted pods are present in dgraph")
-		return nil
+	if len(resources) == 0 {
+		log.Printf("No old %s are present in dgraph", description)
+		return 0, 0, nil
 	}
 
-	_, err = MutateNode(uids, DELETE)
-	return err
+	deletes, edges := getRemovalMutations(resources)
+	_, err = MutateNode(deletes, DELETE)
+	return len(resources), edges, err
 }
 
-func retrieveResourcesWithEndTimeBeforeCurrentMonthStart() ([]resource, error) {
+func retrieveResourcesEndedBefore(filter string, before time.Time) ([]removableResource, error) {
 	q := `query {
-		resources(func: le(endTime, "` + utils.ConverTimeToRFC3339(utils.GetCurrentMonthStartTime()) + `")) @filter(NOT(has(isPod))) {
-			uid
+		resources(func: le(endTime, "` + utils.ConverTimeToRFC3339(before) + `")) @filter(` + filter + `) {
+			uid` + getInboundEdgesSelection() + `
 		}
 	}`
 
 	type root struct {
-		Resources []resource `json:"resources"`
+		Resources []removableResource `json:"resources"`
 	}
 	newRoot := root{}
 	err := ExecuteQuery(q, &newRoot)
@@ -88,9 +194,67 @@ func retrieveResourcesWithEndTimeBeforeCurrentMonthStart() ([]resource, error) {
 	return newRoot.Resources, nil
 }
 
-func retrievePodsWithEndTimeBeforeThreeMonths() ([]resource, error) {
+// getInboundEdgesSelection selects uids of resources linking to a resource by each uid predicate of the schema
+func getInboundEdgesSelection() string {
+	selection := ``
+	for _, predicate := range uidPredicates() {
+		selection += `
+			~` + predicate + ` {
+				uid
+			}`
+	}
+	return selection
+}
+
+// uidPredicates returns sorted names of predicates of the schema linking resources, they all have reverse edges
+func uidPredicates() []string {
+	var predicates []string
+	for predicate, definition := range schemaDefinitions() {
+		if strings.Contains(definition, ": uid @reverse") {
+			predicates = append(predicates, predicate)
+		}
+	}
+	sort.Strings(predicates)
+	return predicates
+}
+
+// getRemovalMutations returns the deletions of the resources and of edges of other resources to them, with the
+// number of those edges. Edges of resources which are removed too go with them.
+func getRemovalMutations(resources []removableResource) ([]interface{}, int) {
+	isRemoved := make(map[string]bool)
+	for _, r := range resources {
+		isRemoved[r.UID] = true
+	}
+
+	deletes := []interface{}{}
+	edges := 0
+	for _, r := range resources {
+		predicates := []string{}
+		for predicate := range r.inbound {
+			predicates = append(predicates, predicate)
+		}
+		sort.Strings(predicates)
+		for _, predicate := range predicates {
+			for _, subject := range r.inbound[predicate] {
+				if subject.UID == "" || isRemoved[subject.UID] {
+					continue
+				}
+				deletes = append(deletes, map[string]interface{}{
+					"uid":     subject.UID,
+					predicate: map[string]string{"uid": r.UID},
+				})
+				edges++
+			}
+		}
+		deletes = append(deletes, resource{ID{UID: r.UID}})
+	}
+	return deletes, edges
+}
+
+// removeOldDailyCosts removes daily costs of groups and cost snapshots of days before the time
+func removeOldDailyCosts(before time.Time) (int, error) {
 	q := `query {
-		resources(func: le(endTime, "` + utils.ConverTimeToRFC3339(utils.GetCurrentMonthStartTime().Add(-time.Hour*24*30*2)) + `")) @filter(has(isPod)) {
+		resources(func: le(day, "` + utils.ConverTimeToRFC3339(before) + `")) @filter(has(isGroupDailyCost) OR has(isCostSnapshot)) {
 			uid
 		}
 	}`
@@ -101,7 +265,26 @@ func retrievePodsWithEndTimeBeforeThreeMonths() ([]resource, error) {
 	newRoot := root{}
 	err := ExecuteQuery(q, &newRoot)
 	if err != nil {
-		return nil, err
+		return 0, err
 	}
-	return newRoot.Resources, nil
+	if len(newRoot.Resources) == 0 {
+		log.Println("No old daily costs are present in dgraph")
+		return 0, nil
+	}
+
+	_, err = MutateNode(newRoot.Resources, DELETE)
+	return len(newRoot.Resources), err
+}
+
+// getRetentionStart returns the start of the retention window if it is set, the start of the months before the
+// current month otherwise
+func getRetentionStart(months int) time.Time {
+	if retentionWindow > 0 {
+		return now().Add(-retentionWindow)
+	}
+	return getRetentionStartTime(months)
+}
+
+func getRetentionStartTime(months int) time.Time {
+	return utils.GetCurrentMonthStartTime().Add(-time.Hour * 24 * 30 * time.Duration(months))
 }
@@ -0,0 +1,73 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dgraph
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/vmware/purser/test/utils"
+)
+
+func TestRemovableResourceUnmarshalJSON(t *testing.T) {
+	resources := []removableResource{}
+	err := json.Unmarshal([]byte(`[{"uid":"0x1","~pod":[{"uid":"0x2"},{"uid":"0x3"}],"~container":[{"uid":"0x4"}],"name":"pod-a"}]`), &resources)
+	utils.Ok(t, err)
+	utils.Equals(t, 1, len(resources))
+	utils.Equals(t, "0x1", resources[0].UID)
+	utils.Equals(t, map[string][]ID{
+		"pod":       {{UID: "0x2"}, {UID: "0x3"}},
+		"container": {{UID: "0x4"}},
+	}, resources[0].inbound)
+}
+
+func TestGetRemovalMutations(t *testing.T) {
+	resources := []removableResource{
+		{UID: "0x1", inbound: map[string][]ID{"pod": {{UID: "0x2"}, {UID: "0x3"}}}},
+		{UID: "0x3", inbound: map[string][]ID{}},
+	}
+	deletes, edges := getRemovalMutations(resources)
+	utils.Equals(t, 1, edges)
+	utils.Equals(t, []interface{}{
+		map[string]interface{}{"uid": "0x2", "pod": map[string]string{"uid": "0x1"}},
+		resource{ID{UID: "0x1"}},
+		resource{ID{UID: "0x3"}},
+	}, deletes)
+}
+
+func TestUIDPredicates(t *testing.T) {
+	predicates := uidPredicates()
+	utils.Assert(t, len(predicates) > 2, "uid predicates are missing")
+	utils.Equals(t, "container", predicates[0])
+	for _, predicate := range predicates {
+		utils.Assert(t, predicate != "name", "string predicate is selected as uid predicate")
+	}
+}
+
+func TestGetRetentionStart(t *testing.T) {
+	clock, _, restore := mockClock()
+	defer restore()
+	defer SetRetentionWindow(0)
+
+	utils.Equals(t, getRetentionStartTime(2), getRetentionStart(2))
+	SetRetentionWindow(90 * 24 * time.Hour)
+	utils.Equals(t, clock.Add(-90*24*time.Hour), getRetentionStart(2))
+	SetRetentionWindow(-time.Hour)
+	utils.Equals(t, clock.Add(-90*24*time.Hour), getRetentionStart(2))
+}
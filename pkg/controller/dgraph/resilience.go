@@ -0,0 +1,238 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dgraph
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/dgraph-io/dgo/y"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Defaults of connection pools, retries and circuit breaking of requests to dgraph
+const (
+	DefaultPoolSize         = 1
+	DefaultRetries          = 3
+	DefaultRetryBackoff     = 100 * time.Millisecond
+	DefaultRetryMaxBackoff  = 5 * time.Second
+	DefaultBreakerThreshold = 5
+	DefaultBreakerCooldown  = 30 * time.Second
+)
+
+// ErrUnavailable is returned without sending the request while the circuit breaker is open, i.e. dgraph is down
+var ErrUnavailable = errors.New("dgraph is unavailable")
+
+var (
+	resilienceMu    sync.RWMutex
+	poolSize        = DefaultPoolSize
+	retries         = DefaultRetries
+	retryBackoff    = DefaultRetryBackoff
+	retryMaxBackoff = DefaultRetryMaxBackoff
+	breaker         = &circuitBreaker{threshold: DefaultBreakerThreshold, cooldown: DefaultBreakerCooldown}
+
+	// replaced in tests
+	sleep = sleepContext
+	now   = time.Now
+)
+
+// SetPoolSize sets the number of connections to dgraph of the write path and of read queries each, requests are
+// spread over the connections of a pool. It must be set before Start, sizes less than 1 are ignored.
+func SetPoolSize(size int) {
+	if size < 1 {
+		log.Errorf("dgraph connection pool size must be positive: %d", size)
+		return
+	}
+	resilienceMu.Lock()
+	defer resilienceMu.Unlock()
+	poolSize = size
+}
+
+// SetRetry sets how many times requests failing with transient errors(ex: dgraph is restarting) are retried, the
+// first retry is after backoff which doubles for each retry up to maxBackoff. 0 retries disables retrying.
+func SetRetry(attempts int, backoff, maxBackoff time.Duration) {
+	if attempts < 0 || backoff <= 0 || maxBackoff < backoff {
+		log.Errorf("invalid dgraph retry, attempts: %d, backoff: %v, max backoff: %v", attempts, backoff, maxBackoff)
+		return
+	}
+	resilienceMu.Lock()
+	defer resilienceMu.Unlock()
+	retries = attempts
+	retryBackoff = backoff
+	retryMaxBackoff = maxBackoff
+}
+
+// SetCircuitBreaker sets the number of consecutive requests failing with transient errors after retries which open
+// the circuit breaker and how long it stays open. 0 threshold disables the circuit breaker.
+func SetCircuitBreaker(threshold int, cooldown time.Duration) {
+	if threshold < 0 || cooldown <= 0 {
+		log.Errorf("invalid dgraph circuit breaker, threshold: %d, cooldown: %v", threshold, cooldown)
+		return
+	}
+	breaker.configure(threshold, cooldown)
+}
+
+// IsAvailable returns false while the circuit breaker is open
+func IsAvailable() bool {
+	return !breaker.isOpen()
+}
+
+func getPoolSize() int {
+	resilienceMu.RLock()
+	defer resilienceMu.RUnlock()
+	return poolSize
+}
+
+func getRetry() (int, time.Duration, time.Duration) {
+	resilienceMu.RLock()
+	defer resilienceMu.RUnlock()
+	return retries, retryBackoff, retryMaxBackoff
+}
+
+// isTransient returns whether the error is due to dgraph being unreachable, overloaded or aborting the transaction
+// so that the request can succeed when retried
+func isTransient(err error) bool {
+	if err == y.ErrAborted {
+		return true
+	}
+	switch status.Code(err) {
+	case codes.Unavailable, codes.ResourceExhausted, codes.Aborted:
+		return true
+	}
+	return false
+}
+
+// withRetry executes the request, retrying it with exponential backoff on transient errors until ctx is done.
+// Requests fail with ErrUnavailable while the circuit breaker is open, requests failing after retries count
+// towards opening it unless they are stopped by ctx.
+func withRetry(ctx context.Context, name string, request func() error) error {
+	if !breaker.allow() {
+		return ErrUnavailable
+	}
+	attempts, backoff, maxBackoff := getRetry()
+	err := request()
+	for attempt := 1; attempt <= attempts && isTransient(err) && ctx.Err() == nil; attempt++ {
+		log.Warnf("dgraph %s failed, retry %d of %d in %v, err: %v", name, attempt, attempts, backoff, err)
+		if sleep(ctx, backoff) != nil {
+			break
+		}
+		err = request()
+		backoff *= 2
+		if backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+	if ctx.Err() == nil {
+		breaker.record(!isTransient(err))
+	} else {
+		breaker.release()
+	}
+	return err
+}
+
+// sleepContext waits for d, returns the error of ctx if it is done before
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// circuitBreaker rejects requests to dgraph for cooldown after threshold consecutive requests failed, then lets one
+// request through to probe dgraph and closes if it succeeds
+type circuitBreaker struct {
+	mu        sync.Mutex
+	threshold int
+	cooldown  time.Duration
+	failures  int
+	openedAt  time.Time
+	isProbing bool
+	// onClose is called when the breaker closes, i.e. dgraph is reachable again
+	onClose func()
+}
+
+func (b *circuitBreaker) setOnClose(onClose func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.onClose = onClose
+}
+
+func (b *circuitBreaker) configure(threshold int, cooldown time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.threshold = threshold
+	b.cooldown = cooldown
+	b.failures = 0
+	b.isProbing = false
+}
+
+// allow returns whether a request can be sent
+func (b *circuitBreaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.threshold == 0 || b.failures < b.threshold {
+		return true
+	}
+	if b.isProbing || now().Sub(b.openedAt) < b.cooldown {
+		return false
+	}
+	b.isProbing = true
+	return true
+}
+
+// record records the result of a request, isReachable is false if it failed with a transient error
+func (b *circuitBreaker) record(isReachable bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.isProbing = false
+	if isReachable {
+		if b.threshold > 0 && b.failures >= b.threshold {
+			log.Info("dgraph is reachable, circuit breaker is closed")
+			if b.onClose != nil {
+				b.onClose()
+			}
+		}
+		b.failures = 0
+		return
+	}
+	b.failures++
+	if b.threshold > 0 && b.failures >= b.threshold {
+		log.Errorf("dgraph is unreachable, circuit breaker is open for %v", b.cooldown)
+		b.openedAt = now()
+	}
+}
+
+// release lets another request probe dgraph if the request probing it is stopped before its result is known
+func (b *circuitBreaker) release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.isProbing = false
+}
+
+func (b *circuitBreaker) isOpen() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.threshold > 0 && b.failures >= b.threshold
+}
@@ -0,0 +1,120 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dgraph
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/vmware/purser/test/utils"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func mockClock() (*time.Time, *[]time.Duration, func()) {
+	clock := time.Date(2018, 10, 1, 0, 0, 0, 0, time.UTC)
+	sleeps := []time.Duration{}
+	now = func() time.Time { return clock }
+	sleep = func(ctx context.Context, d time.Duration) error {
+		sleeps = append(sleeps, d)
+		return nil
+	}
+	return &clock, &sleeps, func() {
+		now = time.Now
+		sleep = sleepContext
+		SetRetry(DefaultRetries, DefaultRetryBackoff, DefaultRetryMaxBackoff)
+		SetCircuitBreaker(DefaultBreakerThreshold, DefaultBreakerCooldown)
+	}
+}
+
+func TestWithRetryBacksOff(t *testing.T) {
+	_, sleeps, restore := mockClock()
+	defer restore()
+	SetRetry(4, 100*time.Millisecond, 300*time.Millisecond)
+
+	calls := 0
+	err := withRetry(context.Background(), "query", func() error {
+		calls++
+		if calls < 4 {
+			return status.Error(codes.Unavailable, "connection refused")
+		}
+		return nil
+	})
+	utils.Ok(t, err)
+	utils.Equals(t, 4, calls)
+	utils.Equals(t, []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 300 * time.Millisecond}, *sleeps)
+}
+
+func TestWithRetryReturnsPermanentErrors(t *testing.T) {
+	_, sleeps, restore := mockClock()
+	defer restore()
+
+	calls := 0
+	err := withRetry(context.Background(), "query", func() error {
+		calls++
+		return fmt.Errorf("syntax error")
+	})
+	utils.Assert(t, err != nil, "expected error of the request")
+	utils.Equals(t, 1, calls)
+	utils.Equals(t, 0, len(*sleeps))
+	utils.Assert(t, IsAvailable(), "permanent errors must not open the circuit breaker")
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	clock, _, restore := mockClock()
+	defer restore()
+	SetRetry(0, time.Millisecond, time.Millisecond)
+	SetCircuitBreaker(2, time.Minute)
+
+	unavailable := func() error { return status.Error(codes.Unavailable, "connection refused") }
+	calls := 0
+	available := func() error {
+		calls++
+		return nil
+	}
+	utils.Assert(t, withRetry(context.Background(), "mutation", unavailable) != nil, "expected error of the request")
+	utils.Assert(t, IsAvailable(), "breaker must be closed before the threshold")
+	utils.Assert(t, withRetry(context.Background(), "mutation", unavailable) != nil, "expected error of the request")
+	utils.Assert(t, !IsAvailable(), "breaker must be open after the threshold")
+
+	utils.Equals(t, ErrUnavailable, withRetry(context.Background(), "query", available))
+	utils.Equals(t, 0, calls)
+
+	*clock = clock.Add(time.Minute)
+	utils.Ok(t, withRetry(context.Background(), "query", available))
+	utils.Equals(t, 1, calls)
+	utils.Assert(t, IsAvailable(), "breaker must be closed after a successful probe")
+}
+
+func TestWithRetryStopsWithContext(t *testing.T) {
+	_, _, restore := mockClock()
+	defer restore()
+	ctx, cancel := context.WithCancel(context.Background())
+
+	calls := 0
+	err := withRetry(ctx, "query", func() error {
+		calls++
+		cancel()
+		return status.Error(codes.Unavailable, "connection refused")
+	})
+	utils.Assert(t, err != nil, "expected error of the request")
+	utils.Equals(t, 1, calls)
+	utils.Assert(t, IsAvailable(), "stopped requests must not open the circuit breaker")
+}
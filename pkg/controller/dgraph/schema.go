@@ -0,0 +1,209 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dgraph
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// schema of purser predicates in dgraph, one predicate definition per line
+const schema = `
+	name: string @index(term, trigram) .
+	username: string @index(term) .
+	xid:  string @index(term) .
+	cluster: string @index(exact) .
+	startTime: dateTime @index(hour) .
+	endTime: dateTime @index(hour) .
+	phase: string @index(exact) .
+	scheduledTime: dateTime .
+	runningTime: dateTime .
+	terminatedTime: dateTime .
+	restarts: int @index(int) .
+	schedulingConstraints: string .
+	tolerations: string .
+	taints: string .
+	infraTags: string .
+	gpuShare: float .
+	capacityType: string @index(exact) .
+	deleted: bool .
+	isService: bool .
+	isServiceUnitCost: bool .
+	isPod: bool .
+	isVirtual: bool .
+	isContainer: bool .
+	isProc: bool .
+	isGroup: bool .
+	isGroupDailyCost: bool .
+	isCostSnapshot: bool .
+	isAlert: bool .
+	isNodeEvent: bool .
+	isScaleUp: bool .
+	isNodePrice: bool .
+	isStoragePrice: bool .
+	isRateCard: bool .
+	isLogin: bool .
+	pod: uid @reverse .
+	namespace: uid @reverse .
+	deployment: uid @reverse .
+	replicaset: uid @reverse .
+	statefulset: uid @reverse .
+	container: uid @reverse .
+	service: uid @reverse .
+	group: uid @reverse .
+	node: uid @reverse .
+	pv: uid @reverse .
+	daemonset: uid @reverse .
+	job: uid @reverse .
+	cronjob: uid @reverse .
+	storageClass: uid @reverse .
+	deploymentconfig: uid @reverse .
+	imagestream: uid @reverse .
+	label: uid @reverse .
+	key: string @index(term) .
+	value: string @index(term) .
+	os: string @index(exact) .
+	image: string .
+	imageRegistry: string @index(exact) .
+	imageRepository: string @index(exact) .
+	imageTag: string .
+	region: string @index(exact) .
+	zone: string @index(exact) .
+	rule: string @index(exact) .
+	state: string @index(exact) .
+	event: string @index(exact) .
+	nodeName: string @index(exact) .
+	nodeStartTime: dateTime .
+	source: string @index(exact) .
+	target: string @index(exact) .
+	revision: string @index(exact) .
+	application: string @index(exact) .
+	applicationTool: string .
+	helmRelease: string @index(exact) .
+	helmChart: string .
+	cpu: float .
+	cpuRequest: float .
+	cpuLimit: float .
+	cpuCapacity: float .
+	cpuPrice: float .
+	hourlyPrice: float .
+	cpuCarbon: float .
+	memory: float .
+	memoryRequest: float .
+	memoryLimit: float .
+	memoryCapacity: float .
+	memoryPrice: float .
+	memoryCarbon: float .
+	energy: float .
+	cpuUsage: float .
+	memoryUsage: float .
+	usageSamples: int .
+	requests: float .
+	cost: float .
+	day: dateTime @index(day) .
+	resourceType: string @index(exact) .
+	resourceName: string @index(exact) .
+	snapshotNamespace: string @index(exact) .
+	cpuCost: float .
+	memoryCost: float .
+	storageCost: float .
+	samples: int .
+	alertValue: float .
+	threshold: float .
+	costPer1kRequests: float .
+	storage: float .
+	storageRequest: float .
+	storageLimit: float .
+	storageCapacity: float .
+	storageUsed: float .
+	storageUsedPeak: float .
+	volumeCapacity: float .
+	usageTime: dateTime .
+	storagePrice: float .
+	ephemeralStorageRequest: float .
+	ephemeralStorageLimit: float .
+	ephemeralStoragePrice: float .
+	hugepagesRequest: float .
+	hugepagesLimit: float .
+	hugepagesPrice: float .
+	gpuRequest: float .
+	gpuPrice: float .
+	extendedResourcePrice: float .
+	ingressBandwidth: float .
+	egressBandwidth: float .
+	bandwidthPrice: float .
+	mtdCPU: float .
+	mtdCPUCost: float .
+	mtdCost: float .
+	mtdMemory: float .
+	mtdMemoryCost: float .
+	price: float .
+	podsCount: int .
+	syntheticRequests: bool .
+	syntheticCpuRequest: float .
+	syntheticMemoryRequest: float .
+	externalBytes: float .
+	externalWindowEnd: dateTime .
+`
+
+var indexRegex = regexp.MustCompile(`\s*@index\([^)]*\)`)
+
+// schemaDefinitions returns definition of each predicate in the schema keyed by predicate name
+func schemaDefinitions() map[string]string {
+	definitions := make(map[string]string)
+	for _, line := range strings.Split(schema, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		definitions[strings.TrimSpace(strings.SplitN(line, ":", 2)[0])] = line
+	}
+	return definitions
+}
+
+// IndexedPredicates returns sorted names of predicates which have an index in the schema
+func IndexedPredicates() []string {
+	var predicates []string
+	for predicate, definition := range schemaDefinitions() {
+		if indexRegex.MatchString(definition) {
+			predicates = append(predicates, predicate)
+		}
+	}
+	sort.Strings(predicates)
+	return predicates
+}
+
+// getIndexDefinitions returns definitions of given predicates with and without their index,
+// all indexed predicates are returned if no predicate is given.
+func getIndexDefinitions(predicates []string) (withIndex []string, withoutIndex []string, err error) {
+	if len(predicates) == 0 {
+		predicates = IndexedPredicates()
+	}
+	definitions := schemaDefinitions()
+	for _, predicate := range predicates {
+		definition, isPresent := definitions[predicate]
+		if !isPresent || !indexRegex.MatchString(definition) {
+			return nil, nil, fmt.Errorf("predicate %s has no index in purser schema", predicate)
+		}
+		withIndex = append(withIndex, definition)
+		withoutIndex = append(withoutIndex, indexRegex.ReplaceAllString(definition, ""))
+	}
+	return withIndex, withoutIndex, nil
+}
@@ -0,0 +1,44 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dgraph
+
+import (
+	"testing"
+
+	"github.com/vmware/purser/test/utils"
+)
+
+func TestGetIndexDefinitions(t *testing.T) {
+	withIndex, withoutIndex, err := getIndexDefinitions([]string{"name", "endTime"})
+	utils.Ok(t, err)
+	utils.Equals(t, []string{"name: string @index(term, trigram) .", "endTime: dateTime @index(hour) ."}, withIndex)
+	utils.Equals(t, []string{"name: string .", "endTime: dateTime ."}, withoutIndex)
+
+	_, _, err = getIndexDefinitions([]string{"isPod"})
+	utils.Assert(t, err != nil, "predicate without index is reindexed")
+	_, _, err = getIndexDefinitions([]string{"unknown"})
+	utils.Assert(t, err != nil, "unknown predicate is reindexed")
+}
+
+func TestIndexedPredicates(t *testing.T) {
+	predicates := IndexedPredicates()
+	withIndex, _, err := getIndexDefinitions(nil)
+	utils.Ok(t, err)
+	utils.Equals(t, len(predicates), len(withIndex))
+	utils.Assert(t, len(predicates) > 0 && predicates[0] == "application", "predicates are not sorted: %v", predicates)
+}
@@ -0,0 +1,201 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dgraph
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"sync"
+
+	"github.com/dgraph-io/dgo/protos/api"
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Login method of dgraph ACL and the metadata key of its access token, the dgo client of the dgraph version purser
+// is built with predates ACL so they are called over the connections directly
+const (
+	aclLoginMethod = "/api.Dgraph/Login"
+	aclTokenKey    = "accessJwt"
+)
+
+var (
+	securityMu           sync.Mutex
+	transportCredentials credentials.TransportCredentials
+	acl                  *aclSession
+
+	// replaced in tests
+	invokeLogin = func(ctx context.Context, cc *grpc.ClientConn, request *loginRequest, response *api.Response) error {
+		return cc.Invoke(ctx, aclLoginMethod, request, response)
+	}
+)
+
+// loginRequest message of dgraph ACL, a refresh token renews the access token without the password
+type loginRequest struct {
+	Userid       string `protobuf:"bytes,1,opt,name=userid,proto3" json:"userid,omitempty"`
+	Password     string `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"`
+	RefreshToken string `protobuf:"bytes,3,opt,name=refresh_token,json=refreshToken,proto3" json:"refresh_token,omitempty"`
+}
+
+func (m *loginRequest) Reset()         { *m = loginRequest{} }
+func (m *loginRequest) String() string { return proto.CompactTextString(m) }
+func (*loginRequest) ProtoMessage()    {}
+
+// jwt message of dgraph ACL, it is returned encoded in the json of the login response
+type jwt struct {
+	AccessJwt  string `protobuf:"bytes,1,opt,name=access_jwt,json=accessJwt,proto3" json:"access_jwt,omitempty"`
+	RefreshJwt string `protobuf:"bytes,2,opt,name=refresh_jwt,json=refreshJwt,proto3" json:"refresh_jwt,omitempty"`
+}
+
+func (m *jwt) Reset()         { *m = jwt{} }
+func (m *jwt) String() string { return proto.CompactTextString(m) }
+func (*jwt) ProtoMessage()    {}
+
+// SetTLS connects to dgraph over TLS, verifying its certificate with the CA certificate if caFile is given(system
+// CAs otherwise) and its name with serverName if it is given. The client certificate is presented if certFile and
+// keyFile are given, for dgraph requiring client certificates. It must be set before Start.
+func SetTLS(caFile, certFile, keyFile, serverName string) error {
+	config := &tls.Config{ServerName: serverName}
+	if caFile != "" {
+		ca, err := ioutil.ReadFile(caFile)
+		if err != nil {
+			return fmt.Errorf("unable to read dgraph CA certificate: %v", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(ca) {
+			return fmt.Errorf("no certificate found in dgraph CA certificate: %s", caFile)
+		}
+		config.RootCAs = pool
+	}
+	if certFile != "" || keyFile != "" {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return fmt.Errorf("unable to load dgraph client certificate: %v", err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+	securityMu.Lock()
+	defer securityMu.Unlock()
+	transportCredentials = credentials.NewTLS(config)
+	return nil
+}
+
+// SetACL logs in to dgraph ACL as the user, requests carry its access token which is renewed with the refresh
+// token, or the password if that fails, once dgraph rejects it. Empty user disables it, it must be set before Start.
+func SetACL(user, password string) {
+	securityMu.Lock()
+	defer securityMu.Unlock()
+	if user == "" {
+		acl = nil
+		return
+	}
+	acl = &aclSession{user: user, password: password}
+}
+
+// getDialOptions returns options of connections to dgraph for TLS and ACL if they are set
+func getDialOptions() []grpc.DialOption {
+	securityMu.Lock()
+	defer securityMu.Unlock()
+	options := []grpc.DialOption{grpc.WithInsecure()}
+	if transportCredentials != nil {
+		options = []grpc.DialOption{grpc.WithTransportCredentials(transportCredentials)}
+	}
+	if acl != nil {
+		options = append(options, grpc.WithUnaryInterceptor(acl.intercept))
+	}
+	return options
+}
+
+// aclSession holds the tokens of the user, shared by all connections to dgraph
+type aclSession struct {
+	user     string
+	password string
+
+	mu         sync.Mutex
+	accessJwt  string
+	refreshJwt string
+}
+
+// intercept adds the access token to requests, logging in first if there is none. Requests rejected as
+// unauthenticated(ex: the token expired) are sent once more with a renewed token.
+func (s *aclSession) intercept(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	if method == aclLoginMethod {
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+	token, err := s.getAccessJwt(ctx, cc, "")
+	if err != nil {
+		return err
+	}
+	err = invoker(metadata.AppendToOutgoingContext(ctx, aclTokenKey, token), method, req, reply, cc, opts...)
+	if status.Code(err) != codes.Unauthenticated {
+		return err
+	}
+	log.Debugf("dgraph rejected access token of %s, renewing it: %v", s.user, err)
+	token, err = s.getAccessJwt(ctx, cc, token)
+	if err != nil {
+		return err
+	}
+	return invoker(metadata.AppendToOutgoingContext(ctx, aclTokenKey, token), method, req, reply, cc, opts...)
+}
+
+// getAccessJwt returns the access token, it is renewed if there is none or it is the rejected token. Concurrent
+// requests rejected with the same token renew it once.
+func (s *aclSession) getAccessJwt(ctx context.Context, cc *grpc.ClientConn, rejected string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.accessJwt != "" && s.accessJwt != rejected {
+		return s.accessJwt, nil
+	}
+	if s.refreshJwt != "" {
+		token, err := login(ctx, cc, &loginRequest{RefreshToken: s.refreshJwt})
+		if err == nil {
+			s.accessJwt, s.refreshJwt = token.AccessJwt, token.RefreshJwt
+			return s.accessJwt, nil
+		}
+		log.Debugf("unable to refresh dgraph access token of %s, logging in again: %v", s.user, err)
+	}
+	token, err := login(ctx, cc, &loginRequest{Userid: s.user, Password: s.password})
+	if err != nil {
+		s.accessJwt, s.refreshJwt = "", ""
+		return "", fmt.Errorf("unable to log in to dgraph as %s: %v", s.user, err)
+	}
+	s.accessJwt, s.refreshJwt = token.AccessJwt, token.RefreshJwt
+	return s.accessJwt, nil
+}
+
+func login(ctx context.Context, cc *grpc.ClientConn, request *loginRequest) (*jwt, error) {
+	response := &api.Response{}
+	if err := invokeLogin(ctx, cc, request, response); err != nil {
+		return nil, err
+	}
+	token := &jwt{}
+	if err := proto.Unmarshal(response.Json, token); err != nil {
+		return nil, fmt.Errorf("unable to decode tokens of login response: %v", err)
+	}
+	if token.AccessJwt == "" {
+		return nil, fmt.Errorf("no access token in login response")
+	}
+	return token, nil
+}
@@ -0,0 +1,123 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dgraph
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/dgraph-io/dgo/protos/api"
+	"github.com/golang/protobuf/proto"
+	"github.com/vmware/purser/test/utils"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// mockLogin issues tokens numbered by login, refresh tokens are accepted if isRefreshValid
+func mockLogin(isRefreshValid bool) (*[]loginRequest, func()) {
+	requests := []loginRequest{}
+	invokeLogin = func(ctx context.Context, cc *grpc.ClientConn, request *loginRequest, response *api.Response) error {
+		requests = append(requests, *request)
+		if request.RefreshToken != "" && !isRefreshValid {
+			return status.Error(codes.Unauthenticated, "refresh token is expired")
+		}
+		if request.RefreshToken == "" && request.Password != "secret" {
+			return status.Error(codes.Unauthenticated, "invalid password")
+		}
+		var err error
+		n := len(requests)
+		response.Json, err = proto.Marshal(&jwt{AccessJwt: fmt.Sprintf("access-%d", n), RefreshJwt: fmt.Sprintf("refresh-%d", n)})
+		return err
+	}
+	return &requests, func() {
+		invokeLogin = func(ctx context.Context, cc *grpc.ClientConn, request *loginRequest, response *api.Response) error {
+			return cc.Invoke(ctx, aclLoginMethod, request, response)
+		}
+	}
+}
+
+// invokerAccepting returns an invoker which accepts only the token and records tokens of requests
+func invokerAccepting(token string, tokens *[]string) grpc.UnaryInvoker {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		md, _ := metadata.FromOutgoingContext(ctx)
+		values := md.Get(aclTokenKey)
+		if len(values) == 0 {
+			return status.Error(codes.Unauthenticated, "no access token")
+		}
+		*tokens = append(*tokens, values[0])
+		if values[0] != token {
+			return status.Error(codes.Unauthenticated, "Token is expired")
+		}
+		return nil
+	}
+}
+
+func TestACLSessionLogsInOnce(t *testing.T) {
+	requests, restore := mockLogin(true)
+	defer restore()
+
+	session := &aclSession{user: "purser", password: "secret"}
+	tokens := []string{}
+	for i := 0; i < 2; i++ {
+		utils.Ok(t, session.intercept(context.Background(), "/api.Dgraph/Query", nil, nil, nil, invokerAccepting("access-1", &tokens)))
+	}
+	utils.Equals(t, []loginRequest{{Userid: "purser", Password: "secret"}}, *requests)
+	utils.Equals(t, []string{"access-1", "access-1"}, tokens)
+}
+
+func TestACLSessionRefreshesRejectedToken(t *testing.T) {
+	requests, restore := mockLogin(true)
+	defer restore()
+
+	session := &aclSession{user: "purser", password: "secret", accessJwt: "access-0", refreshJwt: "refresh-0"}
+	tokens := []string{}
+	utils.Ok(t, session.intercept(context.Background(), "/api.Dgraph/Query", nil, nil, nil, invokerAccepting("access-1", &tokens)))
+	utils.Equals(t, []loginRequest{{RefreshToken: "refresh-0"}}, *requests)
+	utils.Equals(t, []string{"access-0", "access-1"}, tokens)
+	utils.Equals(t, "refresh-1", session.refreshJwt)
+}
+
+func TestACLSessionLogsInWhenRefreshFails(t *testing.T) {
+	requests, restore := mockLogin(false)
+	defer restore()
+
+	session := &aclSession{user: "purser", password: "secret", accessJwt: "access-0", refreshJwt: "refresh-0"}
+	tokens := []string{}
+	utils.Ok(t, session.intercept(context.Background(), "/api.Dgraph/Query", nil, nil, nil, invokerAccepting("access-2", &tokens)))
+	utils.Equals(t, []loginRequest{{RefreshToken: "refresh-0"}, {Userid: "purser", Password: "secret"}}, *requests)
+	utils.Equals(t, []string{"access-0", "access-2"}, tokens)
+}
+
+func TestACLSessionReturnsLoginErrors(t *testing.T) {
+	_, restore := mockLogin(true)
+	defer restore()
+
+	session := &aclSession{user: "purser", password: "wrong"}
+	tokens := []string{}
+	err := session.intercept(context.Background(), "/api.Dgraph/Query", nil, nil, nil, invokerAccepting("access-1", &tokens))
+	utils.Assert(t, err != nil, "request sent without logging in")
+	utils.Equals(t, 0, len(tokens))
+}
+
+func TestSetTLSRejectsMissingFiles(t *testing.T) {
+	utils.Assert(t, SetTLS("/nonexistent/ca.crt", "", "", "") != nil, "missing CA certificate accepted")
+	utils.Assert(t, SetTLS("", "/nonexistent/client.crt", "/nonexistent/client.key", "") != nil, "missing client certificate accepted")
+}
@@ -0,0 +1,200 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package hubble
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/vmware/purser/pkg/controller/discovery/telemetry"
+	"google.golang.org/grpc"
+)
+
+// CollectorName is the name used to select Hubble flows as interaction source
+const CollectorName = "hubble"
+
+// retryInterval is the wait before reconnecting to Hubble Relay when the flow stream breaks
+const retryInterval = 30 * time.Second
+
+var getFlowsStream = &grpc.StreamDesc{StreamName: "GetFlows", ServerStreams: true}
+
+// Collector streams flows from Hubble Relay and aggregates them into interactions between pods
+type Collector struct {
+	conn *grpc.ClientConn
+
+	mu    sync.Mutex
+	edges map[string]*telemetry.Edge
+}
+
+// Dial returns a collector connected to Hubble Relay at the given address
+func Dial(address string) (*Collector, error) {
+	conn, err := grpc.Dial(address, grpc.WithInsecure())
+	if err != nil {
+		return nil, err
+	}
+	return &Collector{conn: conn, edges: make(map[string]*telemetry.Edge)}, nil
+}
+
+// Configure connects to Hubble Relay, starts streaming flows and registers Hubble collector.
+// It does nothing if address is empty.
+func Configure(address string) error {
+	if address == "" {
+		return nil
+	}
+	collector, err := Dial(address)
+	if err != nil {
+		return err
+	}
+	go collector.run()
+	telemetry.RegisterCollector(collector)
+	log.Infof("hubble telemetry collector configured with relay: %s", address)
+	return nil
+}
+
+// Name returns CollectorName
+func (c *Collector) Name() string {
+	return CollectorName
+}
+
+// Collect returns interactions aggregated from flows since the last discovery
+func (c *Collector) Collect() ([]telemetry.Edge, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	edges := []telemetry.Edge{}
+	for _, edge := range c.edges {
+		if edge.Requests > 0 {
+			edge.SuccessRate = 1 - edge.Dropped/edge.Requests
+			if edge.SuccessRate < 0 {
+				edge.SuccessRate = 0
+			}
+		}
+		edges = append(edges, *edge)
+	}
+	c.edges = make(map[string]*telemetry.Edge)
+	return edges, nil
+}
+
+// run keeps the flow stream open, reconnecting after retryInterval when it breaks
+func (c *Collector) run() {
+	for {
+		err := c.streamFlows()
+		log.Errorf("hubble flow stream closed: %v, retrying in %v", err, retryInterval)
+		time.Sleep(retryInterval)
+	}
+}
+
+func (c *Collector) streamFlows() error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stream, err := c.conn.NewStream(ctx, getFlowsStream, getFlowsMethod)
+	if err != nil {
+		return err
+	}
+	if err = stream.SendMsg(&GetFlowsRequest{Follow: true}); err != nil {
+		return err
+	}
+	if err = stream.CloseSend(); err != nil {
+		return err
+	}
+	for {
+		response := &GetFlowsResponse{}
+		err = stream.RecvMsg(response)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if response.Flow != nil {
+			c.addFlow(response.Flow)
+		}
+	}
+}
+
+// addFlow counts the flow if it opens a connection between two pods. Connections are counted where
+// they leave the source pod, drops are counted wherever the policy denies them.
+func (c *Collector) addFlow(flow *Flow) {
+	if !isPodToPod(flow) || !isNewConnection(flow) {
+		return
+	}
+	isDropped := flow.Verdict == Dropped
+	if flow.TrafficDirection != Egress && !isDropped {
+		return
+	}
+
+	source := flow.Source.Namespace + telemetry.KeySpliter + flow.Source.PodName
+	destination := flow.Destination.Namespace + telemetry.KeySpliter + flow.Destination.PodName
+	key := source + "->" + destination
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	edge, isPresent := c.edges[key]
+	if !isPresent {
+		edge = &telemetry.Edge{SourcePod: source, DestinationPod: destination}
+		c.edges[key] = edge
+	}
+	if flow.TrafficDirection == Egress {
+		edge.Requests++
+	}
+	if isDropped {
+		edge.Dropped++
+	}
+	if port := destinationPort(flow); port != 0 && !containsPort(edge.Ports, port) {
+		edge.Ports = append(edge.Ports, port)
+	}
+}
+
+func isPodToPod(flow *Flow) bool {
+	return flow.Source != nil && flow.Destination != nil &&
+		flow.Source.PodName != "" && flow.Destination.PodName != ""
+}
+
+// isNewConnection returns true for tcp flows opening a connection(SYN without ACK) and for all other flows
+func isNewConnection(flow *Flow) bool {
+	if flow.L4 == nil || flow.L4.TCP == nil {
+		return true
+	}
+	flags := flow.L4.TCP.Flags
+	return flags != nil && flags.SYN && !flags.ACK
+}
+
+func destinationPort(flow *Flow) uint32 {
+	if flow.L4 == nil {
+		return 0
+	}
+	if flow.L4.TCP != nil {
+		return flow.L4.TCP.DestinationPort
+	}
+	if flow.L4.UDP != nil {
+		return flow.L4.UDP.DestinationPort
+	}
+	return 0
+}
+
+func containsPort(ports []uint32, port uint32) bool {
+	for _, existing := range ports {
+		if existing == port {
+			return true
+		}
+	}
+	return false
+}
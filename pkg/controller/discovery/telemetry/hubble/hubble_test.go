@@ -0,0 +1,63 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package hubble
+
+import (
+	"testing"
+
+	"github.com/vmware/purser/pkg/controller/discovery/telemetry"
+	"github.com/vmware/purser/test/utils"
+)
+
+func newTCPFlow(verdict Verdict, direction TrafficDirection, flags *TCPFlags) *Flow {
+	return &Flow{
+		Verdict:          verdict,
+		TrafficDirection: direction,
+		Source:           &Endpoint{Namespace: "default", PodName: "frontend"},
+		Destination:      &Endpoint{Namespace: "default", PodName: "backend"},
+		L4:               &Layer4{TCP: &TCP{SourcePort: 43210, DestinationPort: 8080, Flags: flags}},
+	}
+}
+
+func TestCollectFlows(t *testing.T) {
+	collector := &Collector{edges: make(map[string]*telemetry.Edge)}
+	syn := &TCPFlags{SYN: true}
+
+	collector.addFlow(newTCPFlow(Forwarded, Egress, syn))
+	collector.addFlow(newTCPFlow(Forwarded, Egress, syn))
+	collector.addFlow(newTCPFlow(Forwarded, Ingress, syn))
+	collector.addFlow(newTCPFlow(Dropped, Ingress, syn))
+	collector.addFlow(newTCPFlow(Forwarded, Egress, &TCPFlags{ACK: true}))
+	collector.addFlow(&Flow{Verdict: Forwarded, TrafficDirection: Egress, Source: &Endpoint{Namespace: "default", PodName: "frontend"}})
+
+	edges, err := collector.Collect()
+	utils.Ok(t, err)
+	expected := []telemetry.Edge{{
+		SourcePod:      "default:frontend",
+		DestinationPod: "default:backend",
+		Requests:       2,
+		Dropped:        1,
+		SuccessRate:    0.5,
+		Ports:          []uint32{8080},
+	}}
+	utils.Equals(t, expected, edges)
+
+	edges, err = collector.Collect()
+	utils.Ok(t, err)
+	utils.Equals(t, 0, len(edges))
+}
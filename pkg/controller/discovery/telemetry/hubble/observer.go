@@ -0,0 +1,132 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package hubble
+
+import (
+	"github.com/golang/protobuf/proto"
+)
+
+// Full method name of Observer service defined in observer.proto
+const getFlowsMethod = "/observer.Observer/GetFlows"
+
+// Verdict of a flow
+type Verdict int32
+
+// Verdicts of flows
+const (
+	VerdictUnknown Verdict = 0
+	Forwarded      Verdict = 1
+	Dropped        Verdict = 2
+	Error          Verdict = 3
+)
+
+// TrafficDirection of a flow relative to the endpoint where it is observed
+type TrafficDirection int32
+
+// Traffic directions of flows
+const (
+	TrafficDirectionUnknown TrafficDirection = 0
+	Ingress                 TrafficDirection = 1
+	Egress                  TrafficDirection = 2
+)
+
+// GetFlowsRequest message
+type GetFlowsRequest struct {
+	Number uint64 `protobuf:"varint,1,opt,name=number,proto3" json:"number,omitempty"`
+	Follow bool   `protobuf:"varint,3,opt,name=follow,proto3" json:"follow,omitempty"`
+}
+
+func (m *GetFlowsRequest) Reset()         { *m = GetFlowsRequest{} }
+func (m *GetFlowsRequest) String() string { return proto.CompactTextString(m) }
+func (*GetFlowsRequest) ProtoMessage()    {}
+
+// GetFlowsResponse message
+type GetFlowsResponse struct {
+	Flow *Flow `protobuf:"bytes,1,opt,name=flow,proto3" json:"flow,omitempty"`
+}
+
+func (m *GetFlowsResponse) Reset()         { *m = GetFlowsResponse{} }
+func (m *GetFlowsResponse) String() string { return proto.CompactTextString(m) }
+func (*GetFlowsResponse) ProtoMessage()    {}
+
+// Flow message
+type Flow struct {
+	Verdict          Verdict          `protobuf:"varint,2,opt,name=verdict,proto3,enum=observer.Verdict" json:"verdict,omitempty"`
+	L4               *Layer4          `protobuf:"bytes,6,opt,name=l4,proto3" json:"l4,omitempty"`
+	Source           *Endpoint        `protobuf:"bytes,8,opt,name=source,proto3" json:"source,omitempty"`
+	Destination      *Endpoint        `protobuf:"bytes,9,opt,name=destination,proto3" json:"destination,omitempty"`
+	TrafficDirection TrafficDirection `protobuf:"varint,22,opt,name=traffic_direction,json=trafficDirection,proto3,enum=observer.TrafficDirection" json:"traffic_direction,omitempty"`
+}
+
+func (m *Flow) Reset()         { *m = Flow{} }
+func (m *Flow) String() string { return proto.CompactTextString(m) }
+func (*Flow) ProtoMessage()    {}
+
+// Endpoint message
+type Endpoint struct {
+	Namespace string `protobuf:"bytes,3,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	PodName   string `protobuf:"bytes,5,opt,name=pod_name,json=podName,proto3" json:"pod_name,omitempty"`
+}
+
+func (m *Endpoint) Reset()         { *m = Endpoint{} }
+func (m *Endpoint) String() string { return proto.CompactTextString(m) }
+func (*Endpoint) ProtoMessage()    {}
+
+// Layer4 message
+type Layer4 struct {
+	TCP *TCP `protobuf:"bytes,1,opt,name=TCP,proto3" json:"TCP,omitempty"`
+	UDP *UDP `protobuf:"bytes,2,opt,name=UDP,proto3" json:"UDP,omitempty"`
+}
+
+func (m *Layer4) Reset()         { *m = Layer4{} }
+func (m *Layer4) String() string { return proto.CompactTextString(m) }
+func (*Layer4) ProtoMessage()    {}
+
+// TCP message
+type TCP struct {
+	SourcePort      uint32    `protobuf:"varint,1,opt,name=source_port,json=sourcePort,proto3" json:"source_port,omitempty"`
+	DestinationPort uint32    `protobuf:"varint,2,opt,name=destination_port,json=destinationPort,proto3" json:"destination_port,omitempty"`
+	Flags           *TCPFlags `protobuf:"bytes,3,opt,name=flags,proto3" json:"flags,omitempty"`
+}
+
+func (m *TCP) Reset()         { *m = TCP{} }
+func (m *TCP) String() string { return proto.CompactTextString(m) }
+func (*TCP) ProtoMessage()    {}
+
+// TCPFlags message
+type TCPFlags struct {
+	FIN bool `protobuf:"varint,1,opt,name=FIN,proto3" json:"FIN,omitempty"`
+	SYN bool `protobuf:"varint,2,opt,name=SYN,proto3" json:"SYN,omitempty"`
+	RST bool `protobuf:"varint,3,opt,name=RST,proto3" json:"RST,omitempty"`
+	PSH bool `protobuf:"varint,4,opt,name=PSH,proto3" json:"PSH,omitempty"`
+	ACK bool `protobuf:"varint,5,opt,name=ACK,proto3" json:"ACK,omitempty"`
+}
+
+func (m *TCPFlags) Reset()         { *m = TCPFlags{} }
+func (m *TCPFlags) String() string { return proto.CompactTextString(m) }
+func (*TCPFlags) ProtoMessage()    {}
+
+// UDP message
+type UDP struct {
+	SourcePort      uint32 `protobuf:"varint,1,opt,name=source_port,json=sourcePort,proto3" json:"source_port,omitempty"`
+	DestinationPort uint32 `protobuf:"varint,2,opt,name=destination_port,json=destinationPort,proto3" json:"destination_port,omitempty"`
+}
+
+func (m *UDP) Reset()         { *m = UDP{} }
+func (m *UDP) String() string { return proto.CompactTextString(m) }
+func (*UDP) ProtoMessage()    {}
@@ -0,0 +1,146 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package istio
+
+import (
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/vmware/purser/pkg/controller/discovery/telemetry"
+)
+
+// CollectorName is the name used to select Istio telemetry as interaction source
+const CollectorName = "istio"
+
+// Labels of istio standard metrics reported by client side(source) proxies. Source pod labels are
+// attached by Prometheus kubernetes pod discovery.
+const (
+	sourceNamespaceLabel      = "namespace"
+	sourcePodLabel            = "pod"
+	destinationNamespaceLabel = "destination_service_namespace"
+	destinationServiceLabel   = "destination_service_name"
+	groupBy                   = "(" + sourceNamespaceLabel + ", " + sourcePodLabel + ", " + destinationNamespaceLabel + ", " + destinationServiceLabel + ")"
+
+	requestsQuery = `sum(increase(istio_requests_total{reporter="source"}[` + telemetry.WindowRange + `])) by ` + groupBy
+	successQuery  = `sum(increase(istio_requests_total{reporter="source", response_code!~"5.."}[` + telemetry.WindowRange + `])) by ` + groupBy
+	latencyQuery  = `sum(increase(istio_request_duration_milliseconds_sum{reporter="source"}[` + telemetry.WindowRange + `])) by ` + groupBy +
+		` / sum(increase(istio_request_duration_milliseconds_count{reporter="source"}[` + telemetry.WindowRange + `])) by ` + groupBy
+	bytesQuery = `sum(increase(istio_request_bytes_sum{reporter="source"}[` + telemetry.WindowRange + `])) by ` + groupBy +
+		` + sum(increase(istio_response_bytes_sum{reporter="source"}[` + telemetry.WindowRange + `])) by ` + groupBy
+)
+
+var keyLabels = []string{sourceNamespaceLabel, sourcePodLabel, destinationNamespaceLabel, destinationServiceLabel}
+
+// Collector builds interactions from Istio/Envoy request metrics stored in Prometheus
+type Collector struct {
+	prometheus *telemetry.PrometheusClient
+}
+
+// NewCollector returns a collector querying the Prometheus server scraping Istio metrics
+func NewCollector(prometheusURL string, timeout time.Duration) *Collector {
+	return &Collector{prometheus: telemetry.NewPrometheusClient(prometheusURL, timeout)}
+}
+
+// Configure registers Istio collector if prometheus url is given
+func Configure(prometheusURL string, timeout time.Duration) {
+	if prometheusURL == "" {
+		return
+	}
+	telemetry.RegisterCollector(NewCollector(prometheusURL, timeout))
+	log.Infof("istio telemetry collector configured with prometheus: %s", prometheusURL)
+}
+
+// Name returns CollectorName
+func (c *Collector) Name() string {
+	return CollectorName
+}
+
+// Collect returns source pod to destination service request counts, average latency, success rate and bytes of the
+// last hour. Requests leaving the mesh are returned as external edges with their bytes.
+func (c *Collector) Collect() ([]telemetry.Edge, error) {
+	requests, err := c.prometheus.Query(requestsQuery)
+	if err != nil {
+		return nil, err
+	}
+	successes := c.queryByKey(successQuery)
+	latencies := c.queryByKey(latencyQuery)
+	bytes := c.queryByKey(bytesQuery)
+
+	edges := []telemetry.Edge{}
+	for _, sample := range requests {
+		key := telemetry.SampleKey(sample, keyLabels...)
+		edge, isValid := newEdge(sample)
+		if !isValid {
+			if edge, isExternal := newExternalEdge(sample); isExternal {
+				edge.Bytes = bytes[key]
+				edges = append(edges, edge)
+			}
+			continue
+		}
+		edge.LatencyMs = latencies[key]
+		edge.Bytes = bytes[key]
+		if edge.Requests > 0 {
+			edge.SuccessRate = successes[key] / edge.Requests
+		}
+		edges = append(edges, edge)
+	}
+	return edges, nil
+}
+
+// queryByKey returns values of samples by their key, empty map if query fails
+func (c *Collector) queryByKey(query string) map[string]float64 {
+	values := make(map[string]float64)
+	samples, err := c.prometheus.Query(query)
+	if err != nil {
+		log.Errorf("istio telemetry query failed: %v", err)
+		return values
+	}
+	for _, sample := range samples {
+		values[telemetry.SampleKey(sample, keyLabels...)] = sample.Value
+	}
+	return values
+}
+
+func newEdge(sample telemetry.Sample) (telemetry.Edge, bool) {
+	srcNamespace, srcPod := sample.Metric[sourceNamespaceLabel], sample.Metric[sourcePodLabel]
+	dstNamespace, dstService := sample.Metric[destinationNamespaceLabel], sample.Metric[destinationServiceLabel]
+	// traffic leaving the mesh(ex: PassthroughCluster) has no destination service namespace
+	if srcNamespace == "" || srcPod == "" || dstNamespace == "" || dstService == "" || dstNamespace == "unknown" {
+		return telemetry.Edge{}, false
+	}
+	return telemetry.Edge{
+		SourcePod:          srcNamespace + telemetry.KeySpliter + srcPod,
+		DestinationService: dstNamespace + telemetry.KeySpliter + dstService,
+		Requests:           sample.Value,
+	}, true
+}
+
+// newExternalEdge returns an external edge for requests of a pod leaving the mesh(ex: PassthroughCluster), which have
+// no destination service namespace
+func newExternalEdge(sample telemetry.Sample) (telemetry.Edge, bool) {
+	srcNamespace, srcPod := sample.Metric[sourceNamespaceLabel], sample.Metric[sourcePodLabel]
+	dstNamespace := sample.Metric[destinationNamespaceLabel]
+	if srcNamespace == "" || srcPod == "" || (dstNamespace != "" && dstNamespace != "unknown") {
+		return telemetry.Edge{}, false
+	}
+	return telemetry.Edge{
+		SourcePod: srcNamespace + telemetry.KeySpliter + srcPod,
+		Requests:  sample.Value,
+		External:  true,
+	}, true
+}
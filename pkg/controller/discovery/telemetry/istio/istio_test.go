@@ -0,0 +1,63 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package istio
+
+import (
+	"testing"
+
+	"github.com/vmware/purser/pkg/controller/discovery/telemetry"
+	"github.com/vmware/purser/test/utils"
+)
+
+func TestNewEdge(t *testing.T) {
+	sample := telemetry.Sample{
+		Metric: map[string]string{
+			"namespace":                     "default",
+			"pod":                           "productpage-v1-0",
+			"destination_service_namespace": "default",
+			"destination_service_name":      "reviews",
+		},
+		Value: 120,
+	}
+	edge, isValid := newEdge(sample)
+	utils.Assert(t, isValid, "valid sample rejected")
+	utils.Equals(t, telemetry.Edge{SourcePod: "default:productpage-v1-0", DestinationService: "default:reviews", Requests: 120}, edge)
+
+	sample.Metric["destination_service_namespace"] = "unknown"
+	_, isValid = newEdge(sample)
+	utils.Assert(t, !isValid, "sample without destination namespace accepted")
+}
+
+func TestNewExternalEdge(t *testing.T) {
+	sample := telemetry.Sample{
+		Metric: map[string]string{
+			"namespace":                     "default",
+			"pod":                           "productpage-v1-0",
+			"destination_service_namespace": "unknown",
+			"destination_service_name":      "PassthroughCluster",
+		},
+		Value: 12,
+	}
+	edge, isExternal := newExternalEdge(sample)
+	utils.Assert(t, isExternal, "request leaving the mesh is not external")
+	utils.Equals(t, telemetry.Edge{SourcePod: "default:productpage-v1-0", Requests: 12, External: true}, edge)
+
+	sample.Metric["destination_service_namespace"] = "default"
+	_, isExternal = newExternalEdge(sample)
+	utils.Assert(t, !isExternal, "request to a service of the mesh is external")
+}
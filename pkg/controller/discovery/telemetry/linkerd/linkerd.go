@@ -0,0 +1,125 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package linkerd
+
+import (
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/vmware/purser/pkg/controller/discovery/telemetry"
+)
+
+// CollectorName is the name used to select Linkerd telemetry as interaction source
+const CollectorName = "linkerd"
+
+// Labels of outbound metrics reported by linkerd proxies and scraped by linkerd viz Prometheus
+const (
+	sourceNamespaceLabel      = "namespace"
+	sourcePodLabel            = "pod"
+	destinationNamespaceLabel = "dst_namespace"
+	destinationPodLabel       = "dst_pod"
+	groupBy                   = "(" + sourceNamespaceLabel + ", " + sourcePodLabel + ", " + destinationNamespaceLabel + ", " + destinationPodLabel + ")"
+	outbound                  = `direction="outbound", dst_pod!=""`
+
+	requestsQuery = `sum(increase(response_total{` + outbound + `}[` + telemetry.WindowRange + `])) by ` + groupBy
+	successQuery  = `sum(increase(response_total{` + outbound + `, classification="success"}[` + telemetry.WindowRange + `])) by ` + groupBy
+	latencyQuery  = `sum(increase(response_latency_ms_sum{` + outbound + `}[` + telemetry.WindowRange + `])) by ` + groupBy +
+		` / sum(increase(response_latency_ms_count{` + outbound + `}[` + telemetry.WindowRange + `])) by ` + groupBy
+	bytesQuery = `sum(increase(tcp_write_bytes_total{` + outbound + `}[` + telemetry.WindowRange + `])) by ` + groupBy +
+		` + sum(increase(tcp_read_bytes_total{` + outbound + `}[` + telemetry.WindowRange + `])) by ` + groupBy
+)
+
+var keyLabels = []string{sourceNamespaceLabel, sourcePodLabel, destinationNamespaceLabel, destinationPodLabel}
+
+// Collector builds interactions from metrics of Linkerd viz extension
+type Collector struct {
+	prometheus *telemetry.PrometheusClient
+}
+
+// NewCollector returns a collector querying Linkerd viz Prometheus
+func NewCollector(prometheusURL string, timeout time.Duration) *Collector {
+	return &Collector{prometheus: telemetry.NewPrometheusClient(prometheusURL, timeout)}
+}
+
+// Configure registers Linkerd collector if viz prometheus url is given
+func Configure(prometheusURL string, timeout time.Duration) {
+	if prometheusURL == "" {
+		return
+	}
+	telemetry.RegisterCollector(NewCollector(prometheusURL, timeout))
+	log.Infof("linkerd telemetry collector configured with prometheus: %s", prometheusURL)
+}
+
+// Name returns CollectorName
+func (c *Collector) Name() string {
+	return CollectorName
+}
+
+// Collect returns pod to pod request counts, average latency, success rate and bytes of the last hour
+func (c *Collector) Collect() ([]telemetry.Edge, error) {
+	requests, err := c.prometheus.Query(requestsQuery)
+	if err != nil {
+		return nil, err
+	}
+	successes := c.queryByKey(successQuery)
+	latencies := c.queryByKey(latencyQuery)
+	bytes := c.queryByKey(bytesQuery)
+
+	edges := []telemetry.Edge{}
+	for _, sample := range requests {
+		edge, isValid := newEdge(sample)
+		if !isValid {
+			continue
+		}
+		key := telemetry.SampleKey(sample, keyLabels...)
+		edge.LatencyMs = latencies[key]
+		edge.Bytes = bytes[key]
+		if edge.Requests > 0 {
+			edge.SuccessRate = successes[key] / edge.Requests
+		}
+		edges = append(edges, edge)
+	}
+	return edges, nil
+}
+
+// queryByKey returns values of samples by their key, empty map if query fails
+func (c *Collector) queryByKey(query string) map[string]float64 {
+	values := make(map[string]float64)
+	samples, err := c.prometheus.Query(query)
+	if err != nil {
+		log.Errorf("linkerd telemetry query failed: %v", err)
+		return values
+	}
+	for _, sample := range samples {
+		values[telemetry.SampleKey(sample, keyLabels...)] = sample.Value
+	}
+	return values
+}
+
+func newEdge(sample telemetry.Sample) (telemetry.Edge, bool) {
+	srcNamespace, srcPod := sample.Metric[sourceNamespaceLabel], sample.Metric[sourcePodLabel]
+	dstNamespace, dstPod := sample.Metric[destinationNamespaceLabel], sample.Metric[destinationPodLabel]
+	if srcNamespace == "" || srcPod == "" || dstNamespace == "" || dstPod == "" {
+		return telemetry.Edge{}, false
+	}
+	return telemetry.Edge{
+		SourcePod:      srcNamespace + telemetry.KeySpliter + srcPod,
+		DestinationPod: dstNamespace + telemetry.KeySpliter + dstPod,
+		Requests:       sample.Value,
+	}, true
+}
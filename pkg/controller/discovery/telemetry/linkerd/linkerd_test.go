@@ -0,0 +1,44 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package linkerd
+
+import (
+	"testing"
+
+	"github.com/vmware/purser/pkg/controller/discovery/telemetry"
+	"github.com/vmware/purser/test/utils"
+)
+
+func TestNewEdge(t *testing.T) {
+	sample := telemetry.Sample{
+		Metric: map[string]string{
+			"namespace":     "emojivoto",
+			"pod":           "web-0",
+			"dst_namespace": "emojivoto",
+			"dst_pod":       "voting-0",
+		},
+		Value: 42,
+	}
+	edge, isValid := newEdge(sample)
+	utils.Assert(t, isValid, "valid sample rejected")
+	utils.Equals(t, telemetry.Edge{SourcePod: "emojivoto:web-0", DestinationPod: "emojivoto:voting-0", Requests: 42}, edge)
+
+	delete(sample.Metric, "dst_pod")
+	_, isValid = newEdge(sample)
+	utils.Assert(t, !isValid, "sample without destination pod accepted")
+}
@@ -0,0 +1,107 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package telemetry
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Sample is a single series of an instant vector returned by Prometheus
+type Sample struct {
+	Metric map[string]string
+	Value  float64
+}
+
+// PrometheusClient runs instant queries against Prometheus HTTP API
+type PrometheusClient struct {
+	url        string
+	httpClient *http.Client
+}
+
+type prometheusResponse struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+	Data   struct {
+		ResultType string `json:"resultType"`
+		Result     []struct {
+			Metric map[string]string `json:"metric"`
+			Value  []interface{}     `json:"value"`
+		} `json:"result"`
+	} `json:"data"`
+}
+
+// NewPrometheusClient returns a client for the Prometheus server at the given url(ex: http://prometheus:9090)
+func NewPrometheusClient(address string, timeout time.Duration) *PrometheusClient {
+	return &PrometheusClient{
+		url:        strings.TrimSuffix(address, "/"),
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+// Query runs an instant query and returns the samples of resulting vector
+func (c *PrometheusClient) Query(query string) ([]Sample, error) {
+	resp, err := c.httpClient.Get(c.url + "/api/v1/query?query=" + url.QueryEscape(query))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	response := prometheusResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("unable to decode prometheus response: %v", err)
+	}
+	if response.Status != "success" {
+		return nil, fmt.Errorf("prometheus query failed: %s", response.Error)
+	}
+	if response.Data.ResultType != "vector" {
+		return nil, fmt.Errorf("unexpected prometheus result type: %s", response.Data.ResultType)
+	}
+
+	samples := []Sample{}
+	for _, result := range response.Data.Result {
+		if len(result.Value) != 2 {
+			continue
+		}
+		value, isString := result.Value[1].(string)
+		if !isString {
+			continue
+		}
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			continue
+		}
+		samples = append(samples, Sample{Metric: result.Metric, Value: parsed})
+	}
+	return samples, nil
+}
+
+// SampleKey returns the values of given labels of the sample joined by KeySpliter, used to
+// join samples of different queries grouped by the same labels
+func SampleKey(sample Sample, labels ...string) string {
+	values := make([]string, len(labels))
+	for i, label := range labels {
+		values[i] = sample.Metric[label]
+	}
+	return strings.Join(values, KeySpliter)
+}
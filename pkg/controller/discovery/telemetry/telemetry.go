@@ -0,0 +1,350 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package telemetry
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/vmware/purser/pkg/controller/dgraph/models"
+	"github.com/vmware/purser/pkg/controller/utils"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+)
+
+// CaptureSource is the name of the default interaction source which captures tcp connections inside containers
+const CaptureSource = "capture"
+
+// KeySpliter splits the key into resource namespace and name used for processing Xids
+const KeySpliter = ":"
+
+// Window is the interval between two discoveries over which collectors report interactions,
+// WindowRange is the same interval as a Prometheus range.
+const (
+	Window      = time.Hour
+	WindowRange = "1h"
+)
+
+// Edge is an interaction between two pods observed by a telemetry source. Collectors which only know
+// the destination service set DestinationService instead of DestinationPod, requests are then
+// distributed evenly across pods of the service. Dropped and Ports are reported by network flow
+// sources(ex: hubble) where Dropped is the number of requests denied by network policies and Ports
+// are the destination ports used. Bytes are the bytes sent and received over the interaction, edges
+// to destinations outside the cluster are External and have no destination.
+type Edge struct {
+	SourcePod          string
+	DestinationPod     string
+	DestinationService string
+	Requests           float64
+	LatencyMs          float64
+	SuccessRate        float64
+	Dropped            float64
+	Ports              []uint32
+	Bytes              float64
+	External           bool
+}
+
+// Collector is implemented by every telemetry source(ex: service meshes, CNI flow logs)
+// which can report interactions between pods.
+type Collector interface {
+	// Name returns the unique name used to select the collector in config
+	Name() string
+	// Collect returns interactions observed since the last discovery
+	Collect() ([]Edge, error)
+}
+
+var (
+	collectorsMu    sync.RWMutex
+	collectors      = make(map[string]Collector)
+	selectedSources = []string{CaptureSource}
+)
+
+// RegisterCollector makes a telemetry collector available for selection by its name.
+// Registering a collector with an existing name replaces it.
+func RegisterCollector(collector Collector) {
+	collectorsMu.Lock()
+	defer collectorsMu.Unlock()
+	collectors[collector.Name()] = collector
+	log.Debugf("registered interaction collector: %s", collector.Name())
+}
+
+// SelectSources sets the interaction sources used by discovery. CaptureSource is always available,
+// all other sources must be registered.
+func SelectSources(names []string) error {
+	collectorsMu.Lock()
+	defer collectorsMu.Unlock()
+	if len(names) == 0 {
+		return fmt.Errorf("at least one interaction source must be selected")
+	}
+	for _, name := range names {
+		if _, isPresent := collectors[name]; !isPresent && name != CaptureSource {
+			return fmt.Errorf("interaction source: %s is not registered", name)
+		}
+	}
+	selectedSources = names
+	log.Infof("selected interaction sources: %v", names)
+	return nil
+}
+
+// IsCaptureSelected returns true if interactions should be captured from inside containers
+func IsCaptureSelected() bool {
+	collectorsMu.RLock()
+	defer collectorsMu.RUnlock()
+	for _, name := range selectedSources {
+		if name == CaptureSource {
+			return true
+		}
+	}
+	return false
+}
+
+// CollectAndStoreInteractions collects interactions from selected telemetry collectors and stores them in Dgraph.
+func CollectAndStoreInteractions(client *kubernetes.Clientset) {
+	edges := []Edge{}
+	collectorsMu.RLock()
+	for _, name := range selectedSources {
+		collector, isPresent := collectors[name]
+		if !isPresent {
+			continue
+		}
+		collected, err := collector.Collect()
+		if err != nil {
+			log.Errorf("failed to collect interactions from %s: %v", name, err)
+			continue
+		}
+		log.Infof("collected (%d) interactions from %s", len(collected), name)
+		edges = append(edges, collected...)
+	}
+	collectorsMu.RUnlock()
+
+	if len(edges) == 0 {
+		return
+	}
+	edges = resolveServiceDestinations(client, edges)
+	end := time.Now()
+	interactions := aggregateEdges(edges)
+	storeEdges(interactions, end)
+	storeExternalTraffic(aggregateExternalBytes(edges), end)
+	storeServiceUnitCosts(interactions, end)
+}
+
+// resolveServiceDestinations replaces edges to a service with edges to each pod selected by the service
+func resolveServiceDestinations(client *kubernetes.Clientset, edges []Edge) []Edge {
+	servicePods := make(map[string][]string)
+	resolved := []Edge{}
+	for _, edge := range edges {
+		if edge.DestinationPod != "" || edge.DestinationService == "" {
+			resolved = append(resolved, edge)
+			continue
+		}
+		pods, isPresent := servicePods[edge.DestinationService]
+		if !isPresent {
+			pods = retrievePodsOfService(client, edge.DestinationService)
+			servicePods[edge.DestinationService] = pods
+		}
+		for _, pod := range pods {
+			podEdge := edge
+			podEdge.DestinationPod = pod
+			podEdge.DestinationService = ""
+			podEdge.Requests = edge.Requests / float64(len(pods))
+			podEdge.Dropped = edge.Dropped / float64(len(pods))
+			podEdge.Bytes = edge.Bytes / float64(len(pods))
+			resolved = append(resolved, podEdge)
+		}
+	}
+	return resolved
+}
+
+func retrievePodsOfService(client *kubernetes.Clientset, serviceXID string) []string {
+	namespace, name := splitXID(serviceXID)
+	svc, err := client.CoreV1().Services(namespace).Get(name, metav1.GetOptions{})
+	if err != nil {
+		log.Debugf("unable to retrieve service: %s, err: %v", serviceXID, err)
+		return nil
+	}
+	if len(svc.Spec.Selector) == 0 {
+		return nil
+	}
+	options := metav1.ListOptions{
+		LabelSelector: labels.Set(svc.Spec.Selector).AsSelector().String(),
+	}
+	pods := utils.RetrievePodList(client, options)
+	if pods == nil {
+		return nil
+	}
+	podsXIDs := []string{}
+	for _, pod := range pods.Items {
+		if pod.Namespace == namespace {
+			podsXIDs = append(podsXIDs, pod.Namespace+KeySpliter+pod.Name)
+		}
+	}
+	return podsXIDs
+}
+
+// aggregateEdges merges edges between the same pods. Requests, dropped requests and bytes are added, ports
+// are merged while latency and success rate are averaged weighted by requests.
+func aggregateEdges(edges []Edge) map[string]map[string]models.PodInteractionMetrics {
+	interactions := make(map[string]map[string]models.PodInteractionMetrics)
+	for _, edge := range edges {
+		if edge.SourcePod == "" || edge.DestinationPod == "" {
+			continue
+		}
+		if _, isPresent := interactions[edge.SourcePod]; !isPresent {
+			interactions[edge.SourcePod] = make(map[string]models.PodInteractionMetrics)
+		}
+		current := interactions[edge.SourcePod][edge.DestinationPod]
+		total := current.Count + edge.Requests
+		if total > 0 {
+			current.Latency = (current.Latency*current.Count + edge.LatencyMs*edge.Requests) / total
+			current.SuccessRate = (current.SuccessRate*current.Count + edge.SuccessRate*edge.Requests) / total
+		}
+		current.Count = total
+		current.RequestsPerSecond = total / Window.Seconds()
+		current.Dropped += edge.Dropped
+		current.Bytes += edge.Bytes
+		current.Ports = mergePorts(current.Ports, edge.Ports)
+		interactions[edge.SourcePod][edge.DestinationPod] = current
+	}
+	return interactions
+}
+
+// mergePorts adds ports to the comma separated sorted list of ports
+func mergePorts(list string, ports []uint32) string {
+	if len(ports) == 0 {
+		return list
+	}
+	unique := make(map[uint64]bool)
+	for _, port := range strings.Split(list, ",") {
+		if value, err := strconv.ParseUint(port, 10, 32); err == nil {
+			unique[value] = true
+		}
+	}
+	for _, port := range ports {
+		unique[uint64(port)] = true
+	}
+	sorted := []uint64{}
+	for port := range unique {
+		sorted = append(sorted, port)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	merged := []string{}
+	for _, port := range sorted {
+		merged = append(merged, strconv.FormatUint(port, 10))
+	}
+	return strings.Join(merged, ",")
+}
+
+// storeEdges stores the interactions with the end of the window in which they were seen
+func storeEdges(interactions map[string]map[string]models.PodInteractionMetrics, end time.Time) {
+	log.Info("Storing telemetry interactions ....")
+	for srcPod, communication := range interactions {
+		dstPods := []string{}
+		metrics := []models.PodInteractionMetrics{}
+		for dstPod, metric := range communication {
+			metric.WindowEnd = end
+			dstPods = append(dstPods, dstPod)
+			metrics = append(metrics, metric)
+		}
+		err := models.StorePodsInteractionWithMetrics(srcPod, dstPods, metrics)
+		if err != nil {
+			log.Errorf("failed to store pod interaction in Dgraph %v", err)
+		}
+	}
+	log.Info("Finished storing telemetry interactions.")
+}
+
+// aggregateExternalBytes returns bytes exchanged by each source pod with destinations outside the cluster
+func aggregateExternalBytes(edges []Edge) map[string]float64 {
+	external := make(map[string]float64)
+	for _, edge := range edges {
+		if edge.External && edge.SourcePod != "" {
+			external[edge.SourcePod] += edge.Bytes
+		}
+	}
+	return external
+}
+
+func storeExternalTraffic(external map[string]float64, end time.Time) {
+	for pod, bytes := range external {
+		if err := models.StorePodExternalTraffic(pod, bytes, end); err != nil {
+			log.Errorf("failed to store external traffic of pod: %s, err: %v", pod, err)
+		}
+	}
+}
+
+// storeServiceUnitCosts stores requests served by each service in the window along with cost of its pods
+func storeServiceUnitCosts(interactions map[string]map[string]models.PodInteractionMetrics, end time.Time) {
+	services, err := models.RetrieveLiveServicesWithPods()
+	if err != nil {
+		log.Errorf("unable to retrieve services for unit costs: %v", err)
+		return
+	}
+	for service, unitCost := range computeServiceUnitCosts(services, getInboundRequests(interactions)) {
+		if _, err := models.StoreServiceUnitCost(service, end, Window, unitCost.requests, unitCost.cost); err != nil {
+			log.Errorf("failed to store unit cost of service: %s, err: %v", service, err)
+		}
+	}
+}
+
+// getInboundRequests returns requests received by each destination pod
+func getInboundRequests(interactions map[string]map[string]models.PodInteractionMetrics) map[string]float64 {
+	inbound := make(map[string]float64)
+	for _, communication := range interactions {
+		for dstPod, metric := range communication {
+			inbound[dstPod] += metric.Count
+		}
+	}
+	return inbound
+}
+
+type serviceUnitCost struct {
+	requests float64
+	cost     float64
+}
+
+// computeServiceUnitCosts returns requests and cost in the window of each service which received requests
+func computeServiceUnitCosts(services []models.Service, inbound map[string]float64) map[string]serviceUnitCost {
+	unitCosts := make(map[string]serviceUnitCost)
+	for _, service := range services {
+		requests, cost := 0.0, 0.0
+		for _, pod := range service.Pod {
+			requests += inbound[pod.Xid]
+			hourlyCost := pod.CPURequest*pod.CPUPrice + pod.MemoryRequest*pod.MemoryPrice + pod.StorageRequest*models.DefaultStorageCostInFloat64
+			cost += hourlyCost * Window.Hours()
+		}
+		if requests > 0 {
+			unitCosts[service.Xid] = serviceUnitCost{requests: requests, cost: cost}
+		}
+	}
+	return unitCosts
+}
+
+func splitXID(xid string) (string, string) {
+	parts := strings.SplitN(xid, KeySpliter, 2)
+	if len(parts) < 2 {
+		return "", xid
+	}
+	return parts[0], parts[1]
+}
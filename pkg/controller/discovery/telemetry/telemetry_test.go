@@ -0,0 +1,109 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package telemetry
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/vmware/purser/pkg/controller/dgraph"
+	"github.com/vmware/purser/pkg/controller/dgraph/models"
+	"github.com/vmware/purser/test/utils"
+)
+
+func TestPrometheusQuery(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		utils.Equals(t, "/api/v1/query", r.URL.Path)
+		utils.Equals(t, "up", r.URL.Query().Get("query"))
+		_, err := w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[{"metric":{"pod":"a"},"value":[1540000000,"12.5"]}]}}`))
+		utils.Ok(t, err)
+	}))
+	defer server.Close()
+
+	samples, err := NewPrometheusClient(server.URL, time.Second).Query("up")
+	utils.Ok(t, err)
+	utils.Equals(t, []Sample{{Metric: map[string]string{"pod": "a"}, Value: 12.5}}, samples)
+}
+
+func TestAggregateEdges(t *testing.T) {
+	edges := []Edge{
+		{SourcePod: "ns:a", DestinationPod: "ns:b", Requests: 10, LatencyMs: 10, SuccessRate: 1},
+		{SourcePod: "ns:a", DestinationPod: "ns:b", Requests: 30, LatencyMs: 30, SuccessRate: 0.5},
+		{SourcePod: "ns:a", DestinationService: "ns:svc", Requests: 5},
+	}
+	got := aggregateEdges(edges)
+	expected := map[string]map[string]models.PodInteractionMetrics{
+		"ns:a": {"ns:b": {Count: 40, RequestsPerSecond: 40 / Window.Seconds(), Latency: 25, SuccessRate: 0.625}},
+	}
+	utils.Equals(t, expected, got)
+}
+
+func TestAggregateEdgesWithFlows(t *testing.T) {
+	edges := []Edge{
+		{SourcePod: "ns:a", DestinationPod: "ns:b", Requests: 4, SuccessRate: 0.5, Dropped: 2, Ports: []uint32{8080}},
+		{SourcePod: "ns:a", DestinationPod: "ns:b", Requests: 4, SuccessRate: 1, Ports: []uint32{443, 8080}},
+	}
+	got := aggregateEdges(edges)
+	utils.Equals(t, 2.0, got["ns:a"]["ns:b"].Dropped)
+	utils.Equals(t, 0.75, got["ns:a"]["ns:b"].SuccessRate)
+	utils.Equals(t, "443,8080", got["ns:a"]["ns:b"].Ports)
+}
+
+func TestAggregateBytes(t *testing.T) {
+	edges := []Edge{
+		{SourcePod: "ns:a", DestinationPod: "ns:b", Requests: 4, Bytes: 1000},
+		{SourcePod: "ns:a", DestinationPod: "ns:b", Requests: 4, Bytes: 500},
+		{SourcePod: "ns:a", External: true, Requests: 2, Bytes: 300},
+		{SourcePod: "ns:a", External: true, Requests: 1, Bytes: 200},
+		{SourcePod: "ns:c", External: true, Bytes: 50},
+	}
+	got := aggregateEdges(edges)
+	utils.Equals(t, 1, len(got["ns:a"]))
+	utils.Equals(t, 1500.0, got["ns:a"]["ns:b"].Bytes)
+	utils.Equals(t, map[string]float64{"ns:a": 500, "ns:c": 50}, aggregateExternalBytes(edges))
+}
+
+func TestComputeServiceUnitCosts(t *testing.T) {
+	services := []models.Service{
+		{
+			ID: dgraph.ID{Xid: "ns:frontend"},
+			Pod: []*models.Pod{
+				{ID: dgraph.ID{Xid: "ns:a"}, CPURequest: 2, CPUPrice: 0.5, MemoryRequest: 4, MemoryPrice: 0.25},
+				{ID: dgraph.ID{Xid: "ns:b"}, CPURequest: 1, CPUPrice: 0.5},
+			},
+		},
+		{
+			ID:  dgraph.ID{Xid: "ns:idle"},
+			Pod: []*models.Pod{{ID: dgraph.ID{Xid: "ns:c"}, CPURequest: 1, CPUPrice: 0.5}},
+		},
+	}
+	inbound := getInboundRequests(map[string]map[string]models.PodInteractionMetrics{
+		"ns:x": {"ns:a": {Count: 300}, "ns:b": {Count: 100}},
+		"ns:y": {"ns:a": {Count: 600}},
+	})
+	got := computeServiceUnitCosts(services, inbound)
+	utils.Equals(t, map[string]serviceUnitCost{"ns:frontend": {requests: 1000, cost: 2.5}}, got)
+}
+
+func TestSelectSources(t *testing.T) {
+	utils.Ok(t, SelectSources([]string{CaptureSource}))
+	utils.Assert(t, IsCaptureSelected(), "capture source not selected")
+	utils.Assert(t, SelectSources([]string{"unknown"}) != nil, "expected error for unregistered source")
+}
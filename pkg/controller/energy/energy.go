@@ -0,0 +1,171 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package energy
+
+import (
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/vmware/purser/pkg/controller/dgraph/models"
+	"github.com/vmware/purser/pkg/controller/discovery/telemetry"
+	"github.com/vmware/purser/pkg/controller/utils"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Queries returning average power in Watts of each node over the last hour
+const (
+	// RAPLNodePowerQuery uses RAPL counters exposed by node_exporter(joules per second is Watts)
+	RAPLNodePowerQuery = `sum(rate(node_rapl_package_joules_total[` + telemetry.WindowRange + `]) + rate(node_rapl_dram_joules_total[` + telemetry.WindowRange + `])) by (node)`
+	// IPMINodePowerQuery uses power readings of ipmi_exporter
+	IPMINodePowerQuery = `avg(avg_over_time(ipmi_dcmi_power_consumption_watts[` + telemetry.WindowRange + `])) by (node)`
+
+	// DefaultNodeLabel is the label of node power samples holding name of the node
+	DefaultNodeLabel = "node"
+)
+
+// podUsageQuery returns cpu cores used by each pod over the last hour
+const podUsageQuery = `sum(rate(container_cpu_usage_seconds_total{container!="", container!="POD", pod!=""}[` + telemetry.WindowRange + `])) by (namespace, pod)`
+
+var collector *Collector
+
+// Collector attributes energy consumed by nodes to their pods proportionally to cpu usage
+type Collector struct {
+	prometheus     *telemetry.PrometheusClient
+	nodePowerQuery string
+	nodeLabel      string
+}
+
+// Configure enables energy collection from the given Prometheus. It does nothing if url is empty.
+func Configure(prometheusURL, nodePowerQuery, nodeLabel string, timeout time.Duration) {
+	if prometheusURL == "" {
+		return
+	}
+	if nodePowerQuery == "" {
+		nodePowerQuery = RAPLNodePowerQuery
+	}
+	if nodeLabel == "" {
+		nodeLabel = DefaultNodeLabel
+	}
+	collector = &Collector{
+		prometheus:     telemetry.NewPrometheusClient(prometheusURL, timeout),
+		nodePowerQuery: nodePowerQuery,
+		nodeLabel:      nodeLabel,
+	}
+	log.Infof("energy collection configured with prometheus: %s, node power query: %s", prometheusURL, nodePowerQuery)
+}
+
+// IsConfigured returns true if energy collection is enabled
+func IsConfigured() bool {
+	return collector != nil
+}
+
+// CollectAndStoreEnergy stores energy consumed in the last hour by nodes and their pods in Dgraph
+func CollectAndStoreEnergy(client *kubernetes.Clientset) {
+	if collector == nil {
+		return
+	}
+	nodePower, err := collector.queryNodePower()
+	if err != nil {
+		log.Errorf("failed to retrieve node power: %v", err)
+		return
+	}
+	podUsage, err := collector.queryPodUsage()
+	if err != nil {
+		log.Errorf("failed to retrieve pod usage, energy of nodes is not attributed to pods: %v", err)
+	}
+	nodeEnergy, podEnergy := attribute(nodePower, retrievePodNodes(client), podUsage)
+
+	for node, kWh := range nodeEnergy {
+		if err := models.AddNodeEnergy(node, kWh); err != nil {
+			log.Debugf("unable to store energy of node: %s, err: %v", node, err)
+		}
+	}
+	for pod, kWh := range podEnergy {
+		if err := models.AddPodEnergy(pod, kWh); err != nil {
+			log.Debugf("unable to store energy of pod: %s, err: %v", pod, err)
+		}
+	}
+	log.Infof("stored energy of (%d) nodes and (%d) pods", len(nodeEnergy), len(podEnergy))
+}
+
+func (c *Collector) queryNodePower() (map[string]float64, error) {
+	samples, err := c.prometheus.Query(c.nodePowerQuery)
+	if err != nil {
+		return nil, err
+	}
+	power := make(map[string]float64)
+	for _, sample := range samples {
+		if node := sample.Metric[c.nodeLabel]; node != "" {
+			power[node] += sample.Value
+		}
+	}
+	return power, nil
+}
+
+func (c *Collector) queryPodUsage() (map[string]float64, error) {
+	samples, err := c.prometheus.Query(podUsageQuery)
+	if err != nil {
+		return nil, err
+	}
+	usage := make(map[string]float64)
+	for _, sample := range samples {
+		usage[sample.Metric["namespace"]+telemetry.KeySpliter+sample.Metric["pod"]] = sample.Value
+	}
+	return usage, nil
+}
+
+// retrievePodNodes returns node name of each running pod
+func retrievePodNodes(client *kubernetes.Clientset) map[string]string {
+	podNodes := make(map[string]string)
+	pods := utils.RetrievePodList(client, metav1.ListOptions{})
+	if pods == nil {
+		return podNodes
+	}
+	for _, pod := range pods.Items {
+		if pod.Spec.NodeName != "" {
+			podNodes[pod.Namespace+telemetry.KeySpliter+pod.Name] = pod.Spec.NodeName
+		}
+	}
+	return podNodes
+}
+
+// attribute converts average power(Watts) of nodes into energy(kWh) of the window and splits it among pods
+// of the node proportionally to their cpu usage. Energy of nodes without pod usage is not attributed.
+func attribute(nodePower map[string]float64, podNodes map[string]string, podUsage map[string]float64) (map[string]float64, map[string]float64) {
+	nodeEnergy := make(map[string]float64)
+	for node, watts := range nodePower {
+		nodeEnergy[node] = watts * telemetry.Window.Hours() / 1000
+	}
+
+	nodeUsage := make(map[string]float64)
+	for pod, node := range podNodes {
+		nodeUsage[node] += podUsage[pod]
+	}
+
+	podEnergy := make(map[string]float64)
+	for pod, node := range podNodes {
+		kWh, isPresent := nodeEnergy[node]
+		if !isPresent || nodeUsage[node] == 0 || podUsage[pod] == 0 {
+			continue
+		}
+		podEnergy[pod] = kWh * podUsage[pod] / nodeUsage[node]
+	}
+	return nodeEnergy, podEnergy
+}
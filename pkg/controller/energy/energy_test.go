@@ -0,0 +1,39 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package energy
+
+import (
+	"testing"
+
+	"github.com/vmware/purser/test/utils"
+)
+
+func TestAttribute(t *testing.T) {
+	nodePower := map[string]float64{"node-1": 1000, "node-2": 500}
+	podNodes := map[string]string{
+		"default:a": "node-1",
+		"default:b": "node-1",
+		"default:c": "node-2",
+		"default:d": "node-3",
+	}
+	podUsage := map[string]float64{"default:a": 3, "default:b": 1, "default:d": 1}
+
+	nodeEnergy, podEnergy := attribute(nodePower, podNodes, podUsage)
+	utils.Equals(t, map[string]float64{"node-1": 1, "node-2": 0.5}, nodeEnergy)
+	utils.Equals(t, map[string]float64{"default:a": 0.75, "default:b": 0.25}, podEnergy)
+}
@@ -18,17 +18,22 @@
 package eventprocessor
 
 import (
+	"context"
 	"encoding/json"
 	"time"
 
+	"github.com/vmware/purser/pkg/controller/dgraph"
 	"github.com/vmware/purser/pkg/controller/dgraph/models/query"
 
 	log "github.com/Sirupsen/logrus"
 
 	groups_v1 "github.com/vmware/purser/pkg/apis/groups/v1"
+	openshift_v1 "github.com/vmware/purser/pkg/apis/openshift/v1"
 	subcriber_v1 "github.com/vmware/purser/pkg/apis/subscriber/v1"
 	"github.com/vmware/purser/pkg/controller"
+	"github.com/vmware/purser/pkg/controller/cloudevents"
 	"github.com/vmware/purser/pkg/controller/dgraph/models"
+	"github.com/vmware/purser/pkg/controller/status"
 
 	apps_v1beta1 "k8s.io/api/apps/v1beta1"
 	batch_v1 "k8s.io/api/batch/v1"
@@ -52,13 +57,17 @@ func ProcessEvents(conf *controller.Config) {
 
 			ProcessPayloads(data, conf)
 
-			subscribers, err := query.RetrieveSubscribers()
+			subscribers, err := query.RetrieveSubscribers(dgraph.WithOwnCluster(context.Background()))
 			if err == nil {
 				notifySubscribers(data, subscribers)
 			} else {
 				log.Errorf("unable to retrieve subscribers from dgraph: %v", err)
 			}
 
+			if cloudevents.IsConfigured() {
+				cloudevents.EmitResourceEvents(data)
+			}
+
 			conf.RingBuffer.RemoveN(size)
 			conf.RingBuffer.PrintDetails()
 		}
@@ -89,7 +98,17 @@ func handlePayloadBasedOnResource(payload *controller.Payload, conf *controller.
 	case "Node":
 		node := api_v1.Node{}
 		unmarshalPayload(payload, &node)
-		_, err = models.StoreNode(node)
+		event := models.NodeAdded
+		if payload.EventType == controller.Delete {
+			event = models.NodeRemoved
+			// nodes deleted without graceful deletion have no deletion timestamp
+			if node.GetDeletionTimestamp().IsZero() {
+				node.SetDeletionTimestamp(&payload.CaptureTime)
+			}
+		}
+		if _, err = models.StoreNode(node); err == nil {
+			err = models.StoreNodeEvent(node, event, payload.CaptureTime.Time)
+		}
 	case "Namespace":
 		ns := api_v1.Namespace{}
 		unmarshalPayload(payload, &ns)
@@ -122,6 +141,18 @@ func handlePayloadBasedOnResource(payload *controller.Payload, conf *controller.
 		job := batch_v1.Job{}
 		unmarshalPayload(payload, &job)
 		_, err = models.StoreJob(job)
+	case "DeploymentConfig":
+		dc := openshift_v1.DeploymentConfig{}
+		unmarshalPayload(payload, &dc)
+		_, err = models.StoreDeploymentConfig(dc)
+	case "Route":
+		route := openshift_v1.Route{}
+		unmarshalPayload(payload, &route)
+		_, err = models.StoreRoute(route)
+	case "ImageStream":
+		imageStream := openshift_v1.ImageStream{}
+		unmarshalPayload(payload, &imageStream)
+		_, err = models.StoreImageStream(imageStream)
 	case "Group":
 		groupCRD := &groups_v1.Group{}
 		unmarshalPayload(payload, &groupCRD)
@@ -132,6 +163,7 @@ func handlePayloadBasedOnResource(payload *controller.Payload, conf *controller.
 		_, err = models.StoreSubscriberCRD(subscriberCRD)
 	}
 	checkDgraphError(payload.ResourceType, err)
+	status.RecordProcessed(payload.ResourceType, time.Now(), err)
 }
 
 func unmarshalPayload(payload *controller.Payload, resource interface{}) {
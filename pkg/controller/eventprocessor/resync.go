@@ -0,0 +1,340 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package eventprocessor
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/vmware/purser/pkg/controller/dgraph"
+	"github.com/vmware/purser/pkg/controller/dgraph/models"
+	"github.com/vmware/purser/pkg/controller/dgraph/models/query"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// ResyncResult is the outcome of reconciling resources of a kind. Created are resources missing in Dgraph,
+// Updated are resources already stored whose fields are rewritten and Closed are resources missing in the cluster.
+// Duplicates is the number of extra live resources stored with the same xid.
+type ResyncResult struct {
+	Kind       string   `json:"kind"`
+	InCluster  int      `json:"inCluster"`
+	Created    int      `json:"created"`
+	Updated    int      `json:"updated"`
+	Closed     int      `json:"closed"`
+	Duplicates int      `json:"duplicates"`
+	Errors     []string `json:"errors,omitempty"`
+}
+
+// ResyncReport structure
+type ResyncReport struct {
+	StartTime string         `json:"startTime"`
+	EndTime   string         `json:"endTime"`
+	Kinds     []ResyncResult `json:"kinds"`
+}
+
+// clusterObject is a resource listed from the cluster with the function storing it in Dgraph
+type clusterObject struct {
+	xid   string
+	store func() error
+}
+
+type lister func(client *kubernetes.Clientset) ([]clusterObject, error)
+
+// resyncKinds are reconciled in this order so that namespaces, nodes and volumes exist before the resources
+// referring to them
+var resyncKinds = []struct {
+	kind string
+	list lister
+}{
+	{"Namespace", listNamespaces},
+	{"Node", listNodes},
+	{"PersistentVolume", listPersistentVolumes},
+	{"PersistentVolumeClaim", listPersistentVolumeClaims},
+	{"Deployment", listDeployments},
+	{"ReplicaSet", listReplicasets},
+	{"StatefulSet", listStatefulsets},
+	{"DaemonSet", listDaemonsets},
+	{"Job", listJobs},
+	{"Pod", listPods},
+	{"Service", listServices},
+}
+
+var resyncMu sync.Mutex
+
+// ResyncCluster re-lists all resources of the cluster and reconciles Dgraph with them: missing resources are
+// created, stored resources are rewritten to correct drifted fields and live resources missing in the cluster are
+// closed with the current time. Resources are stored by xid so that running it again creates no duplicates.
+func ResyncCluster(kubeClient *kubernetes.Clientset) ResyncReport {
+	resyncMu.Lock()
+	defer resyncMu.Unlock()
+
+	now := time.Now()
+	report := ResyncReport{StartTime: now.Format(time.RFC3339), Kinds: []ResyncResult{}}
+	logrus.Infof("[RESYNC] started full resync")
+	for _, resyncKind := range resyncKinds {
+		result := resyncKindOfResources(kubeClient, resyncKind.kind, resyncKind.list, now)
+		logrus.Infof("[RESYNC] %s: in cluster: %d, created: %d, updated: %d, closed: %d, duplicates: %d, errors: %d",
+			result.Kind, result.InCluster, result.Created, result.Updated, result.Closed, result.Duplicates, len(result.Errors))
+		report.Kinds = append(report.Kinds, result)
+	}
+	report.EndTime = time.Now().Format(time.RFC3339)
+	logrus.Infof("[RESYNC] finished full resync")
+	return report
+}
+
+func resyncKindOfResources(kubeClient *kubernetes.Clientset, kind string, list lister, endTime time.Time) ResyncResult {
+	result := ResyncResult{Kind: kind}
+	objects, err := list(kubeClient)
+	if err != nil {
+		// without the list every live resource would look deleted, so nothing is closed
+		result.Errors = append(result.Errors, "unable to list: "+err.Error())
+		return result
+	}
+	live, err := query.RetrieveLiveResources(dgraph.WithOwnCluster(context.Background()), kind)
+	if err != nil {
+		result.Errors = append(result.Errors, "unable to retrieve live resources: "+err.Error())
+		return result
+	}
+	result.InCluster = len(objects)
+
+	missing, stored, deleted, duplicates := planResync(objects, live)
+	result.Duplicates = duplicates
+	for _, object := range append(missing, stored...) {
+		if err := object.store(); err != nil {
+			result.Errors = append(result.Errors, object.xid+": "+err.Error())
+		}
+	}
+	result.Created, result.Updated = len(missing), len(stored)
+	for _, resource := range deleted {
+		if err := models.CloseResource(kind, resource.UID, resource.Xid, resource.Name, endTime); err != nil {
+			result.Errors = append(result.Errors, resource.Xid+": "+err.Error())
+			continue
+		}
+		result.Closed++
+	}
+	return result
+}
+
+// planResync splits objects of the cluster into missing(not live in Dgraph) and stored ones, and returns live
+// resources which are not in the cluster with the number of extra live resources sharing an xid
+func planResync(objects []clusterObject, live []query.LiveResource) ([]clusterObject, []clusterObject, []query.LiveResource, int) {
+	inCluster := make(map[string]bool)
+	for _, object := range objects {
+		inCluster[object.xid] = true
+	}
+	isLive := make(map[string]bool)
+	var deleted []query.LiveResource
+	duplicates := 0
+	for _, resource := range live {
+		if isLive[resource.Xid] {
+			duplicates++
+		}
+		isLive[resource.Xid] = true
+		if !inCluster[resource.Xid] {
+			deleted = append(deleted, resource)
+		}
+	}
+
+	var missing, stored []clusterObject
+	for _, object := range objects {
+		if isLive[object.xid] {
+			stored = append(stored, object)
+		} else {
+			missing = append(missing, object)
+		}
+	}
+	return missing, stored, deleted, duplicates
+}
+
+func listNamespaces(client *kubernetes.Clientset) ([]clusterObject, error) {
+	list, err := client.CoreV1().Namespaces().List(v1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	var objects []clusterObject
+	for i := range list.Items {
+		namespace := list.Items[i]
+		objects = append(objects, clusterObject{namespace.Name, func() error {
+			_, err := models.StoreNamespace(namespace)
+			return err
+		}})
+	}
+	return objects, nil
+}
+
+func listNodes(client *kubernetes.Clientset) ([]clusterObject, error) {
+	list, err := client.CoreV1().Nodes().List(v1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	var objects []clusterObject
+	for i := range list.Items {
+		node := list.Items[i]
+		objects = append(objects, clusterObject{node.Name, func() error {
+			_, err := models.StoreNode(node)
+			return err
+		}})
+	}
+	return objects, nil
+}
+
+func listPersistentVolumes(client *kubernetes.Clientset) ([]clusterObject, error) {
+	list, err := client.CoreV1().PersistentVolumes().List(v1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	var objects []clusterObject
+	for i := range list.Items {
+		pv := list.Items[i]
+		objects = append(objects, clusterObject{pv.Name, func() error {
+			_, err := models.StorePersistentVolume(pv, client)
+			return err
+		}})
+	}
+	return objects, nil
+}
+
+func listPersistentVolumeClaims(client *kubernetes.Clientset) ([]clusterObject, error) {
+	list, err := client.CoreV1().PersistentVolumeClaims(v1.NamespaceAll).List(v1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	var objects []clusterObject
+	for i := range list.Items {
+		pvc := list.Items[i]
+		objects = append(objects, clusterObject{pvc.Namespace + ":" + pvc.Name, func() error {
+			_, err := models.StorePersistentVolumeClaim(pvc)
+			return err
+		}})
+	}
+	return objects, nil
+}
+
+func listDeployments(client *kubernetes.Clientset) ([]clusterObject, error) {
+	list, err := client.AppsV1beta1().Deployments(v1.NamespaceAll).List(v1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	var objects []clusterObject
+	for i := range list.Items {
+		deployment := list.Items[i]
+		objects = append(objects, clusterObject{deployment.Namespace + ":" + deployment.Name, func() error {
+			_, err := models.StoreDeployment(deployment)
+			return err
+		}})
+	}
+	return objects, nil
+}
+
+func listReplicasets(client *kubernetes.Clientset) ([]clusterObject, error) {
+	list, err := client.ExtensionsV1beta1().ReplicaSets(v1.NamespaceAll).List(v1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	var objects []clusterObject
+	for i := range list.Items {
+		replicaset := list.Items[i]
+		objects = append(objects, clusterObject{replicaset.Namespace + ":" + replicaset.Name, func() error {
+			_, err := models.StoreReplicaset(replicaset)
+			return err
+		}})
+	}
+	return objects, nil
+}
+
+func listStatefulsets(client *kubernetes.Clientset) ([]clusterObject, error) {
+	list, err := client.AppsV1beta1().StatefulSets(v1.NamespaceAll).List(v1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	var objects []clusterObject
+	for i := range list.Items {
+		statefulset := list.Items[i]
+		objects = append(objects, clusterObject{statefulset.Namespace + ":" + statefulset.Name, func() error {
+			_, err := models.StoreStatefulset(statefulset)
+			return err
+		}})
+	}
+	return objects, nil
+}
+
+func listDaemonsets(client *kubernetes.Clientset) ([]clusterObject, error) {
+	list, err := client.ExtensionsV1beta1().DaemonSets(v1.NamespaceAll).List(v1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	var objects []clusterObject
+	for i := range list.Items {
+		daemonset := list.Items[i]
+		objects = append(objects, clusterObject{daemonset.Namespace + ":" + daemonset.Name, func() error {
+			_, err := models.StoreDaemonset(daemonset)
+			return err
+		}})
+	}
+	return objects, nil
+}
+
+func listJobs(client *kubernetes.Clientset) ([]clusterObject, error) {
+	list, err := client.BatchV1().Jobs(v1.NamespaceAll).List(v1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	var objects []clusterObject
+	for i := range list.Items {
+		job := list.Items[i]
+		objects = append(objects, clusterObject{job.Namespace + ":" + job.Name, func() error {
+			_, err := models.StoreJob(job)
+			return err
+		}})
+	}
+	return objects, nil
+}
+
+func listPods(client *kubernetes.Clientset) ([]clusterObject, error) {
+	list, err := client.CoreV1().Pods(v1.NamespaceAll).List(v1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	var objects []clusterObject
+	for i := range list.Items {
+		pod := list.Items[i]
+		objects = append(objects, clusterObject{pod.Namespace + ":" + pod.Name, func() error {
+			return models.StorePod(pod)
+		}})
+	}
+	return objects, nil
+}
+
+func listServices(client *kubernetes.Clientset) ([]clusterObject, error) {
+	list, err := client.CoreV1().Services(v1.NamespaceAll).List(v1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	var objects []clusterObject
+	for i := range list.Items {
+		service := list.Items[i]
+		objects = append(objects, clusterObject{service.Namespace + ":" + service.Name, func() error {
+			return models.StoreService(service)
+		}})
+	}
+	return objects, nil
+}
@@ -0,0 +1,58 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package eventprocessor
+
+import (
+	"testing"
+
+	"github.com/vmware/purser/pkg/controller/dgraph/models/query"
+	"github.com/vmware/purser/test/utils"
+)
+
+func xidsOf(objects []clusterObject) []string {
+	var xids []string
+	for _, object := range objects {
+		xids = append(xids, object.xid)
+	}
+	return xids
+}
+
+func TestPlanResync(t *testing.T) {
+	objects := []clusterObject{{xid: "default:pod1"}, {xid: "default:pod2"}, {xid: "default:pod3"}}
+	live := []query.LiveResource{
+		{UID: "0x1", Xid: "default:pod1", Name: "pod1"},
+		{UID: "0x2", Xid: "default:pod1", Name: "pod1"},
+		{UID: "0x3", Xid: "default:pod4", Name: "pod4"},
+		{UID: "0x4", Xid: "default:pod3", Name: "pod3"},
+	}
+	missing, stored, deleted, duplicates := planResync(objects, live)
+	utils.Equals(t, []string{"default:pod2"}, xidsOf(missing))
+	utils.Equals(t, []string{"default:pod1", "default:pod3"}, xidsOf(stored))
+	utils.Equals(t, []query.LiveResource{{UID: "0x3", Xid: "default:pod4", Name: "pod4"}}, deleted)
+	utils.Equals(t, 1, duplicates)
+}
+
+func TestPlanResyncRerun(t *testing.T) {
+	objects := []clusterObject{{xid: "node1"}}
+	live := []query.LiveResource{{UID: "0x1", Xid: "node1", Name: "node1"}}
+	missing, stored, deleted, duplicates := planResync(objects, live)
+	utils.Assert(t, missing == nil, "expected no missing resources, got %v", xidsOf(missing))
+	utils.Equals(t, []string{"node1"}, xidsOf(stored))
+	utils.Assert(t, deleted == nil, "expected no deleted resources, got %v", deleted)
+	utils.Equals(t, 0, duplicates)
+}
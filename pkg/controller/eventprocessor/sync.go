@@ -18,6 +18,7 @@
 package eventprocessor
 
 import (
+	"context"
 	"time"
 
 	"github.com/vmware/purser/pkg/controller/dgraph"
@@ -41,7 +42,7 @@ func SyncCluster(kubeClient *kubernetes.Clientset) {
 // syncPods handles missed creation and deletion of pod events
 func syncPods(kubeClient *kubernetes.Clientset, endTime string) {
 	logrus.Infof("[SYNC] started syncing pods")
-	livePodsFromDgraph := query.RetrieveAllLivePods()
+	livePodsFromDgraph := query.RetrieveAllLivePods(dgraph.WithOwnCluster(context.Background()))
 	logrus.Infof("[SYNC] number of livePodsFromDgraph: %d", len(livePodsFromDgraph))
 
 	podsInCluster := utils.RetrievePodList(kubeClient, v1.ListOptions{})
@@ -60,10 +61,11 @@ func syncPods(kubeClient *kubernetes.Clientset, endTime string) {
 func handleDeadPodsAndNewPods(livePodsFromDgraph []models.Pod, podsInCluster *corev1.PodList, endTime string) {
 	// create a map from pod xid to k8s pod pointer
 	podXIDToPod := make(map[string]*corev1.Pod)
-	for _, pod := range podsInCluster.Items {
+	for i := range podsInCluster.Items {
+		pod := &podsInCluster.Items[i]
 		xid := pod.Namespace + ":" + pod.Name
 		if _, isPresent := podXIDToPod[xid]; !isPresent {
-			podXIDToPod[xid] = &pod
+			podXIDToPod[xid] = pod
 		}
 	}
 
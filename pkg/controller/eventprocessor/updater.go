@@ -18,8 +18,10 @@
 package eventprocessor
 
 import (
+	"context"
 	"time"
 
+	"github.com/vmware/purser/pkg/controller/dgraph"
 	"github.com/vmware/purser/pkg/controller/dgraph/models"
 
 	"github.com/vmware/purser/pkg/controller/dgraph/models/query"
@@ -70,24 +72,28 @@ func UpdateGroup(group *groups_v1.Group, groupCRDClient *groupsClient_v1.GroupCl
 		MemoryCost:  groupMetrics.CostMemory,
 		StorageCost: groupMetrics.CostStorage,
 		TotalCost:   groupMetrics.CostCPU + groupMetrics.CostMemory + groupMetrics.CostStorage,
+		Carbon:      groupMetrics.Carbon,
 	}
 	group.Spec.PerHourCost = &groups_v1.Cost{
 		CPUCost:     groupMetrics.CostCPUPerHour,
 		MemoryCost:  groupMetrics.CostMemoryPerHour,
 		StorageCost: groupMetrics.CostStoragePerHour,
 		TotalCost:   groupMetrics.CostCPUPerHour + groupMetrics.CostMemoryPerHour + groupMetrics.CostStoragePerHour,
+		Carbon:      groupMetrics.CarbonPerHour,
 	}
 	group.Spec.LastMonthCost = &groups_v1.Cost{
 		CPUCost:     groupMetrics.LastMonthCPUCost,
 		MemoryCost:  groupMetrics.LastMonthMemoryCost,
 		StorageCost: groupMetrics.LastMonthStorageCost,
 		TotalCost:   groupMetrics.LastMonthCPUCost + groupMetrics.LastMonthMemoryCost + groupMetrics.LastMonthStorageCost,
+		Carbon:      groupMetrics.LastMonthCarbon,
 	}
 	group.Spec.LastLastMonthCost = &groups_v1.Cost{
 		CPUCost:     groupMetrics.LastLastMonthCPUCost,
 		MemoryCost:  groupMetrics.LastLastMonthMemoryCost,
 		StorageCost: groupMetrics.LastLastMonthStorageCost,
 		TotalCost:   groupMetrics.LastLastMonthCPUCost + groupMetrics.LastLastMonthMemoryCost + groupMetrics.LastLastMonthStorageCost,
+		Carbon:      groupMetrics.LastLastMonthCarbon,
 	}
 	group.Spec.LastUpdated = time.Now()
 
@@ -122,7 +128,7 @@ func getGroupMetrics(group *groups_v1.Group) query.GroupMetrics {
 	log.Debugf("Group: (%v), uidQuery: (%v)", group.Name, uidQueryForPods)
 
 	// get group metrics
-	groupMetrics, err := query.RetrieveGroupMetricsFromPodUIDs(uidQueryForPods)
+	groupMetrics, err := query.RetrieveGroupMetricsFromPodUIDs(dgraph.WithOwnCluster(context.Background()), uidQueryForPods)
 	if err != nil {
 		log.Errorf("Unable to retrieve group metrics, group: %v, UIDs: (%v)", group.Name, uidQueryForPods)
 		return query.GroupMetrics{}
@@ -137,7 +143,7 @@ func getPodUIDsFromAllExpressions(expressions map[string]map[string][]string) []
 	var podsUIDsFromExpressions [][]string
 	for _, selector := range expressions {
 		labelFilter := query.CreateFilterFromListOfLabels(selector)
-		podsUIDsFromSelector, err := query.RetrievePodsUIDsByLabelsFilter(labelFilter)
+		podsUIDsFromSelector, err := query.RetrievePodsUIDsByLabelsFilter(dgraph.WithOwnCluster(context.Background()), labelFilter)
 		if err == nil {
 			podsUIDsFromExpressions = append(podsUIDsFromExpressions, podsUIDsFromSelector)
 		}
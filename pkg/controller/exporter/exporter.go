@@ -0,0 +1,202 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package exporter
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/vmware/purser/pkg/controller/dgraph"
+	"github.com/vmware/purser/pkg/controller/dgraph/models/query"
+)
+
+// ContentType is the content type of the Prometheus text exposition format
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+var (
+	refreshInterval time.Duration
+
+	metricsMu sync.RWMutex
+	metrics   []byte
+
+	labelValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+)
+
+// sample is a value of a metric with its rendered labels(ex: {namespace="default"})
+type sample struct {
+	labels string
+	value  float64
+}
+
+// family is a metric with help, type and its samples
+type family struct {
+	name       string
+	help       string
+	metricType string
+	samples    []sample
+}
+
+// Configure enables the exporter with costs refreshed every interval, it stays disabled if interval is not positive
+func Configure(interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+	refreshInterval = interval
+	log.Infof("prometheus exporter configured with refresh interval: %s", interval)
+}
+
+// IsConfigured returns true if the exporter is enabled
+func IsConfigured() bool {
+	return refreshInterval > 0
+}
+
+// GetRefreshInterval returns the interval of refreshing exported costs
+func GetRefreshInterval() time.Duration {
+	return refreshInterval
+}
+
+// Refresh computes month to date costs of live pods, namespaces and nodes and renders them as Prometheus metrics
+func Refresh() {
+	ctx := dgraph.WithOwnCluster(context.Background())
+	pods := query.RetrieveLivePodCosts(ctx)
+	namespaces := query.RetrieveClusterMetricsWithDeleted(ctx, query.Logical, query.All, query.All, query.Exclude).Data.Children
+	nodes := []query.Children{}
+	for _, resource := range query.RetrieveClusterMetrics(ctx, query.Physical).Data.Children {
+		if resource.Type == query.NodeType {
+			nodes = append(nodes, resource)
+		}
+	}
+
+	buffer := &bytes.Buffer{}
+	families := append(getPodFamilies(pods), getNamespaceFamilies(namespaces)...)
+	families = append(families, getNodeFamilies(nodes)...)
+	families = append(families, family{
+		name:       "purser_exporter_last_refresh_timestamp_seconds",
+		help:       "Unix time of the last refresh of exported costs.",
+		metricType: "gauge",
+		samples:    []sample{{value: float64(time.Now().Unix())}},
+	})
+	writeFamilies(buffer, families)
+
+	metricsMu.Lock()
+	metrics = buffer.Bytes()
+	metricsMu.Unlock()
+	log.Debugf("exported costs of (%d) pods, (%d) namespaces and (%d) nodes", len(pods), len(namespaces), len(nodes))
+}
+
+// WriteMetrics writes the metrics of the last refresh, it returns false if costs have not been refreshed yet
+func WriteMetrics(w io.Writer) (bool, error) {
+	metricsMu.RLock()
+	defer metricsMu.RUnlock()
+	if metrics == nil {
+		return false, nil
+	}
+	_, err := w.Write(metrics)
+	return true, err
+}
+
+func getPodFamilies(pods []query.PodCost) []family {
+	cpu := newCostFamily("pod", "cpu", "requested cpu")
+	memory := newCostFamily("pod", "memory", "requested memory")
+	storage := newCostFamily("pod", "storage", "requested storage")
+	gpu := newCostFamily("pod", "gpu", "requested GPUs")
+	for _, pod := range pods {
+		labels := formatLabels("namespace", pod.Namespace, "pod", pod.Name, "node", pod.Node)
+		cpu.samples = append(cpu.samples, sample{labels, pod.CPUCost})
+		memory.samples = append(memory.samples, sample{labels, pod.MemoryCost})
+		storage.samples = append(storage.samples, sample{labels, pod.StorageCost})
+		gpu.samples = append(gpu.samples, sample{labels, pod.GPUCost})
+	}
+	return []family{cpu, memory, storage, gpu}
+}
+
+func getNamespaceFamilies(namespaces []query.Children) []family {
+	cpu := newCostFamily("namespace", "cpu", "cpu requested by pods")
+	memory := newCostFamily("namespace", "memory", "memory requested by pods")
+	storage := newCostFamily("namespace", "storage", "storage requested by pods")
+	total := newCostFamily("namespace", "", "resources requested by pods")
+	for _, namespace := range namespaces {
+		labels := formatLabels("namespace", strings.TrimPrefix(namespace.Name, query.NamespaceType+"-"))
+		cpu.samples = append(cpu.samples, sample{labels, namespace.CPUCost})
+		memory.samples = append(memory.samples, sample{labels, namespace.MemoryCost})
+		storage.samples = append(storage.samples, sample{labels, namespace.StorageCost})
+		total.samples = append(total.samples, sample{labels, namespace.CPUCost + namespace.MemoryCost + namespace.StorageCost +
+			namespace.GPUCost + namespace.ExtendedResourceCost + namespace.BandwidthCost})
+	}
+	return []family{cpu, memory, storage, total}
+}
+
+func getNodeFamilies(nodes []query.Children) []family {
+	cpu := newCostFamily("node", "cpu", "cpu capacity")
+	memory := newCostFamily("node", "memory", "memory capacity")
+	total := newCostFamily("node", "", "cpu and memory capacity")
+	for _, node := range nodes {
+		labels := formatLabels("node", strings.TrimPrefix(node.Name, query.NodeType+"-"))
+		cpu.samples = append(cpu.samples, sample{labels, node.CPUCost})
+		memory.samples = append(memory.samples, sample{labels, node.MemoryCost})
+		total.samples = append(total.samples, sample{labels, node.CPUCost + node.MemoryCost})
+	}
+	return []family{cpu, memory, total}
+}
+
+// newCostFamily returns the family of month to date cost of the resource(ex: purser_pod_cpu_cost_total), total cost
+// if resource is empty. Costs reset at the start of every month like counters on restart.
+func newCostFamily(resourceType, resource, costOf string) family {
+	name := "purser_" + resourceType + "_cost_total"
+	if resource != "" {
+		name = "purser_" + resourceType + "_" + resource + "_cost_total"
+	}
+	return family{
+		name:       name,
+		help:       fmt.Sprintf("Month to date cost of %s of the %s.", costOf, resourceType),
+		metricType: "counter",
+	}
+}
+
+func writeFamilies(buffer *bytes.Buffer, families []family) {
+	for _, f := range families {
+		sort.Slice(f.samples, func(i, j int) bool { return f.samples[i].labels < f.samples[j].labels })
+		buffer.WriteString(fmt.Sprintf("# HELP %s %s\n# TYPE %s %s\n", f.name, f.help, f.name, f.metricType))
+		for _, s := range f.samples {
+			buffer.WriteString(f.name + s.labels + " " + strconv.FormatFloat(s.value, 'g', -1, 64) + "\n")
+		}
+	}
+}
+
+// formatLabels renders label names and values given as pairs, labels with empty values are omitted
+func formatLabels(pairs ...string) string {
+	labels := []string{}
+	for i := 0; i+1 < len(pairs); i += 2 {
+		if pairs[i+1] == "" {
+			continue
+		}
+		labels = append(labels, pairs[i]+`="`+labelValueEscaper.Replace(pairs[i+1])+`"`)
+	}
+	if len(labels) == 0 {
+		return ""
+	}
+	return "{" + strings.Join(labels, ",") + "}"
+}
@@ -0,0 +1,62 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package exporter
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/vmware/purser/pkg/controller/dgraph/models/query"
+	"github.com/vmware/purser/test/utils"
+)
+
+func TestWriteFamilies(t *testing.T) {
+	pods := []query.PodCost{
+		{Name: "web-2", Namespace: "shop", Node: "n1", CPUCost: 1.5, MemoryCost: 0.25},
+		{Name: "web-1", Namespace: "shop", CPUCost: 2},
+	}
+	buffer := &bytes.Buffer{}
+	writeFamilies(buffer, getPodFamilies(pods)[:2])
+
+	utils.Equals(t, `# HELP purser_pod_cpu_cost_total Month to date cost of requested cpu of the pod.
+# TYPE purser_pod_cpu_cost_total counter
+purser_pod_cpu_cost_total{namespace="shop",pod="web-1"} 2
+purser_pod_cpu_cost_total{namespace="shop",pod="web-2",node="n1"} 1.5
+# HELP purser_pod_memory_cost_total Month to date cost of requested memory of the pod.
+# TYPE purser_pod_memory_cost_total counter
+purser_pod_memory_cost_total{namespace="shop",pod="web-1"} 0
+purser_pod_memory_cost_total{namespace="shop",pod="web-2",node="n1"} 0.25
+`, buffer.String())
+}
+
+func TestGetNamespaceFamilies(t *testing.T) {
+	families := getNamespaceFamilies([]query.Children{{Name: "namespace-default", CPUCost: 1, MemoryCost: 2, GPUCost: 3}})
+	utils.Equals(t, "purser_namespace_cost_total", families[3].name)
+	utils.Equals(t, []sample{{`{namespace="default"}`, 6}}, families[3].samples)
+}
+
+func TestFormatLabels(t *testing.T) {
+	utils.Equals(t, `{pod="a\"b\\c\n"}`, formatLabels("pod", "a\"b\\c\n", "node", ""))
+	utils.Equals(t, "", formatLabels("node", ""))
+}
+
+func TestWriteMetricsBeforeRefresh(t *testing.T) {
+	isRefreshed, err := WriteMetrics(&bytes.Buffer{})
+	utils.Ok(t, err)
+	utils.Assert(t, !isRefreshed, "metrics are written before refresh")
+}
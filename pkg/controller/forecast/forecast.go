@@ -0,0 +1,145 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package forecast
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// MinSeasonalDays is the minimum number of days of history needed to learn weekly seasonality,
+// with less history the forecast falls back to the linear trend.
+const MinSeasonalDays = 14
+
+const daysInWeek = 7
+
+// DailyCost is the cost of a day
+type DailyCost struct {
+	Day  time.Time
+	Cost float64
+}
+
+// Model forecasts daily cost as a linear trend multiplied by the seasonal index of the weekday
+type Model struct {
+	origin    time.Time
+	intercept float64
+	slope     float64
+	indices   [daysInWeek]float64
+}
+
+// Fit learns the trend and weekly seasonality from daily costs. It returns false if there is no history.
+func Fit(history []DailyCost) (*Model, bool) {
+	if len(history) == 0 {
+		return nil, false
+	}
+	sorted := make([]DailyCost, len(history))
+	copy(sorted, history)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Day.Before(sorted[j].Day) })
+
+	m := &Model{origin: truncateToDay(sorted[0].Day)}
+	m.indices = getSeasonalIndices(sorted)
+
+	// linear trend of deseasonalized costs by least squares
+	// days of weekdays which never have cost are left out
+	var n, sumX, sumY, sumXY, sumXX float64
+	for _, daily := range sorted {
+		index := m.indices[daily.Day.Weekday()]
+		if index == 0 {
+			continue
+		}
+		x := m.daysSinceOrigin(daily.Day)
+		y := daily.Cost / index
+		n++
+		sumX += x
+		sumY += y
+		sumXY += x * y
+		sumXX += x * x
+	}
+	if denominator := n*sumXX - sumX*sumX; denominator != 0 {
+		m.slope = (n*sumXY - sumX*sumY) / denominator
+	}
+	if n > 0 {
+		m.intercept = (sumY - m.slope*sumX) / n
+	}
+	return m, true
+}
+
+// Predict returns the forecasted cost of the day, never negative
+func (m *Model) Predict(day time.Time) float64 {
+	cost := (m.intercept + m.slope*m.daysSinceOrigin(day)) * m.indices[day.Weekday()]
+	if cost < 0 {
+		return 0
+	}
+	return cost
+}
+
+// PredictRange returns the forecasted cost from start to end, partial days are prorated
+func (m *Model) PredictRange(start, end time.Time) float64 {
+	total := 0.0
+	for day := truncateToDay(start); day.Before(end); day = day.AddDate(0, 0, 1) {
+		from, to := day, day.AddDate(0, 0, 1)
+		if from.Before(start) {
+			from = start
+		}
+		if to.After(end) {
+			to = end
+		}
+		total += m.Predict(day) * to.Sub(from).Hours() / 24
+	}
+	return total
+}
+
+// getSeasonalIndices returns ratio of average cost of each weekday to the overall average cost,
+// all indices are 1 if the history is too short or has no cost. Weekdays missing in history get index 1.
+func getSeasonalIndices(history []DailyCost) [daysInWeek]float64 {
+	var indices [daysInWeek]float64
+	for i := range indices {
+		indices[i] = 1
+	}
+	if len(history) < MinSeasonalDays {
+		return indices
+	}
+
+	var sums [daysInWeek]float64
+	var counts [daysInWeek]float64
+	overall := 0.0
+	for _, daily := range history {
+		sums[daily.Day.Weekday()] += daily.Cost
+		counts[daily.Day.Weekday()]++
+		overall += daily.Cost
+	}
+	overall /= float64(len(history))
+	if overall <= 0 {
+		return indices
+	}
+	for i := range indices {
+		if counts[i] > 0 {
+			indices[i] = sums[i] / counts[i] / overall
+		}
+	}
+	return indices
+}
+
+func (m *Model) daysSinceOrigin(day time.Time) float64 {
+	return math.Round(truncateToDay(day).Sub(m.origin).Hours() / 24)
+}
+
+func truncateToDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,80 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package forecast
+
+import (
+	"testing"
+	"time"
+
+	"github.com/vmware/purser/test/utils"
+)
+
+// 2018-10-01 is a Monday
+var monday = time.Date(2018, 10, 1, 0, 0, 0, 0, time.UTC)
+
+func getWeeklyHistory(weeks int, weekdayCost, weekendCost float64) []DailyCost {
+	history := []DailyCost{}
+	for i := 0; i < weeks*daysInWeek; i++ {
+		day := monday.AddDate(0, 0, i)
+		cost := weekdayCost
+		if day.Weekday() == time.Saturday || day.Weekday() == time.Sunday {
+			cost = weekendCost
+		}
+		history = append(history, DailyCost{Day: day, Cost: cost})
+	}
+	return history
+}
+
+func assertAlmostEqual(t *testing.T, expected, actual float64) {
+	utils.Assert(t, actual > expected-1e-9 && actual < expected+1e-9, "expected %v, got %v", expected, actual)
+}
+
+// TestFitWithWeeklySeasonality ...
+func TestFitWithWeeklySeasonality(t *testing.T) {
+	model, ok := Fit(getWeeklyHistory(3, 10, 30))
+	utils.Assert(t, ok, "model not fitted")
+
+	nextMonday := monday.AddDate(0, 0, 21)
+	assertAlmostEqual(t, 10, model.Predict(nextMonday))
+	assertAlmostEqual(t, 30, model.Predict(nextMonday.AddDate(0, 0, 5)))
+	assertAlmostEqual(t, 110, model.PredictRange(nextMonday, nextMonday.AddDate(0, 0, 7)))
+	assertAlmostEqual(t, 15, model.PredictRange(nextMonday.AddDate(0, 0, 5).Add(12*time.Hour), nextMonday.AddDate(0, 0, 6)))
+}
+
+// TestFitWithoutSeasonality ...
+func TestFitWithoutSeasonality(t *testing.T) {
+	history := []DailyCost{
+		{Day: monday.AddDate(0, 0, 2), Cost: 3},
+		{Day: monday, Cost: 1},
+		{Day: monday.AddDate(0, 0, 1), Cost: 2},
+	}
+	model, ok := Fit(history)
+	utils.Assert(t, ok, "model not fitted")
+	assertAlmostEqual(t, 4, model.Predict(monday.AddDate(0, 0, 3)))
+	assertAlmostEqual(t, 0, model.Predict(monday.AddDate(0, 0, -5)))
+
+	_, ok = Fit(nil)
+	utils.Assert(t, !ok, "model fitted without history")
+}
+
+// TestFitWithIdleWeekends ...
+func TestFitWithIdleWeekends(t *testing.T) {
+	model, _ := Fit(getWeeklyHistory(2, 7, 0))
+	assertAlmostEqual(t, 0, model.Predict(monday.AddDate(0, 0, 19)))
+	assertAlmostEqual(t, 7, model.Predict(monday.AddDate(0, 0, 21)))
+}
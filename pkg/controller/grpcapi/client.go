@@ -0,0 +1,118 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package grpcapi
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+// Client calls Purser service with a Kubernetes token, ex: a service account token of another service
+type Client struct {
+	conn    *grpc.ClientConn
+	client  PurserClient
+	timeout time.Duration
+}
+
+// tokenCredentials adds the bearer token to the metadata of every call
+type tokenCredentials struct {
+	token  string
+	secure bool
+}
+
+func (c tokenCredentials) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	return map[string]string{"authorization": "Bearer " + c.token}, nil
+}
+
+func (c tokenCredentials) RequireTransportSecurity() bool {
+	return c.secure
+}
+
+// Dial returns a client connected to Purser gRPC API at the address. Connection uses TLS verified with the CA
+// certificate if caFile is given. Unary calls time out after timeout, streams are not limited.
+func Dial(address, token, caFile string, timeout time.Duration) (*Client, error) {
+	options := []grpc.DialOption{grpc.WithPerRPCCredentials(tokenCredentials{token: token, secure: caFile != ""})}
+	if caFile != "" {
+		creds, err := credentials.NewClientTLSFromFile(caFile, "")
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, grpc.WithTransportCredentials(creds))
+	} else {
+		options = append(options, grpc.WithInsecure())
+	}
+	conn, err := grpc.Dial(address, options...)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{conn: conn, client: NewPurserClient(conn), timeout: timeout}, nil
+}
+
+// Close closes connection to Purser
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// GetPodHierarchy returns the pod with its containers
+func (c *Client) GetPodHierarchy(request *ResourceRequest) (*Hierarchy, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+	return c.client.GetPodHierarchy(ctx, request)
+}
+
+// GetPodMetrics returns resources and costs of the pod in the time range
+func (c *Client) GetPodMetrics(request *ResourceRequest) (*Hierarchy, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+	return c.client.GetPodMetrics(ctx, request)
+}
+
+// GetPodInteractions returns interactions of the pod, or of a page of pods if name is empty
+func (c *Client) GetPodInteractions(request *InteractionsRequest) (*PodInteractions, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+	return c.client.GetPodInteractions(ctx, request)
+}
+
+// StreamPodInteractions calls receive with interactions of each pod until all are streamed or receive returns
+// an error
+func (c *Client) StreamPodInteractions(request *InteractionsRequest, receive func(*PodInteraction) error) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stream, err := c.client.StreamPodInteractions(ctx, request)
+	if err != nil {
+		return err
+	}
+	for {
+		pod, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err = receive(pod); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,370 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package grpcapi
+
+import (
+	"context"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/vmware/purser/pkg/controller/auth"
+	"github.com/vmware/purser/pkg/controller/dgraph/models/query"
+	"github.com/vmware/purser/pkg/logging"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+var log = logging.Component("grpcapi")
+
+// MaxPageSize is the largest page of pod interactions, it is the page size of streams by default
+const MaxPageSize = 1000
+
+// Query functions used by the server, replaced in tests
+var (
+	retrieveHierarchy = func(ctx context.Context, resource query.Resource) query.JSONDataWrapper {
+		return resource.RetrieveResourceHierarchy(ctx)
+	}
+	retrieveMetrics = func(ctx context.Context, resource query.Resource) query.JSONDataWrapper {
+		return resource.RetrieveResourceMetrics(ctx)
+	}
+	retrieveInteractions = query.RetrievePodsInteractionsInNamespace
+)
+
+// Authorization functions used by the server, replaced in tests
+var (
+	authenticateToken = auth.Authenticate
+	canView           = auth.CanView
+)
+
+// namespacedRequest is a request for resources of a namespace, requests of all methods of Purser service are
+type namespacedRequest interface {
+	GetNamespace() string
+}
+
+// authorizedStream authorizes the request of a stream once it is received
+type authorizedStream struct {
+	grpc.ServerStream
+	identity auth.Identity
+}
+
+func (s *authorizedStream) RecvMsg(m interface{}) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	return authorize(s.identity, m)
+}
+
+// Server implements Purser service of purser.proto over the query package
+// (purser.pb.go is generated by protoc --go_out=plugins=grpc:. purser.proto)
+type Server struct{}
+
+// StartServer serves Purser service on the address, with TLS if certFile and keyFile are given. Requests are
+// authenticated with bearer tokens, so token authentication of the API must be configured.
+func StartServer(address, certFile, keyFile string) {
+	if !auth.IsConfigured() {
+		log.Errorf("gRPC API is not started, it needs token authentication(--apiTokenAuth)")
+		return
+	}
+	options := []grpc.ServerOption{
+		grpc.UnaryInterceptor(authenticateUnary),
+		grpc.StreamInterceptor(authenticateStream),
+	}
+	if certFile != "" && keyFile != "" {
+		creds, err := credentials.NewServerTLSFromFile(certFile, keyFile)
+		if err != nil {
+			log.Errorf("unable to load TLS certificate of gRPC API, err: %v", err)
+			return
+		}
+		options = append(options, grpc.Creds(creds))
+	}
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		log.Errorf("unable to listen on %s for gRPC API, err: %v", address, err)
+		return
+	}
+	server := grpc.NewServer(options...)
+	RegisterPurserServer(server, &Server{})
+	log.Infof("Purser gRPC server started on %s", address)
+	if err := server.Serve(listener); err != nil {
+		log.Errorf("gRPC API stopped, err: %v", err)
+	}
+}
+
+// GetPodHierarchy returns the pod with its containers, if it is in the namespace of the request
+func (s *Server) GetPodHierarchy(ctx context.Context, request *ResourceRequest) (*Hierarchy, error) {
+	if err := validateResourceRequest(request); err != nil {
+		return nil, err
+	}
+	resource := query.Resource{
+		Check:       query.PodCheck,
+		Type:        query.PodType,
+		Name:        request.Name,
+		ChildFilter: query.IsContainerFilter,
+		AsOf:        request.AsOf,
+		Namespace:   request.Namespace,
+	}
+	return toHierarchy(retrieveHierarchy(ctx, resource))
+}
+
+// GetPodMetrics returns resources and costs of the pod in the time range
+func (s *Server) GetPodMetrics(ctx context.Context, request *ResourceRequest) (*Hierarchy, error) {
+	if err := validateResourceRequest(request); err != nil {
+		return nil, err
+	}
+	resource := query.Resource{
+		Check:     query.PodCheck,
+		Type:      query.PodType,
+		Name:      request.Name,
+		Start:     request.Start,
+		End:       request.End,
+		Namespace: request.Namespace,
+	}
+	return toHierarchy(retrieveMetrics(ctx, resource))
+}
+
+// GetPodInteractions returns interactions of the pod, or of a page of pods if name is empty
+func (s *Server) GetPodInteractions(ctx context.Context, request *InteractionsRequest) (*PodInteractions, error) {
+	if err := validateInteractionsRequest(request); err != nil {
+		return nil, err
+	}
+	page := query.Page{First: int(request.PageSize), After: request.After}
+	name := request.Name
+	if name == "" {
+		name = query.All
+	} else {
+		page = query.Page{}
+	}
+	return getPodInteractions(ctx, name, request.Namespace, request.Orphan, page)
+}
+
+// StreamPodInteractions sends interactions of all pods, one page of pods at a time
+func (s *Server) StreamPodInteractions(request *InteractionsRequest, stream Purser_StreamPodInteractionsServer) error {
+	if err := validateInteractionsRequest(request); err != nil {
+		return err
+	}
+	page := query.Page{First: int(request.PageSize), After: request.After}
+	if page.First == 0 {
+		page.First = MaxPageSize
+	}
+	for {
+		interactions, err := getPodInteractions(stream.Context(), query.All, request.Namespace, request.Orphan, page)
+		if err != nil {
+			return err
+		}
+		for _, pod := range interactions.Pods {
+			if err := stream.Send(pod); err != nil {
+				return err
+			}
+		}
+		if interactions.Next == "" {
+			return nil
+		}
+		page.After = interactions.Next
+	}
+}
+
+// getPodInteractions returns the message of the interactions with the fields allowed by the field policy of the API
+func getPodInteractions(ctx context.Context, name, namespace string, isOrphan bool, page query.Page) (*PodInteractions, error) {
+	result, err := retrieveInteractions(ctx, name, namespace, isOrphan, page)
+	if err != nil {
+		log.Error(err)
+		return nil, status.Error(codes.Internal, "unable to retrieve pod interactions")
+	}
+	if err := query.FilterResult(&result); err != nil {
+		log.Errorf("unable to filter fields of pod interactions, err: %v", err)
+		return nil, status.Error(codes.Internal, "unable to retrieve pod interactions")
+	}
+	interactions := &PodInteractions{Pods: []*PodInteraction{}}
+	for _, pod := range result.Pods {
+		podInteraction := &PodInteraction{Name: pod.Name}
+		for _, outbound := range pod.Outbound {
+			podInteraction.Outbound = append(podInteraction.Outbound, &Interaction{Name: outbound.Name, Count: outbound.Count})
+		}
+		for _, inbound := range pod.Inbound {
+			podInteraction.Inbound = append(podInteraction.Inbound, &Interaction{Name: inbound.Name, Count: inbound.Count})
+		}
+		interactions.Pods = append(interactions.Pods, podInteraction)
+	}
+	if result.Page != nil {
+		interactions.Next = result.Page.Next
+	}
+	return interactions, nil
+}
+
+// toHierarchy returns the message of the pod with the fields allowed by the field policy of the API
+func toHierarchy(data query.JSONDataWrapper) (*Hierarchy, error) {
+	if data.Data.Name == "" {
+		return nil, status.Error(codes.NotFound, "pod not found")
+	}
+	if err := query.FilterResult(&data); err != nil {
+		log.Errorf("unable to filter fields of pod, err: %v", err)
+		return nil, status.Error(codes.Internal, "unable to retrieve pod")
+	}
+	parent := data.Data
+	hierarchy := &Hierarchy{
+		Parent: &Resource{
+			Name:                 parent.Name,
+			Type:                 parent.Type,
+			Cpu:                  parent.CPU,
+			Memory:               parent.Memory,
+			Storage:              parent.Storage,
+			CpuCost:              parent.CPUCost,
+			MemoryCost:           parent.MemoryCost,
+			StorageCost:          parent.StorageCost,
+			EphemeralStorageCost: parent.EphemeralStorageCost,
+			HugepagesCost:        parent.HugepagesCost,
+			Gpu:                  parent.GPU,
+			GpuCost:              parent.GPUCost,
+			ExtendedResourceCost: parent.ExtendedResourceCost,
+			BandwidthCost:        parent.BandwidthCost,
+			Carbon:               parent.Carbon,
+		},
+		Children: []*Resource{},
+	}
+	hierarchy.Parent.TotalCost = getTotalCost(hierarchy.Parent)
+	for _, child := range parent.Children {
+		resource := &Resource{
+			Name:                 child.Name,
+			Type:                 child.Type,
+			Cpu:                  child.CPU,
+			Memory:               child.Memory,
+			Storage:              child.Storage,
+			CpuCost:              child.CPUCost,
+			MemoryCost:           child.MemoryCost,
+			StorageCost:          child.StorageCost,
+			EphemeralStorageCost: child.EphemeralStorageCost,
+			HugepagesCost:        child.HugepagesCost,
+			Gpu:                  child.GPU,
+			GpuCost:              child.GPUCost,
+			ExtendedResourceCost: child.ExtendedResourceCost,
+			BandwidthCost:        child.BandwidthCost,
+			Carbon:               child.Carbon,
+		}
+		resource.TotalCost = getTotalCost(resource)
+		hierarchy.Children = append(hierarchy.Children, resource)
+	}
+	return hierarchy, nil
+}
+
+func getTotalCost(r *Resource) float64 {
+	return r.CpuCost + r.MemoryCost + r.StorageCost + r.EphemeralStorageCost + r.HugepagesCost + r.GpuCost +
+		r.ExtendedResourceCost + r.BandwidthCost
+}
+
+func validateResourceRequest(request *ResourceRequest) error {
+	if request.Name == "" {
+		return status.Error(codes.InvalidArgument, "no name is given")
+	}
+	if err := validateNames(request.Name, request.Namespace); err != nil {
+		return err
+	}
+	for _, value := range []string{request.Start, request.End, request.AsOf} {
+		if _, err := time.Parse(time.RFC3339, value); value != "" && err != nil {
+			return status.Errorf(codes.InvalidArgument, "time '%s' is not in RFC 3339 format", value)
+		}
+	}
+	return nil
+}
+
+func validateInteractionsRequest(request *InteractionsRequest) error {
+	if err := validateNames(request.Name, request.Namespace); err != nil {
+		return err
+	}
+	if request.PageSize < 0 || request.PageSize > MaxPageSize {
+		return status.Errorf(codes.InvalidArgument, "page size must be between 0(default) and %d", MaxPageSize)
+	}
+	if request.After != "" && !strings.HasPrefix(request.After, "0x") {
+		return status.Errorf(codes.InvalidArgument, "after '%s' is not a uid", request.After)
+	}
+	return nil
+}
+
+func validateNames(names ...string) error {
+	for _, name := range names {
+		if name != "" && !query.IsValidName(name) {
+			return status.Errorf(codes.InvalidArgument, "name '%s' is not valid", name)
+		}
+	}
+	return nil
+}
+
+// authenticate returns the identity of the bearer token in the authorization metadata, it must belong to a viewer
+// or admin
+func authenticate(ctx context.Context) (auth.Identity, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	token := ""
+	if values := md.Get("authorization"); len(values) > 0 {
+		token = auth.GetBearerToken(values[0])
+	}
+	if token == "" {
+		return auth.Identity{}, status.Error(codes.Unauthenticated, "no bearer token is given")
+	}
+	identity, err := authenticateToken(token)
+	if err != nil {
+		log.Errorf("unable to authenticate bearer token, err: %v", err)
+		return auth.Identity{}, status.Error(codes.Unauthenticated, "invalid token")
+	}
+	if identity.Role == "" {
+		return auth.Identity{}, status.Error(codes.PermissionDenied, "user has no role")
+	}
+	return identity, nil
+}
+
+// authorize checks that the user can view the namespace of the request(ClusterScope if it is empty) when viewers are
+// scoped(--apiScopedViewers), as the HTTP API does for endpoints of the cluster and namespace scopes
+func authorize(identity auth.Identity, request interface{}) error {
+	if identity.Role != auth.Viewer || !auth.HasScopedViewers() {
+		return nil
+	}
+	scope := auth.ClusterScope
+	if r, isNamespaced := request.(namespacedRequest); isNamespaced && r.GetNamespace() != "" {
+		scope = strings.TrimPrefix(r.GetNamespace(), query.NamespaceType+"-")
+	}
+	isAllowed, err := canView(identity, scope)
+	if err != nil {
+		log.Errorf("access review failed, err: %v", err)
+		return status.Error(codes.Internal, "unable to review access")
+	}
+	if !isAllowed {
+		return status.Error(codes.PermissionDenied, "access to scope denied")
+	}
+	return nil
+}
+
+func authenticateUnary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	identity, err := authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorize(identity, req); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func authenticateStream(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	identity, err := authenticate(stream.Context())
+	if err != nil {
+		return err
+	}
+	return handler(srv, &authorizedStream{ServerStream: stream, identity: identity})
+}
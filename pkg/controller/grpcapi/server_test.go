@@ -0,0 +1,254 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package grpcapi
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/vmware/purser/pkg/controller/auth"
+	"github.com/vmware/purser/pkg/controller/dgraph/models/query"
+	"github.com/vmware/purser/test/utils"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+type testSender struct {
+	grpc.ServerStream
+	ctx     context.Context
+	request *InteractionsRequest
+	pods    []*PodInteraction
+}
+
+func (s *testSender) Context() context.Context {
+	if s.ctx != nil {
+		return s.ctx
+	}
+	return context.Background()
+}
+
+func (s *testSender) RecvMsg(m interface{}) error {
+	*m.(*InteractionsRequest) = *s.request
+	return nil
+}
+
+func (s *testSender) Send(pod *PodInteraction) error {
+	s.pods = append(s.pods, pod)
+	return nil
+}
+
+func mockRetrieveInteractions(pages map[string]string, calls *[]query.Page) {
+	retrieveInteractions = func(ctx context.Context, name, namespace string, isOrphan bool, page query.Page) (query.PodInteractions, error) {
+		*calls = append(*calls, page)
+		result := []byte(pages[page.After])
+		if name != query.All {
+			result = []byte(`{"pods": [{"name": "` + name + `", "outbound": [{"name": "pod-db", "pod|count": 4}], "inbound": [{"name": "pod-lb", "pod|count": 2}]}]}`)
+		}
+		interactions := query.PodInteractions{}
+		err := json.Unmarshal(result, &interactions)
+		return interactions, err
+	}
+}
+
+// TestGetPodHierarchy ...
+func TestGetPodHierarchy(t *testing.T) {
+	var got query.Resource
+	retrieveHierarchy = func(ctx context.Context, resource query.Resource) query.JSONDataWrapper {
+		got = resource
+		return query.JSONDataWrapper{Data: query.ParentWrapper{
+			Name:     "pod-web",
+			Type:     query.PodType,
+			CPUCost:  1,
+			GPUCost:  0.5,
+			Children: []query.Children{{Name: "container-web", Type: "container", CPU: 0.5, MemoryCost: 2}},
+		}}
+	}
+	server := &Server{}
+	hierarchy, err := server.GetPodHierarchy(context.Background(), &ResourceRequest{Name: "pod-web", Namespace: "namespace-default"})
+	utils.Ok(t, err)
+	utils.Equals(t, "pod-web", got.Name)
+	utils.Equals(t, query.IsContainerFilter, got.ChildFilter)
+	utils.Equals(t, "namespace-default", got.Namespace)
+	utils.Equals(t, &Resource{Name: "pod-web", Type: query.PodType, CpuCost: 1, GpuCost: 0.5, TotalCost: 1.5}, hierarchy.Parent)
+	utils.Equals(t, []*Resource{{Name: "container-web", Type: "container", Cpu: 0.5, MemoryCost: 2, TotalCost: 2}}, hierarchy.Children)
+
+	retrieveHierarchy = func(ctx context.Context, resource query.Resource) query.JSONDataWrapper {
+		return query.JSONDataWrapper{}
+	}
+	_, err = server.GetPodHierarchy(context.Background(), &ResourceRequest{Name: "pod-missing"})
+	utils.Equals(t, codes.NotFound, status.Code(err))
+}
+
+// TestGetPodHierarchyOfOtherNamespace ...
+func TestGetPodHierarchyOfOtherNamespace(t *testing.T) {
+	scopes := []string{}
+	mockAuthorization(&scopes)
+	auth.SetScopedViewers(true)
+	defer auth.SetScopedViewers(false)
+	// pod-web only exists in namespace-prod
+	retrieveHierarchy = func(ctx context.Context, resource query.Resource) query.JSONDataWrapper {
+		if resource.Name != "pod-web" || resource.Namespace != "namespace-prod" {
+			return query.JSONDataWrapper{}
+		}
+		return query.JSONDataWrapper{Data: query.ParentWrapper{Name: "pod-web", Type: query.PodType}}
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return (&Server{}).GetPodHierarchy(ctx, req.(*ResourceRequest))
+	}
+
+	_, err := authenticateUnary(withToken("dev-token"), &ResourceRequest{Name: "pod-web", Namespace: "namespace-dev"}, nil, handler)
+	utils.Equals(t, codes.NotFound, status.Code(err))
+	_, err = authenticateUnary(withToken("dev-token"), &ResourceRequest{Name: "pod-web", Namespace: "namespace-prod"}, nil, handler)
+	utils.Equals(t, codes.PermissionDenied, status.Code(err))
+	_, err = authenticateUnary(withToken("admin-token"), &ResourceRequest{Name: "pod-web", Namespace: "namespace-prod"}, nil, handler)
+	utils.Ok(t, err)
+}
+
+// TestFieldPolicy ...
+func TestFieldPolicy(t *testing.T) {
+	utils.Ok(t, query.ConfigureFieldPolicy(nil, []string{"gpuCost", "inbound"}))
+	defer func() { utils.Ok(t, query.ConfigureFieldPolicy(nil, nil)) }()
+	retrieveHierarchy = func(ctx context.Context, resource query.Resource) query.JSONDataWrapper {
+		return query.JSONDataWrapper{Data: query.ParentWrapper{
+			Name:     "pod-web",
+			CPUCost:  1,
+			GPUCost:  0.5,
+			Children: []query.Children{{Name: "container-web", GPUCost: 0.5}},
+		}}
+	}
+	hierarchy, err := (&Server{}).GetPodHierarchy(context.Background(), &ResourceRequest{Name: "pod-web"})
+	utils.Ok(t, err)
+	utils.Equals(t, &Resource{Name: "pod-web", CpuCost: 1, TotalCost: 1}, hierarchy.Parent)
+	utils.Equals(t, []*Resource{{Name: "container-web"}}, hierarchy.Children)
+
+	calls := []query.Page{}
+	mockRetrieveInteractions(nil, &calls)
+	interactions, err := (&Server{}).GetPodInteractions(context.Background(), &InteractionsRequest{Name: "pod-web"})
+	utils.Ok(t, err)
+	utils.Equals(t, []*PodInteraction{{Name: "pod-web", Outbound: []*Interaction{{Name: "pod-db", Count: 4}}}}, interactions.Pods)
+}
+
+// TestValidateResourceRequest ...
+func TestValidateResourceRequest(t *testing.T) {
+	utils.Ok(t, validateResourceRequest(&ResourceRequest{Name: "pod-web", Start: "2018-10-01T00:00:00Z"}))
+	utils.Equals(t, codes.InvalidArgument, status.Code(validateResourceRequest(&ResourceRequest{})))
+	utils.Equals(t, codes.InvalidArgument, status.Code(validateResourceRequest(&ResourceRequest{Name: `pod") { uid }`})))
+	utils.Equals(t, codes.InvalidArgument, status.Code(validateResourceRequest(&ResourceRequest{Name: "pod-web", End: "yesterday"})))
+	utils.Ok(t, validateInteractionsRequest(&InteractionsRequest{PageSize: 0}))
+	utils.Equals(t, codes.InvalidArgument, status.Code(validateInteractionsRequest(&InteractionsRequest{PageSize: MaxPageSize + 1})))
+	utils.Equals(t, codes.InvalidArgument, status.Code(validateInteractionsRequest(&InteractionsRequest{After: "abc"})))
+}
+
+// TestGetPodInteractions ...
+func TestGetPodInteractions(t *testing.T) {
+	calls := []query.Page{}
+	mockRetrieveInteractions(nil, &calls)
+	interactions, err := (&Server{}).GetPodInteractions(context.Background(), &InteractionsRequest{Name: "pod-web", PageSize: 10})
+	utils.Ok(t, err)
+	utils.Equals(t, []query.Page{{}}, calls)
+	utils.Equals(t, []*PodInteraction{{
+		Name:     "pod-web",
+		Outbound: []*Interaction{{Name: "pod-db", Count: 4}},
+		Inbound:  []*Interaction{{Name: "pod-lb", Count: 2}},
+	}}, interactions.Pods)
+}
+
+// TestStreamPodInteractions ...
+func TestStreamPodInteractions(t *testing.T) {
+	page := func(names []string, next string) string {
+		pods := []map[string]string{}
+		for _, name := range names {
+			pods = append(pods, map[string]string{"name": name})
+		}
+		encoded, _ := json.Marshal(map[string]interface{}{"pods": pods, "page": query.PageInfo{First: 2, Next: next}})
+		return string(encoded)
+	}
+	calls := []query.Page{}
+	mockRetrieveInteractions(map[string]string{
+		"":    page([]string{"pod-a", "pod-b"}, "0x2"),
+		"0x2": page([]string{"pod-c"}, ""),
+	}, &calls)
+
+	sender := &testSender{}
+	utils.Ok(t, (&Server{}).StreamPodInteractions(&InteractionsRequest{PageSize: 2}, sender))
+	utils.Equals(t, []query.Page{{First: 2}, {First: 2, After: "0x2"}}, calls)
+	utils.Equals(t, 3, len(sender.pods))
+	utils.Equals(t, "pod-c", sender.pods[2].Name)
+
+	calls = []query.Page{}
+	utils.Ok(t, (&Server{}).StreamPodInteractions(&InteractionsRequest{After: "0x2"}, &testSender{}))
+	utils.Equals(t, []query.Page{{First: MaxPageSize, After: "0x2"}}, calls)
+}
+
+func mockAuthorization(scopes *[]string) {
+	authenticateToken = func(token string) (auth.Identity, error) {
+		if token == "admin-token" {
+			return auth.Identity{Username: "admin", Role: auth.Admin}, nil
+		}
+		return auth.Identity{Username: "system:serviceaccount:dev:default", Role: auth.Viewer}, nil
+	}
+	canView = func(identity auth.Identity, scope string) (bool, error) {
+		*scopes = append(*scopes, scope)
+		return identity.Role == auth.Admin || scope == "dev", nil
+	}
+}
+
+func withToken(token string) context.Context {
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+token))
+}
+
+// TestAuthorizeScopedViewers ...
+func TestAuthorizeScopedViewers(t *testing.T) {
+	scopes := []string{}
+	mockAuthorization(&scopes)
+	auth.SetScopedViewers(true)
+	defer auth.SetScopedViewers(false)
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return req, nil
+	}
+
+	_, err := authenticateUnary(withToken("dev-token"), &ResourceRequest{Name: "pod-web", Namespace: "namespace-dev"}, nil, handler)
+	utils.Ok(t, err)
+	_, err = authenticateUnary(withToken("dev-token"), &ResourceRequest{Name: "pod-web", Namespace: "namespace-prod"}, nil, handler)
+	utils.Equals(t, codes.PermissionDenied, status.Code(err))
+	_, err = authenticateUnary(withToken("dev-token"), &InteractionsRequest{}, nil, handler)
+	utils.Equals(t, codes.PermissionDenied, status.Code(err))
+	utils.Equals(t, []string{"dev", "prod", auth.ClusterScope}, scopes)
+
+	_, err = authenticateUnary(withToken("admin-token"), &InteractionsRequest{}, nil, handler)
+	utils.Ok(t, err)
+	_, err = authenticateUnary(context.Background(), &InteractionsRequest{}, nil, handler)
+	utils.Equals(t, codes.Unauthenticated, status.Code(err))
+
+	streamHandler := func(srv interface{}, stream grpc.ServerStream) error {
+		return stream.RecvMsg(&InteractionsRequest{})
+	}
+	stream := &testSender{ctx: withToken("dev-token"), request: &InteractionsRequest{Namespace: "namespace-prod"}}
+	utils.Equals(t, codes.PermissionDenied, status.Code(authenticateStream(nil, stream, nil, streamHandler)))
+	stream.request = &InteractionsRequest{Namespace: "namespace-dev"}
+	utils.Ok(t, authenticateStream(nil, stream, nil, streamHandler))
+
+	auth.SetScopedViewers(false)
+	scopes = []string{}
+	_, err = authenticateUnary(withToken("dev-token"), &InteractionsRequest{}, nil, handler)
+	utils.Ok(t, err)
+	utils.Equals(t, []string{}, scopes)
+}
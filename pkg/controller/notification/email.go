@@ -0,0 +1,106 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package notification
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"net/smtp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// EmailChannelName is the name of the channel mailing notifications over SMTP
+const EmailChannelName = "email"
+
+var (
+	// sendMail is smtp.SendMail, replaced in tests
+	sendMail = smtp.SendMail
+
+	// headerEscaper keeps titles from adding headers to messages
+	headerEscaper = strings.NewReplacer("\r", " ", "\n", " ")
+)
+
+// EmailChannel mails notifications as plain text to recipients through an SMTP server
+type EmailChannel struct {
+	address string
+	auth    smtp.Auth
+	from    string
+	to      []string
+}
+
+// NewEmailChannel returns a channel mailing notifications from the sender to recipients through the SMTP server at
+// address(ex: smtp.example.com:587), it authenticates with PLAIN auth if username is given
+func NewEmailChannel(address, username, password, from string, to []string) (*EmailChannel, error) {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, fmt.Errorf("invalid SMTP address %s: %v", address, err)
+	}
+	if from == "" || len(to) == 0 {
+		return nil, fmt.Errorf("email channel needs a sender and at least one recipient")
+	}
+	channel := &EmailChannel{address: address, from: from, to: to}
+	if username != "" {
+		channel.auth = smtp.PlainAuth("", username, password, host)
+	}
+	return channel, nil
+}
+
+// Name of the channel
+func (c *EmailChannel) Name() string {
+	return EmailChannelName
+}
+
+// Send mails the notification with the title as subject
+func (c *EmailChannel) Send(n Notification) error {
+	return sendMail(c.address, c.auth, c.from, c.to, getEmailMessage(c.from, c.to, n))
+}
+
+// getEmailMessage returns the message with headers and a body of the summary, details and source of the notification
+func getEmailMessage(from string, to []string, n Notification) []byte {
+	subject := n.Title
+	if n.Status != "" {
+		subject = "[" + strings.ToUpper(n.Status) + "] " + subject
+	}
+	message := &bytes.Buffer{}
+	fmt.Fprintf(message, "From: %s\r\n", from)
+	fmt.Fprintf(message, "To: %s\r\n", strings.Join(to, ", "))
+	fmt.Fprintf(message, "Subject: %s\r\n", headerEscaper.Replace(subject))
+	fmt.Fprintf(message, "Date: %s\r\n", n.Time.Format(time.RFC1123Z))
+	message.WriteString("MIME-Version: 1.0\r\n")
+	message.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	if n.Summary != "" {
+		message.WriteString(strings.Replace(n.Summary, "\n", "\r\n", -1) + "\r\n\r\n")
+	}
+
+	keys := make([]string, 0, len(n.Details))
+	for key := range n.Details {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fmt.Fprintf(message, "%s: %v\r\n", key, n.Details[key])
+	}
+	if len(keys) > 0 {
+		message.WriteString("\r\n")
+	}
+	fmt.Fprintf(message, "%s · %s\r\n", n.Source, n.Time.Format(time.RFC1123))
+	return message.Bytes()
+}
@@ -0,0 +1,59 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package notification
+
+import (
+	"net/smtp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vmware/purser/test/utils"
+)
+
+// TestEmailChannelSend ...
+func TestEmailChannelSend(t *testing.T) {
+	var address string
+	var message []byte
+	sendMail = func(addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+		address, message = addr, msg
+		utils.Assert(t, a != nil, "no auth for username")
+		utils.Equals(t, []string{"finance@example.com"}, to)
+		return nil
+	}
+	defer func() { sendMail = smtp.SendMail }()
+
+	channel, err := NewEmailChannel("smtp.example.com:587", "purser", "secret", "purser@example.com", []string{"finance@example.com"})
+	utils.Ok(t, err)
+	n := Notification{Title: "Weekly cost report\r\nBcc: x@example.com", Summary: "Total: 120.50\nshop: 80.00", Source: "purser", Time: time.Now()}
+	utils.Ok(t, channel.Send(n))
+	utils.Equals(t, "smtp.example.com:587", address)
+	utils.Assert(t, strings.Contains(string(message), "Subject: Weekly cost report  Bcc: x@example.com\r\n"), "subject not escaped: %s", message)
+	utils.Assert(t, strings.Contains(string(message), "\r\n\r\nTotal: 120.50\r\nshop: 80.00\r\n"), "body missing: %s", message)
+}
+
+// TestNewEmailChannel ...
+func TestNewEmailChannel(t *testing.T) {
+	_, err := NewEmailChannel("smtp.example.com", "", "", "purser@example.com", []string{"finance@example.com"})
+	utils.Assert(t, err != nil, "address without port accepted")
+	_, err = NewEmailChannel("smtp.example.com:25", "", "", "purser@example.com", nil)
+	utils.Assert(t, err != nil, "no recipients accepted")
+	channel, err := NewEmailChannel("smtp.example.com:25", "", "", "purser@example.com", []string{"finance@example.com"})
+	utils.Ok(t, err)
+	utils.Assert(t, channel.auth == nil, "auth without username")
+}
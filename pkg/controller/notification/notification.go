@@ -0,0 +1,98 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package notification
+
+import (
+	"sync"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+)
+
+// Statuses of notifications
+const (
+	Firing   = "firing"
+	Resolved = "resolved"
+)
+
+// Severities of notifications in increasing order
+const (
+	SeverityInfo     = "info"
+	SeverityWarning  = "warning"
+	SeverityCritical = "critical"
+)
+
+var severityOrder = map[string]int{SeverityInfo: 0, SeverityWarning: 1, SeverityCritical: 2}
+
+// Notification is an event worth notifying, DedupKey identifies notifications of the same incident so that
+// channels can group them and resolve the incident when a notification with Resolved status is dispatched
+type Notification struct {
+	Title    string                 `json:"title"`
+	Summary  string                 `json:"summary,omitempty"`
+	Severity string                 `json:"severity,omitempty"`
+	Status   string                 `json:"status"`
+	DedupKey string                 `json:"dedupKey,omitempty"`
+	Source   string                 `json:"source,omitempty"`
+	Time     time.Time              `json:"time"`
+	Details  map[string]interface{} `json:"details,omitempty"`
+}
+
+// Channel delivers notifications to a destination
+type Channel interface {
+	Name() string
+	Send(n Notification) error
+}
+
+var (
+	channelsMu sync.RWMutex
+	channels   = make(map[string]Channel)
+)
+
+// IsSeverityAtLeast returns true if severity is same as or above min, unknown severities are treated as warning
+func IsSeverityAtLeast(severity, min string) bool {
+	return getSeverityOrder(severity) >= getSeverityOrder(min)
+}
+
+func getSeverityOrder(severity string) int {
+	if order, isPresent := severityOrder[severity]; isPresent {
+		return order
+	}
+	return severityOrder[SeverityWarning]
+}
+
+// RegisterChannel adds the channel to the dispatcher, a channel with the same name is replaced
+func RegisterChannel(channel Channel) {
+	channelsMu.Lock()
+	defer channelsMu.Unlock()
+	channels[channel.Name()] = channel
+	log.Infof("registered notification channel: %s", channel.Name())
+}
+
+// Dispatch sends the notification to all registered channels, failures of a channel don't affect others
+func Dispatch(n Notification) {
+	if n.Time.IsZero() {
+		n.Time = time.Now()
+	}
+	channelsMu.RLock()
+	defer channelsMu.RUnlock()
+	for name, channel := range channels {
+		if err := channel.Send(n); err != nil {
+			log.Errorf("unable to send notification: %s to channel: %s, err: %v", n.Title, name, err)
+		}
+	}
+}
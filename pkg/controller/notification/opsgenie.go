@@ -0,0 +1,116 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package notification
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Opsgenie constants
+const (
+	OpsgenieChannelName = "opsgenie"
+	OpsgenieURL         = "https://api.opsgenie.com"
+)
+
+// OpsgenieChannel creates alerts with Opsgenie Alert API for firing notifications and closes them for resolved
+// notifications. Dedup key of notifications is used as alias so that Opsgenie deduplicates alerts.
+type OpsgenieChannel struct {
+	client      *http.Client
+	url         string
+	apiKey      string
+	minSeverity string
+}
+
+type opsgenieAlert struct {
+	Message     string            `json:"message"`
+	Alias       string            `json:"alias,omitempty"`
+	Description string            `json:"description,omitempty"`
+	Source      string            `json:"source,omitempty"`
+	Priority    string            `json:"priority,omitempty"`
+	Details     map[string]string `json:"details,omitempty"`
+}
+
+// NewOpsgenieChannel returns a channel sending notifications of at least minSeverity to Opsgenie at the url
+// (ex: https://api.eu.opsgenie.com for EU accounts) with the API key
+func NewOpsgenieChannel(apiURL, apiKey, minSeverity string, timeout time.Duration) *OpsgenieChannel {
+	if apiURL == "" {
+		apiURL = OpsgenieURL
+	}
+	return &OpsgenieChannel{
+		client:      &http.Client{Timeout: timeout},
+		url:         apiURL,
+		apiKey:      apiKey,
+		minSeverity: minSeverity,
+	}
+}
+
+// Name of the channel
+func (c *OpsgenieChannel) Name() string {
+	return OpsgenieChannelName
+}
+
+// Send creates or closes the alert of the notification
+func (c *OpsgenieChannel) Send(n Notification) error {
+	if !IsSeverityAtLeast(n.Severity, c.minSeverity) {
+		return nil
+	}
+	headers := map[string]string{"Authorization": "GenieKey " + c.apiKey}
+	if n.Status == Resolved {
+		if n.DedupKey == "" {
+			return fmt.Errorf("resolved notification: %s has no dedup key", n.Title)
+		}
+		closeURL := c.url + "/v2/alerts/" + url.PathEscape(n.DedupKey) + "/close?identifierType=alias"
+		body, err := json.Marshal(map[string]string{"source": n.Source})
+		if err != nil {
+			return err
+		}
+		return post(c.client, closeURL, headers, body)
+	}
+
+	alert := opsgenieAlert{
+		Message:     truncate(n.Title, 130),
+		Alias:       n.DedupKey,
+		Description: n.Summary,
+		Source:      n.Source,
+		Priority:    getOpsgeniePriority(n.Severity),
+		Details:     make(map[string]string),
+	}
+	for key, value := range n.Details {
+		alert.Details[key] = fmt.Sprintf("%v", value)
+	}
+	body, err := json.Marshal(alert)
+	if err != nil {
+		return err
+	}
+	return post(c.client, c.url+"/v2/alerts", headers, body)
+}
+
+// getOpsgeniePriority maps severity of notification to priority P1(critical), P3(warning) or P5(info)
+func getOpsgeniePriority(severity string) string {
+	switch severity {
+	case SeverityCritical:
+		return "P1"
+	case SeverityInfo:
+		return "P5"
+	}
+	return "P3"
+}
@@ -0,0 +1,62 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package notification
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/vmware/purser/test/utils"
+)
+
+// TestOpsgenieChannelSend ...
+func TestOpsgenieChannelSend(t *testing.T) {
+	paths := []string{}
+	var alert opsgenieAlert
+	var authorization string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.RequestURI())
+		authorization = r.Header.Get("Authorization")
+		if r.URL.Path == "/v2/alerts" {
+			utils.Ok(t, json.NewDecoder(r.Body).Decode(&alert))
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	channel := NewOpsgenieChannel(server.URL, "key", SeverityWarning, time.Second)
+	n := Notification{
+		Title:    "high cost",
+		Severity: SeverityCritical,
+		Status:   Firing,
+		DedupKey: "purser-alert-high-cost",
+		Details:  map[string]interface{}{"value": 120.5},
+	}
+	utils.Ok(t, channel.Send(n))
+	utils.Equals(t, "GenieKey key", authorization)
+	utils.Equals(t, "P1", alert.Priority)
+	utils.Equals(t, "purser-alert-high-cost", alert.Alias)
+	utils.Equals(t, "120.5", alert.Details["value"])
+
+	n.Status = Resolved
+	utils.Ok(t, channel.Send(n))
+	utils.Equals(t, []string{"/v2/alerts", "/v2/alerts/purser-alert-high-cost/close?identifierType=alias"}, paths)
+}
@@ -0,0 +1,116 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package notification
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// PagerDuty constants
+const (
+	PagerDutyChannelName = "pagerduty"
+	PagerDutyEventsURL   = "https://events.pagerduty.com/v2/enqueue"
+)
+
+// PagerDutyChannel sends notifications as events of PagerDuty Events API v2. Firing notifications trigger
+// incidents and resolved notifications resolve them, incidents are deduplicated by the dedup key of notifications.
+type PagerDutyChannel struct {
+	client      *http.Client
+	url         string
+	routingKey  string
+	minSeverity string
+}
+
+type pagerDutyEvent struct {
+	RoutingKey  string            `json:"routing_key"`
+	EventAction string            `json:"event_action"`
+	DedupKey    string            `json:"dedup_key,omitempty"`
+	Payload     *pagerDutyPayload `json:"payload,omitempty"`
+}
+
+type pagerDutyPayload struct {
+	Summary       string                 `json:"summary"`
+	Source        string                 `json:"source"`
+	Severity      string                 `json:"severity"`
+	Timestamp     string                 `json:"timestamp,omitempty"`
+	CustomDetails map[string]interface{} `json:"custom_details,omitempty"`
+}
+
+// NewPagerDutyChannel returns a channel sending notifications of at least minSeverity to the integration with the routing key
+func NewPagerDutyChannel(routingKey, minSeverity string, timeout time.Duration) *PagerDutyChannel {
+	return &PagerDutyChannel{
+		client:      &http.Client{Timeout: timeout},
+		url:         PagerDutyEventsURL,
+		routingKey:  routingKey,
+		minSeverity: minSeverity,
+	}
+}
+
+// Name of the channel
+func (c *PagerDutyChannel) Name() string {
+	return PagerDutyChannelName
+}
+
+// Send triggers or resolves the incident of the notification
+func (c *PagerDutyChannel) Send(n Notification) error {
+	if !IsSeverityAtLeast(n.Severity, c.minSeverity) {
+		return nil
+	}
+	event := pagerDutyEvent{
+		RoutingKey:  c.routingKey,
+		EventAction: "trigger",
+		DedupKey:    n.DedupKey,
+	}
+	if n.Status == Resolved {
+		event.EventAction = "resolve"
+	} else {
+		event.Payload = &pagerDutyPayload{
+			Summary:       truncate(n.Title, 1024),
+			Source:        n.Source,
+			Severity:      getPagerDutySeverity(n.Severity),
+			Timestamp:     n.Time.Format(time.RFC3339),
+			CustomDetails: n.Details,
+		}
+	}
+
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	return post(c.client, c.url, nil, body)
+}
+
+// getPagerDutySeverity maps severity of notification to one of critical, error, warning or info
+func getPagerDutySeverity(severity string) string {
+	switch severity {
+	case SeverityCritical, SeverityInfo:
+		return severity
+	}
+	return SeverityWarning
+}
+
+// truncate returns at most max characters of s
+func truncate(s string, max int) string {
+	runes := []rune(s)
+	if len(runes) > max {
+		return string(runes[:max])
+	}
+	return s
+}
@@ -0,0 +1,64 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package notification
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/vmware/purser/test/utils"
+)
+
+// TestPagerDutyChannelSend ...
+func TestPagerDutyChannelSend(t *testing.T) {
+	events := []pagerDutyEvent{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		event := pagerDutyEvent{}
+		utils.Ok(t, json.NewDecoder(r.Body).Decode(&event))
+		events = append(events, event)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	channel := NewPagerDutyChannel("key", SeverityCritical, time.Second)
+	channel.url = server.URL
+	n := Notification{Title: "high cost", Severity: SeverityCritical, Status: Firing, DedupKey: "purser-alert-high-cost", Source: "purser"}
+	utils.Ok(t, channel.Send(n))
+	n.Status = Resolved
+	utils.Ok(t, channel.Send(n))
+	utils.Ok(t, channel.Send(Notification{Title: "low cost", Severity: SeverityWarning, Status: Firing}))
+
+	utils.Equals(t, 2, len(events))
+	utils.Equals(t, "trigger", events[0].EventAction)
+	utils.Equals(t, "key", events[0].RoutingKey)
+	utils.Equals(t, "purser-alert-high-cost", events[0].DedupKey)
+	utils.Equals(t, "critical", events[0].Payload.Severity)
+	utils.Equals(t, "resolve", events[1].EventAction)
+	utils.Equals(t, "purser-alert-high-cost", events[1].DedupKey)
+	utils.Assert(t, events[1].Payload == nil, "payload sent with resolve event")
+}
+
+// TestIsSeverityAtLeast ...
+func TestIsSeverityAtLeast(t *testing.T) {
+	utils.Assert(t, IsSeverityAtLeast(SeverityCritical, SeverityWarning), "critical is below warning")
+	utils.Assert(t, !IsSeverityAtLeast(SeverityInfo, SeverityWarning), "info is not below warning")
+	utils.Assert(t, IsSeverityAtLeast("", SeverityWarning), "unknown severity is below warning")
+}
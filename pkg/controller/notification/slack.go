@@ -0,0 +1,113 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package notification
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SlackChannelName is the name of the channel posting notifications to Slack
+const SlackChannelName = "slack"
+
+// SlackChannel posts notifications as Block Kit messages to a Slack incoming webhook
+type SlackChannel struct {
+	client *http.Client
+	url    string
+}
+
+type slackMessage struct {
+	Text   string       `json:"text"`
+	Blocks []slackBlock `json:"blocks"`
+}
+
+type slackBlock struct {
+	Type     string      `json:"type"`
+	Text     *slackText  `json:"text,omitempty"`
+	Fields   []slackText `json:"fields,omitempty"`
+	Elements []slackText `json:"elements,omitempty"`
+}
+
+type slackText struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// NewSlackChannel returns a channel posting notifications to the Slack webhook url
+func NewSlackChannel(webhookURL string, timeout time.Duration) *SlackChannel {
+	return &SlackChannel{
+		client: &http.Client{Timeout: timeout},
+		url:    webhookURL,
+	}
+}
+
+// Name of the channel
+func (c *SlackChannel) Name() string {
+	return SlackChannelName
+}
+
+// Send posts the notification as a message with the status, title, summary and details
+func (c *SlackChannel) Send(n Notification) error {
+	body, err := json.Marshal(getSlackMessage(n))
+	if err != nil {
+		return err
+	}
+	return post(c.client, c.url, nil, body)
+}
+
+// getSlackMessage returns a message with the title as fallback text, a section with the status, title and summary
+// in mrkdwn, details as fields and the source with time as context
+func getSlackMessage(n Notification) slackMessage {
+	heading := "*" + n.Title + "*"
+	if n.Status != "" {
+		status := strings.ToUpper(n.Status)
+		if n.Severity != "" {
+			status += " · " + n.Severity
+		}
+		heading = "*" + status + "* " + heading
+	}
+	if n.Summary != "" {
+		heading += "\n" + n.Summary
+	}
+	blocks := []slackBlock{{Type: "section", Text: &slackText{Type: "mrkdwn", Text: heading}}}
+
+	keys := make([]string, 0, len(n.Details))
+	for key := range n.Details {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	fields := []slackText{}
+	for _, key := range keys {
+		fields = append(fields, slackText{Type: "mrkdwn", Text: fmt.Sprintf("*%s*\n%v", key, n.Details[key])})
+	}
+	// slack allows at most 10 fields in a section
+	for len(fields) > 0 {
+		count := len(fields)
+		if count > 10 {
+			count = 10
+		}
+		blocks = append(blocks, slackBlock{Type: "section", Fields: fields[:count]})
+		fields = fields[count:]
+	}
+	blocks = append(blocks, slackBlock{Type: "context", Elements: []slackText{{Type: "mrkdwn", Text: n.Source + " · " + n.Time.Format(time.RFC1123)}}})
+	return slackMessage{Text: n.Title, Blocks: blocks}
+}
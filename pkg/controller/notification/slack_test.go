@@ -0,0 +1,59 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package notification
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/vmware/purser/test/utils"
+)
+
+// TestSlackChannelSend ...
+func TestSlackChannelSend(t *testing.T) {
+	var message map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		utils.Ok(t, json.NewDecoder(r.Body).Decode(&message))
+	}))
+	defer server.Close()
+
+	n := Notification{Title: "high cost", Severity: SeverityCritical, Status: Firing, Details: map[string]interface{}{"value": 120.5}}
+	utils.Ok(t, NewSlackChannel(server.URL, time.Second).Send(n))
+	utils.Equals(t, "high cost", message["text"])
+	utils.Equals(t, 3, len(message["blocks"].([]interface{})))
+}
+
+// TestGetSlackMessage ...
+func TestGetSlackMessage(t *testing.T) {
+	n := Notification{Title: "Weekly cost report", Summary: "Total: 120.50", Source: "purser"}
+	blocks := getSlackMessage(n).Blocks
+	utils.Equals(t, "*Weekly cost report*\nTotal: 120.50", blocks[0].Text.Text)
+	utils.Equals(t, "context", blocks[1].Type)
+
+	n = Notification{Title: "high cost", Severity: SeverityWarning, Status: Resolved, Details: map[string]interface{}{}}
+	for i := 0; i < 12; i++ {
+		n.Details[string(rune('a'+i))] = i
+	}
+	blocks = getSlackMessage(n).Blocks
+	utils.Equals(t, "*RESOLVED · warning* *high cost*", blocks[0].Text.Text)
+	utils.Equals(t, 10, len(blocks[1].Fields))
+	utils.Equals(t, slackText{Type: "mrkdwn", Text: "*k*\n10"}, blocks[2].Fields[0])
+}
@@ -0,0 +1,150 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package notification
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// TeamsChannelName is the name of the channel posting notifications to Microsoft Teams
+const TeamsChannelName = "teams"
+
+// TeamsChannel posts notifications as adaptive cards to a Microsoft Teams incoming webhook(or workflow webhook)
+type TeamsChannel struct {
+	client *http.Client
+	url    string
+}
+
+type teamsMessage struct {
+	Type        string            `json:"type"`
+	Attachments []teamsAttachment `json:"attachments"`
+}
+
+type teamsAttachment struct {
+	ContentType string       `json:"contentType"`
+	Content     adaptiveCard `json:"content"`
+}
+
+type adaptiveCard struct {
+	Schema  string        `json:"$schema"`
+	Type    string        `json:"type"`
+	Version string        `json:"version"`
+	Body    []interface{} `json:"body"`
+}
+
+type textBlock struct {
+	Type     string `json:"type"`
+	Text     string `json:"text"`
+	Weight   string `json:"weight,omitempty"`
+	Size     string `json:"size,omitempty"`
+	Color    string `json:"color,omitempty"`
+	IsSubtle bool   `json:"isSubtle,omitempty"`
+	Wrap     bool   `json:"wrap"`
+}
+
+type factSet struct {
+	Type  string `json:"type"`
+	Facts []fact `json:"facts"`
+}
+
+type fact struct {
+	Title string `json:"title"`
+	Value string `json:"value"`
+}
+
+// NewTeamsChannel returns a channel posting notifications to the Teams webhook url
+func NewTeamsChannel(webhookURL string, timeout time.Duration) *TeamsChannel {
+	return &TeamsChannel{
+		client: &http.Client{Timeout: timeout},
+		url:    webhookURL,
+	}
+}
+
+// Name of the channel
+func (c *TeamsChannel) Name() string {
+	return TeamsChannelName
+}
+
+// Send posts the notification as an adaptive card
+func (c *TeamsChannel) Send(n Notification) error {
+	body, err := json.Marshal(getTeamsMessage(n))
+	if err != nil {
+		return err
+	}
+	return post(c.client, c.url, nil, body)
+}
+
+// getTeamsMessage returns a message with an adaptive card having the title colored by status and severity,
+// the summary and details of the notification as facts
+func getTeamsMessage(n Notification) teamsMessage {
+	status := strings.ToUpper(n.Status)
+	if n.Severity != "" {
+		status += " · " + n.Severity
+	}
+	body := []interface{}{
+		textBlock{Type: "TextBlock", Text: status, Weight: "Bolder", Color: getTeamsColor(n), Wrap: true},
+		textBlock{Type: "TextBlock", Text: n.Title, Weight: "Bolder", Size: "Medium", Wrap: true},
+	}
+	if n.Summary != "" {
+		body = append(body, textBlock{Type: "TextBlock", Text: n.Summary, Wrap: true})
+	}
+
+	keys := make([]string, 0, len(n.Details))
+	for key := range n.Details {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	facts := []fact{}
+	for _, key := range keys {
+		facts = append(facts, fact{Title: key, Value: fmt.Sprintf("%v", n.Details[key])})
+	}
+	if len(facts) > 0 {
+		body = append(body, factSet{Type: "FactSet", Facts: facts})
+	}
+	body = append(body, textBlock{Type: "TextBlock", Text: n.Source + " · " + n.Time.Format(time.RFC1123), IsSubtle: true, Size: "Small", Wrap: true})
+
+	return teamsMessage{
+		Type: "message",
+		Attachments: []teamsAttachment{{
+			ContentType: "application/vnd.microsoft.card.adaptive",
+			Content: adaptiveCard{
+				Schema:  "http://adaptivecards.io/schemas/adaptive-card.json",
+				Type:    "AdaptiveCard",
+				Version: "1.4",
+				Body:    body,
+			},
+		}},
+	}
+}
+
+func getTeamsColor(n Notification) string {
+	switch {
+	case n.Status == Resolved:
+		return "Good"
+	case n.Severity == SeverityCritical:
+		return "Attention"
+	case n.Severity == SeverityInfo:
+		return "Accent"
+	}
+	return "Warning"
+}
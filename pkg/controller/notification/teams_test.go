@@ -0,0 +1,55 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package notification
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/vmware/purser/test/utils"
+)
+
+// TestTeamsChannelSend ...
+func TestTeamsChannelSend(t *testing.T) {
+	var message map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		utils.Ok(t, json.NewDecoder(r.Body).Decode(&message))
+	}))
+	defer server.Close()
+
+	n := Notification{Title: "high cost", Severity: SeverityCritical, Status: Firing, Details: map[string]interface{}{"value": 120.5, "metric": "cost"}}
+	utils.Ok(t, NewTeamsChannel(server.URL, time.Second).Send(n))
+	attachment := message["attachments"].([]interface{})[0].(map[string]interface{})
+	utils.Equals(t, "application/vnd.microsoft.card.adaptive", attachment["contentType"])
+	utils.Equals(t, "AdaptiveCard", attachment["content"].(map[string]interface{})["type"])
+}
+
+// TestGetTeamsMessage ...
+func TestGetTeamsMessage(t *testing.T) {
+	n := Notification{Title: "high cost", Severity: SeverityCritical, Status: Firing, Details: map[string]interface{}{"value": 120.5, "metric": "cost"}}
+	body := getTeamsMessage(n).Attachments[0].Content.Body
+	utils.Equals(t, "FIRING · critical", body[0].(textBlock).Text)
+	utils.Equals(t, "Attention", body[0].(textBlock).Color)
+	utils.Equals(t, []fact{{Title: "metric", Value: "cost"}, {Title: "value", Value: "120.5"}}, body[2].(factSet).Facts)
+
+	n.Status = Resolved
+	utils.Equals(t, "Good", getTeamsMessage(n).Attachments[0].Content.Body[0].(textBlock).Color)
+}
@@ -0,0 +1,98 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package notification
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/vmware/purser/pkg/controller/dgraph"
+	"github.com/vmware/purser/pkg/controller/dgraph/models"
+	"github.com/vmware/purser/pkg/controller/dgraph/models/query"
+)
+
+// WebhookChannelName is the name of the channel posting notifications to subscribers
+const WebhookChannelName = "webhook"
+
+// WebhookChannel posts notifications as JSON({"notifications": [...]}) to urls of subscribers
+type WebhookChannel struct {
+	client      *http.Client
+	subscribers func(ctx context.Context) ([]models.SubscriberCRD, error)
+}
+
+// NewWebhookChannel returns a channel posting notifications to subscribers stored in dgraph
+func NewWebhookChannel(timeout time.Duration) *WebhookChannel {
+	return &WebhookChannel{
+		client:      &http.Client{Timeout: timeout},
+		subscribers: query.RetrieveSubscribers,
+	}
+}
+
+// Name of the channel
+func (c *WebhookChannel) Name() string {
+	return WebhookChannelName
+}
+
+// Send posts the notification to all subscribers
+func (c *WebhookChannel) Send(n Notification) error {
+	subscribers, err := c.subscribers(dgraph.WithOwnCluster(context.Background()))
+	if err != nil {
+		return fmt.Errorf("unable to retrieve subscribers: %v", err)
+	}
+	body, err := json.Marshal(map[string][]Notification{"notifications": {n}})
+	if err != nil {
+		return err
+	}
+
+	var failed []string
+	for _, subscriber := range subscribers {
+		if err := post(c.client, subscriber.Spec.URL, subscriber.Spec.Headers, body); err != nil {
+			failed = append(failed, err.Error())
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("%d of %d subscribers failed: %v", len(failed), len(subscribers), failed)
+	}
+	return nil
+}
+
+// post sends JSON body to the url with the given headers and expects a 2xx response
+func post(client *http.Client, url string, headers map[string]string, body []byte) error {
+	req, err := http.NewRequest("POST", url, bytes.NewBuffer(body))
+	if err != nil {
+		return fmt.Errorf("error creating HTTP request for %s: %v", url, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error sending data to %s: %v", url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("posting to %s failed: %s", url, resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/vmware/purser/pkg/controller/dgraph/models"
+	"github.com/vmware/purser/test/utils"
+)
+
+// TestWebhookChannelSend ...
+func TestWebhookChannelSend(t *testing.T) {
+	var received map[string][]Notification
+	var token string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token = r.Header.Get("Authorization")
+		utils.Ok(t, json.NewDecoder(r.Body).Decode(&received))
+	}))
+	defer server.Close()
+
+	channel := NewWebhookChannel(time.Second)
+	channel.subscribers = func(ctx context.Context) ([]models.SubscriberCRD, error) {
+		return []models.SubscriberCRD{{Spec: models.SubscriberSpec{URL: server.URL, Headers: map[string]string{"Authorization": "Bearer x"}}}}, nil
+	}
+	n := Notification{Title: "cost of namespace-default is high", Status: Firing, DedupKey: "purser-high-cost"}
+	utils.Ok(t, channel.Send(n))
+	utils.Equals(t, "Bearer x", token)
+	utils.Equals(t, "purser-high-cost", received["notifications"][0].DedupKey)
+
+	server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	utils.Assert(t, channel.Send(n) != nil, "failed post not reported")
+}
@@ -0,0 +1,359 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package report
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/vmware/purser/pkg/controller/dgraph"
+	"github.com/vmware/purser/pkg/controller/dgraph/models"
+	"github.com/vmware/purser/pkg/controller/dgraph/models/query"
+	"github.com/vmware/purser/pkg/controller/notification"
+	"k8s.io/apimachinery/pkg/util/yaml"
+)
+
+// Schedules of cost reports
+const (
+	Weekly  = "weekly"
+	Monthly = "monthly"
+)
+
+const (
+	// Source of notifications of cost reports
+	Source = "purser"
+	// DefaultTop is the number of namespaces, movers and groups listed in a report
+	DefaultTop = 5
+)
+
+// Config structure of scheduled cost reports, they are delivered to each configured destination
+type Config struct {
+	Schedule string       `json:"schedule"`
+	Top      int          `json:"top,omitempty"`
+	SMTP     *SMTPConfig  `json:"smtp,omitempty"`
+	Slack    *SlackConfig `json:"slack,omitempty"`
+}
+
+// SMTPConfig structure, Address is host:port of the SMTP server and Username enables PLAIN auth
+type SMTPConfig struct {
+	Address  string   `json:"address"`
+	Username string   `json:"username,omitempty"`
+	Password string   `json:"password,omitempty"`
+	From     string   `json:"from"`
+	To       []string `json:"to"`
+}
+
+// SlackConfig structure
+type SlackConfig struct {
+	WebhookURL string `json:"webhookURL"`
+}
+
+// NamespaceCost is the cost of a namespace in the period of a report and in the period before
+type NamespaceCost struct {
+	Name          string  `json:"name"`
+	Cost          float64 `json:"cost"`
+	PreviousCost  float64 `json:"previousCost"`
+	CostChange    float64 `json:"costChange"`
+	PercentChange float64 `json:"percentChange"`
+}
+
+// GroupCost is the month to date, projected and last month cost of a custom group
+type GroupCost struct {
+	Name          string  `json:"name"`
+	MTDCost       float64 `json:"mtdCost"`
+	ProjectedCost float64 `json:"projectedCost"`
+	LastMonthCost float64 `json:"lastMonthCost"`
+}
+
+// Report is the cost summary of a period, TopNamespaces are the most expensive namespaces in it, Movers the
+// namespaces whose cost changed most compared to the period before and Groups the custom groups by projected cost
+type Report struct {
+	Schedule      string          `json:"schedule"`
+	Start         time.Time       `json:"start"`
+	End           time.Time       `json:"end"`
+	TotalCost     float64         `json:"totalCost"`
+	PreviousCost  float64         `json:"previousCost"`
+	CostChange    float64         `json:"costChange"`
+	PercentChange float64         `json:"percentChange"`
+	TopNamespaces []NamespaceCost `json:"topNamespaces"`
+	Movers        []NamespaceCost `json:"movers"`
+	Groups        []GroupCost     `json:"groups"`
+}
+
+var (
+	config   *Config
+	channels []notification.Channel
+
+	retrieveClusterMetrics = query.RetrieveClusterMetricsInRange
+	retrieveGroups         = query.RetrieveGroupsData
+)
+
+// Configure loads the report config from the given JSON or YAML file and creates the channels of its destinations,
+// reports stay disabled if path is empty
+func Configure(path string, timeout time.Duration) error {
+	if path == "" {
+		return nil
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	jsonData, err := yaml.ToJSON(data)
+	if err != nil {
+		return err
+	}
+	reportConfig := Config{}
+	if err = json.Unmarshal(jsonData, &reportConfig); err != nil {
+		return err
+	}
+	reportChannels, err := buildChannels(&reportConfig, timeout)
+	if err != nil {
+		return err
+	}
+	config, channels = &reportConfig, reportChannels
+	log.Infof("%s cost reports configured with %d destinations", config.Schedule, len(channels))
+	return nil
+}
+
+// buildChannels validates the config, sets its defaults and returns the channels of its destinations
+func buildChannels(reportConfig *Config, timeout time.Duration) ([]notification.Channel, error) {
+	if reportConfig.Schedule == "" {
+		reportConfig.Schedule = Weekly
+	}
+	if reportConfig.Schedule != Weekly && reportConfig.Schedule != Monthly {
+		return nil, fmt.Errorf("unknown report schedule: %s, expected %s or %s", reportConfig.Schedule, Weekly, Monthly)
+	}
+	if reportConfig.Top <= 0 {
+		reportConfig.Top = DefaultTop
+	}
+	reportChannels := []notification.Channel{}
+	if smtpConfig := reportConfig.SMTP; smtpConfig != nil {
+		channel, err := notification.NewEmailChannel(smtpConfig.Address, smtpConfig.Username, smtpConfig.Password, smtpConfig.From, smtpConfig.To)
+		if err != nil {
+			return nil, err
+		}
+		reportChannels = append(reportChannels, channel)
+	}
+	if reportConfig.Slack != nil && reportConfig.Slack.WebhookURL != "" {
+		reportChannels = append(reportChannels, notification.NewSlackChannel(reportConfig.Slack.WebhookURL, timeout))
+	}
+	if len(reportChannels) == 0 {
+		return nil, fmt.Errorf("cost reports need smtp or slack destination")
+	}
+	return reportChannels, nil
+}
+
+// IsConfigured returns true if scheduled reports are enabled
+func IsConfigured() bool {
+	return config != nil
+}
+
+// GetCronSpec returns the cron spec of the schedule, reports are sent at midnight on sundays or on the first of
+// each month
+func GetCronSpec() string {
+	if config != nil && config.Schedule == Monthly {
+		return "@monthly"
+	}
+	return "@weekly"
+}
+
+// Deliver generates the report of the last period and sends it to each destination
+func Deliver() {
+	if config == nil {
+		return
+	}
+	now := time.Now()
+	report := Generate(dgraph.WithOwnCluster(context.Background()), config.Schedule, config.Top, now)
+	n := notification.Notification{
+		Title:   getTitle(report),
+		Summary: Render(report),
+		Source:  Source,
+		Time:    now,
+	}
+	for _, channel := range channels {
+		if err := channel.Send(n); err != nil {
+			log.Errorf("unable to send cost report to channel: %s, err: %v", channel.Name(), err)
+		}
+	}
+}
+
+// GetPeriod returns the last full week or month before now and the period before it
+func GetPeriod(schedule string, now time.Time) (query.TimeRange, query.TimeRange) {
+	end := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	start, previousStart := end.AddDate(0, 0, -7), end.AddDate(0, 0, -14)
+	if schedule == Monthly {
+		end = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+		start, previousStart = end.AddDate(0, -1, 0), end.AddDate(0, -2, 0)
+	}
+	return query.TimeRange{Start: start.Format(time.RFC3339), End: end.Format(time.RFC3339)},
+		query.TimeRange{Start: previousStart.Format(time.RFC3339), End: start.Format(time.RFC3339)}
+}
+
+// Generate computes the report of the last period of the schedule with the top namespaces, movers and groups
+func Generate(ctx context.Context, schedule string, top int, now time.Time) Report {
+	period, previous := GetPeriod(schedule, now)
+	report := Report{Schedule: schedule}
+	report.Start, _ = time.Parse(time.RFC3339, period.Start)
+	report.End, _ = time.Parse(time.RFC3339, period.End)
+
+	costs := getNamespaceCosts(retrieveClusterMetrics(ctx, query.Logical, query.All, period, query.Include))
+	previousCosts := getNamespaceCosts(retrieveClusterMetrics(ctx, query.Logical, query.All, previous, query.Include))
+	namespaces := make(map[string]*NamespaceCost)
+	for name, cost := range costs {
+		namespaces[name] = &NamespaceCost{Name: name, Cost: cost}
+		report.TotalCost += cost
+	}
+	for name, cost := range previousCosts {
+		if _, isPresent := namespaces[name]; !isPresent {
+			namespaces[name] = &NamespaceCost{Name: name}
+		}
+		namespaces[name].PreviousCost = cost
+		report.PreviousCost += cost
+	}
+	all := []NamespaceCost{}
+	for _, namespace := range namespaces {
+		namespace.CostChange = namespace.Cost - namespace.PreviousCost
+		namespace.PercentChange = getPercentChange(namespace.PreviousCost, namespace.Cost)
+		all = append(all, *namespace)
+	}
+	report.CostChange = report.TotalCost - report.PreviousCost
+	report.PercentChange = getPercentChange(report.PreviousCost, report.TotalCost)
+
+	sort.Slice(all, func(i, j int) bool {
+		if all[i].Cost != all[j].Cost {
+			return all[i].Cost > all[j].Cost
+		}
+		return all[i].Name < all[j].Name
+	})
+	report.TopNamespaces = limit(all, top, func(namespace NamespaceCost) bool { return namespace.Cost > 0 })
+	sort.SliceStable(all, func(i, j int) bool {
+		return math.Abs(all[i].CostChange) > math.Abs(all[j].CostChange)
+	})
+	report.Movers = limit(all, top, func(namespace NamespaceCost) bool { return namespace.CostChange != 0 })
+	report.Groups = getGroupCosts(ctx, top)
+	return report
+}
+
+// getNamespaceCosts returns total cost of each namespace(without type prefix) in cluster metrics
+func getNamespaceCosts(metrics query.JSONDataWrapper) map[string]float64 {
+	costs := make(map[string]float64)
+	for _, namespace := range metrics.Data.Children {
+		costs[strings.TrimPrefix(namespace.Name, query.NamespaceType+"-")] = namespace.CPUCost + namespace.MemoryCost + namespace.StorageCost
+	}
+	return costs
+}
+
+// getGroupCosts returns custom groups with the highest projected cost
+func getGroupCosts(ctx context.Context, top int) []GroupCost {
+	groups, err := retrieveGroups(ctx)
+	if err != nil {
+		log.Errorf("unable to retrieve groups for cost report, err: %v", err)
+		return []GroupCost{}
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		if groups[i].ProjectedCost != groups[j].ProjectedCost {
+			return groups[i].ProjectedCost > groups[j].ProjectedCost
+		}
+		return groups[i].Name < groups[j].Name
+	})
+	groupCosts := []GroupCost{}
+	for _, group := range groups {
+		if len(groupCosts) == top {
+			break
+		}
+		groupCosts = append(groupCosts, toGroupCost(group))
+	}
+	return groupCosts
+}
+
+func toGroupCost(group models.Group) GroupCost {
+	return GroupCost{Name: group.Name, MTDCost: group.MtdCost, ProjectedCost: group.ProjectedCost, LastMonthCost: group.LastMonthCost}
+}
+
+func limit(namespaces []NamespaceCost, top int, isListed func(NamespaceCost) bool) []NamespaceCost {
+	listed := []NamespaceCost{}
+	for _, namespace := range namespaces {
+		if len(listed) == top {
+			break
+		}
+		if isListed(namespace) {
+			listed = append(listed, namespace)
+		}
+	}
+	return listed
+}
+
+func getPercentChange(before, after float64) float64 {
+	if before == 0 {
+		return 0
+	}
+	return (after - before) / before * 100
+}
+
+func getTitle(report Report) string {
+	period := "Weekly"
+	if report.Schedule == Monthly {
+		period = "Monthly"
+	}
+	return fmt.Sprintf("%s cost report %s - %s", period, report.Start.Format("2006-01-02"), report.End.AddDate(0, 0, -1).Format("2006-01-02"))
+}
+
+// Render returns the report as text with mrkdwn emphasis, readable in emails and Slack
+func Render(report Report) string {
+	text := &bytes.Buffer{}
+	fmt.Fprintf(text, "*Total spend:* %.2f (%s compared to %.2f in the period before)\n", report.TotalCost, formatChange(report.CostChange, report.PercentChange), report.PreviousCost)
+
+	text.WriteString("\n*Top namespaces*\n")
+	for _, namespace := range report.TopNamespaces {
+		fmt.Fprintf(text, "• %s: %.2f\n", namespace.Name, namespace.Cost)
+	}
+	if len(report.TopNamespaces) == 0 {
+		text.WriteString("no costs in the period\n")
+	}
+
+	text.WriteString("\n*Biggest movers*\n")
+	for _, namespace := range report.Movers {
+		fmt.Fprintf(text, "• %s: %.2f → %.2f (%s)\n", namespace.Name, namespace.PreviousCost, namespace.Cost, formatChange(namespace.CostChange, namespace.PercentChange))
+	}
+	if len(report.Movers) == 0 {
+		text.WriteString("no changes in cost\n")
+	}
+
+	if len(report.Groups) > 0 {
+		text.WriteString("\n*Groups*\n")
+		for _, group := range report.Groups {
+			fmt.Fprintf(text, "• %s: %.2f month to date, %.2f projected, %.2f last month\n", group.Name, group.MTDCost, group.ProjectedCost, group.LastMonthCost)
+		}
+	}
+	return strings.TrimSuffix(text.String(), "\n")
+}
+
+func formatChange(change, percentChange float64) string {
+	if percentChange == 0 {
+		return fmt.Sprintf("%+.2f", change)
+	}
+	return fmt.Sprintf("%+.2f, %+.1f%%", change, percentChange)
+}
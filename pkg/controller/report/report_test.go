@@ -0,0 +1,122 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package report
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vmware/purser/pkg/controller/dgraph/models"
+	"github.com/vmware/purser/pkg/controller/dgraph/models/query"
+	"github.com/vmware/purser/test/utils"
+)
+
+func mockMetrics(periods map[string][]query.Children) func() {
+	oldRetrieveClusterMetrics, oldRetrieveGroups := retrieveClusterMetrics, retrieveGroups
+	retrieveClusterMetrics = func(ctx context.Context, view, os string, timeRange query.TimeRange, deleted string) query.JSONDataWrapper {
+		return query.JSONDataWrapper{Data: query.ParentWrapper{Children: periods[timeRange.Start]}}
+	}
+	retrieveGroups = func(ctx context.Context) ([]models.Group, error) {
+		return []models.Group{{Name: "small", ProjectedCost: 10}, {Name: "big", MtdCost: 40, ProjectedCost: 90, LastMonthCost: 80}}, nil
+	}
+	return func() {
+		retrieveClusterMetrics, retrieveGroups = oldRetrieveClusterMetrics, oldRetrieveGroups
+	}
+}
+
+// TestGetPeriod ...
+func TestGetPeriod(t *testing.T) {
+	now := time.Date(2018, 3, 14, 10, 30, 0, 0, time.UTC)
+	period, previous := GetPeriod(Weekly, now)
+	utils.Equals(t, query.TimeRange{Start: "2018-03-07T00:00:00Z", End: "2018-03-14T00:00:00Z"}, period)
+	utils.Equals(t, query.TimeRange{Start: "2018-02-28T00:00:00Z", End: "2018-03-07T00:00:00Z"}, previous)
+
+	period, previous = GetPeriod(Monthly, now)
+	utils.Equals(t, query.TimeRange{Start: "2018-02-01T00:00:00Z", End: "2018-03-01T00:00:00Z"}, period)
+	utils.Equals(t, query.TimeRange{Start: "2018-01-01T00:00:00Z", End: "2018-02-01T00:00:00Z"}, previous)
+}
+
+// TestGenerate ...
+func TestGenerate(t *testing.T) {
+	defer mockMetrics(map[string][]query.Children{
+		"2018-03-07T00:00:00Z": {
+			{Name: "namespace-default", CPUCost: 60, MemoryCost: 30, StorageCost: 10},
+			{Name: "namespace-dev", CPUCost: 20},
+			{Name: "namespace-new", MemoryCost: 15},
+		},
+		"2018-02-28T00:00:00Z": {
+			{Name: "namespace-default", CPUCost: 50, MemoryCost: 30},
+			{Name: "namespace-dev", CPUCost: 20},
+			{Name: "namespace-old", CPUCost: 30},
+		},
+	})()
+
+	report := Generate(context.Background(), Weekly, 2, time.Date(2018, 3, 14, 10, 30, 0, 0, time.UTC))
+	utils.Equals(t, time.Date(2018, 3, 7, 0, 0, 0, 0, time.UTC), report.Start)
+	utils.Equals(t, 135.0, report.TotalCost)
+	utils.Equals(t, 130.0, report.PreviousCost)
+	utils.Equals(t, 5.0, report.CostChange)
+	utils.Equals(t, []NamespaceCost{
+		{Name: "default", Cost: 100, PreviousCost: 80, CostChange: 20, PercentChange: 25},
+		{Name: "dev", Cost: 20, PreviousCost: 20},
+	}, report.TopNamespaces)
+	utils.Equals(t, []NamespaceCost{
+		{Name: "old", PreviousCost: 30, CostChange: -30, PercentChange: -100},
+		{Name: "default", Cost: 100, PreviousCost: 80, CostChange: 20, PercentChange: 25},
+	}, report.Movers)
+	utils.Equals(t, []GroupCost{{Name: "big", MTDCost: 40, ProjectedCost: 90, LastMonthCost: 80}, {Name: "small", ProjectedCost: 10}}, report.Groups)
+}
+
+// TestRender ...
+func TestRender(t *testing.T) {
+	report := Report{
+		Schedule:      Monthly,
+		Start:         time.Date(2018, 2, 1, 0, 0, 0, 0, time.UTC),
+		End:           time.Date(2018, 3, 1, 0, 0, 0, 0, time.UTC),
+		TotalCost:     150,
+		PreviousCost:  100,
+		CostChange:    50,
+		PercentChange: 50,
+		TopNamespaces: []NamespaceCost{{Name: "default", Cost: 150}},
+	}
+	text := Render(report)
+	utils.Equals(t, "Monthly cost report 2018-02-01 - 2018-02-28", getTitle(report))
+	utils.Assert(t, strings.HasPrefix(text, "*Total spend:* 150.00 (+50.00, +50.0% compared to 100.00 in the period before)"), "unexpected total: %s", text)
+	utils.Assert(t, strings.Contains(text, "• default: 150.00\n"), "top namespace missing: %s", text)
+	utils.Assert(t, strings.Contains(text, "no changes in cost"), "movers missing: %s", text)
+	utils.Assert(t, !strings.Contains(text, "*Groups*"), "empty groups rendered: %s", text)
+}
+
+// TestBuildChannels ...
+func TestBuildChannels(t *testing.T) {
+	reportConfig := &Config{Slack: &SlackConfig{WebhookURL: "http://localhost/hook"}}
+	channels, err := buildChannels(reportConfig, time.Second)
+	utils.Ok(t, err)
+	utils.Equals(t, 1, len(channels))
+	utils.Equals(t, Weekly, reportConfig.Schedule)
+	utils.Equals(t, DefaultTop, reportConfig.Top)
+
+	_, err = buildChannels(&Config{Schedule: "daily", Slack: reportConfig.Slack}, time.Second)
+	utils.Equals(t, fmt.Errorf("unknown report schedule: daily, expected weekly or monthly"), err)
+
+	_, err = buildChannels(&Config{Schedule: Monthly}, time.Second)
+	utils.Assert(t, err != nil, "expected error without destinations")
+}
@@ -0,0 +1,219 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package status
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// StaleAfter is how long an event can wait to be persisted before data of its kind is reported stale
+const StaleAfter = time.Minute
+
+// Source is a watch of a resource kind, implemented by the controller of each kind
+type Source interface {
+	HasSynced() bool
+	QueueLength() int
+	ObjectsInCluster() int
+}
+
+// Buffer holds events waiting to be persisted, implemented by the ring buffer
+type Buffer interface {
+	Len() uint32
+	Capacity() uint32
+}
+
+// KindStatus is the sync health of a resource kind(Pod, Node, ...)
+type KindStatus struct {
+	Kind              string `json:"kind"`
+	Synced            bool   `json:"synced"`
+	Stale             bool   `json:"stale"`
+	LastEventTime     string `json:"lastEventTime,omitempty"`
+	LastProcessedTime string `json:"lastProcessedTime,omitempty"`
+	ObjectsInCluster  int    `json:"objectsInCluster"`
+	ObjectsTracked    int    `json:"objectsTracked"`
+	QueueLength       int    `json:"queueLength"`
+	ProcessedEvents   int    `json:"processedEvents"`
+	DroppedEvents     int    `json:"droppedEvents"`
+	WriteErrors       int    `json:"writeErrors"`
+	LastError         string `json:"lastError,omitempty"`
+	LastErrorTime     string `json:"lastErrorTime,omitempty"`
+}
+
+// Status is the sync health of the controller, Current is false if data of any kind is stale or mutations are
+// waiting in the mutation log to be replayed
+type Status struct {
+	Current         bool         `json:"current"`
+	Error           string       `json:"error,omitempty"`
+	BufferedEvents  int          `json:"bufferedEvents"`
+	BufferCapacity  int          `json:"bufferCapacity"`
+	QueuedMutations int          `json:"queuedMutations"`
+	Kinds           []KindStatus `json:"kinds"`
+}
+
+type kindStats struct {
+	source          Source
+	lastEvent       time.Time
+	lastProcessed   time.Time
+	processedEvents int
+	droppedEvents   int
+	writeErrors     int
+	lastError       string
+	lastErrorTime   time.Time
+}
+
+var (
+	mu     sync.Mutex
+	kinds  = make(map[string]*kindStats)
+	buffer Buffer
+	// queuedMutations returns the number of mutations waiting in the mutation log of dgraph
+	queuedMutations func() int
+)
+
+// Register adds the watch of a resource kind to the status
+func Register(kind string, source Source) {
+	mu.Lock()
+	defer mu.Unlock()
+	getKindStats(kind).source = source
+}
+
+// SetBuffer sets the buffer whose pending events are reported in status
+func SetBuffer(b Buffer) {
+	mu.Lock()
+	defer mu.Unlock()
+	buffer = b
+}
+
+// SetMutationQueue sets the function returning the number of mutations queued while dgraph is unavailable which is
+// reported in status
+func SetMutationQueue(queued func() int) {
+	mu.Lock()
+	defer mu.Unlock()
+	queuedMutations = queued
+}
+
+// RecordEvent records that an event of kind was received from the cluster at captureTime
+func RecordEvent(kind string, captureTime time.Time) {
+	mu.Lock()
+	defer mu.Unlock()
+	stats := getKindStats(kind)
+	if captureTime.After(stats.lastEvent) {
+		stats.lastEvent = captureTime
+	}
+}
+
+// RecordDropped records that an event of kind was dropped because the buffer was full
+func RecordDropped(kind string) {
+	mu.Lock()
+	defer mu.Unlock()
+	getKindStats(kind).droppedEvents++
+}
+
+// RecordProcessed records that an event of kind was persisted in Dgraph at processedTime, err is the write error if any
+func RecordProcessed(kind string, processedTime time.Time, err error) {
+	mu.Lock()
+	defer mu.Unlock()
+	stats := getKindStats(kind)
+	stats.lastProcessed = processedTime
+	stats.processedEvents++
+	if err != nil {
+		stats.writeErrors++
+		stats.lastError = err.Error()
+		stats.lastErrorTime = processedTime
+	}
+}
+
+// GetStatus returns sync health of all kinds sorted by kind at now. tracked is the number of
+// objects of each kind stored in Dgraph, kinds missing from it are reported with no tracked objects.
+func GetStatus(now time.Time, tracked map[string]int) Status {
+	mu.Lock()
+	defer mu.Unlock()
+
+	status := Status{Current: true, Kinds: []KindStatus{}}
+	if buffer != nil {
+		status.BufferedEvents = int(buffer.Len())
+		status.BufferCapacity = int(buffer.Capacity())
+	}
+	if queuedMutations != nil {
+		status.QueuedMutations = queuedMutations()
+		if status.QueuedMutations > 0 {
+			status.Current = false
+		}
+	}
+	for kind, stats := range kinds {
+		kindStatus := KindStatus{
+			Kind:              kind,
+			LastEventTime:     formatTime(stats.lastEvent),
+			LastProcessedTime: formatTime(stats.lastProcessed),
+			ObjectsTracked:    tracked[kind],
+			ProcessedEvents:   stats.processedEvents,
+			DroppedEvents:     stats.droppedEvents,
+			WriteErrors:       stats.writeErrors,
+			LastError:         stats.lastError,
+			LastErrorTime:     formatTime(stats.lastErrorTime),
+		}
+		if stats.source != nil {
+			kindStatus.Synced = stats.source.HasSynced()
+			kindStatus.QueueLength = stats.source.QueueLength()
+			kindStatus.ObjectsInCluster = stats.source.ObjectsInCluster()
+		}
+		kindStatus.Stale = isStale(kindStatus.Synced, stats, now)
+		if kindStatus.Stale {
+			status.Current = false
+		}
+		status.Kinds = append(status.Kinds, kindStatus)
+	}
+	sort.Slice(status.Kinds, func(i, j int) bool {
+		return status.Kinds[i].Kind < status.Kinds[j].Kind
+	})
+	return status
+}
+
+// isStale returns true if the watch of kind hasn't synced or the latest event is waiting
+// to be persisted for longer than StaleAfter
+func isStale(synced bool, stats *kindStats, now time.Time) bool {
+	if !synced {
+		return true
+	}
+	return stats.lastEvent.After(stats.lastProcessed) && now.Sub(stats.lastEvent) > StaleAfter
+}
+
+func reset() {
+	mu.Lock()
+	defer mu.Unlock()
+	kinds = make(map[string]*kindStats)
+	buffer = nil
+	queuedMutations = nil
+}
+
+func getKindStats(kind string) *kindStats {
+	stats, isPresent := kinds[kind]
+	if !isPresent {
+		stats = &kindStats{}
+		kinds[kind] = stats
+	}
+	return stats
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
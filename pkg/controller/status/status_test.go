@@ -0,0 +1,127 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package status
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/vmware/purser/test/utils"
+)
+
+type testSource struct {
+	synced  bool
+	queue   int
+	objects int
+}
+
+func (s testSource) HasSynced() bool       { return s.synced }
+func (s testSource) QueueLength() int      { return s.queue }
+func (s testSource) ObjectsInCluster() int { return s.objects }
+
+type testBuffer struct{}
+
+func (testBuffer) Len() uint32      { return 3 }
+func (testBuffer) Capacity() uint32 { return 5000 }
+
+func TestGetStatus(t *testing.T) {
+	reset()
+	defer reset()
+	now := time.Date(2018, 10, 15, 10, 0, 0, 0, time.UTC)
+
+	Register("Pod", testSource{synced: true, queue: 2, objects: 10})
+	Register("Node", testSource{synced: true, objects: 3})
+	Register("Service", testSource{synced: false})
+	SetBuffer(testBuffer{})
+
+	RecordEvent("Pod", now.Add(-5*time.Minute))
+	RecordProcessed("Pod", now.Add(-4*time.Minute), nil)
+	RecordEvent("Pod", now.Add(-2*time.Minute))
+	RecordDropped("Pod")
+	RecordEvent("Node", now.Add(-time.Hour))
+	RecordEvent("Node", now.Add(-2*time.Hour))
+	RecordProcessed("Node", now.Add(-59*time.Minute), errors.New("dgraph unavailable"))
+
+	status := GetStatus(now, map[string]int{"Pod": 9, "Node": 3})
+	utils.Assert(t, !status.Current, "status with stale kinds is current")
+	utils.Equals(t, 3, status.BufferedEvents)
+	utils.Equals(t, 5000, status.BufferCapacity)
+	utils.Equals(t, []KindStatus{
+		{
+			Kind:              "Node",
+			Synced:            true,
+			LastEventTime:     "2018-10-15T09:00:00Z",
+			LastProcessedTime: "2018-10-15T09:01:00Z",
+			ObjectsInCluster:  3,
+			ObjectsTracked:    3,
+			ProcessedEvents:   1,
+			WriteErrors:       1,
+			LastError:         "dgraph unavailable",
+			LastErrorTime:     "2018-10-15T09:01:00Z",
+		},
+		{
+			Kind:              "Pod",
+			Synced:            true,
+			Stale:             true,
+			LastEventTime:     "2018-10-15T09:58:00Z",
+			LastProcessedTime: "2018-10-15T09:56:00Z",
+			ObjectsInCluster:  10,
+			ObjectsTracked:    9,
+			QueueLength:       2,
+			ProcessedEvents:   1,
+			DroppedEvents:     1,
+		},
+		{
+			Kind:  "Service",
+			Stale: true,
+		},
+	}, status.Kinds)
+}
+
+func TestGetStatusCurrent(t *testing.T) {
+	reset()
+	defer reset()
+	now := time.Date(2018, 10, 15, 10, 0, 0, 0, time.UTC)
+
+	status := GetStatus(now, nil)
+	utils.Assert(t, status.Current, "status without kinds is not current")
+	utils.Equals(t, []KindStatus{}, status.Kinds)
+
+	Register("Pod", testSource{synced: true})
+	RecordEvent("Pod", now.Add(-30*time.Second))
+	status = GetStatus(now, nil)
+	utils.Assert(t, status.Current, "event waiting less than StaleAfter is stale")
+	utils.Equals(t, 0, status.Kinds[0].ObjectsTracked)
+}
+
+func TestGetStatusWithQueuedMutations(t *testing.T) {
+	reset()
+	defer reset()
+	now := time.Date(2018, 10, 15, 10, 0, 0, 0, time.UTC)
+	queued := 2
+	SetMutationQueue(func() int { return queued })
+
+	status := GetStatus(now, nil)
+	utils.Equals(t, 2, status.QueuedMutations)
+	utils.Assert(t, !status.Current, "status with queued mutations is current")
+
+	queued = 0
+	status = GetStatus(now, nil)
+	utils.Assert(t, status.Current, "status without queued mutations is not current")
+}
@@ -18,7 +18,10 @@
 package controller
 
 import (
+	alertrule_v1 "github.com/vmware/purser/pkg/client/clientset/typed/alertrule/v1"
+	costbudget_v1 "github.com/vmware/purser/pkg/client/clientset/typed/costbudget/v1"
 	groups_v1 "github.com/vmware/purser/pkg/client/clientset/typed/groups/v1"
+	openshift_v1 "github.com/vmware/purser/pkg/client/clientset/typed/openshift/v1"
 	subscriber_v1 "github.com/vmware/purser/pkg/client/clientset/typed/subscriber/v1"
 	"github.com/vmware/purser/pkg/controller/buffering"
 	"k8s.io/client-go/kubernetes"
@@ -47,6 +50,9 @@ type Resource struct {
 	Namespace             bool `json:"namespace"`
 	Group                 bool `json:"groups.vmware.purser.com"`
 	Subscriber            bool `json:"subscribers.vmware.purser.com"`
+	DeploymentConfig      bool `json:"deploymentconfigs.apps.openshift.io"`
+	Route                 bool `json:"routes.route.openshift.io"`
+	ImageStream           bool `json:"imagestreams.image.openshift.io"`
 }
 
 // Config contains config objects
@@ -56,5 +62,8 @@ type Config struct {
 	RingBuffer       *buffering.RingBuffer
 	Groupcrdclient   *groups_v1.GroupClient
 	Subscriberclient *subscriber_v1.SubscriberClient
+	Alertruleclient  *alertrule_v1.AlertRuleClient
+	Costbudgetclient *costbudget_v1.CostBudgetClient
+	OpenShiftclient  *openshift_v1.OpenShiftClient
 	Kubeclient       *kubernetes.Clientset
 }
@@ -0,0 +1,108 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package usage
+
+import (
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/vmware/purser/pkg/controller/dgraph/models"
+	"github.com/vmware/purser/pkg/controller/discovery/telemetry"
+	"github.com/vmware/purser/pkg/controller/utils"
+)
+
+// Queries returning usage of each pod over the last hour
+const (
+	// cpuUsageQuery returns the average cpu cores used
+	cpuUsageQuery = `sum(rate(container_cpu_usage_seconds_total{container!="", container!="POD", pod!=""}[` + telemetry.WindowRange + `])) by (namespace, pod)`
+	// memoryUsageQuery returns the average working set memory in bytes
+	memoryUsageQuery = `sum(avg_over_time(container_memory_working_set_bytes{container!="", container!="POD", pod!=""}[` + telemetry.WindowRange + `])) by (namespace, pod)`
+)
+
+var collector *Collector
+
+// Collector stores cpu and memory used by pods reported by cadvisor metrics
+type Collector struct {
+	prometheus *telemetry.PrometheusClient
+}
+
+// Usage is the cpu(cores) and memory(GB) used by a pod
+type Usage struct {
+	CPU    float64
+	Memory float64
+}
+
+// Configure enables pod usage collection from the given Prometheus. It does nothing if url is empty.
+func Configure(prometheusURL string, timeout time.Duration) {
+	if prometheusURL == "" {
+		return
+	}
+	collector = &Collector{
+		prometheus: telemetry.NewPrometheusClient(prometheusURL, timeout),
+	}
+	log.Infof("pod usage collection configured with prometheus: %s", prometheusURL)
+}
+
+// IsConfigured returns true if pod usage collection is enabled
+func IsConfigured() bool {
+	return collector != nil
+}
+
+// CollectAndStorePodUsage stores cpu and memory used by pods in the last hour in Dgraph
+func CollectAndStorePodUsage() {
+	if collector == nil {
+		return
+	}
+	cpu, err := collector.prometheus.Query(cpuUsageQuery)
+	if err != nil {
+		log.Errorf("failed to retrieve pod cpu usage: %v", err)
+		return
+	}
+	memory, err := collector.prometheus.Query(memoryUsageQuery)
+	if err != nil {
+		log.Errorf("failed to retrieve pod memory usage, only cpu usage is stored: %v", err)
+	}
+
+	usages := toUsages(cpu, memory)
+	for pod, usage := range usages {
+		if err := models.UpdatePodUsage(pod, usage.CPU, usage.Memory); err != nil {
+			log.Debugf("unable to store usage of pod: %s, err: %v", pod, err)
+		}
+	}
+	log.Infof("stored usage of (%d) pods", len(usages))
+}
+
+// toUsages joins cpu and memory samples by pod(<namespace>:<name>) and converts bytes to GB.
+// Memory of pods without a memory sample is 0.
+func toUsages(cpu, memory []telemetry.Sample) map[string]Usage {
+	usages := make(map[string]Usage)
+	for _, sample := range cpu {
+		if sample.Metric["pod"] == "" {
+			continue
+		}
+		usages[telemetry.SampleKey(sample, "namespace", "pod")] = Usage{CPU: sample.Value}
+	}
+	for _, sample := range memory {
+		key := telemetry.SampleKey(sample, "namespace", "pod")
+		if usage, isPresent := usages[key]; isPresent {
+			usage.Memory = utils.BytesToGB(int64(sample.Value))
+			usages[key] = usage
+		}
+	}
+	return usages
+}
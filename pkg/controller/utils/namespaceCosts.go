@@ -0,0 +1,57 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package utils
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// OtherNamespaces is the namespace of a cost which applies to namespaces without a cost of their own
+const OtherNamespaces = "*"
+
+// ParseNamespaceCosts parses a comma separated list of <namespace>=<monthly cost>(ex: dev=100,*=1000)
+func ParseNamespaceCosts(list string) (map[string]float64, error) {
+	costs := map[string]float64{}
+	for _, item := range strings.Split(list, ",") {
+		if item = strings.TrimSpace(item); item == "" {
+			continue
+		}
+		parts := strings.SplitN(item, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, fmt.Errorf("invalid namespace cost %s, expected <namespace>=<monthly cost>", item)
+		}
+		cost, err := strconv.ParseFloat(parts[1], 64)
+		if err != nil || cost < 0 {
+			return nil, fmt.Errorf("invalid monthly cost of namespace cost %s", item)
+		}
+		costs[parts[0]] = cost
+	}
+	return costs, nil
+}
+
+// GetNamespaceCost returns the cost of the namespace, the cost of other namespaces if it has none and whether
+// there is one
+func GetNamespaceCost(costs map[string]float64, namespace string) (float64, bool) {
+	if cost, isPresent := costs[namespace]; isPresent {
+		return cost, true
+	}
+	cost, isPresent := costs[OtherNamespaces]
+	return cost, isPresent
+}
@@ -0,0 +1,45 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package utils
+
+import (
+	"testing"
+
+	"github.com/vmware/purser/test/utils"
+)
+
+func TestParseNamespaceCosts(t *testing.T) {
+	costs, err := ParseNamespaceCosts("dev=100, *=500.5,")
+	utils.Ok(t, err)
+	utils.Equals(t, map[string]float64{"dev": 100, "*": 500.5}, costs)
+
+	for _, invalid := range []string{"dev", "=100", "dev=-1", "dev=abc"} {
+		_, err = ParseNamespaceCosts(invalid)
+		utils.Assert(t, err != nil, "invalid namespace cost %s is parsed", invalid)
+	}
+}
+
+func TestGetNamespaceCost(t *testing.T) {
+	costs := map[string]float64{"dev": 100, OtherNamespaces: 500}
+	cost, isPresent := GetNamespaceCost(costs, "dev")
+	utils.Assert(t, isPresent && cost == 100, "cost of dev is %v", cost)
+	cost, isPresent = GetNamespaceCost(costs, "prod")
+	utils.Assert(t, isPresent && cost == 500, "cost of prod is %v", cost)
+	_, isPresent = GetNamespaceCost(map[string]float64{}, "prod")
+	utils.Assert(t, !isPresent, "prod has a cost without costs")
+}
@@ -0,0 +1,109 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package volume
+
+import (
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/vmware/purser/pkg/controller/dgraph/models"
+	"github.com/vmware/purser/pkg/controller/discovery/telemetry"
+	"github.com/vmware/purser/pkg/controller/utils"
+)
+
+// Queries returning kubelet volume stats of each pvc over the last hour
+const (
+	// usedBytesQuery returns the highest space used in bytes
+	usedBytesQuery = `max(max_over_time(kubelet_volume_stats_used_bytes[` + telemetry.WindowRange + `])) by (namespace, persistentvolumeclaim)`
+	// capacityBytesQuery returns the capacity in bytes of the filesystem of the volume
+	capacityBytesQuery = `max(max_over_time(kubelet_volume_stats_capacity_bytes[` + telemetry.WindowRange + `])) by (namespace, persistentvolumeclaim)`
+)
+
+var collector *Collector
+
+// Collector stores space used by volumes of pvcs reported by kubelet volume stats
+type Collector struct {
+	prometheus *telemetry.PrometheusClient
+}
+
+// Usage is the space used and capacity of the volume of a pvc in GB
+type Usage struct {
+	Used     float64
+	Capacity float64
+}
+
+// Configure enables volume usage collection from the given Prometheus. It does nothing if url is empty.
+func Configure(prometheusURL string, timeout time.Duration) {
+	if prometheusURL == "" {
+		return
+	}
+	collector = &Collector{
+		prometheus: telemetry.NewPrometheusClient(prometheusURL, timeout),
+	}
+	log.Infof("volume usage collection configured with prometheus: %s", prometheusURL)
+}
+
+// IsConfigured returns true if volume usage collection is enabled
+func IsConfigured() bool {
+	return collector != nil
+}
+
+// CollectAndStoreVolumeUsage stores space used by volumes of pvcs in the last hour in Dgraph
+func CollectAndStoreVolumeUsage() {
+	if collector == nil {
+		return
+	}
+	used, err := collector.prometheus.Query(usedBytesQuery)
+	if err != nil {
+		log.Errorf("failed to retrieve volume usage: %v", err)
+		return
+	}
+	capacity, err := collector.prometheus.Query(capacityBytesQuery)
+	if err != nil {
+		log.Errorf("failed to retrieve volume capacity, only usage is stored: %v", err)
+	}
+
+	now := time.Now()
+	usages := toUsages(used, capacity)
+	for pvc, usage := range usages {
+		if err := models.UpdatePersistentVolumeClaimUsage(pvc, usage.Used, usage.Capacity, now); err != nil {
+			log.Debugf("unable to store usage of pvc: %s, err: %v", pvc, err)
+		}
+	}
+	log.Infof("stored usage of (%d) volumes", len(usages))
+}
+
+// toUsages joins used and capacity samples by pvc(<namespace>:<name>) and converts bytes to GB.
+// Capacity of pvcs without a capacity sample is 0.
+func toUsages(used, capacity []telemetry.Sample) map[string]Usage {
+	usages := make(map[string]Usage)
+	for _, sample := range used {
+		if sample.Metric["persistentvolumeclaim"] == "" {
+			continue
+		}
+		usages[telemetry.SampleKey(sample, "namespace", "persistentvolumeclaim")] = Usage{Used: utils.BytesToGB(int64(sample.Value))}
+	}
+	for _, sample := range capacity {
+		key := telemetry.SampleKey(sample, "namespace", "persistentvolumeclaim")
+		if usage, isPresent := usages[key]; isPresent {
+			usage.Capacity = utils.BytesToGB(int64(sample.Value))
+			usages[key] = usage
+		}
+	}
+	return usages
+}
@@ -0,0 +1,42 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package volume
+
+import (
+	"testing"
+
+	"github.com/vmware/purser/pkg/controller/discovery/telemetry"
+	"github.com/vmware/purser/test/utils"
+)
+
+func TestToUsages(t *testing.T) {
+	used := []telemetry.Sample{
+		{Metric: map[string]string{"namespace": "default", "persistentvolumeclaim": "data"}, Value: 2 * 1024 * 1024 * 1024},
+		{Metric: map[string]string{"namespace": "default", "persistentvolumeclaim": "logs"}, Value: 512 * 1024 * 1024},
+		{Metric: map[string]string{"namespace": "default"}, Value: 1024},
+	}
+	capacity := []telemetry.Sample{
+		{Metric: map[string]string{"namespace": "default", "persistentvolumeclaim": "data"}, Value: 10 * 1024 * 1024 * 1024},
+		{Metric: map[string]string{"namespace": "other", "persistentvolumeclaim": "data"}, Value: 1024},
+	}
+
+	utils.Equals(t, map[string]Usage{
+		"default:data": {Used: 2, Capacity: 10},
+		"default:logs": {Used: 0.5},
+	}, toUsages(used, capacity))
+}
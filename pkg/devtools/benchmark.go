@@ -0,0 +1,335 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package devtools
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vmware/purser/pkg/controller/dgraph/models/query"
+)
+
+// BenchmarkConfig holds the load replayed against a seeded Dgraph
+type BenchmarkConfig struct {
+	Requests    int
+	Concurrency int
+	// Mix is the relative weight of each operation, operations not in it are not run
+	Mix map[string]int
+	// Seed is the config Dgraph was seeded with, names of queried resources are taken from its cluster
+	Seed SeedConfig
+}
+
+// DefaultBenchmarkMix weighs operations roughly as the Purser UI calls them
+var DefaultBenchmarkMix = map[string]int{
+	ClusterMetrics:     4,
+	ClusterHierarchy:   2,
+	PhysicalMetrics:    1,
+	NamespaceMetrics:   3,
+	NamespaceHierarchy: 1,
+	DeploymentMetrics:  2,
+	PodMetrics:         2,
+	NodeMetrics:        1,
+	PodInteractions:    1,
+}
+
+// DefaultBenchmarkConfig replays the default mix against a cluster seeded with DefaultSeedConfig
+var DefaultBenchmarkConfig = BenchmarkConfig{
+	Requests:    500,
+	Concurrency: 4,
+	Mix:         DefaultBenchmarkMix,
+	Seed:        DefaultSeedConfig,
+}
+
+// Benchmark operations
+const (
+	ClusterMetrics     = "clusterMetrics"
+	ClusterHierarchy   = "clusterHierarchy"
+	PhysicalMetrics    = "physicalMetrics"
+	NamespaceMetrics   = "namespaceMetrics"
+	NamespaceHierarchy = "namespaceHierarchy"
+	DeploymentMetrics  = "deploymentMetrics"
+	PodMetrics         = "podMetrics"
+	NodeMetrics        = "nodeMetrics"
+	PodInteractions    = "podInteractions"
+)
+
+// BenchmarkReport holds throughput and latencies of a benchmark, latencies are in milliseconds
+type BenchmarkReport struct {
+	Requests        int                      `json:"requests"`
+	Errors          int                      `json:"errors"`
+	Concurrency     int                      `json:"concurrency"`
+	DurationSeconds float64                  `json:"durationSeconds"`
+	Throughput      float64                  `json:"requestsPerSecond"`
+	Latency         LatencyReport            `json:"latency"`
+	Operations      map[string]LatencyReport `json:"operations"`
+}
+
+// LatencyReport holds latency percentiles in milliseconds of requests of an operation
+type LatencyReport struct {
+	Requests int     `json:"requests"`
+	Errors   int     `json:"errors"`
+	P50      float64 `json:"p50"`
+	P90      float64 `json:"p90"`
+	P99      float64 `json:"p99"`
+	Max      float64 `json:"max"`
+}
+
+// request is an operation called with the name of a resource of the seeded cluster
+type request struct {
+	operation string
+	name      string
+}
+
+// result is the latency of a request and whether it returned data
+type result struct {
+	latency time.Duration
+	isEmpty bool
+}
+
+// targets are names of resources of the seeded cluster as stored in Dgraph
+type targets struct {
+	namespaces  []string
+	deployments []string
+	pods        []string
+	nodes       []string
+}
+
+// operations call the query package as the API handlers do, they return false if no data is returned
+var operations = map[string]func(name string) bool{
+	ClusterMetrics: func(string) bool {
+		return len(query.RetrieveClusterMetrics(context.Background(), query.Logical).Data.Children) > 0
+	},
+	ClusterHierarchy: func(string) bool {
+		return len(query.RetrieveClusterHierarchy(context.Background(), query.Logical).Data.Children) > 0
+	},
+	PhysicalMetrics: func(string) bool {
+		return len(query.RetrieveClusterMetrics(context.Background(), query.Physical).Data.Children) > 0
+	},
+	NamespaceMetrics: func(name string) bool {
+		resource := query.Resource{Check: query.NamespaceCheck, Type: query.NamespaceType, Name: name}
+		return resource.RetrieveResourceMetrics(context.Background()).Data.Name != ""
+	},
+	NamespaceHierarchy: func(name string) bool {
+		resource := query.Resource{Check: query.NamespaceCheck, Type: query.NamespaceType, Name: name, ChildFilter: query.NamespaceChildFilter}
+		return resource.RetrieveResourceHierarchy(context.Background()).Data.Name != ""
+	},
+	DeploymentMetrics: func(name string) bool {
+		resource := query.Resource{Check: query.DeploymentCheck, Type: query.DeploymentType, Name: name}
+		return resource.RetrieveResourceMetrics(context.Background()).Data.Name != ""
+	},
+	PodMetrics: func(name string) bool {
+		resource := query.Resource{Check: query.PodCheck, Type: query.PodType, Name: name}
+		return resource.RetrieveResourceMetrics(context.Background()).Data.Name != ""
+	},
+	NodeMetrics: func(name string) bool {
+		resource := query.Resource{Check: query.NodeCheck, Type: query.NodeType, Name: name}
+		return resource.RetrieveResourceMetrics(context.Background()).Data.Name != ""
+	},
+	PodInteractions: func(name string) bool {
+		pods, err := query.RetrievePodsInteractions(context.Background(), name, false)
+		return err == nil && len(pods) > 0
+	},
+}
+
+// Benchmark replays the config's mix of query package calls against Dgraph, which must be started before and seeded
+// with the config's seed config. Requests returning no data are counted as errors.
+func Benchmark(config BenchmarkConfig) BenchmarkReport {
+	requests := newRequests(config, getTargets(Generate(config.Seed, time.Now())))
+	results := make([]result, len(requests))
+	concurrency := config.Concurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	indices := make(chan int)
+	var wg sync.WaitGroup
+	start := time.Now()
+	for worker := 0; worker < concurrency; worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range indices {
+				requestStart := time.Now()
+				isData := operations[requests[index].operation](requests[index].name)
+				results[index] = result{latency: time.Since(requestStart), isEmpty: !isData}
+			}
+		}()
+	}
+	for index := range requests {
+		indices <- index
+	}
+	close(indices)
+	wg.Wait()
+	return newBenchmarkReport(requests, results, concurrency, time.Since(start))
+}
+
+// ParseMix parses a mix of operations given as comma separated operation=weight pairs(ex: podMetrics=3,nodeMetrics=1)
+func ParseMix(mix string) (map[string]int, error) {
+	weights := make(map[string]int)
+	for _, pair := range strings.Split(mix, ",") {
+		operationAndWeight := strings.Split(strings.TrimSpace(pair), "=")
+		if len(operationAndWeight) != 2 {
+			return nil, fmt.Errorf("%s is not an operation=weight pair", pair)
+		}
+		operation := operationAndWeight[0]
+		if _, isOperation := operations[operation]; !isOperation {
+			return nil, fmt.Errorf("unknown operation %s, supported operations are %s", operation, strings.Join(getOperations(), ", "))
+		}
+		weight, err := strconv.Atoi(operationAndWeight[1])
+		if err != nil || weight < 0 {
+			return nil, fmt.Errorf("weight of %s is not a non negative integer: %s", operation, operationAndWeight[1])
+		}
+		weights[operation] = weight
+	}
+	return weights, nil
+}
+
+// getOperations returns names of all operations in alphabetical order
+func getOperations() []string {
+	names := []string{}
+	for operation := range operations {
+		names = append(names, operation)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// getTargets returns names of namespaces, deployments, live pods and nodes of the cluster as stored in Dgraph
+func getTargets(cluster Cluster) targets {
+	resources := targets{}
+	for _, namespace := range cluster.Namespaces {
+		resources.namespaces = append(resources.namespaces, "namespace-"+namespace.Name)
+	}
+	for _, deployment := range cluster.Deployments {
+		resources.deployments = append(resources.deployments, "deployment-"+deployment.Name)
+	}
+	for _, pod := range cluster.Pods {
+		if pod.DeletionTimestamp == nil {
+			resources.pods = append(resources.pods, "pod-"+pod.Name)
+		}
+	}
+	for _, node := range cluster.Nodes {
+		resources.nodes = append(resources.nodes, "node-"+node.Name)
+	}
+	return resources
+}
+
+// newRequests returns the requests of the config with operations picked by their weights in the mix and
+// resources picked uniformly, the same config gives the same requests
+func newRequests(config BenchmarkConfig, resources targets) []request {
+	random := rand.New(rand.NewSource(config.Seed.RandomSeed))
+	mix := getOperations()
+	totalWeight := 0
+	for _, operation := range mix {
+		totalWeight += config.Mix[operation]
+	}
+	requests := []request{}
+	if totalWeight == 0 {
+		return requests
+	}
+	for len(requests) < config.Requests {
+		pick := random.Intn(totalWeight)
+		for _, operation := range mix {
+			if pick < config.Mix[operation] {
+				requests = append(requests, request{operation: operation, name: pickName(operation, resources, random)})
+				break
+			}
+			pick -= config.Mix[operation]
+		}
+	}
+	return requests
+}
+
+// pickName returns a random name of a resource queried by the operation, empty if it queries the whole cluster
+func pickName(operation string, resources targets, random *rand.Rand) string {
+	var names []string
+	switch operation {
+	case NamespaceMetrics, NamespaceHierarchy:
+		names = resources.namespaces
+	case DeploymentMetrics:
+		names = resources.deployments
+	case PodMetrics, PodInteractions:
+		names = resources.pods
+	case NodeMetrics:
+		names = resources.nodes
+	}
+	if len(names) == 0 {
+		return query.All
+	}
+	return names[random.Intn(len(names))]
+}
+
+func newBenchmarkReport(requests []request, results []result, concurrency int, duration time.Duration) BenchmarkReport {
+	report := BenchmarkReport{
+		Requests:        len(requests),
+		Concurrency:     concurrency,
+		DurationSeconds: duration.Seconds(),
+		Operations:      make(map[string]LatencyReport),
+	}
+	if duration > 0 {
+		report.Throughput = float64(len(requests)) / duration.Seconds()
+	}
+	all := []result{}
+	byOperation := make(map[string][]result)
+	for index, request := range requests {
+		all = append(all, results[index])
+		byOperation[request.operation] = append(byOperation[request.operation], results[index])
+	}
+	report.Latency = newLatencyReport(all)
+	report.Errors = report.Latency.Errors
+	for operation, operationResults := range byOperation {
+		report.Operations[operation] = newLatencyReport(operationResults)
+	}
+	return report
+}
+
+func newLatencyReport(results []result) LatencyReport {
+	latencies := []float64{}
+	report := LatencyReport{Requests: len(results)}
+	for _, result := range results {
+		if result.isEmpty {
+			report.Errors++
+		}
+		latencies = append(latencies, float64(result.latency)/float64(time.Millisecond))
+	}
+	sort.Float64s(latencies)
+	report.P50 = percentile(latencies, 50)
+	report.P90 = percentile(latencies, 90)
+	report.P99 = percentile(latencies, 99)
+	report.Max = percentile(latencies, 100)
+	return report
+}
+
+// percentile returns the nearest rank percentile of sorted values, 0 if there are no values
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
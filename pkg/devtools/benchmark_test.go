@@ -0,0 +1,100 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package devtools
+
+import (
+	"testing"
+	"time"
+
+	"github.com/vmware/purser/test/utils"
+)
+
+func TestParseMix(t *testing.T) {
+	mix, err := ParseMix("podMetrics=3, nodeMetrics=0")
+	utils.Ok(t, err)
+	utils.Equals(t, map[string]int{PodMetrics: 3, NodeMetrics: 0}, mix)
+
+	_, err = ParseMix("podMetrics")
+	utils.Assert(t, err != nil, "operation without weight is parsed")
+	_, err = ParseMix("podCosts=1")
+	utils.Assert(t, err != nil, "unknown operation is parsed")
+	_, err = ParseMix("podMetrics=-1")
+	utils.Assert(t, err != nil, "negative weight is parsed")
+}
+
+func TestNewRequests(t *testing.T) {
+	config := DefaultBenchmarkConfig
+	config.Requests = 200
+	config.Mix = map[string]int{PodMetrics: 1, ClusterMetrics: 1, NodeMetrics: 0}
+	resources := getTargets(Generate(config.Seed, time.Now()))
+	requests := newRequests(config, resources)
+
+	utils.Equals(t, config.Requests, len(requests))
+	counts := make(map[string]int)
+	for _, request := range requests {
+		counts[request.operation]++
+		switch request.operation {
+		case PodMetrics:
+			utils.Assert(t, contains(resources.pods, request.name), "%s is not a live pod", request.name)
+		case ClusterMetrics:
+			utils.Equals(t, "", request.name)
+		}
+	}
+	utils.Equals(t, 0, counts[NodeMetrics])
+	utils.Assert(t, counts[PodMetrics] > 0 && counts[ClusterMetrics] > 0, "operations are not mixed: %v", counts)
+	utils.Equals(t, requests, newRequests(config, resources))
+
+	config.Mix = map[string]int{}
+	utils.Equals(t, 0, len(newRequests(config, resources)))
+}
+
+func TestNewBenchmarkReport(t *testing.T) {
+	requests := []request{{operation: PodMetrics}, {operation: PodMetrics}, {operation: ClusterMetrics}, {operation: PodMetrics}}
+	results := []result{
+		{latency: 10 * time.Millisecond},
+		{latency: 30 * time.Millisecond, isEmpty: true},
+		{latency: 40 * time.Millisecond},
+		{latency: 20 * time.Millisecond},
+	}
+	report := newBenchmarkReport(requests, results, 2, 2*time.Second)
+
+	utils.Equals(t, 4, report.Requests)
+	utils.Equals(t, 1, report.Errors)
+	utils.Equals(t, 2.0, report.Throughput)
+	utils.Equals(t, LatencyReport{Requests: 4, Errors: 1, P50: 20, P90: 40, P99: 40, Max: 40}, report.Latency)
+	utils.Equals(t, LatencyReport{Requests: 3, Errors: 1, P50: 20, P90: 30, P99: 30, Max: 30}, report.Operations[PodMetrics])
+	utils.Equals(t, LatencyReport{Requests: 1, P50: 40, P90: 40, P99: 40, Max: 40}, report.Operations[ClusterMetrics])
+}
+
+func TestPercentile(t *testing.T) {
+	utils.Equals(t, 0.0, percentile([]float64{}, 50))
+	sorted := []float64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	utils.Equals(t, 5.0, percentile(sorted, 50))
+	utils.Equals(t, 9.0, percentile(sorted, 90))
+	utils.Equals(t, 10.0, percentile(sorted, 99))
+	utils.Equals(t, 1.0, percentile(sorted, 0))
+}
+
+func contains(names []string, name string) bool {
+	for _, candidate := range names {
+		if candidate == name {
+			return true
+		}
+	}
+	return false
+}
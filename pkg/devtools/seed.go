@@ -0,0 +1,390 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package devtools generates synthetic data for development and performance testing of Purser without a cluster.
+package devtools
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/vmware/purser/pkg/controller/dgraph/models"
+
+	apps_v1beta1 "k8s.io/api/apps/v1beta1"
+	api_v1 "k8s.io/api/core/v1"
+	ext_v1beta1 "k8s.io/api/extensions/v1beta1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SeedConfig holds the shape of the synthetic cluster
+type SeedConfig struct {
+	Nodes                   int
+	Namespaces              int
+	DeploymentsPerNamespace int
+	Replicas                int
+	// Churn is the fraction of pods of each deployment which were terminated and replaced during the history
+	Churn float64
+	// Interactions is the number of pods of other deployments each live pod sends requests to
+	Interactions int
+	// History is the age of the oldest resources
+	History time.Duration
+	// RandomSeed makes the generated cluster reproducible
+	RandomSeed int64
+}
+
+// DefaultSeedConfig is a small cluster with a month of history
+var DefaultSeedConfig = SeedConfig{
+	Nodes:                   5,
+	Namespaces:              4,
+	DeploymentsPerNamespace: 3,
+	Replicas:                3,
+	Churn:                   0.3,
+	Interactions:            2,
+	History:                 30 * 24 * time.Hour,
+	RandomSeed:              1,
+}
+
+// Cluster holds generated objects in the order they are stored
+type Cluster struct {
+	Namespaces  []api_v1.Namespace
+	Nodes       []api_v1.Node
+	Deployments []apps_v1beta1.Deployment
+	Replicasets []ext_v1beta1.ReplicaSet
+	Pods        []api_v1.Pod
+	Services    []api_v1.Service
+	// ServicePods are xids of pods selected by each service keyed by service xid
+	ServicePods map[string][]string
+	// Interactions are request counts from a source pod to destination pods keyed by pod xids
+	Interactions map[string]map[string]float64
+}
+
+// SeedReport holds the number of objects stored by Seed
+type SeedReport struct {
+	Namespaces     int `json:"namespaces"`
+	Nodes          int `json:"nodes"`
+	Deployments    int `json:"deployments"`
+	Replicasets    int `json:"replicasets"`
+	Pods           int `json:"pods"`
+	TerminatedPods int `json:"terminatedPods"`
+	Services       int `json:"services"`
+	Interactions   int `json:"interactions"`
+	Errors         int `json:"errors"`
+}
+
+// instanceType is a node flavour of the synthetic cluster
+type instanceType struct {
+	name   string
+	cpu    string
+	memory string
+}
+
+var instanceTypes = []instanceType{
+	{"m5.large", "2", "8Gi"},
+	{"m5.xlarge", "4", "16Gi"},
+	{"c5.2xlarge", "8", "16Gi"},
+	{"r5.xlarge", "4", "32Gi"},
+}
+
+var zones = []string{"us-east-1a", "us-east-1b", "us-east-1c"}
+
+var namespaceNames = []string{"payments", "checkout", "catalog", "search", "auth", "analytics", "notifications", "inventory"}
+
+var deploymentNames = []string{"api", "web", "worker", "cache", "gateway", "scheduler"}
+
+var cpuRequests = []string{"100m", "250m", "500m", "1"}
+
+var memoryRequests = []string{"128Mi", "256Mi", "512Mi", "1Gi", "2Gi"}
+
+// Seed generates a synthetic cluster with the config and stores it in Dgraph through the same models used for
+// objects of a live cluster, Dgraph must be started before. Failures to store objects are logged and counted.
+func Seed(config SeedConfig) SeedReport {
+	cluster := Generate(config, time.Now())
+	report := SeedReport{}
+	for _, namespace := range cluster.Namespaces {
+		_, err := models.StoreNamespace(namespace)
+		count(&report.Namespaces, &report.Errors, err)
+	}
+	for _, node := range cluster.Nodes {
+		_, err := models.StoreNode(node)
+		count(&report.Nodes, &report.Errors, err)
+	}
+	for _, deployment := range cluster.Deployments {
+		_, err := models.StoreDeployment(deployment)
+		count(&report.Deployments, &report.Errors, err)
+	}
+	for _, replicaset := range cluster.Replicasets {
+		_, err := models.StoreReplicaset(replicaset)
+		count(&report.Replicasets, &report.Errors, err)
+	}
+	for _, pod := range cluster.Pods {
+		storePod(pod, &report)
+	}
+	for _, service := range cluster.Services {
+		err := models.StoreService(service)
+		if err == nil {
+			err = models.StorePodServiceEdges(getXID(service.ObjectMeta), cluster.ServicePods[getXID(service.ObjectMeta)])
+		}
+		count(&report.Services, &report.Errors, err)
+	}
+	for source, destinations := range cluster.Interactions {
+		xids, counts := []string{}, []float64{}
+		for destination, requests := range destinations {
+			xids = append(xids, destination)
+			counts = append(counts, requests)
+		}
+		err := models.StorePodsInteraction(source, xids, counts)
+		if err != nil {
+			report.Errors++
+			log.Errorf("unable to store interactions of pod: %s, err: %v", source, err)
+			continue
+		}
+		report.Interactions += len(xids)
+	}
+	return report
+}
+
+// storePod stores a terminated pod as live first so that its requests are stored like those of pods of a cluster
+func storePod(pod api_v1.Pod, report *SeedReport) {
+	deletionTimestamp := pod.DeletionTimestamp
+	pod.DeletionTimestamp = nil
+	err := models.StorePod(pod)
+	if err == nil && deletionTimestamp != nil {
+		pod.DeletionTimestamp = deletionTimestamp
+		err = models.StorePod(pod)
+		if err == nil {
+			report.TerminatedPods++
+		}
+	}
+	if err != nil {
+		log.Errorf("unable to store pod: %s, err: %v", getXID(pod.ObjectMeta), err)
+	}
+	count(&report.Pods, &report.Errors, err)
+}
+
+func count(stored, errors *int, err error) {
+	if err != nil {
+		*errors++
+		return
+	}
+	*stored++
+}
+
+// Generate returns a synthetic cluster with the config whose history ends at now,
+// the same config and now give the same cluster.
+func Generate(config SeedConfig, now time.Time) Cluster {
+	random := rand.New(rand.NewSource(config.RandomSeed))
+	start := now.Add(-config.History)
+	cluster := Cluster{
+		ServicePods:  make(map[string][]string),
+		Interactions: make(map[string]map[string]float64),
+	}
+	for index := 0; index < config.Nodes; index++ {
+		cluster.Nodes = append(cluster.Nodes, newNode(index, start, random))
+	}
+
+	livePods := [][]string{}
+	for index := 0; index < config.Namespaces; index++ {
+		namespace := newNamespace(index, start)
+		cluster.Namespaces = append(cluster.Namespaces, namespace)
+		for deploymentIndex := 0; deploymentIndex < config.DeploymentsPerNamespace; deploymentIndex++ {
+			name := getName(deploymentNames, deploymentIndex)
+			created := randomTimeBetween(start, start.Add(config.History/4), random)
+			deployment := newDeployment(namespace.Name, name, config.Replicas, created)
+			replicaset := newReplicaset(deployment)
+			cluster.Deployments = append(cluster.Deployments, deployment)
+			cluster.Replicasets = append(cluster.Replicasets, replicaset)
+
+			pods := newPods(replicaset, config, now, cluster.Nodes, random)
+			cluster.Pods = append(cluster.Pods, pods...)
+			live := []string{}
+			for _, pod := range pods {
+				if pod.DeletionTimestamp == nil {
+					live = append(live, getXID(pod.ObjectMeta))
+				}
+			}
+			livePods = append(livePods, live)
+
+			service := newService(deployment)
+			cluster.Services = append(cluster.Services, service)
+			cluster.ServicePods[getXID(service.ObjectMeta)] = live
+		}
+	}
+	generateInteractions(&cluster, livePods, config.Interactions, random)
+	return cluster
+}
+
+func newNode(index int, created time.Time, random *rand.Rand) api_v1.Node {
+	flavour := instanceTypes[random.Intn(len(instanceTypes))]
+	zone := zones[index%len(zones)]
+	capacity := api_v1.ResourceList{
+		api_v1.ResourceCPU:    resource.MustParse(flavour.cpu),
+		api_v1.ResourceMemory: resource.MustParse(flavour.memory),
+	}
+	return api_v1.Node{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:              fmt.Sprintf("ip-10-0-%d-%d.ec2.internal", index/250, index%250+10),
+			CreationTimestamp: meta_v1.NewTime(created),
+			Labels: map[string]string{
+				models.InstanceTypeLabelKey: flavour.name,
+				models.OSLabelKey:           "linux",
+				models.ZoneLabelKey:         zone,
+				models.RegionLabelKey:       zone[:len(zone)-1],
+			},
+		},
+		Status: api_v1.NodeStatus{Capacity: capacity, Allocatable: capacity},
+	}
+}
+
+func newNamespace(index int, created time.Time) api_v1.Namespace {
+	return api_v1.Namespace{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:              getName(namespaceNames, index),
+			CreationTimestamp: meta_v1.NewTime(created),
+		},
+	}
+}
+
+func newDeployment(namespace, name string, replicas int, created time.Time) apps_v1beta1.Deployment {
+	replicaCount := int32(replicas)
+	return apps_v1beta1.Deployment{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:              name,
+			Namespace:         namespace,
+			CreationTimestamp: meta_v1.NewTime(created),
+			Labels:            map[string]string{"app": name},
+		},
+		Spec: apps_v1beta1.DeploymentSpec{Replicas: &replicaCount},
+	}
+}
+
+func newReplicaset(deployment apps_v1beta1.Deployment) ext_v1beta1.ReplicaSet {
+	return ext_v1beta1.ReplicaSet{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:              deployment.Name + "-7d4b9c8f5",
+			Namespace:         deployment.Namespace,
+			CreationTimestamp: deployment.CreationTimestamp,
+			Labels:            deployment.Labels,
+			Annotations:       map[string]string{models.DeploymentRevisionAnnotation: "1"},
+			OwnerReferences:   []meta_v1.OwnerReference{{Kind: "Deployment", Name: deployment.Name}},
+		},
+	}
+}
+
+// newPods returns replicas of the replicaset running now and the pods they replaced,
+// pods replaced by churn ran for a random part of the history.
+func newPods(replicaset ext_v1beta1.ReplicaSet, config SeedConfig, now time.Time, nodes []api_v1.Node, random *rand.Rand) []api_v1.Pod {
+	terminated := int(float64(config.Replicas)*config.Churn + 0.5)
+	cpu := cpuRequests[random.Intn(len(cpuRequests))]
+	memory := memoryRequests[random.Intn(len(memoryRequests))]
+	pods := []api_v1.Pod{}
+	for index := 0; index < config.Replicas+terminated; index++ {
+		created := randomTimeBetween(replicaset.CreationTimestamp.Time, now, random)
+		pod := newPod(replicaset, fmt.Sprintf("%s-%05d", replicaset.Name, index), created, cpu, memory)
+		if len(nodes) > 0 {
+			pod.Spec.NodeName = nodes[random.Intn(len(nodes))].Name
+		}
+		if index >= config.Replicas {
+			deleted := meta_v1.NewTime(randomTimeBetween(created, now, random))
+			pod.DeletionTimestamp = &deleted
+		}
+		pods = append(pods, pod)
+	}
+	return pods
+}
+
+func newPod(replicaset ext_v1beta1.ReplicaSet, name string, created time.Time, cpu, memory string) api_v1.Pod {
+	requests := api_v1.ResourceList{
+		api_v1.ResourceCPU:    resource.MustParse(cpu),
+		api_v1.ResourceMemory: resource.MustParse(memory),
+	}
+	return api_v1.Pod{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:              name,
+			Namespace:         replicaset.Namespace,
+			CreationTimestamp: meta_v1.NewTime(created),
+			Labels:            replicaset.Labels,
+			OwnerReferences:   []meta_v1.OwnerReference{{Kind: "ReplicaSet", Name: replicaset.Name}},
+		},
+		Spec: api_v1.PodSpec{
+			Containers: []api_v1.Container{{
+				Name:      replicaset.Labels["app"],
+				Image:     "registry.example.com/" + replicaset.Namespace + "/" + replicaset.Labels["app"] + ":1.0.0",
+				Resources: api_v1.ResourceRequirements{Requests: requests, Limits: requests},
+			}},
+		},
+	}
+}
+
+func newService(deployment apps_v1beta1.Deployment) api_v1.Service {
+	return api_v1.Service{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:              deployment.Name,
+			Namespace:         deployment.Namespace,
+			CreationTimestamp: deployment.CreationTimestamp,
+		},
+		Spec: api_v1.ServiceSpec{Selector: deployment.Labels},
+	}
+}
+
+// generateInteractions adds requests from each live pod to interactions pods of other deployments
+func generateInteractions(cluster *Cluster, livePods [][]string, interactions int, random *rand.Rand) {
+	if len(livePods) < 2 {
+		return
+	}
+	for index, pods := range livePods {
+		for _, source := range pods {
+			destinations := make(map[string]float64)
+			for attempt := 0; attempt < interactions; attempt++ {
+				other := random.Intn(len(livePods) - 1)
+				if other >= index {
+					other++
+				}
+				if len(livePods[other]) == 0 {
+					continue
+				}
+				destination := livePods[other][random.Intn(len(livePods[other]))]
+				destinations[destination] += float64(random.Intn(1000) + 1)
+			}
+			if len(destinations) > 0 {
+				cluster.Interactions[source] = destinations
+			}
+		}
+	}
+}
+
+// getName returns the name at index of names, names are suffixed with their round once all are used
+func getName(names []string, index int) string {
+	name := names[index%len(names)]
+	if index < len(names) {
+		return name
+	}
+	return fmt.Sprintf("%s-%d", name, index/len(names))
+}
+
+func getXID(meta meta_v1.ObjectMeta) string {
+	return meta.Namespace + ":" + meta.Name
+}
+
+func randomTimeBetween(start, end time.Time, random *rand.Rand) time.Time {
+	if !end.After(start) {
+		return start
+	}
+	return start.Add(time.Duration(random.Int63n(int64(end.Sub(start))))).Truncate(time.Second)
+}
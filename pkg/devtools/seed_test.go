@@ -0,0 +1,83 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package devtools
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vmware/purser/test/utils"
+)
+
+func TestGenerate(t *testing.T) {
+	now := time.Date(2018, 10, 15, 0, 0, 0, 0, time.UTC)
+	config := DefaultSeedConfig
+	cluster := Generate(config, now)
+
+	utils.Equals(t, config.Nodes, len(cluster.Nodes))
+	utils.Equals(t, config.Namespaces, len(cluster.Namespaces))
+	utils.Equals(t, config.Namespaces*config.DeploymentsPerNamespace, len(cluster.Deployments))
+	utils.Equals(t, len(cluster.Deployments), len(cluster.Replicasets))
+	utils.Equals(t, len(cluster.Deployments), len(cluster.Services))
+
+	terminated := 0
+	for _, pod := range cluster.Pods {
+		utils.Assert(t, !pod.CreationTimestamp.Time.Before(now.Add(-config.History)), "pod %s is older than history", pod.Name)
+		if pod.DeletionTimestamp != nil {
+			terminated++
+			utils.Assert(t, !pod.DeletionTimestamp.Time.Before(pod.CreationTimestamp.Time), "pod %s is deleted before creation", pod.Name)
+		}
+		utils.Assert(t, pod.Spec.NodeName != "", "pod %s is not scheduled", pod.Name)
+	}
+	utils.Equals(t, len(cluster.Deployments)*config.Replicas, len(cluster.Pods)-terminated)
+	utils.Equals(t, len(cluster.Deployments), terminated)
+
+	for source, destinations := range cluster.Interactions {
+		utils.Assert(t, len(destinations) > 0, "pod %s has empty interactions", source)
+		for destination := range destinations {
+			utils.Assert(t, getDeploymentOfPod(source) != getDeploymentOfPod(destination), "pod %s interacts with its own deployment", source)
+		}
+	}
+	utils.Equals(t, len(cluster.Pods)-terminated, len(cluster.Interactions))
+
+	utils.Equals(t, cluster, Generate(config, now))
+}
+
+func TestGenerateWithoutInteractions(t *testing.T) {
+	config := DefaultSeedConfig
+	config.Namespaces = 1
+	config.DeploymentsPerNamespace = 1
+	config.Churn = 0
+	cluster := Generate(config, time.Now())
+	utils.Equals(t, config.Replicas, len(cluster.Pods))
+	utils.Equals(t, 0, len(cluster.Interactions))
+	utils.Equals(t, config.Replicas, len(cluster.ServicePods["payments:api"]))
+}
+
+func TestGetName(t *testing.T) {
+	names := []string{"api", "web"}
+	utils.Equals(t, "web", getName(names, 1))
+	utils.Equals(t, "api-1", getName(names, 2))
+	utils.Equals(t, "web-2", getName(names, 5))
+}
+
+// getDeploymentOfPod returns namespace and deployment of pod xid(ex: payments:api-7d4b9c8f5-00001)
+func getDeploymentOfPod(xid string) string {
+	return xid[:strings.Index(xid, "-7d4b9c8f5-")]
+}
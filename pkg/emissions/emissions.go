@@ -0,0 +1,56 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package emissions
+
+import (
+	"encoding/json"
+	"io/ioutil"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/vmware/purser/pkg/controller/dgraph/models"
+	"k8s.io/apimachinery/pkg/util/yaml"
+)
+
+// LoadCoefficients returns emission coefficients from the given JSON or YAML file
+func LoadCoefficients(path string) (models.EmissionCoefficients, error) {
+	coefficients := models.EmissionCoefficients{}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return coefficients, err
+	}
+	jsonData, err := yaml.ToJSON(data)
+	if err != nil {
+		return coefficients, err
+	}
+	err = json.Unmarshal(jsonData, &coefficients)
+	return coefficients, err
+}
+
+// Configure sets emission coefficients from the given file, defaults are used if path is empty
+func Configure(path string) error {
+	if path == "" {
+		return nil
+	}
+	coefficients, err := LoadCoefficients(path)
+	if err != nil {
+		return err
+	}
+	models.SetEmissionCoefficients(coefficients)
+	logrus.Infof("loaded emission coefficients from: %s", path)
+	return nil
+}
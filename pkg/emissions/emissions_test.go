@@ -0,0 +1,53 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package emissions
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/vmware/purser/pkg/controller/dgraph/models"
+	"github.com/vmware/purser/test/utils"
+)
+
+func TestLoadCoefficients(t *testing.T) {
+	file, err := ioutil.TempFile("", "emissions")
+	utils.Ok(t, err)
+	defer os.Remove(file.Name())
+	_, err = file.WriteString(`
+pue: 1.1
+instanceFamilyPower:
+  m5:
+    minWattsPerCPU: 0.8
+    maxWattsPerCPU: 3.2
+regionCarbonIntensity:
+  on-prem-dc1: 120
+`)
+	utils.Ok(t, err)
+	utils.Ok(t, file.Close())
+
+	coefficients, err := LoadCoefficients(file.Name())
+	utils.Ok(t, err)
+	expected := models.EmissionCoefficients{
+		PUE:                   1.1,
+		InstanceFamilyPower:   map[string]models.PowerCoefficients{"m5": {MinWattsPerCPU: 0.8, MaxWattsPerCPU: 3.2}},
+		RegionCarbonIntensity: map[string]float64{"on-prem-dc1": 120},
+	}
+	utils.Equals(t, expected, coefficients)
+}
@@ -0,0 +1,217 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package logging configures levels and format of logs and provides loggers of components whose levels can be
+// changed at runtime, ex: to silence errors of failing queries or to debug the dgraph client only.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	log "github.com/Sirupsen/logrus"
+)
+
+// Formats of logs
+const (
+	TextFormat = "text"
+	JSONFormat = "json"
+)
+
+// ComponentField is the field of log entries holding the component which logged them
+const ComponentField = "component"
+
+// RequestIDField is the field of log entries holding the ID of the API request they are logged for
+const RequestIDField = "requestID"
+
+type requestIDKey struct{}
+
+type component struct {
+	logger *log.Logger
+	// level set for the component, nil if it follows the default level
+	level *log.Level
+}
+
+var (
+	mu           sync.Mutex
+	components   = make(map[string]*component)
+	defaultLevel = log.InfoLevel
+	format       = TextFormat
+)
+
+// Component returns the logger of the component(ex: query), its entries have the component in field component
+// and are logged at the level of the component, the default level if none is set for it
+func Component(name string) *log.Entry {
+	mu.Lock()
+	defer mu.Unlock()
+	return getComponent(name).logger.WithField(ComponentField, name)
+}
+
+// Configure sets the default level(ex: info), the format(text or json) and levels of components(ex:
+// query=error,dgraph=debug) of logs. Loggers of components write where the standard logger writes.
+func Configure(level, logFormat, componentLevels string) error {
+	parsedLevel, err := log.ParseLevel(level)
+	if err != nil {
+		return fmt.Errorf("invalid log level: %s, err: %v", level, err)
+	}
+	if logFormat != TextFormat && logFormat != JSONFormat {
+		return fmt.Errorf("invalid log format: %s, use %s or %s", logFormat, TextFormat, JSONFormat)
+	}
+	levels, err := parseComponentLevels(componentLevels)
+	if err != nil {
+		return err
+	}
+
+	mu.Lock()
+	format = logFormat
+	log.SetFormatter(newFormatter(format))
+	for _, c := range components {
+		c.logger.Out = log.StandardLogger().Out
+		c.logger.Formatter = newFormatter(format)
+	}
+	mu.Unlock()
+	setDefaultLevel(parsedLevel)
+	for name, componentLevel := range levels {
+		setComponentLevel(name, componentLevel)
+	}
+	return nil
+}
+
+// SetLevel sets the level of logs of the component, the default level if the component is empty. Components
+// without a level follow the default level.
+func SetLevel(name, level string) error {
+	parsedLevel, err := log.ParseLevel(level)
+	if err != nil {
+		return fmt.Errorf("invalid log level: %s, err: %v", level, err)
+	}
+	if name == "" {
+		setDefaultLevel(parsedLevel)
+		log.Infof("default log level set to %s", parsedLevel)
+		return nil
+	}
+	setComponentLevel(name, parsedLevel)
+	log.Infof("log level of component %s set to %s", name, parsedLevel)
+	return nil
+}
+
+// GetLevels returns the default level and levels of components keyed by their name
+func GetLevels() (string, map[string]string) {
+	mu.Lock()
+	defer mu.Unlock()
+	levels := make(map[string]string, len(components))
+	for name, c := range components {
+		level := defaultLevel
+		if c.level != nil {
+			level = *c.level
+		}
+		levels[name] = level.String()
+	}
+	return defaultLevel.String(), levels
+}
+
+// WithRequestID returns a copy of ctx carrying the ID of the API request, ctx is returned if the ID is empty
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	if requestID == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestID returns the ID of the API request of ctx, empty if it has none
+func RequestID(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
+
+// WithContext returns the entry with the ID of the API request of ctx in field requestID, the entry itself if ctx
+// has no request ID
+func WithContext(entry *log.Entry, ctx context.Context) *log.Entry {
+	if requestID := RequestID(ctx); requestID != "" {
+		return entry.WithField(RequestIDField, requestID)
+	}
+	return entry
+}
+
+// IsLevel returns whether the level(ex: info) is a log level
+func IsLevel(level string) bool {
+	_, err := log.ParseLevel(level)
+	return err == nil
+}
+
+// getComponent returns the component with the name and creates it if it doesn't exist, mu must be held
+func getComponent(name string) *component {
+	c, isPresent := components[name]
+	if !isPresent {
+		c = &component{logger: log.New()}
+		c.logger.Out = log.StandardLogger().Out
+		c.logger.Formatter = newFormatter(format)
+		c.logger.SetLevel(defaultLevel)
+		components[name] = c
+	}
+	return c
+}
+
+func setDefaultLevel(level log.Level) {
+	mu.Lock()
+	defer mu.Unlock()
+	defaultLevel = level
+	log.SetLevel(level)
+	for _, c := range components {
+		if c.level == nil {
+			c.logger.SetLevel(level)
+		}
+	}
+}
+
+// setComponentLevel sets the level of the component, it is created if it doesn't log yet
+func setComponentLevel(name string, level log.Level) {
+	mu.Lock()
+	defer mu.Unlock()
+	c := getComponent(name)
+	c.level = &level
+	c.logger.SetLevel(level)
+}
+
+// parseComponentLevels parses levels of components(ex: query=error,dgraph=debug)
+func parseComponentLevels(componentLevels string) (map[string]log.Level, error) {
+	levels := make(map[string]log.Level)
+	for _, item := range strings.Split(componentLevels, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		parts := strings.SplitN(item, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, fmt.Errorf("invalid level of component: %s, use <component>=<level>(ex: query=error)", item)
+		}
+		level, err := log.ParseLevel(parts[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid level of component: %s, err: %v", item, err)
+		}
+		levels[parts[0]] = level
+	}
+	return levels, nil
+}
+
+func newFormatter(logFormat string) log.Formatter {
+	if logFormat == JSONFormat {
+		return &log.JSONFormatter{}
+	}
+	return &log.TextFormatter{ForceColors: true}
+}
@@ -0,0 +1,80 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/vmware/purser/test/utils"
+)
+
+func TestComponentLevels(t *testing.T) {
+	out := &bytes.Buffer{}
+	log.SetOutput(out)
+	utils.Ok(t, Configure("info", JSONFormat, "query=error"))
+	defer func() { utils.Ok(t, Configure("info", TextFormat, "")) }()
+
+	query, dgraph := Component("query"), Component("dgraph")
+	query.Infof("slow query")
+	utils.Equals(t, 0, out.Len())
+	query.Errorf("failed query")
+	dgraph.Infof("connected")
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	utils.Equals(t, 2, len(lines))
+	entry := map[string]interface{}{}
+	utils.Ok(t, json.Unmarshal([]byte(lines[0]), &entry))
+	utils.Equals(t, "failed query", entry["msg"])
+	utils.Equals(t, "query", entry[ComponentField])
+
+	utils.Ok(t, SetLevel("query", "debug"))
+	utils.Ok(t, SetLevel("", "error"))
+	defaultLevel, levels := GetLevels()
+	utils.Equals(t, "error", defaultLevel)
+	utils.Equals(t, "debug", levels["query"])
+	utils.Equals(t, "error", levels["dgraph"])
+}
+
+func TestConfigureInvalid(t *testing.T) {
+	utils.Assert(t, Configure("loud", TextFormat, "") != nil, "invalid level is accepted")
+	utils.Assert(t, Configure("info", "xml", "") != nil, "invalid format is accepted")
+	utils.Assert(t, Configure("info", TextFormat, "query") != nil, "component without level is accepted")
+	utils.Assert(t, Configure("info", TextFormat, "query=loud") != nil, "invalid level of component is accepted")
+	utils.Assert(t, SetLevel("query", "loud") != nil, "invalid level is set")
+}
+
+func TestIsLevel(t *testing.T) {
+	utils.Assert(t, IsLevel("debug"), "debug is a level")
+	utils.Assert(t, !IsLevel("verbose"), "verbose is not a level")
+}
+
+func TestWithContext(t *testing.T) {
+	entry := Component("api")
+	utils.Equals(t, entry, WithContext(entry, context.Background()))
+
+	ctx := WithRequestID(context.Background(), "3f2a9c")
+	utils.Equals(t, "3f2a9c", RequestID(ctx))
+	utils.Equals(t, "3f2a9c", WithContext(entry, ctx).Data[RequestIDField])
+	utils.Equals(t, "api", WithContext(entry, ctx).Data[ComponentField])
+	utils.Equals(t, context.Background(), WithRequestID(context.Background(), ""))
+}
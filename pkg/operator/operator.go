@@ -0,0 +1,211 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package operator
+
+import (
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+
+	installation_v1 "github.com/vmware/purser/pkg/apis/installation/v1"
+	installation_client "github.com/vmware/purser/pkg/client/clientset/typed/installation/v1"
+
+	apps_v1 "k8s.io/api/apps/v1"
+	api_v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+)
+
+// Operator reconciles Purser's own deployments and services with PurserInstallation resources
+type Operator struct {
+	kubeClient         kubernetes.Interface
+	installationClient *installation_client.PurserInstallationClient
+	resyncPeriod       time.Duration
+}
+
+// NewOperator returns an operator for the given clients
+func NewOperator(kubeClient kubernetes.Interface, installationClient *installation_client.PurserInstallationClient, resyncPeriod time.Duration) *Operator {
+	return &Operator{
+		kubeClient:         kubeClient,
+		installationClient: installationClient,
+		resyncPeriod:       resyncPeriod,
+	}
+}
+
+// Run watches PurserInstallation resources and reconciles them until stopCh is closed.
+// Every resource is reconciled again after resyncPeriod to revert manual changes to managed objects.
+func (o *Operator) Run(stopCh <-chan struct{}) {
+	informer := cache.NewSharedIndexInformer(
+		&cache.ListWatch{
+			ListFunc: func(options meta_v1.ListOptions) (runtime.Object, error) {
+				return o.installationClient.List(options)
+			},
+			WatchFunc: func(options meta_v1.ListOptions) (watch.Interface, error) {
+				return o.installationClient.Watch(options)
+			},
+		},
+		&installation_v1.PurserInstallation{},
+		o.resyncPeriod,
+		cache.Indexers{},
+	)
+	informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			o.handle(obj)
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			o.handle(newObj)
+		},
+	})
+	log.Info("Starting purser operator")
+	informer.Run(stopCh)
+	log.Info("Stopping purser operator")
+}
+
+func (o *Operator) handle(obj interface{}) {
+	inst, ok := obj.(*installation_v1.PurserInstallation)
+	if !ok {
+		log.Errorf("unexpected object in purserInstallation informer: %v", obj)
+		return
+	}
+	// objects in informer cache must not be modified
+	o.Reconcile(inst.DeepCopyObject().(*installation_v1.PurserInstallation))
+}
+
+// Reconcile creates or updates managed objects to match the installation and records the result in its status
+func (o *Operator) Reconcile(inst *installation_v1.PurserInstallation) {
+	log.Infof("Reconciling purserInstallation: %s/%s, version: %s", inst.Namespace, inst.Name, inst.Spec.Version)
+	if inst.Spec.Version == "" && (inst.Spec.Controller.Image == "" || (inst.Spec.UI.Enabled && inst.Spec.UI.Image == "")) {
+		o.updateStatus(inst, installation_v1.StateFailed, "spec.version is required when images are not given")
+		return
+	}
+
+	err := o.applyDeployment(newControllerDeployment(inst))
+	if err == nil {
+		err = o.applyService(newControllerService(inst))
+	}
+	if err == nil && inst.Spec.UI.Enabled {
+		err = o.applyDeployment(newUIDeployment(inst))
+		if err == nil {
+			err = o.applyService(newUIService(inst))
+		}
+	}
+	if err != nil {
+		log.Errorf("failed to reconcile purserInstallation: %s/%s, err: %v", inst.Namespace, inst.Name, err)
+		o.updateStatus(inst, installation_v1.StateFailed, err.Error())
+		return
+	}
+
+	state, message := o.getRolloutState(inst)
+	o.updateStatus(inst, state, message)
+}
+
+// applyDeployment creates the deployment if it doesn't exist, otherwise updates its spec.
+// Changing image(i.e, spec.version) triggers a rolling upgrade of the deployment.
+func (o *Operator) applyDeployment(desired *apps_v1.Deployment) error {
+	deployments := o.kubeClient.AppsV1().Deployments(desired.Namespace)
+	existing, err := deployments.Get(desired.Name, meta_v1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		_, err = deployments.Create(desired)
+		if err == nil {
+			log.Infof("Created deployment: %s/%s", desired.Namespace, desired.Name)
+		}
+		return err
+	} else if err != nil {
+		return err
+	}
+
+	existing.Labels = desired.Labels
+	existing.OwnerReferences = desired.OwnerReferences
+	existing.Spec = desired.Spec
+	_, err = deployments.Update(existing)
+	return err
+}
+
+// applyService creates the service if it doesn't exist, otherwise updates its ports and selector
+func (o *Operator) applyService(desired *api_v1.Service) error {
+	services := o.kubeClient.CoreV1().Services(desired.Namespace)
+	existing, err := services.Get(desired.Name, meta_v1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		_, err = services.Create(desired)
+		if err == nil {
+			log.Infof("Created service: %s/%s", desired.Namespace, desired.Name)
+		}
+		return err
+	} else if err != nil {
+		return err
+	}
+
+	// cluster IP is immutable so only selector, ports and type are updated
+	existing.Labels = desired.Labels
+	existing.OwnerReferences = desired.OwnerReferences
+	existing.Spec.Selector = desired.Spec.Selector
+	existing.Spec.Ports = desired.Spec.Ports
+	existing.Spec.Type = desired.Spec.Type
+	_, err = services.Update(existing)
+	return err
+}
+
+func (o *Operator) getRolloutState(inst *installation_v1.PurserInstallation) (string, string) {
+	names := []string{ControllerName}
+	if inst.Spec.UI.Enabled {
+		names = append(names, UIName)
+	}
+	for _, name := range names {
+		deployment, err := o.kubeClient.AppsV1().Deployments(inst.Namespace).Get(name, meta_v1.GetOptions{})
+		if err != nil {
+			return installation_v1.StateFailed, err.Error()
+		}
+		if !isDeploymentRolledOut(deployment) {
+			return installation_v1.StateReconciling, "waiting for rollout of deployment: " + name
+		}
+	}
+	return installation_v1.StateReady, "all components are available"
+}
+
+func isDeploymentRolledOut(deployment *apps_v1.Deployment) bool {
+	replicas := int32(1)
+	if deployment.Spec.Replicas != nil {
+		replicas = *deployment.Spec.Replicas
+	}
+	status := deployment.Status
+	return status.ObservedGeneration >= deployment.Generation && status.UpdatedReplicas == replicas &&
+		status.AvailableReplicas == replicas
+}
+
+func (o *Operator) updateStatus(inst *installation_v1.PurserInstallation, state, message string) {
+	newStatus := installation_v1.PurserInstallationStatus{
+		State:              state,
+		Message:            message,
+		InstalledVersion:   inst.Status.InstalledVersion,
+		ObservedGeneration: inst.Generation,
+	}
+	if state == installation_v1.StateReady {
+		newStatus.InstalledVersion = inst.Spec.Version
+	}
+	if newStatus == inst.Status {
+		return
+	}
+	inst.Status = newStatus
+	if _, err := o.installationClient.Update(inst); err != nil {
+		log.Errorf("failed to update status of purserInstallation: %s/%s, err: %v", inst.Namespace, inst.Name, err)
+	}
+}
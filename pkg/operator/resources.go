@@ -0,0 +1,243 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package operator
+
+import (
+	"strconv"
+
+	installation_v1 "github.com/vmware/purser/pkg/apis/installation/v1"
+
+	apps_v1 "k8s.io/api/apps/v1"
+	api_v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// Names and defaults of the resources managed by operator
+const (
+	ControllerName        = "purser"
+	UIName                = "purser-ui"
+	ServiceAccountName    = "purser-service-account"
+	DefaultControllerRepo = "kreddyj/controller-amd64"
+	DefaultUIRepo         = "kreddyj/purser-ui"
+	DefaultDgraphURL      = "purser-db"
+	DefaultDgraphPort     = "9080"
+	DefaultLogLevel       = "info"
+
+	managedByLabel = "app.kubernetes.io/managed-by"
+	operatorName   = "purser-operator"
+	dgraphURLKey   = "url"
+	dgraphPortKey  = "port"
+	dgraphURLEnv   = "DGRAPH_URL"
+	dgraphPortEnv  = "DGRAPH_PORT"
+	controllerPort = 3030
+	uiPort         = 4200
+	uiServicePort  = 80
+)
+
+func getOwnerReferences(inst *installation_v1.PurserInstallation) []meta_v1.OwnerReference {
+	isController := true
+	return []meta_v1.OwnerReference{{
+		APIVersion: installation_v1.InstallationGroupVersion.String(),
+		Kind:       "PurserInstallation",
+		Name:       inst.Name,
+		UID:        inst.UID,
+		Controller: &isController,
+	}}
+}
+
+func getObjectMeta(inst *installation_v1.PurserInstallation, name string, labels map[string]string) meta_v1.ObjectMeta {
+	objectLabels := map[string]string{managedByLabel: operatorName}
+	for key, value := range labels {
+		objectLabels[key] = value
+	}
+	return meta_v1.ObjectMeta{
+		Name:            name,
+		Namespace:       inst.Namespace,
+		Labels:          objectLabels,
+		OwnerReferences: getOwnerReferences(inst),
+	}
+}
+
+func getImage(image, defaultRepo, version string) string {
+	if image != "" {
+		return image
+	}
+	return defaultRepo + ":" + version
+}
+
+// getControllerArgs returns command line arguments of purser controller for the installation
+func getControllerArgs(inst *installation_v1.PurserInstallation) []string {
+	spec := inst.Spec
+	logLevel := spec.Controller.LogLevel
+	if logLevel == "" {
+		logLevel = DefaultLogLevel
+	}
+	interactions := "disable"
+	if spec.Controller.Interactions {
+		interactions = "enable"
+	}
+
+	args := []string{"--log=" + logLevel, "--interactions=" + interactions}
+	if spec.Dgraph.SecretName != "" {
+		// k8s expands $(VAR) in args from the container environment
+		args = append(args, "--dgraphURL=$("+dgraphURLEnv+")", "--dgraphPort=$("+dgraphPortEnv+")")
+	} else {
+		args = append(args, "--dgraphURL="+getOrDefault(spec.Dgraph.URL, DefaultDgraphURL),
+			"--dgraphPort="+getOrDefault(spec.Dgraph.Port, DefaultDgraphPort))
+	}
+	if spec.Retention.ResourceMonths != nil {
+		args = append(args, "--retentionMonths="+strconv.Itoa(*spec.Retention.ResourceMonths))
+	}
+	if spec.Retention.PodMonths != nil {
+		args = append(args, "--podRetentionMonths="+strconv.Itoa(*spec.Retention.PodMonths))
+	}
+	if spec.Retention.DeletedNamespaceMonths != nil {
+		args = append(args, "--deletedNamespaceRetentionMonths="+strconv.Itoa(*spec.Retention.DeletedNamespaceMonths))
+	}
+	return append(args, spec.ExtraArgs...)
+}
+
+func getDgraphEnv(inst *installation_v1.PurserInstallation) []api_v1.EnvVar {
+	secretName := inst.Spec.Dgraph.SecretName
+	if secretName == "" {
+		return nil
+	}
+	return []api_v1.EnvVar{
+		newSecretEnvVar(dgraphURLEnv, secretName, dgraphURLKey),
+		newSecretEnvVar(dgraphPortEnv, secretName, dgraphPortKey),
+	}
+}
+
+func newSecretEnvVar(name, secretName, key string) api_v1.EnvVar {
+	return api_v1.EnvVar{
+		Name: name,
+		ValueFrom: &api_v1.EnvVarSource{
+			SecretKeyRef: &api_v1.SecretKeySelector{
+				LocalObjectReference: api_v1.LocalObjectReference{Name: secretName},
+				Key:                  key,
+			},
+		},
+	}
+}
+
+func newResourceRequirements(cpu, memory string) api_v1.ResourceRequirements {
+	resources := api_v1.ResourceList{
+		api_v1.ResourceCPU:    resource.MustParse(cpu),
+		api_v1.ResourceMemory: resource.MustParse(memory),
+	}
+	return api_v1.ResourceRequirements{Limits: resources, Requests: resources}
+}
+
+// newControllerDeployment returns the desired deployment of purser controller
+func newControllerDeployment(inst *installation_v1.PurserInstallation) *apps_v1.Deployment {
+	labels := map[string]string{"app": "purser"}
+	replicas := int32(1)
+	return &apps_v1.Deployment{
+		ObjectMeta: getObjectMeta(inst, ControllerName, labels),
+		Spec: apps_v1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &meta_v1.LabelSelector{MatchLabels: labels},
+			Template: api_v1.PodTemplateSpec{
+				ObjectMeta: meta_v1.ObjectMeta{Labels: labels},
+				Spec: api_v1.PodSpec{
+					ServiceAccountName: ServiceAccountName,
+					NodeSelector:       inst.Spec.NodeSelector,
+					Containers: []api_v1.Container{{
+						Name:            "purser-controller",
+						Image:           getImage(inst.Spec.Controller.Image, DefaultControllerRepo, inst.Spec.Version),
+						ImagePullPolicy: api_v1.PullAlways,
+						Command:         []string{"/controller"},
+						Args:            getControllerArgs(inst),
+						Env:             getDgraphEnv(inst),
+						Resources:       newResourceRequirements("300m", "1000Mi"),
+						Ports:           []api_v1.ContainerPort{{Name: "http", ContainerPort: controllerPort}},
+					}},
+				},
+			},
+		},
+	}
+}
+
+// newControllerService returns the desired service of purser controller
+func newControllerService(inst *installation_v1.PurserInstallation) *api_v1.Service {
+	return &api_v1.Service{
+		ObjectMeta: getObjectMeta(inst, ControllerName, nil),
+		Spec: api_v1.ServiceSpec{
+			Selector: map[string]string{"app": "purser"},
+			Ports: []api_v1.ServicePort{{
+				Protocol:   api_v1.ProtocolTCP,
+				Port:       controllerPort,
+				TargetPort: intstr.FromString("http"),
+			}},
+		},
+	}
+}
+
+// newUIDeployment returns the desired deployment of purser UI
+func newUIDeployment(inst *installation_v1.PurserInstallation) *apps_v1.Deployment {
+	labels := map[string]string{"app": "purser", "run": UIName}
+	replicas := int32(1)
+	if inst.Spec.UI.Replicas != nil {
+		replicas = *inst.Spec.UI.Replicas
+	}
+	return &apps_v1.Deployment{
+		ObjectMeta: getObjectMeta(inst, UIName, nil),
+		Spec: apps_v1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &meta_v1.LabelSelector{MatchLabels: labels},
+			Template: api_v1.PodTemplateSpec{
+				ObjectMeta: meta_v1.ObjectMeta{Labels: labels},
+				Spec: api_v1.PodSpec{
+					NodeSelector: inst.Spec.NodeSelector,
+					Containers: []api_v1.Container{{
+						Name:            UIName,
+						Image:           getImage(inst.Spec.UI.Image, DefaultUIRepo, inst.Spec.Version),
+						ImagePullPolicy: api_v1.PullAlways,
+						Resources:       newResourceRequirements("500m", "1200Mi"),
+						Ports:           []api_v1.ContainerPort{{ContainerPort: uiPort}},
+					}},
+				},
+			},
+		},
+	}
+}
+
+// newUIService returns the desired service of purser UI
+func newUIService(inst *installation_v1.PurserInstallation) *api_v1.Service {
+	return &api_v1.Service{
+		ObjectMeta: getObjectMeta(inst, UIName, map[string]string{"app": "purser", "run": UIName}),
+		Spec: api_v1.ServiceSpec{
+			Selector: map[string]string{"app": "purser", "run": UIName},
+			Type:     api_v1.ServiceTypeLoadBalancer,
+			Ports: []api_v1.ServicePort{{
+				Protocol:   api_v1.ProtocolTCP,
+				Port:       uiServicePort,
+				TargetPort: intstr.FromInt(uiPort),
+			}},
+		},
+	}
+}
+
+func getOrDefault(value, defaultValue string) string {
+	if value == "" {
+		return defaultValue
+	}
+	return value
+}
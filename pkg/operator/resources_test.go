@@ -0,0 +1,53 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package operator
+
+import (
+	"testing"
+
+	installation_v1 "github.com/vmware/purser/pkg/apis/installation/v1"
+	"github.com/vmware/purser/test/utils"
+)
+
+func TestGetControllerArgs(t *testing.T) {
+	podMonths := 3
+	inst := &installation_v1.PurserInstallation{
+		Spec: installation_v1.PurserInstallationSpec{
+			Version:   "1.0.2",
+			Retention: installation_v1.RetentionSpec{PodMonths: &podMonths},
+		},
+	}
+	exp := []string{"--log=info", "--interactions=disable", "--dgraphURL=purser-db", "--dgraphPort=9080", "--podRetentionMonths=3"}
+	utils.Equals(t, exp, getControllerArgs(inst))
+
+	inst.Spec.Dgraph.SecretName = "purser-dgraph"
+	inst.Spec.Controller.Interactions = true
+	exp = []string{"--log=info", "--interactions=enable", "--dgraphURL=$(DGRAPH_URL)", "--dgraphPort=$(DGRAPH_PORT)", "--podRetentionMonths=3"}
+	utils.Equals(t, exp, getControllerArgs(inst))
+	utils.Equals(t, 2, len(getDgraphEnv(inst)))
+
+	namespaceMonths := 6
+	inst.Spec.Retention.DeletedNamespaceMonths = &namespaceMonths
+	exp = append(exp, "--deletedNamespaceRetentionMonths=6")
+	utils.Equals(t, exp, getControllerArgs(inst))
+}
+
+func TestGetImage(t *testing.T) {
+	utils.Equals(t, "kreddyj/controller-amd64:1.0.2", getImage("", DefaultControllerRepo, "1.0.2"))
+	utils.Equals(t, "my-registry/controller:dev", getImage("my-registry/controller:dev", DefaultControllerRepo, "1.0.2"))
+}
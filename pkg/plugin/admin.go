@@ -0,0 +1,110 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package plugin
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// RunRetention removes deleted resources older than the retention period of purser controller.
+func RunRetention() {
+	if !isAPIClientProvided() {
+		return
+	}
+	result, err := APIClientInstance.RunRetention()
+	if err != nil {
+		fmt.Printf("unable to run retention: %v\n", err)
+		return
+	}
+	fmt.Printf("Removed %d deleted resources, %d deleted pods, %d resources of deleted namespaces, %d edges to them and %d daily costs of groups\n",
+		result.Resources, result.Pods, result.DeletedNamespaceResources, result.Edges, result.DailyCosts)
+}
+
+// Reindex rebuilds dgraph indices of the predicates, of all indexed predicates if none is given.
+func Reindex(predicates []string) {
+	if !isAPIClientProvided() {
+		return
+	}
+	reindexed, err := APIClientInstance.Reindex(predicates...)
+	if err != nil {
+		fmt.Printf("unable to reindex: %v\n", err)
+		return
+	}
+	fmt.Printf("Reindexed predicates: %s\n", strings.Join(reindexed, ", "))
+}
+
+// Backup writes the json backup of purser data to the file.
+func Backup(fileName string) {
+	if !isAPIClientProvided() {
+		return
+	}
+	file, err := os.Create(fileName)
+	if err != nil {
+		fmt.Printf("unable to create backup file %s: %v\n", fileName, err)
+		return
+	}
+	defer func() {
+		if err := file.Close(); err != nil {
+			fmt.Printf("unable to close backup file %s: %v\n", fileName, err)
+		}
+	}()
+
+	if err = APIClientInstance.Backup(file); err != nil {
+		fmt.Printf("unable to backup: %v\n", err)
+		return
+	}
+	fmt.Printf("Backup is written to %s\n", fileName)
+}
+
+// MigrateSchema applies the schema of purser controller on existing data.
+func MigrateSchema() {
+	if !isAPIClientProvided() {
+		return
+	}
+	migration, err := APIClientInstance.MigrateSchema()
+	if err != nil {
+		fmt.Printf("unable to migrate schema: %v\n", err)
+		return
+	}
+	fmt.Printf("%-20s%s\n", "Added predicates:", strings.Join(migration.Added, ", "))
+	fmt.Printf("%-20s%s\n", "Changed predicates:", strings.Join(migration.Changed, ", "))
+	fmt.Printf("%-20s%s\n", "Renamed predicates:", strings.Join(migration.Migrated, ", "))
+}
+
+// VerifyConsistency prints the result of each consistency check of purser data.
+func VerifyConsistency() {
+	if !isAPIClientProvided() {
+		return
+	}
+	report, err := APIClientInstance.VerifyConsistency()
+	if err != nil {
+		fmt.Printf("unable to verify consistency: %v\n", err)
+		return
+	}
+	fmt.Printf("   %-30s   %10s   %s\n", "Check", "Count", "Sample UIDs")
+	for _, check := range report.Checks {
+		fmt.Printf("   %-30s   %10d   %s\n", check.Name, check.Count, strings.Join(check.UIDs, ", "))
+	}
+	if report.Consistent {
+		fmt.Println("Purser data is consistent")
+	} else {
+		fmt.Println("Purser data is inconsistent")
+	}
+}
@@ -0,0 +1,125 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package plugin
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/vmware/purser/pkg/client"
+)
+
+// APIClientInstance is the client of purser controller API, nil if the controller url isn't given.
+var APIClientInstance *client.APIClient
+
+// ProvideAPIClientInstance sets the purser controller API client instance.
+func ProvideAPIClientInstance(apiClient *client.APIClient) {
+	APIClientInstance = apiClient
+}
+
+// GetNamespaceCost prints month to date cost of the namespace and its workloads computed by purser controller.
+func GetNamespaceCost(name string) {
+	if !isAPIClientProvided() {
+		return
+	}
+	namespace, err := APIClientInstance.Metrics("namespace", name, nil)
+	if err != nil {
+		fmt.Printf("unable to get cost of namespace %s: %v\n", name, err)
+		return
+	}
+	printResourceCost(namespace)
+}
+
+// GetGroupCost prints month to date, projected and last month costs of the custom group computed by purser controller.
+func GetGroupCost(name string) {
+	if !isAPIClientProvided() {
+		return
+	}
+	groups, err := APIClientInstance.Groups()
+	if err != nil {
+		fmt.Printf("unable to get groups: %v\n", err)
+		return
+	}
+	for _, group := range groups {
+		if group.Name != name {
+			continue
+		}
+		fmt.Printf("%-25s%s\n", "Group:", group.Name)
+		fmt.Printf("%-25s%d\n", "Pods:", group.PodsCount)
+		fmt.Printf("%-25s%.2f\n", "Month To Date Cost($):", group.MtdCost)
+		fmt.Printf("%-25s%.2f\n", "Projected Cost($):", group.ProjectedCost)
+		fmt.Printf("%-25s%.2f\n", "Last Month Cost($):", group.LastMonthCost)
+		return
+	}
+	fmt.Printf("No group with name: %s\n", name)
+}
+
+// EstimateManifestCost prints the projected monthly cost on the cluster of workloads of the manifest file computed
+// by purser controller.
+func EstimateManifestCost(fileName string) {
+	if !isAPIClientProvided() {
+		return
+	}
+	file, err := os.Open(fileName)
+	if err != nil {
+		fmt.Printf("unable to open manifest %s: %v\n", fileName, err)
+		return
+	}
+	defer func() {
+		if err := file.Close(); err != nil {
+			fmt.Printf("unable to close manifest %s: %v\n", fileName, err)
+		}
+	}()
+
+	estimate, err := APIClientInstance.EstimateManifest(file)
+	if err != nil {
+		fmt.Printf("unable to estimate cost of manifest %s: %v\n", fileName, err)
+		return
+	}
+	fmt.Println("==============================")
+	fmt.Printf("%s Projected Monthly Cost\n", fileName)
+	fmt.Println("==============================")
+	fmt.Printf("   %-40s   %8s   %10s   %10s   %10s\n", "Workload", "Replicas", "CPU", "Memory(GB)", "Monthly($)")
+	for _, workload := range estimate.Workloads {
+		fmt.Printf("   %-40s   %8d   %10.2f   %10.2f   %10.2f\n", workload.Kind+"/"+workload.Name, workload.Replicas, workload.CPU, workload.Memory, workload.MonthlyCost)
+	}
+	fmt.Printf("   %-40s   %8s   %10.2f   %10.2f   %10.2f\n", "Total", "", estimate.CPU, estimate.Memory, estimate.MonthlyCost)
+	if estimate.RequiredCapacity.Nodes > 0 {
+		fmt.Printf("Needs %d more %s nodes costing $%.2f monthly if the cluster has no free capacity\n",
+			estimate.RequiredCapacity.Nodes, estimate.RequiredCapacity.InstanceType, estimate.RequiredCapacity.MonthlyCost)
+	}
+}
+
+func printResourceCost(resource *client.Resource) {
+	fmt.Println("==============================")
+	fmt.Printf("%s Month To Date Cost\n", resource.Name)
+	fmt.Println("==============================")
+	fmt.Printf("   %-40s   %10s   %10s   %10s   %10s\n", "Name", "CPU($)", "Memory($)", "Storage($)", "Total($)")
+	for _, child := range resource.Children {
+		fmt.Printf("   %-40s   %10.2f   %10.2f   %10.2f   %10.2f\n", child.Name, child.CPUCost, child.MemoryCost, child.StorageCost, child.TotalCost())
+	}
+	fmt.Printf("   %-40s   %10.2f   %10.2f   %10.2f   %10.2f\n", "Total", resource.CPUCost, resource.MemoryCost, resource.StorageCost, resource.TotalCost())
+}
+
+func isAPIClientProvided() bool {
+	if APIClientInstance == nil {
+		fmt.Println("purser controller url is not given, use --purserURL")
+		return false
+	}
+	return true
+}
@@ -0,0 +1,272 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package adjustment
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sync"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/vmware/purser/pkg/controller/dgraph/models/query"
+	"k8s.io/apimachinery/pkg/util/yaml"
+)
+
+// Built-in adjustment types
+const (
+	MultiplierType = "multiplier"
+	TaxType        = "tax"
+	RoundingType   = "rounding"
+)
+
+// Rounding modes
+const (
+	RoundNearest = query.RoundNearest
+	RoundUp      = query.RoundUp
+	RoundDown    = query.RoundDown
+)
+
+// Config structure
+type Config struct {
+	Adjustments []Spec `json:"adjustments"`
+}
+
+// Spec structure describes one adjustment in config.
+// ResourceTypes and CostTypes restrict the adjustment to given types, empty means all.
+type Spec struct {
+	Type          string   `json:"type"`
+	Value         float64  `json:"value,omitempty"`
+	Precision     int      `json:"precision,omitempty"`
+	Mode          string   `json:"mode,omitempty"`
+	ResourceTypes []string `json:"resourceTypes,omitempty"`
+	CostTypes     []string `json:"costTypes,omitempty"`
+}
+
+// Factory creates an adjustment from its spec
+type Factory func(spec Spec) (query.CostAdjustment, error)
+
+var (
+	factoriesMu sync.RWMutex
+	factories   = map[string]Factory{
+		MultiplierType: newMultiplier,
+		TaxType:        newTax,
+		RoundingType:   newRounding,
+	}
+)
+
+// RegisterType makes an adjustment type available in config, so that organizations can add their own policies
+func RegisterType(adjustmentType string, factory Factory) {
+	factoriesMu.Lock()
+	defer factoriesMu.Unlock()
+	factories[adjustmentType] = factory
+}
+
+// Configure loads adjustments from the given JSON or YAML config file and applies them on computed costs
+func Configure(path string) error {
+	if path == "" {
+		return nil
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	jsonData, err := yaml.ToJSON(data)
+	if err != nil {
+		return err
+	}
+	config := Config{}
+	if err = json.Unmarshal(jsonData, &config); err != nil {
+		return err
+	}
+	adjustments, err := Build(config)
+	if err != nil {
+		return err
+	}
+	query.SetCostAdjustments(adjustments)
+	logrus.Infof("loaded %d cost adjustments from: %s", len(adjustments), path)
+	return nil
+}
+
+// ConfigurePrecision sets the decimals of prices used in query math and the rounding of costs returned by APIs,
+// negative costPrecision returns costs unrounded
+func ConfigurePrecision(pricePrecision, costPrecision int, costRounding string) error {
+	pricePolicy, err := query.NewRoundingPolicy(pricePrecision, query.RoundNearest)
+	if err != nil {
+		return fmt.Errorf("invalid price precision: %v", err)
+	}
+	query.SetPricePolicy(pricePolicy)
+	if costPrecision < 0 {
+		query.SetCostPolicy(nil)
+		return nil
+	}
+	costPolicy, err := query.NewRoundingPolicy(costPrecision, costRounding)
+	if err != nil {
+		return fmt.Errorf("invalid cost rounding: %v", err)
+	}
+	query.SetCostPolicy(&costPolicy)
+	logrus.Infof("costs are rounded %s to %d decimals", costPolicy.Mode, costPolicy.Precision)
+	return nil
+}
+
+// Build returns adjustments for the config in the same order
+func Build(config Config) ([]query.CostAdjustment, error) {
+	factoriesMu.RLock()
+	defer factoriesMu.RUnlock()
+	var adjustments []query.CostAdjustment
+	for _, spec := range config.Adjustments {
+		factory, isPresent := factories[spec.Type]
+		if !isPresent {
+			return nil, fmt.Errorf("unknown cost adjustment type: %s", spec.Type)
+		}
+		adjustment, err := factory(spec)
+		if err != nil {
+			return nil, err
+		}
+		adjustments = append(adjustments, &filtered{
+			adjustment:    adjustment,
+			resourceTypes: toSet(spec.ResourceTypes),
+			costTypes:     toSet(spec.CostTypes),
+		})
+	}
+	return adjustments, nil
+}
+
+// filtered applies the adjustment only on costs of selected resource and cost types
+type filtered struct {
+	adjustment    query.CostAdjustment
+	resourceTypes map[string]bool
+	costTypes     map[string]bool
+}
+
+func (f *filtered) Name() string {
+	return f.adjustment.Name()
+}
+
+func (f *filtered) Adjust(ctx query.CostContext, cost float64) float64 {
+	if !f.isSelected(ctx) {
+		return cost
+	}
+	return f.adjustment.Adjust(ctx, cost)
+}
+
+// AdjustAll adjusts the costs of selected resource and cost types, in one call if the adjustment is a batch adjustment
+func (f *filtered) AdjustAll(ctxs []query.CostContext, costs []float64) []float64 {
+	adjusted := append([]float64(nil), costs...)
+	batch, isBatch := f.adjustment.(query.BatchCostAdjustment)
+	selected := []int{}
+	for index, ctx := range ctxs {
+		if !f.isSelected(ctx) {
+			continue
+		}
+		if !isBatch {
+			adjusted[index] = f.adjustment.Adjust(ctx, costs[index])
+			continue
+		}
+		selected = append(selected, index)
+	}
+	if len(selected) == 0 {
+		return adjusted
+	}
+	selectedCtxs := make([]query.CostContext, len(selected))
+	selectedCosts := make([]float64, len(selected))
+	for i, index := range selected {
+		selectedCtxs[i], selectedCosts[i] = ctxs[index], costs[index]
+	}
+	values := batch.AdjustAll(selectedCtxs, selectedCosts)
+	if len(values) != len(selected) {
+		return adjusted
+	}
+	for i, index := range selected {
+		adjusted[index] = values[i]
+	}
+	return adjusted
+}
+
+func (f *filtered) isSelected(ctx query.CostContext) bool {
+	if len(f.resourceTypes) > 0 && !f.resourceTypes[ctx.ResourceType] {
+		return false
+	}
+	return len(f.costTypes) == 0 || f.costTypes[ctx.CostType]
+}
+
+type multiplier struct {
+	factor float64
+}
+
+func newMultiplier(spec Spec) (query.CostAdjustment, error) {
+	if spec.Value <= 0 {
+		return nil, fmt.Errorf("multiplier value should be positive, given: %v", spec.Value)
+	}
+	return &multiplier{factor: spec.Value}, nil
+}
+
+func (m *multiplier) Name() string {
+	return MultiplierType
+}
+
+func (m *multiplier) Adjust(ctx query.CostContext, cost float64) float64 {
+	return cost * m.factor
+}
+
+type tax struct {
+	percent float64
+}
+
+func newTax(spec Spec) (query.CostAdjustment, error) {
+	if spec.Value < 0 {
+		return nil, fmt.Errorf("tax percent should not be negative, given: %v", spec.Value)
+	}
+	return &tax{percent: spec.Value}, nil
+}
+
+func (t *tax) Name() string {
+	return TaxType
+}
+
+func (t *tax) Adjust(ctx query.CostContext, cost float64) float64 {
+	return cost * (1 + t.percent/100)
+}
+
+type rounding struct {
+	policy query.RoundingPolicy
+}
+
+func newRounding(spec Spec) (query.CostAdjustment, error) {
+	policy, err := query.NewRoundingPolicy(spec.Precision, spec.Mode)
+	if err != nil {
+		return nil, err
+	}
+	return &rounding{policy: policy}, nil
+}
+
+func (r *rounding) Name() string {
+	return RoundingType
+}
+
+func (r *rounding) Adjust(ctx query.CostContext, cost float64) float64 {
+	return r.policy.Round(cost)
+}
+
+func toSet(values []string) map[string]bool {
+	set := make(map[string]bool)
+	for _, value := range values {
+		set[value] = true
+	}
+	return set
+}
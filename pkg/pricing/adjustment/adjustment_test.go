@@ -0,0 +1,106 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package adjustment
+
+import (
+	"testing"
+
+	"github.com/vmware/purser/pkg/controller/dgraph/models/query"
+	"github.com/vmware/purser/test/utils"
+)
+
+func TestBuild(t *testing.T) {
+	adjustments, err := Build(Config{
+		Adjustments: []Spec{
+			{Type: MultiplierType, Value: 2, CostTypes: []string{query.CPUCostType}},
+			{Type: TaxType, Value: 10},
+			{Type: RoundingType, Precision: 2, Mode: RoundUp},
+		},
+	})
+	utils.Ok(t, err)
+
+	apply := func(ctx query.CostContext, cost float64) float64 {
+		for _, adjustment := range adjustments {
+			cost = adjustment.Adjust(ctx, cost)
+		}
+		return cost
+	}
+	utils.Equals(t, 2.21, apply(query.CostContext{ResourceType: "pod", CostType: query.CPUCostType}, 1.001))
+	utils.Equals(t, 1.11, apply(query.CostContext{ResourceType: "pod", CostType: query.MemoryCostType}, 1.001))
+}
+
+type batchAdjustment struct {
+	requested [][]float64
+}
+
+func (b *batchAdjustment) Name() string {
+	return "batch"
+}
+
+func (b *batchAdjustment) Adjust(ctx query.CostContext, cost float64) float64 {
+	return cost + 1
+}
+
+func (b *batchAdjustment) AdjustAll(ctxs []query.CostContext, costs []float64) []float64 {
+	b.requested = append(b.requested, costs)
+	adjusted := []float64{}
+	for _, cost := range costs {
+		adjusted = append(adjusted, cost+1)
+	}
+	return adjusted
+}
+
+func TestBuildWithBatchAdjustment(t *testing.T) {
+	batch := &batchAdjustment{}
+	RegisterType("batch", func(spec Spec) (query.CostAdjustment, error) {
+		return batch, nil
+	})
+	adjustments, err := Build(Config{
+		Adjustments: []Spec{
+			{Type: "batch", CostTypes: []string{query.CPUCostType}},
+			{Type: MultiplierType, Value: 2, ResourceTypes: []string{"node"}},
+		},
+	})
+	utils.Ok(t, err)
+
+	ctxs := []query.CostContext{
+		{ResourceType: "pod", CostType: query.CPUCostType},
+		{ResourceType: "pod", CostType: query.MemoryCostType},
+		{ResourceType: "node", CostType: query.CPUCostType},
+	}
+	costs := []float64{1, 2, 3}
+	for _, adjustment := range adjustments {
+		costs = adjustment.(query.BatchCostAdjustment).AdjustAll(ctxs, costs)
+	}
+	utils.Equals(t, []float64{2, 2, 8}, costs)
+	utils.Equals(t, [][]float64{{1, 3}}, batch.requested)
+}
+
+func TestBuildWithInvalidSpec(t *testing.T) {
+	_, err := Build(Config{Adjustments: []Spec{{Type: "discount"}}})
+	utils.Assert(t, err != nil, "expected error for unknown adjustment type")
+
+	_, err = Build(Config{Adjustments: []Spec{{Type: RoundingType, Mode: "half-even"}}})
+	utils.Assert(t, err != nil, "expected error for unknown rounding mode")
+}
+
+func TestConfigurePrecision(t *testing.T) {
+	utils.Ok(t, ConfigurePrecision(query.DefaultPricePrecision, -1, ""))
+	utils.Assert(t, ConfigurePrecision(-1, -1, "") != nil, "expected error for negative price precision")
+	utils.Assert(t, ConfigurePrecision(query.DefaultPricePrecision, 2, "half-even") != nil, "expected error for unknown rounding mode")
+}
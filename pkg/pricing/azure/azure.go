@@ -0,0 +1,75 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package azure
+
+import (
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/vmware/purser/pkg/controller/utils"
+)
+
+const (
+	httpTimeout     = 100 * time.Second
+	retailPricesURL = "https://prices.azure.com/api/retail/prices"
+)
+
+// PriceList structure
+type PriceList struct {
+	Items        []PriceItem `json:"Items"`
+	NextPageLink string      `json:"NextPageLink"`
+}
+
+// PriceItem structure
+type PriceItem struct {
+	CurrencyCode  string  `json:"currencyCode"`
+	RetailPrice   float64 `json:"retailPrice"`
+	ArmRegionName string  `json:"armRegionName"`
+	ArmSkuName    string  `json:"armSkuName"`
+	ProductName   string  `json:"productName"`
+	SkuName       string  `json:"skuName"`
+	ServiceName   string  `json:"serviceName"`
+	UnitOfMeasure string  `json:"unitOfMeasure"`
+	Type          string  `json:"type"`
+}
+
+// GetAzurePricing retrieves pay as you go prices of virtual machines in the region(ex: eastus) from Azure Retail
+// Prices API, the API is public and needs no credentials
+func GetAzurePricing(region string) ([]PriceItem, error) {
+	var myClient = &http.Client{Timeout: httpTimeout}
+	var items []PriceItem
+	pageURL := getURLForRegion(region)
+	for pageURL != "" {
+		page := PriceList{}
+		err := utils.GetJSONResponse(myClient, pageURL, &page)
+		if err != nil {
+			logrus.Errorf("Unable to get azure pricing. Reason: %v", err)
+			return nil, err
+		}
+		items = append(items, page.Items...)
+		pageURL = page.NextPageLink
+	}
+	return items, nil
+}
+
+func getURLForRegion(region string) string {
+	filter := "serviceName eq 'Virtual Machines' and armRegionName eq '" + region + "' and priceType eq 'Consumption'"
+	return retailPricesURL + "?" + url.Values{"$filter": []string{filter}}.Encode()
+}
@@ -0,0 +1,98 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package azure
+
+import (
+	"strings"
+
+	"github.com/vmware/purser/pkg/controller/dgraph"
+	"github.com/vmware/purser/pkg/controller/dgraph/models"
+)
+
+// Azure specific constants
+const (
+	oneHour     = "1 Hour"
+	spot        = "Spot"
+	lowPriority = "Low Priority"
+	windows     = "Windows"
+	linux       = "Linux"
+	deliminator = "-"
+)
+
+// GetRateCardForAzure takes region(ex: eastus) as input and returns RateCard
+func GetRateCardForAzure(region string) *models.RateCard {
+	items, err := GetAzurePricing(region)
+	if err == nil {
+		return convertAzurePricingToPurserRateCard(region, items)
+	}
+	return nil
+}
+
+func convertAzurePricingToPurserRateCard(region string, items []PriceItem) *models.RateCard {
+	return &models.RateCard{
+		ID:            dgraph.ID{Xid: models.RateCardXID},
+		IsRateCard:    true,
+		CloudProvider: models.Azure,
+		Region:        region,
+		NodePrices:    storeNodePrices(getNodePricesFromItems(items)),
+	}
+}
+
+// getNodePricesFromItems returns hourly prices of virtual machine sizes(ex: Standard_D4s_v3) per os. Retail prices
+// don't have cpus and memory of sizes, so only the price of the instance is stored and it is split using capacity
+// of nodes when they are priced. Spot and low priority prices are skipped.
+func getNodePricesFromItems(items []PriceItem) []*models.NodePrice {
+	var nodePrices []*models.NodePrice
+	duplicateSizeChecker := make(map[string]bool)
+	for _, item := range items {
+		if item.UnitOfMeasure != oneHour || item.RetailPrice <= 0 || item.ArmSkuName == "" ||
+			strings.Contains(item.SkuName, spot) || strings.Contains(item.SkuName, lowPriority) {
+			continue
+		}
+		os := linux
+		if strings.HasSuffix(item.ProductName, windows) {
+			os = windows
+		}
+		productXID := item.ArmSkuName + deliminator + os
+		if duplicateSizeChecker[productXID] {
+			continue
+		}
+		duplicateSizeChecker[productXID] = true
+		nodePrices = append(nodePrices, &models.NodePrice{
+			ID:              dgraph.ID{Xid: productXID},
+			IsNodePrice:     true,
+			InstanceType:    item.ArmSkuName,
+			InstanceFamily:  strings.TrimSuffix(item.ProductName, " "+windows),
+			OperatingSystem: os,
+			Price:           item.RetailPrice,
+		})
+	}
+	return nodePrices
+}
+
+func storeNodePrices(nodePrices []*models.NodePrice) []*models.NodePrice {
+	var stored []*models.NodePrice
+	for _, nodePrice := range nodePrices {
+		uid := models.StoreNodePrice(nodePrice, nodePrice.Xid)
+		if uid != "" {
+			nodePrice.ID = dgraph.ID{UID: uid, Xid: nodePrice.Xid}
+			stored = append(stored, nodePrice)
+		}
+	}
+	return stored
+}
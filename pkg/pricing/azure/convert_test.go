@@ -0,0 +1,43 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package azure
+
+import (
+	"testing"
+
+	"github.com/vmware/purser/test/utils"
+)
+
+func TestGetNodePricesFromItems(t *testing.T) {
+	items := []PriceItem{
+		{ArmSkuName: "Standard_D4s_v3", SkuName: "D4s v3", ProductName: "Virtual Machines DSv3 Series", RetailPrice: 0.192, UnitOfMeasure: "1 Hour"},
+		{ArmSkuName: "Standard_D4s_v3", SkuName: "D4s v3 Spot", ProductName: "Virtual Machines DSv3 Series", RetailPrice: 0.0384, UnitOfMeasure: "1 Hour"},
+		{ArmSkuName: "Standard_D4s_v3", SkuName: "D4s v3", ProductName: "Virtual Machines DSv3 Series Windows", RetailPrice: 0.376, UnitOfMeasure: "1 Hour"},
+		{ArmSkuName: "Standard_D4s_v3", SkuName: "D4s v3", ProductName: "Virtual Machines DSv3 Series", RetailPrice: 0.199, UnitOfMeasure: "1 Hour"},
+		{ArmSkuName: "Standard_B1s", SkuName: "B1s Low Priority", ProductName: "Virtual Machines BS Series", RetailPrice: 0.002, UnitOfMeasure: "1 Hour"},
+	}
+	nodePrices := getNodePricesFromItems(items)
+
+	utils.Equals(t, 2, len(nodePrices))
+	utils.Equals(t, "Standard_D4s_v3-Linux", nodePrices[0].Xid)
+	utils.Equals(t, 0.192, nodePrices[0].Price)
+	utils.Equals(t, "Virtual Machines DSv3 Series", nodePrices[0].InstanceFamily)
+	utils.Equals(t, "Standard_D4s_v3-Windows", nodePrices[1].Xid)
+	utils.Equals(t, 0.376, nodePrices[1].Price)
+	utils.Equals(t, 0.0, nodePrices[1].PricePerCPU)
+}
@@ -18,33 +18,103 @@
 package pricing
 
 import (
+	"strings"
+
 	"github.com/Sirupsen/logrus"
 	"github.com/vmware/purser/pkg/controller/dgraph/models"
 	"github.com/vmware/purser/pkg/pricing/aws"
+	"github.com/vmware/purser/pkg/pricing/azure"
+	"github.com/vmware/purser/pkg/pricing/gcp"
+	api_v1 "k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 )
 
+// Default cloud provider and region used when they can't be found from nodes of the cluster
+const (
+	DefaultCloudProvider = models.AWS
+	DefaultRegion        = "us-east-1"
+)
+
+// providerIDPrefixes maps prefixes of provider ids of nodes(spec.providerID) to cloud providers
+var providerIDPrefixes = map[string]string{
+	"aws://":   models.AWS,
+	"gce://":   models.GCP,
+	"azure://": models.Azure,
+}
+
 // Cloud structure used for pricing
 type Cloud struct {
 	CloudProvider string
 	Region        string
 	Kubeclient    *kubernetes.Clientset
+	// GCPAPIKey is the API key used for Cloud Billing Catalog API, GCP rate card is not populated without it
+	GCPAPIKey string
 }
 
-// GetClusterProviderAndRegion returns cluster provider(ex: aws) and region(ex: us-east-1)
-func GetClusterProviderAndRegion() (string, string) {
-	// TODO: https://github.com/vmware/purser/issues/143
-	cloudProvider := models.AWS
-	region := "us-east-1"
+// GetClusterProviderAndRegion returns cluster provider(ex: aws) and region(ex: us-east-1) from provider ids and
+// region labels of nodes of the cluster, defaults are returned if nodes can't be listed
+func GetClusterProviderAndRegion(kubeclient *kubernetes.Clientset) (string, string) {
+	var nodes []api_v1.Node
+	if kubeclient != nil {
+		nodeList, err := kubeclient.CoreV1().Nodes().List(meta_v1.ListOptions{})
+		if err != nil {
+			logrus.Errorf("unable to list nodes for finding cloud provider, err: %v", err)
+		} else {
+			nodes = nodeList.Items
+		}
+	}
+	cloudProvider, region := getProviderAndRegionFromNodes(nodes)
 	logrus.Infof("CloudProvider: %s, Region: %s", cloudProvider, region)
 	return cloudProvider, region
 }
 
+// getProviderAndRegionFromNodes returns cloud provider and region of the first node which has them
+func getProviderAndRegionFromNodes(nodes []api_v1.Node) (string, string) {
+	cloudProvider, region := "", ""
+	for _, node := range nodes {
+		if cloudProvider == "" {
+			cloudProvider = getProviderFromID(node.Spec.ProviderID)
+		}
+		if region == "" {
+			if region = node.Labels[models.StableRegionLabelKey]; region == "" {
+				region = node.Labels[models.RegionLabelKey]
+			}
+		}
+	}
+	if cloudProvider == "" {
+		cloudProvider = DefaultCloudProvider
+	}
+	if region == "" {
+		region = DefaultRegion
+	}
+	return cloudProvider, region
+}
+
+func getProviderFromID(providerID string) string {
+	for prefix, cloudProvider := range providerIDPrefixes {
+		if strings.HasPrefix(providerID, prefix) {
+			return cloudProvider
+		}
+	}
+	return ""
+}
+
 // PopulateRateCard given a cloud (cloudProvider and region) it populates corresponding rate card in dgraph
 func (c *Cloud) PopulateRateCard() {
 	switch c.CloudProvider {
 	case models.AWS:
 		rateCard := aws.GetRateCardForAWS(c.Region)
 		models.StoreRateCard(rateCard)
+	case models.GCP:
+		if c.GCPAPIKey == "" {
+			logrus.Warnf("gcp rate card is not populated, Cloud Billing API key is not given")
+			return
+		}
+		rateCard := gcp.GetRateCardForGCP(c.Region, c.GCPAPIKey)
+		models.StoreRateCard(rateCard)
+	case models.Azure:
+		rateCard := azure.GetRateCardForAzure(c.Region)
+		models.StoreRateCard(rateCard)
 	}
 }
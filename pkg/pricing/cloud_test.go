@@ -0,0 +1,41 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package pricing
+
+import (
+	"testing"
+
+	"github.com/vmware/purser/pkg/controller/dgraph/models"
+	"github.com/vmware/purser/test/utils"
+	api_v1 "k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGetProviderAndRegionFromNodes(t *testing.T) {
+	nodes := []api_v1.Node{
+		{ObjectMeta: meta_v1.ObjectMeta{Labels: map[string]string{models.StableRegionLabelKey: "us-east1"}}},
+		{Spec: api_v1.NodeSpec{ProviderID: "gce://project/us-east1-b/node-1"}},
+	}
+	cloudProvider, region := getProviderAndRegionFromNodes(nodes)
+	utils.Equals(t, models.GCP, cloudProvider)
+	utils.Equals(t, "us-east1", region)
+
+	cloudProvider, region = getProviderAndRegionFromNodes(nil)
+	utils.Equals(t, DefaultCloudProvider, cloudProvider)
+	utils.Equals(t, DefaultRegion, region)
+}
@@ -0,0 +1,64 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package extended
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/vmware/purser/pkg/controller/dgraph/models"
+	"k8s.io/apimachinery/pkg/util/yaml"
+)
+
+// LoadPricing returns prices of extended resources from the given JSON or YAML file
+func LoadPricing(path string) (models.ExtendedResourcePricing, error) {
+	pricing := models.ExtendedResourcePricing{}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return pricing, err
+	}
+	jsonData, err := yaml.ToJSON(data)
+	if err != nil {
+		return pricing, err
+	}
+	if err = json.Unmarshal(jsonData, &pricing); err != nil {
+		return pricing, err
+	}
+	for name, price := range pricing.Prices {
+		if price < 0 {
+			return pricing, fmt.Errorf("negative price: %v of extended resource: %s", price, name)
+		}
+	}
+	return pricing, nil
+}
+
+// Configure sets prices of extended resources from the given file, extended resources are not priced if path is empty
+func Configure(path string) error {
+	if path == "" {
+		return nil
+	}
+	pricing, err := LoadPricing(path)
+	if err != nil {
+		return err
+	}
+	models.SetExtendedResourcePricing(pricing)
+	logrus.Infof("loaded prices of %d extended resources from: %s", len(pricing.Prices), path)
+	return nil
+}
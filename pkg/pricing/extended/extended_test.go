@@ -0,0 +1,60 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package extended
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/vmware/purser/pkg/controller/dgraph/models"
+	"github.com/vmware/purser/test/utils"
+)
+
+func writePricing(t *testing.T, content string) string {
+	file, err := ioutil.TempFile("", "extended")
+	utils.Ok(t, err)
+	_, err = file.WriteString(content)
+	utils.Ok(t, err)
+	utils.Ok(t, file.Close())
+	return file.Name()
+}
+
+func TestLoadPricing(t *testing.T) {
+	path := writePricing(t, `
+prices:
+  xilinx.com/fpga: 1.65
+  smarter-devices/*: 0.05
+`)
+	defer os.Remove(path)
+
+	pricing, err := LoadPricing(path)
+	utils.Ok(t, err)
+	expected := models.ExtendedResourcePricing{
+		Prices: map[string]float64{"xilinx.com/fpga": 1.65, "smarter-devices/*": 0.05},
+	}
+	utils.Equals(t, expected, pricing)
+}
+
+func TestLoadPricingWithNegativePrice(t *testing.T) {
+	path := writePricing(t, `{"prices": {"xilinx.com/fpga": -1}}`)
+	defer os.Remove(path)
+
+	_, err := LoadPricing(path)
+	utils.Assert(t, err != nil, "expected error for negative price")
+}
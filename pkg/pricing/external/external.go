@@ -0,0 +1,163 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package external
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/vmware/purser/pkg/controller/dgraph/models"
+	"github.com/vmware/purser/pkg/controller/dgraph/models/query"
+	"github.com/vmware/purser/pkg/pricing/adjustment"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ProviderName is the name used to select external cost model as pricing provider(--pricingProviders)
+// and as cost adjustment type(adjustments config)
+const ProviderName = "external"
+
+// Client delegates price lookup and cost adjustment to an external gRPC service implementing costmodel.proto
+// (costmodel.pb.go is generated by protoc --go_out=plugins=grpc:. costmodel.proto)
+type Client struct {
+	conn    *grpc.ClientConn
+	client  CostModelClient
+	timeout time.Duration
+}
+
+// Dial returns a client connected to the external cost model service at the given address
+func Dial(address string, timeout time.Duration) (*Client, error) {
+	conn, err := grpc.Dial(address, grpc.WithInsecure())
+	if err != nil {
+		return nil, err
+	}
+	return &Client{conn: conn, client: NewCostModelClient(conn), timeout: timeout}, nil
+}
+
+// Configure connects to the external cost model service and registers it as pricing provider
+// and cost adjustment type. It does nothing if address is empty.
+func Configure(address string, timeout time.Duration) error {
+	if address == "" {
+		return nil
+	}
+	client, err := Dial(address, timeout)
+	if err != nil {
+		return err
+	}
+	models.RegisterPricingProvider(client)
+	adjustment.RegisterType(ProviderName, func(spec adjustment.Spec) (query.CostAdjustment, error) {
+		return client, nil
+	})
+	logrus.Infof("external cost model: %s is registered", address)
+	return nil
+}
+
+// Close closes connection to the external cost model service
+func (c *Client) Close() {
+	err := c.conn.Close()
+	if err != nil {
+		logrus.Errorf("Error while closing connection to external cost model: %v", err)
+	}
+}
+
+// Name returns name of the provider
+func (c *Client) Name() string {
+	return ProviderName
+}
+
+// GetNodePrice returns rates of the node from the external cost model
+func (c *Client) GetNodePrice(node models.Node) (*models.NodeRates, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+
+	request := &NodePriceRequest{
+		Node: &Node{
+			Name:           node.Name,
+			InstanceType:   node.InstanceType,
+			Os:             node.OS,
+			CpuCapacity:    node.CPUCapacity,
+			MemoryCapacity: node.MemoryCapacity,
+		},
+	}
+	response, err := c.client.GetNodePrice(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	if !response.Found {
+		return nil, fmt.Errorf("external cost model has no price for node: %s", node.Name)
+	}
+	return &models.NodeRates{CPUPrice: response.CpuPrice, MemoryPrice: response.MemoryPrice}, nil
+}
+
+// Adjust returns the cost adjusted by external cost model, cost is returned unchanged on failure
+func (c *Client) Adjust(costCtx query.CostContext, cost float64) float64 {
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+	return c.adjust(ctx, costCtx, cost)
+}
+
+// AdjustAll returns the costs of a response adjusted by external cost model in one request, costs are returned
+// unchanged on failure. Costs are adjusted one by one within the same timeout if the service does not implement
+// AdjustCosts.
+func (c *Client) AdjustAll(costCtxs []query.CostContext, costs []float64) []float64 {
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+
+	request := &AdjustCostsRequest{}
+	for index, costCtx := range costCtxs {
+		request.Costs = append(request.Costs, newAdjustCostRequest(costCtx, costs[index]))
+	}
+	response, err := c.client.AdjustCosts(ctx, request)
+	if status.Code(err) == codes.Unimplemented {
+		adjusted := make([]float64, len(costs))
+		for index, costCtx := range costCtxs {
+			adjusted[index] = c.adjust(ctx, costCtx, costs[index])
+		}
+		return adjusted
+	}
+	if err != nil {
+		logrus.Errorf("unable to adjust costs through external cost model, err: %v", err)
+		return costs
+	}
+	if len(response.Costs) != len(costs) {
+		logrus.Errorf("external cost model returned %d costs for %d costs", len(response.Costs), len(costs))
+		return costs
+	}
+	return response.Costs
+}
+
+func (c *Client) adjust(ctx context.Context, costCtx query.CostContext, cost float64) float64 {
+	response, err := c.client.AdjustCost(ctx, newAdjustCostRequest(costCtx, cost))
+	if err != nil {
+		logrus.Errorf("unable to adjust cost through external cost model, err: %v", err)
+		return cost
+	}
+	return response.Cost
+}
+
+func newAdjustCostRequest(costCtx query.CostContext, cost float64) *AdjustCostRequest {
+	return &AdjustCostRequest{
+		ResourceType: costCtx.ResourceType,
+		ResourceName: costCtx.ResourceName,
+		CostType:     costCtx.CostType,
+		Cost:         cost,
+	}
+}
@@ -0,0 +1,96 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package external
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/vmware/purser/pkg/controller/dgraph/models/query"
+	"github.com/vmware/purser/test/utils"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type costModel struct {
+	isBatch     bool
+	adjustCalls int
+	batchCalls  int
+}
+
+func (m *costModel) GetNodePrice(ctx context.Context, request *NodePriceRequest) (*NodePriceResponse, error) {
+	return &NodePriceResponse{Found: true, CpuPrice: 0.03, MemoryPrice: 0.004}, nil
+}
+
+func (m *costModel) AdjustCost(ctx context.Context, request *AdjustCostRequest) (*AdjustCostResponse, error) {
+	m.adjustCalls++
+	return &AdjustCostResponse{Cost: request.Cost * 2}, nil
+}
+
+func (m *costModel) AdjustCosts(ctx context.Context, request *AdjustCostsRequest) (*AdjustCostsResponse, error) {
+	if !m.isBatch {
+		return nil, status.Error(codes.Unimplemented, "AdjustCosts is not implemented")
+	}
+	m.batchCalls++
+	response := &AdjustCostsResponse{}
+	for _, cost := range request.Costs {
+		response.Costs = append(response.Costs, cost.Cost*2)
+	}
+	return response, nil
+}
+
+func startCostModel(t *testing.T, model *costModel) (*Client, func()) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	utils.Ok(t, err)
+	server := grpc.NewServer()
+	RegisterCostModelServer(server, model)
+	go server.Serve(listener)
+	client, err := Dial(listener.Addr().String(), 5*time.Second)
+	utils.Ok(t, err)
+	return client, func() {
+		client.Close()
+		server.Stop()
+	}
+}
+
+func TestAdjustAll(t *testing.T) {
+	model := &costModel{isBatch: true}
+	client, stop := startCostModel(t, model)
+	defer stop()
+
+	ctxs := []query.CostContext{
+		{ResourceType: "pod", ResourceName: "pod-web", CostType: query.CPUCostType},
+		{ResourceType: "pod", ResourceName: "pod-web", CostType: query.MemoryCostType},
+	}
+	utils.Equals(t, []float64{2, 4}, client.AdjustAll(ctxs, []float64{1, 2}))
+	utils.Equals(t, 1, model.batchCalls)
+	utils.Equals(t, 0, model.adjustCalls)
+}
+
+func TestAdjustAllWithoutBatchRPC(t *testing.T) {
+	model := &costModel{}
+	client, stop := startCostModel(t, model)
+	defer stop()
+
+	ctxs := []query.CostContext{{ResourceType: "pod", CostType: query.CPUCostType}, {ResourceType: "pod", CostType: query.MemoryCostType}}
+	utils.Equals(t, []float64{2, 4}, client.AdjustAll(ctxs, []float64{1, 2}))
+	utils.Equals(t, 2, model.adjustCalls)
+	utils.Equals(t, 3.0, client.Adjust(ctxs[0], 1.5))
+}
@@ -0,0 +1,206 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gcp
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/vmware/purser/pkg/controller/dgraph"
+	"github.com/vmware/purser/pkg/controller/dgraph/models"
+)
+
+// GCP specific constants
+const (
+	computeFamily = "Compute"
+	storageFamily = "Storage"
+	onDemand      = "OnDemand"
+	hour          = "h"
+	gbHour        = "GiBy.h"
+	gbMonth       = "GiBy.mo"
+	core          = "core"
+	ram           = "ram"
+	deliminator   = "-"
+	linux         = "Linux"
+)
+
+// predefinedInstancePattern matches descriptions of cpu and memory SKUs of predefined machine types of a machine
+// family, ex: "N1 Predefined Instance Core running in Americas", "E2 Instance Ram running in Virginia"
+var predefinedInstancePattern = regexp.MustCompile(`^(\w+) (?:Predefined )?Instance (Core|Ram) running in`)
+
+// storageDescriptions maps description prefixes of persistent disk capacity SKUs to disk types
+var storageDescriptions = map[string]string{
+	"Storage PD Capacity":    "pd-standard",
+	"Balanced PD Capacity":   "pd-balanced",
+	"SSD backed PD Capacity": "pd-ssd",
+}
+
+// GetRateCardForGCP takes region(ex: us-east1) and Cloud Billing API key as input and returns RateCard
+func GetRateCardForGCP(region, apiKey string) *models.RateCard {
+	skus, err := GetGCPPricing(apiKey)
+	if err == nil {
+		return convertGCPPricingToPurserRateCard(region, skus)
+	}
+	return nil
+}
+
+func convertGCPPricingToPurserRateCard(region string, skus []SKU) *models.RateCard {
+	nodePrices, storagePrices := getResourcePricesFromSKUs(region, skus)
+	return &models.RateCard{
+		ID:            dgraph.ID{Xid: models.RateCardXID},
+		IsRateCard:    true,
+		CloudProvider: models.GCP,
+		Region:        region,
+		NodePrices:    storeNodePrices(nodePrices),
+		StoragePrices: storeStoragePrices(storagePrices),
+	}
+}
+
+// getResourcePricesFromSKUs returns prices per cpu and per GB of memory of each machine family(ex: n1, e2) and price
+// per GB of each persistent disk type in the region. GCP prices predefined machine types by their cpus and memory,
+// so families are priced instead of machine types and looked up by the family of the instance type of nodes.
+func getResourcePricesFromSKUs(region string, skus []SKU) ([]*models.NodePrice, []*models.StoragePrice) {
+	familyPrices := make(map[string]*models.NodePrice)
+	var families []string
+	var storagePrices []*models.StoragePrice
+	for _, sku := range skus {
+		if sku.Category.UsageType != onDemand || !isInRegion(sku, region) {
+			continue
+		}
+		price, unit := getSKUPrice(sku)
+		if price == models.PriceError {
+			continue
+		}
+		switch sku.Category.ResourceFamily {
+		case computeFamily:
+			match := predefinedInstancePattern.FindStringSubmatch(sku.Description)
+			if match == nil {
+				continue
+			}
+			family := strings.ToLower(match[1])
+			nodePrice, isPresent := familyPrices[family]
+			if !isPresent {
+				nodePrice = &models.NodePrice{
+					ID:              dgraph.ID{Xid: family + deliminator + linux},
+					IsNodePrice:     true,
+					InstanceType:    family,
+					InstanceFamily:  family,
+					OperatingSystem: linux,
+				}
+				familyPrices[family] = nodePrice
+				families = append(families, family)
+			}
+			if strings.ToLower(match[2]) == core && unit == hour {
+				nodePrice.PricePerCPU = price
+			} else if strings.ToLower(match[2]) == ram && unit == gbHour {
+				nodePrice.PricePerMemory = price
+			}
+		case storageFamily:
+			if storagePrice := getStoragePrice(sku, price, unit); storagePrice != nil {
+				storagePrices = append(storagePrices, storagePrice)
+			}
+		}
+	}
+
+	var nodePrices []*models.NodePrice
+	for _, family := range families {
+		if nodePrice := familyPrices[family]; nodePrice.PricePerCPU > 0 && nodePrice.PricePerMemory > 0 {
+			nodePrices = append(nodePrices, nodePrice)
+		}
+	}
+	return nodePrices, storagePrices
+}
+
+func isInRegion(sku SKU, region string) bool {
+	for _, serviceRegion := range sku.ServiceRegions {
+		if serviceRegion == region {
+			return true
+		}
+	}
+	return false
+}
+
+// getSKUPrice returns price of the last tier of the SKU and its usage unit, tiers starting at zero usage are free
+// tiers for some SKUs
+func getSKUPrice(sku SKU) (float64, string) {
+	if len(sku.PricingInfo) == 0 {
+		return models.PriceError, ""
+	}
+	expression := sku.PricingInfo[0].PricingExpression
+	if len(expression.TieredRates) == 0 {
+		return models.PriceError, ""
+	}
+	unitPrice := expression.TieredRates[len(expression.TieredRates)-1].UnitPrice
+	units := 0.0
+	if unitPrice.Units != "" {
+		var err error
+		units, err = strconv.ParseFloat(unitPrice.Units, 64)
+		if err != nil {
+			logrus.Errorf("unable to parse string: %s to float. err: %v", unitPrice.Units, err)
+			return models.PriceError, ""
+		}
+	}
+	return units + unitPrice.Nanos/1e9, expression.UsageUnit
+}
+
+func getStoragePrice(sku SKU, price float64, unit string) *models.StoragePrice {
+	for prefix, volumeType := range storageDescriptions {
+		if !strings.HasPrefix(sku.Description, prefix) {
+			continue
+		}
+		if unit == gbMonth {
+			// convert to GBHour
+			price = price / models.HoursInMonth
+		}
+		productXID := volumeType + deliminator + sku.Category.ResourceGroup
+		return &models.StoragePrice{
+			ID:             dgraph.ID{Xid: productXID},
+			IsStoragePrice: true,
+			VolumeType:     volumeType,
+			UsageType:      sku.Category.ResourceGroup,
+			Price:          price,
+		}
+	}
+	return nil
+}
+
+func storeNodePrices(nodePrices []*models.NodePrice) []*models.NodePrice {
+	var stored []*models.NodePrice
+	for _, nodePrice := range nodePrices {
+		uid := models.StoreNodePrice(nodePrice, nodePrice.Xid)
+		if uid != "" {
+			nodePrice.ID = dgraph.ID{UID: uid, Xid: nodePrice.Xid}
+			stored = append(stored, nodePrice)
+		}
+	}
+	return stored
+}
+
+func storeStoragePrices(storagePrices []*models.StoragePrice) []*models.StoragePrice {
+	var stored []*models.StoragePrice
+	for _, storagePrice := range storagePrices {
+		uid := models.StoreStoragePrice(storagePrice, storagePrice.Xid)
+		if uid != "" {
+			storagePrice.ID = dgraph.ID{UID: uid, Xid: storagePrice.Xid}
+			stored = append(stored, storagePrice)
+		}
+	}
+	return stored
+}
@@ -0,0 +1,69 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gcp
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/vmware/purser/pkg/controller/dgraph/models"
+	"github.com/vmware/purser/test/utils"
+)
+
+const testSKUs = `[
+	{"description": "N1 Predefined Instance Core running in Americas", "serviceRegions": ["us-east1", "us-west1"],
+		"category": {"resourceFamily": "Compute", "resourceGroup": "N1Standard", "usageType": "OnDemand"},
+		"pricingInfo": [{"pricingExpression": {"usageUnit": "h", "tieredRates": [{"unitPrice": {"units": "0", "nanos": 31611000}}]}}]},
+	{"description": "N1 Predefined Instance Ram running in Americas", "serviceRegions": ["us-east1", "us-west1"],
+		"category": {"resourceFamily": "Compute", "resourceGroup": "N1Standard", "usageType": "OnDemand"},
+		"pricingInfo": [{"pricingExpression": {"usageUnit": "GiBy.h", "tieredRates": [{"unitPrice": {"units": "0", "nanos": 4237000}}]}}]},
+	{"description": "Preemptible N1 Predefined Instance Core running in Americas", "serviceRegions": ["us-east1"],
+		"category": {"resourceFamily": "Compute", "resourceGroup": "N1Standard", "usageType": "Preemptible"},
+		"pricingInfo": [{"pricingExpression": {"usageUnit": "h", "tieredRates": [{"unitPrice": {"units": "0", "nanos": 6655000}}]}}]},
+	{"description": "E2 Instance Core running in Belgium", "serviceRegions": ["europe-west1"],
+		"category": {"resourceFamily": "Compute", "resourceGroup": "CPU", "usageType": "OnDemand"},
+		"pricingInfo": [{"pricingExpression": {"usageUnit": "h", "tieredRates": [{"unitPrice": {"units": "0", "nanos": 23030000}}]}}]},
+	{"description": "N1 Custom Instance Core running in Americas", "serviceRegions": ["us-east1"],
+		"category": {"resourceFamily": "Compute", "resourceGroup": "CPU", "usageType": "OnDemand"},
+		"pricingInfo": [{"pricingExpression": {"usageUnit": "h", "tieredRates": [{"unitPrice": {"units": "0", "nanos": 33174000}}]}}]},
+	{"description": "Storage PD Capacity", "serviceRegions": ["us-east1"],
+		"category": {"resourceFamily": "Storage", "resourceGroup": "PDStandard", "usageType": "OnDemand"},
+		"pricingInfo": [{"pricingExpression": {"usageUnit": "GiBy.mo", "tieredRates": [{"unitPrice": {"units": "0", "nanos": 40000000}}]}}]}
+]`
+
+func TestGetResourcePricesFromSKUs(t *testing.T) {
+	var skus []SKU
+	utils.Ok(t, json.Unmarshal([]byte(testSKUs), &skus))
+	nodePrices, storagePrices := getResourcePricesFromSKUs("us-east1", skus)
+
+	utils.Equals(t, 1, len(nodePrices))
+	utils.Equals(t, "n1-Linux", nodePrices[0].Xid)
+	utils.Equals(t, "n1", nodePrices[0].InstanceType)
+	utils.Equals(t, 0.031611, nodePrices[0].PricePerCPU)
+	utils.Equals(t, 0.004237, nodePrices[0].PricePerMemory)
+
+	utils.Equals(t, 1, len(storagePrices))
+	utils.Equals(t, "pd-standard", storagePrices[0].VolumeType)
+	monthlyPrice := 0.04
+	utils.Equals(t, monthlyPrice/models.HoursInMonth, storagePrices[0].Price)
+}
+
+func TestGetSKUPriceWithoutTiers(t *testing.T) {
+	price, _ := getSKUPrice(SKU{PricingInfo: []PricingInfo{{}}})
+	utils.Equals(t, models.PriceError, price)
+}
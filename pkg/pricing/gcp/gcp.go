@@ -0,0 +1,109 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gcp
+
+import (
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/vmware/purser/pkg/controller/utils"
+)
+
+const (
+	httpTimeout = 100 * time.Second
+	// computeEngineServiceID is the id of Compute Engine service in Cloud Billing Catalog
+	computeEngineServiceID = "6F81-5844-456A"
+	catalogURL             = "https://cloudbilling.googleapis.com/v1/services/" + computeEngineServiceID + "/skus"
+)
+
+// SKUList structure
+type SKUList struct {
+	Skus          []SKU  `json:"skus"`
+	NextPageToken string `json:"nextPageToken"`
+}
+
+// SKU structure
+type SKU struct {
+	Description    string        `json:"description"`
+	Category       Category      `json:"category"`
+	ServiceRegions []string      `json:"serviceRegions"`
+	PricingInfo    []PricingInfo `json:"pricingInfo"`
+}
+
+// Category structure
+type Category struct {
+	ResourceFamily string `json:"resourceFamily"`
+	ResourceGroup  string `json:"resourceGroup"`
+	UsageType      string `json:"usageType"`
+}
+
+// PricingInfo structure
+type PricingInfo struct {
+	PricingExpression PricingExpression `json:"pricingExpression"`
+}
+
+// PricingExpression structure
+type PricingExpression struct {
+	UsageUnit   string       `json:"usageUnit"`
+	TieredRates []TieredRate `json:"tieredRates"`
+}
+
+// TieredRate structure
+type TieredRate struct {
+	StartUsageAmount float64 `json:"startUsageAmount"`
+	UnitPrice        Money   `json:"unitPrice"`
+}
+
+// Money structure, price is units + nanos/10^9
+type Money struct {
+	CurrencyCode string  `json:"currencyCode"`
+	Units        string  `json:"units"`
+	Nanos        float64 `json:"nanos"`
+}
+
+// GetGCPPricing retrieves all Compute Engine SKUs from Cloud Billing Catalog API using the API key
+func GetGCPPricing(apiKey string) ([]SKU, error) {
+	var myClient = &http.Client{Timeout: httpTimeout}
+	var skus []SKU
+	pageToken := ""
+	for {
+		page := SKUList{}
+		err := utils.GetJSONResponse(myClient, getURLForPage(apiKey, pageToken), &page)
+		if err != nil {
+			logrus.Errorf("Unable to get gcp pricing. Reason: %v", err)
+			return nil, err
+		}
+		skus = append(skus, page.Skus...)
+		if page.NextPageToken == "" {
+			return skus, nil
+		}
+		pageToken = page.NextPageToken
+	}
+}
+
+func getURLForPage(apiKey, pageToken string) string {
+	params := url.Values{}
+	params.Set("key", apiKey)
+	params.Set("currencyCode", "USD")
+	if pageToken != "" {
+		params.Set("pageToken", pageToken)
+	}
+	return catalogURL + "?" + params.Encode()
+}
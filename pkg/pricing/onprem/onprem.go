@@ -0,0 +1,133 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package onprem
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/vmware/purser/pkg/controller/dgraph/models"
+	"k8s.io/apimachinery/pkg/util/yaml"
+)
+
+// ProviderName is the name used to select on-prem pricing provider
+const ProviderName = "onprem"
+
+// HoursPerYear is the number of hours over which a year of depreciation is amortized
+const HoursPerYear = 365 * 24
+
+// anyInstanceType is used in machine entries which apply to nodes of instance types without an entry of their own
+const anyInstanceType = "*"
+
+// Config structure
+// Costs should be in USD($), power in watts and electricity price per kWh
+type Config struct {
+	// ElectricityPrice is the price of a kWh
+	ElectricityPrice float64 `json:"electricityPrice"`
+	// PUE is power usage effectiveness of the datacenter, total power per power of machines(ex: 1.5 for cooling)
+	PUE float64 `json:"pue,omitempty"`
+	// Overhead is the fraction of amortized hardware cost added for racks, network, staff and maintenance
+	Overhead float64   `json:"overhead,omitempty"`
+	Machines []Machine `json:"machines"`
+}
+
+// Machine structure
+type Machine struct {
+	InstanceType      string  `json:"instanceType"`
+	CapitalCost       float64 `json:"capitalCost"`
+	DepreciationYears float64 `json:"depreciationYears"`
+	// Power is the average power draw of the machine in watts
+	Power float64 `json:"power,omitempty"`
+}
+
+// Provider prices nodes of bare metal and on-prem clusters by amortizing capital cost of their machines over the
+// depreciation period and adding overhead and cost of power
+type Provider struct {
+	prices map[string]float64
+}
+
+// NewProvider returns an on-prem pricing provider for the given config, an error if the config is invalid
+func NewProvider(config Config) (*Provider, error) {
+	if config.ElectricityPrice < 0 || config.Overhead < 0 {
+		return nil, fmt.Errorf("electricity price: %v and overhead: %v can't be negative", config.ElectricityPrice, config.Overhead)
+	}
+	pue := config.PUE
+	if pue == 0 {
+		pue = 1
+	} else if pue < 1 {
+		return nil, fmt.Errorf("pue: %v can't be less than 1", pue)
+	}
+
+	prices := make(map[string]float64)
+	for _, machine := range config.Machines {
+		if machine.InstanceType == "" {
+			return nil, fmt.Errorf("instanceType of machine is missing")
+		}
+		if machine.CapitalCost < 0 || machine.Power < 0 || machine.DepreciationYears <= 0 {
+			return nil, fmt.Errorf("invalid machine: %s, capital cost and power can't be negative and depreciation years must be positive", machine.InstanceType)
+		}
+		amortizedCost := machine.CapitalCost / (machine.DepreciationYears * HoursPerYear)
+		powerCost := machine.Power / 1000 * pue * config.ElectricityPrice
+		prices[machine.InstanceType] = amortizedCost*(1+config.Overhead) + powerCost
+	}
+	return &Provider{prices: prices}, nil
+}
+
+// NewProviderFromFile returns an on-prem pricing provider for the config in the given JSON or YAML file
+func NewProviderFromFile(path string) (*Provider, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	jsonData, err := yaml.ToJSON(data)
+	if err != nil {
+		return nil, err
+	}
+	config := Config{}
+	if err = json.Unmarshal(jsonData, &config); err != nil {
+		return nil, err
+	}
+	logrus.Infof("loaded %d machines from on-prem pricing config: %s", len(config.Machines), path)
+	return NewProvider(config)
+}
+
+// Name returns name of the provider
+func (p *Provider) Name() string {
+	return ProviderName
+}
+
+// GetNodePrice returns rates of the hourly price of node's instance type, falls back to the price of machines of any
+// instance type. The price is split between cpus and memory of the node like prices of rate cards.
+func (p *Provider) GetNodePrice(node models.Node) (*models.NodeRates, error) {
+	price, isPresent := p.prices[node.InstanceType]
+	if !isPresent {
+		price, isPresent = p.prices[anyInstanceType]
+	}
+	if !isPresent {
+		return nil, fmt.Errorf("no machine in on-prem pricing config for instanceType: %s", node.InstanceType)
+	}
+	if node.CPUCapacity <= 0 || node.MemoryCapacity <= 0 {
+		return nil, fmt.Errorf("on-prem price can't be split for node: %s without capacity", node.Name)
+	}
+	return &models.NodeRates{
+		CPUPrice:    models.NodePriceSplitRatio * price / node.CPUCapacity,
+		MemoryPrice: (1 - models.NodePriceSplitRatio) * price / node.MemoryCapacity,
+	}, nil
+}
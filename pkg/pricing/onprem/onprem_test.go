@@ -0,0 +1,73 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package onprem
+
+import (
+	"testing"
+
+	"github.com/vmware/purser/pkg/controller/dgraph/models"
+	"github.com/vmware/purser/test/utils"
+)
+
+func TestGetNodePrice(t *testing.T) {
+	provider, err := NewProvider(Config{
+		ElectricityPrice: 0.1,
+		PUE:              1.5,
+		Overhead:         0.25,
+		Machines: []Machine{
+			{InstanceType: "r640", CapitalCost: 17520, DepreciationYears: 4, Power: 400},
+			{InstanceType: "*", CapitalCost: 8760, DepreciationYears: 5},
+		},
+	})
+	utils.Ok(t, err)
+
+	// 17520/(4*8760)*1.25 + 0.4*1.5*0.1 = 0.685
+	rates, err := provider.GetNodePrice(models.Node{Name: "node-1", InstanceType: "r640", CPUCapacity: 0.5, MemoryCapacity: 0.5})
+	utils.Ok(t, err)
+	utils.Assert(t, rates.CPUPrice > 0.68499 && rates.CPUPrice < 0.68501, "unexpected cpu price: %v", rates.CPUPrice)
+	utils.Equals(t, rates.CPUPrice, rates.MemoryPrice)
+
+	// 8760/(5*8760)*1.25 = 0.25
+	rates, err = provider.GetNodePrice(models.Node{Name: "node-2", InstanceType: models.DefaultNodeInstance, CPUCapacity: 1, MemoryCapacity: 0.5})
+	utils.Ok(t, err)
+	utils.Equals(t, &models.NodeRates{CPUPrice: 0.125, MemoryPrice: 0.25}, rates)
+
+	_, err = provider.GetNodePrice(models.Node{Name: "node-3", InstanceType: "r640"})
+	utils.Assert(t, err != nil, "expected error for node without capacity")
+}
+
+func TestNewProviderValidatesConfig(t *testing.T) {
+	_, err := NewProvider(Config{PUE: 0.8})
+	utils.Assert(t, err != nil, "expected error for pue less than 1")
+
+	_, err = NewProvider(Config{Machines: []Machine{{InstanceType: "r640", CapitalCost: 12000}}})
+	utils.Assert(t, err != nil, "expected error for machine without depreciation period")
+
+	_, err = NewProvider(Config{Machines: []Machine{{CapitalCost: 12000, DepreciationYears: 4}}})
+	utils.Assert(t, err != nil, "expected error for machine without instance type")
+
+	_, err = NewProvider(Config{Machines: []Machine{{InstanceType: "*", CapitalCost: 12000, DepreciationYears: 4}}})
+	utils.Ok(t, err)
+}
+
+func TestGetNodePriceWithoutMachine(t *testing.T) {
+	provider, err := NewProvider(Config{Machines: []Machine{{InstanceType: "r640", CapitalCost: 12000, DepreciationYears: 4}}})
+	utils.Ok(t, err)
+	_, err = provider.GetNodePrice(models.Node{Name: "node-1", InstanceType: "r740", CPUCapacity: 4, MemoryCapacity: 16})
+	utils.Assert(t, err != nil, "expected error for instance type missing in config")
+}
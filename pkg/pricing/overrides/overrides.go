@@ -0,0 +1,80 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package overrides
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/vmware/purser/pkg/controller/dgraph/models"
+	"k8s.io/apimachinery/pkg/util/yaml"
+)
+
+// LoadOverrides returns container price overrides from the given JSON or YAML file
+func LoadOverrides(file string) (models.ContainerPriceOverrides, error) {
+	overrides := models.ContainerPriceOverrides{}
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return overrides, err
+	}
+	jsonData, err := yaml.ToJSON(data)
+	if err != nil {
+		return overrides, err
+	}
+	if err = json.Unmarshal(jsonData, &overrides); err != nil {
+		return overrides, err
+	}
+	for index, override := range overrides.Overrides {
+		if err = validate(override); err != nil {
+			return overrides, fmt.Errorf("invalid override at index %d: %v", index, err)
+		}
+	}
+	return overrides, nil
+}
+
+func validate(override models.ContainerPriceOverride) error {
+	if override.Image == "" && override.Name == "" {
+		return fmt.Errorf("image or name pattern is required")
+	}
+	if override.CPUPrice < 0 || override.MemoryPrice < 0 {
+		return fmt.Errorf("prices can't be negative")
+	}
+	for _, pattern := range []string{override.Image, override.Name} {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("pattern: %s, err: %v", pattern, err)
+		}
+	}
+	return nil
+}
+
+// Configure sets container price overrides from the given file, containers take prices of their pods if file is empty
+func Configure(file string) error {
+	if file == "" {
+		return nil
+	}
+	overrides, err := LoadOverrides(file)
+	if err != nil {
+		return err
+	}
+	models.SetContainerPriceOverrides(overrides)
+	logrus.Infof("loaded %d container price overrides from: %s", len(overrides.Overrides), file)
+	return nil
+}
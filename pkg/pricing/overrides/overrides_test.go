@@ -0,0 +1,69 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package overrides
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/vmware/purser/pkg/controller/dgraph/models"
+	"github.com/vmware/purser/test/utils"
+)
+
+func writeOverrides(t *testing.T, content string) string {
+	file, err := ioutil.TempFile("", "overrides")
+	utils.Ok(t, err)
+	_, err = file.WriteString(content)
+	utils.Ok(t, err)
+	utils.Ok(t, file.Close())
+	return file.Name()
+}
+
+func TestLoadOverrides(t *testing.T) {
+	file := writeOverrides(t, `
+overrides:
+- image: nvcr.io/nvidia/*
+  cpuPrice: 0.5
+  memoryPrice: 0.05
+- name: sidecar-*
+  cpuPrice: 0.01
+`)
+	defer os.Remove(file)
+
+	overrides, err := LoadOverrides(file)
+	utils.Ok(t, err)
+	expected := models.ContainerPriceOverrides{Overrides: []models.ContainerPriceOverride{
+		{Image: "nvcr.io/nvidia/*", CPUPrice: 0.5, MemoryPrice: 0.05},
+		{Name: "sidecar-*", CPUPrice: 0.01},
+	}}
+	utils.Equals(t, expected, overrides)
+}
+
+func TestLoadOverridesWithInvalidOverride(t *testing.T) {
+	for _, content := range []string{
+		`{"overrides": [{"cpuPrice": 0.5}]}`,
+		`{"overrides": [{"name": "web", "cpuPrice": -1}]}`,
+		`{"overrides": [{"image": "[nginx", "cpuPrice": 0.5}]}`,
+	} {
+		file := writeOverrides(t, content)
+		_, err := LoadOverrides(file)
+		os.Remove(file)
+		utils.Assert(t, err != nil, "expected error for overrides: %s", content)
+	}
+}
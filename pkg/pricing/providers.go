@@ -0,0 +1,61 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package pricing
+
+import (
+	"strings"
+
+	"github.com/vmware/purser/pkg/controller/dgraph/models"
+	"github.com/vmware/purser/pkg/pricing/onprem"
+	"github.com/vmware/purser/pkg/pricing/static"
+	"github.com/vmware/purser/pkg/pricing/vsphere"
+)
+
+// ConfigurePricingProviders registers the configurable providers and selects providers
+// given as comma separated names(ex: static,ratecard) in the order of preference.
+func ConfigurePricingProviders(providers, staticCatalogPath, onPremConfigPath, vSphereConfigPath string) error {
+	if staticCatalogPath != "" {
+		provider, err := static.NewProviderFromFile(staticCatalogPath)
+		if err != nil {
+			return err
+		}
+		models.RegisterPricingProvider(provider)
+	}
+	if onPremConfigPath != "" {
+		provider, err := onprem.NewProviderFromFile(onPremConfigPath)
+		if err != nil {
+			return err
+		}
+		models.RegisterPricingProvider(provider)
+	}
+	if vSphereConfigPath != "" {
+		provider, err := vsphere.NewProviderFromFile(vSphereConfigPath)
+		if err != nil {
+			return err
+		}
+		models.RegisterPricingProvider(provider)
+	}
+
+	var names []string
+	for _, name := range strings.Split(providers, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return models.SelectPricingProviders(names)
+}
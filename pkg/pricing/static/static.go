@@ -0,0 +1,107 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package static
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/vmware/purser/pkg/controller/dgraph/models"
+	"k8s.io/apimachinery/pkg/util/yaml"
+)
+
+// ProviderName is the name used to select static catalog pricing provider
+const ProviderName = "static"
+
+// anyOS is used in catalog entries which apply to an instance type irrespective of its os
+const anyOS = "*"
+
+// Catalog structure
+// Prices should be in USD($)-(per unit resource)-(per Hour)
+type Catalog struct {
+	NodePrices []CatalogNodePrice `json:"nodePrices"`
+}
+
+// CatalogNodePrice structure
+type CatalogNodePrice struct {
+	InstanceType    string  `json:"instanceType"`
+	OperatingSystem string  `json:"operatingSystem,omitempty"`
+	CPUPrice        float64 `json:"cpuPrice"`
+	MemoryPrice     float64 `json:"memoryPrice"`
+}
+
+// Provider prices nodes from a static catalog, ex: customer-internal rate cards
+type Provider struct {
+	rates map[string]models.NodeRates
+}
+
+// NewProvider returns a static pricing provider for the given catalog
+func NewProvider(catalog Catalog) *Provider {
+	rates := make(map[string]models.NodeRates)
+	for _, nodePrice := range catalog.NodePrices {
+		os := nodePrice.OperatingSystem
+		if os == "" {
+			os = anyOS
+		}
+		rates[getCatalogKey(nodePrice.InstanceType, os)] = models.NodeRates{
+			CPUPrice:    nodePrice.CPUPrice,
+			MemoryPrice: nodePrice.MemoryPrice,
+		}
+	}
+	return &Provider{rates: rates}
+}
+
+// NewProviderFromFile returns a static pricing provider for the catalog in the given JSON or YAML file
+func NewProviderFromFile(path string) (*Provider, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	jsonData, err := yaml.ToJSON(data)
+	if err != nil {
+		return nil, err
+	}
+	catalog := Catalog{}
+	if err = json.Unmarshal(jsonData, &catalog); err != nil {
+		return nil, err
+	}
+	logrus.Infof("loaded %d node prices from static catalog: %s", len(catalog.NodePrices), path)
+	return NewProvider(catalog), nil
+}
+
+// Name returns name of the provider
+func (p *Provider) Name() string {
+	return ProviderName
+}
+
+// GetNodePrice returns rates of node's instance type and os, falls back to rates of instance type for any os
+func (p *Provider) GetNodePrice(node models.Node) (*models.NodeRates, error) {
+	if rates, isPresent := p.rates[getCatalogKey(node.InstanceType, node.OS)]; isPresent {
+		return &rates, nil
+	}
+	if rates, isPresent := p.rates[getCatalogKey(node.InstanceType, anyOS)]; isPresent {
+		return &rates, nil
+	}
+	return nil, fmt.Errorf("no price in static catalog for instanceType: %s, os: %s", node.InstanceType, node.OS)
+}
+
+func getCatalogKey(instanceType, os string) string {
+	return instanceType + "-" + os
+}
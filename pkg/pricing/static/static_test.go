@@ -0,0 +1,45 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package static
+
+import (
+	"testing"
+
+	"github.com/vmware/purser/pkg/controller/dgraph/models"
+	"github.com/vmware/purser/test/utils"
+)
+
+func TestGetNodePrice(t *testing.T) {
+	provider := NewProvider(Catalog{
+		NodePrices: []CatalogNodePrice{
+			{InstanceType: "m4.large", OperatingSystem: "linux", CPUPrice: 0.02, MemoryPrice: 0.005},
+			{InstanceType: "m4.large", CPUPrice: 0.04, MemoryPrice: 0.01},
+		},
+	})
+
+	rates, err := provider.GetNodePrice(models.Node{InstanceType: "m4.large", OS: "linux"})
+	utils.Ok(t, err)
+	utils.Equals(t, &models.NodeRates{CPUPrice: 0.02, MemoryPrice: 0.005}, rates)
+
+	rates, err = provider.GetNodePrice(models.Node{InstanceType: "m4.large", OS: "windows"})
+	utils.Ok(t, err)
+	utils.Equals(t, &models.NodeRates{CPUPrice: 0.04, MemoryPrice: 0.01}, rates)
+
+	_, err = provider.GetNodePrice(models.Node{InstanceType: "t2.micro", OS: "linux"})
+	utils.Assert(t, err != nil, "expected error for instance type missing in catalog")
+}
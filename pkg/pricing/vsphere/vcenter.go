@@ -0,0 +1,164 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vsphere
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/Sirupsen/logrus"
+)
+
+const (
+	sessionPath   = "/rest/com/vmware/cis/session"
+	clusterPath   = "/rest/vcenter/cluster"
+	hostPath      = "/rest/vcenter/host"
+	vmPath        = "/rest/vcenter/vm"
+	sessionHeader = "vmware-api-session-id"
+	poweredOn     = "POWERED_ON"
+)
+
+// vcenter is a client of vSphere Automation REST API of a vCenter
+type vcenter struct {
+	client   *http.Client
+	url      string
+	username string
+	password string
+	session  string
+}
+
+type cluster struct {
+	Cluster string `json:"cluster"`
+	Name    string `json:"name"`
+}
+
+type hostSummary struct {
+	Host string `json:"host"`
+	Name string `json:"name"`
+}
+
+type vmSummary struct {
+	Name          string  `json:"name"`
+	CPUCount      float64 `json:"cpu_count"`
+	MemorySizeMiB float64 `json:"memory_size_MiB"`
+}
+
+// host is an ESXi host with its cluster and powered on VMs
+type host struct {
+	name    string
+	cluster string
+	vms     []vmSummary
+}
+
+// listHosts returns all hosts of the vCenter with powered on VMs running on them, it logs in for the listing and
+// logs out afterwards
+func (v *vcenter) listHosts() ([]host, error) {
+	if err := v.login(); err != nil {
+		return nil, err
+	}
+	defer v.logout()
+
+	clusters := []cluster{}
+	if err := v.get(clusterPath, nil, &clusters); err != nil {
+		return nil, err
+	}
+	clusterOfHost := make(map[string]string)
+	for _, c := range clusters {
+		hosts := []hostSummary{}
+		if err := v.get(hostPath, url.Values{"filter.clusters": {c.Cluster}}, &hosts); err != nil {
+			return nil, err
+		}
+		for _, h := range hosts {
+			clusterOfHost[h.Host] = c.Name
+		}
+	}
+
+	summaries := []hostSummary{}
+	if err := v.get(hostPath, nil, &summaries); err != nil {
+		return nil, err
+	}
+	hosts := []host{}
+	for _, summary := range summaries {
+		vms := []vmSummary{}
+		params := url.Values{"filter.hosts": {summary.Host}, "filter.power_states": {poweredOn}}
+		if err := v.get(vmPath, params, &vms); err != nil {
+			return nil, err
+		}
+		hosts = append(hosts, host{name: summary.Name, cluster: clusterOfHost[summary.Host], vms: vms})
+	}
+	return hosts, nil
+}
+
+func (v *vcenter) login() error {
+	request, err := http.NewRequest(http.MethodPost, v.url+sessionPath, nil)
+	if err != nil {
+		return err
+	}
+	request.SetBasicAuth(v.username, v.password)
+	return v.do(request, &v.session)
+}
+
+func (v *vcenter) logout() {
+	request, err := http.NewRequest(http.MethodDelete, v.url+sessionPath, nil)
+	if err == nil {
+		err = v.do(request, nil)
+	}
+	if err != nil {
+		logrus.Warnf("unable to log out of vCenter: %s, err: %v", v.url, err)
+	}
+	v.session = ""
+}
+
+func (v *vcenter) get(path string, params url.Values, value interface{}) error {
+	requestURL := v.url + path
+	if len(params) > 0 {
+		requestURL += "?" + params.Encode()
+	}
+	request, err := http.NewRequest(http.MethodGet, requestURL, nil)
+	if err != nil {
+		return err
+	}
+	return v.do(request, value)
+}
+
+// do sends the request with the session and decodes value of the response({"value": ...}) into value if it is given
+func (v *vcenter) do(request *http.Request, value interface{}) error {
+	if v.session != "" {
+		request.Header.Set(sessionHeader, v.session)
+	}
+	response, err := v.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := response.Body.Close(); err != nil {
+			logrus.Errorf("unable to close response body of vCenter, err: %v", err)
+		}
+	}()
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s %s returned status: %s", request.Method, request.URL.Path, response.Status)
+	}
+	if value == nil {
+		return nil
+	}
+	return json.NewDecoder(response.Body).Decode(&struct {
+		Value interface{} `json:"value"`
+	}{Value: value})
+}
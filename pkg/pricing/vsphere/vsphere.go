@@ -0,0 +1,228 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vsphere
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/vmware/purser/pkg/controller/dgraph/models"
+	"k8s.io/apimachinery/pkg/util/yaml"
+)
+
+// ProviderName is the name used to select vSphere pricing provider
+const ProviderName = "vsphere"
+
+const (
+	httpTimeout            = 30 * time.Second
+	defaultRefreshInterval = time.Hour
+	mibInGiB               = 1024
+)
+
+// VMCHostTypes are approximate hourly on demand prices per host of VMware Cloud on AWS host types in US regions,
+// used for host types without a price in config. Current, discounted and subscription prices are set in hostTypes.
+var VMCHostTypes = map[string]float64{
+	"i3.metal":   8.3681,
+	"i3en.metal": 13.0208,
+	"i4i.metal":  14.0277,
+}
+
+// Config structure
+// Prices should be in USD($) per host per hour
+type Config struct {
+	// URL of the vCenter(ex: https://vcenter.example.com)
+	URL      string `json:"url"`
+	Username string `json:"username"`
+	Password string `json:"password"`
+	// Insecure skips verification of the certificate of the vCenter, ex: for self-signed certificates
+	Insecure bool `json:"insecure,omitempty"`
+	// RefreshInterval is the duration(ex: 30m) after which hosts and VMs are listed again, 1h by default
+	RefreshInterval string `json:"refreshInterval,omitempty"`
+	// HostType is the host type of hosts of clusters not given in Clusters
+	HostType string `json:"hostType"`
+	// Clusters are host types keyed by name of the cluster
+	Clusters map[string]string `json:"clusters,omitempty"`
+	// HostTypes are prices of host types keyed by host type, they override prices of VMCHostTypes
+	HostTypes map[string]float64 `json:"hostTypes,omitempty"`
+}
+
+// Provider prices nodes running as VMs on vSphere or VMware Cloud on AWS by splitting the price of their host
+// among powered on VMs of the host by their allocated cpus and memory. Hosts are listed from the vCenter API.
+type Provider struct {
+	vcenter         *vcenter
+	hostTypes       map[string]string
+	prices          map[string]float64
+	defaultHostType string
+	refreshInterval time.Duration
+
+	mu          sync.Mutex
+	rates       map[string]*models.NodeRates
+	refreshedAt time.Time
+}
+
+// NewProvider returns a vSphere pricing provider for the given config, an error if the config is invalid
+func NewProvider(config Config) (*Provider, error) {
+	if config.URL == "" {
+		return nil, fmt.Errorf("url of vCenter is missing")
+	}
+	refreshInterval := defaultRefreshInterval
+	if config.RefreshInterval != "" {
+		interval, err := time.ParseDuration(config.RefreshInterval)
+		if err != nil || interval <= 0 {
+			return nil, fmt.Errorf("invalid refresh interval: %s", config.RefreshInterval)
+		}
+		refreshInterval = interval
+	}
+
+	prices := make(map[string]float64)
+	for hostType, price := range VMCHostTypes {
+		prices[hostType] = price
+	}
+	for hostType, price := range config.HostTypes {
+		if price < 0 {
+			return nil, fmt.Errorf("price of host type: %s can't be negative", hostType)
+		}
+		prices[hostType] = price
+	}
+	for cluster, hostType := range config.Clusters {
+		if _, isPresent := prices[hostType]; !isPresent {
+			return nil, fmt.Errorf("no price for host type: %s of cluster: %s", hostType, cluster)
+		}
+	}
+	if _, isPresent := prices[config.HostType]; config.HostType != "" && !isPresent {
+		return nil, fmt.Errorf("no price for host type: %s", config.HostType)
+	}
+
+	client := &http.Client{Timeout: httpTimeout}
+	if config.Insecure {
+		client.Transport = &http.Transport{
+			Proxy:           http.ProxyFromEnvironment,
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		}
+	}
+	return &Provider{
+		vcenter: &vcenter{
+			client:   client,
+			url:      strings.TrimSuffix(config.URL, "/"),
+			username: config.Username,
+			password: config.Password,
+		},
+		hostTypes:       config.Clusters,
+		prices:          prices,
+		defaultHostType: config.HostType,
+		refreshInterval: refreshInterval,
+	}, nil
+}
+
+// NewProviderFromFile returns a vSphere pricing provider for the config in the given JSON or YAML file
+func NewProviderFromFile(path string) (*Provider, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	jsonData, err := yaml.ToJSON(data)
+	if err != nil {
+		return nil, err
+	}
+	config := Config{}
+	if err = json.Unmarshal(jsonData, &config); err != nil {
+		return nil, err
+	}
+	logrus.Infof("loaded vSphere pricing config of vCenter: %s from: %s", config.URL, path)
+	return NewProvider(config)
+}
+
+// Name returns name of the provider
+func (p *Provider) Name() string {
+	return ProviderName
+}
+
+// GetNodePrice returns rates of the VM of the node, VMs are matched by name of the node. Hosts and VMs are listed
+// again if they are older than the refresh interval, rates of the last listing are used if it fails.
+func (p *Provider) GetNodePrice(node models.Node) (*models.NodeRates, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.rates == nil || time.Since(p.refreshedAt) > p.refreshInterval {
+		if err := p.refresh(); err != nil {
+			logrus.Errorf("unable to list hosts and VMs of vCenter: %s, err: %v", p.vcenter.url, err)
+		}
+	}
+
+	name := node.Xid
+	if name == "" {
+		name = strings.TrimPrefix(node.Name, "node-")
+	}
+	rates, isPresent := p.rates[strings.ToLower(name)]
+	if !isPresent {
+		return nil, fmt.Errorf("no powered on VM of vCenter for node: %s", node.Name)
+	}
+	return rates, nil
+}
+
+// refresh computes rates of VMs of all hosts, the time of refresh is updated on errors too so that a vCenter which
+// is down is not called for every node
+func (p *Provider) refresh() error {
+	p.refreshedAt = time.Now()
+	hosts, err := p.vcenter.listHosts()
+	if err != nil {
+		return err
+	}
+	p.rates = p.getRates(hosts)
+	logrus.Infof("priced %d VMs of %d hosts of vCenter: %s", len(p.rates), len(hosts), p.vcenter.url)
+	return nil
+}
+
+// getRates splits the price of each host among its VMs, the cpu part of the price(models.NodePriceSplitRatio) is
+// split by allocated cpus and the rest by allocated memory, so the full price of hosts is assigned to VMs
+func (p *Provider) getRates(hosts []host) map[string]*models.NodeRates {
+	rates := make(map[string]*models.NodeRates)
+	for _, h := range hosts {
+		hostType, isPresent := p.hostTypes[h.cluster]
+		if !isPresent {
+			hostType = p.defaultHostType
+		}
+		price, isPresent := p.prices[hostType]
+		if !isPresent {
+			logrus.Warnf("host: %s of cluster: %s has no host type, its VMs are not priced", h.name, h.cluster)
+			continue
+		}
+
+		cpus, memory := 0.0, 0.0
+		for _, vm := range h.vms {
+			cpus += vm.CPUCount
+			memory += vm.MemorySizeMiB / mibInGiB
+		}
+		if cpus <= 0 || memory <= 0 {
+			continue
+		}
+		for _, vm := range h.vms {
+			rates[strings.ToLower(vm.Name)] = &models.NodeRates{
+				CPUPrice:    models.NodePriceSplitRatio * price / cpus,
+				MemoryPrice: (1 - models.NodePriceSplitRatio) * price / memory,
+			}
+		}
+	}
+	return rates
+}
@@ -0,0 +1,120 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vsphere
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/vmware/purser/pkg/controller/dgraph"
+	"github.com/vmware/purser/pkg/controller/dgraph/models"
+	"github.com/vmware/purser/test/utils"
+)
+
+// newVCenter returns a fake vCenter with host-1 of cluster-1(domain-c1) and standalone host-2
+func newVCenter(t *testing.T) *httptest.Server {
+	responses := map[string]string{
+		clusterPath:                             `{"value": [{"cluster": "domain-c1", "name": "cluster-1"}]}`,
+		hostPath + "?filter.clusters=domain-c1": `{"value": [{"host": "host-1", "name": "esx-1"}]}`,
+		hostPath:                                `{"value": [{"host": "host-1", "name": "esx-1"}, {"host": "host-2", "name": "esx-2"}]}`,
+		vmPath + "?filter.hosts=host-1&filter.power_states=POWERED_ON": `{"value": [
+			{"vm": "vm-1", "name": "Worker-1", "cpu_count": 4, "memory_size_MiB": 16384},
+			{"vm": "vm-2", "name": "worker-2", "cpu_count": 12, "memory_size_MiB": 49152}]}`,
+		vmPath + "?filter.hosts=host-2&filter.power_states=POWERED_ON": `{"value": [
+			{"vm": "vm-3", "name": "worker-3", "cpu_count": 2, "memory_size_MiB": 8192}]}`,
+	}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == sessionPath {
+			if username, password, _ := r.BasicAuth(); r.Method == http.MethodPost && (username != "purser" || password != "secret") {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Write([]byte(`{"value": "session"}`))
+			return
+		}
+		if r.Header.Get(sessionHeader) != "session" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		key := r.URL.Path
+		if r.URL.RawQuery != "" {
+			key += "?" + r.URL.RawQuery
+		}
+		response, isPresent := responses[key]
+		if !isPresent {
+			t.Errorf("unexpected request: %s", key)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(response))
+	}))
+}
+
+func TestGetNodePrice(t *testing.T) {
+	server := newVCenter(t)
+	defer server.Close()
+	provider, err := NewProvider(Config{
+		URL:       server.URL,
+		Username:  "purser",
+		Password:  "secret",
+		HostType:  "r640",
+		Clusters:  map[string]string{"cluster-1": "i3.metal"},
+		HostTypes: map[string]float64{"i3.metal": 8, "r640": 1},
+	})
+	utils.Ok(t, err)
+
+	// 16 cpus and 64GiB are allocated on host-1, VMs are matched by name ignoring case
+	rates, err := provider.GetNodePrice(models.Node{ID: dgraph.ID{Xid: "Worker-1"}, Name: "node-worker-1"})
+	utils.Ok(t, err)
+	utils.Equals(t, &models.NodeRates{CPUPrice: 0.25, MemoryPrice: 0.0625}, rates)
+
+	// standalone host-2 has the default host type
+	rates, err = provider.GetNodePrice(models.Node{Name: "node-worker-3"})
+	utils.Ok(t, err)
+	utils.Equals(t, &models.NodeRates{CPUPrice: 0.25, MemoryPrice: 0.0625}, rates)
+
+	_, err = provider.GetNodePrice(models.Node{Name: "node-worker-4"})
+	utils.Assert(t, err != nil, "expected error for node without VM")
+}
+
+func TestGetNodePriceWithVCenterError(t *testing.T) {
+	server := newVCenter(t)
+	defer server.Close()
+	provider, err := NewProvider(Config{URL: server.URL, Username: "purser", Password: "wrong", HostType: "i3.metal"})
+	utils.Ok(t, err)
+	_, err = provider.GetNodePrice(models.Node{Name: "node-worker-1"})
+	utils.Assert(t, err != nil, "expected error when vCenter login fails")
+}
+
+func TestNewProviderValidatesConfig(t *testing.T) {
+	_, err := NewProvider(Config{HostType: "i3.metal"})
+	utils.Assert(t, err != nil, "expected error for config without url")
+
+	_, err = NewProvider(Config{URL: "https://vcenter", HostType: "r640"})
+	utils.Assert(t, err != nil, "expected error for host type without price")
+
+	_, err = NewProvider(Config{URL: "https://vcenter", Clusters: map[string]string{"cluster-1": "r640"}})
+	utils.Assert(t, err != nil, "expected error for host type of cluster without price")
+
+	_, err = NewProvider(Config{URL: "https://vcenter", RefreshInterval: "daily"})
+	utils.Assert(t, err != nil, "expected error for invalid refresh interval")
+
+	_, err = NewProvider(Config{URL: "https://vcenter", HostType: "i3en.metal", RefreshInterval: "30m"})
+	utils.Ok(t, err)
+}
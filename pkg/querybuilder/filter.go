@@ -0,0 +1,157 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package querybuilder
+
+import "strings"
+
+// Filter is a condition of a func or @filter directive, it is built with the functions below so that
+// values are escaped and nested conditions are parenthesized.
+type Filter struct {
+	expression string
+	compound   bool
+	operator   string
+}
+
+// String returns the filter in Dgraph query syntax
+func (f Filter) String() string {
+	return f.expression
+}
+
+// IsEmpty checks whether the filter has no condition
+func (f Filter) IsEmpty() bool {
+	return f.expression == ""
+}
+
+// Raw returns a filter from an expression in Dgraph query syntax, the expression is used as is
+func Raw(expression string) Filter {
+	return Filter{expression: expression, compound: strings.ContainsAny(expression, " ")}
+}
+
+// Has returns has(predicate)
+func Has(predicate string) Filter {
+	return Filter{expression: "has(" + predicate + ")"}
+}
+
+// Eq returns eq(predicate, "value")
+func Eq(predicate, value string) Filter {
+	return compare("eq", predicate, value)
+}
+
+// Le returns le(predicate, "value")
+func Le(predicate, value string) Filter {
+	return compare("le", predicate, value)
+}
+
+// Lt returns lt(predicate, "value")
+func Lt(predicate, value string) Filter {
+	return compare("lt", predicate, value)
+}
+
+// Ge returns ge(predicate, "value")
+func Ge(predicate, value string) Filter {
+	return compare("ge", predicate, value)
+}
+
+// Gt returns gt(predicate, "value")
+func Gt(predicate, value string) Filter {
+	return compare("gt", predicate, value)
+}
+
+// EqParam returns eq(predicate, $variable), the variable is declared with Query.Param
+func EqParam(predicate, variable string) Filter {
+	return compareParam("eq", predicate, variable)
+}
+
+// LeParam returns le(predicate, $variable)
+func LeParam(predicate, variable string) Filter {
+	return compareParam("le", predicate, variable)
+}
+
+// GeParam returns ge(predicate, $variable)
+func GeParam(predicate, variable string) Filter {
+	return compareParam("ge", predicate, variable)
+}
+
+// UID returns uid(values...), values are uids or names of uid variables
+func UID(values ...string) Filter {
+	return Filter{expression: "uid(" + strings.Join(values, ", ") + ")"}
+}
+
+// And returns conjunction of the non empty filters
+func And(filters ...Filter) Filter {
+	return join(" AND ", filters)
+}
+
+// Or returns disjunction of the non empty filters
+func Or(filters ...Filter) Filter {
+	return join(" OR ", filters)
+}
+
+// Not returns negation of the filter
+func Not(filter Filter) Filter {
+	if filter.IsEmpty() {
+		return filter
+	}
+	return Filter{expression: "NOT " + filter.operand()}
+}
+
+// Quote returns value as a Dgraph string literal
+func Quote(value string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value) + `"`
+}
+
+func compare(function, predicate, value string) Filter {
+	return Filter{expression: function + "(" + predicate + ", " + Quote(value) + ")"}
+}
+
+func compareParam(function, predicate, variable string) Filter {
+	return Filter{expression: function + "(" + predicate + ", " + variable + ")"}
+}
+
+func join(operator string, filters []Filter) Filter {
+	nonEmpty := []Filter{}
+	for _, filter := range filters {
+		if !filter.IsEmpty() {
+			nonEmpty = append(nonEmpty, filter)
+		}
+	}
+	if len(nonEmpty) == 0 {
+		return Filter{}
+	}
+	if len(nonEmpty) == 1 {
+		return nonEmpty[0]
+	}
+	operands := []string{}
+	for _, filter := range nonEmpty {
+		if filter.operator == operator {
+			// conjunction of conjunctions(or disjunction of disjunctions) needs no parentheses
+			operands = append(operands, filter.expression)
+			continue
+		}
+		operands = append(operands, filter.operand())
+	}
+	return Filter{expression: strings.Join(operands, operator), compound: true, operator: operator}
+}
+
+// operand returns the filter parenthesized if it is a compound condition
+func (f Filter) operand() string {
+	if f.compound {
+		return "(" + f.expression + ")"
+	}
+	return f.expression
+}
@@ -0,0 +1,255 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package querybuilder builds Dgraph queries against the Purser schema from composable blocks, filters,
+// variables and math expressions instead of concatenating strings. Values given to filters are escaped.
+//
+//	query := querybuilder.New(
+//		querybuilder.Func("pods", querybuilder.Has("isPod")).
+//			Filter(querybuilder.Eq("name", name)).
+//			Fields("name", "startTime"),
+//	)
+//	executeQuery(query.String(), &root)
+//
+// Values given by users are better passed as variables of the query, they are sent apart from the query text:
+//
+//	query := querybuilder.New(
+//		querybuilder.Func("pods", querybuilder.Has("isPod")).
+//			Filter(querybuilder.EqParam("name", "$name")).
+//			Fields("name", "startTime"),
+//	).Param("$name", name)
+//	executeQueryWithVars(query.String(), query.Vars(), &root)
+package querybuilder
+
+import (
+	"sort"
+	"strings"
+)
+
+// Query is a Dgraph query made of root blocks
+type Query struct {
+	blocks []*Block
+	vars   Vars
+}
+
+// Vars are values of string variables of a query keyed by their names(ex: $name)
+type Vars map[string]string
+
+// Declaration returns the header of a query declaring the variables, ex: query q($name: string)
+func (v Vars) Declaration() string {
+	if len(v) == 0 {
+		return "query"
+	}
+	names := []string{}
+	for name := range v {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for index, name := range names {
+		names[index] = name + ": string"
+	}
+	return "query q(" + strings.Join(names, ", ") + ")"
+}
+
+// New returns a query with the given root blocks
+func New(blocks ...*Block) *Query {
+	return &Query{blocks: blocks}
+}
+
+// Block adds root blocks to the query
+func (q *Query) Block(blocks ...*Block) *Query {
+	q.blocks = append(q.blocks, blocks...)
+	return q
+}
+
+// Param declares the variable(ex: $name) of the query with its value, filters compare with it through EqParam...
+func (q *Query) Param(name, value string) *Query {
+	if q.vars == nil {
+		q.vars = Vars{}
+	}
+	q.vars[name] = value
+	return q
+}
+
+// Vars returns values of the variables declared by Param
+func (q *Query) Vars() Vars {
+	return q.vars
+}
+
+// String returns the query in Dgraph query syntax
+func (q *Query) String() string {
+	var builder strings.Builder
+	builder.WriteString(q.vars.Declaration() + " {")
+	for _, block := range q.blocks {
+		builder.WriteString("\n")
+		block.write(&builder, 1)
+	}
+	builder.WriteString("\n}")
+	return builder.String()
+}
+
+// Block is a root query block(name(func: ...)) or a nested block of an edge
+type Block struct {
+	name      string
+	root      Filter
+	order     string
+	variable  string
+	alias     string
+	filter    Filter
+	directive string
+	lines     []string
+	children  []*Block
+}
+
+// Func returns a root block named name selecting nodes matching root, ex: pods(func: has(isPod))
+func Func(name string, root Filter) *Block {
+	return &Block{name: name, root: root}
+}
+
+// Var returns a root var block selecting nodes matching root, its results are only used through variables
+func Var(root Filter) *Block {
+	return Func("var", root)
+}
+
+// Edge returns a nested block traversing the predicate, use ~predicate for reverse edges
+func Edge(predicate string) *Block {
+	return &Block{name: predicate}
+}
+
+// As stores uids selected by the block in a variable, ex: pods as ~namespace
+func (b *Block) As(variable string) *Block {
+	b.variable = variable
+	return b
+}
+
+// Alias sets the key of the block in results, ex: children: ~namespace
+func (b *Block) Alias(alias string) *Block {
+	b.alias = alias
+	return b
+}
+
+// OrderAsc orders root block results by predicate ascending
+func (b *Block) OrderAsc(predicate string) *Block {
+	b.order = "orderasc: " + predicate
+	return b
+}
+
+// OrderDesc orders root block results by predicate descending
+func (b *Block) OrderDesc(predicate string) *Block {
+	b.order = "orderdesc: " + predicate
+	return b
+}
+
+// Filter adds @filter directive to the block, empty filter adds nothing
+func (b *Block) Filter(filter Filter) *Block {
+	b.filter = filter
+	return b
+}
+
+// Directive adds another directive to the block, ex: @normalize
+func (b *Block) Directive(directive string) *Block {
+	b.directive = directive
+	return b
+}
+
+// Fields adds predicates to be returned
+func (b *Block) Fields(predicates ...string) *Block {
+	b.lines = append(b.lines, predicates...)
+	return b
+}
+
+// AliasedField adds predicate to be returned with the alias as key, ex: podName: name
+func (b *Block) AliasedField(alias, predicate string) *Block {
+	b.lines = append(b.lines, alias+": "+predicate)
+	return b
+}
+
+// VarField stores the value of predicate in variable, ex: podCpu as cpuRequest
+func (b *Block) VarField(variable, predicate string) *Block {
+	b.lines = append(b.lines, variable+" as "+predicate)
+	return b
+}
+
+// Math adds a math expression, alias and variable are optional, ex: cost: podCost as math(cpu * price)
+func (b *Block) Math(alias, variable, expression string) *Block {
+	b.lines = append(b.lines, getPrefix(alias, variable)+MathExpr(expression))
+	return b
+}
+
+// Val adds the value of a variable with alias as key, ex: cpu: val(podCpu)
+func (b *Block) Val(alias, variable string) *Block {
+	b.lines = append(b.lines, getPrefix(alias, "")+"val("+variable+")")
+	return b
+}
+
+// Aggregate adds value of an aggregation(sum, min, max, avg) of variable, ex: cpu: sum(val(podCpu))
+func (b *Block) Aggregate(alias, variable, function, value string) *Block {
+	b.lines = append(b.lines, getPrefix(alias, variable)+function+"(val("+value+"))")
+	return b
+}
+
+// Child adds nested blocks
+func (b *Block) Child(children ...*Block) *Block {
+	b.children = append(b.children, children...)
+	return b
+}
+
+// MathExpr returns math(expression)
+func MathExpr(expression string) string {
+	return "math(" + expression + ")"
+}
+
+func getPrefix(alias, variable string) string {
+	prefix := ""
+	if alias != "" {
+		prefix = alias + ": "
+	}
+	if variable != "" {
+		prefix += variable + " as "
+	}
+	return prefix
+}
+
+func (b *Block) write(builder *strings.Builder, depth int) {
+	indent := strings.Repeat("\t", depth)
+	builder.WriteString(indent + getPrefix(b.alias, b.variable) + b.name)
+	if !b.root.IsEmpty() {
+		builder.WriteString("(func: " + b.root.String())
+		if b.order != "" {
+			builder.WriteString(", " + b.order)
+		}
+		builder.WriteString(")")
+	}
+	if !b.filter.IsEmpty() {
+		builder.WriteString(" @filter(" + b.filter.String() + ")")
+	}
+	if b.directive != "" {
+		builder.WriteString(" " + b.directive)
+	}
+	if len(b.lines) == 0 && len(b.children) == 0 {
+		return
+	}
+	builder.WriteString(" {")
+	for _, line := range b.lines {
+		builder.WriteString("\n" + indent + "\t" + line)
+	}
+	for _, child := range b.children {
+		builder.WriteString("\n")
+		child.write(builder, depth+1)
+	}
+	builder.WriteString("\n" + indent + "}")
+}
@@ -0,0 +1,79 @@
+/*
+ * Copyright (c) 2018 VMware Inc. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package querybuilder
+
+import (
+	"testing"
+
+	"github.com/vmware/purser/test/utils"
+)
+
+func TestFilters(t *testing.T) {
+	utils.Equals(t, `eq(name, "pod-\"a\\)")`, Eq("name", `pod-"a\)`).String())
+	utils.Equals(t, `has(isPod) AND (NOT has(endTime) OR gt(endTime, "2018-10-01T00:00:00Z"))`,
+		And(Has("isPod"), Or(Not(Has("endTime")), Gt("endTime", "2018-10-01T00:00:00Z"))).String())
+	utils.Equals(t, "has(isPod)", And(Filter{}, Has("isPod"), Or()).String())
+	utils.Equals(t, "NOT (has(isPod) OR has(isNode))", Not(Or(Has("isPod"), Has("isNode"))).String())
+	utils.Equals(t, "has(a) AND has(b) AND (has(c) OR has(d))", And(And(Has("a"), Has("b")), Or(Has("c"), Has("d"))).String())
+	utils.Equals(t, "uid(0x1, 0x2)", UID("0x1", "0x2").String())
+	utils.Assert(t, Not(And()).IsEmpty(), "negation of empty filter is not empty")
+}
+
+func TestQuery(t *testing.T) {
+	query := New(
+		Var(Has("isNamespace")).Filter(Eq("name", "namespace-default")).Child(
+			Edge("~namespace").As("pods").Filter(Has("isPod")).
+				VarField("podCpu", "cpuRequest").
+				Math("", "podCost", "podCpu * 0.5"),
+		),
+		Func("pods", UID("pods")).OrderDesc("startTime").
+			Fields("name").
+			Val("cost", "podCost").
+			Child(Edge("node").Alias("podNode").Fields("name")),
+	)
+	expected := `query {
+	var(func: has(isNamespace)) @filter(eq(name, "namespace-default")) {
+		pods as ~namespace @filter(has(isPod)) {
+			podCpu as cpuRequest
+			podCost as math(podCpu * 0.5)
+		}
+	}
+	pods(func: uid(pods), orderdesc: startTime) {
+		name
+		cost: val(podCost)
+		podNode: node {
+			name
+		}
+	}
+}`
+	utils.Equals(t, expected, query.String())
+}
+
+func TestQueryWithParams(t *testing.T) {
+	query := New(
+		Func("pods", Has("isPod")).Filter(And(EqParam("name", "$name"), GeParam("startTime", "$start"))).Fields("name"),
+	).Param("$start", "2018-10-01T00:00:00Z").Param("$name", `pod-"a"`)
+	expected := `query q($name: string, $start: string) {
+	pods(func: has(isPod)) @filter(eq(name, $name) AND ge(startTime, $start)) {
+		name
+	}
+}`
+	utils.Equals(t, expected, query.String())
+	utils.Equals(t, Vars{"$name": `pod-"a"`, "$start": "2018-10-01T00:00:00Z"}, query.Vars())
+	utils.Equals(t, "query", Vars{}.Declaration())
+}
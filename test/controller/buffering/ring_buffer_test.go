@@ -50,3 +50,19 @@ func TestGet(t *testing.T) {
 	ret2 := r.Get()
 	utils.Assert(t, (*ret2).(int) == testValue, "get elements from non empty buffer")
 }
+
+func TestLen(t *testing.T) {
+	r := &buffering.RingBuffer{Size: 3, Mutex: &sync.Mutex{}}
+	utils.Equals(t, uint32(2), r.Capacity())
+	utils.Equals(t, uint32(0), r.Len())
+
+	r.Put(1)
+	r.Put(2)
+	utils.Equals(t, uint32(2), r.Len())
+
+	r.Get()
+	r.Put(3)
+	utils.Equals(t, uint32(2), r.Len())
+	r.RemoveN(2)
+	utils.Equals(t, uint32(0), r.Len())
+}